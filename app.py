@@ -19,23 +19,35 @@ YOUR_YEAR = int(os.getenv('ESPN_YEAR', 2025))
 YOUR_ESPN_S2 = os.getenv('ESPN_S2', 'AEANF5s/YFx8uRBzF0ySSDkyZkZVNuQ95avS3MuJaOMoWTdXFYiRItuIfiDSE/EADpCTJYbypKBuEva4kJ6+3kj/G58wrOwlk+HiORhAHPQeZ/ibNioe6PRhLjSLMttbmV2PKL6SjFT87LpLTYlgYL9Pw3cm32NNS8740CFpIbsUUBGLJ0Ry6dpXGL/dxMhX7AmhmdwQhfV7LsopKrI6tR/YD2NUCxTfs722KQHg0f64uSK3zdXAtNM8wNAkc7K1WsWCY1g35RHzE8esgza5WXwVcld3X7pAdGX6Wa1fn34OPA==')
 YOUR_SWID = os.getenv('ESPN_SWID', '{06B8EDC1-CAAD-40F0-A6AB-22C15EDF791B}')
 
+def request_credentials():
+    """League credentials the Go API forwards for the calling user, falling back to the defaults"""
+    headers = request.headers
+    return {
+        'league_id': int(headers.get('X-ESPN-League-ID', YOUR_LEAGUE_ID)),
+        'team_id': int(headers.get('X-ESPN-Team-ID', YOUR_TEAM_ID)),
+        'year': int(headers.get('X-ESPN-Year', YOUR_YEAR)),
+        'espn_s2': headers.get('X-ESPN-S2', YOUR_ESPN_S2),
+        'swid': headers.get('X-ESPN-SWID', YOUR_SWID),
+    }
+
 def get_league_and_team():
     """Helper function to initialize league and get team"""
+    creds = request_credentials()
     league = League(
-        league_id=YOUR_LEAGUE_ID,
-        year=YOUR_YEAR,
-        espn_s2=YOUR_ESPN_S2,
-        swid=YOUR_SWID
+        league_id=creds['league_id'],
+        year=creds['year'],
+        espn_s2=creds['espn_s2'],
+        swid=creds['swid']
     )
     
     team = None
     for t in league.teams:
-        if t.team_id == YOUR_TEAM_ID:
+        if t.team_id == creds['team_id']:
             team = t
             break
     
     if not team:
-        return None, None, f'Team with ID {YOUR_TEAM_ID} not found'
+        return None, None, f"Team with ID {creds['team_id']} not found"
     
     return league, team, None
 
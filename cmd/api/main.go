@@ -9,6 +9,7 @@ import (
 
 	"github.com/ai-atl/nfl-platform/internal/config"
 	"github.com/ai-atl/nfl-platform/internal/handlers"
+	"github.com/ai-atl/nfl-platform/internal/jobs"
 	"github.com/ai-atl/nfl-platform/internal/middleware"
 	"github.com/ai-atl/nfl-platform/internal/services"
 	"github.com/ai-atl/nfl-platform/pkg/mongodb"
@@ -29,7 +30,7 @@ func main() {
 	defer cancel()
 
 	var err error
-	mongoClient, err = mongodb.Connect(ctx, cfg.MongoURI)
+	mongoClient, err = mongodb.Connect(ctx, cfg.MongoURI, cfg.MongoPool())
 	if err != nil {
 		log.Fatalf("Failed to connect to MongoDB: %v", err)
 	}
@@ -41,29 +42,85 @@ func main() {
 
 	log.Println("Connected to MongoDB successfully!")
 
+	// Indexes back the unique and TTL constraints that idempotency keys and refresh tokens
+	// rely on, so create any that are missing before serving
+	indexCtx, indexCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	if err := mongodb.CreateIndexes(indexCtx, mongoClient.Database(cfg.DBName)); err != nil {
+		log.Printf("WARNING: failed to create some MongoDB indexes: %v", err)
+	}
+	indexCancel()
+
+	// Watch the primary so requests fail fast with a 503 during an outage
+	dbMonitor := mongodb.NewMonitor(mongoClient)
+	if cfg.MongoHealthInterval > 0 {
+		go dbMonitor.Run(context.Background(), cfg.MongoHealthInterval)
+	}
+
 	// Initialize Gin router
-	router := gin.Default()
+	router := gin.New()
 
 	db := mongoClient.Database(cfg.DBName)
 	yahooService := services.NewYahooService(db, cfg)
 	fantasyHandler := handlers.NewFantasyHandler(cfg, yahooService)
-	espnHandler := handlers.NewESPNHandler(db, "http://localhost:5002")
+	espnService := services.NewFlaskESPNClient(cfg.ESPNServiceURL)
+	espnHandler := handlers.NewESPNHandler(db, espnService, cfg.ESPNNativeClient)
+
+	// Connected ESPN rosters come from the Flask service, which the native client replaces
+	var espnRosters services.ESPNRosterSource
+	if !cfg.ESPNNativeClient {
+		espnRosters = services.NewFlaskESPNRosters(db, espnService)
+	}
+
+	// Precompute defensive rankings weekly so matchup lookups don't scan plays
+	if cfg.DefenseRankingsInterval > 0 {
+		go jobs.ScheduleDefenseRankings(context.Background(), db, cfg.DefenseRankingsInterval)
+	}
+
+	// Score start/sit and waiver recommendations against actual points once weeks finish
+	if cfg.RecommendationScoringInterval > 0 {
+		go jobs.ScheduleRecommendationScoring(context.Background(), db, cfg.RecommendationScoringInterval)
+	}
+
+	// Poll connected ESPN rosters for injury status changes
+	if cfg.ESPNAlertInterval > 0 && cfg.ESPNNativeClient {
+		log.Println("ESPN alert poller disabled: it requires the Flask ESPN service")
+	} else if cfg.ESPNAlertInterval > 0 {
+		go jobs.NewESPNAlertPoller(db, espnService).Run(context.Background(), cfg.ESPNAlertInterval)
+	}
 
 	// Middleware
-	router.Use(middleware.CORS())
 	router.Use(middleware.RequestLogger())
+	router.Use(middleware.Recovery())
+	router.Use(middleware.CORS())
+	router.Use(middleware.SeasonOverride(cfg.SeasonOverride))
+
+	// Every route but the chatbot stream gets the request deadline; a streamed answer runs
+	// for as long as the client stays connected
+	timed := router.Group("", middleware.Timeout(cfg.RequestTimeout))
 
 	// Health check
-	router.GET("/health", func(c *gin.Context) {
+	timed.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "ok",
 			"service": "nfl-platform-api",
 			"time":    time.Now().Format(time.RFC3339),
 		})
 	})
+	timed.GET("/health/ready", handlers.NewHealthHandler(mongoClient).Ready)
+
+	// Expensive AI endpoints get a per-user, per-route limit, configured per route group
+	insightsRateLimit := middleware.RateLimit(cfg.InsightsRateLimit, time.Minute)
+	chatbotRateLimit := middleware.RateLimit(cfg.ChatbotRateLimit, time.Minute)
+	espnAIRateLimit := middleware.RateLimit(cfg.ESPNAIRateLimit, time.Minute)
+	tradesRateLimit := middleware.RateLimit(cfg.TradesRateLimit, time.Minute)
+	idempotent := middleware.Idempotency(db, cfg.IdempotencyTTL)
+	leaderboard := middleware.ETag(db, cfg.LeaderboardMaxAge)
+
+	chatbotHandler := handlers.NewChatbotHandler(db, cfg.ChatHistoryTurns)
 
 	// API v1 routes
-	v1 := router.Group("/api/v1")
+	v1 := timed.Group("/api/v1")
+	v1.Use(middleware.DatabaseAvailable(dbMonitor.Healthy))
 	{
 		// Auth routes
 		auth := v1.Group("/auth")
@@ -72,6 +129,7 @@ func main() {
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/logout", authHandler.Logout)
 		}
 
 		// Yahoo OAuth callback (public)
@@ -90,6 +148,7 @@ func main() {
 				fantasy.GET("/status", fantasyHandler.Status)
 				fantasy.GET("/oauth/url", fantasyHandler.GetAuthURL)
 				fantasy.GET("/teams", fantasyHandler.Teams)
+				fantasy.GET("/teams/:team_key/roster", fantasyHandler.Roster)
 			}
 
 			// ESPN Fantasy routes
@@ -100,7 +159,10 @@ func main() {
 				espn.GET("/roster", espnHandler.GetRoster)
 				espn.GET("/optimize-lineup", espnHandler.OptimizeLineup)
 				espn.GET("/free-agents", espnHandler.GetFreeAgents)
-				espn.POST("/ai-start-sit", espnHandler.GetAIStartSitAdvice)
+				espn.POST("/ai-start-sit", espnAIRateLimit, espnHandler.GetAIStartSitAdvice)
+				espn.GET("/alerts", espnHandler.GetAlerts)
+				espn.GET("/matchup", espnHandler.GetMatchup)
+				espn.GET("/league/settings", espnHandler.GetLeagueSettings)
 			}
 
 			// Players
@@ -115,7 +177,7 @@ func main() {
 			// Lineups
 			lineups := protected.Group("/lineups")
 			{
-				lineupHandler := handlers.NewLineupHandler(db)
+				lineupHandler := handlers.NewLineupHandler(db, espnRosters)
 				lineups.GET("", lineupHandler.List)
 				lineups.POST("", lineupHandler.Create)
 				lineups.GET("/:id", lineupHandler.Get)
@@ -130,19 +192,37 @@ func main() {
 				dataHandler := handlers.NewDataHandler(db)
 
 				// Player queries
+				data.GET("/players/search", dataHandler.SearchPlayers)
+				data.POST("/players/batch", dataHandler.GetPlayerStatsBatch)
 				data.GET("/players/:nfl_id", dataHandler.GetPlayer)
 				data.GET("/players/:nfl_id/stats", dataHandler.GetPlayerStats)
+				data.GET("/players/:nfl_id/weekly", dataHandler.GetPlayerWeeklyStats)
+				data.GET("/players/:nfl_id/gamelog", dataHandler.GetPlayerGameLog)
+				data.GET("/players/:nfl_id/extremes", dataHandler.GetPlayerExtremes)
+				data.GET("/players/:nfl_id/qbr", dataHandler.GetPlayerQBR)
 				data.GET("/players/:nfl_id/epa", dataHandler.GetPlayerEPA)
+				data.GET("/players/:nfl_id/epa/trend", dataHandler.GetPlayerEPATrend)
+				data.GET("/players/:nfl_id/redzone", dataHandler.GetPlayerRedZone)
 				data.GET("/players/:nfl_id/plays", dataHandler.GetPlayerPlays)
 				data.GET("/players/:nfl_id/ngs", dataHandler.GetPlayerNGS)
+				data.GET("/players/:nfl_id/ngs/trend", dataHandler.GetPlayerNGSTrend)
 				data.GET("/players/:nfl_id/summary", dataHandler.GetPlayerSummary)
+				data.GET("/players/:nfl_id/status", dataHandler.GetPlayerStatus)
 
 				// Team queries
+				data.GET("/teams", dataHandler.GetTeams)
+				data.GET("/standings", dataHandler.GetStandings)
 				data.GET("/teams/:team/players", dataHandler.GetPlayersByTeam)
 				data.GET("/teams/:team/epa", dataHandler.GetTeamEPA)
+				data.GET("/teams/:team/tendencies", dataHandler.GetTeamTendencies)
 				data.GET("/teams/:team/plays", dataHandler.GetTeamPlays)
 				data.GET("/teams/:team/depth-chart", dataHandler.GetTeamDepthChart)
 				data.GET("/teams/:team/upcoming", dataHandler.GetUpcomingGames)
+				data.GET("/teams/:team/schedule", dataHandler.GetTeamSchedule)
+				data.GET("/teams/:team/leaders", dataHandler.GetTeamLeaders)
+
+				// Player comparison (structured data behind start/sit advice)
+				data.GET("/compare", dataHandler.ComparePlayers)
 
 				// Position queries
 				data.GET("/positions/:position", dataHandler.GetPlayersByPosition)
@@ -155,32 +235,31 @@ func main() {
 			data.GET("/games/scheduled", dataHandler.GetScheduledGames)
 			data.GET("/games/:game_id", dataHandler.GetGame)
 			data.GET("/games/:game_id/plays", dataHandler.GetGamePlays)
+			data.GET("/games/:game_id/projection", dataHandler.GetGameProjection)
+			data.GET("/scoreboard", dataHandler.GetScoreboard)
 
 				// NGS leaders
-				data.GET("/ngs/leaders", dataHandler.GetNGSLeaders)
-			}
+				data.GET("/ngs/leaders", leaderboard, dataHandler.GetNGSLeaders)
+				data.GET("/ngs/receiving/leaders", leaderboard, dataHandler.GetReceivingLeaders)
 
-			// Insights (AI-powered features)
-			insights := protected.Group("/insights")
-			{
-				insightHandler := handlers.NewInsightHandler(db)
-				insights.GET("/game_script", insightHandler.GameScript)
-				insights.POST("/injury_impact", insightHandler.InjuryImpact)
-				insights.GET("/streaks", insightHandler.Streaks)
-				insights.GET("/top_performers", insightHandler.TopPerformers)
-				insights.GET("/waiver_gems", insightHandler.WaiverGems)
-				insights.POST("/personalized_waiver_gems", insightHandler.PersonalizedWaiverGems)
-			} // Trade Analyzer
-			trades := protected.Group("/trades")
-			{
-				tradeHandler := handlers.NewTradeHandler(db)
-				trades.POST("/analyze", tradeHandler.Analyze)
+				// Defense queries
+				data.GET("/defense/rankings", leaderboard, dataHandler.GetDefensiveRankings)
 			}
 
+			registerInsightRoutes(protected,
+				handlers.NewInsightHandler(db, espnRosters),
+				handlers.NewTradeHandler(db),
+				aiRouteMiddleware{
+					insightsRateLimit: insightsRateLimit,
+					tradesRateLimit:   tradesRateLimit,
+					idempotent:        idempotent,
+					leaderboard:       leaderboard,
+				})
+
 			// Chatbot
 			chatbot := protected.Group("/chatbot")
+			chatbot.Use(chatbotRateLimit)
 			{
-				chatbotHandler := handlers.NewChatbotHandler(db)
 				chatbot.POST("/ask", chatbotHandler.Ask)
 				chatbot.GET("/history", chatbotHandler.History)
 			}
@@ -195,6 +274,14 @@ func main() {
 		}
 	}
 
+	// Chatbot stream, registered outside the timed routes with the same middleware as /chatbot
+	stream := router.Group("/api/v1/chatbot",
+		middleware.DatabaseAvailable(dbMonitor.Healthy),
+		middleware.AuthRequired(),
+		chatbotRateLimit,
+	)
+	stream.POST("/ask/stream", chatbotHandler.AskStream)
+
 	// Start server
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/ai-atl/nfl-platform/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+// aiRouteMiddleware is the per-route middleware the insight and trade routes are wired with
+type aiRouteMiddleware struct {
+	insightsRateLimit gin.HandlerFunc
+	tradesRateLimit   gin.HandlerFunc
+	idempotent        gin.HandlerFunc
+	leaderboard       gin.HandlerFunc
+}
+
+// registerInsightRoutes wires /insights and /trades under protected. Only the routes that
+// call Gemini are rate limited; the rest are database reads.
+func registerInsightRoutes(protected *gin.RouterGroup, insightHandler *handlers.InsightHandler, tradeHandler *handlers.TradeHandler, mw aiRouteMiddleware) {
+	// Insights (AI-powered features)
+	insights := protected.Group("/insights")
+	{
+		insights.GET("/game_script", mw.insightsRateLimit, insightHandler.GameScript)
+		insights.POST("/injury_impact", mw.insightsRateLimit, insightHandler.InjuryImpact)
+		insights.POST("/lineup_help", insightHandler.LineupHelp)
+		insights.GET("/similar", insightHandler.Similar)
+		insights.GET("/streaks", insightHandler.Streaks)
+		insights.GET("/streaming_defenses", insightHandler.StreamingDefenses)
+		insights.GET("/top_performers", mw.leaderboard, insightHandler.TopPerformers)
+		insights.GET("/vorp", insightHandler.VORP)
+		insights.GET("/waiver_gems", mw.insightsRateLimit, insightHandler.WaiverGems)
+		insights.POST("/waiver_gems", mw.insightsRateLimit, insightHandler.ConnectedWaiverGems)
+		insights.POST("/personalized_waiver_gems", mw.insightsRateLimit, insightHandler.PersonalizedWaiverGems)
+		insights.POST("/roster_analysis", insightHandler.RosterAnalysis)
+		insights.GET("/accuracy", insightHandler.Accuracy)
+	}
+
+	// Trade Analyzer. Analysis saves a history entry and calls Gemini, so a repeated
+	// Idempotency-Key replays the first result. pkg/espn has no waiver or transaction
+	// submit yet; those handlers should be mounted with idempotent once it does.
+	trades := protected.Group("/trades")
+	{
+		trades.POST("/analyze", mw.tradesRateLimit, mw.idempotent, tradeHandler.Analyze)
+		trades.GET("/history", tradeHandler.History)
+		trades.GET("/:id", tradeHandler.Get)
+	}
+}
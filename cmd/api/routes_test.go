@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+// Markers stand in for the real middleware so each route's chain can be read by name
+func insightsLimited(c *gin.Context) {}
+func tradesLimited(c *gin.Context)   {}
+func idempotent(c *gin.Context)      {}
+func leaderboard(c *gin.Context)     {}
+
+// insightRouteChains registers the insight and trade routes with the marker middleware and
+// returns a function reporting the names of the handlers chained on a route
+func insightRouteChains(t *testing.T) func(route string) []string {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	chains := make(map[string][]string)
+	// Record each route's handler chain and stop before the handlers touch the database
+	router.Use(func(c *gin.Context) {
+		chains[c.Request.Method+" "+c.FullPath()] = c.HandlerNames()
+		c.AbortWithStatus(http.StatusNoContent)
+	})
+	registerInsightRoutes(router.Group(""), &handlers.InsightHandler{}, &handlers.TradeHandler{}, aiRouteMiddleware{
+		insightsRateLimit: insightsLimited,
+		tradesRateLimit:   tradesLimited,
+		idempotent:        idempotent,
+		leaderboard:       leaderboard,
+	})
+
+	return func(route string) []string {
+		method, path, _ := strings.Cut(route, " ")
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, strings.Replace(path, ":id", "abc", 1), nil))
+		chain, ok := chains[route]
+		if !ok {
+			t.Errorf("%s is not registered", route)
+		}
+		return chain
+	}
+}
+
+// hasHandler reports whether chain includes the package-level function name
+func hasHandler(chain []string, name string) bool {
+	for _, h := range chain {
+		if strings.HasSuffix(h, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInsightRoutesRateLimitGeminiCalls(t *testing.T) {
+	chainOf := insightRouteChains(t)
+
+	tests := []struct {
+		route   string
+		limiter string // "" for routes that don't call Gemini
+	}{
+		{"GET /insights/game_script", "insightsLimited"},
+		{"POST /insights/injury_impact", "insightsLimited"},
+		{"GET /insights/waiver_gems", "insightsLimited"},
+		{"POST /insights/waiver_gems", "insightsLimited"},
+		{"POST /insights/personalized_waiver_gems", "insightsLimited"},
+		{"POST /trades/analyze", "tradesLimited"},
+		{"POST /insights/lineup_help", ""},
+		{"GET /insights/similar", ""},
+		{"GET /insights/streaks", ""},
+		{"GET /insights/streaming_defenses", ""},
+		{"GET /insights/top_performers", ""},
+		{"GET /insights/vorp", ""},
+		{"POST /insights/roster_analysis", ""},
+		{"GET /insights/accuracy", ""},
+		{"GET /trades/history", ""},
+		{"GET /trades/:id", ""},
+	}
+
+	for _, tt := range tests {
+		chain := chainOf(tt.route)
+		var limiters []string
+		for _, limiter := range []string{"insightsLimited", "tradesLimited"} {
+			if hasHandler(chain, limiter) {
+				limiters = append(limiters, limiter)
+			}
+		}
+		switch {
+		case tt.limiter == "" && len(limiters) > 0:
+			t.Errorf("%s is rate limited by %v, want no limit", tt.route, limiters)
+		case tt.limiter != "" && (len(limiters) != 1 || limiters[0] != tt.limiter):
+			t.Errorf("%s is rate limited by %v, want %s", tt.route, limiters, tt.limiter)
+		}
+	}
+}
+
+// Trade analysis stands in for submissions until pkg/espn can submit waiver claims, so it
+// is the only route that replays a repeated Idempotency-Key
+func TestOnlyTradeAnalysisIsIdempotent(t *testing.T) {
+	chainOf := insightRouteChains(t)
+
+	if !hasHandler(chainOf("POST /trades/analyze"), "idempotent") {
+		t.Error("POST /trades/analyze is not guarded by the idempotency middleware")
+	}
+	for _, route := range []string{"GET /trades/history", "GET /trades/:id", "POST /insights/waiver_gems"} {
+		if hasHandler(chainOf(route), "idempotent") {
+			t.Errorf("%s is guarded by the idempotency middleware", route)
+		}
+	}
+}
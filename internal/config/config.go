@@ -3,22 +3,49 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/ai-atl/nfl-platform/pkg/mongodb"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	MongoURI          string
-	DBName            string
-	JWTSecret         string
-	GeminiAPIKey      string
-	RedisURL          string
-	Environment       string
-	Port              string
-	YahooClientID     string
-	YahooClientSecret string
-	YahooRedirectURL  string
-	ClientAppURL      string
+	MongoURI                string
+	DBName                  string
+	JWTSecret               string
+	GeminiAPIKey            string
+	RedisURL                string
+	Environment             string
+	Port                    string
+	YahooClientID           string
+	YahooClientSecret       string
+	YahooRedirectURL        string
+	ClientAppURL            string
+	ChatHistoryTurns        int
+	AIRateLimit             int // Default requests per user per minute on each AI endpoint
+	InsightsRateLimit       int // Requests per user per minute on each /insights route
+	ChatbotRateLimit        int // Requests per user per minute on each /chatbot route
+	ESPNAIRateLimit         int // Requests per user per minute on /espn/ai-start-sit
+	TradesRateLimit         int // Requests per user per minute on /trades/analyze
+	RequestTimeout          time.Duration
+	ESPNAlertInterval       time.Duration // How often to poll ESPN rosters for injury changes; 0 disables
+	ESPNServiceURL          string        // Base URL of the Flask ESPN service
+	ESPNNativeClient        bool          // Use the Go ESPN client instead of proxying to the Flask service
+	SeasonOverride          bool          // Honor X-Override-Season/X-Override-Week headers (debug/QA only)
+	DefenseRankingsInterval time.Duration // How often to precompute defense rankings; 0 disables
+	IdempotencyTTL          time.Duration // How long an Idempotency-Key's response is replayed
+	LeaderboardMaxAge       time.Duration // Cache-Control max-age on ETagged leaderboard responses
+
+	RecommendationScoringInterval time.Duration // How often to score recommendations against actual points; 0 disables
+
+	// MongoDB connection pool
+	MongoMaxPoolSize            int
+	MongoMinPoolSize            int
+	MongoConnectTimeout         time.Duration
+	MongoServerSelectionTimeout time.Duration
+	MongoRetry                  bool          // Retry reads and writes once on transient errors
+	MongoHealthInterval         time.Duration // How often the API pings the primary to detect outages
 }
 
 func Load() *Config {
@@ -28,19 +55,44 @@ func Load() *Config {
 	}
 
 	cfg := &Config{
-		MongoURI:          getEnv("MONGO_URI", "mongodb://localhost:27017"),
-		DBName:            getEnv("DB_NAME", "nfl_platform"),
-		JWTSecret:         getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
-		GeminiAPIKey:      getEnv("GEMINI_API_KEY", ""),
-		RedisURL:          getEnv("REDIS_URL", "redis://localhost:6379"),
-		Environment:       getEnv("ENV", "development"),
-		Port:              getEnv("PORT", "8080"),
-		YahooClientID:     getEnv("YAHOO_CLIENT_ID", ""),
-		YahooClientSecret: getEnv("YAHOO_CLIENT_SECRET", ""),
-		YahooRedirectURL:  getEnv("YAHOO_REDIRECT_URL", ""),
-		ClientAppURL:      getEnv("CLIENT_APP_URL", "http://localhost:3000"),
+		MongoURI:                getEnv("MONGO_URI", "mongodb://localhost:27017"),
+		DBName:                  getEnv("DB_NAME", "nfl_platform"),
+		JWTSecret:               getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+		GeminiAPIKey:            getEnv("GEMINI_API_KEY", ""),
+		RedisURL:                getEnv("REDIS_URL", "redis://localhost:6379"),
+		Environment:             getEnv("ENV", "development"),
+		Port:                    getEnv("PORT", "8080"),
+		YahooClientID:           getEnv("YAHOO_CLIENT_ID", ""),
+		YahooClientSecret:       getEnv("YAHOO_CLIENT_SECRET", ""),
+		YahooRedirectURL:        getEnv("YAHOO_REDIRECT_URL", ""),
+		ClientAppURL:            getEnv("CLIENT_APP_URL", "http://localhost:3000"),
+		ChatHistoryTurns:        getEnvInt("CHAT_HISTORY_TURNS", 5),
+		AIRateLimit:             getEnvInt("AI_RATE_LIMIT_PER_MINUTE", 10),
+		RequestTimeout:          time.Duration(getEnvInt("REQUEST_TIMEOUT_SECONDS", 60)) * time.Second,
+		ESPNAlertInterval:       time.Duration(getEnvInt("ESPN_ALERT_POLL_MINUTES", 30)) * time.Minute,
+		ESPNServiceURL:          getEnv("ESPN_SERVICE_URL", "http://localhost:5002"),
+		ESPNNativeClient:        getEnv("ESPN_NATIVE_CLIENT", "false") == "true",
+		SeasonOverride:          getEnv("ENABLE_SEASON_OVERRIDE", "false") == "true",
+		DefenseRankingsInterval: time.Duration(getEnvInt("DEFENSE_RANKINGS_REFRESH_HOURS", 6)) * time.Hour,
+		IdempotencyTTL:          time.Duration(getEnvInt("IDEMPOTENCY_TTL_MINUTES", 60)) * time.Minute,
+		LeaderboardMaxAge:       time.Duration(getEnvInt("LEADERBOARD_CACHE_SECONDS", 60)) * time.Second,
+
+		RecommendationScoringInterval: time.Duration(getEnvInt("RECOMMENDATION_SCORING_HOURS", 168)) * time.Hour,
+
+		MongoMaxPoolSize:            getEnvInt("MONGO_MAX_POOL_SIZE", 50),
+		MongoMinPoolSize:            getEnvInt("MONGO_MIN_POOL_SIZE", 10),
+		MongoConnectTimeout:         time.Duration(getEnvInt("MONGO_CONNECT_TIMEOUT_SECONDS", 30)) * time.Second,
+		MongoServerSelectionTimeout: time.Duration(getEnvInt("MONGO_SERVER_SELECTION_TIMEOUT_SECONDS", 30)) * time.Second,
+		MongoRetry:                  getEnv("MONGO_RETRY", "true") == "true",
+		MongoHealthInterval:         time.Duration(getEnvInt("MONGO_HEALTH_CHECK_SECONDS", 10)) * time.Second,
 	}
 
+	// Each AI route group can be tuned on its own, falling back to the shared limit
+	cfg.InsightsRateLimit = getEnvInt("INSIGHTS_RATE_LIMIT_PER_MINUTE", cfg.AIRateLimit)
+	cfg.ChatbotRateLimit = getEnvInt("CHATBOT_RATE_LIMIT_PER_MINUTE", cfg.AIRateLimit)
+	cfg.ESPNAIRateLimit = getEnvInt("ESPN_AI_RATE_LIMIT_PER_MINUTE", cfg.AIRateLimit)
+	cfg.TradesRateLimit = getEnvInt("TRADES_RATE_LIMIT_PER_MINUTE", cfg.AIRateLimit)
+
 	// Validate critical config
 	if cfg.GeminiAPIKey == "" {
 		log.Println("WARNING: GEMINI_API_KEY not set - AI features will not work")
@@ -49,12 +101,50 @@ func Load() *Config {
 		log.Println("WARNING: Yahoo Fantasy credentials not fully configured - fantasy integration will be disabled")
 	}
 
+	if cfg.SeasonOverride {
+		log.Println("WARNING: ENABLE_SEASON_OVERRIDE is set - requests can override the current season and week")
+	}
+
 	return cfg
 }
 
+// MongoPool returns the configured connection pool settings
+func (c *Config) MongoPool() mongodb.PoolOptions {
+	pool := mongodb.DefaultPoolOptions()
+	if c.MongoMaxPoolSize > 0 {
+		pool.MaxPoolSize = uint64(c.MongoMaxPoolSize)
+	}
+	if c.MongoMinPoolSize >= 0 {
+		pool.MinPoolSize = uint64(c.MongoMinPoolSize)
+	}
+	// The driver rejects a minimum above the maximum, so cap it
+	if pool.MinPoolSize > pool.MaxPoolSize {
+		pool.MinPoolSize = pool.MaxPoolSize
+	}
+	if c.MongoConnectTimeout > 0 {
+		pool.ConnectTimeout = c.MongoConnectTimeout
+	}
+	if c.MongoServerSelectionTimeout > 0 {
+		pool.ServerSelectionTimeout = c.MongoServerSelectionTimeout
+	}
+	pool.RetryWrites = c.MongoRetry
+	pool.RetryReads = c.MongoRetry
+	return pool
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+		log.Printf("WARNING: invalid integer for %s: %q, using default %d", key, value, defaultValue)
+	}
+	return defaultValue
+}
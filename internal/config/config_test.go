@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestMongoPool(t *testing.T) {
+	tests := []struct {
+		name     string
+		max, min int
+		wantMax  uint64
+		wantMin  uint64
+	}{
+		{"defaults", 50, 10, 50, 10},
+		{"max below the default min", 5, 10, 5, 5},
+		{"min above max", 20, 30, 20, 20},
+		{"zero min", 20, 0, 20, 0},
+		{"unset max keeps the default", 0, 10, 50, 10},
+		{"negative min keeps the default", 50, -1, 50, 10},
+		{"negative min capped by a small max", 4, -1, 4, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{MongoMaxPoolSize: tt.max, MongoMinPoolSize: tt.min}
+			pool := cfg.MongoPool()
+			if pool.MaxPoolSize != tt.wantMax || pool.MinPoolSize != tt.wantMin {
+				t.Errorf("MongoPool() max/min = %d/%d, want %d/%d", pool.MaxPoolSize, pool.MinPoolSize, tt.wantMax, tt.wantMin)
+			}
+		})
+	}
+}
+
+func TestLoadESPNSettings(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceURL string
+		native     string
+		wantURL    string
+		wantNative bool
+	}{
+		{"defaults", "", "", "http://localhost:5002", false},
+		{"native client", "", "true", "http://localhost:5002", true},
+		{"custom service URL", "http://espn:5002", "false", "http://espn:5002", false},
+		{"only exactly true enables", "", "1", "http://localhost:5002", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ESPN_SERVICE_URL", tt.serviceURL)
+			t.Setenv("ESPN_NATIVE_CLIENT", tt.native)
+
+			cfg := Load()
+			if cfg.ESPNServiceURL != tt.wantURL {
+				t.Errorf("ESPNServiceURL = %q, want %q", cfg.ESPNServiceURL, tt.wantURL)
+			}
+			if cfg.ESPNNativeClient != tt.wantNative {
+				t.Errorf("ESPNNativeClient = %v, want %v", cfg.ESPNNativeClient, tt.wantNative)
+			}
+		})
+	}
+}
+
+func TestLoadDBName(t *testing.T) {
+	t.Setenv("DB_NAME", "")
+	if got := Load().DBName; got != "nfl_platform" {
+		t.Errorf("DBName = %q, want the default nfl_platform", got)
+	}
+
+	t.Setenv("DB_NAME", "nfl_staging")
+	if got := Load().DBName; got != "nfl_staging" {
+		t.Errorf("DBName = %q, want nfl_staging", got)
+	}
+}
+
+// TestToolsUseConfiguredDBName guards against a script or cmd tool opening a hardcoded
+// database, which would load into one database while the API reads another
+func TestToolsUseConfiguredDBName(t *testing.T) {
+	var files []string
+	for _, pattern := range []string{"../../scripts/*.go", "../../cmd/*/*.go"} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatalf("Glob(%q) error = %v", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		t.Fatal("no scripts or cmd tools found")
+	}
+
+	hardcoded := regexp.MustCompile(`\.Database\("`)
+	for _, path := range files {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile(%q) error = %v", path, err)
+		}
+		if hardcoded.Match(src) {
+			t.Errorf("%s opens a hardcoded database; use cfg.DBName", path)
+		}
+	}
+}
+
+func TestLoadAIRateLimits(t *testing.T) {
+	t.Setenv("AI_RATE_LIMIT_PER_MINUTE", "20")
+	t.Setenv("INSIGHTS_RATE_LIMIT_PER_MINUTE", "")
+	t.Setenv("CHATBOT_RATE_LIMIT_PER_MINUTE", "5")
+	t.Setenv("ESPN_AI_RATE_LIMIT_PER_MINUTE", "")
+	t.Setenv("TRADES_RATE_LIMIT_PER_MINUTE", "")
+
+	cfg := Load()
+	if cfg.InsightsRateLimit != 20 || cfg.ESPNAIRateLimit != 20 || cfg.TradesRateLimit != 20 {
+		t.Errorf("insights/ESPN/trades limits = %d/%d/%d, want the shared 20", cfg.InsightsRateLimit, cfg.ESPNAIRateLimit, cfg.TradesRateLimit)
+	}
+	if cfg.ChatbotRateLimit != 5 {
+		t.Errorf("ChatbotRateLimit = %d, want 5", cfg.ChatbotRateLimit)
+	}
+}
@@ -2,6 +2,11 @@ package handlers
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"net/http"
 	"os"
 	"time"
@@ -15,11 +20,59 @@ import (
 )
 
 type AuthHandler struct {
-	db *mongo.Database
+	db     *mongo.Database
+	tokens tokenStore
+	// findUser loads the user a refresh token was issued to
+	findUser func(ctx context.Context, id bson.ObjectID) (*models.User, error)
 }
 
 func NewAuthHandler(db *mongo.Database) *AuthHandler {
-	return &AuthHandler{db: db}
+	h := &AuthHandler{
+		db:     db,
+		tokens: mongoTokenStore{collection: db.Collection("refresh_tokens")},
+	}
+	h.findUser = h.findUserByID
+	return h
+}
+
+// tokenStore keeps refresh tokens by hash. Take removes the token as it reads it, so each
+// token can be exchanged once; it returns mongo.ErrNoDocuments for an unknown hash.
+type tokenStore interface {
+	Insert(ctx context.Context, token models.RefreshToken) error
+	Take(ctx context.Context, hash string) (*models.RefreshToken, error)
+	Revoke(ctx context.Context, hash string) error
+}
+
+// mongoTokenStore keeps refresh tokens in refresh_tokens
+type mongoTokenStore struct {
+	collection *mongo.Collection
+}
+
+func (s mongoTokenStore) Insert(ctx context.Context, token models.RefreshToken) error {
+	_, err := s.collection.InsertOne(ctx, token)
+	return err
+}
+
+func (s mongoTokenStore) Take(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	// Deleting on lookup makes each refresh token single-use, even under concurrent refreshes
+	var stored models.RefreshToken
+	if err := s.collection.FindOneAndDelete(ctx, bson.M{"token_hash": hash}).Decode(&stored); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (s mongoTokenStore) Revoke(ctx context.Context, hash string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"token_hash": hash})
+	return err
+}
+
+func (h *AuthHandler) findUserByID(ctx context.Context, id bson.ObjectID) (*models.User, error) {
+	var user models.User
+	if err := h.db.Collection("users").FindOne(ctx, bson.M{"_id": id}).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
 }
 
 type RegisterRequest struct {
@@ -33,10 +86,19 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// refreshTokenTTL is how long a refresh token can be exchanged before the user must log in again
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 type TokenResponse struct {
-	Token     string               `json:"token"`
-	ExpiresAt time.Time            `json:"expires_at"`
-	User      models.UserResponse  `json:"user"`
+	Token            string               `json:"token"`
+	ExpiresAt        time.Time            `json:"expires_at"`
+	RefreshToken     string               `json:"refresh_token"`
+	RefreshExpiresAt time.Time            `json:"refresh_expires_at"`
+	User             *models.UserResponse `json:"user,omitempty"`
 }
 
 // Register creates a new user account
@@ -49,7 +111,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	// Check if user already exists
 	collection := h.db.Collection("users")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	var existingUser models.User
@@ -82,18 +144,14 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, expiresAt, err := generateToken(user.ID.Hex(), user.Email)
+	// Generate access and refresh tokens
+	tokens, err := h.issueTokens(ctx, &user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, TokenResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		User:      user.ToResponse(),
-	})
+	c.JSON(http.StatusCreated, tokens)
 }
 
 // Login authenticates a user and returns a JWT token
@@ -106,7 +164,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	// Find user
 	collection := h.db.Collection("users")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	var user models.User
@@ -123,42 +181,112 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, expiresAt, err := generateToken(user.ID.Hex(), user.Email)
+	// Generate access and refresh tokens
+	tokens, err := h.issueTokens(ctx, &user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, TokenResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		User:      user.ToResponse(),
-	})
+	c.JSON(http.StatusOK, tokens)
 }
 
-// RefreshToken generates a new JWT token from an existing valid token
+// RefreshToken exchanges a refresh token for a new access token. The refresh token is
+// rotated: the presented token is invalidated and a new one is returned with the access token.
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	// This would typically validate the existing token and issue a new one
-	// For hackathon simplicity, we'll just require a valid token in header
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	stored, err := h.tokens.Take(ctx, hashRefreshToken(req.RefreshToken))
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && time.Now().After(stored.ExpiresAt)) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
 		return
 	}
 
-	email, _ := c.Get("email")
+	user, err := h.findUser(ctx, stored.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
 
-	token, expiresAt, err := generateToken(userID.(string), email.(string))
+	tokens, err := h.issueTokens(ctx, user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
+	tokens.User = nil
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout revokes a refresh token. Access tokens already issued remain valid until they expire.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := h.tokens.Revoke(ctx, hashRefreshToken(req.RefreshToken)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// issueTokens generates an access token and stores a new refresh token for the user
+func (h *AuthHandler) issueTokens(ctx context.Context, user *models.User) (*TokenResponse, error) {
+	token, expiresAt, err := generateToken(user.ID.Hex(), user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	stored := models.RefreshToken{
+		ID:        bson.NewObjectID(),
+		TokenHash: hashRefreshToken(refreshToken),
+		UserID:    user.ID,
+		ExpiresAt: now.Add(refreshTokenTTL),
+		CreatedAt: now,
+	}
+	if err := h.tokens.Insert(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	resp := user.ToResponse()
+	return &TokenResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt,
+		User:             &resp,
+	}, nil
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"token":      token,
-		"expires_at": expiresAt,
-	})
+// hashRefreshToken returns the hex SHA-256 of a refresh token, as stored in the database
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
 // generateToken creates a new JWT token
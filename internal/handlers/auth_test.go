@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// memTokenStore is a tokenStore over an in-memory map of token hashes
+type memTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]models.RefreshToken
+}
+
+func (s *memTokenStore) Insert(ctx context.Context, token models.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (s *memTokenStore) Take(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[hash]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	delete(s.tokens, hash)
+	return &token, nil
+}
+
+func (s *memTokenStore) Revoke(ctx context.Context, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, hash)
+	return nil
+}
+
+// newTestAuth returns an auth router backed by an in-memory token store, with a refresh
+// token already issued to one user
+func newTestAuth(t *testing.T) (*gin.Engine, *memTokenStore, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	user := &models.User{ID: bson.NewObjectID(), Email: "fan@example.com", Username: "fan"}
+	store := &memTokenStore{tokens: make(map[string]models.RefreshToken)}
+	h := &AuthHandler{
+		tokens: store,
+		findUser: func(ctx context.Context, id bson.ObjectID) (*models.User, error) {
+			if id != user.ID {
+				return nil, mongo.ErrNoDocuments
+			}
+			return user, nil
+		},
+	}
+
+	issued, err := h.issueTokens(context.Background(), user)
+	if err != nil {
+		t.Fatalf("issueTokens() error = %v", err)
+	}
+
+	router := gin.New()
+	router.POST("/auth/refresh", h.RefreshToken)
+	router.POST("/auth/logout", h.Logout)
+	return router, store, issued.RefreshToken
+}
+
+func postToken(router *gin.Engine, path, refreshToken string) *httptest.ResponseRecorder {
+	body := `{"refresh_token":"` + refreshToken + `"}`
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRefreshTokenRotates(t *testing.T) {
+	router, store, original := newTestAuth(t)
+
+	w := postToken(router, "/auth/refresh", original)
+	if w.Code != http.StatusOK {
+		t.Fatalf("refresh = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var resp TokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Token == "" || resp.RefreshToken == "" || resp.RefreshToken == original {
+		t.Fatalf("refresh response = %+v, want a new access and refresh token", resp)
+	}
+	if resp.User != nil {
+		t.Errorf("refresh response includes the user")
+	}
+
+	// The presented token was rotated out and can't be exchanged again
+	if w := postToken(router, "/auth/refresh", original); w.Code != http.StatusUnauthorized {
+		t.Errorf("reusing the old refresh token = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if len(store.tokens) != 1 {
+		t.Errorf("store holds %d tokens, want only the rotated one", len(store.tokens))
+	}
+
+	if w := postToken(router, "/auth/refresh", resp.RefreshToken); w.Code != http.StatusOK {
+		t.Errorf("refreshing with the rotated token = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestRefreshTokenRejectedAfterLogout(t *testing.T) {
+	router, store, token := newTestAuth(t)
+
+	if w := postToken(router, "/auth/logout", token); w.Code != http.StatusOK {
+		t.Fatalf("logout = %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(store.tokens) != 0 {
+		t.Errorf("store holds %d tokens after logout, want 0", len(store.tokens))
+	}
+	if w := postToken(router, "/auth/refresh", token); w.Code != http.StatusUnauthorized {
+		t.Errorf("refresh after logout = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRefreshTokenRejectsExpired(t *testing.T) {
+	router, store, token := newTestAuth(t)
+
+	hash := hashRefreshToken(token)
+	expired := store.tokens[hash]
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	store.tokens[hash] = expired
+
+	if w := postToken(router, "/auth/refresh", token); w.Code != http.StatusUnauthorized {
+		t.Errorf("refresh with an expired token = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w := postToken(router, "/auth/refresh", "never-issued"); w.Code != http.StatusUnauthorized {
+		t.Errorf("refresh with an unknown token = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
@@ -1,7 +1,13 @@
 package handlers
 
 import (
+	"context"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ai-atl/nfl-platform/internal/services"
 	"github.com/gin-gonic/gin"
@@ -13,10 +19,10 @@ type ChatbotHandler struct {
 	chatbotService *services.ChatbotService
 }
 
-func NewChatbotHandler(db *mongo.Database) *ChatbotHandler {
+func NewChatbotHandler(db *mongo.Database, historyTurns int) *ChatbotHandler {
 	return &ChatbotHandler{
 		db:             db,
-		chatbotService: services.NewChatbotService(db),
+		chatbotService: services.NewChatbotService(db, historyTurns),
 	}
 }
 
@@ -46,17 +52,94 @@ func (h *ChatbotHandler) Ask(c *gin.Context) {
 	})
 }
 
-// History returns chat history for the user
+// AskStream handles a question to the AI chatbot and streams the response
+// back as server-sent events ("chunk", then "done" or "error"). The route is registered
+// without the API's request timeout, so long answers aren't cut off mid-stream.
+func (h *ChatbotHandler) AskStream(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req ChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Request context is cancelled when the client disconnects, which aborts the upstream call
+	chunks, errs := h.chatbotService.AskStream(c.Request.Context(), userID.(string), req.Question)
+
+	streamChunks(c, req.Question, chunks, errs, func(response string) {
+		h.saveStreamedTurn(userID.(string), req.Question, response)
+	})
+}
+
+// streamChunks forwards chunks to the client as server-sent events until the stream ends,
+// then calls onDone with the full response. Nothing is saved when the stream fails or the
+// client disconnects.
+func streamChunks(c *gin.Context, question string, chunks <-chan string, errs <-chan error, onDone func(response string)) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	var response strings.Builder
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				if err := <-errs; err != nil {
+					c.SSEvent("error", gin.H{"error": err.Error()})
+					return false
+				}
+				onDone(response.String())
+				c.SSEvent("done", gin.H{"question": question})
+				return false
+			}
+			response.WriteString(chunk)
+			c.SSEvent("chunk", gin.H{"text": chunk})
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// saveStreamedTurn persists a completed streamed response to chat history
+func (h *ChatbotHandler) saveStreamedTurn(userID, question, response string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.chatbotService.SaveTurn(ctx, userID, question, response); err != nil {
+		log.Printf("Failed to save chat history for user %s: %v", userID, err)
+	}
+}
+
+// History returns paginated chat history for the user
+// GET /api/v1/chatbot/history?page=1&limit=20
 func (h *ChatbotHandler) History(c *gin.Context) {
-	// TODO: Implement chat history retrieval
+	userID, _ := c.Get("user_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	messages, total, err := h.chatbotService.GetHistory(ctx, userID.(string), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch chat history"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"history": []map[string]interface{}{
-			{
-				"question":  "Who should I start at RB?",
-				"response":  "Based on matchups and recent performance...",
-				"timestamp": "2024-11-08T10:30:00Z",
-			},
-		},
+		"history": messages,
+		"page":    page,
+		"limit":   limit,
+		"total":   total,
 	})
 }
-
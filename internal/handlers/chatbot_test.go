@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bufio"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sseEvent is one parsed server-sent event
+type sseEvent struct {
+	name string
+	data string
+}
+
+// streamRecorder is an httptest.ResponseRecorder that satisfies http.CloseNotifier, which
+// gin's Context.Stream requires
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r streamRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+// readEvents parses the SSE stream written to body
+func readEvents(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			current.name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			current.data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		case line == "" && current.name != "":
+			events = append(events, current)
+			current = sseEvent{}
+		}
+	}
+	return events
+}
+
+func TestStreamChunks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		chunks    []string
+		err       error
+		want      []sseEvent
+		wantSaved string
+	}{
+		{
+			name:   "complete stream",
+			chunks: []string{"Start ", "Bijan."},
+			want: []sseEvent{
+				{"chunk", `{"text":"Start "}`},
+				{"chunk", `{"text":"Bijan."}`},
+				{"done", `{"question":"Start Bijan?"}`},
+			},
+			wantSaved: "Start Bijan.",
+		},
+		{
+			name:   "upstream error",
+			chunks: []string{"Start "},
+			err:    errors.New("API error (500)"),
+			want: []sseEvent{
+				{"chunk", `{"text":"Start "}`},
+				{"error", `{"error":"API error (500)"}`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := make(chan string, len(tt.chunks))
+			errs := make(chan error, 1)
+			for _, chunk := range tt.chunks {
+				chunks <- chunk
+			}
+			close(chunks)
+			errs <- tt.err
+			close(errs)
+
+			w := streamRecorder{httptest.NewRecorder()}
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/chatbot/ask/stream", nil)
+
+			saved := ""
+			streamChunks(c, "Start Bijan?", chunks, errs, func(response string) { saved = response })
+
+			if got := w.Header().Get("Content-Type"); got != "text/event-stream" {
+				t.Errorf("Content-Type = %q, want text/event-stream", got)
+			}
+			got := readEvents(t, w.Body.String())
+			if len(got) != len(tt.want) {
+				t.Fatalf("events = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("event %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+			if saved != tt.wantSaved {
+				t.Errorf("saved response = %q, want %q", saved, tt.wantSaved)
+			}
+		})
+	}
+}
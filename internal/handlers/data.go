@@ -2,12 +2,17 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ai-atl/nfl-platform/internal/httputil"
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/season"
 	"github.com/ai-atl/nfl-platform/internal/services"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -15,11 +20,13 @@ import (
 
 type DataHandler struct {
 	service *services.DataService
+	advisor *services.FantasyAdvisorService
 }
 
 func NewDataHandler(db *mongo.Database) *DataHandler {
 	return &DataHandler{
 		service: services.NewDataService(db),
+		advisor: services.NewFantasyAdvisorService(db),
 	}
 }
 
@@ -29,38 +36,73 @@ func NewDataHandler(db *mongo.Database) *DataHandler {
 
 // GetPlayer - GET /api/data/players/:nfl_id?season=2024
 func (h *DataHandler) GetPlayer(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	nflID := c.Param("nfl_id")
-	season, _ := strconv.Atoi(c.DefaultQuery("season", "2025"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
 
-	player, err := h.service.GetPlayer(ctx, nflID, season)
+	player, err := h.service.GetPlayer(ctx, nflID, year)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		httputil.RespondError(c, http.StatusNotFound, httputil.CodeNotFound, "Player not found")
 		return
 	}
 
 	c.JSON(http.StatusOK, player)
 }
 
+// SearchPlayers - GET /api/data/players/search?q=mahomes&limit=10
+func (h *DataHandler) SearchPlayers(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	query := strings.TrimSpace(c.Query("q"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if query == "" {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "q is required")
+		return
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	players, err := h.service.SearchPlayers(ctx, query, limit)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to search players")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   query,
+		"count":   len(players),
+		"players": players,
+	})
+}
+
 // GetPlayersByTeam - GET /api/data/teams/:team/players?season=2024
 func (h *DataHandler) GetPlayersByTeam(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	team := c.Param("team")
-	season, _ := strconv.Atoi(c.DefaultQuery("season", "2025"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
 
-	players, err := h.service.GetPlayersByTeam(ctx, team, season)
+	players, err := h.service.GetPlayersByTeam(ctx, team, year)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch players"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch players")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"team":    team,
-		"season":  season,
+		"season":  year,
 		"count":   len(players),
 		"players": players,
 	})
@@ -68,21 +110,24 @@ func (h *DataHandler) GetPlayersByTeam(c *gin.Context) {
 
 // GetPlayersByPosition - GET /api/data/positions/:position?season=2024
 func (h *DataHandler) GetPlayersByPosition(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	position := c.Param("position")
-	season, _ := strconv.Atoi(c.DefaultQuery("season", "2025"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
 
-	players, err := h.service.GetPlayersByPosition(ctx, position, season)
+	players, err := h.service.GetPlayersByPosition(ctx, position, year)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch players"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch players")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"position": position,
-		"season":   season,
+		"season":   year,
 		"count":    len(players),
 		"players":  players,
 	})
@@ -90,47 +135,319 @@ func (h *DataHandler) GetPlayersByPosition(c *gin.Context) {
 
 // GetInjuredPlayers - GET /api/data/injuries?season=2024
 func (h *DataHandler) GetInjuredPlayers(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	season, _ := strconv.Atoi(c.DefaultQuery("season", "2025"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	week, ok := httputil.QueryWeek(c, "week", 0)
+	if !ok {
+		return
+	}
 
-	players, err := h.service.GetInjuredPlayers(ctx, season)
+	players, err := h.service.GetInjuredPlayers(ctx, year, week)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch injured players"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch injured players")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"season":  season,
+		"season":  year,
+		"week":    week,
 		"count":   len(players),
 		"players": players,
 	})
 }
 
+// GetPlayerStatus - GET /api/data/players/:nfl_id/status?season=2025&week=6
+func (h *DataHandler) GetPlayerStatus(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	nflID := c.Param("nfl_id")
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	week, ok := httputil.QueryWeek(c, "week", 0)
+	if !ok {
+		return
+	}
+	if week == 0 {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "week is required")
+		return
+	}
+
+	status, err := h.service.GetPlayerStatusForWeek(ctx, nflID, year, week)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		httputil.RespondError(c, http.StatusNotFound, httputil.CodeNotFound, "No status for that week")
+		return
+	}
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch player status")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
 // ========================================
 // STATS ENDPOINTS
 // ========================================
 
-// GetPlayerStats - GET /api/data/players/:nfl_id/stats?season=2024
+// GetPlayerStats - GET /api/data/players/:nfl_id/stats?season=2024&season_type=REG
 func (h *DataHandler) GetPlayerStats(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	nflID := c.Param("nfl_id")
+	year, ok := httputil.QuerySeason(c, 0)
+	if !ok {
+		return
+	}
+	seasonType, ok := seasonTypeParam(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.service.GetPlayerStats(ctx, nflID, year, seasonType)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"nfl_id":      nflID,
+		"season":      year,
+		"season_type": seasonType,
+		"count":       len(stats),
+		"stats":       stats,
+	})
+}
+
+// maxBatchPlayers caps how many players one batch stats request can ask for
+const maxBatchPlayers = 100
+
+type BatchStatsRequest struct {
+	NFLIDs     []string `json:"nfl_ids" binding:"required,min=1"`
+	Season     int      `json:"season"`
+	SeasonType string   `json:"season_type"`
+}
+
+// GetPlayerStatsBatch - POST /api/data/players/batch
+// Body: {"nfl_ids": ["00-0033873", ...], "season": 2025, "season_type": "REG"}
+func (h *DataHandler) GetPlayerStatsBatch(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	var req BatchStatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, err.Error())
+		return
+	}
+	if len(req.NFLIDs) > maxBatchPlayers {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, fmt.Sprintf("at most %d nfl_ids per request", maxBatchPlayers))
+		return
+	}
+	if req.Season == 0 {
+		req.Season = season.Season(c.Request.Context())
+	}
+	if err := httputil.ValidateSeason(req.Season); err != nil {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, err.Error())
+		return
+	}
+	req.SeasonType = strings.ToUpper(req.SeasonType)
+	if req.SeasonType == "" {
+		req.SeasonType = models.SeasonTypeRegular
+	}
+	if !models.IsValidSeasonType(req.SeasonType) {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "season_type must be REG, POST or REGPOST")
+		return
+	}
+
+	stats, err := h.service.GetPlayerStatsBatch(ctx, req.NFLIDs, req.Season, req.SeasonType)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"season":      req.Season,
+		"season_type": req.SeasonType,
+		"count":       len(stats),
+		"stats":       stats,
+	})
+}
+
+// GetPlayerWeeklyStats - GET /api/data/players/:nfl_id/weekly?season=2025&from=1&to=8
+func (h *DataHandler) GetPlayerWeeklyStats(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	nflID := c.Param("nfl_id")
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	fromWeek, ok := httputil.QueryWeek(c, "from", 0)
+	if !ok {
+		return
+	}
+	toWeek, ok := httputil.QueryWeek(c, "to", 0)
+	if !ok {
+		return
+	}
+
+	if fromWeek > 0 && toWeek > 0 && fromWeek > toWeek {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "from must be less than or equal to to")
+		return
+	}
+
+	weeklyStats, err := h.service.GetPlayerWeeklyStatsRange(ctx, nflID, year, fromWeek, toWeek)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch weekly stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"nfl_id": nflID,
+		"season": year,
+		"count":  len(weeklyStats),
+		"weeks":  weeklyStats,
+	})
+}
+
+// GetPlayerExtremes - GET /api/data/players/:nfl_id/extremes?season=2025
+func (h *DataHandler) GetPlayerExtremes(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	nflID := c.Param("nfl_id")
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+
+	extremes, err := h.service.GetPlayerExtremes(ctx, nflID, year)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch player games")
+		return
+	}
+
+	c.JSON(http.StatusOK, extremes)
+}
+
+// GetPlayerGameLog - GET /api/data/players/:nfl_id/gamelog?season=2025
+func (h *DataHandler) GetPlayerGameLog(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	nflID := c.Param("nfl_id")
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+
+	games, err := h.service.GetGameLog(ctx, nflID, year)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch game log")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"nfl_id": nflID,
+		"season": year,
+		"count":  len(games),
+		"games":  games,
+	})
+}
+
+// GetPlayerEPATrend - GET /api/data/players/:nfl_id/epa/trend?season=2025&from=1&to=8
+func (h *DataHandler) GetPlayerEPATrend(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	nflID := c.Param("nfl_id")
-	season, _ := strconv.Atoi(c.Query("season"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	fromWeek, ok := httputil.QueryWeek(c, "from", 0)
+	if !ok {
+		return
+	}
+	toWeek, ok := httputil.QueryWeek(c, "to", 0)
+	if !ok {
+		return
+	}
 
-	stats, err := h.service.GetPlayerStats(ctx, nflID, season)
+	if fromWeek > 0 && toWeek > 0 && fromWeek > toWeek {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "from must be less than or equal to to")
+		return
+	}
+
+	trend, err := h.service.GetPlayerEPATrend(ctx, nflID, year, fromWeek, toWeek)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stats"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch EPA trend")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"nfl_id": nflID,
-		"season": season,
+		"season": year,
+		"count":  len(trend),
+		"weeks":  trend,
+	})
+}
+
+// GetPlayerRedZone - GET /api/data/players/:nfl_id/redzone?season=2025
+func (h *DataHandler) GetPlayerRedZone(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	nflID := c.Param("nfl_id")
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+
+	usage, err := h.service.GetRedZoneUsage(ctx, nflID, year)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch red zone usage")
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// GetPlayerQBR - GET /api/data/players/:nfl_id/qbr?season=2025
+func (h *DataHandler) GetPlayerQBR(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	nflID := c.Param("nfl_id")
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+
+	stats, err := h.service.GetPlayerQBR(ctx, nflID, year)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			httputil.RespondError(c, http.StatusNotFound, httputil.CodeNotFound, "Player not found")
+			return
+		}
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch QBR")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"nfl_id": nflID,
+		"season": year,
 		"count":  len(stats),
-		"stats":  stats,
+		"qbr":    stats,
 	})
 }
 
@@ -140,21 +457,24 @@ func (h *DataHandler) GetPlayerStats(c *gin.Context) {
 
 // GetPlayerEPA - GET /api/data/players/:nfl_id/epa?season=2024
 func (h *DataHandler) GetPlayerEPA(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	nflID := c.Param("nfl_id")
-	season, _ := strconv.Atoi(c.Query("season"))
+	year, ok := httputil.QuerySeason(c, 0)
+	if !ok {
+		return
+	}
 
-	epa, playCount, err := h.service.CalculatePlayerEPA(ctx, nflID, season)
+	epa, playCount, err := h.service.CalculatePlayerEPA(ctx, nflID, year)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate EPA"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to calculate EPA")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"nfl_id":     nflID,
-		"season":     season,
+		"season":     year,
 		"epa":        epa,
 		"play_count": playCount,
 	})
@@ -162,86 +482,162 @@ func (h *DataHandler) GetPlayerEPA(c *gin.Context) {
 
 // GetTeamEPA - GET /api/data/teams/:team/epa?season=2024
 func (h *DataHandler) GetTeamEPA(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	team := c.Param("team")
-	season, _ := strconv.Atoi(c.Query("season"))
+	year, ok := httputil.QuerySeason(c, 0)
+	if !ok {
+		return
+	}
 
-	epa, playCount, err := h.service.CalculateTeamEPA(ctx, team, season)
+	epa, playCount, err := h.service.CalculateTeamEPA(ctx, team, year)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate EPA"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to calculate EPA")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"team":       team,
-		"season":     season,
+		"season":     year,
 		"epa":        epa,
 		"play_count": playCount,
 	})
 }
 
+// GetTeamTendencies - GET /api/data/teams/:team/tendencies?season=2025
+func (h *DataHandler) GetTeamTendencies(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	team := strings.ToUpper(c.Param("team"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+
+	tendencies, err := h.service.GetTeamTendencies(ctx, team, year)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to calculate tendencies")
+		return
+	}
+
+	c.JSON(http.StatusOK, tendencies)
+}
+
 // ========================================
 // PLAYS ENDPOINTS
 // ========================================
 
-// GetPlayerPlays - GET /api/data/players/:nfl_id/plays?season=2024&limit=100
+// maxPlayPageLimit caps how many plays one page can return
+const maxPlayPageLimit = 500
+
+// playPageParams reads limit (default 100) and offset (default 0) for paging through plays,
+// responding 400 for a limit outside 1-500 or a negative or non-numeric offset
+func playPageParams(c *gin.Context) (int, int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > maxPlayPageLimit {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, fmt.Sprintf("limit must be between 1 and %d", maxPlayPageLimit))
+		return 0, 0, false
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "offset must be a non-negative integer")
+		return 0, 0, false
+	}
+	return limit, offset, true
+}
+
+// GetPlayerPlays - GET /api/data/players/:nfl_id/plays?season=2024&limit=100&offset=0
+// With summary=true it returns the plays aggregated into pass, rush and receiving totals
+// instead of the plays themselves (limit is ignored).
 func (h *DataHandler) GetPlayerPlays(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	nflID := c.Param("nfl_id")
-	season, _ := strconv.Atoi(c.Query("season"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	year, ok := httputil.QuerySeason(c, 0)
+	if !ok {
+		return
+	}
+
+	if summarize, _ := strconv.ParseBool(c.DefaultQuery("summary", "false")); summarize {
+		summary, err := h.service.GetPlayerPlaySummary(ctx, nflID, year)
+		if err != nil {
+			httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to summarize plays")
+			return
+		}
 
-	plays, err := h.service.GetPlayerPlays(ctx, nflID, season, limit)
+		c.JSON(http.StatusOK, gin.H{
+			"nfl_id":  nflID,
+			"season":  year,
+			"summary": summary,
+		})
+		return
+	}
+
+	limit, offset, ok := playPageParams(c)
+	if !ok {
+		return
+	}
+
+	plays, hasMore, err := h.service.GetPlayerPlays(ctx, nflID, year, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plays"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch plays")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"nfl_id": nflID,
-		"season": season,
-		"count":  len(plays),
-		"plays":  plays,
+		"nfl_id":   nflID,
+		"season":   year,
+		"offset":   offset,
+		"count":    len(plays),
+		"has_more": hasMore,
+		"plays":    plays,
 	})
 }
 
-// GetTeamPlays - GET /api/data/teams/:team/plays?season=2024&limit=100
+// GetTeamPlays - GET /api/data/teams/:team/plays?season=2024&limit=100&offset=0
 func (h *DataHandler) GetTeamPlays(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	team := c.Param("team")
-	season, _ := strconv.Atoi(c.Query("season"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	year, ok := httputil.QuerySeason(c, 0)
+	if !ok {
+		return
+	}
+	limit, offset, ok := playPageParams(c)
+	if !ok {
+		return
+	}
 
-	plays, err := h.service.GetTeamPlays(ctx, team, season, limit)
+	plays, hasMore, err := h.service.GetTeamPlays(ctx, team, year, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plays"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch plays")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"team":   team,
-		"season": season,
-		"count":  len(plays),
-		"plays":  plays,
+		"team":     team,
+		"season":   year,
+		"offset":   offset,
+		"count":    len(plays),
+		"has_more": hasMore,
+		"plays":    plays,
 	})
 }
 
 // GetGamePlays - GET /api/data/games/:game_id/plays
 func (h *DataHandler) GetGamePlays(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	gameID := c.Param("game_id")
 
 	plays, err := h.service.GetGamePlays(ctx, gameID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plays"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch plays")
 		return
 	}
 
@@ -258,105 +654,404 @@ func (h *DataHandler) GetGamePlays(c *gin.Context) {
 
 // GetPlayerNGS - GET /api/data/players/:nfl_id/ngs?stat_type=passing&season=2024
 func (h *DataHandler) GetPlayerNGS(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	nflID := c.Param("nfl_id")
 	statType := c.Query("stat_type")
-	season, _ := strconv.Atoi(c.Query("season"))
+	year, ok := httputil.QuerySeason(c, 0)
+	if !ok {
+		return
+	}
 
-	stats, err := h.service.GetPlayerNGS(ctx, nflID, statType, season)
+	stats, err := h.service.GetPlayerNGS(ctx, nflID, statType, year)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NGS stats"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch NGS stats")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"nfl_id":    nflID,
 		"stat_type": statType,
-		"season":    season,
+		"season":    year,
 		"count":     len(stats),
 		"stats":     stats,
 	})
 }
 
-// GetNGSLeaders - GET /api/data/ngs/leaders?stat_type=passing&season=2024&metric=completion_percentage_above_expectation&limit=10
+// GetPlayerNGSTrend - GET /api/data/players/:nfl_id/ngs/trend?stat_type=receiving&metric=avg_separation&season=2025
+func (h *DataHandler) GetPlayerNGSTrend(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	nflID := c.Param("nfl_id")
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+
+	trend, err := h.service.GetPlayerNGSTrend(ctx, nflID, c.Query("stat_type"), c.Query("metric"), year)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidNGSQuery) {
+			httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, err.Error())
+			return
+		}
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch NGS trend")
+		return
+	}
+
+	c.JSON(http.StatusOK, trend)
+}
+
+// maxLeaderLimit caps how many rows a leaderboard can return
+const maxLeaderLimit = 100
+
+// leaderLimitParam reads limit (default 10) for a leaderboard, responding 400 outside 1-100
+func leaderLimitParam(c *gin.Context) (int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > maxLeaderLimit {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, fmt.Sprintf("limit must be between 1 and %d", maxLeaderLimit))
+		return 0, false
+	}
+	return limit, true
+}
+
+// GetNGSLeaders - GET /api/data/ngs/leaders?stat_type=passing&season=2024&metric=avg_time_to_throw&direction=asc&week=0&limit=10
 func (h *DataHandler) GetNGSLeaders(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	statType := c.Query("stat_type")
-	season, _ := strconv.Atoi(c.Query("season"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	week, ok := httputil.QueryWeek(c, "week", 0)
+	if !ok {
+		return
+	}
 	metric := c.Query("metric")
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	direction := strings.ToLower(c.DefaultQuery("direction", "desc"))
+	limit, ok := leaderLimitParam(c)
+	if !ok {
+		return
+	}
 
-	stats, err := h.service.GetNGSLeaders(ctx, statType, season, metric, limit)
+	if direction != "asc" && direction != "desc" {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "direction must be asc or desc")
+		return
+	}
+
+	stats, err := h.service.GetNGSLeaders(ctx, services.NGSLeadersQuery{
+		StatType:  statType,
+		Season:    year,
+		Week:      week,
+		Metric:    metric,
+		Ascending: direction == "asc",
+		Limit:     limit,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NGS leaders"})
+		if errors.Is(err, services.ErrInvalidNGSQuery) {
+			httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, err.Error())
+			return
+		}
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch NGS leaders")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"stat_type": statType,
-		"season":    season,
+		"season":    year,
+		"week":      week,
 		"metric":    metric,
+		"direction": direction,
 		"count":     len(stats),
 		"leaders":   stats,
 	})
 }
 
+// GetReceivingLeaders - GET /api/data/ngs/receiving/leaders?season=2024&metric=avg_separation&min_targets=30&limit=10
+// Ranks wide receivers by separation (or avg_cushion / avg_yac_above_expectation), skipping
+// players under min_targets so one-target outliers don't top the list. min_targets
+// defaults to 30 for season totals and 4 for a single week.
+func (h *DataHandler) GetReceivingLeaders(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	week, ok := httputil.QueryWeek(c, "week", 0)
+	if !ok {
+		return
+	}
+	metric := c.DefaultQuery("metric", "avg_separation")
+	position := strings.ToUpper(c.DefaultQuery("position", "WR"))
+	limit, ok := leaderLimitParam(c)
+	if !ok {
+		return
+	}
+
+	defaultMinTargets := "30"
+	if week > 0 {
+		defaultMinTargets = "4"
+	}
+	minTargets, _ := strconv.Atoi(c.DefaultQuery("min_targets", defaultMinTargets))
+
+	switch metric {
+	case "avg_separation", "avg_cushion", "avg_yac_above_expectation":
+	default:
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "metric must be avg_separation, avg_cushion or avg_yac_above_expectation")
+		return
+	}
+	if position == "ALL" {
+		position = ""
+	}
+
+	stats, err := h.service.GetNGSLeaders(ctx, services.NGSLeadersQuery{
+		StatType:  "receiving",
+		Season:    year,
+		Week:      week,
+		Metric:    metric,
+		Position:  position,
+		MinSample: minTargets,
+		Limit:     limit,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidNGSQuery) {
+			httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, err.Error())
+			return
+		}
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch receiving leaders")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"season":      year,
+		"week":        week,
+		"metric":      metric,
+		"position":    position,
+		"min_targets": minTargets,
+		"count":       len(stats),
+		"leaders":     stats,
+	})
+}
+
+// ComparePlayers - GET /api/data/compare?a=00-0036355&b=00-0035710&season=2025&week=11
+// Returns both players' recent games, EPA, trend and matchup side by side for charts.
+// This is the data behind the AI start/sit advice, without the AI call.
+func (h *DataHandler) ComparePlayers(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	nflIDA := c.Query("a")
+	nflIDB := c.Query("b")
+	currentSeason, currentWeek := season.Current(c.Request.Context())
+	year, ok := httputil.QuerySeason(c, currentSeason)
+	if !ok {
+		return
+	}
+	week, ok := httputil.QueryWeek(c, "week", currentWeek)
+	if !ok {
+		return
+	}
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+
+	if nflIDA == "" || nflIDB == "" {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "a and b player IDs are required")
+		return
+	}
+
+	players := make([]*services.EnrichedPlayerData, 2)
+	for i, nflID := range []string{nflIDA, nflIDB} {
+		enriched, err := h.advisor.WithScoring(scoring).EnrichPlayer(ctx, nflID, year, week)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				httputil.RespondError(c, http.StatusNotFound, httputil.CodeNotFound, "Player not found: "+nflID)
+				return
+			}
+			httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to load player data")
+			return
+		}
+		players[i] = enriched
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"season":  year,
+		"week":    week,
+		"scoring": scoring.Name,
+		"a":       players[0],
+		"b":       players[1],
+	})
+}
+
+// ========================================
+// DEFENSE ENDPOINTS
+// ========================================
+
+// GetDefensiveRankings - GET /api/data/defense/rankings?position=WR&season=2025&through_week=10
+func (h *DataHandler) GetDefensiveRankings(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	position := strings.ToUpper(c.Query("position"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	throughWeek, ok := httputil.QueryWeek(c, "through_week", 0)
+	if !ok {
+		return
+	}
+
+	switch position {
+	case "QB", "RB", "WR", "TE":
+	default:
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "position must be one of QB, RB, WR, TE")
+		return
+	}
+
+	rankings, err := h.service.GetDefensiveRankings(ctx, position, year, throughWeek)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to compute defensive rankings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"position":     position,
+		"season":       year,
+		"through_week": throughWeek,
+		"count":        len(rankings),
+		"rankings":     rankings,
+	})
+}
+
 // ========================================
 // GAME ENDPOINTS
 // ========================================
 
 // GetGame - GET /api/data/games/:game_id
 func (h *DataHandler) GetGame(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	gameID := c.Param("game_id")
 
 	game, err := h.service.GetGame(ctx, gameID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		httputil.RespondError(c, http.StatusNotFound, httputil.CodeNotFound, "Game not found")
 		return
 	}
 
 	c.JSON(http.StatusOK, game)
 }
 
+// GetGameProjection - GET /api/data/games/:game_id/projection
+func (h *DataHandler) GetGameProjection(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	projection, err := h.service.ProjectGame(ctx, c.Param("game_id"))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		httputil.RespondError(c, http.StatusNotFound, httputil.CodeNotFound, "Game not found")
+		return
+	}
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to project game")
+		return
+	}
+
+	c.JSON(http.StatusOK, projection)
+}
+
 // GetGamesBySeason - GET /api/data/games?season=2024&week=1
 func (h *DataHandler) GetGamesBySeason(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	season, _ := strconv.Atoi(c.Query("season"))
-	week, _ := strconv.Atoi(c.Query("week"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	week, ok := httputil.QueryWeek(c, "week", 0)
+	if !ok {
+		return
+	}
 
-	games, err := h.service.GetGamesBySeason(ctx, season, week)
+	games, err := h.service.GetGamesBySeason(ctx, year, week)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch games"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch games")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"season": season,
+		"season": year,
 		"week":   week,
 		"count":  len(games),
 		"games":  games,
 	})
 }
 
+// GetTeams - GET /api/data/teams?conference=AFC
+func (h *DataHandler) GetTeams(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	conference := strings.ToUpper(c.Query("conference"))
+	if conference != "" && conference != "AFC" && conference != "NFC" {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "conference must be AFC or NFC")
+		return
+	}
+
+	teams, err := h.service.GetTeams(ctx, conference)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch teams")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count": len(teams),
+		"teams": teams,
+	})
+}
+
+// GetStandings - GET /api/data/standings?season=2025&through_week=10
+func (h *DataHandler) GetStandings(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	throughWeek, ok := httputil.QueryWeek(c, "through_week", 0)
+	if !ok {
+		return
+	}
+
+	standings, err := h.service.GetStandings(ctx, year, throughWeek)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to compute standings")
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
 // GetUpcomingGames - GET /api/data/teams/:team/upcoming
 func (h *DataHandler) GetUpcomingGames(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	team := c.Param("team")
 
 	games, err := h.service.GetUpcomingGames(ctx, team)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch games"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch games")
 		return
 	}
 
@@ -367,22 +1062,97 @@ func (h *DataHandler) GetUpcomingGames(c *gin.Context) {
 	})
 }
 
+// GetTeamSchedule - GET /api/data/teams/:team/schedule?season=2025
+func (h *DataHandler) GetTeamSchedule(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	team := c.Param("team")
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.GetTeamSchedule(ctx, team, year)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// GetTeamLeaders - GET /api/data/teams/:team/leaders?season=2025
+func (h *DataHandler) GetTeamLeaders(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	team := c.Param("team")
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+
+	leaders, err := h.service.GetTeamLeaders(ctx, team, year)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch team leaders")
+		return
+	}
+
+	c.JSON(http.StatusOK, leaders)
+}
+
 // GetScheduledGames - GET /api/data/games/scheduled?season=2025&week=10
 func (h *DataHandler) GetScheduledGames(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	season, _ := strconv.Atoi(c.DefaultQuery("season", "2025"))
-	week, _ := strconv.Atoi(c.Query("week"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	week, ok := httputil.QueryWeek(c, "week", 0)
+	if !ok {
+		return
+	}
+
+	games, err := h.service.GetScheduledGames(ctx, year, week)
+	if err != nil {
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch scheduled games")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"season": year,
+		"week":   week,
+		"count":  len(games),
+		"games":  games,
+	})
+}
+
+// GetScoreboard - GET /api/data/scoreboard?season=2025&week=11
+func (h *DataHandler) GetScoreboard(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	currentSeason, currentWeek := season.Current(c.Request.Context())
+	year, ok := httputil.QuerySeason(c, currentSeason)
+	if !ok {
+		return
+	}
+	week, ok := httputil.QueryWeek(c, "week", currentWeek)
+	if !ok {
+		return
+	}
 
-	games, err := h.service.GetScheduledGames(ctx, season, week)
+	games, err := h.service.GetScoreboard(ctx, year, week)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduled games"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch scoreboard")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"season": season,
+		"season": year,
 		"week":   week,
 		"count":  len(games),
 		"games":  games,
@@ -395,21 +1165,24 @@ func (h *DataHandler) GetScheduledGames(c *gin.Context) {
 
 // GetPlayerSummary - GET /api/data/players/:nfl_id/summary?season=2024
 func (h *DataHandler) GetPlayerSummary(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second) // Fast now - EPA pre-calculated
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second) // Fast now - EPA pre-calculated
 	defer cancel()
 
 	nflID := c.Param("nfl_id")
-	season, _ := strconv.Atoi(c.DefaultQuery("season", "2025"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
 
-	log.Printf("🔍 GetPlayerSummary: nfl_id=%s, season=%d", nflID, season)
+	log.Printf("🔍 GetPlayerSummary: nfl_id=%s, season=%d", nflID, year)
 
-	summary, err := h.service.GetPlayerSummary(ctx, nflID, season)
+	summary, err := h.service.GetPlayerSummary(ctx, nflID, year)
 	if err != nil {
 		log.Printf("❌ GetPlayerSummary error: %v", err)
 		if err.Error() == "mongo: no documents in result" {
-			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Player not found: %s for season %d", nflID, season)})
+			httputil.RespondError(c, http.StatusNotFound, httputil.CodeNotFound, fmt.Sprintf("Player not found: %s for season %d", nflID, year))
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to fetch player summary: %v", err)})
+			httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, fmt.Sprintf("Failed to fetch player summary: %v", err))
 		}
 		return
 	}
@@ -427,21 +1200,24 @@ func getMapKeys(m map[string]interface{}) []string {
 
 // GetTeamDepthChart - GET /api/data/teams/:team/depth-chart?season=2024
 func (h *DataHandler) GetTeamDepthChart(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	team := c.Param("team")
-	season, _ := strconv.Atoi(c.DefaultQuery("season", "2025"))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
 
-	depthChart, err := h.service.GetTeamDepthChart(ctx, team, season)
+	depthChart, err := h.service.GetTeamDepthChart(ctx, team, year)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch depth chart"})
+		httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch depth chart")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"team":        team,
-		"season":      season,
+		"season":      year,
 		"depth_chart": depthChart,
 	})
 }
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestGetPlayerStatusValidatesWeek checks the week is required and validated before the
+// status is looked up
+func TestGetPlayerStatusValidatesWeek(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &DataHandler{}
+	router := gin.New()
+	router.GET("/players/:nfl_id/status", h.GetPlayerStatus)
+
+	for _, query := range []string{"", "?season=2024", "?week=0", "?week=abc", "?week=-1", "?season=1800&week=6"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/players/00-0033873/status"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET status%s = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestPlaysRejectInvalidPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &DataHandler{}
+	router := gin.New()
+	router.GET("/players/:nfl_id/plays", h.GetPlayerPlays)
+	router.GET("/teams/:team/plays", h.GetTeamPlays)
+
+	for _, path := range []string{"/players/00-0033873/plays", "/teams/KC/plays"} {
+		for _, query := range []string{"?offset=-1", "?offset=abc", "?limit=abc", "?limit=0", "?limit=-5", "?limit=501"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+query, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("GET %s%s = %d, want %d", path, query, w.Code, http.StatusBadRequest)
+			}
+		}
+	}
+}
+
+func TestLeadersRejectInvalidLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &DataHandler{}
+	router := gin.New()
+	router.GET("/ngs/leaders", h.GetNGSLeaders)
+	router.GET("/ngs/receiving/leaders", h.GetReceivingLeaders)
+
+	for _, path := range []string{"/ngs/leaders?stat_type=passing&metric=avg_time_to_throw&", "/ngs/receiving/leaders?"} {
+		for _, limit := range []string{"abc", "0", "-1", "101"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"limit="+limit, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("GET %slimit=%s = %d, want %d", path, limit, w.Code, http.StatusBadRequest)
+			}
+		}
+	}
+}
+
+func TestGetPlayerWeeklyStatsValidatesRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &DataHandler{}
+	router := gin.New()
+	router.GET("/players/:nfl_id/weekly", h.GetPlayerWeeklyStats)
+
+	for _, query := range []string{"?from=8&to=3", "?from=abc", "?to=-1", "?season=1800"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/players/00-0033873/weekly"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET weekly%s = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetPlayerStatsBatchValidates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &DataHandler{}
+	router := gin.New()
+	router.POST("/players/batch", h.GetPlayerStatsBatch)
+
+	tooMany := make([]string, maxBatchPlayers+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf(`"00-%07d"`, i)
+	}
+
+	for _, body := range []string{
+		`{}`,
+		`{"nfl_ids": []}`,
+		`{"nfl_ids": [` + strings.Join(tooMany, ",") + `]}`,
+		`{"nfl_ids": ["00-0033873"], "season": 1800}`,
+		`{"nfl_ids": ["00-0033873"], "season_type": "PRE"}`,
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/players/batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("POST batch %.60s = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
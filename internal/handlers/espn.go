@@ -1,29 +1,42 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/ai-atl/nfl-platform/internal/httputil"
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/season"
 	"github.com/ai-atl/nfl-platform/internal/services"
+	"github.com/ai-atl/nfl-platform/pkg/espn"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type ESPNHandler struct {
 	db              *mongo.Database
-	flaskServiceURL string
+	flask           *services.FlaskESPNClient
+	nativeClient    bool // Serve ESPN data with the Go espn.Client instead of the Flask service
 	advisorService  *services.FantasyAdvisorService
+	recommendations *services.RecommendationService
+	leagues         *services.ESPNLeagues // Reads the user's league directly from ESPN
 }
 
-func NewESPNHandler(db *mongo.Database, flaskServiceURL string) *ESPNHandler {
+func NewESPNHandler(db *mongo.Database, flask *services.FlaskESPNClient, nativeClient bool) *ESPNHandler {
 	return &ESPNHandler{
 		db:              db,
-		flaskServiceURL: flaskServiceURL,
+		flask:           flask,
+		nativeClient:    nativeClient,
 		advisorService:  services.NewFantasyAdvisorService(db),
+		recommendations: services.NewRecommendationService(db),
+		leagues:         services.NewESPNLeagues(db),
 	}
 }
 
@@ -144,54 +157,87 @@ func (h *ESPNHandler) GetStatus(c *gin.Context) {
 	})
 }
 
-// GetRoster fetches the user's ESPN fantasy roster from Flask service
-func (h *ESPNHandler) GetRoster(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
+// espnErrorStatus maps a failed ESPN league read to a status and a message telling the user
+// what to fix, falling back to a 502 with fallback for errors ESPN didn't explain
+func espnErrorStatus(err error, fallback string) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrESPNNotConnected):
+		return http.StatusBadRequest, "ESPN credentials not configured"
+	case errors.Is(err, espn.ErrAuthExpired):
+		return http.StatusUnauthorized, "Your ESPN cookies are missing or expired. Copy fresh espn_s2 and SWID values from your browser and save them again."
+	case errors.Is(err, espn.ErrLeaguePrivate):
+		return http.StatusForbidden, "This ESPN league is private and your ESPN account can't see it. Check that you're a member, or ask the commissioner to make the league viewable."
+	case errors.Is(err, espn.ErrLeagueNotFound):
+		return http.StatusNotFound, "ESPN couldn't find this league. Check the league ID and year you saved."
+	}
+	return http.StatusBadGateway, fallback
+}
+
+func respondESPNError(c *gin.Context, err error, fallback string) {
+	status, msg := espnErrorStatus(err, fallback)
+	c.JSON(status, gin.H{"error": msg})
+}
+
+// requireFlask rejects the request when the native client is selected, for endpoints that
+// are so far only implemented on top of the Flask service
+func (h *ESPNHandler) requireFlask(c *gin.Context) bool {
+	if !h.nativeClient {
+		return true
 	}
+	c.JSON(http.StatusNotImplemented, gin.H{
+		"error": "this endpoint is not available with the native ESPN client yet; set ESPN_NATIVE_CLIENT=false to use the Flask service",
+	})
+	return false
+}
 
-	objectID, err := bson.ObjectIDFromHex(userID)
+// connectedUser loads the authenticated user's saved ESPN credentials, writing the error
+// response and returning false when they aren't available
+func (h *ESPNHandler) connectedUser(c *gin.Context) (*models.User, bool) {
+	objectID, err := bson.ObjectIDFromHex(c.GetString("user_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
-		return
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, false
 	}
 
-	// Get user's ESPN credentials
-	var user models.User
-	err = h.db.Collection("users").FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&user)
+	user, err := services.ConnectedESPNUser(c.Request.Context(), h.db, objectID)
+	if errors.Is(err, services.ErrESPNNotConnected) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ESPN credentials not configured"})
+		return nil, false
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch user"})
-		return
+		return nil, false
 	}
+	return user, true
+}
 
-	if user.ESPNS2 == "" || user.ESPNSWID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ESPN credentials not configured"})
+// respondFlaskError reports a failed Flask ESPN service call, passing the service's own
+// error through
+func respondFlaskError(c *gin.Context, err error, fallback string) {
+	var serviceErr *services.ESPNServiceError
+	if errors.As(err, &serviceErr) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "ESPN service returned error: " + serviceErr.Body})
 		return
 	}
+	log.Printf("ESPN service request failed: %v", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+}
 
-	// Call Flask service to get roster
-	flaskURL := fmt.Sprintf("%s/api/espn/roster", h.flaskServiceURL)
-	resp, err := http.Get(flaskURL)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch roster from ESPN service"})
+// GetRoster fetches the user's ESPN fantasy roster from Flask service
+func (h *ESPNHandler) GetRoster(c *gin.Context) {
+	if !h.requireFlask(c) {
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "ESPN service returned error: " + string(body),
-		})
+	user, ok := h.connectedUser(c)
+	if !ok {
 		return
 	}
 
-	// Parse the roster response
+	// Call Flask service to get roster
 	var players []ESPNPlayer
-	if err := json.NewDecoder(resp.Body).Decode(&players); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse roster data"})
+	if err := h.flask.Get(c.Request.Context(), user, "/api/espn/roster", &players); err != nil {
+		respondFlaskError(c, err, "failed to fetch roster from ESPN service")
 		return
 	}
 
@@ -203,52 +249,19 @@ func (h *ESPNHandler) GetRoster(c *gin.Context) {
 
 // OptimizeLineup gets the optimal lineup based on projected points
 func (h *ESPNHandler) OptimizeLineup(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-
-	objectID, err := bson.ObjectIDFromHex(userID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+	if !h.requireFlask(c) {
 		return
 	}
 
-	// Get user's ESPN credentials
-	var user models.User
-	err = h.db.Collection("users").FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&user)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch user"})
-		return
-	}
-
-	if user.ESPNS2 == "" || user.ESPNSWID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ESPN credentials not configured"})
+	user, ok := h.connectedUser(c)
+	if !ok {
 		return
 	}
 
 	// Call Flask service to get optimized lineup
-	flaskURL := fmt.Sprintf("%s/api/espn/optimize-lineup", h.flaskServiceURL)
-	resp, err := http.Get(flaskURL)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch optimized lineup from ESPN service"})
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "ESPN service returned error: " + string(body),
-		})
-		return
-	}
-
-	// Parse the optimize response
 	var optimized OptimizeLineupResponse
-	if err := json.NewDecoder(resp.Body).Decode(&optimized); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse optimization data"})
+	if err := h.flask.Get(c.Request.Context(), user, "/api/espn/optimize-lineup", &optimized); err != nil {
+		respondFlaskError(c, err, "failed to fetch optimized lineup from ESPN service")
 		return
 	}
 
@@ -275,28 +288,12 @@ type FreeAgentsResponse struct {
 
 // GetFreeAgents fetches available free agents from ESPN
 func (h *ESPNHandler) GetFreeAgents(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-
-	objectID, err := bson.ObjectIDFromHex(userID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+	if !h.requireFlask(c) {
 		return
 	}
 
-	// Get user's ESPN credentials
-	var user models.User
-	err = h.db.Collection("users").FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&user)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch user"})
-		return
-	}
-
-	if user.ESPNS2 == "" || user.ESPNSWID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ESPN credentials not configured"})
+	user, ok := h.connectedUser(c)
+	if !ok {
 		return
 	}
 
@@ -305,44 +302,13 @@ func (h *ESPNHandler) GetFreeAgents(c *gin.Context) {
 	size := c.DefaultQuery("size", "50")
 
 	// Call Flask service to get free agents
-	flaskURL := fmt.Sprintf("%s/api/espn/free-agents?size=%s", h.flaskServiceURL, size)
+	query := url.Values{"size": {size}}
 	if position != "" {
-		flaskURL += "&position=" + position
+		query.Set("position", position)
 	}
-	resp, err := http.Get(flaskURL)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch free agents from ESPN service"})
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "ESPN service returned error: " + string(body),
-		})
-		return
-	}
-
-	// Read and log the response for debugging
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read response body"})
-		return
-	}
-
-	// Log first 500 chars for debugging
-	bodyStr := string(body)
-	if len(bodyStr) > 500 {
-		bodyStr = bodyStr[:500] + "..."
-	}
-	fmt.Printf("Flask response: %s\n", bodyStr)
-
 	var freeAgents FreeAgentsResponse
-	if err := json.Unmarshal(body, &freeAgents); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to parse free agents data: " + err.Error(),
-		})
+	if err := h.flask.Get(c.Request.Context(), user, "/api/espn/free-agents?"+query.Encode(), &freeAgents); err != nil {
+		respondFlaskError(c, err, "failed to fetch free agents from ESPN service")
 		return
 	}
 
@@ -375,6 +341,10 @@ func (h *ESPNHandler) GetAIStartSitAdvice(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
 		return
 	}
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
 
 	// Extract player data
 	playerAInj := ""
@@ -388,7 +358,7 @@ func (h *ESPNHandler) GetAIStartSitAdvice(c *gin.Context) {
 	}
 
 	// Call advisor service with database enrichment
-	comparison, err := h.advisorService.GetStartSitAdvice(
+	comparison, err := h.advisorService.WithScoring(scoring).GetStartSitAdvice(
 		c.Request.Context(),
 		req.PlayerA.Name, req.PlayerA.Position, req.PlayerA.ProTeam,
 		req.PlayerA.ProjectedPoints, req.PlayerA.Points,
@@ -405,6 +375,30 @@ func (h *ESPNHandler) GetAIStartSitAdvice(c *gin.Context) {
 		return
 	}
 
+	// Save the recommendation so the accuracy job can score it once the week is played
+	if objectID, err := bson.ObjectIDFromHex(userID); err == nil {
+		currentSeason, currentWeek := season.Current(c.Request.Context())
+		pick := comparison.PlayerAName
+		if comparison.Recommendation == "B" {
+			pick = comparison.PlayerBName
+		}
+		rec := models.Recommendation{
+			UserID: objectID,
+			Kind:   models.RecommendationStartSit,
+			Season: currentSeason,
+			Week:   currentWeek,
+			Players: []models.RecommendedPlayer{
+				{Name: req.PlayerA.Name, Position: req.PlayerA.Position, Team: req.PlayerA.ProTeam},
+				{Name: req.PlayerB.Name, Position: req.PlayerB.Position, Team: req.PlayerB.ProTeam},
+			},
+			Pick:       pick,
+			Confidence: comparison.Confidence,
+		}
+		if err := h.recommendations.Record(c.Request.Context(), rec); err != nil {
+			log.Printf("Failed to save start/sit recommendation for user %s: %v", userID, err)
+		}
+	}
+
 	// Build response
 	response := AIStartSitResponse{
 		Recommendation: comparison.Recommendation,
@@ -416,3 +410,102 @@ func (h *ESPNHandler) GetAIStartSitAdvice(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// GetMatchup returns the user's matchup for a week, defaulting to the current one, with
+// ESPN's projected final scores and each team's win probability. It reads ESPN directly,
+// so it works with either ESPN backend.
+// GET /api/v1/espn/matchup?week=10
+func (h *ESPNHandler) GetMatchup(c *gin.Context) {
+	objectID, err := bson.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	_, currentWeek := season.Current(c.Request.Context())
+	week, ok := httputil.QueryWeek(c, "week", currentWeek)
+	if !ok {
+		return
+	}
+
+	matchup, err := h.leagues.Matchup(c.Request.Context(), objectID, week)
+	if err != nil {
+		log.Printf("ESPN GetMatchup failed for user %s: %v", objectID.Hex(), err)
+		respondESPNError(c, err, "failed to fetch matchup from ESPN")
+		return
+	}
+
+	c.JSON(http.StatusOK, matchup)
+}
+
+// GetLeagueSettings returns the user's ESPN league settings: lineup_slot_counts keyed by
+// ESPN slot ID and lineup, the same slots as the optimizer's lineup_slots
+// GET /api/v1/espn/league/settings
+func (h *ESPNHandler) GetLeagueSettings(c *gin.Context) {
+	objectID, err := bson.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	settings, err := h.leagues.LeagueSettings(c.Request.Context(), objectID)
+	if err != nil {
+		log.Printf("ESPN GetLeagueSettings failed for user %s: %v", objectID.Hex(), err)
+		respondESPNError(c, err, "failed to fetch league settings from ESPN")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// GetAlerts returns the injury status changes the alerts poller recorded for the user's
+// roster, newest first
+// GET /api/v1/espn/alerts?since=2025-10-01T00:00:00Z&limit=50
+func (h *ESPNHandler) GetAlerts(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	objectID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	filter := bson.M{"user_id": objectID}
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC 3339 timestamp"})
+			return
+		}
+		filter["created_at"] = bson.M{"$gt": t}
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	ctx := c.Request.Context()
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := h.db.Collection("alerts").Find(ctx, filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch alerts"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	alerts := []models.RosterAlert{}
+	if err := cursor.All(ctx, &alerts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decode alerts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": alerts,
+		"count":  len(alerts),
+	})
+}
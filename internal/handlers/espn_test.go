@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/services"
+	"github.com/ai-atl/nfl-platform/pkg/espn"
+	"github.com/gin-gonic/gin"
+)
+
+func TestESPNErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{services.ErrESPNNotConnected, http.StatusBadRequest},
+		{espn.ErrAuthExpired, http.StatusUnauthorized},
+		{espn.ErrLeaguePrivate, http.StatusForbidden},
+		{fmt.Errorf("fetch league: %w", espn.ErrLeagueNotFound), http.StatusNotFound},
+		{errors.New("ESPN API returned status 503"), http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		status, msg := espnErrorStatus(tt.err, "fallback")
+		if status != tt.want {
+			t.Errorf("espnErrorStatus(%v) status = %d, want %d", tt.err, status, tt.want)
+		}
+		if (status == http.StatusBadGateway) != (msg == "fallback") {
+			t.Errorf("espnErrorStatus(%v) message = %q", tt.err, msg)
+		}
+	}
+}
+
+func TestRequireFlask(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		native     bool
+		wantOK     bool
+		wantStatus int
+	}{
+		{"flask service", false, true, http.StatusOK},
+		{"native client", true, false, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &ESPNHandler{nativeClient: tt.native}
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			if ok := h.requireFlask(c); ok != tt.wantOK {
+				t.Errorf("requireFlask() = %v, want %v", ok, tt.wantOK)
+			}
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// TestNativeClientRejectsFlaskOnlyEndpoints checks the Flask-only routes answer 501 before
+// touching the user or the service when the native client is selected
+func TestNativeClientRejectsFlaskOnlyEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &ESPNHandler{nativeClient: true}
+	router := gin.New()
+	router.GET("/roster", h.GetRoster)
+	router.GET("/optimize-lineup", h.OptimizeLineup)
+	router.GET("/free-agents", h.GetFreeAgents)
+
+	for _, path := range []string{"/roster", "/optimize-lineup", "/free-agents"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusNotImplemented {
+			t.Errorf("GET %s status = %d, want %d", path, w.Code, http.StatusNotImplemented)
+		}
+	}
+}
@@ -10,11 +10,13 @@ import (
 	"time"
 
 	"github.com/ai-atl/nfl-platform/internal/config"
+	"github.com/ai-atl/nfl-platform/internal/models"
 	"github.com/ai-atl/nfl-platform/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"golang.org/x/oauth2"
 )
 
 type yahooStateClaims struct {
@@ -143,54 +145,88 @@ func (h *FantasyHandler) Status(c *gin.Context) {
 }
 
 func (h *FantasyHandler) Teams(c *gin.Context) {
+	user, token, ok := h.yahooToken(c)
+	if !ok {
+		return
+	}
+
+	teams, err := h.yahoo.FetchTeams(c.Request.Context(), user, token)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"connected": true,
+		"teams":     teams,
+	})
+}
+
+// Roster returns the players on one of the user's Yahoo teams
+// GET /api/v1/fantasy/teams/:team_key/roster
+func (h *FantasyHandler) Roster(c *gin.Context) {
+	teamKey := c.Param("team_key")
+
+	user, token, ok := h.yahooToken(c)
+	if !ok {
+		return
+	}
+
+	players, err := h.yahoo.FetchRoster(c.Request.Context(), user, token, teamKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team_key": teamKey,
+		"count":    len(players),
+		"players":  players,
+	})
+}
+
+// yahooToken loads the authenticated user and their Yahoo token, refreshing it if expired.
+// It writes the error response and returns false if the user can't call Yahoo.
+func (h *FantasyHandler) yahooToken(c *gin.Context) (*models.User, *oauth2.Token, bool) {
 	if !h.yahoo.Enabled() {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "fantasy integration is not configured"})
-		return
+		return nil, nil, false
 	}
 
 	userID := c.GetString("user_id")
 	if userID == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found in context"})
-		return
+		return nil, nil, false
 	}
 
 	user, err := h.yahoo.LoadUser(c.Request.Context(), userID)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
+			return nil, nil, false
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load user"})
-		return
+		return nil, nil, false
 	}
 
 	if user.YahooAccessToken == "" || user.YahooRefreshToken == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "yahoo account not connected"})
-		return
+		return nil, nil, false
 	}
 
 	token, err := h.yahoo.TokenFromUser(user)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+		return nil, nil, false
 	}
 
 	refreshedToken, err := h.yahoo.RefreshIfNeeded(c.Request.Context(), user, token)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	teams, err := h.yahoo.FetchTeams(c.Request.Context(), refreshedToken)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		return nil, nil, false
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"connected": true,
-		"teams":     teams,
-	})
+	return user, refreshedToken, true
 }
 
 func (h *FantasyHandler) buildState(userID string) (string, error) {
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/pkg/gemini"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type HealthHandler struct {
+	// checks maps each dependency to a probe that returns an error when it's unreachable
+	checks map[string]func(context.Context) error
+}
+
+func NewHealthHandler(client *mongo.Client) *HealthHandler {
+	return &HealthHandler{
+		checks: map[string]func(context.Context) error{
+			"mongodb": func(ctx context.Context) error { return client.Ping(ctx, nil) },
+			"gemini":  gemini.NewClient().Ping,
+		},
+	}
+}
+
+// Ready reports whether the API's dependencies are reachable, for readiness probes.
+// /health stays a static liveness probe.
+// GET /health/ready
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	dependencies := make(gin.H, len(h.checks))
+	ready := true
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func(name string, check func(context.Context) error) {
+			defer wg.Done()
+			err := check(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// The endpoint is unauthenticated, so the cause is only logged
+				log.Printf("Readiness check %s failed: %v", name, err)
+				ready = false
+				dependencies[name] = gin.H{"status": "down"}
+				return
+			}
+			dependencies[name] = gin.H{"status": "ok"}
+		}(name, check)
+	}
+	wg.Wait()
+
+	status, code := "ok", http.StatusOK
+	if !ready {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{
+		"status":       status,
+		"dependencies": dependencies,
+		"time":         time.Now().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Nothing listens on port 1, so pinging this client fails once server selection times out
+	down, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1").SetServerSelectionTimeout(100 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { down.Disconnect(context.Background()) })
+
+	ok := func(context.Context) error { return nil }
+	tests := []struct {
+		name       string
+		mongoCheck func(context.Context) error
+		wantCode   int
+		wantStatus string
+		wantMongo  string
+	}{
+		{"all dependencies up", ok, http.StatusOK, "ok", "ok"},
+		{"mongo ping fails", func(ctx context.Context) error { return down.Ping(ctx, nil) }, http.StatusServiceUnavailable, "unavailable", "down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &HealthHandler{checks: map[string]func(context.Context) error{
+				"mongodb": tt.mongoCheck,
+				"gemini":  ok,
+			}}
+			router := gin.New()
+			router.GET("/health/ready", h.Ready)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+
+			var body struct {
+				Status       string                       `json:"status"`
+				Dependencies map[string]map[string]string `json:"dependencies"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Status != tt.wantStatus {
+				t.Errorf("body status = %q, want %q", body.Status, tt.wantStatus)
+			}
+			if got := body.Dependencies["mongodb"]["status"]; got != tt.wantMongo {
+				t.Errorf("mongodb status = %q, want %q", got, tt.wantMongo)
+			}
+			if got := body.Dependencies["gemini"]["status"]; got != "ok" {
+				t.Errorf("gemini status = %q, want ok", got)
+			}
+			// The endpoint is public, so failure causes stay in the logs
+			if len(body.Dependencies["mongodb"]) != 1 {
+				t.Errorf("mongodb entry = %v, want only a status", body.Dependencies["mongodb"])
+			}
+		})
+	}
+}
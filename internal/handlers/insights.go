@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/ai-atl/nfl-platform/internal/season"
 	"github.com/ai-atl/nfl-platform/internal/services"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -12,13 +18,25 @@ type InsightHandler struct {
 	db                *mongo.Database
 	gameScriptService *services.GameScriptService
 	waiverWireService *services.WaiverWireService
+	streaksService    *services.StreaksService
+	insightService    *services.InsightService
+	injuryService     *services.InjuryImpactService
+	recommendations   *services.RecommendationService
+	espnRosters       services.ESPNRosterSource // nil when connected ESPN rosters aren't available
+	espnLeagues       *services.ESPNLeagues
 }
 
-func NewInsightHandler(db *mongo.Database) *InsightHandler {
+func NewInsightHandler(db *mongo.Database, espnRosters services.ESPNRosterSource) *InsightHandler {
 	return &InsightHandler{
 		db:                db,
 		gameScriptService: services.NewGameScriptService(db),
 		waiverWireService: services.NewWaiverWireService(db),
+		streaksService:    services.NewStreaksService(db),
+		insightService:    services.NewInsightService(db),
+		injuryService:     services.NewInjuryImpactService(db),
+		recommendations:   services.NewRecommendationService(db),
+		espnRosters:       espnRosters,
+		espnLeagues:       services.NewESPNLeagues(db),
 	}
 }
 
@@ -50,70 +68,274 @@ func (h *InsightHandler) InjuryImpact(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement injury impact analysis
-	c.JSON(http.StatusOK, gin.H{
-		"injured_player": req.PlayerID,
-		"analysis":       "Player X will see 30% more targets",
-		"beneficiaries": []map[string]interface{}{
-			{"player": "John Doe", "increase": "30%"},
-		},
-	})
+	impact, err := h.injuryService.Analyze(c.Request.Context(), req.PlayerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, impact)
 }
 
-// Streaks detects hot/cold streaks for a player
+// Streaks finds players on hot/cold scoring streaks
+// GET /api/v1/insights/streaks?position=WR&season=2025&min_games=3&threshold=0.2
 func (h *InsightHandler) Streaks(c *gin.Context) {
-	playerID := c.Query("player_id")
-	if playerID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "player_id is required"})
+	position := strings.ToUpper(c.Query("position"))
+	season := seasonQuery(c)
+	minGames, _ := strconv.Atoi(c.DefaultQuery("min_games", "3"))
+	threshold, _ := strconv.ParseFloat(c.DefaultQuery("threshold", "0.2"), 64)
+	direction := c.Query("direction") // optional: "hot" or "cold"
+
+	if position == "ALL" {
+		position = ""
+	}
+
+	streaks, err := h.streaksService.FindStreaks(c.Request.Context(), position, season, services.StreakOptions{
+		MinGames:  minGames,
+		Threshold: threshold,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// TODO: Implement streak detection
+	if direction != "" {
+		filtered := streaks[:0]
+		for _, s := range streaks {
+			if s.Direction == direction {
+				filtered = append(filtered, s)
+			}
+		}
+		streaks = filtered
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"player_id": playerID,
-		"streaks": []map[string]interface{}{
-			{
-				"type":        "over",
-				"stat":        "receiving_yards",
-				"line":        75.5,
-				"games":       4,
-				"ai_analysis": "Player has favorable matchups and increased target share",
-			},
-		},
+		"position": position,
+		"season":   season,
+		"count":    len(streaks),
+		"streaks":  streaks,
 	})
 }
 
-// TopPerformers returns top over/under performers of the week
+// seasonQuery reads the season query param, defaulting to the current season
+func seasonQuery(c *gin.Context) int {
+	s, _ := strconv.Atoi(c.DefaultQuery("season", strconv.Itoa(season.Season(c.Request.Context()))))
+	return s
+}
+
+// scoringParam reads the scoring query param (standard, half_ppr or ppr), defaulting to
+// ppr. It responds 400 and returns false for anything else.
+func scoringParam(c *gin.Context) (services.ScoringConfig, bool) {
+	scoring, ok := services.ScoringPreset(c.DefaultQuery("scoring", "ppr"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scoring must be standard, half_ppr or ppr"})
+		return services.ScoringConfig{}, false
+	}
+	return scoring, true
+}
+
+// faabBudgetParam reads the optional faab_budget query param, the league's remaining FAAB
+// dollars. It's 0 when omitted and responds 400 for a negative or non-numeric value.
+func faabBudgetParam(c *gin.Context) (int, bool) {
+	budget, err := strconv.Atoi(c.DefaultQuery("faab_budget", "0"))
+	if err != nil || budget < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "faab_budget must be a non-negative whole number"})
+		return 0, false
+	}
+	return budget, true
+}
+
+// TopPerformers returns the top fantasy scorers for a week (or season-to-date if week is omitted)
+// GET /api/v1/insights/top_performers?position=RB&season=2025&week=9&limit=10&scoring=ppr
 func (h *InsightHandler) TopPerformers(c *gin.Context) {
-	week := c.DefaultQuery("week", "9")
-	performerType := c.DefaultQuery("type", "over")
+	position := strings.ToUpper(c.Query("position"))
+	season := seasonQuery(c)
+	week, _ := strconv.Atoi(c.DefaultQuery("week", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+	if position == "ALL" {
+		position = ""
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	performers, err := h.insightService.TopPerformers(c.Request.Context(), position, season, week, limit, scoring)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	// TODO: Calculate from actual data
 	c.JSON(http.StatusOK, gin.H{
-		"week": week,
-		"type": performerType,
-		"performers": []map[string]interface{}{
-			{
-				"player":     "Patrick Mahomes",
-				"projected":  24.5,
-				"actual":     38.2,
-				"difference": 13.7,
-				"epa":        15.3,
-			},
-		},
+		"position":   position,
+		"season":     season,
+		"week":       week,
+		"scoring":    scoring.Name,
+		"count":      len(performers),
+		"performers": performers,
 	})
 }
 
-// WaiverGems finds undervalued players with breakout potential
+// VORP ranks a position by season fantasy points above the replacement-level player
+// (default QB12, RB24, WR36, TE12; QB24 with superflex=true; override with replacement=N)
+// GET /api/v1/insights/vorp?position=RB&season=2025&replacement=24&superflex=false&scoring=ppr
+func (h *InsightHandler) VORP(c *gin.Context) {
+	position := strings.ToUpper(c.Query("position"))
+	if _, ok := services.DefaultReplacementLevels[position]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "position must be QB, RB, WR or TE"})
+		return
+	}
+	season := seasonQuery(c)
+	replacement, _ := strconv.Atoi(c.DefaultQuery("replacement", "0"))
+	if replacement <= 0 {
+		superflex, _ := strconv.ParseBool(c.DefaultQuery("superflex", "false"))
+		replacement = services.ReplacementLevel(position, superflex)
+	}
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+
+	vorp, err := h.insightService.VORP(c.Request.Context(), position, season, replacement, scoring)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, vorp)
+}
+
+// Similar finds the players at the same position with the closest usage and efficiency profile
+// GET /api/v1/insights/similar?nfl_id=00-0036900&season=2025&limit=5
+func (h *InsightHandler) Similar(c *gin.Context) {
+	nflID := c.Query("nfl_id")
+	if nflID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nfl_id is required"})
+		return
+	}
+	season := seasonQuery(c)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if limit < 1 || limit > 25 {
+		limit = 5
+	}
+
+	similar, err := h.insightService.SimilarPlayers(c.Request.Context(), nflID, season, limit)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, similar)
+}
+
+// StreamingDefenses ranks defenses by fantasy points allowed to a position, best matchups first
+// GET /api/v1/insights/streaming_defenses?position=QB&season=2025&week=11&scoring=ppr
+func (h *InsightHandler) StreamingDefenses(c *gin.Context) {
+	position := strings.ToUpper(c.DefaultQuery("position", "QB"))
+	season := seasonQuery(c)
+	week, _ := strconv.Atoi(c.DefaultQuery("week", "0"))
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+
+	switch position {
+	case "QB", "RB", "WR", "TE":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "position must be QB, RB, WR or TE"})
+		return
+	}
+	defenses, err := h.insightService.StreamingDefenses(c.Request.Context(), position, season, week, scoring)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"position": position,
+		"season":   season,
+		"week":     week,
+		"scoring":  scoring.Name,
+		"count":    len(defenses),
+		"defenses": defenses,
+	})
+}
+
+// LineupHelpRequest is a roster to get start/sit help for
+type LineupHelpRequest struct {
+	PlayerIDs []string `json:"player_ids" binding:"required"`
+	Season    int      `json:"season"`
+	Week      int      `json:"week" binding:"required"`
+	LeagueSettingsRequest
+}
+
+// LineupHelp sorts a roster into start, questionable and sit for a week, accounting for
+// byes, injury reports, projections and matchups
+// POST /api/v1/insights/lineup_help?scoring=ppr
+func (h *InsightHandler) LineupHelp(c *gin.Context) {
+	var req LineupHelpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Season == 0 {
+		req.Season = season.Season(c.Request.Context())
+	}
+	if req.Week < 1 || req.Week > 22 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "week must be between 1 and 22"})
+		return
+	}
+
+	settings, ok := resolveLeagueSettings(c, h.espnLeagues, req.LeagueSettingsRequest)
+	if !ok {
+		return
+	}
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+
+	help, err := h.insightService.LineupHelp(c.Request.Context(), req.PlayerIDs, req.Season, req.Week, settings, scoring)
+	if errors.Is(err, services.ErrInvalidLineup) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, help)
+}
+
+// WaiverGems finds undervalued players with breakout potential. With faab_budget (the
+// remaining FAAB dollars) each gem also gets a suggested bid.
+// GET /api/v1/insights/waiver_gems?position=WR&scoring=ppr&faab_budget=100
 func (h *InsightHandler) WaiverGems(c *gin.Context) {
 	position := c.DefaultQuery("position", "ALL")
 	limit := 10 // Top 10 candidates
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+	budget, ok := faabBudgetParam(c)
+	if !ok {
+		return
+	}
 
-	gems, err := h.waiverWireService.FindWaiverGems(c.Request.Context(), position, limit)
+	gems, err := h.waiverWireService.WithScoring(scoring).WithFAABBudget(budget).FindWaiverGems(c.Request.Context(), position, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.recordWaiverGems(c, gems)
 
 	c.JSON(http.StatusOK, gin.H{
 		"gems":  gems,
@@ -121,7 +343,36 @@ func (h *InsightHandler) WaiverGems(c *gin.Context) {
 	})
 }
 
-// PersonalizedWaiverGems provides waiver recommendations based on user's ESPN roster
+// RosterAnalysis rates a roster by position against the league and finds bye-week gaps
+// POST /api/v1/insights/roster_analysis
+// Body: {"roster": [{"name", "position", "proTeam", "lineupSlot", "projectedPoints", "injuryStatus"}, ...]}
+func (h *InsightHandler) RosterAnalysis(c *gin.Context) {
+	var req struct {
+		Roster []services.RosterPlayer `json:"roster" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	currentSeason, currentWeek := season.Current(ctx)
+	analysis, err := h.waiverWireService.WithScoring(scoring).AnalyzeRoster(ctx, req.Roster, currentSeason, currentWeek)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// PersonalizedWaiverGems provides waiver recommendations based on user's ESPN roster, with
+// FAAB bids when faab_budget is given as for WaiverGems
 func (h *InsightHandler) PersonalizedWaiverGems(c *gin.Context) {
 	var req struct {
 		Roster   []services.RosterPlayer `json:"roster" binding:"required"`
@@ -138,15 +389,121 @@ func (h *InsightHandler) PersonalizedWaiverGems(c *gin.Context) {
 		req.Position = "ALL"
 	}
 
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+	budget, ok := faabBudgetParam(c)
+	if !ok {
+		return
+	}
+
 	limit := 10
-	gems, err := h.waiverWireService.FindPersonalizedWaiverGems(c.Request.Context(), req.Roster, req.Position, limit)
+	gems, err := h.waiverWireService.WithScoring(scoring).WithFAABBudget(budget).FindPersonalizedWaiverGems(c.Request.Context(), req.Roster, req.Position, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.recordWaiverGems(c, gems)
 
 	c.JSON(http.StatusOK, gin.H{
 		"gems":  gems,
 		"count": len(gems),
 	})
 }
+
+// ConnectedWaiverGems personalizes waiver gems with the roster of the user's connected ESPN
+// league, so the client doesn't have to send it. Without a connected league (or if the
+// roster can't be fetched) it returns the generic gems; personalized says which it did.
+// POST /api/v1/insights/waiver_gems?position=ALL&scoring=ppr&faab_budget=100 (no body)
+func (h *InsightHandler) ConnectedWaiverGems(c *gin.Context) {
+	position := c.DefaultQuery("position", "ALL")
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+	budget, ok := faabBudgetParam(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	roster := connectedRoster(c, h.espnRosters)
+	service := h.waiverWireService.WithScoring(scoring).WithFAABBudget(budget)
+	limit := 10
+
+	var gems []services.WaiverGem
+	var err error
+	if len(roster) > 0 {
+		gems, err = service.FindPersonalizedWaiverGems(ctx, roster, position, limit)
+	} else {
+		gems, err = service.FindWaiverGems(ctx, position, limit)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.recordWaiverGems(c, gems)
+
+	c.JSON(http.StatusOK, gin.H{
+		"gems":         gems,
+		"count":        len(gems),
+		"personalized": len(roster) > 0,
+	})
+}
+
+// connectedRoster fetches the user's ESPN roster from source, or nil when source is nil,
+// the user has no connected league or the roster can't be fetched
+func connectedRoster(c *gin.Context, source services.ESPNRosterSource) []services.RosterPlayer {
+	if source == nil {
+		return nil
+	}
+	userID, err := bson.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		return nil
+	}
+
+	roster, err := source.FetchRoster(c.Request.Context(), userID)
+	if err != nil {
+		if !errors.Is(err, services.ErrESPNNotConnected) {
+			log.Printf("Failed to fetch connected ESPN roster for user %s: %v", userID.Hex(), err)
+		}
+		return nil
+	}
+	return roster
+}
+
+// recordWaiverGems saves the gems returned to the user as waiver recommendations for the
+// current week. A failure is logged rather than failing the request.
+func (h *InsightHandler) recordWaiverGems(c *gin.Context, gems []services.WaiverGem) {
+	userID, err := bson.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	currentSeason, currentWeek := season.Current(ctx)
+	if err := h.recommendations.RecordWaiverGems(ctx, userID, currentSeason, currentWeek, gems); err != nil {
+		log.Printf("Failed to save waiver recommendations for user %s: %v", userID.Hex(), err)
+	}
+}
+
+// Accuracy reports how often start/sit and waiver recommendations paid off, scored against
+// actual PPR points once each week's games are final
+// GET /api/v1/insights/accuracy?season=2025
+func (h *InsightHandler) Accuracy(c *gin.Context) {
+	currentSeason, _ := season.Current(c.Request.Context())
+	seasonYear, err := strconv.Atoi(c.DefaultQuery("season", strconv.Itoa(currentSeason)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "season must be a year"})
+		return
+	}
+
+	accuracy, err := h.recommendations.Accuracy(c.Request.Context(), seasonYear)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, accuracy)
+}
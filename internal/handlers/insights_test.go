@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// stubRosterSource returns roster and err for every user
+type stubRosterSource struct {
+	roster []services.RosterPlayer
+	err    error
+}
+
+func (s stubRosterSource) FetchRoster(ctx context.Context, userID bson.ObjectID) ([]services.RosterPlayer, error) {
+	return s.roster, s.err
+}
+
+func TestConnectedRoster(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	roster := []services.RosterPlayer{{Name: "Josh Allen", Position: "QB", ProjectedPoints: 24, LineupSlot: "QB"}}
+
+	tests := []struct {
+		name   string
+		userID string
+		source services.ESPNRosterSource
+		want   int
+	}{
+		{"connected league", bson.NewObjectID().Hex(), stubRosterSource{roster: roster}, 1},
+		{"no ESPN roster source", bson.NewObjectID().Hex(), nil, 0},
+		{"league not connected", bson.NewObjectID().Hex(), stubRosterSource{err: services.ErrESPNNotConnected}, 0},
+		{"ESPN service down", bson.NewObjectID().Hex(), stubRosterSource{err: errors.New("connection refused")}, 0},
+		{"no authenticated user", "", stubRosterSource{roster: roster}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/api/v1/insights/waiver_gems", nil)
+			c.Set("user_id", tt.userID)
+
+			if got := connectedRoster(c, tt.source); len(got) != tt.want {
+				t.Errorf("connectedRoster() = %+v, want %d players", got, tt.want)
+			}
+		})
+	}
+}
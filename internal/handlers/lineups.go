@@ -2,71 +2,104 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/ai-atl/nfl-platform/internal/services"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type LineupHandler struct {
-	db *mongo.Database
+	db            *mongo.Database
+	lineupService *services.LineupService
+	espnRosters   services.ESPNRosterSource // nil when connected ESPN rosters aren't available
+	espnLeagues   *services.ESPNLeagues
 }
 
-func NewLineupHandler(db *mongo.Database) *LineupHandler {
-	return &LineupHandler{db: db}
+func NewLineupHandler(db *mongo.Database, espnRosters services.ESPNRosterSource) *LineupHandler {
+	return &LineupHandler{
+		db:            db,
+		lineupService: services.NewLineupService(db),
+		espnRosters:   espnRosters,
+		espnLeagues:   services.NewESPNLeagues(db),
+	}
+}
+
+// LineupRequest is the body for creating or updating a lineup. The league settings pick
+// the slots the lineup is validated against; a new lineup without them is for a standard
+// league, and an update without them keeps the lineup's league.
+type LineupRequest struct {
+	Name   string              `json:"name" binding:"required"`
+	Week   int                 `json:"week" binding:"required"`
+	Season int                 `json:"season"`
+	Slots  []models.LineupSlot `json:"slots" binding:"required"`
+	LeagueSettingsRequest
+}
+
+// lineup builds the lineup, defaulting an omitted season to the current one
+func (r LineupRequest) lineup(ctx context.Context) *models.FantasyLineup {
+	lineupSeason := r.Season
+	if lineupSeason == 0 {
+		lineupSeason = season.Season(ctx)
+	}
+	return &models.FantasyLineup{
+		Name:   r.Name,
+		Week:   r.Week,
+		Season: lineupSeason,
+		Slots:  r.Slots,
+	}
 }
 
 // List returns all lineups for the authenticated user
 func (h *LineupHandler) List(c *gin.Context) {
-	userID, _ := c.Get("user_id")
-	objID, _ := bson.ObjectIDFromHex(userID.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
 
-	collection := h.db.Collection("lineups")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	cursor, err := collection.Find(ctx, bson.M{"user_id": objID})
+	lineups, err := h.lineupService.List(ctx, userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch lineups"})
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var lineups []models.FantasyLineup
-	if err := cursor.All(ctx, &lineups); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode lineups"})
-		return
-	}
 
 	c.JSON(http.StatusOK, gin.H{"lineups": lineups})
 }
 
-// Create creates a new lineup
+// Create validates and stores a new lineup
 func (h *LineupHandler) Create(c *gin.Context) {
-	userID, _ := c.Get("user_id")
-	objID, _ := bson.ObjectIDFromHex(userID.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
 
-	var lineup models.FantasyLineup
-	if err := c.ShouldBindJSON(&lineup); err != nil {
+	var req LineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	lineup.ID = bson.NewObjectID()
-	lineup.UserID = objID
-	lineup.CreatedAt = time.Now()
-	lineup.UpdatedAt = time.Now()
-
-	collection := h.db.Collection("lineups")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	_, err := collection.InsertOne(ctx, lineup)
+	lineup := req.lineup(ctx)
+	if !h.applyLeagueSlots(c, lineup, req.LeagueSettingsRequest) {
+		return
+	}
+
+	lineup, err := h.lineupService.Create(ctx, userID, lineup)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create lineup"})
+		respondLineupError(c, err, "Failed to create lineup")
 		return
 	}
 
@@ -75,89 +108,273 @@ func (h *LineupHandler) Create(c *gin.Context) {
 
 // Get returns a specific lineup
 func (h *LineupHandler) Get(c *gin.Context) {
-	id := c.Param("id")
-	objID, err := bson.ObjectIDFromHex(id)
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	lineupID, err := bson.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lineup ID"})
 		return
 	}
 
-	collection := h.db.Collection("lineups")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	var lineup models.FantasyLineup
-	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&lineup)
+	lineup, err := h.lineupService.Get(ctx, userID, lineupID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Lineup not found"})
+		respondLineupError(c, err, "Failed to fetch lineup")
 		return
 	}
 
 	c.JSON(http.StatusOK, lineup)
 }
 
-// Update updates an existing lineup
+// Update replaces an existing lineup owned by the user
 func (h *LineupHandler) Update(c *gin.Context) {
-	id := c.Param("id")
-	objID, err := bson.ObjectIDFromHex(id)
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	lineupID, err := bson.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lineup ID"})
 		return
 	}
 
-	var updates bson.M
-	if err := c.ShouldBindJSON(&updates); err != nil {
+	var req LineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	updates["updated_at"] = time.Now()
-
-	collection := h.db.Collection("lineups")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	result, err := collection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": updates})
-	if err != nil || result.MatchedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Lineup not found"})
+	changes := req.lineup(ctx)
+	if !h.applyLeagueSlots(c, changes, req.LeagueSettingsRequest) {
+		return
+	}
+
+	lineup, err := h.lineupService.Update(ctx, userID, lineupID, changes)
+	if err != nil {
+		respondLineupError(c, err, "Failed to update lineup")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Lineup updated"})
+	c.JSON(http.StatusOK, lineup)
 }
 
-// Delete removes a lineup
+// Delete removes a lineup owned by the user
 func (h *LineupHandler) Delete(c *gin.Context) {
-	id := c.Param("id")
-	objID, err := bson.ObjectIDFromHex(id)
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	lineupID, err := bson.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lineup ID"})
 		return
 	}
 
-	collection := h.db.Collection("lineups")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	result, err := collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil || result.DeletedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Lineup not found"})
+	if err := h.lineupService.Delete(ctx, userID, lineupID); err != nil {
+		respondLineupError(c, err, "Failed to delete lineup")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Lineup deleted"})
 }
 
-// Optimize generates an optimized lineup using AI (placeholder)
-func (h *LineupHandler) Optimize(c *gin.Context) {
-	// TODO: Implement AI optimization
-	c.JSON(http.StatusOK, gin.H{
-		"message": "AI optimization feature coming soon",
-		"suggested_lineup": gin.H{
-			"QB":  "player_123",
-			"RB1": "player_456",
-			"RB2": "player_789",
-		},
-	})
+// applyLeagueSlots sets the lineup's league slots when the request specifies a league. It
+// writes the error response and returns false when the settings can't be resolved.
+func (h *LineupHandler) applyLeagueSlots(c *gin.Context, lineup *models.FantasyLineup, req LeagueSettingsRequest) bool {
+	if !req.specified() {
+		return true
+	}
+	settings, ok := resolveLeagueSettings(c, h.espnLeagues, req)
+	if !ok {
+		return false
+	}
+	lineup.LineupSlots = settings.LineupSlots
+	return true
+}
+
+// currentUserID reads the authenticated user's ID, responding 401 if it is missing or malformed
+func currentUserID(c *gin.Context) (bson.ObjectID, bool) {
+	userID, _ := c.Get("user_id")
+	hex, _ := userID.(string)
+	objID, err := bson.ObjectIDFromHex(hex)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return bson.ObjectID{}, false
+	}
+	return objID, true
+}
+
+// respondLineupError maps lineup service errors to HTTP status codes
+func respondLineupError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidLineup):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrLineupNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lineup not found"})
+	case errors.Is(err, services.ErrLineupForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not own this lineup"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// OptimizeRequest identifies the roster to optimize: a stored lineup, a list of
+// player IDs, or both (player_ids are then treated as the lineup's bench)
+type OptimizeRequest struct {
+	LineupID  string   `json:"lineup_id"`
+	PlayerIDs []string `json:"player_ids"`
+	Season    int      `json:"season"`
+	Week      int      `json:"week"`
+	LeagueSettingsRequest
+}
+
+// LeagueSettingsRequest describes the league's starting slots: espn_settings reads them from
+// the user's connected ESPN league, lineup_slots gives the full composition, superflex adds a
+// QB-eligible flex to the standard league
+type LeagueSettingsRequest struct {
+	ESPNSettings bool           `json:"espn_settings"`
+	LineupSlots  map[string]int `json:"lineup_slots"`
+	Superflex    bool           `json:"superflex"`
+}
+
+// specified reports whether the request names a league rather than relying on the default
+func (r LeagueSettingsRequest) specified() bool {
+	return r.ESPNSettings || len(r.LineupSlots) > 0 || r.Superflex
+}
+
+// Settings resolves the request into league settings, defaulting to a standard league
+func (r LeagueSettingsRequest) Settings() (models.LeagueSettings, error) {
+	if len(r.LineupSlots) > 0 {
+		for slot, count := range r.LineupSlots {
+			if _, ok := models.SlotEligibility[slot]; !ok {
+				return models.LeagueSettings{}, fmt.Errorf("unknown lineup slot %q", slot)
+			}
+			if count < 0 {
+				return models.LeagueSettings{}, fmt.Errorf("lineup slot %s has a negative count", slot)
+			}
+		}
+		return models.LeagueSettings{LineupSlots: r.LineupSlots}, nil
+	}
+	if r.Superflex {
+		return models.SuperflexLeagueSettings(), nil
+	}
+	return models.DefaultLeagueSettings(), nil
 }
 
+// resolveLeagueSettings resolves the request's league settings, fetching the lineup slots of
+// the user's ESPN league when espn_settings is set. It writes the error response and returns
+// false when they can't be resolved.
+func resolveLeagueSettings(c *gin.Context, leagues *services.ESPNLeagues, req LeagueSettingsRequest) (models.LeagueSettings, bool) {
+	if !req.ESPNSettings {
+		settings, err := req.Settings()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return models.LeagueSettings{}, false
+		}
+		return settings, true
+	}
+
+	userID, err := bson.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return models.LeagueSettings{}, false
+	}
+	espnSettings, err := leagues.LeagueSettings(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to fetch ESPN league settings for user %s: %v", userID.Hex(), err)
+		respondESPNError(c, err, "failed to fetch league settings from ESPN")
+		return models.LeagueSettings{}, false
+	}
+	return espnSettings.Lineup, true
+}
+
+// Optimize returns the highest-projected starting lineup for a roster and how many
+// points the current lineup leaves on the bench. Users with a connected ESPN league are
+// projected with ESPN's numbers where ESPN has them.
+// POST /api/v1/lineups/optimize?scoring=ppr
+func (h *LineupHandler) Optimize(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req OptimizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.LineupID == "" && len(req.PlayerIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lineup_id or player_ids is required"})
+		return
+	}
+	settings, ok := resolveLeagueSettings(c, h.espnLeagues, req.LeagueSettingsRequest)
+	if !ok {
+		return
+	}
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+
+	lineupService := h.lineupService.WithScoring(scoring)
+	if roster := connectedRoster(c, h.espnRosters); len(roster) > 0 {
+		projected := make(map[string]float64, len(roster))
+		for _, p := range roster {
+			projected[p.Name] = p.ProjectedPoints
+		}
+		lineupService = lineupService.WithESPNProjections(projected)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	currentSeason := season.Season(ctx)
+	season, week := req.Season, req.Week
+	var current []models.LineupSlot
+	roster := append([]string{}, req.PlayerIDs...)
+	if req.LineupID != "" {
+		lineupID, err := bson.ObjectIDFromHex(req.LineupID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lineup ID"})
+			return
+		}
+		lineup, err := h.lineupService.Get(ctx, userID, lineupID)
+		if err != nil {
+			respondLineupError(c, err, "Failed to fetch lineup")
+			return
+		}
+		current = lineup.Slots
+		for _, slot := range lineup.Slots {
+			if slot.Slot != models.SlotDST {
+				roster = append(roster, slot.PlayerID)
+			}
+		}
+		if season == 0 {
+			season = lineup.Season
+		}
+		if week == 0 {
+			week = lineup.Week
+		}
+	}
+	if season == 0 {
+		season = currentSeason
+	}
+
+	result, err := lineupService.Optimize(ctx, roster, current, season, week, settings)
+	if err != nil {
+		respondLineupError(c, err, "Failed to optimize lineup")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -6,9 +6,13 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ai-atl/nfl-platform/internal/httputil"
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/ai-atl/nfl-platform/internal/services"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -43,15 +47,31 @@ type PlayerWithStats struct {
 	ForcedFumbles    int     `json:"forced_fumbles,omitempty"`
 	FumbleRecoveries int     `json:"fumble_recoveries,omitempty"`
 
+	// Fantasy Points
+	FantasyPoints    float64 `json:"fantasy_points,omitempty"`
+	FantasyPointsPPR float64 `json:"fantasy_points_ppr,omitempty"`
+	Points           float64 `json:"points"` // Headline total in the requested scoring format
+
 	AvgEPA            float64 `json:"avg_epa"`
 	IsCurrentPlayer   bool    `json:"is_current_player"`
 	StatusDescription string  `json:"status_description"` // Human-readable status
 }
 
+// seasonTypeParam reads the season_type query param (REG, POST or REGPOST), defaulting
+// to REG. It responds 400 and returns false for anything else.
+func seasonTypeParam(c *gin.Context) (string, bool) {
+	seasonType := strings.ToUpper(c.DefaultQuery("season_type", models.SeasonTypeRegular))
+	if !models.IsValidSeasonType(seasonType) {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "season_type must be REG, POST or REGPOST")
+		return "", false
+	}
+	return seasonType, true
+}
+
 // List returns a list of unique players (one entry per player, showing most recent season)
 func (h *PlayerHandler) List(c *gin.Context) {
 	collection := h.db.Collection("players")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	// Build match filter
@@ -75,6 +95,15 @@ func (h *PlayerHandler) List(c *gin.Context) {
 		}
 	}
 
+	seasonType, ok := seasonTypeParam(c)
+	if !ok {
+		return
+	}
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+
 	// Pagination
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
@@ -82,32 +111,42 @@ func (h *PlayerHandler) List(c *gin.Context) {
 
 	// Sorting - use 'name' as default since it's indexed
 	sortField := c.DefaultQuery("sort", "name")
-	sortOrder := 1
-	if c.Query("order") == "desc" {
-		sortOrder = -1
+	if !services.ValidPlayerSort(sortField) {
+		httputil.RespondError(c, http.StatusBadRequest, httputil.CodeInvalidParam, "sort must be name, season, team, position or a stat such as passing_yards")
+		return
 	}
-
-	// Aggregation pipeline to get unique players with their most recent season
-	pipeline := mongo.Pipeline{
-		// Match filters (uses indexes!)
-		{{Key: "$match", Value: matchFilter}},
-		// Sort by season descending to get most recent first
-		{{Key: "$sort", Value: bson.D{{Key: "season", Value: -1}}}},
-		// Group by nfl_id and take the first (most recent) document
-		{{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: "$nfl_id"},
-			{Key: "doc", Value: bson.D{{Key: "$first", Value: "$$ROOT"}}},
-		}}},
-		// Replace root with the document
-		{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$doc"}}}},
-		// Sort by name (or other field) - uses name index!
-		{{Key: "$sort", Value: bson.D{{Key: sortField, Value: sortOrder}}}},
-		// Pagination
-		{{Key: "$skip", Value: skip}},
-		{{Key: "$limit", Value: limit}},
+	query := services.PlayerListQuery{
+		Filter:     matchFilter,
+		SeasonType: seasonType,
+		Sort:       sortField,
+		Descending: c.Query("order") == "desc",
+		Skip:       skip,
+		Limit:      limit,
 	}
 
-	cursor, err := collection.Aggregate(ctx, pipeline)
+	// Count unique players matching the filter while the page is fetched
+	totalCh := make(chan int64, 1)
+	go func() {
+		var result []struct {
+			Total int64 `bson:"total"`
+		}
+		countCursor, err := collection.Aggregate(ctx, services.PlayerCountPipeline(query))
+		if err == nil {
+			err = countCursor.All(ctx, &result)
+		}
+		if err != nil {
+			log.Printf("❌ Count error: %v", err)
+			totalCh <- -1
+			return
+		}
+		if len(result) == 0 {
+			totalCh <- 0
+			return
+		}
+		totalCh <- result[0].Total
+	}()
+
+	cursor, err := collection.Aggregate(ctx, services.PlayerListPipeline(query))
 	if err != nil {
 		log.Printf("❌ Aggregation error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch players"})
@@ -136,7 +175,7 @@ func (h *PlayerHandler) List(c *gin.Context) {
 	// Single batch query for all stats (uses nfl_id+season index!)
 	statsFilter := bson.M{
 		"nfl_id":      bson.M{"$in": nflIDs},
-		"season_type": "REGPOST",
+		"season_type": seasonType,
 	}
 
 	statsCursor, err := h.db.Collection("player_stats").Find(ctx, statsFilter)
@@ -199,6 +238,11 @@ func (h *PlayerHandler) List(c *gin.Context) {
 			enriched.ForcedFumbles = stats.ForcedFumbles
 			enriched.FumbleRecoveries = stats.FumbleRecoveries
 
+			// Fantasy Points
+			enriched.FantasyPoints = stats.FantasyPoints
+			enriched.FantasyPointsPPR = stats.FantasyPointsPPR
+			enriched.Points = headlinePoints(stats, scoring)
+
 			// Store EPA for frontend
 			enriched.AvgEPA = stats.EPA
 		}
@@ -206,19 +250,46 @@ func (h *PlayerHandler) List(c *gin.Context) {
 		enrichedPlayers = append(enrichedPlayers, enriched)
 	}
 
+	total := <-totalCh
+	if total < 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count players"})
+		return
+	}
+	totalPages := int64(0)
+	if limit > 0 {
+		totalPages = (total + int64(limit) - 1) / int64(limit)
+	}
+
 	// Return enriched players (not raw players!)
 	c.JSON(http.StatusOK, gin.H{
-		"players": enrichedPlayers,
-		"count":   len(enrichedPlayers),
-		"page":    page,
-		"limit":   limit,
+		"players":     enrichedPlayers,
+		"count":       len(enrichedPlayers),
+		"page":        page,
+		"limit":       limit,
+		"total":       total,
+		"total_pages": totalPages,
+		"season_type": seasonType,
+		"scoring":     scoring.Name,
 	})
 }
 
+// headlinePoints picks the stored fantasy total for a scoring format. Half PPR isn't stored
+// but sits exactly halfway between standard and PPR.
+func headlinePoints(stats models.PlayerStats, scoring services.ScoringConfig) float64 {
+	switch scoring.Name {
+	case services.ScoringStandard.Name:
+		return stats.FantasyPoints
+	case services.ScoringHalfPPR.Name:
+		return (stats.FantasyPoints + stats.FantasyPointsPPR) / 2
+	default:
+		return stats.FantasyPointsPPR
+	}
+}
+
 // Get returns a single player by ID
 func (h *PlayerHandler) Get(c *gin.Context) {
 	collection := h.db.Collection("players")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	id := c.Param("id")
@@ -248,11 +319,18 @@ func (h *PlayerHandler) Get(c *gin.Context) {
 // GetStats returns player statistics for a specific season and week
 func (h *PlayerHandler) GetStats(c *gin.Context) {
 	collection := h.db.Collection("players")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
 	id := c.Param("id")
-	season, _ := strconv.Atoi(c.DefaultQuery("season", strconv.Itoa(time.Now().Year())))
+	year, ok := httputil.QuerySeason(c, season.Season(c.Request.Context()))
+	if !ok {
+		return
+	}
+	seasonType, ok := seasonTypeParam(c)
+	if !ok {
+		return
+	}
 
 	objID, err := bson.ObjectIDFromHex(id)
 	if err != nil {
@@ -269,12 +347,7 @@ func (h *PlayerHandler) GetStats(c *gin.Context) {
 
 	// Query player stats from player_stats collection
 	statsCollection := h.db.Collection("player_stats")
-	filter := bson.M{"nfl_id": player.NFLID}
-	if season > 0 {
-		filter["season"] = season
-	}
-
-	cursor, err := statsCollection.Find(ctx, filter)
+	cursor, err := statsCollection.Find(ctx, services.PlayerStatsFilter(player.NFLID, year, seasonType))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stats"})
 		return
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestSeasonTypeParam(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/stats", func(c *gin.Context) {
+		if seasonType, ok := seasonTypeParam(c); ok {
+			c.String(http.StatusOK, seasonType)
+		}
+	})
+
+	tests := []struct {
+		query    string
+		wantCode int
+		want     string
+	}{
+		{"", http.StatusOK, "REG"},
+		{"?season_type=POST", http.StatusOK, "POST"},
+		{"?season_type=regpost", http.StatusOK, "REGPOST"},
+		{"?season_type=PRE", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats"+tt.query, nil))
+		if w.Code != tt.wantCode {
+			t.Errorf("GET /stats%s = %d, want %d", tt.query, w.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantCode == http.StatusOK && w.Body.String() != tt.want {
+			t.Errorf("season type for %q = %q, want %q", tt.query, w.Body.String(), tt.want)
+		}
+	}
+}
+
+func TestGetStatsValidatesSeason(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The season is validated before any query, so an unreachable database is never used
+	client, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1").SetServerSelectionTimeout(100 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	h := &PlayerHandler{db: client.Database("test")}
+	router := gin.New()
+	router.GET("/players/:id/stats", h.GetStats)
+
+	for _, query := range []string{"?season=abc", "?season=1800", "?season=2024&season_type=PRE"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/players/65f1a2b3c4d5e6f7a8b9c0d1/stats"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET stats%s = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHeadlinePoints(t *testing.T) {
+	// 80 receptions separate standard from PPR
+	stats := models.PlayerStats{FantasyPoints: 180, FantasyPointsPPR: 260}
+
+	tests := []struct {
+		scoring string
+		want    float64
+	}{
+		{"standard", 180},
+		{"half_ppr", 220},
+		{"half", 220},
+		{"ppr", 260},
+	}
+
+	for _, tt := range tests {
+		scoring, ok := services.ScoringPreset(tt.scoring)
+		if !ok {
+			t.Fatalf("ScoringPreset(%q) not found", tt.scoring)
+		}
+		if got := headlinePoints(stats, scoring); got != tt.want {
+			t.Errorf("headlinePoints(%s) = %v, want %v", tt.scoring, got, tt.want)
+		}
+	}
+}
+
+func TestListValidatesScoring(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Query params are validated before any query, so an unreachable database is never used
+	client, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1").SetServerSelectionTimeout(100 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	h := &PlayerHandler{db: client.Database("test")}
+	router := gin.New()
+	router.GET("/players", h.List)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/players?scoring=superflex", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET /players?scoring=superflex = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
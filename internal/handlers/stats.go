@@ -30,7 +30,7 @@ type DashboardStats struct {
 
 // GetDashboardStats returns statistics from the database (optimized with estimated counts)
 func (h *StatsHandler) GetDashboardStats(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second) // Reduced timeout
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second) // Reduced timeout
 	defer cancel()
 
 	stats := DashboardStats{
@@ -1,43 +1,175 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/ai-atl/nfl-platform/internal/services"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type TradeHandler struct {
-	db *mongo.Database
+	db                   *mongo.Database
+	tradeAnalyzerService *services.TradeAnalyzerService
 }
 
 func NewTradeHandler(db *mongo.Database) *TradeHandler {
-	return &TradeHandler{db: db}
+	return &TradeHandler{
+		db:                   db,
+		tradeAnalyzerService: services.NewTradeAnalyzerService(db),
+	}
 }
 
+// TradeAnalysisRequest lists the nfl_ids moving in each direction.
+// team_b_gives/team_b_gets are optional mirrors of team_a_gets/team_a_gives.
 type TradeAnalysisRequest struct {
 	TeamAGives []string `json:"team_a_gives" binding:"required"`
 	TeamAGets  []string `json:"team_a_gets" binding:"required"`
-	TeamBGives []string `json:"team_b_gives" binding:"required"`
-	TeamBGets  []string `json:"team_b_gets" binding:"required"`
+	TeamBGives []string `json:"team_b_gives"`
+	TeamBGets  []string `json:"team_b_gets"`
+	Season     int      `json:"season"`
+	Week       int      `json:"week"`
 }
 
-// Analyze evaluates a trade and provides fairness assessment
+// Analyze evaluates a trade, provides a fairness assessment and saves it to the user's history
 func (h *TradeHandler) Analyze(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var req TradeAnalysisRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// TODO: Implement trade analysis with EPA metrics and AI insights
+	if len(req.TeamAGives) == 0 || len(req.TeamAGets) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "both sides of the trade must include at least one player"})
+		return
+	}
+	if (len(req.TeamBGives) > 0 && !sameIDs(req.TeamBGives, req.TeamAGets)) ||
+		(len(req.TeamBGets) > 0 && !sameIDs(req.TeamBGets, req.TeamAGives)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "team_b_gives/team_b_gets must mirror team_a_gets/team_a_gives"})
+		return
+	}
+
+	scoring, ok := scoringParam(c)
+	if !ok {
+		return
+	}
+
+	currentSeason, currentWeek := season.Current(c.Request.Context())
+	if req.Season == 0 {
+		req.Season = currentSeason
+	}
+	if req.Week == 0 {
+		req.Week = currentWeek
+	}
+
+	analysis, err := h.tradeAnalyzerService.WithScoring(scoring).AnalyzeTrade(c.Request.Context(), req.TeamAGives, req.TeamAGets, req.Season, req.Week)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// A failed save shouldn't cost the user the analysis they waited for
+	if trade, err := h.tradeAnalyzerService.SaveTrade(c.Request.Context(), userID, analysis); err != nil {
+		log.Printf("Failed to save trade for user %s: %v", userID.Hex(), err)
+	} else {
+		analysis.TradeID = trade.ID.Hex()
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// History returns the user's saved trades, newest first
+// GET /api/v1/trades/history?page=1&limit=20
+func (h *TradeHandler) History(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	trades, total, err := h.tradeAnalyzerService.ListTrades(ctx, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trade history"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"team_a_grade": "A-",
-		"team_b_grade": "B+",
-		"fairness_score": 8.5,
-		"ai_analysis": "Team A receives more consistent performers with better playoff schedules",
-		"team_a_value_change": "+12.5 projected points per week",
-		"team_b_value_change": "+8.2 projected points per week",
+		"trades": trades,
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
 	})
 }
 
+// Get returns one of the user's saved trades
+// GET /api/v1/trades/:id
+func (h *TradeHandler) Get(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	tradeID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trade ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	trade, err := h.tradeAnalyzerService.GetTrade(ctx, userID, tradeID)
+	switch {
+	case errors.Is(err, services.ErrTradeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trade not found"})
+		return
+	case errors.Is(err, services.ErrTradeForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not own this trade"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trade"})
+		return
+	}
+
+	c.JSON(http.StatusOK, trade)
+}
+
+// sameIDs reports whether a and b contain the same IDs regardless of order
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, id := range a {
+		counts[id]++
+	}
+	for _, id := range b {
+		counts[id]--
+		if counts[id] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -2,110 +2,88 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
-	"strconv"
 	"time"
 
-	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/services"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type VoteHandler struct {
-	db *mongo.Database
+	db          *mongo.Database
+	voteService *services.VoteService
 }
 
 func NewVoteHandler(db *mongo.Database) *VoteHandler {
-	return &VoteHandler{db: db}
+	return &VoteHandler{
+		db:          db,
+		voteService: services.NewVoteService(db),
+	}
+}
+
+type VoteRequest struct {
+	SubjectType string `json:"subject_type" binding:"required"`
+	SubjectID   string `json:"subject_id" binding:"required"`
+	Choice      string `json:"choice" binding:"required"`
 }
 
-// Create creates a new vote
+// Create records a vote, replacing the user's previous vote on the same subject
 func (h *VoteHandler) Create(c *gin.Context) {
 	userID, _ := c.Get("user_id")
-	objID, _ := bson.ObjectIDFromHex(userID.(string))
+	objID, err := bson.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return
+	}
 
-	var vote models.Vote
-	if err := c.ShouldBindJSON(&vote); err != nil {
+	var req VoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	vote.ID = bson.NewObjectID()
-	vote.UserID = objID
-	vote.CreatedAt = time.Now()
-
-	collection := h.db.Collection("votes")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	_, err := collection.InsertOne(ctx, vote)
+	vote, err := h.voteService.Create(ctx, objID, req.SubjectType, req.SubjectID, req.Choice)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create vote"})
+		if errors.Is(err, services.ErrInvalidVote) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save vote"})
 		return
 	}
 
 	c.JSON(http.StatusCreated, vote)
 }
 
-// GetConsensus returns community consensus for a player
+// GetConsensus returns community consensus for a subject
+// GET /api/v1/votes/consensus?subject_type=start_sit&subject_id=00-0036355
 func (h *VoteHandler) GetConsensus(c *gin.Context) {
-	playerID := c.Query("player_id")
-	week, _ := strconv.Atoi(c.Query("week"))
+	subjectType := c.Query("subject_type")
+	subjectID := c.Query("subject_id")
 
-	if playerID == "" || week == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "player_id and week are required"})
+	if subjectType == "" || subjectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "subject_type and subject_id are required"})
 		return
 	}
 
-	collection := h.db.Collection("votes")
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	filter := bson.M{
-		"player_id": playerID,
-		"week":      week,
-	}
-
-	cursor, err := collection.Find(ctx, filter)
+	consensus, err := h.voteService.GetConsensus(ctx, subjectType, subjectID)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidVote) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch votes"})
 		return
 	}
-	defer cursor.Close(ctx)
 
-	var votes []models.Vote
-	if err := cursor.All(ctx, &votes); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode votes"})
-		return
-	}
-
-	// Count vote types
-	consensus := map[string]int{
-		"over": 0,
-		"under": 0,
-		"lock": 0,
-		"fade": 0,
-	}
-
-	for _, vote := range votes {
-		consensus[vote.PredictionType]++
-	}
-
-	total := len(votes)
-	percentages := map[string]float64{}
-	for key, count := range consensus {
-		if total > 0 {
-			percentages[key] = float64(count) / float64(total) * 100
-		}
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"player_id":   playerID,
-		"week":        week,
-		"total_votes": total,
-		"consensus":   consensus,
-		"percentages": percentages,
-	})
+	c.JSON(http.StatusOK, consensus)
 }
-
@@ -0,0 +1,116 @@
+// Package httputil holds the request parsing and error response helpers shared by the
+// HTTP handlers.
+package httputil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes returned in the "code" field of error responses
+const (
+	CodeInvalidParam = "invalid_parameter"
+	CodeNotFound     = "not_found"
+	CodeInternal     = "internal_error"
+	CodeTimeout      = "timeout"
+)
+
+const (
+	// MinSeason is the first season nflverse has play-by-play data for
+	MinSeason = 1999
+	// MaxWeek is the last week of an NFL season, counting the playoffs
+	MaxWeek = 22
+)
+
+// ErrorBody is the body of every error response: {"error": {"code": ..., "message": ...}}
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RespondError aborts the request with status and a {"error": {"code", "message"}} body.
+// A server error after the request deadline has passed is reported as a 503 timeout,
+// since the failure was the deadline rather than the query.
+func RespondError(c *gin.Context, status int, code, msg string) {
+	if status >= http.StatusInternalServerError && errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		status, code, msg = http.StatusServiceUnavailable, CodeTimeout, "request timed out"
+	}
+	c.AbortWithStatusJSON(status, gin.H{"error": ErrorBody{Code: code, Message: msg}})
+}
+
+// MaxSeason is the latest season a request may ask for: next year, so schedules released
+// before January can be queried
+func MaxSeason() int {
+	return time.Now().Year() + 1
+}
+
+// ParseSeason parses a season value. An empty value returns def; anything else must be a
+// year between MinSeason and MaxSeason.
+func ParseSeason(raw string, def int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	season, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("season must be a year")
+	}
+	if err := ValidateSeason(season); err != nil {
+		return 0, err
+	}
+	return season, nil
+}
+
+// ValidateSeason checks that season is between MinSeason and MaxSeason
+func ValidateSeason(season int) error {
+	if season < MinSeason || season > MaxSeason() {
+		return fmt.Errorf("season must be between %d and %d", MinSeason, MaxSeason())
+	}
+	return nil
+}
+
+// ParseWeek parses a week value. An empty value returns def, negative weeks are rejected
+// and weeks past MaxWeek are capped to it. 0 is allowed and means "all weeks" where a
+// handler supports it.
+func ParseWeek(raw string, def int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	week, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("week must be a number")
+	}
+	if week < 0 {
+		return 0, fmt.Errorf("week must not be negative")
+	}
+	if week > MaxWeek {
+		week = MaxWeek
+	}
+	return week, nil
+}
+
+// QuerySeason parses the season query parameter, responding 400 if it is invalid
+func QuerySeason(c *gin.Context, def int) (int, bool) {
+	season, err := ParseSeason(c.Query("season"), def)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, CodeInvalidParam, err.Error())
+		return 0, false
+	}
+	return season, true
+}
+
+// QueryWeek parses the named week query parameter (week, from, to, through_week),
+// responding 400 if it is invalid
+func QueryWeek(c *gin.Context, name string, def int) (int, bool) {
+	week, err := ParseWeek(c.Query(name), def)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, CodeInvalidParam, fmt.Sprintf("%s: %v", name, err))
+		return 0, false
+	}
+	return week, true
+}
@@ -0,0 +1,117 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseSeason(t *testing.T) {
+	next := strconv.Itoa(MaxSeason())
+	tooLate := strconv.Itoa(MaxSeason() + 1)
+
+	tests := []struct {
+		name    string
+		raw     string
+		def     int
+		want    int
+		wantErr bool
+	}{
+		{"empty uses default", "", 2024, 2024, false},
+		{"valid season", "2023", 2025, 2023, false},
+		{"first season", "1999", 2025, 1999, false},
+		{"next season", next, 2025, MaxSeason(), false},
+		{"before play-by-play data", "1998", 2025, 0, true},
+		{"too far ahead", tooLate, 2025, 0, true},
+		{"not a number", "last", 2025, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSeason(tt.raw, tt.def)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSeason(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSeason(%q) = %d, want %d", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWeek(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		def     int
+		want    int
+		wantErr bool
+	}{
+		{"empty uses default", "", 10, 10, false},
+		{"zero means all weeks", "0", 10, 0, false},
+		{"regular season week", "7", 10, 7, false},
+		{"last playoff week", "22", 10, 22, false},
+		{"past the playoffs is capped", "40", 10, MaxWeek, false},
+		{"negative", "-1", 10, 0, true},
+		{"not a number", "seven", 10, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWeek(tt.raw, tt.def)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWeek(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseWeek(%q) = %d, want %d", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuerySeasonDefaultsToCurrentSeason(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		target     string
+		override   int
+		wantStatus int
+		want       int
+	}{
+		{"default season", "/", 0, http.StatusOK, season.DefaultSeason},
+		{"overridden season", "/", 2022, http.StatusOK, 2022},
+		{"requested season wins", "/?season=2021", 2022, http.StatusOK, 2021},
+		{"invalid season", "/?season=1990", 0, http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.override > 0 {
+				req = req.WithContext(season.WithOverride(req.Context(), tt.override, 0))
+			}
+			c.Request = req
+
+			got, ok := QuerySeason(c, season.Season(c.Request.Context()))
+			if ok != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("QuerySeason() ok = %v, want status %d", ok, tt.wantStatus)
+			}
+			if !ok {
+				if rec.Code != tt.wantStatus {
+					t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("QuerySeason() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
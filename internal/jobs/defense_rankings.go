@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/ai-atl/nfl-platform/internal/services"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// RefreshDefenseRankings precomputes defensive rankings for a season through throughWeek.
+// throughWeek=0 uses the latest week with a final game. It returns the week used and the
+// number of ranking documents written.
+func RefreshDefenseRankings(ctx context.Context, db *mongo.Database, season, throughWeek int) (int, int, error) {
+	if throughWeek == 0 {
+		week, err := lastCompletedWeek(ctx, db, season)
+		if err != nil {
+			return 0, 0, err
+		}
+		throughWeek = week
+	}
+
+	written, err := services.NewDataService(db).PrecomputeDefensiveRankings(ctx, season, throughWeek)
+	if err != nil {
+		return throughWeek, written, err
+	}
+	return throughWeek, written, nil
+}
+
+// lastCompletedWeek returns the highest week of season with a final game, or 0 if none
+func lastCompletedWeek(ctx context.Context, db *mongo.Database, season int) (int, error) {
+	return services.NewDataService(db).LastCompletedWeek(ctx, season)
+}
+
+// ScheduleDefenseRankings refreshes the current season's defensive rankings now and then
+// every interval until ctx is cancelled. The season is resolved on each tick so a
+// long-running process follows it into the next season. A refresh is skipped while the
+// stored rankings already include the latest completed week, so restarts don't recompute them.
+func ScheduleDefenseRankings(ctx context.Context, db *mongo.Database, interval time.Duration) {
+	scheduleDefenseRankings(ctx, interval, func(refreshCtx context.Context, currentSeason int) {
+		current, err := services.NewDataService(db).DefenseRankingsCurrent(refreshCtx, currentSeason)
+		if err != nil {
+			log.Printf("Defense rankings refresh error: %v", err)
+			return
+		}
+		if current {
+			return
+		}
+
+		week, written, err := RefreshDefenseRankings(refreshCtx, db, currentSeason, 0)
+		if err != nil {
+			log.Printf("Defense rankings refresh error: %v", err)
+			return
+		}
+		log.Printf("Precomputed %d defense rankings for %d through week %d", written, currentSeason, week)
+	})
+}
+
+// scheduleDefenseRankings calls refresh with the season current at each tick
+func scheduleDefenseRankings(ctx context.Context, interval time.Duration, refresh func(ctx context.Context, season int)) {
+	tick := func() {
+		refreshCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+		defer cancel()
+		refresh(refreshCtx, season.Season(refreshCtx))
+	}
+
+	tick()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tick()
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/season"
+)
+
+func TestScheduleDefenseRankingsResolvesSeasonEachTick(t *testing.T) {
+	ctx, cancel := context.WithCancel(season.WithOverride(context.Background(), 2026, 0))
+	defer cancel()
+
+	var seasons []int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scheduleDefenseRankings(ctx, time.Millisecond, func(ctx context.Context, s int) {
+			seasons = append(seasons, s)
+			if len(seasons) == 3 {
+				cancel()
+			}
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler did not stop after its context was cancelled")
+	}
+
+	// The first refresh runs immediately and each tick after it resolves the season again
+	if len(seasons) < 3 {
+		t.Fatalf("refreshed %d times, want at least 3", len(seasons))
+	}
+	for i, s := range seasons {
+		if s != 2026 {
+			t.Errorf("refresh %d season = %d, want the context's 2026", i+1, s)
+		}
+	}
+}
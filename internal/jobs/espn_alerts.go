@@ -0,0 +1,173 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/services"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// statusActive is recorded for players ESPN returns without an injury status
+const statusActive = "ACTIVE"
+
+// espnRosterPlayer is the subset of the Flask roster response the poller diffs
+type espnRosterPlayer struct {
+	Name         string  `json:"name"`
+	Position     string  `json:"position"`
+	ProTeam      string  `json:"proTeam"`
+	InjuryStatus *string `json:"injuryStatus"`
+	PlayerID     *int    `json:"playerId,omitempty"`
+}
+
+// ESPNAlertPoller fetches connected users' ESPN rosters and records injury status changes
+// in the alerts collection
+type ESPNAlertPoller struct {
+	db     *mongo.Database
+	client *services.FlaskESPNClient
+}
+
+func NewESPNAlertPoller(db *mongo.Database, client *services.FlaskESPNClient) *ESPNAlertPoller {
+	return &ESPNAlertPoller{db: db, client: client}
+}
+
+// Run polls every interval until ctx is cancelled
+func (p *ESPNAlertPoller) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pollCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+			if err := p.PollAll(pollCtx); err != nil {
+				log.Printf("ESPN alert poll error: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// PollAll checks the roster of every user with ESPN credentials. A failure for one user is
+// logged and doesn't stop the others.
+func (p *ESPNAlertPoller) PollAll(ctx context.Context) error {
+	cursor, err := p.db.Collection("users").Find(ctx, bson.M{
+		"espn_s2":   bson.M{"$nin": bson.A{nil, ""}},
+		"espn_swid": bson.M{"$nin": bson.A{nil, ""}},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to fetch ESPN users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return fmt.Errorf("failed to decode ESPN users: %w", err)
+	}
+
+	for _, user := range users {
+		alerts, err := p.PollUser(ctx, user.ID)
+		if err != nil {
+			log.Printf("ESPN alert poll failed for user %s: %v", user.ID.Hex(), err)
+			continue
+		}
+		if alerts > 0 {
+			log.Printf("Recorded %d ESPN alerts for user %s", alerts, user.ID.Hex())
+		}
+	}
+	return nil
+}
+
+// PollUser fetches a user's roster from their own league, records alerts for injury status changes since the last
+// snapshot and saves the new snapshot. The first poll only stores a baseline. It returns the
+// number of alerts recorded.
+func (p *ESPNAlertPoller) PollUser(ctx context.Context, userID bson.ObjectID) (int, error) {
+	user, err := services.ConnectedESPNUser(ctx, p.db, userID)
+	if err != nil {
+		return 0, err
+	}
+	var roster []espnRosterPlayer
+	if err := p.client.Get(ctx, user, "/api/espn/roster", &roster); err != nil {
+		return 0, fmt.Errorf("failed to fetch roster: %w", err)
+	}
+	current := snapshotPlayers(roster)
+
+	var previous models.RosterSnapshot
+	err = p.db.Collection("roster_snapshots").FindOne(ctx, bson.M{"user_id": userID}).Decode(&previous)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return 0, fmt.Errorf("failed to fetch roster snapshot: %w", err)
+	}
+
+	now := time.Now()
+	alerts := diffInjuryStatuses(userID, previous.Players, current, now)
+	if len(alerts) > 0 {
+		if _, err := p.db.Collection("alerts").InsertMany(ctx, alerts); err != nil {
+			return 0, fmt.Errorf("failed to insert alerts: %w", err)
+		}
+	}
+
+	_, err = p.db.Collection("roster_snapshots").UpdateOne(ctx,
+		bson.M{"user_id": userID},
+		bson.M{"$set": models.RosterSnapshot{UserID: userID, Players: current, UpdatedAt: now}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save roster snapshot: %w", err)
+	}
+
+	return len(alerts), nil
+}
+
+// snapshotPlayers keys a roster by ESPN player ID, falling back to the name
+func snapshotPlayers(roster []espnRosterPlayer) map[string]models.RosterSnapshotPlayer {
+	players := make(map[string]models.RosterSnapshotPlayer, len(roster))
+	for _, rp := range roster {
+		key := rp.Name
+		if rp.PlayerID != nil {
+			key = strconv.Itoa(*rp.PlayerID)
+		}
+		status := statusActive
+		if rp.InjuryStatus != nil && *rp.InjuryStatus != "" {
+			status = *rp.InjuryStatus
+		}
+		players[key] = models.RosterSnapshotPlayer{
+			Name:         rp.Name,
+			Position:     rp.Position,
+			ProTeam:      rp.ProTeam,
+			InjuryStatus: status,
+		}
+	}
+	return players
+}
+
+// diffInjuryStatuses returns an alert for each player on both rosters whose injury status
+// changed. Players added or dropped since the last poll don't raise alerts.
+func diffInjuryStatuses(userID bson.ObjectID, previous, current map[string]models.RosterSnapshotPlayer, now time.Time) []models.RosterAlert {
+	var alerts []models.RosterAlert
+	for key, cur := range current {
+		prev, ok := previous[key]
+		if !ok || prev.InjuryStatus == cur.InjuryStatus {
+			continue
+		}
+		alerts = append(alerts, models.RosterAlert{
+			UserID:     userID,
+			Type:       models.AlertInjuryStatus,
+			PlayerKey:  key,
+			PlayerName: cur.Name,
+			Position:   cur.Position,
+			ProTeam:    cur.ProTeam,
+			FromStatus: prev.InjuryStatus,
+			ToStatus:   cur.InjuryStatus,
+			CreatedAt:  now,
+		})
+	}
+	return alerts
+}
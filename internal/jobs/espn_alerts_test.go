@@ -0,0 +1,108 @@
+package jobs
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestDiffInjuryStatuses(t *testing.T) {
+	userID := bson.NewObjectID()
+	now := time.Date(2025, 11, 9, 17, 0, 0, 0, time.UTC)
+	player := func(name, status string) models.RosterSnapshotPlayer {
+		return models.RosterSnapshotPlayer{Name: name, Position: "WR", ProTeam: "MIN", InjuryStatus: status}
+	}
+
+	tests := []struct {
+		name     string
+		previous map[string]models.RosterSnapshotPlayer
+		current  map[string]models.RosterSnapshotPlayer
+		want     []string // player key: from -> to
+	}{
+		{
+			name:     "first poll has no baseline",
+			previous: nil,
+			current:  map[string]models.RosterSnapshotPlayer{"1": player("Justin Jefferson", "QUESTIONABLE")},
+		},
+		{
+			name:     "unchanged status",
+			previous: map[string]models.RosterSnapshotPlayer{"1": player("Justin Jefferson", "ACTIVE")},
+			current:  map[string]models.RosterSnapshotPlayer{"1": player("Justin Jefferson", "ACTIVE")},
+		},
+		{
+			name:     "status change raises an alert",
+			previous: map[string]models.RosterSnapshotPlayer{"1": player("Justin Jefferson", "ACTIVE")},
+			current:  map[string]models.RosterSnapshotPlayer{"1": player("Justin Jefferson", "QUESTIONABLE")},
+			want:     []string{"1: ACTIVE -> QUESTIONABLE"},
+		},
+		{
+			name: "recovery and downgrade on the same poll",
+			previous: map[string]models.RosterSnapshotPlayer{
+				"1": player("Justin Jefferson", "OUT"),
+				"2": player("Jordan Addison", "ACTIVE"),
+				"3": player("T.J. Hockenson", "QUESTIONABLE"),
+			},
+			current: map[string]models.RosterSnapshotPlayer{
+				"1": player("Justin Jefferson", "ACTIVE"),
+				"2": player("Jordan Addison", "DOUBTFUL"),
+				"3": player("T.J. Hockenson", "QUESTIONABLE"),
+			},
+			want: []string{"1: OUT -> ACTIVE", "2: ACTIVE -> DOUBTFUL"},
+		},
+		{
+			name:     "added and dropped players don't alert",
+			previous: map[string]models.RosterSnapshotPlayer{"1": player("Justin Jefferson", "ACTIVE")},
+			current:  map[string]models.RosterSnapshotPlayer{"2": player("Jordan Addison", "OUT")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := diffInjuryStatuses(userID, tt.previous, tt.current, now)
+
+			var got []string
+			for _, a := range alerts {
+				got = append(got, a.PlayerKey+": "+a.FromStatus+" -> "+a.ToStatus)
+				if a.UserID != userID || a.Type != models.AlertInjuryStatus || !a.CreatedAt.Equal(now) {
+					t.Errorf("alert %+v has the wrong user, type or time", a)
+				}
+				if a.PlayerName != tt.current[a.PlayerKey].Name || a.ProTeam != "MIN" {
+					t.Errorf("alert %+v doesn't describe the current player", a)
+				}
+			}
+			sort.Strings(got)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("alerts = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("alerts = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotPlayers(t *testing.T) {
+	id := 4262921
+	questionable := "QUESTIONABLE"
+	empty := ""
+	roster := []espnRosterPlayer{
+		{Name: "Justin Jefferson", Position: "WR", ProTeam: "MIN", InjuryStatus: &questionable, PlayerID: &id},
+		{Name: "Vikings D/ST", Position: "D/ST", ProTeam: "MIN", InjuryStatus: &empty},
+		{Name: "Will Reichard", Position: "K", ProTeam: "MIN"},
+	}
+
+	got := snapshotPlayers(roster)
+	if got["4262921"].InjuryStatus != "QUESTIONABLE" {
+		t.Errorf("player with an ID = %+v, want keyed by ID with status QUESTIONABLE", got["4262921"])
+	}
+	if got["Vikings D/ST"].InjuryStatus != statusActive || got["Will Reichard"].InjuryStatus != statusActive {
+		t.Errorf("players without a status = %+v, want keyed by name and ACTIVE", got)
+	}
+}
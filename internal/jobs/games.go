@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/parquet"
+	"github.com/ai-atl/nfl-platform/pkg/mongodb"
+	"github.com/ai-atl/nfl-platform/pkg/nflverse"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// UpsertGames inserts new games and refreshes existing ones by game_id, so scores,
+// status and lines are backfilled when a schedule is reloaded after games are played.
+// It returns the number of games inserted or changed, including those written before a
+// partial failure.
+func UpsertGames(ctx context.Context, db *mongo.Database, games []models.Game) (int, error) {
+	if len(games) == 0 {
+		return 0, nil
+	}
+
+	result, err := db.Collection("games").BulkWrite(ctx, gameWrites(games), options.BulkWrite().SetOrdered(false))
+	written := 0
+	if result != nil {
+		written = int(result.UpsertedCount + result.ModifiedCount)
+	}
+	if err != nil {
+		return written, fmt.Errorf("failed to upsert games: %w", err)
+	}
+
+	return written, nil
+}
+
+// gameWrites upserts each game on its game_id, replacing every parsed field so a reload
+// re-derives status and scores
+func gameWrites(games []models.Game) []mongo.WriteModel {
+	writes := make([]mongo.WriteModel, 0, len(games))
+	for _, game := range games {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"game_id": game.GameID}).
+			SetUpdate(bson.M{"$set": game}).
+			SetUpsert(true))
+	}
+	return writes
+}
+
+// RefreshWeek downloads the current schedule and upserts the games for one week, recording
+// the refresh in load_state. week=0 picks the latest week of the season that has kicked off.
+// It returns the week that was refreshed and the games written for it.
+func RefreshWeek(ctx context.Context, db *mongo.Database, season, week int) (int, []models.Game, error) {
+	data, err := nflverse.NewClient().FetchSchedules(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	games, err := parquet.ParseSchedules(data)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if week == 0 {
+		week = currentWeek(games, season, time.Now())
+	}
+
+	weekGames := make([]models.Game, 0, 16)
+	for _, g := range games {
+		if g.Season == season && g.Week == week {
+			weekGames = append(weekGames, g)
+		}
+	}
+	if len(weekGames) == 0 {
+		return week, nil, fmt.Errorf("no games found for %d week %d", season, week)
+	}
+
+	if _, err := UpsertGames(ctx, db, weekGames); err != nil {
+		return week, nil, err
+	}
+	if err := mongodb.RecordLoad(ctx, db, "schedules", season, len(weekGames)); err != nil {
+		return week, weekGames, err
+	}
+	return week, weekGames, nil
+}
+
+// currentWeek returns the latest week of season with a game that has kicked off,
+// or week 1 before the season starts
+func currentWeek(games []models.Game, season int, now time.Time) int {
+	week := 1
+	for _, g := range games {
+		if g.Season == season && !g.StartTime.IsZero() && g.StartTime.Before(now) && g.Week > week {
+			week = g.Week
+		}
+	}
+	return week
+}
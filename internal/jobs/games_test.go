@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// applyGameWrites applies gameWrites to games stored by game_id, the way the upsert would
+func applyGameWrites(t *testing.T, stored map[string]bson.M, writes []mongo.WriteModel) {
+	t.Helper()
+	for _, w := range writes {
+		m := w.(*mongo.UpdateOneModel)
+		if m.Upsert == nil || !*m.Upsert {
+			t.Fatal("game write is not an upsert")
+		}
+		id := m.Filter.(bson.M)["game_id"].(string)
+
+		raw, err := bson.Marshal(m.Update.(bson.M)["$set"])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var set bson.M
+		if err := bson.Unmarshal(raw, &set); err != nil {
+			t.Fatal(err)
+		}
+
+		doc, ok := stored[id]
+		if !ok {
+			doc = bson.M{"game_id": id}
+			stored[id] = doc
+		}
+		for k, v := range set {
+			doc[k] = v
+		}
+	}
+}
+
+func TestGameWritesBackfillFinalScores(t *testing.T) {
+	kickoff := time.Date(2025, 11, 16, 18, 0, 0, 0, time.UTC)
+	scheduled := []models.Game{
+		{GameID: "2025_11_KC_DEN", Season: 2025, Week: 11, HomeTeam: "DEN", AwayTeam: "KC", StartTime: kickoff, Status: "scheduled", VegasLine: 3.5},
+		{GameID: "2025_11_DET_PHI", Season: 2025, Week: 11, HomeTeam: "PHI", AwayTeam: "DET", StartTime: kickoff, Status: "scheduled"},
+	}
+	final := []models.Game{
+		{GameID: "2025_11_KC_DEN", Season: 2025, Week: 11, HomeTeam: "DEN", AwayTeam: "KC", StartTime: kickoff, Status: "final", VegasLine: 3.5, HomeScore: 22, AwayScore: 19},
+	}
+
+	stored := make(map[string]bson.M)
+	applyGameWrites(t, stored, gameWrites(scheduled))
+	applyGameWrites(t, stored, gameWrites(final))
+
+	if len(stored) != 2 {
+		t.Fatalf("stored %d games after reload, want 2", len(stored))
+	}
+
+	var game models.Game
+	raw, _ := bson.Marshal(stored["2025_11_KC_DEN"])
+	if err := bson.Unmarshal(raw, &game); err != nil {
+		t.Fatal(err)
+	}
+	if game.Status != "final" || game.HomeScore != 22 || game.AwayScore != 19 {
+		t.Errorf("reloaded game = %s %d-%d, want final 19-22", game.Status, game.AwayScore, game.HomeScore)
+	}
+	if game.VegasLine != 3.5 || !game.StartTime.Equal(kickoff) {
+		t.Errorf("reloaded line/kickoff = %v/%v, want 3.5/%v", game.VegasLine, game.StartTime, kickoff)
+	}
+	if got := stored["2025_11_DET_PHI"]["status"]; got != "scheduled" {
+		t.Errorf("untouched game status = %v, want scheduled", got)
+	}
+}
+
+func TestCurrentWeek(t *testing.T) {
+	now := time.Date(2025, 11, 18, 12, 0, 0, 0, time.UTC)
+	games := []models.Game{
+		{Season: 2025, Week: 10, StartTime: now.AddDate(0, 0, -9)},
+		{Season: 2025, Week: 11, StartTime: now.AddDate(0, 0, -2)},
+		{Season: 2025, Week: 12, StartTime: now.AddDate(0, 0, 5)},
+		{Season: 2024, Week: 18, StartTime: now.AddDate(-1, 0, 0)},
+	}
+
+	if got := currentWeek(games, 2025, now); got != 11 {
+		t.Errorf("currentWeek() = %d, want 11", got)
+	}
+	if got := currentWeek(games, 2026, now); got != 1 {
+		t.Errorf("currentWeek() before the season = %d, want 1", got)
+	}
+}
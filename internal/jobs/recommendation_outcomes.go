@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/ai-atl/nfl-platform/internal/services"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ScoreRecommendations scores a season's unscored start/sit and waiver recommendations for
+// every week with a final game. It returns the week scored through and the number of
+// outcomes written.
+func ScoreRecommendations(ctx context.Context, db *mongo.Database, season int) (int, int, error) {
+	week, err := lastCompletedWeek(ctx, db, season)
+	if err != nil || week == 0 {
+		return week, 0, err
+	}
+
+	written, err := services.NewRecommendationService(db).ScorePending(ctx, season, week)
+	return week, written, err
+}
+
+// ScheduleRecommendationScoring scores finished weeks' recommendations now and then every
+// interval until ctx is cancelled. The season is resolved on every run so a long-running
+// server moves on to the new season without a restart.
+func ScheduleRecommendationScoring(ctx context.Context, db *mongo.Database, interval time.Duration) {
+	score := func() {
+		scoreCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+		defer cancel()
+
+		currentSeason := season.Season(scoreCtx)
+		week, written, err := ScoreRecommendations(scoreCtx, db, currentSeason)
+		if err != nil {
+			log.Printf("Recommendation scoring error: %v", err)
+			return
+		}
+		log.Printf("Scored %d recommendations for %d through week %d", written, currentSeason, week)
+	}
+
+	score()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			score()
+		}
+	}
+}
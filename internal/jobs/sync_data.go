@@ -5,6 +5,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/ai-atl/nfl-platform/internal/season"
 	"github.com/ai-atl/nfl-platform/pkg/nflverse"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -14,7 +15,7 @@ func SyncNFLverseData(ctx context.Context, db *mongo.Database) error {
 	log.Println("Starting NFLverse data sync...")
 	
 	client := nflverse.NewClient()
-	currentSeason := time.Now().Year()
+	currentSeason := season.Season(ctx)
 	
 	// Sync player stats
 	log.Printf("Fetching player stats for season %d", currentSeason)
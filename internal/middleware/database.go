@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DatabaseAvailable responds 503 with a Retry-After hint while healthy reports the database
+// as unreachable, so clients get a clear error instead of a timeout or a driver message
+func DatabaseAvailable(healthy func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !healthy() {
+			c.Header("Retry-After", "5")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Database temporarily unavailable, please retry shortly"})
+			return
+		}
+		c.Next()
+	}
+}
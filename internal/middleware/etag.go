@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// dataUpdateSources are the collections and timestamp fields that record when the data
+// behind leaderboards last changed: loader runs and precomputed defense rankings
+var dataUpdateSources = []struct {
+	collection string
+	field      string
+}{
+	{"load_state", "loaded_at"},
+	{"defense_rankings", "computed_at"},
+}
+
+// etagWriter adds the ETag and Cache-Control headers only to successful responses, so an
+// error is never cached or revalidated
+type etagWriter struct {
+	gin.ResponseWriter
+	etag         string
+	cacheControl string
+}
+
+func (w *etagWriter) WriteHeader(code int) {
+	if code == http.StatusOK {
+		w.Header().Set("ETag", w.etag)
+		w.Header().Set("Cache-Control", w.cacheControl)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// ETag lets clients revalidate expensive GET endpoints instead of having them recomputed.
+// The weak ETag covers the path, query params, current season and week, and the last time
+// the underlying data changed; a request whose If-None-Match matches gets 304 without the
+// handler running. Successful responses may be reused for maxAge before revalidating. If
+// the last update can't be read the request is served normally without caching headers.
+func ETag(db *mongo.Database, maxAge time.Duration) gin.HandlerFunc {
+	return etag(func(ctx context.Context) (time.Time, error) {
+		return lastDataUpdate(ctx, db)
+	}, maxAge)
+}
+
+// etag is ETag with the last data update read through lastUpdate
+func etag(lastUpdate func(ctx context.Context) (time.Time, error), maxAge time.Duration) gin.HandlerFunc {
+	cacheControl := fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		updated, err := lastUpdate(c.Request.Context())
+		if err != nil {
+			log.Printf("ETag skipped for %s: %v", c.Request.URL.Path, err)
+			c.Next()
+			return
+		}
+
+		currentSeason, currentWeek := season.Current(c.Request.Context())
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s?%s|%d|%d|%d",
+			c.Request.URL.Path, c.Request.URL.Query().Encode(), currentSeason, currentWeek, updated.UnixNano())))
+		tag := `W/"` + hex.EncodeToString(sum[:12]) + `"`
+
+		if etagMatches(c.GetHeader("If-None-Match"), tag) {
+			c.Header("ETag", tag)
+			c.Header("Cache-Control", cacheControl)
+			c.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+
+		c.Writer = &etagWriter{ResponseWriter: c.Writer, etag: tag, cacheControl: cacheControl}
+		c.Next()
+	}
+}
+
+// lastDataUpdate returns the latest timestamp across dataUpdateSources (zero when none
+// have been written yet)
+func lastDataUpdate(ctx context.Context, db *mongo.Database) (time.Time, error) {
+	var latest time.Time
+	for _, source := range dataUpdateSources {
+		var doc bson.M
+		opts := options.FindOne().
+			SetSort(bson.D{{Key: source.field, Value: -1}}).
+			SetProjection(bson.M{"_id": 0, source.field: 1})
+		err := db.Collection(source.collection).FindOne(ctx, bson.M{}, opts).Decode(&doc)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to read %s: %w", source.collection, err)
+		}
+		if dt, ok := doc[source.field].(bson.DateTime); ok && dt.Time().After(latest) {
+			latest = dt.Time()
+		}
+	}
+	return latest, nil
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing weakly (the W/
+// prefix is ignored) as RFC 9110 requires for If-None-Match
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestETagMatches(t *testing.T) {
+	const tag = `W/"abc123"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`W/"abc123"`, true},
+		{`"abc123"`, true}, // Weak comparison ignores W/
+		{`"other", W/"abc123"`, true},
+		{`  W/"abc123"  `, true},
+		{"*", true},
+		{`W/"abc124"`, false},
+		{`abc123`, false},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, tag); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+// newETagRouter serves GET and POST /leaders behind etag, counting handler runs and
+// reading the last data update from *updated
+func newETagRouter(updated *time.Time, updateErr *error, status int) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+
+	calls := 0
+	lastUpdate := func(ctx context.Context) (time.Time, error) { return *updated, *updateErr }
+	router := gin.New()
+	router.Use(etag(lastUpdate, time.Minute))
+	handler := func(c *gin.Context) {
+		calls++
+		c.JSON(status, gin.H{"leaders": []string{"KC"}})
+	}
+	router.GET("/leaders", handler)
+	router.POST("/leaders", handler)
+	return router, &calls
+}
+
+func getLeaders(router *gin.Engine, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestETagRevalidation(t *testing.T) {
+	updated := time.Date(2025, 11, 2, 12, 0, 0, 0, time.UTC)
+	var updateErr error
+	router, calls := newETagRouter(&updated, &updateErr, http.StatusOK)
+
+	first := getLeaders(router, "/leaders?season=2025", "")
+	tag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || tag == "" {
+		t.Fatalf("first request = %d with ETag %q, want 200 with an ETag", first.Code, tag)
+	}
+	if got := first.Header().Get("Cache-Control"); got != "private, max-age=60" {
+		t.Errorf("Cache-Control = %q, want private, max-age=60", got)
+	}
+
+	cached := getLeaders(router, "/leaders?season=2025", tag)
+	if cached.Code != http.StatusNotModified || cached.Body.Len() != 0 {
+		t.Errorf("revalidation = %d with %d body bytes, want an empty 304", cached.Code, cached.Body.Len())
+	}
+	if cached.Header().Get("ETag") != tag {
+		t.Errorf("304 ETag = %q, want %q", cached.Header().Get("ETag"), tag)
+	}
+	if *calls != 1 {
+		t.Errorf("handler ran %d times, want 1 (the 304 must skip it)", *calls)
+	}
+
+	// Different params are a different resource
+	other := getLeaders(router, "/leaders?season=2024", tag)
+	if other.Code != http.StatusOK || other.Header().Get("ETag") == tag {
+		t.Errorf("other params = %d with ETag %q, want 200 with a new ETag", other.Code, other.Header().Get("ETag"))
+	}
+
+	// A load recorded in load_state changes the ETag
+	updated = updated.Add(time.Hour)
+	reloaded := getLeaders(router, "/leaders?season=2025", tag)
+	if reloaded.Code != http.StatusOK || reloaded.Header().Get("ETag") == tag {
+		t.Errorf("after a data load = %d with ETag %q, want 200 with a new ETag", reloaded.Code, reloaded.Header().Get("ETag"))
+	}
+}
+
+func TestETagSkipped(t *testing.T) {
+	updated := time.Date(2025, 11, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("error responses aren't tagged", func(t *testing.T) {
+		var updateErr error
+		router, _ := newETagRouter(&updated, &updateErr, http.StatusInternalServerError)
+		w := getLeaders(router, "/leaders", "")
+		if w.Header().Get("ETag") != "" || w.Header().Get("Cache-Control") != "" {
+			t.Errorf("500 response headers ETag=%q Cache-Control=%q, want none", w.Header().Get("ETag"), w.Header().Get("Cache-Control"))
+		}
+	})
+
+	t.Run("unreadable load state serves normally", func(t *testing.T) {
+		updateErr := errors.New("server selection timeout")
+		router, calls := newETagRouter(&updated, &updateErr, http.StatusOK)
+		w := getLeaders(router, "/leaders", "*")
+		if w.Code != http.StatusOK || w.Header().Get("ETag") != "" || *calls != 1 {
+			t.Errorf("response = %d with ETag %q after %d calls, want an untagged 200", w.Code, w.Header().Get("ETag"), *calls)
+		}
+	})
+
+	t.Run("non-GET requests pass through", func(t *testing.T) {
+		var updateErr error
+		router, calls := newETagRouter(&updated, &updateErr, http.StatusOK)
+		req := httptest.NewRequest(http.MethodPost, "/leaders", nil)
+		req.Header.Set("If-None-Match", "*")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || w.Header().Get("ETag") != "" || *calls != 1 {
+			t.Errorf("POST = %d with ETag %q after %d calls, want an untagged 200", w.Code, w.Header().Get("ETag"), *calls)
+		}
+	})
+}
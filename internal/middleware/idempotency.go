@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// IdempotencyHeader is the request header clients set to make a submission safe to retry
+const IdempotencyHeader = "Idempotency-Key"
+
+// idempotencyRecord is a claimed Idempotency-Key and, once the handler has finished, the
+// response it produced. Records are removed by the TTL index on expires_at.
+type idempotencyRecord struct {
+	Key         string    `bson:"key"` // User, method, route and header value
+	Completed   bool      `bson:"completed"`
+	Status      int       `bson:"status,omitempty"`
+	ContentType string    `bson:"content_type,omitempty"`
+	Body        []byte    `bson:"body,omitempty"`
+	CreatedAt   time.Time `bson:"created_at"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+}
+
+// recordingWriter keeps a copy of the response body so it can be replayed
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotencyStore claims keys and keeps their responses. Claim reports false when the key
+// is already held.
+type idempotencyStore interface {
+	Claim(ctx context.Context, record idempotencyRecord) (bool, error)
+	Find(ctx context.Context, key string) (*idempotencyRecord, error)
+	Complete(ctx context.Context, key string, status int, contentType string, body []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+// mongoIdempotencyStore keeps keys in idempotency_keys, relying on its unique key index
+// to reject a second claim
+type mongoIdempotencyStore struct {
+	collection *mongo.Collection
+}
+
+func (s mongoIdempotencyStore) Claim(ctx context.Context, record idempotencyRecord) (bool, error) {
+	_, err := s.collection.InsertOne(ctx, record)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s mongoIdempotencyStore) Find(ctx context.Context, key string) (*idempotencyRecord, error) {
+	var record idempotencyRecord
+	if err := s.collection.FindOne(ctx, bson.M{"key": key}).Decode(&record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s mongoIdempotencyStore) Complete(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	_, err := s.collection.UpdateOne(ctx, bson.M{"key": key}, bson.M{"$set": bson.M{
+		"completed":    true,
+		"status":       status,
+		"content_type": contentType,
+		"body":         body,
+	}})
+	return err
+}
+
+func (s mongoIdempotencyStore) Release(ctx context.Context, key string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"key": key})
+	return err
+}
+
+// Idempotency makes the routes it guards safe to submit twice. The first request carrying an
+// Idempotency-Key claims the key in idempotency_keys and its response is stored for ttl; a
+// repeat with the same key from the same user gets that response back (with an
+// Idempotent-Replayed header) without running the handler again, or 409 while the first
+// request is still in flight. Server errors and panics release the key so the client can
+// retry. Requests without the header are not affected. Deduplication needs the unique index
+// on idempotency_keys.key from mongodb.CreateIndexes.
+func Idempotency(db *mongo.Database, ttl time.Duration) gin.HandlerFunc {
+	return idempotency(mongoIdempotencyStore{collection: db.Collection("idempotency_keys")}, ttl)
+}
+
+func idempotency(store idempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(IdempotencyHeader)
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		user := c.ClientIP()
+		if userID, ok := c.Get("user_id"); ok {
+			if id, ok := userID.(string); ok && id != "" {
+				user = id
+			}
+		}
+		key := fmt.Sprintf("%s %s %s %s", user, c.Request.Method, c.FullPath(), header)
+
+		now := time.Now()
+		claimed, err := store.Claim(c.Request.Context(), idempotencyRecord{
+			Key:       key,
+			CreatedAt: now,
+			ExpiresAt: now.Add(ttl),
+		})
+		if err != nil {
+			// Don't block submissions on the bookkeeping; the request just isn't deduplicated
+			log.Printf("Idempotency key claim error: %v", err)
+			c.Next()
+			return
+		}
+		if !claimed {
+			existing, err := store.Find(c.Request.Context(), key)
+			if err != nil || !existing.Completed {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is already in progress"})
+				return
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(existing.Status, existing.ContentType, existing.Body)
+			c.Abort()
+			return
+		}
+
+		// Release the key unless the response is saved, including when the handler panics
+		saved := false
+		defer func() {
+			if saved {
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := store.Release(ctx, key); err != nil {
+				log.Printf("Idempotency key release error: %v", err)
+			}
+		}()
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := store.Complete(ctx, key, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			log.Printf("Idempotency key save error: %v", err)
+			return
+		}
+		saved = true
+	}
+}
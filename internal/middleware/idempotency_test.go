@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryIdempotencyStore is an idempotencyStore that enforces unique keys in memory
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]idempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]idempotencyRecord)}
+}
+
+func (s *memoryIdempotencyStore) Claim(ctx context.Context, record idempotencyRecord) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.records[record.Key]; ok {
+		return false, nil
+	}
+	s.records[record.Key] = record
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) Find(ctx context.Context, key string) (*idempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &record, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.records[key]
+	record.Completed, record.Status, record.ContentType, record.Body = true, status, contentType, body
+	s.records[key] = record
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// newIdempotentRouter serves POST /trades through the idempotency middleware, running
+// handler for each call that reaches it
+func newIdempotentRouter(store idempotencyStore, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Recovery())
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-Test-User"))
+		c.Next()
+	})
+	router.POST("/trades", idempotency(store, time.Hour), handler)
+	return router
+}
+
+func postTrade(router *gin.Engine, user, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/trades", nil)
+	req.Header.Set("X-Test-User", user)
+	if key != "" {
+		req.Header.Set(IdempotencyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysRepeatedKey(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(newMemoryIdempotencyStore(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"trade": calls})
+	})
+
+	first := postTrade(router, "u1", "abc")
+	second := postTrade(router, "u1", "abc")
+
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("replay is missing the Idempotent-Replayed header")
+	}
+	if got := second.Header().Get("Content-Type"); got != first.Header().Get("Content-Type") {
+		t.Errorf("replay Content-Type = %q, want %q", got, first.Header().Get("Content-Type"))
+	}
+
+	// Another user, another key or no key at all runs the handler
+	postTrade(router, "u2", "abc")
+	postTrade(router, "u1", "def")
+	postTrade(router, "u1", "")
+	postTrade(router, "u1", "")
+	if calls != 5 {
+		t.Errorf("handler ran %d times, want 5", calls)
+	}
+}
+
+func TestIdempotencyInFlightConflict(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	var repeat *httptest.ResponseRecorder
+	var router *gin.Engine
+	router = newIdempotentRouter(store, func(c *gin.Context) {
+		// The same submission arrives while this one is still running
+		if repeat == nil {
+			repeat = postTrade(router, "u1", "abc")
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	postTrade(router, "u1", "abc")
+	if repeat == nil || repeat.Code != http.StatusConflict {
+		t.Fatalf("in-flight repeat = %v, want %d", repeat, http.StatusConflict)
+	}
+}
+
+func TestIdempotencyReleasesKeyAfterFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		failure gin.HandlerFunc
+	}{
+		{"server error", func(c *gin.Context) { c.JSON(http.StatusInternalServerError, gin.H{"error": "boom"}) }},
+		{"panic", func(c *gin.Context) { panic("boom") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryIdempotencyStore()
+			calls := 0
+			router := newIdempotentRouter(store, func(c *gin.Context) {
+				calls++
+				if calls == 1 {
+					tt.failure(c)
+					return
+				}
+				c.JSON(http.StatusCreated, gin.H{"attempt": strconv.Itoa(calls)})
+			})
+
+			if rec := postTrade(router, "u1", "abc"); rec.Code != http.StatusInternalServerError {
+				t.Fatalf("first attempt status = %d, want %d", rec.Code, http.StatusInternalServerError)
+			}
+			retry := postTrade(router, "u1", "abc")
+			if retry.Code != http.StatusCreated || calls != 2 {
+				t.Errorf("retry = %d after %d calls, want %d after 2", retry.Code, calls, http.StatusCreated)
+			}
+			if retry.Header().Get("Idempotent-Replayed") != "" {
+				t.Error("retry after a failure was replayed")
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	prev := requestLog
+	requestLog = slog.New(slog.NewJSONHandler(&buf, nil))
+	t.Cleanup(func() { requestLog = prev })
+
+	var seenID string
+	router := gin.New()
+	router.Use(RequestLogger())
+	router.GET("/players/:id", func(c *gin.Context) {
+		seenID = c.GetString("request_id")
+		c.Set("user_id", "user-1")
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+
+	tests := []struct {
+		name   string
+		sentID string
+	}{
+		{"generated ID", ""},
+		{"caller's ID is reused", "trace-abc-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/players/00-0036355?season=2025", nil)
+			if tt.sentID != "" {
+				req.Header.Set(RequestIDHeader, tt.sentID)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			if tt.sentID != "" && id != tt.sentID {
+				t.Errorf("%s = %q, want the caller's %q", RequestIDHeader, id, tt.sentID)
+			}
+			if tt.sentID == "" && !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(id) {
+				t.Errorf("%s = %q, want a 32-character hex ID", RequestIDHeader, id)
+			}
+			if seenID != id {
+				t.Errorf("handler saw request_id %q, want %q", seenID, id)
+			}
+
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+			if len(lines) != 1 {
+				t.Fatalf("logged %d lines, want 1:\n%s", len(lines), buf.String())
+			}
+			var entry map[string]any
+			if err := json.Unmarshal(lines[0], &entry); err != nil {
+				t.Fatalf("log line is not JSON: %v\n%s", err, lines[0])
+			}
+
+			want := map[string]any{
+				"msg":        "request",
+				"request_id": id,
+				"method":     "GET",
+				"path":       "/players/00-0036355",
+				"status":     float64(http.StatusNotFound),
+				"bytes":      float64(w.Body.Len()),
+				"client_ip":  "192.0.2.1",
+				"user_id":    "user-1",
+			}
+			for k, v := range want {
+				if entry[k] != v {
+					t.Errorf("log %s = %v, want %v", k, entry[k], v)
+				}
+			}
+			if latency, ok := entry["latency_ms"].(float64); !ok || latency < 0 {
+				t.Errorf("log latency_ms = %v, want a non-negative number", entry["latency_ms"])
+			}
+		})
+	}
+}
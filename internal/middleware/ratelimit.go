@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bucket is a token bucket for one user on one route
+type bucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	lastSeen time.Time
+}
+
+// take refills the bucket for the time elapsed since it was last used and consumes one
+// token. If the bucket is empty it returns how long until a token is available.
+func (b *bucket) take(now time.Time, capacity, perSecond float64) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.lastSeen).Seconds()*perSecond)
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := (1 - b.tokens) / perSecond
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// RateLimit allows each user up to limit requests per window on every route it guards,
+// refilling continuously (a token bucket). Users are identified by the user_id set by
+// AuthRequired, falling back to client IP. Requests over the limit get 429 with a
+// Retry-After header. Buckets idle for a full window are dropped periodically. A limit of
+// zero or less disables rate limiting.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	capacity := float64(limit)
+	perSecond := capacity / window.Seconds()
+
+	var buckets sync.Map
+	go func() {
+		ticker := time.NewTicker(window)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			buckets.Range(func(key, value interface{}) bool {
+				b := value.(*bucket)
+				b.mu.Lock()
+				idle := now.Sub(b.lastSeen) >= window
+				b.mu.Unlock()
+				if idle {
+					buckets.Delete(key)
+				}
+				return true
+			})
+		}
+	}()
+
+	return func(c *gin.Context) {
+		user := c.ClientIP()
+		if userID, ok := c.Get("user_id"); ok {
+			if id, ok := userID.(string); ok && id != "" {
+				user = id
+			}
+		}
+		key := user + " " + c.Request.Method + " " + c.FullPath()
+
+		now := time.Now()
+		value, _ := buckets.LoadOrStore(key, &bucket{tokens: capacity, lastSeen: now})
+		allowed, wait := value.(*bucket).take(now, capacity, perSecond)
+		if !allowed {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Rate limit exceeded",
+				"retry_after": retryAfter,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newRateLimitRouter serves /insights/a, /insights/b and /chatbot behind separate
+// limiters, as the API does for each AI route group
+func newRateLimitRouter(insightsLimit, chatbotLimit int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	insights := router.Group("/insights", RateLimit(insightsLimit, time.Minute))
+	insights.GET("/a", ok)
+	insights.GET("/b", ok)
+	router.GET("/chatbot", RateLimit(chatbotLimit, time.Minute), ok)
+	return router
+}
+
+func getFrom(router *gin.Engine, path, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitBurst(t *testing.T) {
+	router := newRateLimitRouter(3, 1)
+
+	for i := 0; i < 3; i++ {
+		if w := getFrom(router, "/insights/a", "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("request %d = %d, want %d", i+1, w.Code, http.StatusOK)
+		}
+	}
+
+	w := getFrom(router, "/insights/a", "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the burst = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 || retryAfter > 20 {
+		t.Errorf("Retry-After = %q, want whole seconds until the next token (<= 20)", w.Header().Get("Retry-After"))
+	}
+	var body struct {
+		Error      string `json:"error"`
+		RetryAfter int    `json:"retry_after"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error == "" || body.RetryAfter != retryAfter {
+		t.Errorf("body = %+v, want an error and retry_after = %d", body, retryAfter)
+	}
+
+	// Buckets are per user and per route, and each group has its own limiter
+	if w := getFrom(router, "/insights/a", "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("another user = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := getFrom(router, "/insights/b", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("another route = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := getFrom(router, "/chatbot", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("chatbot after the insights burst = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := getFrom(router, "/chatbot", "10.0.0.1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("chatbot over its own limit = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+}
+
+func TestBucketRefills(t *testing.T) {
+	start := time.Now()
+	b := &bucket{tokens: 1, lastSeen: start}
+	perSecond := 1.0 / 6 // 10 per minute
+
+	if ok, _ := b.take(start, 10, perSecond); !ok {
+		t.Fatal("take() with a token left = false, want true")
+	}
+	ok, wait := b.take(start.Add(time.Second), 10, perSecond)
+	if ok || wait != 5*time.Second {
+		t.Errorf("take() on an empty bucket = %v, %v, want false, 5s", ok, wait)
+	}
+	if ok, _ := b.take(start.Add(7*time.Second), 10, perSecond); !ok {
+		t.Error("take() after a refill interval = false, want true")
+	}
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	for _, limit := range []int{0, -1} {
+		router := newRateLimitRouter(limit, limit)
+		for i := 0; i < 20; i++ {
+			w := getFrom(router, "/insights/a", "10.0.0.1")
+			if w.Code != http.StatusOK || w.Header().Get("Retry-After") != "" {
+				t.Fatalf("limit %d: request %d = %d (Retry-After %q), want %d", limit, i+1, w.Code, w.Header().Get("Retry-After"), http.StatusOK)
+			}
+		}
+	}
+}
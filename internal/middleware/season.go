@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/gin-gonic/gin"
+)
+
+// Headers that replace the current season and week for one request
+const (
+	OverrideSeasonHeader = "X-Override-Season"
+	OverrideWeekHeader   = "X-Override-Week"
+)
+
+// SeasonOverride lets a request pin season.Current with the X-Override-Season and
+// X-Override-Week headers. The headers are ignored unless enabled, which should only be
+// true in debug or QA environments.
+func SeasonOverride(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		seasonHeader := c.GetHeader(OverrideSeasonHeader)
+		weekHeader := c.GetHeader(OverrideWeekHeader)
+		if seasonHeader == "" && weekHeader == "" {
+			c.Next()
+			return
+		}
+
+		var overrideSeason, overrideWeek int
+		if seasonHeader != "" {
+			s, err := strconv.Atoi(seasonHeader)
+			if err != nil || s < 1999 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": OverrideSeasonHeader + " must be a season year"})
+				return
+			}
+			overrideSeason = s
+		}
+		if weekHeader != "" {
+			w, err := strconv.Atoi(weekHeader)
+			if err != nil || w < 1 || w > 22 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": OverrideWeekHeader + " must be a week from 1 to 22"})
+				return
+			}
+			overrideWeek = w
+		}
+
+		c.Request = c.Request.WithContext(season.WithOverride(c.Request.Context(), overrideSeason, overrideWeek))
+		c.Next()
+	}
+}
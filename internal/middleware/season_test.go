@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/gin-gonic/gin"
+)
+
+func TestSeasonOverride(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name                     string
+		enabled                  bool
+		seasonHeader, weekHeader string
+		wantStatus               int
+		wantSeason, wantWeek     int
+	}{
+		{"disabled ignores headers", false, "2023", "5", http.StatusOK, season.DefaultSeason, season.DefaultWeek},
+		{"disabled ignores invalid headers", false, "soon", "99", http.StatusOK, season.DefaultSeason, season.DefaultWeek},
+		{"enabled without headers", true, "", "", http.StatusOK, season.DefaultSeason, season.DefaultWeek},
+		{"enabled season and week", true, "2023", "5", http.StatusOK, 2023, 5},
+		{"enabled week only", true, "", "5", http.StatusOK, season.DefaultSeason, 5},
+		{"enabled invalid season", true, "soon", "", http.StatusBadRequest, 0, 0},
+		{"enabled week out of range", true, "", "23", http.StatusBadRequest, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(SeasonOverride(tt.enabled))
+			router.GET("/", func(c *gin.Context) {
+				s, w := season.Current(c.Request.Context())
+				c.String(http.StatusOK, "%d/%d", s, w)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.seasonHeader != "" {
+				req.Header.Set(OverrideSeasonHeader, tt.seasonHeader)
+			}
+			if tt.weekHeader != "" {
+				req.Header.Set(OverrideWeekHeader, tt.weekHeader)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if want := fmt.Sprintf("%d/%d", tt.wantSeason, tt.wantWeek); rec.Body.String() != want {
+				t.Errorf("season/week = %s, want %s", rec.Body.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/httputil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// slow waits on the request context like a database or AI call would, then reports
+	// the failure through RespondError
+	slow := func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			httputil.RespondError(c, http.StatusInternalServerError, httputil.CodeInternal, "Failed to fetch players")
+		case <-time.After(time.Second):
+			c.JSON(http.StatusOK, gin.H{"players": []string{}})
+		}
+	}
+	silent := func(c *gin.Context) { <-c.Request.Context().Done() }
+	fast := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) }
+
+	router := gin.New()
+	router.Use(Timeout(20 * time.Millisecond))
+	router.GET("/slow", slow)
+	router.GET("/silent", silent)
+	router.GET("/fast", fast)
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"/slow", http.StatusServiceUnavailable, `{"error":{"code":"` + httputil.CodeTimeout + `","message":"request timed out"}}`},
+		{"/silent", http.StatusServiceUnavailable, `{"error":"Request timed out"}`},
+		{"/fast", http.StatusOK, `{"ok":true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if !json.Valid(w.Body.Bytes()) || w.Body.String() != tt.wantBody {
+				t.Errorf("body = %s, want %s", w.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestTimeoutDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, d := range []time.Duration{0, -time.Second} {
+		router := gin.New()
+		router.Use(Timeout(d))
+		router.GET("/", func(c *gin.Context) {
+			if _, ok := c.Request.Context().Deadline(); ok {
+				t.Errorf("Timeout(%v) set a deadline", d)
+			}
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("Timeout(%v) status = %d, want %d", d, w.Code, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Alert types
+const (
+	AlertInjuryStatus = "injury_status"
+)
+
+// RosterAlert records a change on a user's ESPN roster detected by the alerts poller
+type RosterAlert struct {
+	ID     bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID bson.ObjectID `json:"user_id" bson:"user_id"`
+	Type   string        `json:"type" bson:"type"`
+
+	PlayerKey  string `json:"player_key" bson:"player_key"`
+	PlayerName string `json:"player_name" bson:"player_name"`
+	Position   string `json:"position,omitempty" bson:"position,omitempty"`
+	ProTeam    string `json:"pro_team,omitempty" bson:"pro_team,omitempty"`
+	FromStatus string `json:"from_status" bson:"from_status"`
+	ToStatus   string `json:"to_status" bson:"to_status"`
+
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// RosterSnapshotPlayer is one player's state as of the last poll
+type RosterSnapshotPlayer struct {
+	Name         string `bson:"name"`
+	Position     string `bson:"position,omitempty"`
+	ProTeam      string `bson:"pro_team,omitempty"`
+	InjuryStatus string `bson:"injury_status"`
+}
+
+// RosterSnapshot is the last polled ESPN roster for a user, keyed by ESPN player ID
+// (or name when ESPN doesn't return one)
+type RosterSnapshot struct {
+	UserID    bson.ObjectID                   `bson:"user_id"`
+	Players   map[string]RosterSnapshotPlayer `bson:"players"`
+	UpdatedAt time.Time                       `bson:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Chat message roles
+const (
+	ChatRoleUser  = "user"
+	ChatRoleModel = "model"
+)
+
+// ChatMessage is a single message in a user's conversation with the AI chatbot. A turn is
+// stored as two messages: the user's question followed by the model's response.
+type ChatMessage struct {
+	ID     bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID bson.ObjectID `json:"user_id" bson:"user_id"`
+
+	Role    string `json:"role" bson:"role"` // "user" or "model"
+	Content string `json:"content" bson:"content"`
+
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+}
@@ -0,0 +1,115 @@
+package models
+
+// ESPNLeague is a league read from the ESPN Fantasy API with its settings and teams
+type ESPNLeague struct {
+	Settings ESPNLeagueSettings `json:"settings"`
+	Teams    []ESPNTeam         `json:"teams"`
+}
+
+// ESPNLeagueSettings is the league configuration from ESPN's mSettings view
+type ESPNLeagueSettings struct {
+	LeagueID           string         `json:"league_id"`
+	SeasonYear         int            `json:"season_year"`
+	Name               string         `json:"name"`
+	Size               int            `json:"size"`
+	CurrentWeek        int            `json:"current_week"`
+	ScoringPeriodID    int            `json:"scoring_period_id"`
+	FinalScoringPeriod int            `json:"final_scoring_period"`
+	ScoringType        string         `json:"scoring_type"`
+	PlayoffTeamCount   int            `json:"playoff_team_count"`
+	TradeDeadline      int            `json:"trade_deadline"` // Unix milliseconds
+	VetoVotesRequired  int            `json:"veto_votes_required"`
+	WaiverProcessHour  *int           `json:"waiver_process_hour,omitempty"`
+	RegSeasonCount     int            `json:"reg_season_count"`
+	LineupSlotCounts   map[int]int    `json:"lineup_slot_counts"` // ESPN lineup slot ID -> starters
+	Lineup             LeagueSettings `json:"lineup"`             // LineupSlotCounts in optimizer slots
+}
+
+// ESPNTeam is one fantasy team in an ESPN league
+type ESPNTeam struct {
+	TeamID        int          `json:"team_id"`
+	Abbrev        string       `json:"abbrev"`
+	TeamName      string       `json:"team_name"`
+	Owner         string       `json:"owner"`
+	Wins          int          `json:"wins"`
+	Losses        int          `json:"losses"`
+	Ties          int          `json:"ties"`
+	PointsFor     float64      `json:"points_for"`
+	PointsAgainst float64      `json:"points_against"`
+	Standing      int          `json:"standing"`
+	DivisionID    int          `json:"division_id"`
+	LogoURL       string       `json:"logo_url,omitempty"`
+	Roster        []ESPNPlayer `json:"roster,omitempty"`
+}
+
+// ESPNPlayer is a player on an ESPN fantasy roster
+type ESPNPlayer struct {
+	PlayerID       int     `json:"player_id"`
+	Name           string  `json:"name"`
+	Position       string  `json:"position"`
+	Team           string  `json:"team"`
+	SlotPosition   string  `json:"slot_position"`
+	InjuryStatus   string  `json:"injury_status,omitempty"`
+	PercentOwned   float64 `json:"percent_owned"`
+	PercentStarted float64 `json:"percent_started"`
+}
+
+// ESPNMatchup is one week's head-to-head matchup with ESPN's projected final scores
+type ESPNMatchup struct {
+	Week               int     `json:"week"`
+	HomeTeamID         int     `json:"home_team_id"`
+	AwayTeamID         int     `json:"away_team_id"`
+	HomeScore          float64 `json:"home_score"`
+	AwayScore          float64 `json:"away_score"`
+	HomeProjected      float64 `json:"home_projected"`
+	AwayProjected      float64 `json:"away_projected"`
+	Winner             string  `json:"winner"` // home, away or tie on the current score
+	HomeWinProbability float64 `json:"home_win_probability"`
+	AwayWinProbability float64 `json:"away_win_probability"`
+}
+
+// ESPNFreeAgent is an unrostered player in an ESPN league
+type ESPNFreeAgent struct {
+	PlayerID     int     `json:"player_id"`
+	Name         string  `json:"name"`
+	Position     string  `json:"position"`
+	Team         string  `json:"team"`
+	PercentOwned float64 `json:"percent_owned"`
+	InjuryStatus string  `json:"injury_status,omitempty"`
+}
+
+// ESPNBoxScore is a matchup's score with each side's lineup
+type ESPNBoxScore struct {
+	Week       int             `json:"week"`
+	HomeTeam   ESPNBoxTeam     `json:"home_team"`
+	AwayTeam   ESPNBoxTeam     `json:"away_team"`
+	HomeLineup []ESPNPlayerBox `json:"home_lineup"`
+	AwayLineup []ESPNPlayerBox `json:"away_lineup"`
+}
+
+// ESPNBoxTeam is one side of a box score
+type ESPNBoxTeam struct {
+	TeamID   int     `json:"team_id"`
+	TeamName string  `json:"team_name"`
+	Score    float64 `json:"score"`
+}
+
+// ESPNPlayerBox is a player's fantasy points and raw stats for the week
+type ESPNPlayerBox struct {
+	PlayerID     int                `json:"player_id"`
+	Name         string             `json:"name"`
+	Position     string             `json:"position"`
+	Team         string             `json:"team"`
+	SlotPosition string             `json:"slot_position"`
+	Points       float64            `json:"points"`
+	Stats        map[string]float64 `json:"stats"`
+}
+
+// ESPNActivity is a league transaction or message
+type ESPNActivity struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Date        int64  `json:"date"` // Unix milliseconds
+	TeamID      int    `json:"team_id,omitempty"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// InjuryReport is a player's official injury report entry for one week
+// Loaded from NFLverse injuries_{season}.parquet files
+type InjuryReport struct {
+	ID       bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	NFLID    string        `json:"nfl_id" bson:"nfl_id"` // gsis_id
+	Season   int           `json:"season" bson:"season"`
+	Week     int           `json:"week" bson:"week"`
+	GameType string        `json:"game_type" bson:"game_type"` // REG, WC, DIV, CON, SB
+
+	PlayerName string `json:"player_name" bson:"player_name"`
+	Team       string `json:"team" bson:"team"`
+	Position   string `json:"position" bson:"position"`
+
+	ReportStatus    string `json:"report_status" bson:"report_status"`     // Out, Doubtful, Questionable
+	PracticeStatus  string `json:"practice_status" bson:"practice_status"` // Did Not Participate, Limited, Full
+	Injury          string `json:"injury" bson:"injury"`                   // Primary body part, e.g. Hamstring
+	SecondaryInjury string `json:"secondary_injury,omitempty" bson:"secondary_injury,omitempty"`
+
+	DateModified time.Time `json:"date_modified" bson:"date_modified"`
+	UpdatedAt    time.Time `json:"updated_at" bson:"updated_at"`
+}
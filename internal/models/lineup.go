@@ -6,15 +6,94 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+// Lineup slots
+const (
+	SlotQB        = "QB"
+	SlotRB        = "RB"
+	SlotWR        = "WR"
+	SlotTE        = "TE"
+	SlotFlex      = "FLEX"
+	SlotSuperflex = "SUPERFLEX" // QB-eligible flex (ESPN "OP")
+	SlotK         = "K"
+	SlotDST       = "DST"
+)
+
+// DefaultLineupSlots is the number of starters per slot in a standard league
+var DefaultLineupSlots = map[string]int{
+	SlotQB:   1,
+	SlotRB:   2,
+	SlotWR:   3,
+	SlotTE:   1,
+	SlotFlex: 1,
+	SlotK:    1,
+	SlotDST:  1,
+}
+
+// SlotEligibility lists the player positions that may fill each slot
+var SlotEligibility = map[string][]string{
+	SlotQB:        {"QB"},
+	SlotRB:        {"RB", "FB"},
+	SlotWR:        {"WR"},
+	SlotTE:        {"TE"},
+	SlotFlex:      {"RB", "FB", "WR", "TE"},
+	SlotSuperflex: {"QB", "RB", "FB", "WR", "TE"},
+	SlotK:         {"K"},
+	SlotDST:       {"DST", "DEF"},
+}
+
+// LeagueSettings is a league's starting lineup composition
+type LeagueSettings struct {
+	LineupSlots map[string]int `json:"lineup_slots"` // Starters per slot
+}
+
+// DefaultLeagueSettings is a standard single-QB league
+func DefaultLeagueSettings() LeagueSettings {
+	return LeagueSettings{LineupSlots: copySlots(DefaultLineupSlots)}
+}
+
+// SuperflexLeagueSettings is a standard league with one added QB-eligible flex slot
+func SuperflexLeagueSettings() LeagueSettings {
+	slots := copySlots(DefaultLineupSlots)
+	slots[SlotSuperflex] = 1
+	return LeagueSettings{LineupSlots: slots}
+}
+
+// StartingQBs is the most QBs the league can start: QB slots plus superflex slots
+func (s LeagueSettings) StartingQBs() int {
+	return s.LineupSlots[SlotQB] + s.LineupSlots[SlotSuperflex]
+}
+
+// IsSuperflex reports whether a second QB can start, through a superflex or a second QB slot
+func (s LeagueSettings) IsSuperflex() bool {
+	return s.StartingQBs() >= 2
+}
+
+func copySlots(slots map[string]int) map[string]int {
+	copied := make(map[string]int, len(slots))
+	for slot, count := range slots {
+		copied[slot] = count
+	}
+	return copied
+}
+
+// LineupSlot assigns one player to a starting slot
+type LineupSlot struct {
+	Slot     string `json:"slot" bson:"slot"`           // QB, RB, WR, TE, FLEX, SUPERFLEX, K, DST
+	PlayerID string `json:"player_id" bson:"player_id"` // nfl_id, or team abbreviation for DST
+}
+
 type FantasyLineup struct {
 	ID     bson.ObjectID `json:"id" bson:"_id,omitempty"`
 	UserID bson.ObjectID `json:"user_id" bson:"user_id"`
+	Name   string        `json:"name" bson:"name"`
 
 	Week   int `json:"week" bson:"week"`
 	Season int `json:"season" bson:"season"`
 
-	// Positions map: QB, RB1, RB2, WR1, WR2, WR3, TE, FLEX, K, DEF
-	Positions map[string]string `json:"positions" bson:"positions"` // position -> player_id
+	Slots []LineupSlot `json:"slots" bson:"slots"`
+
+	// LineupSlots is the composition of the lineup's league; empty means a standard league
+	LineupSlots map[string]int `json:"lineup_slots,omitempty" bson:"lineup_slots,omitempty"`
 
 	ProjectedPoints float64 `json:"projected_points" bson:"projected_points"`
 	ActualPoints    float64 `json:"actual_points" bson:"actual_points"`
@@ -22,4 +101,3 @@ type FantasyLineup struct {
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
-
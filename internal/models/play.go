@@ -24,6 +24,11 @@ type Play struct {
 	GameSeconds    int    `json:"game_seconds" bson:"game_seconds"`
 	Description    string `json:"description" bson:"description"`
 	PlayType       string `json:"play_type" bson:"play_type"` // pass, run, punt, kickoff, etc.
+	PassLocation   string `json:"pass_location,omitempty" bson:"pass_location,omitempty"` // left, middle, right
+	RunLocation    string `json:"run_location,omitempty" bson:"run_location,omitempty"`   // left, middle, right
+	QBScramble     bool   `json:"qb_scramble" bson:"qb_scramble"`
+	Penalty        bool   `json:"penalty" bson:"penalty"`
+	TwoPointAttempt bool  `json:"two_point_attempt" bson:"two_point_attempt"`
 	
 	// Team data
 	PossessionTeam string `json:"possession_team" bson:"possession_team"`
@@ -41,6 +46,7 @@ type Play struct {
 	Interception  bool    `json:"interception" bson:"interception"`
 	Fumble        bool    `json:"fumble" bson:"fumble"`
 	Sack          bool    `json:"sack" bson:"sack"`
+	CompletePass  bool    `json:"complete_pass" bson:"complete_pass"`
 	
 	// Advanced metrics from NFLverse
 	EPA           float64 `json:"epa" bson:"epa"`            // Expected Points Added
@@ -48,6 +54,8 @@ type Play struct {
 	SuccessPlay   bool    `json:"success_play" bson:"success_play"`
 	AirYards      int     `json:"air_yards" bson:"air_yards"`
 	YardsAfterCatch int   `json:"yards_after_catch" bson:"yards_after_catch"`
+	CPOE          float64 `json:"cpoe" bson:"cpoe"`   // Completion % over expected (pass attempts only)
+	XPass         float64 `json:"xpass" bson:"xpass"` // Pre-snap probability of a dropback
 	
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 }
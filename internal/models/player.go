@@ -16,6 +16,15 @@ type Player struct {
 	Team     string        `json:"team" bson:"team"` // Current team for this season
 	Position string        `json:"position" bson:"position"`
 
+	// Bio from the roster file; omitted when the season's file lacks the column
+	JerseyNumber int       `json:"jersey_number,omitempty" bson:"jersey_number,omitempty"`
+	Height       int       `json:"height,omitempty" bson:"height,omitempty"` // Inches
+	Weight       int       `json:"weight,omitempty" bson:"weight,omitempty"` // Pounds
+	College      string    `json:"college,omitempty" bson:"college,omitempty"`
+	BirthDate    time.Time `json:"birth_date,omitempty" bson:"birth_date,omitempty"`
+	YearsExp     int       `json:"years_exp" bson:"years_exp,omitempty"`
+	HeadshotURL  string    `json:"headshot_url,omitempty" bson:"headshot_url,omitempty"`
+
 	// Injury status from weekly rosters
 	Status                string `json:"status" bson:"status"`                                   // ACT or INA (injured)
 	StatusDescriptionAbbr string `json:"status_description_abbr" bson:"status_description_abbr"` // R01 (R/Injured), P02 (Prac Sq.; Inj), etc.
@@ -24,6 +33,47 @@ type Player struct {
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
 
+// PlayerTeamStint is a run of consecutive weeks a player spent on one team, built from
+// weekly rosters so mid-season trades and signings are kept
+type PlayerTeamStint struct {
+	ID        bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	NFLID     string        `json:"nfl_id" bson:"nfl_id"`
+	Season    int           `json:"season" bson:"season"`
+	Team      string        `json:"team" bson:"team"`
+	FromWeek  int           `json:"from_week" bson:"from_week"`
+	ToWeek    int           `json:"to_week" bson:"to_week"` // Inclusive
+	UpdatedAt time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+// PlayerWeeklyStatus is a player's roster status for one week, kept for every week since
+// the players collection only holds the latest
+type PlayerWeeklyStatus struct {
+	ID                    bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	NFLID                 string        `json:"nfl_id" bson:"nfl_id"`
+	Season                int           `json:"season" bson:"season"`
+	Week                  int           `json:"week" bson:"week"`
+	Team                  string        `json:"team" bson:"team"`
+	Status                string        `json:"status" bson:"status"`                                   // ACT or INA
+	StatusDescriptionAbbr string        `json:"status_description_abbr" bson:"status_description_abbr"` // R01, P02, etc.
+	UpdatedAt             time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+// Season types of player_stats entries
+const (
+	SeasonTypeRegular  = "REG"
+	SeasonTypePost     = "POST"
+	SeasonTypeCombined = "REGPOST"
+)
+
+// IsValidSeasonType reports whether seasonType is REG, POST or REGPOST
+func IsValidSeasonType(seasonType string) bool {
+	switch seasonType {
+	case SeasonTypeRegular, SeasonTypePost, SeasonTypeCombined:
+		return true
+	}
+	return false
+}
+
 // PlayerStats represents season-level stats for a player
 // This would be loaded from player_stats Parquet files
 type PlayerStats struct {
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// QBRStat represents ESPN's Total QBR for a quarterback
+// Loaded from NFLverse espn_data qbr_week_level / qbr_season_level files.
+// ESPN identifies players by its own id, so records are matched to players by name.
+type QBRStat struct {
+	ID         bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	ESPNID     string        `json:"espn_id" bson:"espn_id"`
+	PlayerName string        `json:"player_name" bson:"player_name"`
+	Team       string        `json:"team" bson:"team"`
+	Season     int           `json:"season" bson:"season"`
+	SeasonType string        `json:"season_type" bson:"season_type"` // Regular, Playoffs
+	Week       int           `json:"week" bson:"week"`               // 0 for season totals
+
+	QBRTotal    float64 `json:"qbr_total" bson:"qbr_total"` // 0-100, adjusted for opponent
+	QBRRaw      float64 `json:"qbr_raw" bson:"qbr_raw"`
+	PointsAdded float64 `json:"points_added" bson:"points_added"`
+	QBPlays     int     `json:"qb_plays" bson:"qb_plays"`
+
+	// EPA splits (ESPN's expected points model)
+	EPATotal   float64 `json:"epa_total" bson:"epa_total"`
+	PassEPA    float64 `json:"pass_epa" bson:"pass_epa"`
+	RunEPA     float64 `json:"run_epa" bson:"run_epa"`
+	SackEPA    float64 `json:"sack_epa" bson:"sack_epa"`
+	PenaltyEPA float64 `json:"penalty_epa" bson:"penalty_epa"`
+
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
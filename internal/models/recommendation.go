@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Recommendation kinds
+const (
+	RecommendationStartSit = "start_sit"
+	RecommendationWaiver   = "waiver"
+)
+
+// RecommendedPlayer is a player named in a recommendation, as the caller identified them
+type RecommendedPlayer struct {
+	Name     string `json:"name" bson:"name"`
+	Position string `json:"position" bson:"position"`
+	Team     string `json:"team" bson:"team"`
+}
+
+// Recommendation is an AI start/sit or waiver recommendation saved so it can be scored
+// against actual fantasy points once the week's games are final. A repeated request for
+// the same players and week replaces the earlier recommendation.
+type Recommendation struct {
+	ID     bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID bson.ObjectID `json:"user_id" bson:"user_id"`
+	Kind   string        `json:"kind" bson:"kind"`
+	Season int           `json:"season" bson:"season"`
+	Week   int           `json:"week" bson:"week"`
+
+	// Start/sit: both players, with Pick the one to start. Waiver: the single player, with
+	// Action the add recommendation ("Must Add", "Strong Add", "Good Add", "Monitor", "Pass").
+	Players    []RecommendedPlayer `json:"players" bson:"players"`
+	Pick       string              `json:"pick" bson:"pick"`
+	Action     string              `json:"action,omitempty" bson:"action,omitempty"`
+	Confidence int                 `json:"confidence,omitempty" bson:"confidence,omitempty"`
+
+	Scored    bool      `json:"scored" bson:"scored"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// RecommendationOutcome records whether a recommendation paid off, scored with the PPR
+// points each player actually put up that week
+type RecommendationOutcome struct {
+	ID               bson.ObjectID      `json:"id" bson:"_id,omitempty"`
+	RecommendationID bson.ObjectID      `json:"recommendation_id" bson:"recommendation_id"`
+	UserID           bson.ObjectID      `json:"user_id" bson:"user_id"`
+	Kind             string             `json:"kind" bson:"kind"`
+	Season           int                `json:"season" bson:"season"`
+	Week             int                `json:"week" bson:"week"`
+	Pick             string             `json:"pick" bson:"pick"`
+	Points           map[string]float64 `json:"points" bson:"points"` // Actual PPR points by player name
+	Hit              bool               `json:"hit" bson:"hit"`
+	ScoredAt         time.Time          `json:"scored_at" bson:"scored_at"`
+}
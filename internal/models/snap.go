@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// SnapCount is a player's offensive snap share for one game
+// Built from NFLverse pbp_participation_{season}.parquet files
+type SnapCount struct {
+	ID     bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	NFLID  string        `json:"nfl_id" bson:"nfl_id"` // gsis_id
+	Season int           `json:"season" bson:"season"`
+	Week   int           `json:"week" bson:"week"`
+	GameID string        `json:"game_id" bson:"game_id"`
+	Team   string        `json:"team" bson:"team"`
+
+	OffenseSnaps int     `json:"offense_snaps" bson:"offense_snaps"`
+	TeamSnaps    int     `json:"team_snaps" bson:"team_snaps"`   // Team's offensive snaps in the game
+	OffensePct   float64 `json:"offense_pct" bson:"offense_pct"` // 0-100
+
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// KickerStats is a kicker's line for one week from nflverse's weekly player stats, stored in
+// kicker_weekly_stats. Field goals are bucketed the way fantasy leagues score them.
+type KickerStats struct {
+	ID       bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	NFLID    string        `json:"nfl_id" bson:"nfl_id"`
+	Season   int           `json:"season" bson:"season"`
+	Week     int           `json:"week" bson:"week"`
+	Team     string        `json:"team" bson:"team"`
+	Opponent string        `json:"opponent" bson:"opponent"`
+
+	FGMade0To39  int `json:"fg_made_0_39" bson:"fg_made_0_39"`
+	FGMade40To49 int `json:"fg_made_40_49" bson:"fg_made_40_49"`
+	FGMade50Plus int `json:"fg_made_50_plus" bson:"fg_made_50_plus"`
+	FGMissed     int `json:"fg_missed" bson:"fg_missed"`
+	PATMade      int `json:"pat_made" bson:"pat_made"`
+	PATMissed    int `json:"pat_missed" bson:"pat_missed"`
+
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// DefenseStats is a team's defense and special teams line for one week from nflverse's
+// weekly team stats, stored in defense_weekly_stats. PointsAllowed comes from the game's
+// final score.
+type DefenseStats struct {
+	ID       bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	Team     string        `json:"team" bson:"team"`
+	Season   int           `json:"season" bson:"season"`
+	Week     int           `json:"week" bson:"week"`
+	Opponent string        `json:"opponent" bson:"opponent"`
+
+	Sacks            float64 `json:"sacks" bson:"sacks"`
+	Interceptions    int     `json:"interceptions" bson:"interceptions"`
+	FumbleRecoveries int     `json:"fumble_recoveries" bson:"fumble_recoveries"`
+	Safeties         int     `json:"safeties" bson:"safeties"`
+	DefensiveTDs     int     `json:"defensive_tds" bson:"defensive_tds"`
+	SpecialTeamsTDs  int     `json:"special_teams_tds" bson:"special_teams_tds"`
+	PointsAllowed    int     `json:"points_allowed" bson:"points_allowed"`
+
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
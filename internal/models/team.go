@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Team is an NFL franchise's branding and alignment from nflverse's teams_colors_logos file,
+// stored in the teams collection. The file also carries former abbreviations (OAK, SD, STL)
+// so older seasons can still be labelled.
+type Team struct {
+	ID             bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	Abbr           string        `json:"abbr" bson:"abbr"`
+	Name           string        `json:"name" bson:"name"`
+	Nickname       string        `json:"nickname" bson:"nickname"`
+	Conference     string        `json:"conference" bson:"conference"` // AFC, NFC
+	Division       string        `json:"division" bson:"division"`     // e.g. "AFC East"
+	PrimaryColor   string        `json:"primary_color" bson:"primary_color"`
+	SecondaryColor string        `json:"secondary_color" bson:"secondary_color"`
+	LogoURL        string        `json:"logo_url" bson:"logo_url"`
+	WordmarkURL    string        `json:"wordmark_url,omitempty" bson:"wordmark_url,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TradePlayer is one player in a saved trade with the value the analyzer gave them
+type TradePlayer struct {
+	NFLID    string  `json:"nfl_id" bson:"nfl_id"`
+	Name     string  `json:"name" bson:"name"`
+	Position string  `json:"position" bson:"position"`
+	Team     string  `json:"team" bson:"team"`
+	Value    float64 `json:"value" bson:"value"`
+}
+
+// Trade is an analyzed trade saved to the user's history. Team A is the user's side.
+type Trade struct {
+	ID     bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID bson.ObjectID `json:"user_id" bson:"user_id"`
+	Season int           `json:"season" bson:"season"`
+	Week   int           `json:"week" bson:"week"`
+
+	TeamAGives []TradePlayer `json:"team_a_gives" bson:"team_a_gives"`
+	TeamAGets  []TradePlayer `json:"team_a_gets" bson:"team_a_gets"`
+	ValueDelta float64       `json:"value_delta" bson:"value_delta"` // Net value for team A
+	Verdict    string        `json:"verdict" bson:"verdict"`         // "favors_a", "favors_b", "even"
+	Rationale  string        `json:"rationale" bson:"rationale"`
+
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
@@ -24,6 +24,17 @@ type User struct {
 	Year              int           `json:"-" bson:"year,omitempty"`
 }
 
+// RefreshToken is a long-lived credential exchanged for new access tokens.
+// Only the SHA-256 hash of the token is stored; each token is single-use and
+// replaced on refresh. Expired tokens are removed by a TTL index.
+type RefreshToken struct {
+	ID        bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	TokenHash string        `json:"-" bson:"token_hash"`
+	UserID    bson.ObjectID `json:"user_id" bson:"user_id"`
+	ExpiresAt time.Time     `json:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+}
+
 // UserResponse is used for API responses (excludes password)
 type UserResponse struct {
 	ID             string    `json:"id"`
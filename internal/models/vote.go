@@ -6,15 +6,29 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+// Vote subject types
+const (
+	VoteSubjectStartSit   = "start_sit"
+	VoteSubjectTrade      = "trade"
+	VoteSubjectPlayerProp = "player_prop"
+)
+
+// VoteChoices lists the valid choices for each subject type
+var VoteChoices = map[string][]string{
+	VoteSubjectStartSit:   {"start", "sit"},
+	VoteSubjectTrade:      {"accept", "reject"},
+	VoteSubjectPlayerProp: {"over", "under", "lock", "fade"},
+}
+
+// Vote is a user's community vote on a subject. Each user has at most one vote per subject.
 type Vote struct {
 	ID     bson.ObjectID `json:"id" bson:"_id,omitempty"`
 	UserID bson.ObjectID `json:"user_id" bson:"user_id"`
 
-	PlayerID       string  `json:"player_id" bson:"player_id"`
-	PredictionType string  `json:"prediction_type" bson:"prediction_type"` // over, under, lock, fade
-	StatLine       float64 `json:"stat_line" bson:"stat_line"`
-	Week           int     `json:"week" bson:"week"`
+	SubjectType string `json:"subject_type" bson:"subject_type"` // start_sit, trade, player_prop
+	SubjectID   string `json:"subject_id" bson:"subject_id"`     // e.g. player ID or trade ID
+	Choice      string `json:"choice" bson:"choice"`
 
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
-
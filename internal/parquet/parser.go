@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/teams"
 	"github.com/apache/arrow/go/v14/arrow"
 	"github.com/apache/arrow/go/v14/arrow/array"
 	"github.com/apache/arrow/go/v14/arrow/memory"
@@ -14,143 +19,89 @@ import (
 	"github.com/apache/arrow/go/v14/parquet/pqarrow"
 )
 
-// ParsePlayByPlay reads a Parquet file and returns Play models
-func ParsePlayByPlay(data []byte, season int) ([]models.Play, error) {
-	reader, err := file.NewParquetReader(bytes.NewReader(data))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
-	}
-	defer reader.Close()
+// DefaultBatchSize is the number of rows per record batch when streaming a Parquet file
+const DefaultBatchSize = 10000
 
-	arrowReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+// ParsePlayByPlay reads a Parquet file and returns Play models. It holds the whole
+// season in memory; use StreamPlayByPlay for full play-by-play files.
+func ParsePlayByPlay(data []byte, season int) ([]models.Play, error) {
+	var plays []models.Play
+	err := StreamPlayByPlay(data, season, DefaultBatchSize, func(batch []models.Play) error {
+		plays = append(plays, batch...)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create arrow reader: %w", err)
+		return nil, err
 	}
+	return plays, nil
+}
 
-	table, err := arrowReader.ReadTable(context.Background())
-	if err != nil {
-		return nil, fmt.Errorf("failed to read table: %w", err)
-	}
-	defer table.Release()
+// StreamPlayByPlay reads a Parquet file one record batch at a time and passes the
+// plays from each batch to fn, so only one batch is decoded in memory at once.
+// Returning an error from fn stops the read.
+func StreamPlayByPlay(data []byte, season int, batchSize int, fn func([]models.Play) error) error {
+	return readBatches(data, batchSize, func(table arrow.Table) error {
+		return fn(parsePlayRows(table, season))
+	})
+}
 
+// parsePlayRows converts the rows of a play-by-play table to Play models
+func parsePlayRows(table arrow.Table, season int) []models.Play {
 	numRows := int(table.NumRows())
 	plays := make([]models.Play, 0, numRows)
 
-	// Get column indices
-	schema := table.Schema()
-	colMap := make(map[string]int)
-	for i, field := range schema.Fields() {
-		colMap[field.Name] = i
-	}
-
-	// Helper function to find which chunk contains a row
-	getChunkAndOffset := func(col *arrow.Column, rowIdx int) (arrow.Array, int) {
-		offset := rowIdx
-		for _, chunk := range col.Data().Chunks() {
-			if offset < chunk.Len() {
-				return chunk, offset
-			}
-			offset -= chunk.Len()
-		}
-		return nil, 0
-	}
-
-	// Helper functions to extract values across multiple chunks
-	getString := func(colName string, rowIdx int) string {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.String); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
-			}
-		}
-		return ""
-	}
-
-	getInt := func(colName string, rowIdx int) int {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				switch arr := chunk.(type) {
-				case *array.Int64:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				case *array.Int32:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				}
-			}
-		}
-		return 0
-	}
-
-	getFloat := func(colName string, rowIdx int) float64 {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.Float64); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
-			}
-		}
-		return 0.0
-	}
-
-	getBool := func(colName string, rowIdx int) bool {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.Boolean); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
-			}
-		}
-		return false
-	}
+	cols := newColumnReader(table)
 
 	// Parse each row
 	for i := 0; i < numRows; i++ {
-		// Try 'play_id' first, fall back to 'id' column
-		playID := getString("play_id", i)
+		// Try 'play_id' first, fall back to 'id' column. NFLverse encodes play_id as a
+		// double, like the yardage columns, so read it numerically when it isn't a string.
+		playID := cols.String("play_id", i)
+		if playID == "" {
+			if id := cols.Int("play_id", i); id > 0 {
+				playID = strconv.Itoa(id)
+			}
+		}
 		if playID == "" {
-			playID = getString("id", i)
+			playID = cols.String("id", i)
 		}
 
 		play := models.Play{
-			GameID:           getString("game_id", i),
+			GameID:           cols.String("game_id", i),
 			PlayID:           playID,
 			Season:           season,
-			Week:             getInt("week", i),
-			Quarter:          getInt("qtr", i),
-			Down:             getInt("down", i),
-			YardsToGo:        getInt("ydstogo", i),
-			YardLine:         getInt("yardline_100", i),
-			GameSeconds:      getInt("game_seconds_remaining", i),
-			Description:      getString("desc", i),
-			PlayType:         getString("play_type", i),
-			PossessionTeam:   getString("posteam", i),
-			DefenseTeam:      getString("defteam", i),
-			PasserPlayerID:   getString("passer_player_id", i),
-			PasserPlayerName: getString("passer_player_name", i),
-			ReceiverPlayerID: getString("receiver_player_id", i),
-			RusherPlayerID:   getString("rusher_player_id", i),
-			Yards:            getInt("yards_gained", i),
-			Touchdown:        getBool("touchdown", i),
-			Interception:     getBool("interception", i),
-			Fumble:           getBool("fumble", i),
-			Sack:             getBool("sack", i),
-			EPA:              getFloat("epa", i),
-			WPA:              getFloat("wpa", i),
-			SuccessPlay:      getBool("success", i),
-			AirYards:         getInt("air_yards", i),
-			YardsAfterCatch:  getInt("yards_after_catch", i),
+			Week:             cols.Int("week", i),
+			Quarter:          cols.Int("qtr", i),
+			Down:             cols.Int("down", i),
+			YardsToGo:        cols.Int("ydstogo", i),
+			YardLine:         cols.Int("yardline_100", i),
+			GameSeconds:      cols.Int("game_seconds_remaining", i),
+			Description:      cols.String("desc", i),
+			PlayType:         cols.String("play_type", i),
+			PassLocation:     cols.String("pass_location", i),
+			RunLocation:      cols.String("run_location", i),
+			QBScramble:       cols.Bool("qb_scramble", i),
+			Penalty:          cols.Bool("penalty", i),
+			TwoPointAttempt:  cols.Bool("two_point_attempt", i),
+			PossessionTeam:   cols.Team("posteam", i),
+			DefenseTeam:      cols.Team("defteam", i),
+			PasserPlayerID:   cols.String("passer_player_id", i),
+			PasserPlayerName: cols.String("passer_player_name", i),
+			ReceiverPlayerID: cols.String("receiver_player_id", i),
+			RusherPlayerID:   cols.String("rusher_player_id", i),
+			Yards:            cols.Int("yards_gained", i),
+			Touchdown:        cols.Bool("touchdown", i),
+			Interception:     cols.Bool("interception", i),
+			Fumble:           cols.Bool("fumble", i),
+			Sack:             cols.Bool("sack", i),
+			CompletePass:     cols.Bool("complete_pass", i),
+			EPA:              cols.Float("epa", i),
+			WPA:              cols.Float("wpa", i),
+			SuccessPlay:      cols.Bool("success", i),
+			AirYards:         cols.Int("air_yards", i),
+			YardsAfterCatch:  cols.Int("yards_after_catch", i),
+			CPOE:             cols.Float("cpoe", i),
+			XPass:            cols.Float("xpass", i),
 			CreatedAt:        time.Now(),
 		}
 
@@ -159,11 +110,11 @@ func ParsePlayByPlay(data []byte, season int) ([]models.Play, error) {
 		}
 	}
 
-	return plays, nil
+	return plays
 }
 
-// ParseRoster reads a Parquet roster file and returns Player models
-func ParseRoster(data []byte, season int) ([]models.Player, error) {
+// readTable reads an entire Parquet file into an Arrow table (fine for small files)
+func readTable(data []byte) (arrow.Table, error) {
 	reader, err := file.NewParquetReader(bytes.NewReader(data))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
@@ -179,49 +130,235 @@ func ParseRoster(data []byte, season int) ([]models.Player, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read table: %w", err)
 	}
-	defer table.Release()
+	return table, nil
+}
 
-	numRows := int(table.NumRows())
-	players := make([]models.Player, 0, numRows)
+// readBatches streams a Parquet file as record batches of up to batchSize rows, handing
+// each batch to fn as a single-chunk table that is released once fn returns
+func readBatches(data []byte, batchSize int, fn func(arrow.Table) error) error {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
 
-	schema := table.Schema()
+	reader, err := file.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	defer reader.Close()
+
+	props := pqarrow.ArrowReadProperties{BatchSize: int64(batchSize)}
+	arrowReader, err := pqarrow.NewFileReader(reader, props, memory.DefaultAllocator)
+	if err != nil {
+		return fmt.Errorf("failed to create arrow reader: %w", err)
+	}
+
+	recordReader, err := arrowReader.GetRecordReader(context.Background(), nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create record reader: %w", err)
+	}
+	defer recordReader.Release()
+
+	for recordReader.Next() {
+		rec := recordReader.Record()
+		table := array.NewTableFromRecords(rec.Schema(), []arrow.Record{rec})
+		err := fn(table)
+		table.Release()
+		if err != nil {
+			return err
+		}
+	}
+	if err := recordReader.Err(); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read record batch: %w", err)
+	}
+
+	return nil
+}
+
+// columnReader reads typed values from a table by column name and row index, walking
+// the column's chunks and coercing between the integer and float widths nflverse uses.
+// Missing columns and null values read as the zero value.
+type columnReader struct {
+	table  arrow.Table
+	colMap map[string]int
+}
+
+func newColumnReader(table arrow.Table) *columnReader {
 	colMap := make(map[string]int)
-	for i, field := range schema.Fields() {
+	for i, field := range table.Schema().Fields() {
 		colMap[field.Name] = i
 	}
+	return &columnReader{table: table, colMap: colMap}
+}
 
-	getChunkAndOffset := func(col *arrow.Column, rowIdx int) (arrow.Array, int) {
-		offset := rowIdx
-		for _, chunk := range col.Data().Chunks() {
-			if offset < chunk.Len() {
-				return chunk, offset
-			}
-			offset -= chunk.Len()
-		}
+// Names returns the column names in schema order
+func (r *columnReader) Names() []string {
+	fields := r.table.Schema().Fields()
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+// value returns the chunk holding row and the row's offset within it, or nil when the
+// column is missing or the value is null
+func (r *columnReader) value(colName string, row int) (arrow.Array, int) {
+	colIdx, ok := r.colMap[colName]
+	if !ok {
 		return nil, 0
 	}
 
-	getString := func(colName string, rowIdx int) string {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.String); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
+	offset := row
+	for _, chunk := range r.table.Column(colIdx).Data().Chunks() {
+		if offset < chunk.Len() {
+			if chunk.IsNull(offset) {
+				return nil, 0
 			}
+			return chunk, offset
 		}
-		return ""
+		offset -= chunk.Len()
+	}
+	return nil, 0
+}
+
+// Valid reports whether the column exists and the row's value is not null
+func (r *columnReader) Valid(colName string, row int) bool {
+	chunk, _ := r.value(colName, row)
+	return chunk != nil
+}
+
+func (r *columnReader) String(colName string, row int) string {
+	chunk, offset := r.value(colName, row)
+	switch arr := chunk.(type) {
+	case *array.String:
+		return arr.Value(offset)
+	case *array.LargeString:
+		return arr.Value(offset)
 	}
+	return ""
+}
+
+// Team reads a team abbreviation column as its canonical nflverse abbreviation, so files
+// using older or alternate abbreviations (OAK, SD, STL, JAC) load under the current ones
+func (r *columnReader) Team(colName string, row int) string {
+	return teams.Normalize(r.String(colName, row))
+}
+
+// Int reads integer columns of any width; float columns are truncated
+func (r *columnReader) Int(colName string, row int) int {
+	chunk, offset := r.value(colName, row)
+	switch arr := chunk.(type) {
+	case *array.Int64:
+		return int(arr.Value(offset))
+	case *array.Int32:
+		return int(arr.Value(offset))
+	case *array.Int16:
+		return int(arr.Value(offset))
+	case *array.Int8:
+		return int(arr.Value(offset))
+	case *array.Float64:
+		return int(arr.Value(offset))
+	case *array.Float32:
+		return int(arr.Value(offset))
+	}
+	return 0
+}
+
+func (r *columnReader) Float(colName string, row int) float64 {
+	chunk, offset := r.value(colName, row)
+	switch arr := chunk.(type) {
+	case *array.Float64:
+		return arr.Value(offset)
+	case *array.Float32:
+		return float64(arr.Value(offset))
+	case *array.Int64:
+		return float64(arr.Value(offset))
+	case *array.Int32:
+		return float64(arr.Value(offset))
+	}
+	return 0.0
+}
+
+// Bool reads boolean columns; numeric 0/1 flag columns (as in play-by-play) read as != 0
+func (r *columnReader) Bool(colName string, row int) bool {
+	chunk, offset := r.value(colName, row)
+	switch arr := chunk.(type) {
+	case *array.Boolean:
+		return arr.Value(offset)
+	case *array.Float64:
+		return arr.Value(offset) != 0
+	case *array.Int64:
+		return arr.Value(offset) != 0
+	case *array.Int32:
+		return arr.Value(offset) != 0
+	}
+	return false
+}
+
+// Time reads timestamp and date columns, or RFC 3339 / YYYY-MM-DD strings
+func (r *columnReader) Time(colName string, row int) time.Time {
+	chunk, offset := r.value(colName, row)
+	switch arr := chunk.(type) {
+	case *array.Timestamp:
+		unit := arr.DataType().(*arrow.TimestampType).Unit
+		return arr.Value(offset).ToTime(unit)
+	case *array.Date32:
+		return arr.Value(offset).ToTime()
+	case *array.String:
+		if t, err := time.Parse(time.RFC3339, arr.Value(offset)); err == nil {
+			return t
+		}
+		if t, err := time.Parse(time.DateOnly, arr.Value(offset)); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// heightInches reads the height column, which is inches in recent roster files and
+// feet-inches text ("6-2") in older ones
+func heightInches(cols *columnReader, row int) int {
+	if text := cols.String("height", row); text != "" {
+		feet, inches, found := strings.Cut(text, "-")
+		if !found {
+			n, _ := strconv.Atoi(text)
+			return n
+		}
+		f, _ := strconv.Atoi(feet)
+		in, _ := strconv.Atoi(inches)
+		return f*12 + in
+	}
+	return cols.Int("height", row)
+}
+
+// ParseRoster reads a Parquet roster file and returns Player models
+func ParseRoster(data []byte, season int) ([]models.Player, error) {
+	table, err := readTable(data)
+	if err != nil {
+		return nil, err
+	}
+	defer table.Release()
+
+	numRows := int(table.NumRows())
+	players := make([]models.Player, 0, numRows)
+
+	cols := newColumnReader(table)
 
 	for i := 0; i < numRows; i++ {
 		player := models.Player{
-			NFLID:     getString("gsis_id", i),
-			Season:    season, // Track which year this roster is from
-			Name:      getString("full_name", i),
-			Position:  getString("position", i),
-			Team:      getString("team", i),
-			UpdatedAt: time.Now(),
+			NFLID:        cols.String("gsis_id", i),
+			Season:       season, // Track which year this roster is from
+			Name:         cols.String("full_name", i),
+			Position:     cols.String("position", i),
+			Team:         cols.Team("team", i),
+			JerseyNumber: cols.Int("jersey_number", i),
+			Height:       heightInches(cols, i),
+			Weight:       cols.Int("weight", i),
+			College:      cols.String("college", i),
+			BirthDate:    cols.Time("birth_date", i),
+			YearsExp:     cols.Int("years_exp", i),
+			HeadshotURL:  cols.String("headshot_url", i),
+			UpdatedAt:    time.Now(),
 		}
 
 		if player.NFLID != "" {
@@ -232,109 +369,59 @@ func ParseRoster(data []byte, season int) ([]models.Player, error) {
 	return players, nil
 }
 
-// ParsePlayerStats reads a Parquet player stats file and returns PlayerStats models
-func ParsePlayerStats(data []byte, season int, seasonType string) ([]models.PlayerStats, error) {
-	reader, err := file.NewParquetReader(bytes.NewReader(data))
+// ParseWeeklyRoster reads a Parquet weekly roster file and returns each player's status
+// by week (used for injury designations)
+func ParseWeeklyRoster(data []byte, season int) ([]models.WeeklyRosterEntry, error) {
+	table, err := readTable(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+		return nil, err
 	}
-	defer reader.Close()
+	defer table.Release()
 
-	arrowReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create arrow reader: %w", err)
+	numRows := int(table.NumRows())
+	entries := make([]models.WeeklyRosterEntry, 0, numRows)
+
+	cols := newColumnReader(table)
+
+	for i := 0; i < numRows; i++ {
+		entry := models.WeeklyRosterEntry{
+			NFLID:                 cols.String("gsis_id", i), // Use gsis_id, not player_id!
+			Season:                season,
+			Week:                  cols.Int("week", i),
+			Team:                  cols.Team("team", i),
+			Status:                cols.String("status", i),
+			StatusDescriptionAbbr: cols.String("status_description_abbr", i),
+		}
+
+		if entry.NFLID != "" {
+			entries = append(entries, entry)
+		}
 	}
 
-	table, err := arrowReader.ReadTable(context.Background())
+	return entries, nil
+}
+
+// ParsePlayerStats reads a Parquet player stats file and returns PlayerStats models
+func ParsePlayerStats(data []byte, season int, seasonType string) ([]models.PlayerStats, error) {
+	table, err := readTable(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read table: %w", err)
+		return nil, err
 	}
 	defer table.Release()
 
 	numRows := int(table.NumRows())
 	stats := make([]models.PlayerStats, 0, numRows)
 
-	schema := table.Schema()
-	colMap := make(map[string]int)
+	cols := newColumnReader(table)
 
-	// Debug: Print all available columns (first time only)
-	fmt.Printf("📋 Available columns in player_stats (season %d): ", season)
-	columnNames := make([]string, 0, len(schema.Fields()))
-	for i, field := range schema.Fields() {
-		colMap[field.Name] = i
-		columnNames = append(columnNames, field.Name)
-	}
-	fmt.Printf("%v\n", columnNames)
-
-	getChunkAndOffset := func(col *arrow.Column, rowIdx int) (arrow.Array, int) {
-		offset := rowIdx
-		for _, chunk := range col.Data().Chunks() {
-			if offset < chunk.Len() {
-				return chunk, offset
-			}
-			offset -= chunk.Len()
-		}
-		return nil, 0
-	}
-
-	getString := func(colName string, rowIdx int) string {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.String); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
-			}
-		}
-		return ""
-	}
-
-	getInt := func(colName string, rowIdx int) int {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				switch arr := chunk.(type) {
-				case *array.Int64:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				case *array.Int32:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				}
-			}
-		}
-		return 0
-	}
-
-	getFloat := func(colName string, rowIdx int) float64 {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				switch arr := chunk.(type) {
-				case *array.Float64:
-					if !arr.IsNull(offset) {
-						return arr.Value(offset)
-					}
-				case *array.Float32:
-					if !arr.IsNull(offset) {
-						return float64(arr.Value(offset))
-					}
-				}
-			}
-		}
-		return 0.0
-	}
+	// Debug: Print all available columns
+	fmt.Printf("📋 Available columns in player_stats (season %d): %v\n", season, cols.Names())
 
 	for i := 0; i < numRows; i++ {
 		// Calculate combined EPA from passing, rushing, and receiving EPA
-		passingEPA := getFloat("passing_epa", i)
-		rushingEPA := getFloat("rushing_epa", i)
-		receivingEPA := getFloat("receiving_epa", i)
+		passingEPA := cols.Float("passing_epa", i)
+		rushingEPA := cols.Float("rushing_epa", i)
+		receivingEPA := cols.Float("receiving_epa", i)
 
 		// Sum non-zero EPAs
 		combinedEPA := passingEPA + rushingEPA + receivingEPA
@@ -342,54 +429,54 @@ func ParsePlayerStats(data []byte, season int, seasonType string) ([]models.Play
 		// Count how many plays were involved (for averaging)
 		playCount := 0
 		if passingEPA != 0 {
-			playCount += getInt("attempts", i) // Passing attempts
+			playCount += cols.Int("attempts", i) // Passing attempts
 		}
 		if rushingEPA != 0 {
-			playCount += getInt("carries", i) // Rushing carries
+			playCount += cols.Int("carries", i) // Rushing carries
 		}
 		if receivingEPA != 0 {
-			playCount += getInt("targets", i) // Receiving targets
+			playCount += cols.Int("targets", i) // Receiving targets
 		}
 
 		playerStats := models.PlayerStats{
-			NFLID:      getString("player_id", i),
+			NFLID:      cols.String("player_id", i),
 			Season:     season,
 			SeasonType: seasonType,
 
 			// Offensive Stats (CORRECTED COLUMN NAMES)
-			PassingYards:  getInt("passing_yards", i),
-			PassingTDs:    getInt("passing_tds", i),
-			Interceptions: getInt("passing_interceptions", i), // FIXED: was "interceptions"
+			PassingYards:  cols.Int("passing_yards", i),
+			PassingTDs:    cols.Int("passing_tds", i),
+			Interceptions: cols.Int("passing_interceptions", i), // FIXED: was "interceptions"
 
-			RushingYards: getInt("rushing_yards", i),
-			RushingTDs:   getInt("rushing_tds", i),
+			RushingYards: cols.Int("rushing_yards", i),
+			RushingTDs:   cols.Int("rushing_tds", i),
 
-			Receptions:     getInt("receptions", i),
-			ReceivingYards: getInt("receiving_yards", i),
-			ReceivingTDs:   getInt("receiving_tds", i),
-			Targets:        getInt("targets", i),
+			Receptions:     cols.Int("receptions", i),
+			ReceivingYards: cols.Int("receiving_yards", i),
+			ReceivingTDs:   cols.Int("receiving_tds", i),
+			Targets:        cols.Int("targets", i),
 
 			// Defensive Stats (CORRECTED COLUMN NAMES)
-			Tackles:          getInt("def_tackles_with_assist", i), // FIXED: was "def_tackles_combined"
-			TacklesSolo:      getInt("def_tackles_solo", i),
-			TacklesAssist:    getInt("def_tackle_assists", i), // Already correct
-			TacklesForLoss:   getFloat("def_tackles_for_loss", i),
-			Sacks:            getFloat("def_sacks", i),
-			SackYards:        getFloat("def_sack_yards", i),
-			DefInterceptions: getInt("def_interceptions", i),
-			PassDefended:     getInt("def_pass_defended", i), // FIXED: was "def_passes_defended"
-			ForcedFumbles:    getInt("def_fumbles_forced", i),
-			FumbleRecoveries: getInt("fumble_recovery_opp", i), // FIXED: was "def_fumbles_recovered"
-			DefensiveTDs:     getInt("def_tds", i),
-			SafetyMD:         getInt("def_safeties", i), // FIXED: was "def_safety"
+			Tackles:          cols.Int("def_tackles_with_assist", i), // FIXED: was "def_tackles_combined"
+			TacklesSolo:      cols.Int("def_tackles_solo", i),
+			TacklesAssist:    cols.Int("def_tackle_assists", i), // Already correct
+			TacklesForLoss:   cols.Float("def_tackles_for_loss", i),
+			Sacks:            cols.Float("def_sacks", i),
+			SackYards:        cols.Float("def_sack_yards", i),
+			DefInterceptions: cols.Int("def_interceptions", i),
+			PassDefended:     cols.Int("def_pass_defended", i), // FIXED: was "def_passes_defended"
+			ForcedFumbles:    cols.Int("def_fumbles_forced", i),
+			FumbleRecoveries: cols.Int("fumble_recovery_opp", i), // FIXED: was "def_fumbles_recovered"
+			DefensiveTDs:     cols.Int("def_tds", i),
+			SafetyMD:         cols.Int("def_safeties", i), // FIXED: was "def_safety"
 
 			// Performance Metrics (from parquet file)
 			EPA:       combinedEPA,
 			PlayCount: playCount,
 
 			// Fantasy Points
-			FantasyPoints:    getFloat("fantasy_points", i),
-			FantasyPointsPPR: getFloat("fantasy_points_ppr", i),
+			FantasyPoints:    cols.Float("fantasy_points", i),
+			FantasyPointsPPR: cols.Float("fantasy_points_ppr", i),
 
 			UpdatedAt: time.Now(),
 		}
@@ -404,141 +491,55 @@ func ParsePlayerStats(data []byte, season int, seasonType string) ([]models.Play
 
 // ParseWeeklyStats reads a Parquet weekly player stats file and returns WeeklyStat models
 func ParseWeeklyStats(data []byte, season int) ([]models.WeeklyStat, error) {
-	reader, err := file.NewParquetReader(bytes.NewReader(data))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
-	}
-	defer reader.Close()
-
-	arrowReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	table, err := readTable(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create arrow reader: %w", err)
-	}
-
-	table, err := arrowReader.ReadTable(context.Background())
-	if err != nil {
-		return nil, fmt.Errorf("failed to read table: %w", err)
+		return nil, err
 	}
 	defer table.Release()
 
 	numRows := int(table.NumRows())
 	weeklyStats := make([]models.WeeklyStat, 0, numRows)
 
-	schema := table.Schema()
-	colMap := make(map[string]int)
+	cols := newColumnReader(table)
 
-	// Debug: Print all available columns (first time only)
-	fmt.Printf("📋 Available columns in weekly_stats (season %d): ", season)
-	columnNames := make([]string, 0, len(schema.Fields()))
-	for i, field := range schema.Fields() {
-		colMap[field.Name] = i
-		columnNames = append(columnNames, field.Name)
-	}
-	fmt.Printf("%v\n", columnNames)
-
-	getChunkAndOffset := func(col *arrow.Column, rowIdx int) (arrow.Array, int) {
-		offset := rowIdx
-		for _, chunk := range col.Data().Chunks() {
-			if offset < chunk.Len() {
-				return chunk, offset
-			}
-			offset -= chunk.Len()
-		}
-		return nil, 0
-	}
-
-	getString := func(colName string, rowIdx int) string {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.String); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
-			}
-		}
-		return ""
-	}
-
-	getInt := func(colName string, rowIdx int) int {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				switch arr := chunk.(type) {
-				case *array.Int64:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				case *array.Int32:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				case *array.Float64:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				}
-			}
-		}
-		return 0
-	}
-
-	getFloat := func(colName string, rowIdx int) float64 {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				switch arr := chunk.(type) {
-				case *array.Float64:
-					if !arr.IsNull(offset) {
-						return arr.Value(offset)
-					}
-				case *array.Float32:
-					if !arr.IsNull(offset) {
-						return float64(arr.Value(offset))
-					}
-				}
-			}
-		}
-		return 0.0
-	}
+	// Debug: Print all available columns
+	fmt.Printf("📋 Available columns in weekly_stats (season %d): %v\n", season, cols.Names())
 
 	for i := 0; i < numRows; i++ {
 		// Calculate combined EPA from passing, rushing, and receiving EPA
-		passingEPA := getFloat("passing_epa", i)
-		rushingEPA := getFloat("rushing_epa", i)
-		receivingEPA := getFloat("receiving_epa", i)
+		passingEPA := cols.Float("passing_epa", i)
+		rushingEPA := cols.Float("rushing_epa", i)
+		receivingEPA := cols.Float("receiving_epa", i)
 		combinedEPA := passingEPA + rushingEPA + receivingEPA
 
 		weeklyStat := models.WeeklyStat{
-			NFLID:    getString("player_id", i),
-			Week:     getInt("week", i),
+			NFLID:    cols.String("player_id", i),
+			Week:     cols.Int("week", i),
 			Season:   season,
-			Opponent: getString("opponent_team", i),
+			Opponent: cols.Team("opponent_team", i),
 
 			// Passing Stats
-			PassingYards:  getInt("passing_yards", i),
-			PassingTDs:    getInt("passing_tds", i),
-			Interceptions: getInt("passing_interceptions", i),
+			PassingYards:  cols.Int("passing_yards", i),
+			PassingTDs:    cols.Int("passing_tds", i),
+			Interceptions: cols.Int("passing_interceptions", i),
 
 			// Rushing Stats
-			Carries:      getInt("carries", i),
-			RushingYards: getInt("rushing_yards", i),
-			RushingTDs:   getInt("rushing_tds", i),
+			Carries:      cols.Int("carries", i),
+			RushingYards: cols.Int("rushing_yards", i),
+			RushingTDs:   cols.Int("rushing_tds", i),
 
 			// Receiving Stats
-			Receptions:     getInt("receptions", i),
-			Targets:        getInt("targets", i),
-			ReceivingYards: getInt("receiving_yards", i),
-			ReceivingTDs:   getInt("receiving_tds", i),
+			Receptions:     cols.Int("receptions", i),
+			Targets:        cols.Int("targets", i),
+			ReceivingYards: cols.Int("receiving_yards", i),
+			ReceivingTDs:   cols.Int("receiving_tds", i),
 
 			// Performance Metrics
 			EPA: combinedEPA,
 
 			// Fantasy Points
-			FantasyPoints:    getFloat("fantasy_points", i),
-			FantasyPointsPPR: getFloat("fantasy_points_ppr", i),
+			FantasyPoints:    cols.Float("fantasy_points", i),
+			FantasyPointsPPR: cols.Float("fantasy_points_ppr", i),
 
 			UpdatedAt: time.Now(),
 		}
@@ -551,90 +552,314 @@ func ParseWeeklyStats(data []byte, season int) ([]models.WeeklyStat, error) {
 	return weeklyStats, nil
 }
 
-// ParseSchedules reads a Parquet schedule file and returns Game models
-func ParseSchedules(data []byte) ([]models.Game, error) {
-	reader, err := file.NewParquetReader(bytes.NewReader(data))
+// ParseKickerStats reads the kicking columns of a Parquet weekly player stats file and
+// returns a KickerStats for every player-week with a field goal or extra point attempt
+func ParseKickerStats(data []byte, season int) ([]models.KickerStats, error) {
+	table, err := readTable(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+		return nil, err
 	}
-	defer reader.Close()
+	defer table.Release()
 
-	arrowReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	numRows := int(table.NumRows())
+	cols := newColumnReader(table)
+	kickers := make([]models.KickerStats, 0)
+
+	for i := 0; i < numRows; i++ {
+		if cols.Int("fg_att", i) == 0 && cols.Int("pat_att", i) == 0 {
+			continue
+		}
+
+		k := models.KickerStats{
+			NFLID:    cols.String("player_id", i),
+			Season:   season,
+			Week:     cols.Int("week", i),
+			Team:     cols.Team("team", i),
+			Opponent: cols.Team("opponent_team", i),
+
+			FGMade0To39:  cols.Int("fg_made_0_19", i) + cols.Int("fg_made_20_29", i) + cols.Int("fg_made_30_39", i),
+			FGMade40To49: cols.Int("fg_made_40_49", i),
+			FGMade50Plus: cols.Int("fg_made_50_59", i) + cols.Int("fg_made_60_", i),
+			FGMissed:     cols.Int("fg_missed", i),
+			PATMade:      cols.Int("pat_made", i),
+			PATMissed:    cols.Int("pat_missed", i),
+
+			UpdatedAt: time.Now(),
+		}
+
+		if k.NFLID != "" && k.Week > 0 {
+			kickers = append(kickers, k)
+		}
+	}
+
+	return kickers, nil
+}
+
+// ParseDefenseStats reads a Parquet weekly team stats file and returns each team's defense
+// and special teams line. PointsAllowed isn't in the file and is left for the caller to
+// fill from the schedule.
+func ParseDefenseStats(data []byte, season int) ([]models.DefenseStats, error) {
+	table, err := readTable(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create arrow reader: %w", err)
+		return nil, err
 	}
+	defer table.Release()
 
-	table, err := arrowReader.ReadTable(context.Background())
+	numRows := int(table.NumRows())
+	cols := newColumnReader(table)
+	defenses := make([]models.DefenseStats, 0, numRows)
+
+	for i := 0; i < numRows; i++ {
+		d := models.DefenseStats{
+			Team:     cols.Team("team", i),
+			Season:   season,
+			Week:     cols.Int("week", i),
+			Opponent: cols.Team("opponent_team", i),
+
+			Sacks:            cols.Float("def_sacks", i),
+			Interceptions:    cols.Int("def_interceptions", i),
+			FumbleRecoveries: cols.Int("fumble_recovery_opp", i),
+			Safeties:         cols.Int("def_safeties", i),
+			DefensiveTDs:     cols.Int("def_tds", i),
+			SpecialTeamsTDs:  cols.Int("special_teams_tds", i),
+
+			UpdatedAt: time.Now(),
+		}
+
+		if d.Team != "" && d.Week > 0 {
+			defenses = append(defenses, d)
+		}
+	}
+
+	return defenses, nil
+}
+
+// ParseTeams reads nflverse's teams_colors_logos Parquet file and returns each team's
+// branding, conference and division. The ESPN logo is used since it's hosted consistently
+// for every team; the wordmark is optional. The file also lists relocated franchises under
+// their old abbreviations (OAK, SD, STL); those rows are skipped so each team is stored once
+// under its current abbreviation.
+func ParseTeams(data []byte) ([]models.Team, error) {
+	table, err := readTable(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read table: %w", err)
+		return nil, err
 	}
 	defer table.Release()
 
 	numRows := int(table.NumRows())
-	games := make([]models.Game, 0, numRows)
+	cols := newColumnReader(table)
+	parsed := make([]models.Team, 0, numRows)
 
-	schema := table.Schema()
-	colMap := make(map[string]int)
-	for i, field := range schema.Fields() {
-		colMap[field.Name] = i
+	for i := 0; i < numRows; i++ {
+		team := models.Team{
+			Abbr:           cols.String("team_abbr", i),
+			Name:           cols.String("team_name", i),
+			Nickname:       cols.String("team_nick", i),
+			Conference:     cols.String("team_conf", i),
+			Division:       cols.String("team_division", i),
+			PrimaryColor:   cols.String("team_color", i),
+			SecondaryColor: cols.String("team_color2", i),
+			LogoURL:        cols.String("team_logo_espn", i),
+			WordmarkURL:    cols.String("team_wordmark", i),
+			UpdatedAt:      time.Now(),
+		}
+
+		if team.Abbr != "" && teams.Normalize(team.Abbr) == team.Abbr {
+			parsed = append(parsed, team)
+		}
 	}
 
-	getChunkAndOffset := func(col *arrow.Column, rowIdx int) (arrow.Array, int) {
-		offset := rowIdx
-		for _, chunk := range col.Data().Chunks() {
-			if offset < chunk.Len() {
-				return chunk, offset
-			}
-			offset -= chunk.Len()
+	return parsed, nil
+}
+
+// ParseInjuries reads a Parquet injury report file and returns InjuryReport models
+func ParseInjuries(data []byte) ([]models.InjuryReport, error) {
+	table, err := readTable(data)
+	if err != nil {
+		return nil, err
+	}
+	defer table.Release()
+
+	numRows := int(table.NumRows())
+	reports := make([]models.InjuryReport, 0, numRows)
+
+	cols := newColumnReader(table)
+
+	for i := 0; i < numRows; i++ {
+		// The game-status report names the injury; fall back to the practice report
+		injury := cols.String("report_primary_injury", i)
+		secondary := cols.String("report_secondary_injury", i)
+		if injury == "" {
+			injury = cols.String("practice_primary_injury", i)
+			secondary = cols.String("practice_secondary_injury", i)
+		}
+
+		report := models.InjuryReport{
+			NFLID:           cols.String("gsis_id", i),
+			Season:          cols.Int("season", i),
+			Week:            cols.Int("week", i),
+			GameType:        cols.String("game_type", i),
+			PlayerName:      cols.String("full_name", i),
+			Team:            cols.Team("team", i),
+			Position:        cols.String("position", i),
+			ReportStatus:    cols.String("report_status", i),
+			PracticeStatus:  cols.String("practice_status", i),
+			Injury:          injury,
+			SecondaryInjury: secondary,
+			DateModified:    cols.Time("date_modified", i),
+			UpdatedAt:       time.Now(),
+		}
+
+		if report.NFLID != "" && report.Week > 0 {
+			reports = append(reports, report)
 		}
-		return nil, 0
 	}
 
-	getString := func(colName string, rowIdx int) string {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.String); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
-			}
+	return reports, nil
+}
+
+// ParseParticipation reads a Parquet play participation file and returns each player's
+// offensive snap share per game. Season and week come from nflverse_game_id
+// (e.g. 2024_05_BUF_HOU); offense_players lists the gsis_ids on the field, separated by ';'.
+func ParseParticipation(data []byte, season int) ([]models.SnapCount, error) {
+	table, err := readTable(data)
+	if err != nil {
+		return nil, err
+	}
+	defer table.Release()
+
+	numRows := int(table.NumRows())
+	cols := newColumnReader(table)
+
+	// Special teams plays have no offensive formation; older files lack the column
+	hasFormation := slices.Contains(cols.Names(), "offense_formation")
+
+	type teamGame struct{ gameID, team string }
+	teamSnaps := make(map[teamGame]int)
+	playerSnaps := make(map[teamGame]map[string]int)
+
+	for i := 0; i < numRows; i++ {
+		gameID := cols.String("nflverse_game_id", i)
+		team := cols.Team("possession_team", i)
+		players := cols.String("offense_players", i)
+		if gameID == "" || team == "" || players == "" {
+			continue
 		}
-		return ""
-	}
-
-	getInt := func(colName string, rowIdx int) int {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				switch arr := chunk.(type) {
-				case *array.Int64:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				case *array.Int32:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				}
+		if hasFormation && cols.String("offense_formation", i) == "" {
+			continue
+		}
+
+		key := teamGame{gameID, team}
+		teamSnaps[key]++
+		if playerSnaps[key] == nil {
+			playerSnaps[key] = make(map[string]int)
+		}
+		for _, id := range strings.Split(players, ";") {
+			if id = strings.TrimSpace(id); id != "" {
+				playerSnaps[key][id]++
 			}
 		}
-		return 0
 	}
 
-	getFloat := func(colName string, rowIdx int) float64 {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.Float64); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
+	snaps := make([]models.SnapCount, 0, len(playerSnaps)*11)
+	for key, players := range playerSnaps {
+		parts := strings.Split(key.gameID, "_")
+		if len(parts) < 2 {
+			continue
+		}
+		week, err := strconv.Atoi(parts[1])
+		if err != nil || week < 1 {
+			continue
+		}
+
+		total := teamSnaps[key]
+		for id, n := range players {
+			snaps = append(snaps, models.SnapCount{
+				NFLID:        id,
+				Season:       season,
+				Week:         week,
+				GameID:       key.gameID,
+				Team:         key.team,
+				OffenseSnaps: n,
+				TeamSnaps:    total,
+				OffensePct:   float64(n) / float64(total) * 100,
+				UpdatedAt:    time.Now(),
+			})
+		}
+	}
+
+	return snaps, nil
+}
+
+// ParseQBR reads an ESPN QBR Parquet file and returns QBRStat models.
+// weekly selects the week-level file layout; season-level rows get Week 0.
+func ParseQBR(data []byte, weekly bool) ([]models.QBRStat, error) {
+	table, err := readTable(data)
+	if err != nil {
+		return nil, err
+	}
+	defer table.Release()
+
+	numRows := int(table.NumRows())
+	stats := make([]models.QBRStat, 0, numRows)
+
+	cols := newColumnReader(table)
+
+	for i := 0; i < numRows; i++ {
+		// player_id is numeric in some releases
+		espnID := cols.String("player_id", i)
+		if espnID == "" {
+			if id := cols.Int("player_id", i); id > 0 {
+				espnID = strconv.Itoa(id)
 			}
 		}
-		return 0.0
+
+		name := cols.String("name_display", i)
+		if name == "" {
+			name = cols.String("name_short", i)
+		}
+
+		stat := models.QBRStat{
+			ESPNID:      espnID,
+			PlayerName:  name,
+			Team:        cols.Team("team_abb", i),
+			Season:      cols.Int("season", i),
+			SeasonType:  cols.String("season_type", i),
+			QBRTotal:    cols.Float("qbr_total", i),
+			QBRRaw:      cols.Float("qbr_raw", i),
+			PointsAdded: cols.Float("pts_added", i),
+			QBPlays:     cols.Int("qb_plays", i),
+			EPATotal:    cols.Float("epa_total", i),
+			PassEPA:     cols.Float("pass", i),
+			RunEPA:      cols.Float("run", i),
+			SackEPA:     cols.Float("sack", i),
+			PenaltyEPA:  cols.Float("penalty", i),
+			UpdatedAt:   time.Now(),
+		}
+		if weekly {
+			stat.Week = cols.Int("game_week", i)
+		}
+
+		if stat.ESPNID != "" && (!weekly || stat.Week > 0) {
+			stats = append(stats, stat)
+		}
+	}
+
+	return stats, nil
+}
+
+// ParseSchedules reads a Parquet schedule file and returns Game models
+func ParseSchedules(data []byte) ([]models.Game, error) {
+	table, err := readTable(data)
+	if err != nil {
+		return nil, err
 	}
+	defer table.Release()
+
+	numRows := int(table.NumRows())
+	games := make([]models.Game, 0, numRows)
+
+	cols := newColumnReader(table)
 
 	parseGameDateTime := func(gamedayStr, gametimeStr string) time.Time {
 		if gamedayStr == "" {
@@ -669,35 +894,24 @@ func ParseSchedules(data []byte) ([]models.Game, error) {
 	}
 
 	for i := 0; i < numRows; i++ {
-		homeScore := getInt("home_score", i)
-		awayScore := getInt("away_score", i)
-		gamedayStr := getString("gameday", i)
-		gametimeStr := getString("gametime", i)
+		homeScore := cols.Int("home_score", i)
+		awayScore := cols.Int("away_score", i)
+		gamedayStr := cols.String("gameday", i)
+		gametimeStr := cols.String("gametime", i)
 		startTime := parseGameDateTime(gamedayStr, gametimeStr)
 
-		// Determine status based on whether game has been played
-		// If both scores are 0 and game time is in the future, it's scheduled
-		status := "final"
-		if homeScore == 0 && awayScore == 0 && !startTime.IsZero() {
-			// Compare the actual game time to now
-			// Add a buffer: games with no scores scheduled more than 4 hours ago are likely final
-			now := time.Now()
-			fourHoursAgo := now.Add(-4 * time.Hour)
-
-			if startTime.After(fourHoursAgo) {
-				status = "scheduled"
-			}
-		}
+		scored := cols.Valid("home_score", i) && cols.Valid("away_score", i)
+		status := gameStatus(startTime, scored, time.Now())
 
 		game := models.Game{
-			GameID:    getString("game_id", i),
-			Season:    getInt("season", i),
-			Week:      getInt("week", i),
-			HomeTeam:  getString("home_team", i),
-			AwayTeam:  getString("away_team", i),
+			GameID:    cols.String("game_id", i),
+			Season:    cols.Int("season", i),
+			Week:      cols.Int("week", i),
+			HomeTeam:  cols.Team("home_team", i),
+			AwayTeam:  cols.Team("away_team", i),
 			StartTime: startTime,
-			VegasLine: getFloat("spread_line", i),
-			OverUnder: getFloat("total_line", i),
+			VegasLine: cols.Float("spread_line", i),
+			OverUnder: cols.Float("total_line", i),
 			HomeScore: homeScore,
 			AwayScore: awayScore,
 			Status:    status,
@@ -712,139 +926,84 @@ func ParseSchedules(data []byte) ([]models.Game, error) {
 	return games, nil
 }
 
-// ParseNextGenStats reads a Parquet NGS file and returns NextGenStat models
-func ParseNextGenStats(data []byte, statType string) ([]models.NextGenStat, error) {
-	reader, err := file.NewParquetReader(bytes.NewReader(data))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
-	}
-	defer reader.Close()
-
-	arrowReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create arrow reader: %w", err)
+// gameStatus derives a game's status from its scores and kickoff time. NFLverse leaves
+// scores null until a game is played, so a scored game is final; an unscored game is
+// scheduled before kickoff and live for four hours after. Past that the score feed is
+// assumed to be lagging and the game is treated as final until a reload fills it in.
+func gameStatus(startTime time.Time, scored bool, now time.Time) string {
+	switch {
+	case scored:
+		return "final"
+	case startTime.IsZero() || now.Before(startTime):
+		return "scheduled"
+	case now.Before(startTime.Add(4 * time.Hour)):
+		return "live"
+	default:
+		return "final"
 	}
+}
 
-	table, err := arrowReader.ReadTable(context.Background())
+// ParseNextGenStats reads a Parquet NGS file and returns NextGenStat models
+func ParseNextGenStats(data []byte, statType string) ([]models.NextGenStat, error) {
+	table, err := readTable(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read table: %w", err)
+		return nil, err
 	}
 	defer table.Release()
 
 	numRows := int(table.NumRows())
 	stats := make([]models.NextGenStat, 0, numRows)
 
-	schema := table.Schema()
-	colMap := make(map[string]int)
-	for i, field := range schema.Fields() {
-		colMap[field.Name] = i
-	}
-
-	getChunkAndOffset := func(col *arrow.Column, rowIdx int) (arrow.Array, int) {
-		offset := rowIdx
-		for _, chunk := range col.Data().Chunks() {
-			if offset < chunk.Len() {
-				return chunk, offset
-			}
-			offset -= chunk.Len()
-		}
-		return nil, 0
-	}
-
-	getString := func(colName string, rowIdx int) string {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.String); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
-			}
-		}
-		return ""
-	}
-
-	getInt := func(colName string, rowIdx int) int {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				switch arr := chunk.(type) {
-				case *array.Int64:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				case *array.Int32:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				}
-			}
-		}
-		return 0
-	}
-
-	getFloat := func(colName string, rowIdx int) float64 {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.Float64); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
-			}
-		}
-		return 0.0
-	}
+	cols := newColumnReader(table)
 
 	for i := 0; i < numRows; i++ {
 		stat := models.NextGenStat{
-			PlayerID:   getString("player_gsis_id", i),
-			Season:     getInt("season", i),
-			Week:       getInt("week", i),
+			PlayerID:   cols.String("player_gsis_id", i),
+			Season:     cols.Int("season", i),
+			Week:       cols.Int("week", i),
 			StatType:   statType,
-			PlayerName: getString("player_display_name", i),
-			Team:       getString("team_abbr", i),
-			Position:   getString("player_position", i),
+			PlayerName: cols.String("player_display_name", i),
+			Team:       cols.Team("team_abbr", i),
+			Position:   cols.String("player_position", i),
 			UpdatedAt:  time.Now(),
 		}
 
 		// Parse stat-specific fields based on type
 		switch statType {
 		case "passing":
-			stat.PassAttempts = getInt("attempts", i)
-			stat.PassCompletions = getInt("completions", i)
-			stat.PassYards = getInt("pass_yards", i)
-			stat.PassTouchdowns = getInt("pass_touchdowns", i)
-			stat.Interceptions = getInt("interceptions", i)
-			stat.CompletionPercentageAboveExpectation = getFloat("completion_percentage_above_expectation", i)
-			stat.AvgTimeToThrow = getFloat("avg_time_to_throw", i)
-			stat.AvgCompletedAirYards = getFloat("avg_completed_air_yards", i)
-			stat.AvgIntendedAirYards = getFloat("avg_intended_air_yards", i)
-			stat.AvgAirYardsDifferential = getFloat("avg_air_yards_differential", i)
-			stat.MaxCompletedAirDistance = getFloat("max_completed_air_distance", i)
+			stat.PassAttempts = cols.Int("attempts", i)
+			stat.PassCompletions = cols.Int("completions", i)
+			stat.PassYards = cols.Int("pass_yards", i)
+			stat.PassTouchdowns = cols.Int("pass_touchdowns", i)
+			stat.Interceptions = cols.Int("interceptions", i)
+			stat.CompletionPercentageAboveExpectation = cols.Float("completion_percentage_above_expectation", i)
+			stat.AvgTimeToThrow = cols.Float("avg_time_to_throw", i)
+			stat.AvgCompletedAirYards = cols.Float("avg_completed_air_yards", i)
+			stat.AvgIntendedAirYards = cols.Float("avg_intended_air_yards", i)
+			stat.AvgAirYardsDifferential = cols.Float("avg_air_yards_differential", i)
+			stat.MaxCompletedAirDistance = cols.Float("max_completed_air_distance", i)
 
 		case "rushing":
-			stat.Carries = getInt("carries", i)
-			stat.RushYards = getInt("rush_yards", i)
-			stat.RushTouchdowns = getInt("rush_touchdowns", i)
-			stat.ExpectedRushYards = getFloat("expected_rush_yards", i)
-			stat.RushYardsOverExpected = getFloat("rush_yards_over_expected", i)
-			stat.AvgTimeToLOS = getFloat("avg_time_to_los", i)
-			stat.Efficiency = getFloat("efficiency", i)
+			stat.Carries = cols.Int("carries", i)
+			stat.RushYards = cols.Int("rush_yards", i)
+			stat.RushTouchdowns = cols.Int("rush_touchdowns", i)
+			stat.ExpectedRushYards = cols.Float("expected_rush_yards", i)
+			stat.RushYardsOverExpected = cols.Float("rush_yards_over_expected", i)
+			stat.AvgTimeToLOS = cols.Float("avg_time_to_los", i)
+			stat.Efficiency = cols.Float("efficiency", i)
 
 		case "receiving":
-			stat.Receptions = getInt("receptions", i)
-			stat.Targets = getInt("targets", i)
-			stat.ReceivingYards = getInt("yards", i)
-			stat.ReceivingTouchdowns = getInt("rec_touchdowns", i)
-			stat.AvgCushion = getFloat("avg_cushion", i)
-			stat.AvgSeparation = getFloat("avg_separation", i)
-			stat.AvgIntendedAirYardsRec = getFloat("avg_intended_air_yards", i)
-			stat.CatchPercentage = getFloat("percent_share_of_intended_air_yards", i)
-			stat.AvgYAC = getFloat("avg_yac", i)
-			stat.AvgExpectedYAC = getFloat("avg_expected_yac", i)
-			stat.AvgYACAboveExpectation = getFloat("avg_yac_above_expectation", i)
+			stat.Receptions = cols.Int("receptions", i)
+			stat.Targets = cols.Int("targets", i)
+			stat.ReceivingYards = cols.Int("yards", i)
+			stat.ReceivingTouchdowns = cols.Int("rec_touchdowns", i)
+			stat.AvgCushion = cols.Float("avg_cushion", i)
+			stat.AvgSeparation = cols.Float("avg_separation", i)
+			stat.AvgIntendedAirYardsRec = cols.Float("avg_intended_air_yards", i)
+			stat.CatchPercentage = cols.Float("percent_share_of_intended_air_yards", i)
+			stat.AvgYAC = cols.Float("avg_yac", i)
+			stat.AvgExpectedYAC = cols.Float("avg_expected_yac", i)
+			stat.AvgYACAboveExpectation = cols.Float("avg_yac_above_expectation", i)
 		}
 
 		if stat.PlayerID != "" {
@@ -0,0 +1,822 @@
+package parquet
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/parquet/pqarrow"
+)
+
+// writeStringParquet builds an in-memory Parquet file with a string column for each name,
+// filled from rows in order
+func writeStringParquet(t *testing.T, names []string, rows [][]string) []byte {
+	t.Helper()
+
+	fields := make([]arrow.Field, len(names))
+	for i, name := range names {
+		fields[i] = arrow.Field{Name: name, Type: arrow.BinaryTypes.String, Nullable: true}
+	}
+	values := make([][]any, len(rows))
+	for i, row := range rows {
+		values[i] = make([]any, len(row))
+		for j, value := range row {
+			values[i][j] = value
+		}
+	}
+	return writeParquet(t, fields, values)
+}
+
+// writeParquet builds an in-memory Parquet file with typed columns, filled from rows in
+// order; a nil value is written as null
+func writeParquet(t *testing.T, fields []arrow.Field, rows [][]any) []byte {
+	t.Helper()
+
+	table := buildTable(t, fields, rows)
+	defer table.Release()
+
+	var buf bytes.Buffer
+	if err := pqarrow.WriteTable(table, &buf, table.NumRows(), nil, pqarrow.DefaultWriterProps()); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// buildTable builds a table from rows, one chunk per entry in chunks (all rows in one
+// chunk when none are given)
+func buildTable(t *testing.T, fields []arrow.Field, rows [][]any, chunks ...int) arrow.Table {
+	t.Helper()
+
+	if len(chunks) == 0 {
+		chunks = []int{len(rows)}
+	}
+	schema := arrow.NewSchema(fields, nil)
+
+	var records []arrow.Record
+	for _, size := range chunks {
+		builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		for _, row := range rows[:size] {
+			for i, value := range row {
+				appendValue(t, builder.Field(i), value)
+			}
+		}
+		rows = rows[size:]
+		records = append(records, builder.NewRecord())
+		builder.Release()
+	}
+
+	table := array.NewTableFromRecords(schema, records)
+	for _, rec := range records {
+		rec.Release()
+	}
+	return table
+}
+
+func appendValue(t *testing.T, b array.Builder, value any) {
+	t.Helper()
+	if value == nil {
+		b.AppendNull()
+		return
+	}
+	switch b := b.(type) {
+	case *array.StringBuilder:
+		b.Append(value.(string))
+	case *array.LargeStringBuilder:
+		b.Append(value.(string))
+	case *array.Int64Builder:
+		b.Append(value.(int64))
+	case *array.Int32Builder:
+		b.Append(value.(int32))
+	case *array.Int16Builder:
+		b.Append(value.(int16))
+	case *array.Int8Builder:
+		b.Append(value.(int8))
+	case *array.Float64Builder:
+		b.Append(value.(float64))
+	case *array.Float32Builder:
+		b.Append(value.(float32))
+	case *array.BooleanBuilder:
+		b.Append(value.(bool))
+	case *array.TimestampBuilder:
+		b.Append(value.(arrow.Timestamp))
+	case *array.Date32Builder:
+		b.Append(value.(arrow.Date32))
+	default:
+		t.Fatalf("unsupported fixture builder %T", b)
+	}
+}
+
+func TestParseTeams(t *testing.T) {
+	names := []string{"team_abbr", "team_name", "team_nick", "team_conf", "team_division", "team_color", "team_color2", "team_logo_espn", "team_wordmark"}
+	data := writeStringParquet(t, names, [][]string{
+		{"KC", "Kansas City Chiefs", "Chiefs", "AFC", "AFC West", "#E31837", "#FFB612", "https://a.espncdn.com/kc.png", "https://example.com/kc_wordmark.png"},
+		{"LA", "Los Angeles Rams", "Rams", "NFC", "NFC West", "#003594", "#FFA300", "https://a.espncdn.com/lar.png", ""},
+		// Historical and alias rows duplicate a current franchise and are dropped
+		{"OAK", "Oakland Raiders", "Raiders", "AFC", "AFC West", "#000000", "#A5ACAF", "", ""},
+		{"LAR", "Los Angeles Rams", "Rams", "NFC", "NFC West", "#003594", "#FFA300", "", ""},
+		{"", "", "", "", "", "", "", "", ""},
+	})
+
+	got, err := ParseTeams(data)
+	if err != nil {
+		t.Fatalf("ParseTeams() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ParseTeams() returned %d teams, want 2: %+v", len(got), got)
+	}
+
+	kc := got[0]
+	if kc.Abbr != "KC" || kc.Name != "Kansas City Chiefs" || kc.Nickname != "Chiefs" {
+		t.Errorf("KC identity = %q/%q/%q", kc.Abbr, kc.Name, kc.Nickname)
+	}
+	if kc.Conference != "AFC" || kc.Division != "AFC West" {
+		t.Errorf("KC conference/division = %q/%q, want AFC/AFC West", kc.Conference, kc.Division)
+	}
+	if kc.PrimaryColor != "#E31837" || kc.SecondaryColor != "#FFB612" {
+		t.Errorf("KC colors = %q/%q, want #E31837/#FFB612", kc.PrimaryColor, kc.SecondaryColor)
+	}
+	if kc.LogoURL != "https://a.espncdn.com/kc.png" || kc.WordmarkURL != "https://example.com/kc_wordmark.png" {
+		t.Errorf("KC logo/wordmark = %q/%q", kc.LogoURL, kc.WordmarkURL)
+	}
+	if kc.UpdatedAt.IsZero() {
+		t.Error("KC UpdatedAt is zero")
+	}
+
+	if got[1].Abbr != "LA" || got[1].WordmarkURL != "" {
+		t.Errorf("second team = %q (wordmark %q), want LA without a wordmark", got[1].Abbr, got[1].WordmarkURL)
+	}
+}
+
+func TestParseTeamsMissingColumns(t *testing.T) {
+	// Older teams files lack the wordmark column; the other fields must still load
+	data := writeStringParquet(t, []string{"team_abbr", "team_name"}, [][]string{
+		{"BUF", "Buffalo Bills"},
+	})
+
+	got, err := ParseTeams(data)
+	if err != nil {
+		t.Fatalf("ParseTeams() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Abbr != "BUF" || got[0].Name != "Buffalo Bills" || got[0].WordmarkURL != "" {
+		t.Errorf("ParseTeams() = %+v, want BUF with no wordmark", got)
+	}
+}
+
+func TestParseTeamsInvalidData(t *testing.T) {
+	if _, err := ParseTeams([]byte("not parquet")); err == nil {
+		t.Error("ParseTeams() error = nil for invalid data")
+	}
+}
+
+func TestStreamPlayByPlayBatches(t *testing.T) {
+	const numRows = 25
+	rows := make([][]string, numRows)
+	for i := range rows {
+		rows[i] = []string{"2024_01_KC_BAL", strconv.Itoa(i + 1)}
+	}
+	data := writeStringParquet(t, []string{"game_id", "play_id"}, rows)
+
+	var sizes []int
+	total := 0
+	err := StreamPlayByPlay(data, 2024, 10, func(plays []models.Play) error {
+		sizes = append(sizes, len(plays))
+		total += len(plays)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamPlayByPlay() error = %v", err)
+	}
+	if total != numRows {
+		t.Errorf("streamed %d plays, want %d", total, numRows)
+	}
+	if want := []int{10, 10, 5}; !slices.Equal(sizes, want) {
+		t.Errorf("batch sizes = %v, want %v", sizes, want)
+	}
+
+	plays, err := ParsePlayByPlay(data, 2024)
+	if err != nil {
+		t.Fatalf("ParsePlayByPlay() error = %v", err)
+	}
+	if len(plays) != numRows || plays[numRows-1].PlayID != "25" || plays[0].Season != 2024 {
+		t.Errorf("ParsePlayByPlay() returned %d plays, last %+v", len(plays), plays[len(plays)-1])
+	}
+}
+
+func TestStreamPlayByPlayStopsOnError(t *testing.T) {
+	rows := make([][]string, 30)
+	for i := range rows {
+		rows[i] = []string{strconv.Itoa(i + 1)}
+	}
+	data := writeStringParquet(t, []string{"play_id"}, rows)
+
+	stop := errors.New("stop")
+	calls := 0
+	err := StreamPlayByPlay(data, 2024, 10, func([]models.Play) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("StreamPlayByPlay() error = %v, want %v", err, stop)
+	}
+	if calls != 1 {
+		t.Errorf("callback ran %d times after an error, want 1", calls)
+	}
+}
+
+func TestColumnReaderCoercion(t *testing.T) {
+	kickoff := time.Date(2024, 9, 8, 17, 0, 0, 0, time.UTC)
+	fields := []arrow.Field{
+		{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "large_str", Type: arrow.BinaryTypes.LargeString, Nullable: true},
+		{Name: "i64", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "i32", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "i16", Type: arrow.PrimitiveTypes.Int16, Nullable: true},
+		{Name: "i8", Type: arrow.PrimitiveTypes.Int8, Nullable: true},
+		{Name: "f64", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "f32", Type: arrow.PrimitiveTypes.Float32, Nullable: true},
+		{Name: "flag", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Second}, Nullable: true},
+		{Name: "date", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+	}
+	rows := [][]any{
+		{"KC", "Kansas City", int64(12), int32(-3), int16(7), int8(1), 9.75, float32(2.5), true,
+			arrow.Timestamp(kickoff.Unix()), arrow.Date32FromTime(kickoff)},
+		{"2024-09-08", "", int64(0), int32(0), int16(0), int8(0), 0.0, float32(0), false,
+			arrow.Timestamp(0), arrow.Date32(0)},
+		{nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+	}
+	// Split across chunks so rows past the first chunk are found by offset
+	table := buildTable(t, fields, rows, 1, 2)
+	defer table.Release()
+	cols := newColumnReader(table)
+
+	stringTests := []struct {
+		col  string
+		row  int
+		want string
+	}{
+		{"str", 0, "KC"},
+		{"large_str", 0, "Kansas City"},
+		{"i64", 0, ""}, // non-string columns don't read as strings
+		{"str", 2, ""},
+		{"large_str", 2, ""},
+		{"missing", 0, ""},
+	}
+	for _, tt := range stringTests {
+		if got := cols.String(tt.col, tt.row); got != tt.want {
+			t.Errorf("String(%q, %d) = %q, want %q", tt.col, tt.row, got, tt.want)
+		}
+	}
+
+	intTests := []struct {
+		col  string
+		row  int
+		want int
+	}{
+		{"i64", 0, 12},
+		{"i32", 0, -3},
+		{"i16", 0, 7},
+		{"i8", 0, 1},
+		{"f64", 0, 9}, // floats truncate
+		{"f32", 0, 2},
+		{"str", 0, 0},
+		{"i64", 1, 0},
+		{"i64", 2, 0},
+		{"f64", 2, 0},
+		{"missing", 0, 0},
+	}
+	for _, tt := range intTests {
+		if got := cols.Int(tt.col, tt.row); got != tt.want {
+			t.Errorf("Int(%q, %d) = %d, want %d", tt.col, tt.row, got, tt.want)
+		}
+	}
+
+	floatTests := []struct {
+		col  string
+		row  int
+		want float64
+	}{
+		{"f64", 0, 9.75},
+		{"f32", 0, 2.5},
+		{"i64", 0, 12},
+		{"i32", 0, -3},
+		{"f64", 2, 0},
+		{"i32", 2, 0},
+		{"missing", 0, 0},
+	}
+	for _, tt := range floatTests {
+		if got := cols.Float(tt.col, tt.row); got != tt.want {
+			t.Errorf("Float(%q, %d) = %v, want %v", tt.col, tt.row, got, tt.want)
+		}
+	}
+
+	boolTests := []struct {
+		col  string
+		row  int
+		want bool
+	}{
+		{"flag", 0, true},
+		{"flag", 1, false},
+		{"f64", 0, true}, // numeric 0/1 flags
+		{"f64", 1, false},
+		{"i64", 0, true},
+		{"i32", 1, false},
+		{"flag", 2, false},
+		{"missing", 0, false},
+	}
+	for _, tt := range boolTests {
+		if got := cols.Bool(tt.col, tt.row); got != tt.want {
+			t.Errorf("Bool(%q, %d) = %v, want %v", tt.col, tt.row, got, tt.want)
+		}
+	}
+
+	timeTests := []struct {
+		col  string
+		row  int
+		want time.Time
+	}{
+		{"ts", 0, kickoff},
+		{"date", 0, time.Date(2024, 9, 8, 0, 0, 0, 0, time.UTC)},
+		{"str", 1, time.Date(2024, 9, 8, 0, 0, 0, 0, time.UTC)},
+		{"str", 0, time.Time{}}, // unparseable text
+		{"ts", 2, time.Time{}},
+		{"missing", 0, time.Time{}},
+	}
+	for _, tt := range timeTests {
+		if got := cols.Time(tt.col, tt.row); !got.Equal(tt.want) {
+			t.Errorf("Time(%q, %d) = %v, want %v", tt.col, tt.row, got, tt.want)
+		}
+	}
+
+	for _, col := range []string{"str", "i64", "f64", "flag", "ts"} {
+		if !cols.Valid(col, 1) {
+			t.Errorf("Valid(%q, 1) = false for a zero value", col)
+		}
+		if cols.Valid(col, 2) {
+			t.Errorf("Valid(%q, 2) = true for a null", col)
+		}
+	}
+	if cols.Valid("missing", 0) {
+		t.Error("Valid(\"missing\", 0) = true")
+	}
+}
+
+func TestParseInjuries(t *testing.T) {
+	str := func(name string) arrow.Field {
+		return arrow.Field{Name: name, Type: arrow.BinaryTypes.String, Nullable: true}
+	}
+	fields := []arrow.Field{
+		{Name: "season", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "week", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		str("game_type"), str("gsis_id"), str("full_name"), str("team"), str("position"),
+		str("report_status"), str("report_primary_injury"), str("report_secondary_injury"),
+		str("practice_status"), str("practice_primary_injury"), str("practice_secondary_injury"),
+		{Name: "date_modified", Type: &arrow.TimestampType{Unit: arrow.Second}, Nullable: true},
+	}
+	modified := time.Date(2024, 10, 4, 20, 15, 0, 0, time.UTC)
+	data := writeParquet(t, fields, [][]any{
+		{int32(2024), int32(5), "REG", "00-0036971", "Ja'Marr Chase", "CIN", "WR",
+			"Questionable", "Hamstring", "Ankle", "Limited Participation in Practice", "Hamstring", nil,
+			arrow.Timestamp(modified.Unix())},
+		// No game status yet: the practice report supplies the injury. OAK loads as LV.
+		{int32(2024), int32(5), "REG", "00-0035000", "Practice Only", "OAK", "RB",
+			nil, nil, nil, "Did Not Participate In Practice", "Knee", "Illness", nil},
+		// Rows without a player or week are dropped
+		{int32(2024), int32(5), "REG", nil, "No ID", "KC", "TE", "Out", "Foot", nil, nil, nil, nil, nil},
+		{int32(2024), nil, "REG", "00-0037000", "No Week", "KC", "TE", "Out", "Foot", nil, nil, nil, nil, nil},
+	})
+
+	got, err := ParseInjuries(data)
+	if err != nil {
+		t.Fatalf("ParseInjuries() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ParseInjuries() returned %d reports, want 2: %+v", len(got), got)
+	}
+
+	chase := got[0]
+	if chase.NFLID != "00-0036971" || chase.Season != 2024 || chase.Week != 5 || chase.GameType != "REG" {
+		t.Errorf("report key = %q %d week %d %q", chase.NFLID, chase.Season, chase.Week, chase.GameType)
+	}
+	if chase.PlayerName != "Ja'Marr Chase" || chase.Team != "CIN" || chase.Position != "WR" {
+		t.Errorf("player = %q/%q/%q", chase.PlayerName, chase.Team, chase.Position)
+	}
+	if chase.ReportStatus != "Questionable" || chase.PracticeStatus != "Limited Participation in Practice" {
+		t.Errorf("statuses = %q/%q", chase.ReportStatus, chase.PracticeStatus)
+	}
+	if chase.Injury != "Hamstring" || chase.SecondaryInjury != "Ankle" {
+		t.Errorf("injury = %q/%q, want Hamstring/Ankle", chase.Injury, chase.SecondaryInjury)
+	}
+	if !chase.DateModified.Equal(modified) {
+		t.Errorf("DateModified = %v, want %v", chase.DateModified, modified)
+	}
+
+	practice := got[1]
+	if practice.Team != "LV" || practice.ReportStatus != "" {
+		t.Errorf("team/status = %q/%q, want LV with no game status", practice.Team, practice.ReportStatus)
+	}
+	if practice.Injury != "Knee" || practice.SecondaryInjury != "Illness" {
+		t.Errorf("practice injury = %q/%q, want Knee/Illness", practice.Injury, practice.SecondaryInjury)
+	}
+	if !practice.DateModified.IsZero() {
+		t.Errorf("DateModified = %v for a null, want zero", practice.DateModified)
+	}
+}
+
+func TestParseQBR(t *testing.T) {
+	str := func(name string) arrow.Field {
+		return arrow.Field{Name: name, Type: arrow.BinaryTypes.String, Nullable: true}
+	}
+	f64 := func(name string) arrow.Field {
+		return arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64, Nullable: true}
+	}
+	fields := []arrow.Field{
+		{Name: "season", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		str("season_type"),
+		{Name: "game_week", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "player_id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		str("name_display"), str("name_short"), str("team_abb"),
+		f64("qbr_total"), f64("qbr_raw"), f64("pts_added"), f64("qb_plays"),
+		f64("epa_total"), f64("pass"), f64("run"), f64("sack"), f64("penalty"),
+	}
+	rows := [][]any{
+		{int32(2024), "Regular", int32(3), int64(3918298), "Josh Allen", "J. Allen", "BUF",
+			82.4, 78.1, 4.6, 41.0, 7.9, 5.2, 2.9, -0.7, 0.5},
+		// The short name stands in for a missing display name
+		{int32(2024), "Regular", int32(3), int64(4361259), nil, "J. Daniels", "WSH",
+			65.0, 61.2, 1.8, 38.0, 3.3, 2.0, 1.6, -0.4, 0.1},
+		// Rows without an ESPN id are dropped; so are weekly rows without a week
+		{int32(2024), "Regular", int32(3), nil, "Unknown", "U.", "KC", 50.0, 50.0, 0.0, 10.0, 0.0, 0.0, 0.0, 0.0, 0.0},
+		{int32(2024), "Regular", nil, int64(3139477), "Patrick Mahomes", "P. Mahomes", "KC",
+			70.0, 68.0, 3.0, 40.0, 5.0, 4.0, 1.0, 0.0, 0.0},
+	}
+	data := writeParquet(t, fields, rows)
+
+	weekly, err := ParseQBR(data, true)
+	if err != nil {
+		t.Fatalf("ParseQBR(weekly) error = %v", err)
+	}
+	if len(weekly) != 2 {
+		t.Fatalf("ParseQBR(weekly) returned %d stats, want 2: %+v", len(weekly), weekly)
+	}
+
+	allen := weekly[0]
+	if allen.ESPNID != "3918298" || allen.PlayerName != "Josh Allen" || allen.Team != "BUF" {
+		t.Errorf("player = %q/%q/%q", allen.ESPNID, allen.PlayerName, allen.Team)
+	}
+	if allen.Season != 2024 || allen.SeasonType != "Regular" || allen.Week != 3 {
+		t.Errorf("season/type/week = %d/%q/%d", allen.Season, allen.SeasonType, allen.Week)
+	}
+	if allen.QBRTotal != 82.4 || allen.QBRRaw != 78.1 || allen.PointsAdded != 4.6 || allen.QBPlays != 41 {
+		t.Errorf("QBR = %v raw %v added %v plays %d", allen.QBRTotal, allen.QBRRaw, allen.PointsAdded, allen.QBPlays)
+	}
+	if allen.EPATotal != 7.9 || allen.PassEPA != 5.2 || allen.RunEPA != 2.9 || allen.SackEPA != -0.7 || allen.PenaltyEPA != 0.5 {
+		t.Errorf("EPA splits = %v/%v/%v/%v/%v", allen.EPATotal, allen.PassEPA, allen.RunEPA, allen.SackEPA, allen.PenaltyEPA)
+	}
+	if weekly[1].PlayerName != "J. Daniels" || weekly[1].Team != "WAS" {
+		t.Errorf("second stat = %q/%q, want J. Daniels/WAS", weekly[1].PlayerName, weekly[1].Team)
+	}
+
+	// Season-level files keep rows regardless of week and report week 0
+	seasonal, err := ParseQBR(data, false)
+	if err != nil {
+		t.Fatalf("ParseQBR(season) error = %v", err)
+	}
+	if len(seasonal) != 3 {
+		t.Fatalf("ParseQBR(season) returned %d stats, want 3", len(seasonal))
+	}
+	for _, s := range seasonal {
+		if s.Week != 0 {
+			t.Errorf("%s season-level Week = %d, want 0", s.PlayerName, s.Week)
+		}
+	}
+}
+
+// TestParsePlayByPlayFloatYards guards against yardage columns encoded as doubles, as
+// nflverse does, reading as zero
+func TestParsePlayByPlayFloatYards(t *testing.T) {
+	fields := []arrow.Field{
+		{Name: "game_id", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "play_id", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "week", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "yards_gained", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "air_yards", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "yards_after_catch", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}
+	data := writeParquet(t, fields, [][]any{
+		{"2024_01_BAL_KC", 55.0, int32(1), 23.0, 15.0, 8.0},
+		{"2024_01_BAL_KC", 77.0, int32(1), -4.0, nil, nil},
+	})
+
+	plays, err := ParsePlayByPlay(data, 2024)
+	if err != nil {
+		t.Fatalf("ParsePlayByPlay() error = %v", err)
+	}
+	if len(plays) != 2 {
+		t.Fatalf("ParsePlayByPlay() returned %d plays, want 2", len(plays))
+	}
+
+	catch := plays[0]
+	if catch.PlayID != "55" {
+		t.Errorf("PlayID = %q, want 55", catch.PlayID)
+	}
+	if catch.Yards != 23 || catch.AirYards != 15 || catch.YardsAfterCatch != 8 {
+		t.Errorf("yards/air/YAC = %d/%d/%d, want 23/15/8", catch.Yards, catch.AirYards, catch.YardsAfterCatch)
+	}
+	if sack := plays[1]; sack.Yards != -4 || sack.AirYards != 0 {
+		t.Errorf("loss yards/air = %d/%d, want -4/0", sack.Yards, sack.AirYards)
+	}
+}
+
+func TestParsePlayByPlayExtendedFields(t *testing.T) {
+	fields := []arrow.Field{
+		{Name: "game_id", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "play_id", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "pass_location", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "run_location", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "qb_scramble", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "penalty", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "two_point_attempt", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "cpoe", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "xpass", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}
+	data := writeParquet(t, fields, [][]any{
+		{"2024_01_BAL_KC", 1.0, "deep left", nil, 0.0, 1.0, 0.0, 12.5, 0.82},
+		{"2024_01_BAL_KC", 2.0, nil, "middle", 1.0, 0.0, 1.0, nil, 0.31},
+	})
+
+	plays, err := ParsePlayByPlay(data, 2024)
+	if err != nil {
+		t.Fatalf("ParsePlayByPlay() error = %v", err)
+	}
+	if len(plays) != 2 {
+		t.Fatalf("ParsePlayByPlay() returned %d plays, want 2", len(plays))
+	}
+
+	pass, run := plays[0], plays[1]
+	if pass.PassLocation != "deep left" || pass.RunLocation != "" {
+		t.Errorf("pass locations = %q/%q, want deep left/empty", pass.PassLocation, pass.RunLocation)
+	}
+	if pass.QBScramble || !pass.Penalty || pass.TwoPointAttempt {
+		t.Errorf("pass flags scramble/penalty/2pt = %v/%v/%v, want false/true/false", pass.QBScramble, pass.Penalty, pass.TwoPointAttempt)
+	}
+	if pass.CPOE != 12.5 || pass.XPass != 0.82 {
+		t.Errorf("pass CPOE/XPass = %v/%v, want 12.5/0.82", pass.CPOE, pass.XPass)
+	}
+	if run.RunLocation != "middle" || !run.QBScramble || run.Penalty || !run.TwoPointAttempt {
+		t.Errorf("run = %q scramble %v penalty %v 2pt %v", run.RunLocation, run.QBScramble, run.Penalty, run.TwoPointAttempt)
+	}
+	if run.CPOE != 0 || run.XPass != 0.31 {
+		t.Errorf("run CPOE/XPass = %v/%v, want 0/0.31", run.CPOE, run.XPass)
+	}
+}
+
+func TestParsePlayByPlayExtendedFieldsMissing(t *testing.T) {
+	// Older seasons lack the newer columns; they must load as zero values
+	data := writeStringParquet(t, []string{"game_id", "play_id", "play_type"}, [][]string{
+		{"2005_01_NE_OAK", "36", "pass"},
+	})
+
+	plays, err := ParsePlayByPlay(data, 2005)
+	if err != nil {
+		t.Fatalf("ParsePlayByPlay() error = %v", err)
+	}
+	if len(plays) != 1 {
+		t.Fatalf("ParsePlayByPlay() returned %d plays, want 1", len(plays))
+	}
+	p := plays[0]
+	if p.PlayType != "pass" {
+		t.Errorf("PlayType = %q, want pass", p.PlayType)
+	}
+	if p.PassLocation != "" || p.RunLocation != "" || p.QBScramble || p.Penalty || p.TwoPointAttempt || p.CPOE != 0 || p.XPass != 0 {
+		t.Errorf("missing columns parsed as %+v, want zero values", p)
+	}
+}
+
+// TestParseSchedulesReload checks a game first loaded before kickoff reloads as final
+// once nflverse publishes its score
+func TestParseSchedulesReload(t *testing.T) {
+	eastern, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tz database: %v", err)
+	}
+	kickoff := time.Now().In(eastern).AddDate(0, 0, 3)
+	gameday, gametime := kickoff.Format(time.DateOnly), kickoff.Format("15:04")
+
+	fields := []arrow.Field{
+		{Name: "game_id", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "season", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "week", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "home_team", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "away_team", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "gameday", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "gametime", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "home_score", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "away_score", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+	}
+	load := func(home, away any) models.Game {
+		t.Helper()
+		data := writeParquet(t, fields, [][]any{
+			{"2025_11_KC_DEN", int32(2025), int32(11), "DEN", "KC", gameday, gametime, home, away},
+		})
+		games, err := ParseSchedules(data)
+		if err != nil {
+			t.Fatalf("ParseSchedules() error = %v", err)
+		}
+		if len(games) != 1 {
+			t.Fatalf("ParseSchedules() returned %d games, want 1", len(games))
+		}
+		return games[0]
+	}
+
+	before := load(nil, nil)
+	if before.Status != "scheduled" || before.HomeScore != 0 || before.AwayScore != 0 {
+		t.Errorf("before kickoff = %s %d-%d, want scheduled 0-0", before.Status, before.AwayScore, before.HomeScore)
+	}
+	if before.StartTime.Format("2006-01-02 15:04") != kickoff.Format("2006-01-02 15:04") {
+		t.Errorf("StartTime = %v, want %v", before.StartTime, kickoff)
+	}
+
+	after := load(int32(22), int32(19))
+	if after.Status != "final" || after.HomeScore != 22 || after.AwayScore != 19 {
+		t.Errorf("after the game = %s %d-%d, want final 19-22", after.Status, after.AwayScore, after.HomeScore)
+	}
+}
+
+func TestGameStatus(t *testing.T) {
+	kickoff := time.Date(2025, 11, 16, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		startTime time.Time
+		scored    bool
+		now       time.Time
+		want      string
+	}{
+		{"before kickoff", kickoff, false, kickoff.Add(-time.Hour), "scheduled"},
+		{"in progress", kickoff, false, kickoff.Add(2 * time.Hour), "live"},
+		{"long past without a score", kickoff, false, kickoff.Add(5 * time.Hour), "final"},
+		{"scored", kickoff, true, kickoff.Add(time.Hour), "final"},
+		{"no kickoff time", time.Time{}, false, kickoff, "scheduled"},
+	}
+
+	for _, tt := range tests {
+		if got := gameStatus(tt.startTime, tt.scored, tt.now); got != tt.want {
+			t.Errorf("%s: gameStatus() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseParticipation(t *testing.T) {
+	names := []string{"nflverse_game_id", "play_id", "possession_team", "offense_formation", "offense_players"}
+	const bufGame, oldGame = "2024_05_BUF_HOU", "2024_06_KC_SF"
+	data := writeStringParquet(t, names, [][]string{
+		{bufGame, "1", "BUF", "SHOTGUN", "00-0034857;00-0035000;00-0036000"},
+		{bufGame, "2", "BUF", "SINGLEBACK", "00-0034857; 00-0035000;00-0037000"},
+		{bufGame, "3", "BUF", "SHOTGUN", "00-0034857;00-0036000"},
+		{bufGame, "4", "BUF", "", "00-0034857;00-0099999"}, // special teams play, no formation
+		{bufGame, "5", "HOU", "SHOTGUN", "00-0038000"},
+		{bufGame, "6", "", "SHOTGUN", "00-0038000"},   // no possession team
+		{oldGame, "1", "OAK", "I_FORM", "00-0039000"}, // OAK loads as LV
+		{"2024_XX_BAD", "1", "KC", "SHOTGUN", "00-0039500"},
+	})
+
+	got, err := ParseParticipation(data, 2024)
+	if err != nil {
+		t.Fatalf("ParseParticipation() error = %v", err)
+	}
+	slices.SortFunc(got, func(a, b models.SnapCount) int {
+		if a.GameID != b.GameID {
+			return strings.Compare(a.GameID, b.GameID)
+		}
+		return strings.Compare(a.NFLID, b.NFLID)
+	})
+
+	type snap struct {
+		id, game, team string
+		week, n, total int
+		pct            float64
+	}
+	want := []snap{
+		{"00-0034857", bufGame, "BUF", 5, 3, 3, 100},
+		{"00-0035000", bufGame, "BUF", 5, 2, 3, 200.0 / 3},
+		{"00-0036000", bufGame, "BUF", 5, 2, 3, 200.0 / 3},
+		{"00-0037000", bufGame, "BUF", 5, 1, 3, 100.0 / 3},
+		{"00-0038000", bufGame, "HOU", 5, 1, 1, 100},
+		{"00-0039000", oldGame, "LV", 6, 1, 1, 100},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseParticipation() returned %d snap counts, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.NFLID != w.id || g.GameID != w.game || g.Team != w.team || g.Season != 2024 || g.Week != w.week {
+			t.Errorf("snap %d key = %s %s %s %d week %d, want %s %s %s 2024 week %d", i, g.NFLID, g.GameID, g.Team, g.Season, g.Week, w.id, w.game, w.team, w.week)
+		}
+		if g.OffenseSnaps != w.n || g.TeamSnaps != w.total || math.Abs(g.OffensePct-w.pct) > 1e-9 {
+			t.Errorf("%s snaps = %d/%d (%v%%), want %d/%d (%v%%)", g.NFLID, g.OffenseSnaps, g.TeamSnaps, g.OffensePct, w.n, w.total, w.pct)
+		}
+	}
+}
+
+func TestParseParticipationWithoutFormation(t *testing.T) {
+	// Older participation files have no offense_formation column, so every play counts
+	data := writeStringParquet(t, []string{"nflverse_game_id", "possession_team", "offense_players"}, [][]string{
+		{"2018_01_ATL_PHI", "ATL", "00-0026143;00-0027944"},
+		{"2018_01_ATL_PHI", "ATL", "00-0026143"},
+	})
+
+	got, err := ParseParticipation(data, 2018)
+	if err != nil {
+		t.Fatalf("ParseParticipation() error = %v", err)
+	}
+	counts := make(map[string]int)
+	for _, s := range got {
+		counts[s.NFLID] = s.OffenseSnaps
+		if s.TeamSnaps != 2 {
+			t.Errorf("%s TeamSnaps = %d, want 2", s.NFLID, s.TeamSnaps)
+		}
+	}
+	if counts["00-0026143"] != 2 || counts["00-0027944"] != 1 {
+		t.Errorf("snaps = %v, want 2 and 1", counts)
+	}
+}
+
+func TestParseRosterBio(t *testing.T) {
+	fields := []arrow.Field{
+		{Name: "gsis_id", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "full_name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "position", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "team", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "jersey_number", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "height", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "weight", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "college", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "birth_date", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "years_exp", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "headshot_url", Type: arrow.BinaryTypes.String, Nullable: true},
+	}
+	birth := time.Date(1995, time.September, 17, 0, 0, 0, 0, time.UTC)
+	data := writeParquet(t, fields, [][]any{
+		{"00-0033873", "Patrick Mahomes", "QB", "KC", int32(15), 74.0, int32(225), "Texas Tech", arrow.Date32FromTime(birth), int32(8), "https://static.www.nfl.com/mahomes.png"},
+		// A rookie with no bio filled in yet
+		{"00-0040000", "Rookie Player", "WR", "OAK", nil, nil, nil, nil, nil, int32(0), nil},
+		// Rows without a GSIS ID are dropped
+		{"", "Practice Squad", "LB", "KC", int32(50), 73.0, int32(240), "", nil, int32(1), ""},
+	})
+
+	got, err := ParseRoster(data, 2024)
+	if err != nil {
+		t.Fatalf("ParseRoster() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ParseRoster() returned %d players, want 2: %+v", len(got), got)
+	}
+
+	qb := got[0]
+	if qb.NFLID != "00-0033873" || qb.Name != "Patrick Mahomes" || qb.Position != "QB" || qb.Team != "KC" || qb.Season != 2024 {
+		t.Errorf("QB identity = %+v", qb)
+	}
+	if qb.JerseyNumber != 15 || qb.Height != 74 || qb.Weight != 225 || qb.YearsExp != 8 {
+		t.Errorf("QB jersey/height/weight/exp = %d/%d/%d/%d, want 15/74/225/8", qb.JerseyNumber, qb.Height, qb.Weight, qb.YearsExp)
+	}
+	if qb.College != "Texas Tech" || !qb.BirthDate.Equal(birth) || qb.HeadshotURL != "https://static.www.nfl.com/mahomes.png" {
+		t.Errorf("QB college/birth date/headshot = %q/%v/%q", qb.College, qb.BirthDate, qb.HeadshotURL)
+	}
+
+	rookie := got[1]
+	if rookie.Team != "LV" {
+		t.Errorf("rookie team = %q, want OAK normalized to LV", rookie.Team)
+	}
+	if rookie.JerseyNumber != 0 || rookie.Height != 0 || rookie.College != "" || !rookie.BirthDate.IsZero() || rookie.HeadshotURL != "" {
+		t.Errorf("rookie bio = %+v, want null columns left empty", rookie)
+	}
+}
+
+func TestParseRosterOlderBio(t *testing.T) {
+	// Older roster files store height as feet-inches text and lack the headshot column
+	data := writeStringParquet(t, []string{"gsis_id", "full_name", "position", "team", "height", "birth_date"}, [][]string{
+		{"00-0019596", "Tom Brady", "QB", "NE", "6-4", "1977-08-03"},
+	})
+
+	got, err := ParseRoster(data, 2005)
+	if err != nil {
+		t.Fatalf("ParseRoster() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("ParseRoster() returned %d players, want 1", len(got))
+	}
+	want := time.Date(1977, time.August, 3, 0, 0, 0, 0, time.UTC)
+	if p := got[0]; p.Height != 76 || !p.BirthDate.Equal(want) || p.HeadshotURL != "" || p.Weight != 0 {
+		t.Errorf("older roster bio = height %d, birth date %v, headshot %q, weight %d, want 76, %v, none, 0",
+			p.Height, p.BirthDate, p.HeadshotURL, p.Weight, want)
+	}
+}
@@ -0,0 +1,139 @@
+// Package prompts holds the Gemini prompt templates used by the services. Each template
+// lives in templates/<name>.v<N>.tmpl and is parsed once at startup; services render a
+// template by name with a data struct instead of building prompts inline.
+//
+// Several versions of a prompt can ship side by side. The highest version is used unless
+// PROMPT_VERSIONS pins another, e.g. PROMPT_VERSIONS="game_script=1,chat_answer=2".
+package prompts
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Template names
+const (
+	ChatIntent     = "chat_intent"
+	ChatAnswer     = "chat_answer"
+	StartSit       = "start_sit"
+	WaiverFit      = "waiver_fit"
+	WaiverBreakout = "waiver_breakout"
+	GameScript     = "game_script"
+	TradeRationale = "trade_rationale"
+	InjuryImpact   = "injury_impact"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var fileName = regexp.MustCompile(`^([a-z0-9_]+)\.v([0-9]+)\.tmpl$`)
+
+type prompt struct {
+	versions map[int]*template.Template
+	active   int
+}
+
+var registry = mustLoad(templateFS, os.Getenv("PROMPT_VERSIONS"))
+
+// mustLoad parses every embedded template and applies the version pins, panicking on a
+// malformed template or pin so a bad prompt fails at startup rather than mid-request
+func mustLoad(fsys fs.FS, pins string) map[string]*prompt {
+	files, err := fs.Glob(fsys, "templates/*.tmpl")
+	if err != nil {
+		panic(fmt.Sprintf("prompts: failed to list templates: %v", err))
+	}
+
+	reg := make(map[string]*prompt)
+	for _, file := range files {
+		m := fileName.FindStringSubmatch(path.Base(file))
+		if m == nil {
+			panic(fmt.Sprintf("prompts: template %s is not named <name>.v<N>.tmpl", file))
+		}
+		version, _ := strconv.Atoi(m[2])
+
+		tmpl := template.Must(template.New(path.Base(file)).Option("missingkey=error").ParseFS(fsys, file))
+
+		p, ok := reg[m[1]]
+		if !ok {
+			p = &prompt{versions: make(map[int]*template.Template)}
+			reg[m[1]] = p
+		}
+		p.versions[version] = tmpl
+		if version > p.active {
+			p.active = version
+		}
+	}
+
+	for _, pin := range strings.Split(pins, ",") {
+		pin = strings.TrimSpace(pin)
+		if pin == "" {
+			continue
+		}
+		name, raw, _ := strings.Cut(pin, "=")
+		version, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(raw), "v"))
+		if err != nil {
+			panic(fmt.Sprintf("prompts: invalid PROMPT_VERSIONS entry %q", pin))
+		}
+		p, ok := reg[strings.TrimSpace(name)]
+		if !ok || p.versions[version] == nil {
+			panic(fmt.Sprintf("prompts: PROMPT_VERSIONS pins %q, which is not an embedded template", pin))
+		}
+		p.active = version
+	}
+
+	return reg
+}
+
+// Render executes the active version of the named template with data
+func Render(name string, data any) (string, error) {
+	p, ok := registry[name]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt template %q", name)
+	}
+	return execute(p.versions[p.active], data)
+}
+
+// RenderVersion executes a specific version of the named template, for comparing prompt
+// revisions side by side
+func RenderVersion(name string, version int, data any) (string, error) {
+	p, ok := registry[name]
+	if !ok || p.versions[version] == nil {
+		return "", fmt.Errorf("unknown prompt template %s v%d", name, version)
+	}
+	return execute(p.versions[version], data)
+}
+
+// Version returns the active version of the named template, or 0 if it doesn't exist
+func Version(name string) int {
+	if p, ok := registry[name]; ok {
+		return p.active
+	}
+	return 0
+}
+
+// Names returns every registered template name in sorted order
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func execute(tmpl *template.Template, data any) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %s: %w", tmpl.Name(), err)
+	}
+	// Template files end with a newline; the prompts themselves don't
+	return strings.TrimSpace(b.String()), nil
+}
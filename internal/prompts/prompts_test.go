@@ -0,0 +1,157 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// sampleData has example data for every template, shaped like what the services pass.
+// Maps are used so a template referencing a key the sample lacks fails (missingkey=error).
+var sampleData = map[string]any{
+	ChatIntent: map[string]any{"Question": "Should I start Bijan Robinson this week?"},
+	ChatAnswer: map[string]any{
+		"Context": "League: 12-team PPR",
+		"Stats":   "Bijan Robinson: 98 rush yds/game, 0.12 EPA/play",
+		"History": []map[string]any{
+			{"Role": "user", "Content": "Who is ATL's RB1?"},
+			{"Role": "model", "Content": "Bijan Robinson."},
+		},
+		"Question": "Should I start Bijan Robinson this week?",
+	},
+	StartSit: map[string]any{"Players": []map[string]any{
+		{
+			"Label": "A", "Name": "Jalen Hurts", "Position": "QB", "Team": "PHI",
+			"ProjectedPoints": 21.4, "SeasonAverage": 20.1, "InjuryStatus": "ACTIVE", "IsInjured": false,
+			"TrendDescription": "trending up", "AvgEPA": 0.142, "MatchupAnalysis": "DAL allows the 5th-most QB points",
+			"RecentGames": []map[string]any{
+				{"Week": 9, "Opponent": "JAX", "FantasyPoints": 24.2, "PassingYards": 210, "PassingTDs": 2, "Interceptions": 0,
+					"RushingYards": 45, "RushingTDs": 1, "Receptions": 0, "ReceivingYards": 0, "ReceivingTDs": 0},
+			},
+		},
+		{
+			"Label": "B", "Name": "Tucker Kraft", "Position": "TE", "Team": "GB",
+			"ProjectedPoints": 9.8, "SeasonAverage": 8.7, "InjuryStatus": "QUESTIONABLE", "IsInjured": true,
+			"TrendDescription": "", "AvgEPA": 0.0, "MatchupAnalysis": "",
+			"RecentGames": []map[string]any{},
+		},
+	}},
+	WaiverFit: map[string]any{
+		"Gem": map[string]any{
+			"PlayerName": "Jalen McMillan", "Position": "WR", "Team": "TB", "BreakoutScore": 72.0,
+			"EPAPerPlay": 0.21, "EPAPercentile": 81.0, "SnapCountPct": 74.5, "TargetShareTrend": "increasing", "ADOT": 11.2,
+		},
+		"AirYardsSharePct": 23.0,
+		"Starters": []map[string]any{
+			{"Name": "Mike Evans", "ProjectedPoints": 14.2},
+		},
+		"PositionAverage": 14.2,
+	},
+	WaiverBreakout: map[string]any{
+		"Gem": map[string]any{
+			"PlayerName": "Tank Bigsby", "Position": "RB", "Team": "JAX", "BreakoutScore": 64.0,
+			"EPAPerPlay": 0.051, "SnapCountPct": 48.0, "TargetShareTrend": "stable", "DepthChartStatus": "RB2",
+			"UpcomingSchedule": "favorable", "ScheduleRank": 4,
+		},
+		"RecentPerformance": "Week 9: 14.2 pts",
+	},
+	GameScript: map[string]any{
+		"Game": map[string]any{
+			"AwayTeam": "KC", "HomeTeam": "BUF", "VegasLine": -2.5, "OverUnder": 47.5,
+			"StartTime": time.Date(2025, 11, 16, 21, 25, 0, 0, time.UTC), "Week": 11,
+		},
+		"AwayContext":       "KC roster: Patrick Mahomes (STARTER, 9 games)",
+		"HomeContext":       "BUF roster: Josh Allen (STARTER, 9 games)",
+		"HistoricalContext": "Last meeting: BUF 30, KC 21",
+		"HomeAwayContext":   "BUF averages 29.1 points at home",
+		"TendencyContext":   "KC passes 61% of plays",
+	},
+	TradeRationale: map[string]any{
+		"ScoringName": "PPR",
+		"TeamAReceives": map[string]any{"TotalValue": 142.6, "Players": []map[string]any{
+			{"Name": "Bijan Robinson", "Position": "RB", "Team": "ATL", "WeeklyProjection": 18.5, "RemainingGames": 8,
+				"Schedule": "favorable", "Trend": "up", "AvgEPA": 0.081, "InjuryStatus": "", "Value": 142.6},
+		}},
+		"TeamBReceives": map[string]any{"TotalValue": 120.0, "Players": []map[string]any{
+			{"Name": "Ja'Marr Chase", "Position": "WR", "Team": "CIN", "WeeklyProjection": 15.0, "RemainingGames": 8,
+				"Schedule": "neutral", "Trend": "stable", "AvgEPA": 0.154, "InjuryStatus": "QUESTIONABLE", "Value": 120.0},
+		}},
+		"Verdict": "favors_a",
+	},
+	InjuryImpact: map[string]any{
+		"Injured": map[string]any{"Name": "Drake London", "Position": "WR", "Team": "ATL", "Season": 2025},
+		"Impact": map[string]any{
+			"Vacated":       map[string]any{"TargetsPerGame": 9.1, "CarriesPerGame": 0.2, "Games": 9},
+			"NoClearBackup": false,
+			"Beneficiaries": []map[string]any{
+				{"PlayerName": "Darnell Mooney",
+					"Current":   map[string]any{"TargetsPerGame": 6.0, "CarriesPerGame": 0.1},
+					"Projected": map[string]any{"TargetsPerGame": 9.2, "CarriesPerGame": 0.1}},
+			},
+		},
+	},
+}
+
+func TestRenderEveryTemplate(t *testing.T) {
+	for _, name := range Names() {
+		data, ok := sampleData[name]
+		if !ok {
+			t.Errorf("template %s has no sample data", name)
+			continue
+		}
+		for version := range registry[name].versions {
+			out, err := RenderVersion(name, version, data)
+			if err != nil {
+				t.Errorf("RenderVersion(%s, %d) error = %v", name, version, err)
+				continue
+			}
+			if out == "" || strings.Contains(out, "<no value>") {
+				t.Errorf("RenderVersion(%s, %d) rendered %q", name, version, out)
+			}
+		}
+		if _, err := Render(name, data); err != nil {
+			t.Errorf("Render(%s) error = %v", name, err)
+		}
+	}
+
+	for name := range sampleData {
+		if Version(name) == 0 {
+			t.Errorf("sample data for %s, which is not a template", name)
+		}
+	}
+}
+
+func TestRenderMissingField(t *testing.T) {
+	if _, err := Render(ChatIntent, map[string]any{}); err == nil {
+		t.Error("Render() with a missing field returned no error")
+	}
+	if _, err := Render("no_such_prompt", nil); err == nil {
+		t.Error("Render() of an unknown template returned no error")
+	}
+}
+
+func TestVersionPins(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/greeting.v1.tmpl": {Data: []byte("Hello {{.Name}}\n")},
+		"templates/greeting.v2.tmpl": {Data: []byte("Hi {{.Name}}\n")},
+	}
+
+	if got := mustLoad(fsys, "")["greeting"].active; got != 2 {
+		t.Errorf("active version without pins = %d, want 2", got)
+	}
+	if got := mustLoad(fsys, " greeting=v1 ")["greeting"].active; got != 1 {
+		t.Errorf("active version pinned to v1 = %d, want 1", got)
+	}
+
+	for _, pins := range []string{"greeting=3", "farewell=1", "greeting=one"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("mustLoad() with PROMPT_VERSIONS=%q did not panic", pins)
+				}
+			}()
+			mustLoad(fsys, pins)
+		}()
+	}
+}
@@ -0,0 +1,53 @@
+// Package season resolves the NFL season and week the app treats as "now". The defaults
+// can be overridden per request (see middleware.SeasonOverride) so QA and demos can
+// simulate any point in a season against the loaded data.
+package season
+
+import "context"
+
+const (
+	// DefaultSeason is the season the app serves when none is requested
+	DefaultSeason = 2025
+	// DefaultWeek is the week used where the current week isn't computed from the schedule
+	DefaultWeek = 10
+)
+
+type overrideKey struct{}
+
+type override struct {
+	season int
+	week   int
+}
+
+// WithOverride returns a context whose Current season and week are replaced. Zero values
+// keep the default.
+func WithOverride(ctx context.Context, season, week int) context.Context {
+	return context.WithValue(ctx, overrideKey{}, override{season: season, week: week})
+}
+
+// Current returns the season and week for ctx: the request's override when one was set,
+// otherwise DefaultSeason and DefaultWeek
+func Current(ctx context.Context) (int, int) {
+	season, week := DefaultSeason, DefaultWeek
+	if o, ok := ctx.Value(overrideKey{}).(override); ok {
+		if o.season > 0 {
+			season = o.season
+		}
+		if o.week > 0 {
+			week = o.week
+		}
+	}
+	return season, week
+}
+
+// Season returns the current season for ctx
+func Season(ctx context.Context) int {
+	s, _ := Current(ctx)
+	return s
+}
+
+// Week returns the current week for ctx
+func Week(ctx context.Context) int {
+	_, w := Current(ctx)
+	return w
+}
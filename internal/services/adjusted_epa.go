@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// GameAdjustedEPA is a player's EPA in one game measured against what that defense usually allows
+type GameAdjustedEPA struct {
+	GameID      string  `json:"game_id"`
+	Week        int     `json:"week"`
+	Opponent    string  `json:"opponent"`
+	Plays       int     `json:"plays"`
+	EPA         float64 `json:"epa_per_play"`
+	OpponentEPA float64 `json:"opponent_epa_allowed"` // Average EPA per play the defense allowed all season
+	AdjustedEPA float64 `json:"adjusted_epa_per_play"`
+}
+
+// OpponentAdjustedEPA is a player's season EPA corrected for the defenses faced
+type OpponentAdjustedEPA struct {
+	NFLID       string            `json:"nfl_id"`
+	Season      int               `json:"season"`
+	Games       int               `json:"games"`
+	RawEPA      float64           `json:"raw_epa_per_play"`      // Average of per-game EPA
+	AdjustedEPA float64           `json:"adjusted_epa_per_play"` // Average of per-game adjusted EPA
+	ByGame      []GameAdjustedEPA `json:"by_game"`
+}
+
+// GetOpponentAdjustedEPA computes a player's EPA per play in each game of the season minus the
+// opponent defense's average EPA allowed per play, then averages the games. Positive values
+// mean the player beat what that defense usually gives up, so production against elite
+// defenses counts for more than padding stats against weak ones.
+func (s *DataService) GetOpponentAdjustedEPA(ctx context.Context, nflID string, season int) (*OpponentAdjustedEPA, error) {
+	allowed, err := s.defenseEPAAllowed(ctx, season)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, playerGameEPAPipeline(nflID, season))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate player EPA by game: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var games []playerGameEPA
+	if err := cursor.All(ctx, &games); err != nil {
+		return nil, fmt.Errorf("failed to decode player EPA by game: %w", err)
+	}
+	return adjustEPA(nflID, season, games, allowed), nil
+}
+
+// playerGameEPA is a player's average EPA per play in one game
+type playerGameEPA struct {
+	ID struct {
+		GameID      string `bson:"game_id"`
+		Week        int    `bson:"week"`
+		DefenseTeam string `bson:"defense_team"`
+	} `bson:"_id"`
+	AvgEPA float64 `bson:"avg_epa"`
+	Plays  int     `bson:"plays"`
+}
+
+// playerGameEPAPipeline averages a player's EPA per play by game, in week order
+func playerGameEPAPipeline(nflID string, season int) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"season":       season,
+			"defense_team": bson.M{"$ne": ""},
+			"$or": []bson.M{
+				{"passer_player_id": nflID},
+				{"rusher_player_id": nflID},
+				{"receiver_player_id": nflID},
+			},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     bson.M{"game_id": "$game_id", "week": "$week", "defense_team": "$defense_team"},
+			"avg_epa": bson.M{"$avg": "$epa"},
+			"plays":   bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.week", Value: 1}}}},
+	}
+}
+
+// adjustEPA subtracts each game's opponent EPA allowed from the player's EPA and averages
+// the raw and adjusted figures over the games
+func adjustEPA(nflID string, season int, games []playerGameEPA, allowed map[string]float64) *OpponentAdjustedEPA {
+	result := &OpponentAdjustedEPA{NFLID: nflID, Season: season, ByGame: make([]GameAdjustedEPA, 0, len(games))}
+	var rawSum, adjustedSum float64
+	for _, g := range games {
+		game := GameAdjustedEPA{
+			GameID:      g.ID.GameID,
+			Week:        g.ID.Week,
+			Opponent:    g.ID.DefenseTeam,
+			Plays:       g.Plays,
+			EPA:         g.AvgEPA,
+			OpponentEPA: allowed[g.ID.DefenseTeam],
+		}
+		game.AdjustedEPA = game.EPA - game.OpponentEPA
+		result.ByGame = append(result.ByGame, game)
+		rawSum += game.EPA
+		adjustedSum += game.AdjustedEPA
+	}
+
+	result.Games = len(result.ByGame)
+	if result.Games > 0 {
+		result.RawEPA = rawSum / float64(result.Games)
+		result.AdjustedEPA = adjustedSum / float64(result.Games)
+	}
+	return result
+}
+
+// defenseEPAAllowed maps each defense to its average EPA allowed per pass/run play for a season
+func (s *DataService) defenseEPAAllowed(ctx context.Context, season int) (map[string]float64, error) {
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, defenseEPAAllowedPipeline(season))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate defense EPA allowed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []defenseEPAAllowedRow
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode defense EPA allowed: %w", err)
+	}
+	return defenseEPAMap(results), nil
+}
+
+// defenseEPAAllowedRow is a defense's average EPA allowed per play
+type defenseEPAAllowedRow struct {
+	Team   string  `bson:"_id"`
+	AvgEPA float64 `bson:"avg_epa"`
+}
+
+// defenseEPAAllowedPipeline averages the EPA each defense allowed on pass/run plays
+func defenseEPAAllowedPipeline(season int) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"season":       season,
+			"defense_team": bson.M{"$ne": ""},
+			"play_type":    bson.M{"$in": []string{"pass", "run"}},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$defense_team",
+			"avg_epa": bson.M{"$avg": "$epa"},
+		}}},
+	}
+}
+
+func defenseEPAMap(results []defenseEPAAllowedRow) map[string]float64 {
+	allowed := make(map[string]float64, len(results))
+	for _, r := range results {
+		allowed[r.Team] = r.AvgEPA
+	}
+	return allowed
+}
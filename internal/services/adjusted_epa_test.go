@@ -0,0 +1,80 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestOpponentAdjustedEPARanking(t *testing.T) {
+	var plays []models.Play
+	add := func(week int, defense, passer, playType string, epas ...float64) {
+		for _, epa := range epas {
+			plays = append(plays, models.Play{
+				GameID: "g", Season: 2024, Week: week, DefenseTeam: defense,
+				PasserPlayerID: passer, PlayType: playType, EPA: epa,
+			})
+		}
+	}
+
+	// The grinder posts modest EPA against SF and BAL, who allow -0.2 and -0.15 per play
+	add(1, "SF", "grinder", "pass", 0.1)
+	add(1, "SF", "other", "pass", -0.3, -0.3, -0.3)
+	add(1, "SF", "other", "punt", 5) // only pass/run plays count toward EPA allowed
+	add(2, "BAL", "grinder", "pass", 0)
+	add(2, "BAL", "other", "run", -0.2, -0.2, -0.2)
+
+	// The padder posts bigger EPA against NYG and CAR, who allow 0.3 and 0.35 per play
+	add(1, "NYG", "padder", "pass", 0.3)
+	add(1, "NYG", "other", "pass", 0.3, 0.3, 0.3)
+	add(2, "CAR", "padder", "pass", 0.2)
+	add(2, "CAR", "other", "run", 0.4, 0.4, 0.4)
+
+	// Another season doesn't count
+	add(3, "SF", "grinder", "pass", -4)
+	plays[len(plays)-1].Season = 2023
+
+	docs := toDocs(t, plays)
+
+	var defenses []defenseEPAAllowedRow
+	decodeDocs(t, runPipeline(t, defenseEPAAllowedPipeline(2024), docs, nil), &defenses)
+	allowed := defenseEPAMap(defenses)
+	for team, want := range map[string]float64{"SF": -0.2, "BAL": -0.15, "NYG": 0.3, "CAR": 0.35} {
+		if math.Abs(allowed[team]-want) > 1e-9 {
+			t.Errorf("%s EPA allowed = %v, want %v", team, allowed[team], want)
+		}
+	}
+
+	adjusted := func(nflID string) *OpponentAdjustedEPA {
+		var games []playerGameEPA
+		decodeDocs(t, runPipeline(t, playerGameEPAPipeline(nflID, 2024), docs, nil), &games)
+		return adjustEPA(nflID, 2024, games, allowed)
+	}
+	grinder, padder := adjusted("grinder"), adjusted("padder")
+
+	tests := []struct {
+		got           *OpponentAdjustedEPA
+		raw, adjusted float64
+	}{
+		{grinder, 0.05, 0.225},
+		{padder, 0.25, -0.075},
+	}
+	for _, tt := range tests {
+		if tt.got.Games != 2 || math.Abs(tt.got.RawEPA-tt.raw) > 1e-9 || math.Abs(tt.got.AdjustedEPA-tt.adjusted) > 1e-9 {
+			t.Errorf("%s = %d games, raw %v, adjusted %v, want 2, %v, %v",
+				tt.got.NFLID, tt.got.Games, tt.got.RawEPA, tt.got.AdjustedEPA, tt.raw, tt.adjusted)
+		}
+	}
+	if g := grinder.ByGame; len(g) != 2 || g[0].Week != 1 || g[0].Opponent != "SF" || math.Abs(g[0].AdjustedEPA-0.3) > 1e-9 {
+		t.Errorf("grinder by game = %+v, want week 1 vs SF adjusted to 0.3 first", g)
+	}
+
+	// Raw EPA favors the padder; adjusting for the defenses faced flips the ranking
+	ranked := []*OpponentAdjustedEPA{padder, grinder}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].AdjustedEPA > ranked[j].AdjustedEPA })
+	if padder.RawEPA <= grinder.RawEPA || ranked[0].NFLID != "grinder" {
+		t.Errorf("ranking by adjusted EPA = %s, %s, want the grinder first", ranked[0].NFLID, ranked[1].NFLID)
+	}
+}
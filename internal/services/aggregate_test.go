@@ -0,0 +1,1179 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// This file holds a small in-memory evaluator for the query filters, find options and
+// aggregation pipelines the services build, so their logic can be checked against seeded
+// documents without a database. It covers the operators the services use; anything else
+// fails the test rather than being silently ignored.
+
+// toDocs stores each element of items (a slice of models) the way a collection holds it
+func toDocs(t *testing.T, items interface{}) []bson.M {
+	t.Helper()
+
+	v := reflect.ValueOf(items)
+	docs := make([]bson.M, v.Len())
+	for i := range docs {
+		raw, err := bson.Marshal(v.Index(i).Interface())
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if err := bson.Unmarshal(raw, &docs[i]); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+	}
+	return docs
+}
+
+// decodeDocs decodes docs into out, a pointer to a slice, the way cursor.All would
+func decodeDocs(t *testing.T, docs []bson.M, out interface{}) {
+	t.Helper()
+
+	raw, err := bson.Marshal(bson.M{"docs": docs})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	wrapper := reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: "Docs",
+		Type: reflect.TypeOf(out).Elem(),
+		Tag:  `bson:"docs"`,
+	}}))
+	if err := bson.Unmarshal(raw, wrapper.Interface()); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	reflect.ValueOf(out).Elem().Set(wrapper.Elem().Field(0))
+}
+
+// findDocs applies filter and the sort, skip and limit of opts to docs, like Find
+func findDocs(t *testing.T, docs []bson.M, filter interface{}, opts *options.FindOptionsBuilder) []bson.M {
+	t.Helper()
+
+	var found []bson.M
+	for _, doc := range docs {
+		if matchDoc(t, filter, doc) {
+			found = append(found, doc)
+		}
+	}
+	if opts == nil {
+		return found
+	}
+
+	var fo options.FindOptions
+	for _, set := range opts.Opts {
+		if err := set(&fo); err != nil {
+			t.Fatalf("find option error = %v", err)
+		}
+	}
+	if fo.Sort != nil {
+		found = sortDocs(t, found, fo.Sort)
+	}
+	if fo.Skip != nil {
+		found = found[min(int(*fo.Skip), len(found)):]
+	}
+	if fo.Limit != nil && *fo.Limit > 0 {
+		found = found[:min(int(*fo.Limit), len(found))]
+	}
+	return found
+}
+
+// runPipeline evaluates pipeline over docs. collections holds the other collections
+// $lookup stages can join against.
+func runPipeline(t *testing.T, pipeline interface{}, docs []bson.M, collections map[string][]bson.M) []bson.M {
+	t.Helper()
+
+	for _, stage := range asList(t, pipeline) {
+		fields := asPairs(t, stage)
+		if len(fields) != 1 {
+			t.Fatalf("pipeline stage has %d keys: %v", len(fields), stage)
+		}
+		op, arg := fields[0].Key, fields[0].Value
+
+		switch op {
+		case "$match":
+			var kept []bson.M
+			for _, doc := range docs {
+				if matchDoc(t, arg, doc) {
+					kept = append(kept, doc)
+				}
+			}
+			docs = kept
+		case "$group":
+			docs = groupDocs(t, arg, docs)
+		case "$sort":
+			docs = sortDocs(t, docs, arg)
+		case "$limit":
+			docs = docs[:min(int(toFloat(t, arg)), len(docs))]
+		case "$skip":
+			docs = docs[min(int(toFloat(t, arg)), len(docs)):]
+		case "$project":
+			docs = projectDocs(t, arg, docs)
+		case "$addFields", "$set":
+			out := make([]bson.M, len(docs))
+			for i, doc := range docs {
+				out[i] = copyDoc(doc)
+				for _, f := range asPairs(t, arg) {
+					setPath(out[i], f.Key, evalValue(t, f.Value, doc))
+				}
+			}
+			docs = out
+		case "$unwind":
+			docs = unwindDocs(t, arg, docs)
+		case "$lookup":
+			docs = lookupDocs(t, arg, docs, collections)
+		case "$replaceRoot":
+			out := make([]bson.M, len(docs))
+			for i, doc := range docs {
+				out[i] = evalValue(t, asMap(t, arg)["newRoot"], doc).(bson.M)
+			}
+			docs = out
+		case "$count":
+			docs = []bson.M{{arg.(string): int64(len(docs))}}
+		default:
+			t.Fatalf("unsupported pipeline stage %s", op)
+		}
+	}
+	return docs
+}
+
+// matchDoc reports whether doc satisfies a query filter
+func matchDoc(t *testing.T, filter interface{}, doc bson.M) bool {
+	t.Helper()
+
+	for _, f := range asPairs(t, filter) {
+		switch f.Key {
+		case "$or":
+			matched := false
+			for _, sub := range asList(t, f.Value) {
+				if matchDoc(t, sub, doc) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		case "$and":
+			for _, sub := range asList(t, f.Value) {
+				if !matchDoc(t, sub, doc) {
+					return false
+				}
+			}
+		case "$nor":
+			for _, sub := range asList(t, f.Value) {
+				if matchDoc(t, sub, doc) {
+					return false
+				}
+			}
+		case "$expr":
+			if !truthy(evalValue(t, f.Value, doc)) {
+				return false
+			}
+		default:
+			if !matchField(t, getPath(doc, f.Key), f.Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchField reports whether a document value satisfies a field condition
+func matchField(t *testing.T, value interface{}, cond interface{}) bool {
+	t.Helper()
+
+	if isOperatorDoc(cond) {
+		for _, op := range asPairs(t, cond) {
+			if !matchOperator(t, value, op.Key, op.Value, cond) {
+				return false
+			}
+		}
+		return true
+	}
+	return anyElement(value, func(v interface{}) bool { return equalValues(v, cond) })
+}
+
+func matchOperator(t *testing.T, value interface{}, op string, arg interface{}, cond interface{}) bool {
+	t.Helper()
+
+	switch op {
+	case "$eq":
+		return anyElement(value, func(v interface{}) bool { return equalValues(v, arg) })
+	case "$ne":
+		return !anyElement(value, func(v interface{}) bool { return equalValues(v, arg) })
+	case "$gt", "$gte", "$lt", "$lte":
+		if value == nil || arg == nil {
+			return false
+		}
+		return anyElement(value, func(v interface{}) bool {
+			c := compareValues(v, arg)
+			switch op {
+			case "$gt":
+				return c > 0
+			case "$gte":
+				return c >= 0
+			case "$lt":
+				return c < 0
+			}
+			return c <= 0
+		})
+	case "$in", "$nin":
+		found := false
+		for _, candidate := range asList(t, arg) {
+			if anyElement(value, func(v interface{}) bool { return equalValues(v, candidate) }) {
+				found = true
+				break
+			}
+		}
+		return found == (op == "$in")
+	case "$exists":
+		return (value != nil) == truthy(arg)
+	case "$regex":
+		pattern := fmt.Sprint(arg)
+		if opts, ok := asMap(t, cond)["$options"]; ok && strings.Contains(fmt.Sprint(opts), "i") {
+			pattern = "(?i)" + pattern
+		}
+		re := regexp.MustCompile(pattern)
+		return anyElement(value, func(v interface{}) bool {
+			s, ok := v.(string)
+			return ok && re.MatchString(s)
+		})
+	case "$options":
+		return true
+	case "$not":
+		return !matchField(t, value, arg)
+	}
+	t.Fatalf("unsupported query operator %s", op)
+	return false
+}
+
+// groupDocs evaluates a $group stage, keeping groups in first-seen order
+func groupDocs(t *testing.T, spec interface{}, docs []bson.M) []bson.M {
+	t.Helper()
+
+	fields := asPairs(t, spec)
+	var idExpr interface{}
+	for _, f := range fields {
+		if f.Key == "_id" {
+			idExpr = f.Value
+		}
+	}
+
+	var order []string
+	groups := make(map[string][]bson.M)
+	ids := make(map[string]interface{})
+	for _, doc := range docs {
+		id := evalValue(t, idExpr, doc)
+		key := keyString(id)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+			ids[key] = id
+		}
+		groups[key] = append(groups[key], doc)
+	}
+
+	out := make([]bson.M, 0, len(order))
+	for _, key := range order {
+		result := bson.M{"_id": ids[key]}
+		for _, f := range fields {
+			if f.Key == "_id" {
+				continue
+			}
+			acc := asPairs(t, f.Value)
+			if len(acc) != 1 {
+				t.Fatalf("accumulator for %s has %d keys", f.Key, len(acc))
+			}
+			result[f.Key] = accumulate(t, acc[0].Key, acc[0].Value, groups[key])
+		}
+		out = append(out, result)
+	}
+	return out
+}
+
+func accumulate(t *testing.T, op string, expr interface{}, docs []bson.M) interface{} {
+	t.Helper()
+
+	values := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		values[i] = evalValue(t, expr, doc)
+	}
+
+	switch op {
+	case "$sum":
+		return sumValues(t, values)
+	case "$avg":
+		var total float64
+		n := 0
+		for _, v := range values {
+			if isNumber(v) {
+				total += toFloat(t, v)
+				n++
+			}
+		}
+		if n == 0 {
+			return nil
+		}
+		return total / float64(n)
+	case "$max", "$min":
+		var best interface{}
+		for _, v := range values {
+			if v == nil {
+				continue
+			}
+			if best == nil || (op == "$max" && compareValues(v, best) > 0) || (op == "$min" && compareValues(v, best) < 0) {
+				best = v
+			}
+		}
+		return best
+	case "$first":
+		if len(values) == 0 {
+			return nil
+		}
+		return values[0]
+	case "$last":
+		if len(values) == 0 {
+			return nil
+		}
+		return values[len(values)-1]
+	case "$push":
+		return bson.A(values)
+	case "$addToSet":
+		set := bson.A{}
+		seen := make(map[string]bool)
+		for _, v := range values {
+			if key := keyString(v); !seen[key] {
+				seen[key] = true
+				set = append(set, v)
+			}
+		}
+		return set
+	case "$count":
+		return int64(len(values))
+	}
+	t.Fatalf("unsupported accumulator %s", op)
+	return nil
+}
+
+// sumValues adds the numbers in values, staying integral when every number is
+func sumValues(t *testing.T, values []interface{}) interface{} {
+	var total float64
+	integral := true
+	for _, v := range values {
+		if !isNumber(v) {
+			continue
+		}
+		switch v.(type) {
+		case float32, float64:
+			integral = false
+		}
+		total += toFloat(t, v)
+	}
+	if integral {
+		return int64(total)
+	}
+	return total
+}
+
+// sortDocs stably sorts docs by a sort document
+func sortDocs(t *testing.T, docs []bson.M, spec interface{}) []bson.M {
+	t.Helper()
+
+	keys := asPairs(t, spec)
+	sorted := append([]bson.M(nil), docs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for _, k := range keys {
+			c := compareValues(getPath(sorted[i], k.Key), getPath(sorted[j], k.Key))
+			if toFloat(t, k.Value) < 0 {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return sorted
+}
+
+func projectDocs(t *testing.T, spec interface{}, docs []bson.M) []bson.M {
+	t.Helper()
+
+	fields := asPairs(t, spec)
+	exclusion := true
+	for _, f := range fields {
+		if f.Key != "_id" && !isExclusion(f.Value) {
+			exclusion = false
+		}
+	}
+
+	out := make([]bson.M, len(docs))
+	for i, doc := range docs {
+		if exclusion {
+			out[i] = copyDoc(doc)
+			for _, f := range fields {
+				delete(out[i], f.Key)
+			}
+			continue
+		}
+
+		out[i] = bson.M{"_id": doc["_id"]}
+		for _, f := range fields {
+			switch {
+			case isExclusion(f.Value):
+				delete(out[i], f.Key)
+			case isInclusion(f.Value):
+				if v := getPath(doc, f.Key); v != nil {
+					setPath(out[i], f.Key, v)
+				}
+			default:
+				setPath(out[i], f.Key, evalValue(t, f.Value, doc))
+			}
+		}
+	}
+	return out
+}
+
+func unwindDocs(t *testing.T, spec interface{}, docs []bson.M) []bson.M {
+	t.Helper()
+
+	path, preserve := "", false
+	if s, ok := spec.(string); ok {
+		path = s
+	} else {
+		m := asMap(t, spec)
+		path = m["path"].(string)
+		preserve = truthy(m["preserveNullAndEmptyArrays"])
+	}
+	field := strings.TrimPrefix(path, "$")
+
+	var out []bson.M
+	for _, doc := range docs {
+		items, isArray := getPath(doc, field).(bson.A)
+		if !isArray || len(items) == 0 {
+			if preserve {
+				out = append(out, doc)
+			}
+			continue
+		}
+		for _, item := range items {
+			unwound := copyDoc(doc)
+			setPath(unwound, field, item)
+			out = append(out, unwound)
+		}
+	}
+	return out
+}
+
+func lookupDocs(t *testing.T, spec interface{}, docs []bson.M, collections map[string][]bson.M) []bson.M {
+	t.Helper()
+
+	m := asMap(t, spec)
+	from := collections[m["from"].(string)]
+	as := m["as"].(string)
+
+	out := make([]bson.M, len(docs))
+	for i, doc := range docs {
+		joined := bson.A{}
+		if pipeline, ok := m["pipeline"]; ok {
+			// Bind the let variables by rewriting $$name references to literals
+			vars := bson.M{}
+			if let, ok := m["let"]; ok {
+				for _, v := range asPairs(t, let) {
+					vars[v.Key] = evalValue(t, v.Value, doc)
+				}
+			}
+			for _, match := range runPipeline(t, bindVars(pipeline, vars), from, collections) {
+				joined = append(joined, match)
+			}
+		} else {
+			local := getPath(doc, m["localField"].(string))
+			for _, other := range from {
+				if equalValues(getPath(other, m["foreignField"].(string)), local) {
+					joined = append(joined, other)
+				}
+			}
+		}
+		out[i] = copyDoc(doc)
+		out[i][as] = joined
+	}
+	return out
+}
+
+// bindVars replaces "$$name" strings in v with their values
+func bindVars(v interface{}, vars bson.M) interface{} {
+	switch x := v.(type) {
+	case string:
+		if strings.HasPrefix(x, "$$") {
+			if val, ok := vars[strings.TrimPrefix(x, "$$")]; ok {
+				return val
+			}
+		}
+		return x
+	case bson.M:
+		out := bson.M{}
+		for k, e := range x {
+			out[k] = bindVars(e, vars)
+		}
+		return out
+	case bson.D:
+		out := bson.D{}
+		for _, e := range x {
+			out = append(out, bson.E{Key: e.Key, Value: bindVars(e.Value, vars)})
+		}
+		return out
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice {
+		out := bson.A{}
+		for i := 0; i < rv.Len(); i++ {
+			out = append(out, bindVars(rv.Index(i).Interface(), vars))
+		}
+		return out
+	}
+	return v
+}
+
+// evalValue evaluates an aggregation expression against doc
+func evalValue(t *testing.T, expr interface{}, doc bson.M) interface{} {
+	t.Helper()
+
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case string:
+		if e == "$$ROOT" {
+			return doc
+		}
+		if strings.HasPrefix(e, "$") && !strings.HasPrefix(e, "$$") {
+			return getPath(doc, e[1:])
+		}
+		return e
+	case bson.M, bson.D, map[string]interface{}:
+		fields := asPairs(t, e)
+		if len(fields) == 1 && strings.HasPrefix(fields[0].Key, "$") {
+			return evalOperator(t, fields[0].Key, fields[0].Value, doc)
+		}
+		// Keep a bson.D's field order, which decides how documents compare
+		if _, ok := e.(bson.D); ok {
+			out := make(bson.D, len(fields))
+			for i, f := range fields {
+				out[i] = bson.E{Key: f.Key, Value: evalValue(t, f.Value, doc)}
+			}
+			return out
+		}
+		out := bson.M{}
+		for _, f := range fields {
+			out[f.Key] = evalValue(t, f.Value, doc)
+		}
+		return out
+	}
+
+	if rv := reflect.ValueOf(expr); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		out := bson.A{}
+		for i := 0; i < rv.Len(); i++ {
+			out = append(out, evalValue(t, rv.Index(i).Interface(), doc))
+		}
+		return out
+	}
+	return expr
+}
+
+func evalOperator(t *testing.T, op string, arg interface{}, doc bson.M) interface{} {
+	t.Helper()
+
+	args := func() []interface{} {
+		list := asList(t, arg)
+		out := make([]interface{}, len(list))
+		for i, a := range list {
+			out[i] = evalValue(t, a, doc)
+		}
+		return out
+	}
+
+	switch op {
+	case "$cond":
+		var cond, then, otherwise interface{}
+		if isListValue(arg) {
+			list := asList(t, arg)
+			cond, then, otherwise = list[0], list[1], list[2]
+		} else {
+			m := asMap(t, arg)
+			cond, then, otherwise = m["if"], m["then"], m["else"]
+		}
+		if truthy(evalValue(t, cond, doc)) {
+			return evalValue(t, then, doc)
+		}
+		return evalValue(t, otherwise, doc)
+	case "$switch":
+		m := asMap(t, arg)
+		for _, branch := range asList(t, m["branches"]) {
+			b := asMap(t, branch)
+			if truthy(evalValue(t, b["case"], doc)) {
+				return evalValue(t, b["then"], doc)
+			}
+		}
+		return evalValue(t, m["default"], doc)
+	case "$and":
+		for _, v := range args() {
+			if !truthy(v) {
+				return false
+			}
+		}
+		return true
+	case "$or":
+		for _, v := range args() {
+			if truthy(v) {
+				return true
+			}
+		}
+		return false
+	case "$not":
+		return !truthy(args()[0])
+	case "$eq", "$ne", "$gt", "$gte", "$lt", "$lte":
+		a := args()
+		c := compareValues(a[0], a[1])
+		switch op {
+		case "$eq":
+			return equalValues(a[0], a[1])
+		case "$ne":
+			return !equalValues(a[0], a[1])
+		case "$gt":
+			return c > 0
+		case "$gte":
+			return c >= 0
+		case "$lt":
+			return c < 0
+		}
+		return c <= 0
+	case "$in":
+		a := args()
+		for _, v := range asList(t, a[1]) {
+			if equalValues(a[0], v) {
+				return true
+			}
+		}
+		return false
+	case "$ifNull":
+		for _, v := range args() {
+			if v != nil {
+				return v
+			}
+		}
+		return nil
+	case "$add", "$multiply":
+		a := args()
+		total := toFloat(t, a[0])
+		for _, v := range a[1:] {
+			if v == nil {
+				return nil
+			}
+			if op == "$add" {
+				total += toFloat(t, v)
+			} else {
+				total *= toFloat(t, v)
+			}
+		}
+		return total
+	case "$subtract":
+		a := args()
+		return toFloat(t, a[0]) - toFloat(t, a[1])
+	case "$divide":
+		a := args()
+		return toFloat(t, a[0]) / toFloat(t, a[1])
+	case "$abs":
+		v := toFloat(t, evalValue(t, arg, doc))
+		if v < 0 {
+			v = -v
+		}
+		return v
+	case "$toDouble":
+		v := evalValue(t, arg, doc)
+		if isListValue(arg) {
+			v = args()[0]
+		}
+		if v == nil {
+			return nil
+		}
+		return toFloat(t, v)
+	case "$concat":
+		var b strings.Builder
+		for _, v := range args() {
+			if v == nil {
+				return nil
+			}
+			b.WriteString(v.(string))
+		}
+		return b.String()
+	case "$max", "$min":
+		var values []interface{}
+		if isListValue(arg) {
+			values = args()
+		} else {
+			values = asList(t, evalValue(t, arg, doc))
+		}
+		var best interface{}
+		for _, v := range values {
+			if v == nil {
+				continue
+			}
+			if best == nil || (op == "$max" && compareValues(v, best) > 0) || (op == "$min" && compareValues(v, best) < 0) {
+				best = v
+			}
+		}
+		return best
+	case "$sum", "$avg":
+		var values []interface{}
+		if isListValue(arg) {
+			values = args()
+			if len(values) == 1 && isListValue(values[0]) {
+				values = asList(t, values[0])
+			}
+		} else {
+			values = asList(t, evalValue(t, arg, doc))
+		}
+		if op == "$sum" {
+			return sumValues(t, values)
+		}
+		return accumulate(t, "$avg", "$v", wrapValues(values))
+	case "$size":
+		return int64(len(asList(t, evalValue(t, arg, doc))))
+	case "$slice":
+		a := args()
+		list := asList(t, a[0])
+		n := int(toFloat(t, a[1]))
+		if n < 0 {
+			return bson.A(list[max(0, len(list)+n):])
+		}
+		return bson.A(list[:min(n, len(list))])
+	case "$arrayElemAt":
+		a := args()
+		list := asList(t, a[0])
+		i := int(toFloat(t, a[1]))
+		if i < 0 {
+			i += len(list)
+		}
+		if i < 0 || i >= len(list) {
+			return nil
+		}
+		return list[i]
+	case "$first", "$last":
+		list := asList(t, evalValue(t, arg, doc))
+		if len(list) == 0 {
+			return nil
+		}
+		if op == "$first" {
+			return list[0]
+		}
+		return list[len(list)-1]
+	case "$toLower", "$toUpper":
+		s, _ := evalValue(t, arg, doc).(string)
+		if op == "$toLower" {
+			return strings.ToLower(s)
+		}
+		return strings.ToUpper(s)
+	}
+	t.Fatalf("unsupported expression operator %s", op)
+	return nil
+}
+
+func wrapValues(values []interface{}) []bson.M {
+	docs := make([]bson.M, len(values))
+	for i, v := range values {
+		docs[i] = bson.M{"v": v}
+	}
+	return docs
+}
+
+// getPath reads a dotted field path from doc, returning nil when it is missing. A path
+// through an array of documents collects the field from each element, as MongoDB does.
+func getPath(doc bson.M, path string) interface{} {
+	return lookupPath(doc, strings.Split(path, "."))
+}
+
+func lookupPath(cur interface{}, parts []string) interface{} {
+	for i, part := range parts {
+		switch c := cur.(type) {
+		case bson.M:
+			cur = c[part]
+		case bson.D:
+			var found interface{}
+			for _, e := range c {
+				if e.Key == part {
+					found = e.Value
+				}
+			}
+			cur = found
+		case map[string]interface{}:
+			cur = c[part]
+		case bson.A, []interface{}:
+			values := bson.A{}
+			for _, v := range reflectList(c) {
+				if found := lookupPath(v, parts[i:]); found != nil {
+					values = append(values, found)
+				}
+			}
+			return values
+		default:
+			return nil
+		}
+	}
+	return cur
+}
+
+// reflectList converts a slice of any element type to []interface{}
+func reflectList(v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+func setPath(doc bson.M, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := doc[part].(bson.M)
+		if !ok {
+			next = bson.M{}
+			doc[part] = next
+		}
+		doc = next
+	}
+	doc[parts[len(parts)-1]] = value
+}
+
+func copyDoc(doc bson.M) bson.M {
+	out := make(bson.M, len(doc))
+	for k, v := range doc {
+		out[k] = v
+	}
+	return out
+}
+
+// asPairs returns the fields of a document in order (bson.M keys are sorted, which
+// only matters for sort specs, and those are always bson.D)
+func asPairs(t *testing.T, v interface{}) bson.D {
+	t.Helper()
+
+	switch d := v.(type) {
+	case bson.D:
+		return d
+	case bson.M:
+		return mapPairs(d)
+	case map[string]interface{}:
+		return mapPairs(d)
+	case nil:
+		return nil
+	}
+	t.Fatalf("expected a document, got %T", v)
+	return nil
+}
+
+func mapPairs(m map[string]interface{}) bson.D {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	d := make(bson.D, len(keys))
+	for i, k := range keys {
+		d[i] = bson.E{Key: k, Value: m[k]}
+	}
+	return d
+}
+
+func asMap(t *testing.T, v interface{}) bson.M {
+	t.Helper()
+
+	m := bson.M{}
+	for _, f := range asPairs(t, v) {
+		m[f.Key] = f.Value
+	}
+	return m
+}
+
+// asList converts any slice (bson.A, []bson.M, []string, mongo.Pipeline...) to []interface{}
+func asList(t *testing.T, v interface{}) []interface{} {
+	t.Helper()
+
+	if v == nil {
+		return nil
+	}
+	if reflect.ValueOf(v).Kind() != reflect.Slice {
+		t.Fatalf("expected a list, got %T", v)
+	}
+	return reflectList(v)
+}
+
+func isListValue(v interface{}) bool {
+	if _, ok := v.(bson.D); ok {
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	return rv.IsValid() && rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8
+}
+
+// isOperatorDoc reports whether v is a document whose keys are all operators
+func isOperatorDoc(v interface{}) bool {
+	var keys []string
+	switch d := v.(type) {
+	case bson.M:
+		for k := range d {
+			keys = append(keys, k)
+		}
+	case bson.D:
+		for _, e := range d {
+			keys = append(keys, e.Key)
+		}
+	case map[string]interface{}:
+		for k := range d {
+			keys = append(keys, k)
+		}
+	default:
+		return false
+	}
+	for _, k := range keys {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+	return len(keys) > 0
+}
+
+func isExclusion(v interface{}) bool {
+	switch x := v.(type) {
+	case bool:
+		return !x
+	case int, int32, int64, float64:
+		return fmt.Sprint(x) == "0"
+	}
+	return false
+}
+
+func isInclusion(v interface{}) bool {
+	switch x := v.(type) {
+	case bool:
+		return x
+	case int, int32, int64, float64:
+		return fmt.Sprint(x) != "0"
+	}
+	return false
+}
+
+// anyElement applies fn to value, or to each element when value is an array, matching
+// Mongo's array semantics for query conditions
+func anyElement(value interface{}, fn func(interface{}) bool) bool {
+	if list, ok := value.(bson.A); ok {
+		for _, v := range list {
+			if fn(v) {
+				return true
+			}
+		}
+		return fn(value)
+	}
+	return fn(value)
+}
+
+func truthy(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	}
+	if isNumber(v) {
+		return toFloatOK(v) != 0
+	}
+	return true
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+	return false
+}
+
+func toFloatOK(v interface{}) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int8:
+		return float64(n)
+	case int16:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint:
+		return float64(n)
+	case uint8:
+		return float64(n)
+	case uint16:
+		return float64(n)
+	case uint32:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	case float32:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+func toFloat(t *testing.T, v interface{}) float64 {
+	t.Helper()
+
+	if v == nil {
+		return 0
+	}
+	if !isNumber(v) {
+		t.Fatalf("expected a number, got %T (%v)", v, v)
+	}
+	return toFloatOK(v)
+}
+
+// typeRank orders values of different types the way Mongo's comparison order does
+func typeRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case string:
+		return 2
+	case bson.M, bson.D, map[string]interface{}:
+		return 3
+	case bson.A:
+		return 4
+	case bson.ObjectID:
+		return 5
+	case bool:
+		return 6
+	case bson.DateTime, time.Time:
+		return 7
+	}
+	if isNumber(v) {
+		return 1
+	}
+	return 8
+}
+
+func compareValues(a, b interface{}) int {
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+	switch x := a.(type) {
+	case string:
+		return strings.Compare(x, b.(string))
+	case bool:
+		switch {
+		case x == b.(bool):
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	case bson.ObjectID:
+		return strings.Compare(x.Hex(), b.(bson.ObjectID).Hex())
+	case bson.DateTime, time.Time:
+		return asTime(a).Compare(asTime(b))
+	}
+	if ra == 3 {
+		return compareDocs(docPairs(a), docPairs(b))
+	}
+	if ra == 1 {
+		fa, fb := toFloatOK(a), toFloatOK(b)
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(keyString(a), keyString(b))
+}
+
+// compareDocs compares documents field by field, name then value, the way MongoDB does
+func compareDocs(a, b bson.D) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := strings.Compare(a[i].Key, b[i].Key); c != 0 {
+			return c
+		}
+		if c := compareValues(a[i].Value, b[i].Value); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+// docPairs returns a document's fields, in order for a bson.D and by key otherwise
+func docPairs(v interface{}) bson.D {
+	switch d := v.(type) {
+	case bson.D:
+		return d
+	case bson.M:
+		return mapPairs(d)
+	case map[string]interface{}:
+		return mapPairs(d)
+	}
+	return nil
+}
+
+func equalValues(a, b interface{}) bool {
+	if typeRank(a) != typeRank(b) {
+		return false
+	}
+	return compareValues(a, b) == 0
+}
+
+func asTime(v interface{}) time.Time {
+	if dt, ok := v.(bson.DateTime); ok {
+		return dt.Time()
+	}
+	return v.(time.Time)
+}
+
+// keyString renders v so equal values (including numbers of different types) match
+func keyString(v interface{}) string {
+	switch x := v.(type) {
+	case bson.M:
+		return keyString(mapPairs(x))
+	case map[string]interface{}:
+		return keyString(mapPairs(x))
+	case bson.D:
+		parts := make([]string, len(x))
+		for i, e := range x {
+			parts[i] = e.Key + ":" + keyString(e.Value)
+		}
+		return "{" + strings.Join(parts, ",") + "}"
+	case bson.A:
+		parts := make([]string, len(x))
+		for i, e := range x {
+			parts[i] = keyString(e)
+		}
+		return "[" + strings.Join(parts, ",") + "]"
+	case bson.DateTime, time.Time:
+		return asTime(v).UTC().String()
+	}
+	if isNumber(v) {
+		return fmt.Sprintf("n%v", toFloatOK(v))
+	}
+	return fmt.Sprintf("%T:%v", v, v)
+}
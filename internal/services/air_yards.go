@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ReceiverAirYards measures how far downfield a receiver is targeted
+type ReceiverAirYards struct {
+	NFLID         string  `json:"nfl_id"`
+	Season        int     `json:"season"`
+	Targets       int     `json:"targets"`
+	AirYards      int     `json:"air_yards"`
+	ADOT          float64 `json:"adot"` // Average depth of target
+	TeamAirYards  int     `json:"team_air_yards"`
+	AirYardsShare float64 `json:"air_yards_share"` // Share of the team's air yards, 0-1
+}
+
+// teamTargets is a receiver's targets for one team and the games they came in
+type teamTargets struct {
+	Team     string   `bson:"_id"`
+	AirYards int      `bson:"air_yards"`
+	Targets  int      `bson:"targets"`
+	GameIDs  []string `bson:"game_ids"`
+}
+
+// GetReceiverAirYards totals a receiver's air yards on pass targets for a season and their
+// share of their team's air yards. The team total only counts games the receiver was
+// targeted in, so a player who changed teams is measured against each team's air yards
+// only for the games played for it.
+func (s *DataService) GetReceiverAirYards(ctx context.Context, nflID string, season int) (*ReceiverAirYards, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"season":             season,
+			"receiver_player_id": nflID,
+			"play_type":          "pass",
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$possession_team",
+			"air_yards": bson.M{"$sum": "$air_yards"},
+			"targets":   bson.M{"$sum": 1},
+			"game_ids":  bson.M{"$addToSet": "$game_id"},
+		}}},
+	}
+
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate receiver air yards: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var byTeam []teamTargets
+	if err := cursor.All(ctx, &byTeam); err != nil {
+		return nil, fmt.Errorf("failed to decode receiver air yards: %w", err)
+	}
+
+	result := summarizeAirYards(nflID, season, byTeam)
+	if len(byTeam) == 0 {
+		return result, nil
+	}
+
+	teamPipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"season":    season,
+			"play_type": "pass",
+			"$or":       teamGamesFilter(byTeam),
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       nil,
+			"air_yards": bson.M{"$sum": "$air_yards"},
+		}}},
+	}
+
+	teamCursor, err := s.db.Collection("plays").Aggregate(ctx, teamPipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate team air yards: %w", err)
+	}
+	defer teamCursor.Close(ctx)
+
+	var teamTotals []struct {
+		AirYards int `bson:"air_yards"`
+	}
+	if err := teamCursor.All(ctx, &teamTotals); err != nil {
+		return nil, fmt.Errorf("failed to decode team air yards: %w", err)
+	}
+
+	if len(teamTotals) > 0 && teamTotals[0].AirYards > 0 {
+		result.TeamAirYards = teamTotals[0].AirYards
+		result.AirYardsShare = float64(result.AirYards) / float64(result.TeamAirYards)
+	}
+	return result, nil
+}
+
+// summarizeAirYards totals a receiver's per-team targets into season air yards and aDOT
+func summarizeAirYards(nflID string, season int, byTeam []teamTargets) *ReceiverAirYards {
+	result := &ReceiverAirYards{NFLID: nflID, Season: season}
+	for _, t := range byTeam {
+		result.AirYards += t.AirYards
+		result.Targets += t.Targets
+	}
+	if result.Targets > 0 {
+		result.ADOT = float64(result.AirYards) / float64(result.Targets)
+	}
+	return result
+}
+
+// teamGamesFilter matches each team's plays in the games the receiver was targeted for it
+func teamGamesFilter(byTeam []teamTargets) bson.A {
+	filter := make(bson.A, 0, len(byTeam))
+	for _, t := range byTeam {
+		filter = append(filter, bson.M{
+			"possession_team": t.Team,
+			"game_id":         bson.M{"$in": t.GameIDs},
+		})
+	}
+	return filter
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestSummarizeAirYards(t *testing.T) {
+	tests := []struct {
+		name        string
+		byTeam      []teamTargets
+		wantAir     int
+		wantTargets int
+		wantADOT    float64
+	}{
+		{"no targets", nil, 0, 0, 0},
+		// Targets at 2, 8, 15 and 35 yards downfield
+		{"deep threat", []teamTargets{{Team: "MIA", AirYards: 60, Targets: 4}}, 60, 4, 15},
+		// A screen (-3) and checkdowns lower the depth
+		{"underneath role", []teamTargets{{Team: "ATL", AirYards: 9, Targets: 3}}, 9, 3, 3},
+		{"traded mid-season", []teamTargets{
+			{Team: "NYJ", AirYards: 120, Targets: 10},
+			{Team: "LV", AirYards: 60, Targets: 10},
+		}, 180, 20, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarizeAirYards("00-0036900", 2025, tt.byTeam)
+			if got.AirYards != tt.wantAir || got.Targets != tt.wantTargets || math.Abs(got.ADOT-tt.wantADOT) > 1e-9 {
+				t.Errorf("summarizeAirYards() = %d air yards on %d targets, aDOT %v; want %d on %d, aDOT %v",
+					got.AirYards, got.Targets, got.ADOT, tt.wantAir, tt.wantTargets, tt.wantADOT)
+			}
+		})
+	}
+}
+
+func TestTeamGamesFilter(t *testing.T) {
+	byTeam := []teamTargets{
+		{Team: "NYJ", GameIDs: []string{"2025_01_NYJ_PIT", "2025_02_BUF_NYJ"}},
+		{Team: "LV", GameIDs: []string{"2025_09_LV_JAX"}},
+	}
+	want := bson.A{
+		bson.M{"possession_team": "NYJ", "game_id": bson.M{"$in": []string{"2025_01_NYJ_PIT", "2025_02_BUF_NYJ"}}},
+		bson.M{"possession_team": "LV", "game_id": bson.M{"$in": []string{"2025_09_LV_JAX"}}},
+	}
+	if got := teamGamesFilter(byTeam); !reflect.DeepEqual(got, want) {
+		t.Errorf("teamGamesFilter() = %v, want %v", got, want)
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/prompts"
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/ai-atl/nfl-platform/internal/teams"
 	"github.com/ai-atl/nfl-platform/pkg/gemini"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -15,19 +19,66 @@ import (
 )
 
 type ChatbotService struct {
-	db          *mongo.Database
-	gemini      *gemini.Client
-	dataService *DataService
+	db           *mongo.Database
+	gemini       *gemini.Client
+	intent       *gemini.Client // Fast tier for structured intent extraction
+	dataService  *DataService
+	history      chatStore
+	historyTurns int // number of previous turns included in the prompt
 }
 
-func NewChatbotService(db *mongo.Database) *ChatbotService {
+func NewChatbotService(db *mongo.Database, historyTurns int) *ChatbotService {
 	return &ChatbotService{
-		db:          db,
-		gemini:      gemini.NewClient(),
-		dataService: NewDataService(db),
+		db:           db,
+		gemini:       gemini.NewClient(),
+		intent:       gemini.NewClient(gemini.FastTier()),
+		dataService:  NewDataService(db),
+		history:      mongoChatStore{collection: db.Collection("chat_messages")},
+		historyTurns: historyTurns,
 	}
 }
 
+// chatStore keeps chat messages. Latest returns a user's messages newest first.
+type chatStore interface {
+	Insert(ctx context.Context, messages []models.ChatMessage) error
+	Count(ctx context.Context, userID bson.ObjectID) (int64, error)
+	Latest(ctx context.Context, userID bson.ObjectID, skip, limit int64) ([]models.ChatMessage, error)
+}
+
+// mongoChatStore keeps messages in chat_messages, one document per message
+type mongoChatStore struct {
+	collection *mongo.Collection
+}
+
+func (s mongoChatStore) Insert(ctx context.Context, messages []models.ChatMessage) error {
+	_, err := s.collection.InsertMany(ctx, messages)
+	return err
+}
+
+func (s mongoChatStore) Count(ctx context.Context, userID bson.ObjectID) (int64, error) {
+	return s.collection.CountDocuments(ctx, bson.M{"user_id": userID})
+}
+
+func (s mongoChatStore) Latest(ctx context.Context, userID bson.ObjectID, skip, limit int64) ([]models.ChatMessage, error) {
+	// _id breaks timestamp ties so a question always sorts before its response
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+
+	cursor, err := s.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []models.ChatMessage{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
 // QueryIntent represents what data the user is asking about
 type QueryIntent struct {
 	PlayerNames []string `json:"player_names"`
@@ -40,9 +91,45 @@ type QueryIntent struct {
 
 // Ask handles a question from the user and returns an AI-generated response
 func (s *ChatbotService) Ask(ctx context.Context, userID string, question string) (string, error) {
+	prompt, err := s.preparePrompt(ctx, userID, question)
+	if err != nil {
+		return "", err
+	}
+
+	// Get AI response
+	response, err := s.gemini.GenerateWithRetry(ctx, prompt, 3)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate response: %w", err)
+	}
+
+	if err := s.SaveTurn(ctx, userID, question, response); err != nil {
+		log.Printf("Failed to save chat history for user %s: %v", userID, err)
+	}
+
+	return response, nil
+}
+
+// AskStream handles a question from the user and streams the AI-generated response
+// in chunks as they arrive. Cancelling ctx stops generation. Callers are responsible
+// for persisting the completed turn with SaveTurn.
+func (s *ChatbotService) AskStream(ctx context.Context, userID string, question string) (<-chan string, <-chan error) {
+	prompt, err := s.preparePrompt(ctx, userID, question)
+	if err != nil {
+		chunks := make(chan string)
+		errs := make(chan error, 1)
+		close(chunks)
+		errs <- err
+		close(errs)
+		return chunks, errs
+	}
+	return s.gemini.GenerateStream(ctx, prompt)
+}
+
+// preparePrompt gathers lineup and database context for a question and builds the chatbot prompt
+func (s *ChatbotService) preparePrompt(ctx context.Context, userID string, question string) (string, error) {
 	// Get user's lineup context
 	objID, _ := bson.ObjectIDFromHex(userID)
-	
+
 	var lineups []models.FantasyLineup
 	cursor, err := s.db.Collection("lineups").Find(ctx, bson.M{"user_id": objID})
 	if err == nil {
@@ -66,69 +153,265 @@ func (s *ChatbotService) Ask(ctx context.Context, userID string, question string
 		}
 	}
 
+	// Load recent conversation turns so follow-up questions have context
+	history, err := s.recentHistory(ctx, objID)
+	if err != nil {
+		log.Printf("Failed to load chat history for user %s: %v", userID, err)
+	}
+
 	// Build context-aware prompt with database stats
-	prompt := s.buildChatbotPrompt(question, lineups, statsContext)
+	return s.buildChatbotPrompt(question, lineups, statsContext, history)
+}
 
-	// Get AI response
-	response, err := s.gemini.GenerateWithRetry(ctx, prompt, 3)
+// SaveTurn persists a completed turn to chat_messages as a user message followed by a model message
+func (s *ChatbotService) SaveTurn(ctx context.Context, userID string, question string, response string) error {
+	objID, err := bson.ObjectIDFromHex(userID)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate response: %w", err)
+		return fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	return response, nil
-}
+	now := time.Now()
+	messages := []models.ChatMessage{
+		{ID: bson.NewObjectID(), UserID: objID, Role: models.ChatRoleUser, Content: question, Timestamp: now},
+		{ID: bson.NewObjectID(), UserID: objID, Role: models.ChatRoleModel, Content: response, Timestamp: now},
+	}
 
-// extractQueryIntent uses AI to extract what data the user is asking about
-func (s *ChatbotService) extractQueryIntent(ctx context.Context, question string) (*QueryIntent, error) {
-	extractionPrompt := fmt.Sprintf(`Analyze this fantasy football question and extract the data requirements.
-Return ONLY a valid JSON object with this structure:
-{
-  "player_names": ["name1", "name2"],
-  "teams": ["team1"],
-  "positions": ["QB", "RB"],
-  "stat_types": ["passing", "rushing", "receiving", "epa"],
-  "needs_data": true
+	if err := s.history.Insert(ctx, messages); err != nil {
+		return fmt.Errorf("failed to save chat messages: %w", err)
+	}
+
+	return nil
 }
 
-Rules:
-- player_names: Full player names mentioned (e.g., ["Patrick Mahomes", "Travis Kelce"])
-- teams: Team abbreviations (e.g., ["KC", "BUF"])
-- positions: Positions mentioned (QB, RB, WR, TE, K, DEF)
-- stat_types: Types of stats (passing, rushing, receiving, epa, injuries)
-- season: Year mentioned or 2024 if current season, 2025 if future
-- needs_data: true if specific players/teams/stats mentioned, false for general questions
+// GetHistory returns a page of the user's chat messages (newest first) and the total message count
+func (s *ChatbotService) GetHistory(ctx context.Context, userID string, page, limit int) ([]models.ChatMessage, int64, error) {
+	objID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid user ID: %w", err)
+	}
 
-Question: %s
+	total, err := s.history.Count(ctx, objID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count chat messages: %w", err)
+	}
 
-Return only the JSON object, no explanation.`, question)
+	messages, err := s.history.Latest(ctx, objID, int64((page-1)*limit), int64(limit))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch chat messages: %w", err)
+	}
+
+	return messages, total, nil
+}
+
+// recentHistory returns the messages of the last historyTurns turns for the user in
+// chronological order
+func (s *ChatbotService) recentHistory(ctx context.Context, userID bson.ObjectID) ([]models.ChatMessage, error) {
+	if s.historyTurns <= 0 {
+		return nil, nil
+	}
 
-	response, err := s.gemini.Generate(ctx, extractionPrompt)
+	messages, err := s.history.Latest(ctx, userID, 0, int64(2*s.historyTurns))
 	if err != nil {
 		return nil, err
 	}
 
-	// Clean up response to extract JSON
-	response = strings.TrimSpace(response)
-	response = strings.Trim(response, "`")
-	if strings.HasPrefix(response, "json") {
-		response = strings.TrimPrefix(response, "json")
-		response = strings.TrimSpace(response)
+	// Reverse so the oldest message comes first in the prompt
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	// Drop a response whose question fell outside the window
+	if len(messages) > 0 && messages[0].Role != models.ChatRoleUser {
+		messages = messages[1:]
+	}
+
+	return messages, nil
+}
+
+// extractQueryIntent uses AI to extract what data the user is asking about
+func (s *ChatbotService) extractQueryIntent(ctx context.Context, question string) (*QueryIntent, error) {
+	extractionPrompt, err := prompts.Render(prompts.ChatIntent, struct{ Question string }{question})
+	if err != nil {
+		log.Printf("Intent prompt failed, falling back to keyword matching: %v", err)
+		return keywordIntent(question), nil
+	}
+
+	response, err := s.intent.Generate(ctx, extractionPrompt)
+	if err != nil {
+		log.Printf("Intent extraction failed, falling back to keyword matching: %v", err)
+		return keywordIntent(question), nil
+	}
+
+	// Models often wrap the JSON in code fences or commentary, so pull out the object itself
+	jsonBlock, ok := extractJSONObject(response)
+	if !ok {
+		log.Printf("No JSON object in intent response, falling back to keyword matching. Raw response: %q", response)
+		return keywordIntent(question), nil
 	}
 
 	var intent QueryIntent
-	if err := json.Unmarshal([]byte(response), &intent); err != nil {
-		return nil, fmt.Errorf("failed to parse intent JSON: %w", err)
+	if err := json.Unmarshal([]byte(jsonBlock), &intent); err != nil {
+		log.Printf("Failed to parse intent JSON (%v), falling back to keyword matching. Raw response: %q", err, response)
+		return keywordIntent(question), nil
 	}
 
 	return &intent, nil
 }
 
+// extractJSONObject returns the first balanced {...} block in s, ignoring braces inside JSON strings
+func extractJSONObject(s string) (string, bool) {
+	start := strings.Index(s, "{")
+	if start == -1 {
+		return "", false
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+	for i := start; i < len(s); i++ {
+		ch := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch ch {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return s[start : i+1], true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// intentKeywords maps question keywords to the stat types they imply, in the order the
+// stat types are reported. A keyword matches the start of a word, so "rush" matches
+// "rushing" but "pass" doesn't match "bypass".
+var intentKeywords = []struct {
+	keyword  string
+	statType string
+}{
+	{"pass", "passing"},
+	{"throw", "passing"},
+	{"rush", "rushing"},
+	{"carries", "rushing"},
+	{"run", "rushing"},
+	{"receiv", "receiving"},
+	{"target", "receiving"},
+	{"catch", "receiving"},
+	{"epa", "epa"},
+	{"efficien", "epa"},
+	{"injur", "injuries"},
+	{"hurt", "injuries"},
+	{"questionab", "injuries"},
+}
+
+// keywordIntent builds a best-effort QueryIntent from the raw question when AI extraction fails
+func keywordIntent(question string) *QueryIntent {
+	intent := &QueryIntent{}
+	tokens := strings.FieldsFunc(question, func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '\'')
+	})
+
+	// Positions and team abbreviations are matched as standalone upper-case tokens
+	for _, token := range tokens {
+		upper := strings.ToUpper(token)
+		switch upper {
+		case "QB", "RB", "WR", "TE", "K", "DEF":
+			if token == upper {
+				intent.Positions = append(intent.Positions, upper)
+			}
+			continue
+		}
+		if token == upper && len(token) >= 2 && len(token) <= 3 {
+			if team := teams.Normalize(upper); knownTeams[team] {
+				intent.Teams = append(intent.Teams, team)
+			}
+		}
+	}
+
+	seenStat := make(map[string]bool)
+	for _, kw := range intentKeywords {
+		if !seenStat[kw.statType] && hasKeyword(tokens, kw.keyword) {
+			seenStat[kw.statType] = true
+			intent.StatTypes = append(intent.StatTypes, kw.statType)
+		}
+	}
+
+	// Consecutive capitalized words (e.g. "Patrick Mahomes") are treated as player names
+	words := strings.Fields(question)
+	var current []string
+	flush := func() {
+		if len(current) >= 2 {
+			intent.PlayerNames = append(intent.PlayerNames, strings.Join(current, " "))
+		}
+		current = nil
+	}
+	for _, w := range words {
+		w = strings.Trim(w, "?,.!:;\"()")
+		if len(w) > 1 && w[0] >= 'A' && w[0] <= 'Z' && w != strings.ToUpper(w) && !questionWords[w] {
+			current = append(current, strings.TrimSuffix(strings.TrimSuffix(w, "'s"), "’s"))
+			continue
+		}
+		flush()
+	}
+	flush()
+
+	intent.NeedsData = len(intent.PlayerNames) > 0 || len(intent.Teams) > 0 || len(intent.Positions) > 0
+	return intent
+}
+
+// hasKeyword reports whether a word in tokens starts with keyword. "running back" names a
+// position rather than the running game, so it doesn't count as "run".
+func hasKeyword(tokens []string, keyword string) bool {
+	for i, token := range tokens {
+		token = strings.ToLower(token)
+		if !strings.HasPrefix(token, keyword) {
+			continue
+		}
+		if strings.HasPrefix(token, "running") && i+1 < len(tokens) && strings.HasPrefix(strings.ToLower(tokens[i+1]), "back") {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// questionWords are capitalized words that start questions rather than player names
+var questionWords = map[string]bool{
+	"Who": true, "What": true, "When": true, "Where": true, "Why": true, "How": true,
+	"Should": true, "Is": true, "Are": true, "Will": true, "Can": true, "Do": true, "Does": true,
+	"Start": true, "Sit": true, "Compare": true, "Tell": true,
+}
+
+// knownTeams lists current NFL team abbreviations for keyword intent matching
+var knownTeams = map[string]bool{
+	"ARI": true, "ATL": true, "BAL": true, "BUF": true, "CAR": true, "CHI": true, "CIN": true, "CLE": true,
+	"DAL": true, "DEN": true, "DET": true, "GB": true, "HOU": true, "IND": true, "JAX": true, "KC": true,
+	"LA": true, "LAC": true, "LV": true, "MIA": true, "MIN": true, "NE": true, "NO": true, "NYG": true,
+	"NYJ": true, "PHI": true, "PIT": true, "SEA": true, "SF": true, "TB": true, "TEN": true, "WAS": true,
+}
+
 // retrieveRelevantStats fetches stats from MongoDB based on query intent
 func (s *ChatbotService) retrieveRelevantStats(ctx context.Context, intent *QueryIntent) (string, error) {
 	var statsBuilder strings.Builder
 	statsBuilder.WriteString("\n=== RELEVANT DATABASE STATS ===\n\n")
 
-	currentSeason := time.Now().Year()
+	currentSeason := season.Season(ctx)
 	if intent.Season == 0 {
 		intent.Season = currentSeason
 	}
@@ -136,7 +419,7 @@ func (s *ChatbotService) retrieveRelevantStats(ctx context.Context, intent *Quer
 	// Fetch player-specific data
 	for _, playerName := range intent.PlayerNames {
 		// Try to find player by name
-		players, err := s.findPlayersByName(ctx, playerName, intent.Season)
+		players, err := s.dataService.SearchPlayers(ctx, playerName, 1)
 		if err != nil || len(players) == 0 {
 			continue
 		}
@@ -158,7 +441,7 @@ func (s *ChatbotService) retrieveRelevantStats(ctx context.Context, intent *Quer
 		}
 
 		// Get season stats
-		stats, err := s.dataService.GetPlayerStats(ctx, player.NFLID, intent.Season)
+		stats, err := s.dataService.GetPlayerStats(ctx, player.NFLID, intent.Season, models.SeasonTypeCombined)
 		if err == nil && len(stats) > 0 {
 			for _, stat := range stats {
 				statsBuilder.WriteString(fmt.Sprintf("- **%d %s Stats**:\n", stat.Season, stat.SeasonType))
@@ -244,29 +527,6 @@ func (s *ChatbotService) retrieveRelevantStats(ctx context.Context, intent *Quer
 	return result, nil
 }
 
-// findPlayersByName searches for players by name (case-insensitive partial match)
-func (s *ChatbotService) findPlayersByName(ctx context.Context, name string, season int) ([]models.Player, error) {
-	// Try exact match first
-	var players []models.Player
-	opts := options.Find().SetLimit(5)
-	
-	cursor, err := s.db.Collection("players").Find(ctx, bson.M{
-		"name":   bson.M{"$regex": name, "$options": "i"},
-		"season": season,
-	}, opts)
-	
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	if err := cursor.All(ctx, &players); err != nil {
-		return nil, err
-	}
-
-	return players, nil
-}
-
 // containsStatType checks if a stat type is in the list
 func (s *ChatbotService) containsStatType(statTypes []string, target string) bool {
 	for _, st := range statTypes {
@@ -277,41 +537,24 @@ func (s *ChatbotService) containsStatType(statTypes []string, target string) boo
 	return true // Default to including all stats if not specified
 }
 
-func (s *ChatbotService) buildChatbotPrompt(question string, lineups []models.FantasyLineup, statsContext string) string {
+func (s *ChatbotService) buildChatbotPrompt(question string, lineups []models.FantasyLineup, statsContext string, history []models.ChatMessage) (string, error) {
 	contextInfo := "No lineup information available."
 	if len(lineups) > 0 {
 		// Get the most recent lineup
 		lineup := lineups[len(lineups)-1]
-		contextInfo = fmt.Sprintf("User's current lineup: %v", lineup.Positions)
-	}
-
-	// Add database stats context if available
-	dataContext := ""
-	if statsContext != "" {
-		dataContext = fmt.Sprintf("\n\nDatabase Stats:\n%s", statsContext)
+		slots := make([]string, 0, len(lineup.Slots))
+		for _, slot := range lineup.Slots {
+			slots = append(slots, slot.Slot+": "+slot.PlayerID)
+		}
+		contextInfo = fmt.Sprintf("User's current lineup: %s", strings.Join(slots, ", "))
 	}
 
-	return fmt.Sprintf(`You are an expert NFL fantasy football advisor with access to advanced EPA metrics and player data.
-
-User Context:
-%s%s
-
-User Question: %s
-
-Provide specific, actionable fantasy football advice based on:
-1. The actual stats from our database shown above
-2. Recent player performance and trends
-3. Matchup analysis
-4. Injury reports
-5. Advanced metrics (EPA, target share, snap counts)
-
-IMPORTANT: Reference the specific stats provided from our database in your answer. Use the actual numbers shown.
-
-Be conversational but data-driven. Explain your reasoning.`,
-		contextInfo,
-		dataContext,
-		question,
-	)
+	return prompts.Render(prompts.ChatAnswer, struct {
+		Context  string
+		Stats    string
+		History  []models.ChatMessage
+		Question string
+	}{contextInfo, statsContext, history, question})
 }
 
 // isInjuryStatus returns true if the status code represents an actual injury or inactive status
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// memChatStore is an in-memory chatStore sorted the way mongoChatStore sorts
+type memChatStore struct {
+	messages []models.ChatMessage
+}
+
+func (s *memChatStore) Insert(ctx context.Context, messages []models.ChatMessage) error {
+	s.messages = append(s.messages, messages...)
+	return nil
+}
+
+func (s *memChatStore) Count(ctx context.Context, userID bson.ObjectID) (int64, error) {
+	var n int64
+	for _, m := range s.messages {
+		if m.UserID == userID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *memChatStore) Latest(ctx context.Context, userID bson.ObjectID, skip, limit int64) ([]models.ChatMessage, error) {
+	var mine []models.ChatMessage
+	for _, m := range s.messages {
+		if m.UserID == userID {
+			mine = append(mine, m)
+		}
+	}
+	slices.SortFunc(mine, func(a, b models.ChatMessage) int {
+		if c := b.Timestamp.Compare(a.Timestamp); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID.Hex(), a.ID.Hex())
+	})
+	if skip > int64(len(mine)) {
+		skip = int64(len(mine))
+	}
+	mine = mine[skip:]
+	if limit < int64(len(mine)) {
+		mine = mine[:limit]
+	}
+	return mine, nil
+}
+
+// seedTurns saves n turns for the user, one minute apart, and returns the store
+func seedTurns(t *testing.T, svc *ChatbotService, userID string, n int) *memChatStore {
+	t.Helper()
+	store := &memChatStore{}
+	svc.history = store
+	for i := 1; i <= n; i++ {
+		if err := svc.SaveTurn(context.Background(), userID, fmt.Sprintf("q%d", i), fmt.Sprintf("a%d", i)); err != nil {
+			t.Fatalf("SaveTurn() error = %v", err)
+		}
+	}
+	// Space the turns out so ordering doesn't rely on the clock's resolution
+	base := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	for i := range store.messages {
+		store.messages[i].Timestamp = base.Add(time.Duration(i/2) * time.Minute)
+	}
+	return store
+}
+
+func contents(messages []models.ChatMessage) []string {
+	out := make([]string, len(messages))
+	for i, m := range messages {
+		out[i] = m.Content
+	}
+	return out
+}
+
+func TestSaveTurnStoresOneMessagePerRole(t *testing.T) {
+	svc := &ChatbotService{}
+	userID := bson.NewObjectID()
+	store := seedTurns(t, svc, userID.Hex(), 1)
+
+	if len(store.messages) != 2 {
+		t.Fatalf("SaveTurn() stored %d messages, want 2", len(store.messages))
+	}
+	for i, want := range []struct{ role, content string }{{models.ChatRoleUser, "q1"}, {models.ChatRoleModel, "a1"}} {
+		got := store.messages[i]
+		if got.Role != want.role || got.Content != want.content || got.UserID != userID {
+			t.Errorf("message %d = {%s %q %s}, want {%s %q %s}", i, got.Role, got.Content, got.UserID.Hex(), want.role, want.content, userID.Hex())
+		}
+	}
+
+	if err := svc.SaveTurn(context.Background(), "not-an-id", "q", "a"); err == nil {
+		t.Error("SaveTurn() with an invalid user ID returned no error")
+	}
+}
+
+func TestRecentHistory(t *testing.T) {
+	userID := bson.NewObjectID()
+
+	tests := []struct {
+		name  string
+		turns int
+		saved int
+		want  []string
+	}{
+		{"last N turns oldest first", 2, 4, []string{"q3", "a3", "q4", "a4"}},
+		{"fewer turns than the window", 5, 2, []string{"q1", "a1", "q2", "a2"}},
+		{"no history", 3, 0, []string{}},
+		{"disabled", 0, 3, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &ChatbotService{historyTurns: tt.turns}
+			store := seedTurns(t, svc, userID.Hex(), tt.saved)
+			// Another user's turns must not leak into the prompt
+			store.messages = append(store.messages, models.ChatMessage{ID: bson.NewObjectID(), UserID: bson.NewObjectID(), Role: models.ChatRoleUser, Content: "other"})
+
+			got, err := svc.recentHistory(context.Background(), userID)
+			if err != nil {
+				t.Fatalf("recentHistory() error = %v", err)
+			}
+			if !slices.Equal(contents(got), tt.want) {
+				t.Errorf("recentHistory() = %v, want %v", contents(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestRecentHistoryDropsOrphanedResponse(t *testing.T) {
+	userID := bson.NewObjectID()
+	svc := &ChatbotService{historyTurns: 1}
+	store := seedTurns(t, svc, userID.Hex(), 1)
+	// A second question whose response was never saved shifts the window by one message
+	store.messages = append(store.messages, models.ChatMessage{ID: bson.NewObjectID(), UserID: userID, Role: models.ChatRoleUser, Content: "q2", Timestamp: time.Date(2025, 11, 1, 13, 0, 0, 0, time.UTC)})
+
+	got, err := svc.recentHistory(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("recentHistory() error = %v", err)
+	}
+	if want := []string{"q2"}; !slices.Equal(contents(got), want) {
+		t.Errorf("recentHistory() = %v, want %v", contents(got), want)
+	}
+}
+
+func TestGetHistoryPagination(t *testing.T) {
+	userID := bson.NewObjectID()
+	svc := &ChatbotService{}
+	seedTurns(t, svc, userID.Hex(), 3)
+
+	tests := []struct {
+		page, limit int
+		want        []string
+	}{
+		{1, 4, []string{"a3", "q3", "a2", "q2"}},
+		{2, 4, []string{"a1", "q1"}},
+		{3, 4, []string{}},
+		{2, 2, []string{"a2", "q2"}},
+	}
+
+	for _, tt := range tests {
+		messages, total, err := svc.GetHistory(context.Background(), userID.Hex(), tt.page, tt.limit)
+		if err != nil {
+			t.Fatalf("GetHistory(%d, %d) error = %v", tt.page, tt.limit, err)
+		}
+		if total != 6 {
+			t.Errorf("GetHistory(%d, %d) total = %d, want 6", tt.page, tt.limit, total)
+		}
+		if !slices.Equal(contents(messages), tt.want) {
+			t.Errorf("GetHistory(%d, %d) = %v, want %v", tt.page, tt.limit, contents(messages), tt.want)
+		}
+	}
+}
+
+func TestExtractJSONObject(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"bare object", `{"needs_data": true}`, `{"needs_data": true}`, true},
+		{"code fence", "```json\n{\"teams\": [\"KC\"]}\n```", `{"teams": ["KC"]}`, true},
+		{"prose around", `Here is the intent: {"season": 2025} Let me know if you need more.`, `{"season": 2025}`, true},
+		{"trailing text with braces", `{"player_names": ["Josh Allen"]} {not json}`, `{"player_names": ["Josh Allen"]}`, true},
+		{"nested object", `{"a": {"b": 1}, "c": 2} done`, `{"a": {"b": 1}, "c": 2}`, true},
+		{"braces inside strings", `{"q": "why } so {", "x": "\"}"}`, `{"q": "why } so {", "x": "\"}"}`, true},
+		{"unbalanced", `{"teams": ["KC"]`, "", false},
+		{"no object", "I can't help with that.", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := extractJSONObject(tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("extractJSONObject(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestKeywordIntent(t *testing.T) {
+	tests := []struct {
+		question  string
+		players   []string
+		teams     []string
+		positions []string
+		stats     []string
+		needsData bool
+	}{
+		{
+			question:  "Should I start Patrick Mahomes or Josh Allen this week?",
+			players:   []string{"Patrick Mahomes", "Josh Allen"},
+			needsData: true,
+		},
+		{
+			question:  "Which KC WR gets the most targets?",
+			teams:     []string{"KC"},
+			positions: []string{"WR"},
+			stats:     []string{"receiving"},
+			needsData: true,
+		},
+		{
+			question:  "How efficient is Bijan Robinson's rushing? Is he hurt?",
+			players:   []string{"Bijan Robinson"},
+			stats:     []string{"rushing", "epa", "injuries"},
+			needsData: true,
+		},
+		{
+			// Lower-case "te" and "kc" are ordinary words, not tokens
+			question: "is a te from kc worth it?",
+		},
+		{
+			// Keywords match whole words from their start, and "running back" is a position
+			question:  "Is the KC defense overrun? Can I bypass my running back for a receiver?",
+			teams:     []string{"KC"},
+			stats:     []string{"receiving"},
+			needsData: true,
+		},
+		{
+			question: "Who runs the ball most, and who passes best?",
+			stats:    []string{"passing", "rushing"},
+		},
+	}
+
+	for _, tt := range tests {
+		got := keywordIntent(tt.question)
+		if !slices.Equal(got.PlayerNames, tt.players) {
+			t.Errorf("keywordIntent(%q).PlayerNames = %v, want %v", tt.question, got.PlayerNames, tt.players)
+		}
+		if !slices.Equal(got.Teams, tt.teams) {
+			t.Errorf("keywordIntent(%q).Teams = %v, want %v", tt.question, got.Teams, tt.teams)
+		}
+		if !slices.Equal(got.Positions, tt.positions) {
+			t.Errorf("keywordIntent(%q).Positions = %v, want %v", tt.question, got.Positions, tt.positions)
+		}
+		if !slices.Equal(got.StatTypes, tt.stats) {
+			t.Errorf("keywordIntent(%q).StatTypes = %v, want %v", tt.question, got.StatTypes, tt.stats)
+		}
+		if got.NeedsData != tt.needsData {
+			t.Errorf("keywordIntent(%q).NeedsData = %v, want %v", tt.question, got.NeedsData, tt.needsData)
+		}
+	}
+}
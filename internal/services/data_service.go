@@ -2,10 +2,18 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/teams"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -47,6 +55,7 @@ func (s *DataService) GetPlayer(ctx context.Context, nflID string, season int) (
 
 // GetPlayersByTeam gets all players for a team in a season
 func (s *DataService) GetPlayersByTeam(ctx context.Context, team string, season int) ([]models.Player, error) {
+	team = teams.Normalize(team)
 	cursor, err := s.db.Collection("players").Find(ctx, bson.M{
 		"team":   team,
 		"season": season,
@@ -63,6 +72,83 @@ func (s *DataService) GetPlayersByTeam(ctx context.Context, team string, season
 	return players, nil
 }
 
+// GetPlayerTeamHistory gets a player's team stints in week order (season=0 for all seasons)
+func (s *DataService) GetPlayerTeamHistory(ctx context.Context, nflID string, season int) ([]models.PlayerTeamStint, error) {
+	filter := bson.M{"nfl_id": nflID}
+	if season > 0 {
+		filter["season"] = season
+	}
+
+	cursor, err := s.db.Collection("player_team_history").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "season", Value: 1}, {Key: "from_week", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var stints []models.PlayerTeamStint
+	if err := cursor.All(ctx, &stints); err != nil {
+		return nil, err
+	}
+	return stints, nil
+}
+
+// GetPlayersByTeamForWeek gets the players on a team's roster as of a week, following
+// mid-season moves recorded in player_team_history. Each player's team is set to the one
+// they were on that week. Without team history for the season it falls back to
+// GetPlayersByTeam.
+func (s *DataService) GetPlayersByTeamForWeek(ctx context.Context, team string, season, week int) ([]models.Player, error) {
+	team = teams.Normalize(team)
+	cursor, err := s.db.Collection("player_team_history").Aggregate(ctx, teamRosterForWeekPipeline(team, season, week))
+	if err != nil {
+		return nil, err
+	}
+	var stints []struct {
+		NFLID string `bson:"_id"`
+	}
+	err = cursor.All(ctx, &stints)
+	cursor.Close(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(stints) == 0 {
+		return s.GetPlayersByTeam(ctx, team, season)
+	}
+
+	ids := make([]string, len(stints))
+	for i, st := range stints {
+		ids[i] = st.NFLID
+	}
+	cursor, err = s.db.Collection("players").Find(ctx, bson.M{"nfl_id": bson.M{"$in": ids}, "season": season})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var players []models.Player
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, err
+	}
+	for i := range players {
+		players[i].Team = team
+	}
+	return players, nil
+}
+
+// teamRosterForWeekPipeline returns the IDs of players whose stint covering the week, or
+// the last one before it (e.g. during a bye), is with team
+func teamRosterForWeekPipeline(team string, season, week int) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"season": season, "from_week": bson.M{"$lte": week}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "from_week", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":  "$nfl_id",
+			"team": bson.M{"$first": "$team"},
+		}}},
+		{{Key: "$match", Value: bson.M{"team": team}}},
+	}
+}
+
 // GetPlayersByPosition gets players by position for a season
 func (s *DataService) GetPlayersByPosition(ctx context.Context, position string, season int) ([]models.Player, error) {
 	cursor, err := s.db.Collection("players").Find(ctx, bson.M{
@@ -81,14 +167,23 @@ func (s *DataService) GetPlayersByPosition(ctx context.Context, position string,
 	return players, nil
 }
 
-// GetInjuredPlayers gets players with injury status
-func (s *DataService) GetInjuredPlayers(ctx context.Context, season int) ([]models.Player, error) {
+// injuredStatuses matches inactive players and injury designations (R01 IR, R04 PUP,
+// R48 designated for return, P02 practice squad injured)
+var injuredStatuses = []bson.M{
+	{"status": "INA"},
+	{"status_description_abbr": bson.M{"$in": []string{"R01", "R04", "R48", "P02"}}},
+}
+
+// GetInjuredPlayers gets players with injury status. week=0 uses each player's latest
+// status; otherwise the status they held that week, from player_weekly_status.
+func (s *DataService) GetInjuredPlayers(ctx context.Context, season int, week int) ([]models.Player, error) {
+	if week > 0 {
+		return s.getInjuredPlayersForWeek(ctx, season, week)
+	}
+
 	filter := bson.M{
 		"season": season,
-		"$or": []bson.M{
-			{"status": "INA"},
-			{"status_description_abbr": bson.M{"$in": []string{"R01", "R04", "R48", "P02"}}},
-		},
+		"$or":    injuredStatuses,
 	}
 
 	cursor, err := s.db.Collection("players").Find(ctx, filter)
@@ -104,16 +199,218 @@ func (s *DataService) GetInjuredPlayers(ctx context.Context, season int) ([]mode
 	return players, nil
 }
 
+// getInjuredPlayersForWeek finds the players injured in a given week and returns their
+// roster entries with that week's status and team in place of the latest
+func (s *DataService) getInjuredPlayersForWeek(ctx context.Context, season int, week int) ([]models.Player, error) {
+	filter := bson.M{
+		"season": season,
+		"week":   week,
+		"$or":    injuredStatuses,
+	}
+
+	cursor, err := s.db.Collection("player_weekly_status").Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var statuses []models.PlayerWeeklyStatus
+	if err := cursor.All(ctx, &statuses); err != nil {
+		return nil, err
+	}
+	if len(statuses) == 0 {
+		return []models.Player{}, nil
+	}
+
+	ids := make([]string, 0, len(statuses))
+	for _, st := range statuses {
+		ids = append(ids, st.NFLID)
+	}
+
+	playerCursor, err := s.db.Collection("players").Find(ctx, bson.M{"nfl_id": bson.M{"$in": ids}, "season": season})
+	if err != nil {
+		return nil, err
+	}
+	defer playerCursor.Close(ctx)
+
+	var players []models.Player
+	if err := playerCursor.All(ctx, &players); err != nil {
+		return nil, err
+	}
+
+	applyWeeklyStatuses(players, statuses)
+	return players, nil
+}
+
+// applyWeeklyStatuses replaces each player's latest status and team with the one recorded
+// for the week. Players without a weekly status are left as they are.
+func applyWeeklyStatuses(players []models.Player, statuses []models.PlayerWeeklyStatus) {
+	byID := make(map[string]models.PlayerWeeklyStatus, len(statuses))
+	for _, st := range statuses {
+		byID[st.NFLID] = st
+	}
+
+	for i := range players {
+		st, ok := byID[players[i].NFLID]
+		if !ok {
+			continue
+		}
+		players[i].Status = st.Status
+		players[i].StatusDescriptionAbbr = st.StatusDescriptionAbbr
+		players[i].Week = st.Week
+		if st.Team != "" {
+			players[i].Team = st.Team
+		}
+	}
+}
+
+// GetPlayerStatusForWeek gets a player's roster status for one week of a season
+func (s *DataService) GetPlayerStatusForWeek(ctx context.Context, nflID string, season int, week int) (*models.PlayerWeeklyStatus, error) {
+	var status models.PlayerWeeklyStatus
+	err := s.db.Collection("player_weekly_status").FindOne(ctx, bson.M{
+		"nfl_id": nflID,
+		"season": season,
+		"week":   week,
+	}).Decode(&status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetPlayerInjuryHistory gets a player's official injury report entries, most recent first
+// season=0 returns every season
+func (s *DataService) GetPlayerInjuryHistory(ctx context.Context, nflID string, season int) ([]models.InjuryReport, error) {
+	filter := bson.M{"nfl_id": nflID}
+	if season > 0 {
+		filter["season"] = season
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "season", Value: -1}, {Key: "week", Value: -1}})
+	cursor, err := s.db.Collection("injuries").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reports := []models.InjuryReport{}
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// GetSnapCounts gets a player's offensive snap shares for weeks fromWeek..toWeek, most recent first
+func (s *DataService) GetSnapCounts(ctx context.Context, nflID string, season, fromWeek, toWeek int) ([]models.SnapCount, error) {
+	filter := bson.M{
+		"nfl_id": nflID,
+		"season": season,
+		"week":   bson.M{"$gte": fromWeek, "$lte": toWeek},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "week", Value: -1}})
+	cursor, err := s.db.Collection("snap_counts").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	snaps := []models.SnapCount{}
+	if err := cursor.All(ctx, &snaps); err != nil {
+		return nil, err
+	}
+	return snaps, nil
+}
+
+// SearchPlayers finds players by name, case-insensitively. Prefix matches are
+// returned first; if there are not enough, substring matches fill the rest. Each
+// player appears once, as their most recent season entry. Both searches are
+// case-insensitive regexes, which can't use the name index efficiently.
+func (s *DataService) SearchPlayers(ctx context.Context, query string, limit int) ([]models.Player, error) {
+	return searchPlayers(ctx, query, limit, s.searchPlayersByPattern)
+}
+
+// playerPatternSearch returns the latest-season entry per player whose name matches pattern
+type playerPatternSearch func(ctx context.Context, pattern string, excludeIDs []string, limit int) ([]models.Player, error)
+
+// searchPlayers runs the prefix search and then the substring search with byPattern
+func searchPlayers(ctx context.Context, query string, limit int, byPattern playerPatternSearch) ([]models.Player, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []models.Player{}, nil
+	}
+
+	quoted := regexp.QuoteMeta(query)
+	players, err := byPattern(ctx, "^"+quoted, nil, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(players) >= limit {
+		return players, nil
+	}
+
+	seen := make([]string, 0, len(players))
+	for _, p := range players {
+		seen = append(seen, p.NFLID)
+	}
+
+	more, err := byPattern(ctx, quoted, seen, limit-len(players))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(players, more...), nil
+}
+
+// searchPlayersByPattern returns the latest-season entry per player whose name matches pattern
+func (s *DataService) searchPlayersByPattern(ctx context.Context, pattern string, excludeIDs []string, limit int) ([]models.Player, error) {
+	cursor, err := s.db.Collection("players").Aggregate(ctx, searchPlayersPipeline(pattern, excludeIDs, limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	players := []models.Player{}
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, err
+	}
+	return players, nil
+}
+
+// searchPlayersPipeline keeps each matching player's latest season entry, sorted by name
+func searchPlayersPipeline(pattern string, excludeIDs []string, limit int) mongo.Pipeline {
+	match := bson.M{"name": bson.M{"$regex": pattern, "$options": "i"}}
+	if len(excludeIDs) > 0 {
+		match["nfl_id"] = bson.M{"$nin": excludeIDs}
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "season", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$nfl_id",
+			"doc": bson.M{"$first": "$$ROOT"},
+		}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$doc"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "name", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+}
+
 // ========================================
 // PLAYER STATS QUERIES
 // ========================================
 
 // GetPlayerStats gets seasonal stats for a player
-func (s *DataService) GetPlayerStats(ctx context.Context, nflID string, season int) ([]models.PlayerStats, error) {
+// season=0 returns every season; seasonType="" returns REG, POST and REGPOST entries
+func (s *DataService) GetPlayerStats(ctx context.Context, nflID string, season int, seasonType string) ([]models.PlayerStats, error) {
 	filter := bson.M{"nfl_id": nflID}
 	if season > 0 {
 		filter["season"] = season
 	}
+	if seasonType != "" {
+		filter["season_type"] = seasonType
+	}
 
 	cursor, err := s.db.Collection("player_stats").Find(ctx, filter,
 		options.Find().SetSort(bson.D{{"season", -1}}))
@@ -129,6 +426,38 @@ func (s *DataService) GetPlayerStats(ctx context.Context, nflID string, season i
 	return stats, nil
 }
 
+// GetPlayerStatsBatch fetches one season's stats for many players in a single query,
+// keyed by nfl_id. Players without stats for the season are omitted.
+func (s *DataService) GetPlayerStatsBatch(ctx context.Context, nflIDs []string, season int, seasonType string) (map[string]models.PlayerStats, error) {
+	cursor, err := s.db.Collection("player_stats").Find(ctx, playerStatsBatchFilter(nflIDs, season, seasonType))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var stats []models.PlayerStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+	return statsByPlayer(stats), nil
+}
+
+func playerStatsBatchFilter(nflIDs []string, season int, seasonType string) bson.M {
+	return bson.M{
+		"nfl_id":      bson.M{"$in": nflIDs},
+		"season":      season,
+		"season_type": seasonType,
+	}
+}
+
+func statsByPlayer(stats []models.PlayerStats) map[string]models.PlayerStats {
+	byPlayer := make(map[string]models.PlayerStats, len(stats))
+	for _, stat := range stats {
+		byPlayer[stat.NFLID] = stat
+	}
+	return byPlayer
+}
+
 // GetPlayerWeeklyStats gets weekly stats for a player
 func (s *DataService) GetPlayerWeeklyStats(ctx context.Context, nflID string, season int, week int) ([]models.WeeklyStat, error) {
 	filter := bson.M{"nfl_id": nflID}
@@ -139,261 +468,1047 @@ func (s *DataService) GetPlayerWeeklyStats(ctx context.Context, nflID string, se
 		filter["week"] = week
 	}
 
-	cursor, err := s.db.Collection("player_weekly_stats").Find(ctx, filter,
-		options.Find().SetSort(bson.D{{"season", -1}, {"week", -1}}))
+	cursor, err := s.db.Collection("player_weekly_stats").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{"season", -1}, {"week", -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var weeklyStats []models.WeeklyStat
+	if err := cursor.All(ctx, &weeklyStats); err != nil {
+		return nil, err
+	}
+	return weeklyStats, nil
+}
+
+// GetPlayerWeeklyStatsRange gets a player's weekly stats for one season in week order.
+// fromWeek/toWeek bound the range inclusively (0 = unbounded).
+func (s *DataService) GetPlayerWeeklyStatsRange(ctx context.Context, nflID string, season int, fromWeek int, toWeek int) ([]models.WeeklyStat, error) {
+	filter, opts := weeklyStatsRangeQuery(nflID, season, fromWeek, toWeek)
+	cursor, err := s.db.Collection("player_weekly_stats").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	weeklyStats := []models.WeeklyStat{}
+	if err := cursor.All(ctx, &weeklyStats); err != nil {
+		return nil, err
+	}
+	return weeklyStats, nil
+}
+
+// weeklyStatsRangeQuery selects a player's weeks of a season within the bounds, in week order
+func weeklyStatsRangeQuery(nflID string, season int, fromWeek int, toWeek int) (bson.M, *options.FindOptionsBuilder) {
+	filter := bson.M{
+		"nfl_id": nflID,
+		"season": season,
+	}
+
+	weekFilter := bson.M{}
+	if fromWeek > 0 {
+		weekFilter["$gte"] = fromWeek
+	}
+	if toWeek > 0 {
+		weekFilter["$lte"] = toWeek
+	}
+	if len(weekFilter) > 0 {
+		filter["week"] = weekFilter
+	}
+
+	return filter, options.Find().SetSort(bson.D{{Key: "week", Value: 1}})
+}
+
+// ========================================
+// PLAY-BY-PLAY QUERIES
+// ========================================
+
+// GetPlayerPlays gets a page of the plays involving a player, and whether more follow
+func (s *DataService) GetPlayerPlays(ctx context.Context, playerID string, season int, limit, offset int) ([]models.Play, bool, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"passer_player_id": playerID},
+			{"rusher_player_id": playerID},
+			{"receiver_player_id": playerID},
+		},
+	}
+	if season > 0 {
+		filter["season"] = season
+	}
+
+	return s.pagePlays(ctx, filter, limit, offset)
+}
+
+// GetTeamPlays gets a page of a team's plays on offense and defense, and whether more follow
+func (s *DataService) GetTeamPlays(ctx context.Context, team string, season int, limit, offset int) ([]models.Play, bool, error) {
+	team = teams.Normalize(team)
+	filter := bson.M{
+		"$or": []bson.M{
+			{"possession_team": team},
+			{"defense_team": team},
+		},
+	}
+	if season > 0 {
+		filter["season"] = season
+	}
+
+	return s.pagePlays(ctx, filter, limit, offset)
+}
+
+// pagePlays returns up to limit plays matching filter after skipping offset, in game order
+// (game, then clock, then play id so every play has a fixed position across pages). One
+// extra play is fetched to tell whether another page follows. A limit of 0 returns every
+// play from offset on.
+func (s *DataService) pagePlays(ctx context.Context, filter bson.M, limit, offset int) ([]models.Play, bool, error) {
+	cursor, err := s.db.Collection("plays").Find(ctx, filter, playPageOptions(limit, offset))
+	if err != nil {
+		return nil, false, err
+	}
+	defer cursor.Close(ctx)
+
+	plays := []models.Play{}
+	if err := cursor.All(ctx, &plays); err != nil {
+		return nil, false, err
+	}
+
+	plays, hasMore := trimPlayPage(plays, limit)
+	return plays, hasMore, nil
+}
+
+// playPageOptions sorts plays into game order and fetches one past limit for a page
+func playPageOptions(limit, offset int) *options.FindOptionsBuilder {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "game_id", Value: 1}, {Key: "game_seconds", Value: -1}, {Key: "play_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetAllowDiskUse(true) // A full season of a team's plays can exceed the in-memory sort limit
+	if limit > 0 {
+		opts.SetLimit(int64(limit + 1))
+	}
+	return opts
+}
+
+// trimPlayPage drops the extra play playPageOptions fetched, reporting whether there was one
+func trimPlayPage(plays []models.Play, limit int) ([]models.Play, bool) {
+	hasMore := limit > 0 && len(plays) > limit
+	if hasMore {
+		plays = plays[:limit]
+	}
+	return plays, hasMore
+}
+
+// GetGamePlays gets all plays for a specific game
+func (s *DataService) GetGamePlays(ctx context.Context, gameID string) ([]models.Play, error) {
+	cursor, err := s.db.Collection("plays").Find(ctx, bson.M{"game_id": gameID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var plays []models.Play
+	if err := cursor.All(ctx, &plays); err != nil {
+		return nil, err
+	}
+	return plays, nil
+}
+
+// ========================================
+// EPA CALCULATIONS
+// ========================================
+
+// CalculatePlayerEPA calculates average EPA for a player
+func (s *DataService) CalculatePlayerEPA(ctx context.Context, playerID string, season int) (float64, int, error) {
+	return s.averageEPA(ctx, playerEPAFilter(playerID, season))
+}
+
+// CalculateTeamEPA calculates average EPA for a team's offense
+func (s *DataService) CalculateTeamEPA(ctx context.Context, team string, season int) (float64, int, error) {
+	return s.averageEPA(ctx, teamEPAFilter(team, season))
+}
+
+// playerEPAFilter matches plays where the player passed, rushed or was targeted
+func playerEPAFilter(playerID string, season int) bson.M {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"passer_player_id": playerID},
+			{"rusher_player_id": playerID},
+			{"receiver_player_id": playerID},
+		},
+	}
+	if season > 0 {
+		filter["season"] = season
+	}
+	return filter
+}
+
+// teamEPAFilter matches a team's offensive plays
+func teamEPAFilter(team string, season int) bson.M {
+	filter := bson.M{"possession_team": teams.Normalize(team)}
+	if season > 0 {
+		filter["season"] = season
+	}
+	return filter
+}
+
+// epaTotals is the summed EPA and play count averageEPAPipeline produces
+type epaTotals struct {
+	TotalEPA float64 `bson:"total_epa"`
+	Plays    int     `bson:"plays"`
+}
+
+// average returns the mean EPA per play and the play count
+func (r epaTotals) average() (float64, int) {
+	if r.Plays == 0 {
+		return 0, 0
+	}
+	return r.TotalEPA / float64(r.Plays), r.Plays
+}
+
+// averageEPAPipeline sums EPA and counts the plays matching filter
+func averageEPAPipeline(filter bson.M) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       nil,
+			"total_epa": bson.M{"$sum": "$epa"},
+			"plays":     bson.M{"$sum": 1},
+		}}},
+	}
+}
+
+// averageEPA computes the average EPA and play count for plays matching filter.
+// The aggregation runs in Mongo so full play documents never leave the server.
+func (s *DataService) averageEPA(ctx context.Context, filter bson.M) (float64, int, error) {
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, averageEPAPipeline(filter))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []epaTotals
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, 0, err
+	}
+
+	if len(results) == 0 {
+		return 0, 0, nil
+	}
+
+	avg, plays := results[0].average()
+	return avg, plays, nil
+}
+
+// ========================================
+// TEAM TENDENCIES
+// ========================================
+
+// TendencySplit is play-calling and efficiency for one group of snaps
+type TendencySplit struct {
+	Plays    int     `json:"plays"`
+	PassRate float64 `json:"pass_rate"`
+	EPA      float64 `json:"epa_per_play"`
+}
+
+// SideTendencies describes one side of the ball. For defense, pass rate and EPA are
+// what opponents called and gained against the team.
+type SideTendencies struct {
+	Overall     TendencySplit            `json:"overall"`
+	EarlyDown   TendencySplit            `json:"early_down"` // 1st and 2nd down
+	PassEPA     float64                  `json:"pass_epa_per_play"`
+	RunEPA      float64                  `json:"run_epa_per_play"`
+	BySituation map[string]TendencySplit `json:"by_situation"` // e.g. "1st", "3rd_long"
+}
+
+// TeamTendencies is a team's offensive and defensive play-calling profile for a season
+type TeamTendencies struct {
+	Team    string         `json:"team"`
+	Season  int            `json:"season"`
+	Offense SideTendencies `json:"offense"`
+	Defense SideTendencies `json:"defense"`
+}
+
+// GetTeamTendencies aggregates a team's pass/run plays into pass rate by down and
+// distance, early-down EPA and pass vs run EPA, for its offense and defense
+func (s *DataService) GetTeamTendencies(ctx context.Context, team string, season int) (*TeamTendencies, error) {
+	team = teams.Normalize(team)
+
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, tendenciesPipeline(team, season))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate tendencies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []tendencyGroup
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode tendencies: %w", err)
+	}
+
+	return buildTendencies(team, season, groups), nil
+}
+
+// tendencyGroup is one side/situation/play-type bucket from tendenciesPipeline
+type tendencyGroup struct {
+	ID struct {
+		Offense   bool   `bson:"offense"`
+		Situation string `bson:"situation"`
+		PlayType  string `bson:"play_type"`
+	} `bson:"_id"`
+	Plays int     `bson:"plays"`
+	EPA   float64 `bson:"epa"`
+}
+
+// tendenciesPipeline groups a team's pass and run plays by side of the ball, down and
+// distance situation, and play type
+func tendenciesPipeline(team string, season int) mongo.Pipeline {
+	// Distance buckets: short (1-2), medium (3-6), long (7+); 1st down is one bucket
+	distance := bson.M{"$switch": bson.M{
+		"branches": []bson.M{
+			{"case": bson.M{"$lte": []interface{}{"$yards_to_go", 2}}, "then": "short"},
+			{"case": bson.M{"$lte": []interface{}{"$yards_to_go", 6}}, "then": "medium"},
+		},
+		"default": "long",
+	}}
+	situation := bson.M{"$switch": bson.M{
+		"branches": []bson.M{
+			{"case": bson.M{"$eq": []interface{}{"$down", 1}}, "then": "1st"},
+			{"case": bson.M{"$eq": []interface{}{"$down", 2}}, "then": bson.M{"$concat": []interface{}{"2nd_", distance}}},
+			{"case": bson.M{"$eq": []interface{}{"$down", 3}}, "then": bson.M{"$concat": []interface{}{"3rd_", distance}}},
+		},
+		"default": bson.M{"$concat": []interface{}{"4th_", distance}},
+	}}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"season":    season,
+			"play_type": bson.M{"$in": []string{"pass", "run"}},
+			"down":      bson.M{"$gte": 1},
+			"$or": []bson.M{
+				{"possession_team": team},
+				{"defense_team": team},
+			},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"offense":   bson.M{"$eq": []interface{}{"$possession_team", team}},
+				"situation": situation,
+				"play_type": "$play_type",
+			},
+			"plays": bson.M{"$sum": 1},
+			"epa":   bson.M{"$sum": "$epa"},
+		}}},
+	}
+}
+
+// buildTendencies converts grouped play counts and EPA into pass rates and per-play EPA
+func buildTendencies(team string, season int, groups []tendencyGroup) *TeamTendencies {
+	// Running totals per bucket before converting to rates
+	type tally struct {
+		plays, passes, runs  int
+		epa, passEPA, runEPA float64
+	}
+	add := func(t *tally, playType string, plays int, epa float64) {
+		t.plays += plays
+		t.epa += epa
+		if playType == "pass" {
+			t.passes += plays
+			t.passEPA += epa
+		} else {
+			t.runs += plays
+			t.runEPA += epa
+		}
+	}
+	split := func(t tally) TendencySplit {
+		if t.plays == 0 {
+			return TendencySplit{}
+		}
+		return TendencySplit{
+			Plays:    t.plays,
+			PassRate: float64(t.passes) / float64(t.plays),
+			EPA:      t.epa / float64(t.plays),
+		}
+	}
+
+	var overall, early [2]tally
+	situations := [2]map[string]*tally{{}, {}}
+	for _, g := range groups {
+		side := 1 // defense
+		if g.ID.Offense {
+			side = 0
+		}
+		add(&overall[side], g.ID.PlayType, g.Plays, g.EPA)
+		if g.ID.Situation == "1st" || strings.HasPrefix(g.ID.Situation, "2nd_") {
+			add(&early[side], g.ID.PlayType, g.Plays, g.EPA)
+		}
+		t, ok := situations[side][g.ID.Situation]
+		if !ok {
+			t = &tally{}
+			situations[side][g.ID.Situation] = t
+		}
+		add(t, g.ID.PlayType, g.Plays, g.EPA)
+	}
+
+	buildSide := func(side int) SideTendencies {
+		st := SideTendencies{
+			Overall:     split(overall[side]),
+			EarlyDown:   split(early[side]),
+			BySituation: make(map[string]TendencySplit, len(situations[side])),
+		}
+		if overall[side].passes > 0 {
+			st.PassEPA = overall[side].passEPA / float64(overall[side].passes)
+		}
+		if overall[side].runs > 0 {
+			st.RunEPA = overall[side].runEPA / float64(overall[side].runs)
+		}
+		for name, t := range situations[side] {
+			st.BySituation[name] = split(*t)
+		}
+		return st
+	}
+
+	return &TeamTendencies{
+		Team:    team,
+		Season:  season,
+		Offense: buildSide(0),
+		Defense: buildSide(1),
+	}
+}
+
+// ========================================
+// NGS (NEXT GEN STATS) QUERIES
+// ========================================
+
+// GetPlayerNGS gets Next Gen Stats for a player
+func (s *DataService) GetPlayerNGS(ctx context.Context, playerID string, statType string, season int) ([]models.NextGenStat, error) {
+	filter := bson.M{"player_id": playerID}
+	if statType != "" {
+		filter["stat_type"] = statType
+	}
+	if season > 0 {
+		filter["season"] = season
+	}
+
+	cursor, err := s.db.Collection("next_gen_stats").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{"season", -1}, {"week", -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var stats []models.NextGenStat
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ErrInvalidNGSQuery is returned when an NGS leaders request names an unknown stat type or metric
+var ErrInvalidNGSQuery = errors.New("invalid NGS query")
+
+// NGSMetrics lists the metrics NGS leaders can be ranked by for each stat type
+var NGSMetrics = map[string][]string{
+	"passing": {
+		"completion_percentage_above_expectation", "avg_time_to_throw", "avg_completed_air_yards",
+		"avg_intended_air_yards", "avg_air_yards_differential", "max_completed_air_distance",
+		"pass_yards", "pass_touchdowns", "pass_attempts",
+	},
+	"rushing": {
+		"rush_yards_over_expected", "efficiency", "avg_time_to_los", "expected_rush_yards",
+		"rush_yards", "rush_touchdowns", "carries",
+	},
+	"receiving": {
+		"avg_separation", "avg_cushion", "avg_yac_above_expectation", "avg_yac",
+		"avg_intended_air_yards_rec", "catch_percentage", "receiving_yards", "receptions", "targets",
+	},
+}
+
+// ngsSampleFields is the volume stat used to gate small samples for each stat type
+var ngsSampleFields = map[string]string{
+	"passing":   "pass_attempts",
+	"rushing":   "carries",
+	"receiving": "targets",
+}
+
+// NGSLeadersQuery selects and orders NGS leaders
+type NGSLeadersQuery struct {
+	StatType  string // passing, rushing, receiving
+	Season    int
+	Week      int // 0 for season totals
+	Metric    string
+	Ascending bool   // Lowest values first, for metrics where lower is better
+	Position  string // Optional, e.g. WR
+	MinSample int    // Minimum attempts/carries/targets, so small-sample outliers don't rank
+	Limit     int
+}
+
+// GetNGSLeaders gets top players by a specific NGS metric. Week 0 ranks season totals:
+// NGS files carry season summary rows with week 0, and the parser stores a missing week
+// as 0 too. Ascending order ranks the lowest values first (e.g. avg_time_to_throw for
+// quick release); rows where the metric is exactly 0 (not recorded) are skipped in that order.
+func (s *DataService) GetNGSLeaders(ctx context.Context, q NGSLeadersQuery) ([]models.NextGenStat, error) {
+	filter, opts, err := ngsLeadersQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.db.Collection("next_gen_stats").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var stats []models.NextGenStat
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ngsLeadersQuery validates q and builds the filter and sort for GetNGSLeaders
+func ngsLeadersQuery(q NGSLeadersQuery) (bson.M, *options.FindOptionsBuilder, error) {
+	metrics, ok := NGSMetrics[q.StatType]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unknown stat_type %q", ErrInvalidNGSQuery, q.StatType)
+	}
+	if !slices.Contains(metrics, q.Metric) {
+		return nil, nil, fmt.Errorf("%w: unknown %s metric %q", ErrInvalidNGSQuery, q.StatType, q.Metric)
+	}
+	if q.Week < 0 || q.Week > 22 {
+		return nil, nil, fmt.Errorf("%w: week must be 0 (season) or 1-22", ErrInvalidNGSQuery)
+	}
+
+	filter := bson.M{
+		"stat_type": q.StatType,
+		"season":    q.Season,
+		"week":      q.Week,
+	}
+	if q.Position != "" {
+		filter["position"] = q.Position
+	}
+	if q.MinSample > 0 {
+		filter[ngsSampleFields[q.StatType]] = bson.M{"$gte": q.MinSample}
+	}
+
+	order := -1
+	if q.Ascending {
+		order = 1
+		filter[q.Metric] = bson.M{"$ne": 0}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: q.Metric, Value: order}}).
+		SetLimit(int64(q.Limit))
+	return filter, opts, nil
+}
+
+// GetPlayerQBR gets a quarterback's weekly ESPN QBR for a season, plus the season total
+// (week 0) when available. QBR records carry ESPN ids, so the player is matched by name.
+func (s *DataService) GetPlayerQBR(ctx context.Context, nflID string, season int) ([]models.QBRStat, error) {
+	var player models.Player
+	err := s.db.Collection("players").FindOne(ctx, bson.M{"nfl_id": nflID},
+		options.FindOne().SetSort(bson.D{{Key: "season", Value: -1}})).Decode(&player)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"player_name": player.Name,
+		"season":      season,
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "season_type", Value: -1}, {Key: "week", Value: 1}})
+	cursor, err := s.db.Collection("qbr").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stats := []models.QBRStat{}
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ========================================
+// GAME QUERIES
+// ========================================
+
+// GetGame gets a specific game by ID
+func (s *DataService) GetGame(ctx context.Context, gameID string) (*models.Game, error) {
+	var game models.Game
+	err := s.db.Collection("games").FindOne(ctx, bson.M{"game_id": gameID}).Decode(&game)
+	return &game, err
+}
+
+// GetGamesBySeason gets games for a season
+func (s *DataService) GetGamesBySeason(ctx context.Context, season int, week int) ([]models.Game, error) {
+	filter := bson.M{"season": season}
+	if week > 0 {
+		filter["week"] = week
+	}
+
+	cursor, err := s.db.Collection("games").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{"week", 1}, {"start_time", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var games []models.Game
+	if err := cursor.All(ctx, &games); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// GetTeams lists teams with their colors, logos, conference and division, ordered by
+// division. An empty conference returns every team.
+func (s *DataService) GetTeams(ctx context.Context, conference string) ([]models.Team, error) {
+	filter := bson.M{}
+	if conference != "" {
+		filter["conference"] = conference
+	}
+
+	cursor, err := s.db.Collection("teams").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{"conference", 1}, {"division", 1}, {"name", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	teams := []models.Team{}
+	if err := cursor.All(ctx, &teams); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// GetUpcomingGames gets upcoming games for a team
+func (s *DataService) GetUpcomingGames(ctx context.Context, team string) ([]models.Game, error) {
+	filter, opts := upcomingGamesQuery(team, time.Now())
+	cursor, err := s.db.Collection("games").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var games []models.Game
+	if err := cursor.All(ctx, &games); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// upcomingGamesQuery selects a team's next five games kicking off at or after now
+func upcomingGamesQuery(team string, now time.Time) (bson.M, *options.FindOptionsBuilder) {
+	team = teams.Normalize(team)
+	filter := bson.M{
+		"$or": []bson.M{
+			{"home_team": team},
+			{"away_team": team},
+		},
+		"start_time": bson.M{"$gte": now},
+	}
+	return filter, options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}}).SetLimit(5)
+}
+
+// GetScheduledGames gets scheduled (not yet played) games for a season/week
+func (s *DataService) GetScheduledGames(ctx context.Context, season int, week int) ([]models.Game, error) {
+	filter := bson.M{
+		"season": season,
+		"status": "scheduled",
+	}
+	if week > 0 {
+		filter["week"] = week
+	}
+
+	cursor, err := s.db.Collection("games").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{"start_time", 1}}))
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var weeklyStats []models.WeeklyStat
-	if err := cursor.All(ctx, &weeklyStats); err != nil {
+	var games []models.Game
+	if err := cursor.All(ctx, &games); err != nil {
 		return nil, err
 	}
-	return weeklyStats, nil
+	return games, nil
 }
 
 // ========================================
-// PLAY-BY-PLAY QUERIES
+// DEFENSIVE RANKINGS
 // ========================================
 
-// GetPlayerPlays gets all plays involving a player
-func (s *DataService) GetPlayerPlays(ctx context.Context, playerID string, season int, limit int) ([]models.Play, error) {
-	filter := bson.M{
-		"$or": []bson.M{
-			{"passer_player_id": playerID},
-			{"rusher_player_id": playerID},
-			{"receiver_player_id": playerID},
-		},
-	}
-	if season > 0 {
-		filter["season"] = season
-	}
+// DefensiveRanking is a defense's EPA allowed against one position
+type DefensiveRanking struct {
+	Rank       int     `json:"rank" bson:"rank"` // 1 = toughest matchup (lowest EPA allowed)
+	Team       string  `json:"team" bson:"team"`
+	Position   string  `json:"position" bson:"position"`
+	EPAAllowed float64 `json:"epa_allowed" bson:"epa_allowed"`
+	Plays      int     `json:"plays" bson:"plays"`
+}
 
-	opts := options.Find().SetLimit(int64(limit))
-	cursor, err := s.db.Collection("plays").Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
+// defenseRankingDoc is a precomputed ranking stored in the defense_rankings collection
+type defenseRankingDoc struct {
+	Season           int `bson:"season"`
+	ThroughWeek      int `bson:"through_week"`   // 0 = whole season
+	CompletedWeek    int `bson:"completed_week"` // Latest week with a final game when computed
+	DefensiveRanking `bson:",inline"`
+	ComputedAt       time.Time `bson:"computed_at"`
+}
 
-	var plays []models.Play
-	if err := cursor.All(ctx, &plays); err != nil {
-		return nil, err
+// DefenseRankingPositions are the positions defensive rankings are computed for
+var DefenseRankingPositions = []string{"QB", "RB", "WR", "TE"}
+
+const defenseRankingsTTL = 30 * time.Minute
+
+type defenseRankingsEntry struct {
+	rankings  []DefensiveRanking
+	expiresAt time.Time
+}
+
+// defenseRankingsCache is shared by all DataService instances since rankings
+// scan every play of the season and only change when new games are loaded
+var defenseRankingsCache = struct {
+	sync.RWMutex
+	entries map[string]defenseRankingsEntry
+}{entries: make(map[string]defenseRankingsEntry)}
+
+// defensePlayFilter returns the play condition used to attribute plays to a position.
+// Passes count against QBs; carries and targets count against the position of the
+// rusher or receiver, given as nflIDs (the season's players at that position).
+func defensePlayFilter(position string, nflIDs []string) (bson.M, bool) {
+	switch position {
+	case "QB":
+		return bson.M{"passer_player_id": bson.M{"$ne": ""}}, true
+	case "RB":
+		return bson.M{"$or": []bson.M{
+			{"rusher_player_id": bson.M{"$in": nflIDs}},
+			{"receiver_player_id": bson.M{"$in": nflIDs}},
+		}}, true
+	case "WR", "TE":
+		return bson.M{"receiver_player_id": bson.M{"$in": nflIDs}}, true
+	default:
+		return nil, false
 	}
-	return plays, nil
 }
 
-// GetTeamPlays gets all plays for a team
-func (s *DataService) GetTeamPlays(ctx context.Context, team string, season int, limit int) ([]models.Play, error) {
-	filter := bson.M{
-		"$or": []bson.M{
-			{"possession_team": team},
-			{"defense_team": team},
-		},
+// GetDefensiveRankings ranks every defense by average EPA allowed to a position.
+// throughWeek limits the plays considered (0 = whole season). Results are
+// sorted from toughest (lowest EPA allowed) to easiest matchup. Rankings
+// precomputed into defense_rankings are used while they cover every completed
+// week; otherwise they are aggregated from plays.
+func (s *DataService) GetDefensiveRankings(ctx context.Context, position string, season int, throughWeek int) ([]DefensiveRanking, error) {
+	if !slices.Contains(DefenseRankingPositions, position) {
+		return nil, fmt.Errorf("unsupported position: %s", position)
 	}
-	if season > 0 {
-		filter["season"] = season
+
+	cacheKey := fmt.Sprintf("%d:%s:%d", season, position, throughWeek)
+	defenseRankingsCache.RLock()
+	entry, found := defenseRankingsCache.entries[cacheKey]
+	defenseRankingsCache.RUnlock()
+	if found && time.Now().Before(entry.expiresAt) {
+		return entry.rankings, nil
 	}
 
-	opts := options.Find().SetLimit(int64(limit))
-	cursor, err := s.db.Collection("plays").Find(ctx, filter, opts)
+	rankings, err := s.storedDefensiveRankings(ctx, position, season, throughWeek)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(ctx)
+	if rankings == nil {
+		rankings, err = s.computeDefensiveRankings(ctx, position, season, throughWeek)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	var plays []models.Play
-	if err := cursor.All(ctx, &plays); err != nil {
-		return nil, err
+	defenseRankingsCache.Lock()
+	defenseRankingsCache.entries[cacheKey] = defenseRankingsEntry{
+		rankings:  rankings,
+		expiresAt: time.Now().Add(defenseRankingsTTL),
 	}
-	return plays, nil
+	defenseRankingsCache.Unlock()
+
+	return rankings, nil
 }
 
-// GetGamePlays gets all plays for a specific game
-func (s *DataService) GetGamePlays(ctx context.Context, gameID string) ([]models.Play, error) {
-	cursor, err := s.db.Collection("plays").Find(ctx, bson.M{"game_id": gameID})
+// storedDefensiveRankings reads precomputed rankings, returning nil if they haven't been
+// computed or a week has been completed since
+func (s *DataService) storedDefensiveRankings(ctx context.Context, position string, season int, throughWeek int) ([]DefensiveRanking, error) {
+	cursor, err := s.db.Collection("defense_rankings").Find(ctx,
+		bson.M{"season": season, "through_week": throughWeek, "position": position},
+		options.Find().SetSort(bson.D{{Key: "rank", Value: 1}}))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to fetch defense rankings: %w", err)
 	}
 	defer cursor.Close(ctx)
 
-	var plays []models.Play
-	if err := cursor.All(ctx, &plays); err != nil {
-		return nil, err
+	var docs []defenseRankingDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode defense rankings: %w", err)
 	}
-	return plays, nil
-}
-
-// ========================================
-// EPA CALCULATIONS
-// ========================================
 
-// CalculatePlayerEPA calculates average EPA for a player
-func (s *DataService) CalculatePlayerEPA(ctx context.Context, playerID string, season int) (float64, int, error) {
-	filter := bson.M{
-		"$or": []bson.M{
-			{"passer_player_id": playerID},
-			{"rusher_player_id": playerID},
-			{"receiver_player_id": playerID},
-		},
-	}
-	if season > 0 {
-		filter["season"] = season
+	if len(docs) == 0 {
+		return nil, nil
 	}
 
-	cursor, err := s.db.Collection("plays").Find(ctx, filter)
+	completedWeek, err := s.LastCompletedWeek(ctx, season)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
 	}
-	defer cursor.Close(ctx)
-
-	var plays []models.Play
-	if err := cursor.All(ctx, &plays); err != nil {
-		return 0, 0, err
+	if !defenseRankingsCurrent(throughWeek, docs[0].CompletedWeek, completedWeek) {
+		return nil, nil
 	}
 
-	if len(plays) == 0 {
-		return 0, 0, nil
+	rankings := make([]DefensiveRanking, len(docs))
+	for i, d := range docs {
+		rankings[i] = d.DefensiveRanking
 	}
+	return rankings, nil
+}
 
-	totalEPA := 0.0
-	for _, play := range plays {
-		totalEPA += play.EPA
+// defenseRankingsCurrent reports whether rankings through throughWeek (0 = whole season),
+// computed when computedWeek was the latest completed week, still include every play now
+// that completedWeek is. Rankings through a week that was already complete never change.
+func defenseRankingsCurrent(throughWeek, computedWeek, completedWeek int) bool {
+	if throughWeek > 0 && throughWeek <= computedWeek {
+		return true
 	}
-
-	avgEPA := totalEPA / float64(len(plays))
-	return avgEPA, len(plays), nil
+	return computedWeek >= completedWeek
 }
 
-// CalculateTeamEPA calculates average EPA for a team's offense
-func (s *DataService) CalculateTeamEPA(ctx context.Context, team string, season int) (float64, int, error) {
-	filter := bson.M{"possession_team": team}
-	if season > 0 {
-		filter["season"] = season
+// DefenseRankingsCurrent reports whether the season's precomputed whole-season rankings
+// include the latest completed week
+func (s *DataService) DefenseRankingsCurrent(ctx context.Context, season int) (bool, error) {
+	var doc defenseRankingDoc
+	err := s.db.Collection("defense_rankings").FindOne(ctx,
+		bson.M{"season": season, "through_week": 0},
+		options.FindOne().SetProjection(bson.M{"completed_week": 1}),
+	).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
 	}
-
-	cursor, err := s.db.Collection("plays").Find(ctx, filter)
 	if err != nil {
-		return 0, 0, err
+		return false, fmt.Errorf("failed to fetch defense rankings: %w", err)
 	}
-	defer cursor.Close(ctx)
 
-	var plays []models.Play
-	if err := cursor.All(ctx, &plays); err != nil {
-		return 0, 0, err
+	completedWeek, err := s.LastCompletedWeek(ctx, season)
+	if err != nil {
+		return false, err
 	}
+	return defenseRankingsCurrent(0, doc.CompletedWeek, completedWeek), nil
+}
 
-	if len(plays) == 0 {
-		return 0, 0, nil
+// LastCompletedWeek returns the highest week of season with a final game, or 0 if none
+func (s *DataService) LastCompletedWeek(ctx context.Context, season int) (int, error) {
+	var game struct {
+		Week int `bson:"week"`
+	}
+	err := s.db.Collection("games").FindOne(ctx,
+		bson.M{"season": season, "status": "final"},
+		options.FindOne().SetSort(bson.D{{Key: "week", Value: -1}}).SetProjection(bson.M{"week": 1}),
+	).Decode(&game)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find last completed week: %w", err)
 	}
+	return game.Week, nil
+}
 
-	totalEPA := 0.0
-	for _, play := range plays {
-		totalEPA += play.EPA
+// PrecomputeDefensiveRankings aggregates every position's rankings through each week from
+// 1 to throughWeek, plus the whole season (through_week 0), and replaces them in
+// defense_rankings. It returns the number of ranking documents written.
+func (s *DataService) PrecomputeDefensiveRankings(ctx context.Context, season, throughWeek int) (int, error) {
+	collection := s.db.Collection("defense_rankings")
+	written := 0
+	now := time.Now()
+
+	completedWeek, err := s.LastCompletedWeek(ctx, season)
+	if err != nil {
+		return 0, err
 	}
 
-	avgEPA := totalEPA / float64(len(plays))
-	return avgEPA, len(plays), nil
+	for week := 0; week <= throughWeek; week++ {
+		for _, position := range DefenseRankingPositions {
+			rankings, err := s.computeDefensiveRankings(ctx, position, season, week)
+			if err != nil {
+				return written, fmt.Errorf("failed to compute %s rankings through week %d: %w", position, week, err)
+			}
+
+			filter := bson.M{"season": season, "through_week": week, "position": position}
+			if _, err := collection.DeleteMany(ctx, filter); err != nil {
+				return written, fmt.Errorf("failed to clear defense rankings: %w", err)
+			}
+			if len(rankings) == 0 {
+				continue
+			}
+
+			docs := defenseRankingDocs(season, week, completedWeek, rankings, now)
+			if _, err := collection.InsertMany(ctx, docs); err != nil {
+				return written, fmt.Errorf("failed to store defense rankings: %w", err)
+			}
+			written += len(docs)
+
+			cacheKey := fmt.Sprintf("%d:%s:%d", season, position, week)
+			defenseRankingsCache.Lock()
+			delete(defenseRankingsCache.entries, cacheKey)
+			defenseRankingsCache.Unlock()
+		}
+	}
+	return written, nil
 }
 
-// ========================================
-// NGS (NEXT GEN STATS) QUERIES
-// ========================================
+// defenseRankingDocs wraps a position's rankings through week for storage
+func defenseRankingDocs(season, week, completedWeek int, rankings []DefensiveRanking, computedAt time.Time) []defenseRankingDoc {
+	docs := make([]defenseRankingDoc, len(rankings))
+	for i, r := range rankings {
+		docs[i] = defenseRankingDoc{
+			Season:           season,
+			ThroughWeek:      week,
+			CompletedWeek:    completedWeek,
+			DefensiveRanking: r,
+			ComputedAt:       computedAt,
+		}
+	}
+	return docs
+}
 
-// GetPlayerNGS gets Next Gen Stats for a player
-func (s *DataService) GetPlayerNGS(ctx context.Context, playerID string, statType string, season int) ([]models.NextGenStat, error) {
-	filter := bson.M{"player_id": playerID}
-	if statType != "" {
-		filter["stat_type"] = statType
+// computeDefensiveRankings aggregates a position's rankings from plays
+func (s *DataService) computeDefensiveRankings(ctx context.Context, position string, season int, throughWeek int) ([]DefensiveRanking, error) {
+	var nflIDs []string
+	if position != "QB" {
+		err := s.db.Collection("players").Distinct(ctx, "nfl_id", bson.M{"position": position, "season": season}).Decode(&nflIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s players: %w", position, err)
+		}
 	}
-	if season > 0 {
-		filter["season"] = season
+
+	pipeline, err := defenseRankingsPipeline(position, season, throughWeek, nflIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	cursor, err := s.db.Collection("next_gen_stats").Find(ctx, filter,
-		options.Find().SetSort(bson.D{{"season", -1}, {"week", -1}}))
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var stats []models.NextGenStat
-	if err := cursor.All(ctx, &stats); err != nil {
+	var results []defenseEPA
+	if err := cursor.All(ctx, &results); err != nil {
 		return nil, err
 	}
-	return stats, nil
+	return rankDefenses(position, results), nil
 }
 
-// GetNGSLeaders gets top players by a specific NGS metric
-func (s *DataService) GetNGSLeaders(ctx context.Context, statType string, season int, metric string, limit int) ([]models.NextGenStat, error) {
-	filter := bson.M{
-		"stat_type": statType,
-		"season":    season,
-		"week":      0, // Season totals
+// defenseRankingsPipeline averages the EPA each defense allowed on the position's plays
+// through throughWeek (0 = whole season), sorted from lowest to highest. nflIDs are the
+// season's players at the position (unused for QB).
+func defenseRankingsPipeline(position string, season int, throughWeek int, nflIDs []string) (mongo.Pipeline, error) {
+	positionFilter, ok := defensePlayFilter(position, nflIDs)
+	if !ok {
+		return nil, fmt.Errorf("unsupported position: %s", position)
 	}
 
-	opts := options.Find().
-		SetSort(bson.D{{metric, -1}}).
-		SetLimit(int64(limit))
-
-	cursor, err := s.db.Collection("next_gen_stats").Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
+	match := bson.M{
+		"season":       season,
+		"defense_team": bson.M{"$ne": ""},
+	}
+	if throughWeek > 0 {
+		match["week"] = bson.M{"$lte": throughWeek}
+	}
+	for k, v := range positionFilter {
+		match[k] = v
 	}
-	defer cursor.Close(ctx)
 
-	var stats []models.NextGenStat
-	if err := cursor.All(ctx, &stats); err != nil {
-		return nil, err
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$defense_team",
+			"avg_epa": bson.M{"$avg": "$epa"},
+			"plays":   bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "avg_epa", Value: 1}}}},
 	}
-	return stats, nil
+	return pipeline, nil
 }
 
-// ========================================
-// GAME QUERIES
-// ========================================
-
-// GetGame gets a specific game by ID
-func (s *DataService) GetGame(ctx context.Context, gameID string) (*models.Game, error) {
-	var game models.Game
-	err := s.db.Collection("games").FindOne(ctx, bson.M{"game_id": gameID}).Decode(&game)
-	return &game, err
+// defenseEPA is one defense's average EPA allowed to a position
+type defenseEPA struct {
+	Team   string  `bson:"_id"`
+	AvgEPA float64 `bson:"avg_epa"`
+	Plays  int     `bson:"plays"`
 }
 
-// GetGamesBySeason gets games for a season
-func (s *DataService) GetGamesBySeason(ctx context.Context, season int, week int) ([]models.Game, error) {
-	filter := bson.M{"season": season}
-	if week > 0 {
-		filter["week"] = week
+// rankDefenses ranks defenses sorted by EPA allowed, 1 being the lowest
+func rankDefenses(position string, results []defenseEPA) []DefensiveRanking {
+	rankings := make([]DefensiveRanking, 0, len(results))
+	for i, r := range results {
+		rankings = append(rankings, DefensiveRanking{
+			Rank:       i + 1,
+			Team:       r.Team,
+			Position:   position,
+			EPAAllowed: r.AvgEPA,
+			Plays:      r.Plays,
+		})
 	}
+	return rankings
+}
 
-	cursor, err := s.db.Collection("games").Find(ctx, filter,
-		options.Find().SetSort(bson.D{{"week", 1}, {"game_date", 1}}))
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
+// regularSeasonWeeks is the number of weeks in the NFL regular season
+const regularSeasonWeeks = 18
+
+// ScheduleWeek is one week of a team's remaining schedule
+type ScheduleWeek struct {
+	Week         int     `json:"week"`
+	Bye          bool    `json:"bye"`
+	Opponent     string  `json:"opponent,omitempty"`
+	Home         bool    `json:"home"`
+	OpponentEPA  float64 `json:"opponent_epa"`  // EPA the opponent allows to the position
+	OpponentRank int     `json:"opponent_rank"` // 1 = toughest defense vs the position
+}
 
-	var games []models.Game
-	if err := cursor.All(ctx, &games); err != nil {
-		return nil, err
-	}
-	return games, nil
+// ScheduleOutlook summarizes how hard a team's remaining schedule is for a position
+type ScheduleOutlook struct {
+	Team            string         `json:"team"`
+	Position        string         `json:"position"`
+	Season          int            `json:"season"`
+	FromWeek        int            `json:"from_week"`
+	Weeks           []ScheduleWeek `json:"weeks"`
+	GamesRemaining  int            `json:"games_remaining"`
+	AvgOpponentEPA  float64        `json:"avg_opponent_epa"`
+	AvgOpponentRank float64        `json:"avg_opponent_rank"`
+	DifficultyScore float64        `json:"difficulty_score"` // 0-100, higher is harder
+	Difficulty      string         `json:"difficulty"`       // "favorable", "average", "difficult"
 }
 
-// GetUpcomingGames gets upcoming games for a team
-func (s *DataService) GetUpcomingGames(ctx context.Context, team string) ([]models.Game, error) {
-	filter := bson.M{
+// RestOfSeasonSchedule walks a team's games from fromWeek through the end of the regular
+// season and rates each opponent's defense against the position. Weeks without a game are
+// reported as byes.
+func (s *DataService) RestOfSeasonSchedule(ctx context.Context, team, position string, season, fromWeek int) (*ScheduleOutlook, error) {
+	team = teams.Normalize(team)
+	outlook := &ScheduleOutlook{
+		Team:            team,
+		Position:        position,
+		Season:          season,
+		FromWeek:        fromWeek,
+		Weeks:           []ScheduleWeek{},
+		AvgOpponentRank: 16,
+		DifficultyScore: 50,
+		Difficulty:      "average",
+	}
+
+	cursor, err := s.db.Collection("games").Find(ctx, bson.M{
+		"season": season,
+		"week":   bson.M{"$gte": fromWeek, "$lte": regularSeasonWeeks},
 		"$or": []bson.M{
 			{"home_team": team},
 			{"away_team": team},
 		},
-		"game_date": bson.M{"$gte": time.Now()},
-	}
-
-	cursor, err := s.db.Collection("games").Find(ctx, filter,
-		options.Find().SetSort(bson.D{{"game_date", 1}}).SetLimit(5))
+	}, options.Find().SetSort(bson.D{{Key: "week", Value: 1}}))
 	if err != nil {
 		return nil, err
 	}
@@ -403,31 +1518,71 @@ func (s *DataService) GetUpcomingGames(ctx context.Context, team string) ([]mode
 	if err := cursor.All(ctx, &games); err != nil {
 		return nil, err
 	}
-	return games, nil
+	if len(games) == 0 {
+		return outlook, nil
+	}
+
+	// Defensive strength is judged on games already played
+	rankings, _ := s.GetDefensiveRankings(ctx, position, season, fromWeek-1)
+	rateSchedule(outlook, games, rankings)
+	return outlook, nil
 }
 
-// GetScheduledGames gets scheduled (not yet played) games for a season/week
-func (s *DataService) GetScheduledGames(ctx context.Context, season int, week int) ([]models.Game, error) {
-	filter := bson.M{
-		"season": season,
-		"status": "scheduled",
+// rateSchedule fills outlook with each week from outlook.FromWeek through the end of the
+// regular season, marking weeks without one of games as byes, and scores the schedule by
+// the opponents' rankings (unranked opponents count as average)
+func rateSchedule(outlook *ScheduleOutlook, games []models.Game, rankings []DefensiveRanking) {
+	team := outlook.Team
+	rankByTeam := make(map[string]DefensiveRanking, len(rankings))
+	for _, r := range rankings {
+		rankByTeam[r.Team] = r
 	}
-	if week > 0 {
-		filter["week"] = week
+
+	gamesByWeek := make(map[int]models.Game, len(games))
+	for _, g := range games {
+		gamesByWeek[g.Week] = g
 	}
 
-	cursor, err := s.db.Collection("games").Find(ctx, filter,
-		options.Find().SetSort(bson.D{{"start_time", 1}}))
-	if err != nil {
-		return nil, err
+	totalEPA := 0.0
+	totalRank := 0
+	for week := outlook.FromWeek; week <= regularSeasonWeeks; week++ {
+		game, ok := gamesByWeek[week]
+		if !ok {
+			outlook.Weeks = append(outlook.Weeks, ScheduleWeek{Week: week, Bye: true})
+			continue
+		}
+
+		sw := ScheduleWeek{Week: week, Home: game.HomeTeam == team, OpponentRank: 16}
+		if sw.Home {
+			sw.Opponent = game.AwayTeam
+		} else {
+			sw.Opponent = game.HomeTeam
+		}
+		if r, ok := rankByTeam[sw.Opponent]; ok {
+			sw.OpponentEPA = r.EPAAllowed
+			sw.OpponentRank = r.Rank
+		}
+
+		outlook.Weeks = append(outlook.Weeks, sw)
+		outlook.GamesRemaining++
+		totalEPA += sw.OpponentEPA
+		totalRank += sw.OpponentRank
+	}
+	if outlook.GamesRemaining == 0 {
+		return
 	}
-	defer cursor.Close(ctx)
 
-	var games []models.Game
-	if err := cursor.All(ctx, &games); err != nil {
-		return nil, err
+	outlook.AvgOpponentEPA = totalEPA / float64(outlook.GamesRemaining)
+	outlook.AvgOpponentRank = float64(totalRank) / float64(outlook.GamesRemaining)
+
+	// Rank 1 (toughest defense) maps to 100, rank 32 to ~3
+	outlook.DifficultyScore = (33 - outlook.AvgOpponentRank) / 32 * 100
+	switch {
+	case outlook.DifficultyScore >= 60:
+		outlook.Difficulty = "difficult"
+	case outlook.DifficultyScore <= 40:
+		outlook.Difficulty = "favorable"
 	}
-	return games, nil
 }
 
 // ========================================
@@ -481,11 +1636,11 @@ func (s *DataService) GetPlayerSummary(ctx context.Context, nflID string, season
 	summary["all_seasons"] = allSeasons
 
 	// Get ALL stats (all seasons)
-	allStats, _ := s.GetPlayerStats(ctx, nflID, 0) // 0 = all seasons
+	allStats, _ := s.GetPlayerStats(ctx, nflID, 0, models.SeasonTypeCombined) // 0 = all seasons
 	summary["all_stats"] = allStats
 
 	// Get current season stats
-	currentStats, _ := s.GetPlayerStats(ctx, nflID, player.Season)
+	currentStats, _ := s.GetPlayerStats(ctx, nflID, player.Season, models.SeasonTypeCombined)
 	summary["stats"] = currentStats
 
 	// Get EPA from player_stats (pre-calculated, much faster!)
@@ -525,6 +1680,24 @@ func (s *DataService) GetPlayerSummary(ctx context.Context, nflID string, season
 	summary["lifetime_epa"] = lifetimeEPA
 	summary["lifetime_plays"] = lifetimePlaysSum
 
+	// Current season EPA adjusted for the defenses faced
+	adjustedEPA, _ := s.GetOpponentAdjustedEPA(ctx, nflID, player.Season)
+	summary["opponent_adjusted_epa"] = adjustedEPA
+
+	// Where the player's EPA per play ranks among qualified players at the position
+	epaPercentile, _ := s.GetEPAPercentile(ctx, nflID, player.Position, player.Season)
+	summary["epa_percentile"] = epaPercentile
+
+	// Targets, carries and touchdowns inside the opponent's 20
+	redZone, _ := s.GetRedZoneUsage(ctx, nflID, player.Season)
+	summary["red_zone"] = redZone
+
+	// Target depth and share of team air yards for pass catchers
+	if player.Position == "WR" || player.Position == "TE" {
+		airYards, _ := s.GetReceiverAirYards(ctx, nflID, player.Season)
+		summary["air_yards"] = airYards
+	}
+
 	// Get NGS stats for current season
 	ngs, _ := s.GetPlayerNGS(ctx, nflID, "", player.Season)
 	summary["ngs"] = ngs
@@ -533,6 +1706,9 @@ func (s *DataService) GetPlayerSummary(ctx context.Context, nflID string, season
 	allNGS, _ := s.GetPlayerNGS(ctx, nflID, "", 0) // 0 = all seasons
 	summary["all_ngs"] = allNGS
 
+	// Position-appropriate top-line NGS metric (nil for positions NGS doesn't track)
+	summary["ngs_headline"] = NGSHeadlineFor(player.Position, allNGS)
+
 	// Get weekly stats for current season
 	weeklyStats, _ := s.GetPlayerWeeklyStats(ctx, nflID, player.Season, 0) // 0 = all weeks
 	summary["weekly_stats"] = weeklyStats
@@ -541,9 +1717,131 @@ func (s *DataService) GetPlayerSummary(ctx context.Context, nflID string, season
 	allWeeklyStats, _ := s.GetPlayerWeeklyStats(ctx, nflID, 0, 0) // 0, 0 = all seasons, all weeks
 	summary["all_weekly_stats"] = allWeeklyStats
 
+	// Week-to-week consistency of PPR scoring for the current season
+	summary["consistency"] = CalculateConsistency(weeklyStats)
+
 	return summary, nil
 }
 
+// NGSHeadline is the one Next Gen Stats number that best summarizes a player's season
+type NGSHeadline struct {
+	Season   int     `json:"season"`
+	StatType string  `json:"stat_type"`
+	Metric   string  `json:"metric"`
+	Label    string  `json:"label"`
+	Value    float64 `json:"value"`
+}
+
+// ngsHeadlineMetrics maps a position to the NGS stat type and metric it is judged by
+var ngsHeadlineMetrics = map[string]struct {
+	statType, metric, label string
+	value                   func(models.NextGenStat) float64
+}{
+	"QB": {"passing", "completion_percentage_above_expectation", "CPOE",
+		func(s models.NextGenStat) float64 { return s.CompletionPercentageAboveExpectation }},
+	"RB": {"rushing", "rush_yards_over_expected", "RYOE",
+		func(s models.NextGenStat) float64 { return s.RushYardsOverExpected }},
+	"FB": {"rushing", "rush_yards_over_expected", "RYOE",
+		func(s models.NextGenStat) float64 { return s.RushYardsOverExpected }},
+	"WR": {"receiving", "avg_separation", "Separation",
+		func(s models.NextGenStat) float64 { return s.AvgSeparation }},
+	"TE": {"receiving", "avg_separation", "Separation",
+		func(s models.NextGenStat) float64 { return s.AvgSeparation }},
+}
+
+// NGSHeadlineFor picks the position's headline metric from the most recent season's NGS
+// totals, falling back to that season's latest week when there is no season row. It returns
+// nil for positions NGS doesn't track (K, DST, defenders) or players with no NGS data.
+// stats must be sorted by season and week descending, as GetPlayerNGS returns them.
+func NGSHeadlineFor(position string, stats []models.NextGenStat) *NGSHeadline {
+	m, ok := ngsHeadlineMetrics[position]
+	if !ok {
+		return nil
+	}
+
+	var latest *models.NextGenStat
+	for i := range stats {
+		stat := &stats[i]
+		if stat.StatType != m.statType {
+			continue
+		}
+		if latest != nil && stat.Season != latest.Season {
+			break
+		}
+		if latest == nil || stat.Week == 0 {
+			latest = stat
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	return &NGSHeadline{
+		Season:   latest.Season,
+		StatType: m.statType,
+		Metric:   m.metric,
+		Label:    m.label,
+		Value:    m.value(*latest),
+	}
+}
+
+// ConsistencyMetrics describes how steady a player's weekly fantasy output is
+type ConsistencyMetrics struct {
+	Games          int     `json:"games"`
+	MeanPPR        float64 `json:"mean_ppr"`
+	StdDevPPR      float64 `json:"stddev_ppr"`
+	CoeffVariation float64 `json:"coefficient_of_variation"` // stddev / mean
+	Classification string  `json:"classification"`           // consistent, moderate, boom_bust, volatile, insufficient_data
+}
+
+// CalculateConsistency computes the mean, sample standard deviation and coefficient of
+// variation of weekly PPR points, and classifies the player's scoring profile
+func CalculateConsistency(weeks []models.WeeklyStat) ConsistencyMetrics {
+	metrics := ConsistencyMetrics{Games: len(weeks)}
+	if len(weeks) < 2 {
+		metrics.Classification = "insufficient_data"
+		if len(weeks) == 1 {
+			metrics.MeanPPR = weeks[0].FantasyPointsPPR
+		}
+		return metrics
+	}
+
+	total := 0.0
+	for _, w := range weeks {
+		total += w.FantasyPointsPPR
+	}
+	mean := total / float64(len(weeks))
+
+	sumSq := 0.0
+	for _, w := range weeks {
+		diff := w.FantasyPointsPPR - mean
+		sumSq += diff * diff
+	}
+	stdDev := math.Sqrt(sumSq / float64(len(weeks)-1))
+
+	metrics.MeanPPR = mean
+	metrics.StdDevPPR = stdDev
+	if mean > 0 {
+		metrics.CoeffVariation = stdDev / mean
+	}
+
+	switch {
+	case mean <= 0:
+		metrics.Classification = "insufficient_data"
+	case metrics.CoeffVariation < 0.35:
+		metrics.Classification = "consistent"
+	case mean >= 12 && metrics.CoeffVariation >= 0.5:
+		// High ceiling, unreliable floor
+		metrics.Classification = "boom_bust"
+	case metrics.CoeffVariation >= 0.5:
+		metrics.Classification = "volatile"
+	default:
+		metrics.Classification = "moderate"
+	}
+
+	return metrics
+}
+
 // GetTeamDepthChart gets team's roster by position
 func (s *DataService) GetTeamDepthChart(ctx context.Context, team string, season int) (map[string][]models.Player, error) {
 	players, err := s.GetPlayersByTeam(ctx, team, season)
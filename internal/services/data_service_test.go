@@ -0,0 +1,738 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestApplyWeeklyStatuses(t *testing.T) {
+	// Latest roster entries: the first player has since come off IR and been traded
+	players := []models.Player{
+		{NFLID: "00-0031", Name: "Traded Receiver", Team: "NYJ", Status: "ACT", StatusDescriptionAbbr: "A01", Week: 12},
+		{NFLID: "00-0032", Name: "Injured Back", Team: "DAL", Status: "ACT", StatusDescriptionAbbr: "A01", Week: 12},
+		{NFLID: "00-0033", Name: "Unrecorded Tight End", Team: "KC", Status: "ACT", StatusDescriptionAbbr: "A01", Week: 12},
+	}
+	statuses := []models.PlayerWeeklyStatus{
+		{NFLID: "00-0031", Season: 2024, Week: 6, Team: "PIT", Status: "INA", StatusDescriptionAbbr: "R01"},
+		{NFLID: "00-0032", Season: 2024, Week: 6, Status: "INA", StatusDescriptionAbbr: "R48"},
+	}
+
+	applyWeeklyStatuses(players, statuses)
+
+	tests := []struct {
+		nflID, wantTeam, wantStatus, wantAbbr string
+		wantWeek                              int
+	}{
+		{"00-0031", "PIT", "INA", "R01", 6},
+		{"00-0032", "DAL", "INA", "R48", 6}, // No team recorded that week keeps the latest
+		{"00-0033", "KC", "ACT", "A01", 12}, // No weekly status leaves the player untouched
+	}
+	for i, tt := range tests {
+		p := players[i]
+		if p.NFLID != tt.nflID {
+			t.Fatalf("players[%d] = %s, want %s", i, p.NFLID, tt.nflID)
+		}
+		if p.Team != tt.wantTeam || p.Status != tt.wantStatus || p.StatusDescriptionAbbr != tt.wantAbbr || p.Week != tt.wantWeek {
+			t.Errorf("%s = %s %s/%s week %d, want %s %s/%s week %d", p.NFLID,
+				p.Team, p.Status, p.StatusDescriptionAbbr, p.Week,
+				tt.wantTeam, tt.wantStatus, tt.wantAbbr, tt.wantWeek)
+		}
+	}
+}
+
+// findPlays applies a Find's sort, skip and limit to plays the way MongoDB would
+func findPlays(t *testing.T, plays []models.Play, builder *options.FindOptionsBuilder) []models.Play {
+	t.Helper()
+
+	var opts options.FindOptions
+	for _, set := range builder.Opts {
+		if err := set(&opts); err != nil {
+			t.Fatalf("find option error = %v", err)
+		}
+	}
+
+	sorted := slices.Clone(plays)
+	sortSpec, _ := opts.Sort.(bson.D)
+	slices.SortStableFunc(sorted, func(a, b models.Play) int {
+		for _, key := range sortSpec {
+			var c int
+			switch key.Key {
+			case "game_id":
+				c = strings.Compare(a.GameID, b.GameID)
+			case "game_seconds":
+				c = cmp.Compare(a.GameSeconds, b.GameSeconds)
+			case "play_id":
+				c = strings.Compare(a.PlayID, b.PlayID)
+			default:
+				t.Fatalf("unsupported sort key %q", key.Key)
+			}
+			if key.Value.(int) < 0 {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	if opts.Skip != nil {
+		sorted = sorted[min(int(*opts.Skip), len(sorted)):]
+	}
+	if opts.Limit != nil && int(*opts.Limit) < len(sorted) {
+		sorted = sorted[:*opts.Limit]
+	}
+	return sorted
+}
+
+func TestPlayPagesDontOverlap(t *testing.T) {
+	// Two games stored out of order, with plays sharing a clock value
+	plays := []models.Play{
+		{GameID: "2024_02_BUF_MIA", PlayID: "40", GameSeconds: 3520},
+		{GameID: "2024_01_ARI_BUF", PlayID: "75", GameSeconds: 3410},
+		{GameID: "2024_01_ARI_BUF", PlayID: "56", GameSeconds: 3600},
+		{GameID: "2024_02_BUF_MIA", PlayID: "62", GameSeconds: 3480},
+		{GameID: "2024_01_ARI_BUF", PlayID: "99", GameSeconds: 3410},
+		{GameID: "2024_02_BUF_MIA", PlayID: "1", GameSeconds: 3600},
+		{GameID: "2024_01_ARI_BUF", PlayID: "81", GameSeconds: 3370},
+	}
+	const limit = 4
+
+	first, firstMore := trimPlayPage(findPlays(t, plays, playPageOptions(limit, 0)), limit)
+	second, secondMore := trimPlayPage(findPlays(t, plays, playPageOptions(limit, limit)), limit)
+
+	if len(first) != limit || !firstMore {
+		t.Errorf("first page = %d plays, has_more %v, want %d and true", len(first), firstMore, limit)
+	}
+	if len(second) != len(plays)-limit || secondMore {
+		t.Errorf("second page = %d plays, has_more %v, want %d and false", len(second), secondMore, len(plays)-limit)
+	}
+
+	seen := make(map[string]bool)
+	var order []string
+	for _, p := range append(slices.Clone(first), second...) {
+		key := p.GameID + "/" + p.PlayID
+		if seen[key] {
+			t.Errorf("play %s appears on both pages", key)
+		}
+		seen[key] = true
+		order = append(order, key)
+	}
+	if len(seen) != len(plays) {
+		t.Errorf("pages cover %d plays, want all %d", len(seen), len(plays))
+	}
+
+	want := []string{
+		"2024_01_ARI_BUF/56", "2024_01_ARI_BUF/75", "2024_01_ARI_BUF/99", "2024_01_ARI_BUF/81",
+		"2024_02_BUF_MIA/1", "2024_02_BUF_MIA/40", "2024_02_BUF_MIA/62",
+	}
+	if !slices.Equal(order, want) {
+		t.Errorf("play order = %v, want %v", order, want)
+	}
+}
+
+func TestTrimPlayPage(t *testing.T) {
+	plays := make([]models.Play, 5)
+	tests := []struct {
+		name     string
+		fetched  int
+		limit    int
+		wantLen  int
+		wantMore bool
+	}{
+		{"extra play fetched", 5, 4, 4, true},
+		{"exactly a page left", 4, 4, 4, false},
+		{"short last page", 2, 4, 2, false},
+		{"no limit returns everything", 5, 0, 5, false},
+	}
+	for _, tt := range tests {
+		got, more := trimPlayPage(plays[:tt.fetched], tt.limit)
+		if len(got) != tt.wantLen || more != tt.wantMore {
+			t.Errorf("%s: trimPlayPage() = %d plays, has_more %v, want %d and %v", tt.name, len(got), more, tt.wantLen, tt.wantMore)
+		}
+	}
+}
+
+// runAverageEPA evaluates averageEPAPipeline over plays the way averageEPA does
+func runAverageEPA(t *testing.T, filter bson.M, plays []models.Play) (float64, int) {
+	t.Helper()
+
+	var results []epaTotals
+	decodeDocs(t, runPipeline(t, averageEPAPipeline(filter), toDocs(t, plays), nil), &results)
+	if len(results) == 0 {
+		return 0, 0
+	}
+	return results[0].average()
+}
+
+func TestAverageEPAMatchesInMemoryAverage(t *testing.T) {
+	const qb, wr = "00-0033873", "00-0036900"
+	plays := []models.Play{
+		{Season: 2025, PossessionTeam: "KC", PasserPlayerID: qb, ReceiverPlayerID: wr, EPA: 1.25},
+		{Season: 2025, PossessionTeam: "KC", PasserPlayerID: qb, EPA: -0.75},
+		{Season: 2025, PossessionTeam: "KC", RusherPlayerID: qb, EPA: 0.4},
+		{Season: 2025, PossessionTeam: "KC", RusherPlayerID: "00-0037000", EPA: -0.2},
+		{Season: 2024, PossessionTeam: "KC", PasserPlayerID: qb, EPA: 2.0},
+		{Season: 2025, PossessionTeam: "BUF", PasserPlayerID: "00-0034857", EPA: 0.9},
+	}
+
+	// The old implementation loaded the matching plays and averaged them in Go
+	oldAverage := func(match func(models.Play) bool) (float64, int) {
+		var total float64
+		n := 0
+		for _, p := range plays {
+			if match(p) {
+				total += p.EPA
+				n++
+			}
+		}
+		if n == 0 {
+			return 0, 0
+		}
+		return total / float64(n), n
+	}
+
+	tests := []struct {
+		name   string
+		filter bson.M
+		match  func(models.Play) bool
+	}{
+		{"player in season", playerEPAFilter(qb, 2025), func(p models.Play) bool {
+			return p.Season == 2025 && (p.PasserPlayerID == qb || p.RusherPlayerID == qb || p.ReceiverPlayerID == qb)
+		}},
+		{"player all seasons", playerEPAFilter(qb, 0), func(p models.Play) bool {
+			return p.PasserPlayerID == qb || p.RusherPlayerID == qb || p.ReceiverPlayerID == qb
+		}},
+		{"receiver", playerEPAFilter(wr, 2025), func(p models.Play) bool { return p.ReceiverPlayerID == wr }},
+		{"team offense", teamEPAFilter("kc", 2025), func(p models.Play) bool {
+			return p.Season == 2025 && p.PossessionTeam == "KC"
+		}},
+		{"no plays", playerEPAFilter("00-0000000", 2025), func(models.Play) bool { return false }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantAvg, wantPlays := oldAverage(tt.match)
+			gotAvg, gotPlays := runAverageEPA(t, tt.filter, plays)
+			if gotPlays != wantPlays || math.Abs(gotAvg-wantAvg) > 1e-9 {
+				t.Errorf("averageEPA = (%v, %d), want (%v, %d)", gotAvg, gotPlays, wantAvg, wantPlays)
+			}
+		})
+	}
+}
+
+func TestWeeklyStatsRangeQuery(t *testing.T) {
+	const nflID = "00-0036389"
+	// Seeded out of order, with another season and another player mixed in
+	stats := []models.WeeklyStat{
+		{NFLID: nflID, Season: 2025, Week: 3, FantasyPointsPPR: 18.4},
+		{NFLID: nflID, Season: 2025, Week: 1, FantasyPointsPPR: 9.1},
+		{NFLID: "00-0037000", Season: 2025, Week: 2, FantasyPointsPPR: 30.0},
+		{NFLID: nflID, Season: 2024, Week: 2, FantasyPointsPPR: 12.0},
+		{NFLID: nflID, Season: 2025, Week: 2, FantasyPointsPPR: 22.7},
+	}
+	docs := toDocs(t, stats)
+
+	tests := []struct {
+		name     string
+		from, to int
+		want     []int
+	}{
+		{"whole season", 0, 0, []int{1, 2, 3}},
+		{"from week", 2, 0, []int{2, 3}},
+		{"to week", 0, 2, []int{1, 2}},
+		{"single week", 2, 2, []int{2}},
+		{"past the last week", 4, 0, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, opts := weeklyStatsRangeQuery(nflID, 2025, tt.from, tt.to)
+			var got []models.WeeklyStat
+			decodeDocs(t, findDocs(t, docs, filter, opts), &got)
+
+			weeks := []int{}
+			for _, s := range got {
+				weeks = append(weeks, s.Week)
+			}
+			if !slices.Equal(weeks, tt.want) {
+				t.Errorf("weeks = %v, want %v", weeks, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchPlayers(t *testing.T) {
+	docs := toDocs(t, []models.Player{
+		{NFLID: "00-0034857", Name: "Josh Allen", Team: "BUF", Season: 2024},
+		{NFLID: "00-0034857", Name: "Josh Allen", Team: "BUF", Season: 2025},
+		{NFLID: "00-0030279", Name: "Keenan Allen", Team: "CHI", Season: 2024},
+		{NFLID: "00-0030279", Name: "Keenan Allen", Team: "LAC", Season: 2025},
+		{NFLID: "00-0031228", Name: "Allen Lazard", Team: "NYJ", Season: 2025},
+		{NFLID: "00-0030564", Name: "Allen Robinson", Team: "DET", Season: 2023},
+		{NFLID: "00-0033873", Name: "Patrick Mahomes", Team: "KC", Season: 2025},
+	})
+	byPattern := func(ctx context.Context, pattern string, excludeIDs []string, limit int) ([]models.Player, error) {
+		var players []models.Player
+		decodeDocs(t, runPipeline(t, searchPlayersPipeline(pattern, excludeIDs, limit), docs, nil), &players)
+		return players, nil
+	}
+
+	tests := []struct {
+		query string
+		limit int
+		want  []string
+	}{
+		// Prefix matches come first, then substring matches, each player once
+		{"allen", 10, []string{"Allen Lazard/NYJ", "Allen Robinson/DET", "Josh Allen/BUF", "Keenan Allen/LAC"}},
+		{"ALLEN", 3, []string{"Allen Lazard/NYJ", "Allen Robinson/DET", "Josh Allen/BUF"}},
+		{"allen", 2, []string{"Allen Lazard/NYJ", "Allen Robinson/DET"}},
+		{"  mahomes ", 10, []string{"Patrick Mahomes/KC"}},
+		// Regex metacharacters are matched literally
+		{"a.len", 10, []string{}},
+		{"", 10, []string{}},
+	}
+
+	for _, tt := range tests {
+		players, err := searchPlayers(context.Background(), tt.query, tt.limit, byPattern)
+		if err != nil {
+			t.Fatalf("searchPlayers(%q) error = %v", tt.query, err)
+		}
+		got := []string{}
+		for _, p := range players {
+			got = append(got, p.Name+"/"+p.Team)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("searchPlayers(%q, %d) = %v, want %v", tt.query, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateConsistency(t *testing.T) {
+	weeks := func(points ...float64) []models.WeeklyStat {
+		stats := make([]models.WeeklyStat, len(points))
+		for i, p := range points {
+			stats[i] = models.WeeklyStat{Week: i + 1, FantasyPointsPPR: p}
+		}
+		return stats
+	}
+
+	tests := []struct {
+		name  string
+		weeks []models.WeeklyStat
+		want  string
+	}{
+		{"steady starter", weeks(15, 16, 14, 15, 17), "consistent"},
+		{"high mean, high variance", weeks(35, 3, 28, 2, 30), "boom_bust"},
+		{"low mean, high variance", weeks(10, 1, 8, 0), "volatile"},
+		{"in between", weeks(6, 14, 9, 16), "moderate"},
+		{"one game", weeks(22), "insufficient_data"},
+		{"never scored", weeks(0, 0, 0), "insufficient_data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateConsistency(tt.weeks)
+			if got.Classification != tt.want {
+				t.Errorf("Classification = %s (mean %.2f, cv %.2f), want %s", got.Classification, got.MeanPPR, got.CoeffVariation, tt.want)
+			}
+			if got.Games != len(tt.weeks) {
+				t.Errorf("Games = %d, want %d", got.Games, len(tt.weeks))
+			}
+		})
+	}
+
+	// Sample standard deviation of 35, 3, 28, 2, 30 is sqrt(1001.2 / 4)
+	volatile := CalculateConsistency(weeks(35, 3, 28, 2, 30))
+	if math.Abs(volatile.MeanPPR-19.6) > 1e-9 || math.Abs(volatile.StdDevPPR-math.Sqrt(1001.2/4)) > 1e-9 {
+		t.Errorf("mean, stddev = %v, %v, want 19.6, %v", volatile.MeanPPR, volatile.StdDevPPR, math.Sqrt(1001.2/4))
+	}
+}
+
+func TestRateScheduleWithBye(t *testing.T) {
+	games := []models.Game{
+		{Season: 2025, Week: 15, HomeTeam: "ATL", AwayTeam: "CLE"},
+		// Week 16 is ATL's bye
+		{Season: 2025, Week: 17, HomeTeam: "CAR", AwayTeam: "ATL"},
+		{Season: 2025, Week: 18, HomeTeam: "ATL", AwayTeam: "NYJ"},
+	}
+	rankings := []DefensiveRanking{
+		{Rank: 1, Team: "CLE", EPAAllowed: -0.3},
+		{Rank: 32, Team: "CAR", EPAAllowed: 0.3},
+	}
+
+	outlook := &ScheduleOutlook{Team: "ATL", Position: "RB", Season: 2025, FromWeek: 15, AvgOpponentRank: 16, DifficultyScore: 50, Difficulty: "average"}
+	rateSchedule(outlook, games, rankings)
+
+	want := []ScheduleWeek{
+		{Week: 15, Opponent: "CLE", Home: true, OpponentEPA: -0.3, OpponentRank: 1},
+		{Week: 16, Bye: true},
+		{Week: 17, Opponent: "CAR", OpponentEPA: 0.3, OpponentRank: 32},
+		// Unranked defenses count as average
+		{Week: 18, Opponent: "NYJ", Home: true, OpponentRank: 16},
+	}
+	if !slices.Equal(outlook.Weeks, want) {
+		t.Errorf("Weeks = %+v\nwant    %+v", outlook.Weeks, want)
+	}
+	if outlook.GamesRemaining != 3 {
+		t.Errorf("GamesRemaining = %d, want 3", outlook.GamesRemaining)
+	}
+	if wantRank := 49.0 / 3; math.Abs(outlook.AvgOpponentRank-wantRank) > 1e-9 {
+		t.Errorf("AvgOpponentRank = %v, want %v", outlook.AvgOpponentRank, wantRank)
+	}
+	if wantScore := (33 - 49.0/3) / 32 * 100; math.Abs(outlook.DifficultyScore-wantScore) > 1e-9 || outlook.Difficulty != "average" {
+		t.Errorf("difficulty = %v (%s), want %v (average)", outlook.DifficultyScore, outlook.Difficulty, wantScore)
+	}
+
+	// Only elite defenses left
+	tough := &ScheduleOutlook{Team: "ATL", FromWeek: 17}
+	rateSchedule(tough, games[1:2], []DefensiveRanking{{Rank: 2, Team: "CAR"}})
+	if tough.Difficulty != "difficult" || tough.GamesRemaining != 1 {
+		t.Errorf("tough schedule = %s with %d games, want difficult with 1", tough.Difficulty, tough.GamesRemaining)
+	}
+
+	// A schedule of nothing but byes keeps the neutral defaults
+	empty := &ScheduleOutlook{Team: "ATL", FromWeek: 18, AvgOpponentRank: 16, DifficultyScore: 50, Difficulty: "average"}
+	rateSchedule(empty, nil, rankings)
+	if empty.GamesRemaining != 0 || empty.DifficultyScore != 50 || len(empty.Weeks) != 1 || !empty.Weeks[0].Bye {
+		t.Errorf("bye-only schedule = %+v, want one bye week and neutral difficulty", empty)
+	}
+}
+
+func TestUpcomingGamesQuery(t *testing.T) {
+	now := time.Date(2025, 11, 12, 12, 0, 0, 0, time.UTC)
+	game := func(id, home, away string, days int) models.Game {
+		return models.Game{GameID: id, HomeTeam: home, AwayTeam: away, StartTime: now.AddDate(0, 0, days)}
+	}
+	docs := toDocs(t, []models.Game{
+		game("past", "KC", "DEN", -4),
+		game("away", "BUF", "KC", 11),
+		game("home", "KC", "HOU", 4),
+		game("other", "BUF", "MIA", 2),
+	})
+
+	filter, opts := upcomingGamesQuery("kc", now)
+	var got []models.Game
+	decodeDocs(t, findDocs(t, docs, filter, opts), &got)
+
+	var ids []string
+	for _, g := range got {
+		ids = append(ids, g.GameID)
+	}
+	if want := []string{"home", "away"}; !slices.Equal(ids, want) {
+		t.Errorf("upcoming games = %v, want %v", ids, want)
+	}
+
+	// The limit keeps only the next five
+	var season []models.Game
+	for d := 1; d <= 8; d++ {
+		season = append(season, game(strconv.Itoa(d), "KC", "LV", 7*d))
+	}
+	filter, opts = upcomingGamesQuery("KC", now)
+	if n := len(findDocs(t, toDocs(t, season), filter, opts)); n != 5 {
+		t.Errorf("returned %d games, want 5", n)
+	}
+}
+
+func TestTeamTendencies(t *testing.T) {
+	play := func(off, def, playType string, down, toGo int, epa float64) models.Play {
+		return models.Play{Season: 2025, PossessionTeam: off, DefenseTeam: def, PlayType: playType, Down: down, YardsToGo: toGo, EPA: epa}
+	}
+	plays := []models.Play{
+		// Kansas City on offense
+		play("KC", "DEN", "pass", 1, 10, 0.5),
+		play("KC", "DEN", "run", 1, 10, -0.2),
+		play("KC", "DEN", "pass", 2, 8, 1.0),
+		play("KC", "DEN", "run", 3, 1, 0.6),
+		play("KC", "DEN", "pass", 3, 5, -1.0),
+		// Kansas City on defense
+		play("DEN", "KC", "run", 1, 10, 0.3),
+		play("DEN", "KC", "pass", 2, 2, -0.6),
+		// Not counted: special teams, no down, other teams, other seasons
+		play("KC", "DEN", "punt", 4, 12, 0.4),
+		play("KC", "DEN", "pass", 0, 0, 2.0),
+		play("BUF", "MIA", "pass", 1, 10, 3.0),
+		{Season: 2024, PossessionTeam: "KC", DefenseTeam: "LV", PlayType: "pass", Down: 1, YardsToGo: 10, EPA: 5},
+	}
+
+	var groups []tendencyGroup
+	decodeDocs(t, runPipeline(t, tendenciesPipeline("KC", 2025), toDocs(t, plays), nil), &groups)
+	got := buildTendencies("KC", 2025, groups)
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	checkSplit := func(label string, got, want TendencySplit) {
+		t.Helper()
+		if got.Plays != want.Plays || !near(got.PassRate, want.PassRate) || !near(got.EPA, want.EPA) {
+			t.Errorf("%s = %+v, want %+v", label, got, want)
+		}
+	}
+
+	checkSplit("offense overall", got.Offense.Overall, TendencySplit{Plays: 5, PassRate: 0.6, EPA: 0.18})
+	checkSplit("offense early down", got.Offense.EarlyDown, TendencySplit{Plays: 3, PassRate: 2.0 / 3, EPA: 1.3 / 3})
+	if !near(got.Offense.PassEPA, 0.5/3) || !near(got.Offense.RunEPA, 0.2) {
+		t.Errorf("offense pass/run EPA = %v/%v, want %v/0.2", got.Offense.PassEPA, got.Offense.RunEPA, 0.5/3)
+	}
+	checkSplit("offense 1st", got.Offense.BySituation["1st"], TendencySplit{Plays: 2, PassRate: 0.5, EPA: 0.15})
+	checkSplit("offense 2nd_long", got.Offense.BySituation["2nd_long"], TendencySplit{Plays: 1, PassRate: 1, EPA: 1.0})
+	checkSplit("offense 3rd_short", got.Offense.BySituation["3rd_short"], TendencySplit{Plays: 1, PassRate: 0, EPA: 0.6})
+	checkSplit("offense 3rd_medium", got.Offense.BySituation["3rd_medium"], TendencySplit{Plays: 1, PassRate: 1, EPA: -1.0})
+	if len(got.Offense.BySituation) != 4 {
+		t.Errorf("offense situations = %v, want 4", got.Offense.BySituation)
+	}
+
+	checkSplit("defense overall", got.Defense.Overall, TendencySplit{Plays: 2, PassRate: 0.5, EPA: -0.15})
+	checkSplit("defense early down", got.Defense.EarlyDown, TendencySplit{Plays: 2, PassRate: 0.5, EPA: -0.15})
+	checkSplit("defense 2nd_short", got.Defense.BySituation["2nd_short"], TendencySplit{Plays: 1, PassRate: 1, EPA: -0.6})
+	if !near(got.Defense.PassEPA, -0.6) || !near(got.Defense.RunEPA, 0.3) {
+		t.Errorf("defense pass/run EPA = %v/%v, want -0.6/0.3", got.Defense.PassEPA, got.Defense.RunEPA)
+	}
+}
+
+// ngsLeaderNames runs an NGS leaders query over docs and returns the players in order
+func ngsLeaderNames(t *testing.T, docs []bson.M, q NGSLeadersQuery) []string {
+	t.Helper()
+	filter, opts, err := ngsLeadersQuery(q)
+	if err != nil {
+		t.Fatalf("ngsLeadersQuery(%+v) error = %v", q, err)
+	}
+	var stats []models.NextGenStat
+	decodeDocs(t, findDocs(t, docs, filter, opts), &stats)
+
+	names := []string{}
+	for _, s := range stats {
+		names = append(names, s.PlayerName)
+	}
+	return names
+}
+
+func TestNGSLeadersAscending(t *testing.T) {
+	passer := func(name string, week int, timeToThrow float64) models.NextGenStat {
+		return models.NextGenStat{PlayerName: name, Season: 2025, Week: week, StatType: "passing", PassAttempts: 300, AvgTimeToThrow: timeToThrow}
+	}
+	docs := toDocs(t, []models.NextGenStat{
+		passer("Mahomes", 0, 2.81),
+		passer("Tua", 0, 2.35),
+		passer("Allen", 0, 2.97),
+		passer("Unrecorded", 0, 0), // 0 means not recorded, not the quickest release
+		passer("Burrow", 0, 2.52),
+		passer("Tua", 9, 2.10), // weekly rows don't rank with season totals
+		{PlayerName: "Henry", Season: 2025, StatType: "rushing", Carries: 250},
+	})
+
+	tests := []struct {
+		name string
+		q    NGSLeadersQuery
+		want []string
+	}{
+		{"lowest first", NGSLeadersQuery{StatType: "passing", Season: 2025, Metric: "avg_time_to_throw", Ascending: true, Limit: 3}, []string{"Tua", "Burrow", "Mahomes"}},
+		{"highest first", NGSLeadersQuery{StatType: "passing", Season: 2025, Metric: "avg_time_to_throw", Limit: 3}, []string{"Allen", "Mahomes", "Burrow"}},
+		{"ascending keeps every recorded row", NGSLeadersQuery{StatType: "passing", Season: 2025, Metric: "avg_time_to_throw", Ascending: true, Limit: 10}, []string{"Tua", "Burrow", "Mahomes", "Allen"}},
+		{"single week", NGSLeadersQuery{StatType: "passing", Season: 2025, Week: 9, Metric: "avg_time_to_throw", Ascending: true, Limit: 10}, []string{"Tua"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ngsLeaderNames(t, docs, tt.q); !slices.Equal(got, tt.want) {
+				t.Errorf("leaders = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNGSLeadersQueryValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		q    NGSLeadersQuery
+	}{
+		{"unknown stat type", NGSLeadersQuery{StatType: "kicking", Metric: "fg_pct"}},
+		{"metric from another stat type", NGSLeadersQuery{StatType: "passing", Metric: "avg_separation"}},
+		{"injected field name", NGSLeadersQuery{StatType: "passing", Metric: "$where"}},
+		{"week out of range", NGSLeadersQuery{StatType: "passing", Metric: "pass_yards", Week: 23}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ngsLeadersQuery(tt.q); !errors.Is(err, ErrInvalidNGSQuery) {
+				t.Errorf("ngsLeadersQuery() error = %v, want ErrInvalidNGSQuery", err)
+			}
+		})
+	}
+}
+
+func TestNGSLeadersMinSample(t *testing.T) {
+	receiver := func(name, position string, targets int, separation float64) models.NextGenStat {
+		return models.NextGenStat{PlayerName: name, Position: position, Season: 2025, StatType: "receiving", Targets: targets, AvgSeparation: separation}
+	}
+	docs := toDocs(t, []models.NextGenStat{
+		receiver("Gadget", "WR", 2, 6.4), // one-catch outlier
+		receiver("Nacua", "WR", 120, 3.6),
+		receiver("Kelce", "TE", 95, 3.9),
+		receiver("Chase", "WR", 130, 3.1),
+		receiver("Boundary", "WR", 30, 2.9), // exactly at the gate
+		receiver("Depth", "WR", 29, 4.8),
+	})
+
+	tests := []struct {
+		name string
+		q    NGSLeadersQuery
+		want []string
+	}{
+		{"no gate", NGSLeadersQuery{StatType: "receiving", Season: 2025, Metric: "avg_separation", Limit: 3}, []string{"Gadget", "Depth", "Kelce"}},
+		{"min targets", NGSLeadersQuery{StatType: "receiving", Season: 2025, Metric: "avg_separation", MinSample: 30, Limit: 10}, []string{"Kelce", "Nacua", "Chase", "Boundary"}},
+		{"min targets and position", NGSLeadersQuery{StatType: "receiving", Season: 2025, Metric: "avg_separation", Position: "WR", MinSample: 30, Limit: 10}, []string{"Nacua", "Chase", "Boundary"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ngsLeaderNames(t, docs, tt.q); !slices.Equal(got, tt.want) {
+				t.Errorf("leaders = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The gate applies to each stat type's own volume field
+	filter, _, err := ngsLeadersQuery(NGSLeadersQuery{StatType: "rushing", Metric: "rush_yards_over_expected", MinSample: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(filter["carries"], bson.M{"$gte": 50}) {
+		t.Errorf("rushing filter = %v, want carries >= 50", filter)
+	}
+}
+
+func TestNGSHeadlineFor(t *testing.T) {
+	// Sorted by season and week descending, as GetPlayerNGS returns them
+	qb := []models.NextGenStat{
+		{Season: 2024, Week: 12, StatType: "rushing", RushYardsOverExpected: 40},
+		{Season: 2024, Week: 12, StatType: "passing", CompletionPercentageAboveExpectation: 9.1},
+		{Season: 2024, Week: 0, StatType: "passing", CompletionPercentageAboveExpectation: 4.2},
+		{Season: 2023, Week: 0, StatType: "passing", CompletionPercentageAboveExpectation: -1.3},
+	}
+	wr := []models.NextGenStat{
+		{Season: 2024, Week: 7, StatType: "receiving", AvgSeparation: 3.6},
+		{Season: 2024, Week: 6, StatType: "receiving", AvgSeparation: 2.1},
+		{Season: 2023, Week: 0, StatType: "receiving", AvgSeparation: 3.0},
+	}
+
+	tests := []struct {
+		name     string
+		position string
+		stats    []models.NextGenStat
+		want     *NGSHeadline
+	}{
+		{
+			"QB season CPOE", "QB", qb,
+			&NGSHeadline{Season: 2024, StatType: "passing", Metric: "completion_percentage_above_expectation", Label: "CPOE", Value: 4.2},
+		},
+		{
+			// No 2024 season row yet, so the latest week stands in
+			"WR separation from the latest week", "WR", wr,
+			&NGSHeadline{Season: 2024, StatType: "receiving", Metric: "avg_separation", Label: "Separation", Value: 3.6},
+		},
+		{
+			"RB RYOE", "RB", []models.NextGenStat{{Season: 2024, StatType: "rushing", RushYardsOverExpected: 112.5}},
+			&NGSHeadline{Season: 2024, StatType: "rushing", Metric: "rush_yards_over_expected", Label: "RYOE", Value: 112.5},
+		},
+		{"kicker", "K", qb, nil},
+		{"defense", "DST", nil, nil},
+		{"WR with no receiving rows", "WR", qb[:1], nil},
+		{"QB with no NGS data", "QB", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NGSHeadlineFor(tt.position, tt.stats)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NGSHeadlineFor(%s) = %+v, want %+v", tt.position, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeamRosterForWeekFollowsTrade(t *testing.T) {
+	// Traded from NYJ to LV after week 8; LV has its bye in week 10
+	docs := toDocs(t, []models.PlayerTeamStint{
+		{NFLID: "traded", Season: 2024, Team: "NYJ", FromWeek: 1, ToWeek: 8},
+		{NFLID: "traded", Season: 2024, Team: "LV", FromWeek: 9, ToWeek: 9},
+		{NFLID: "traded", Season: 2024, Team: "LV", FromWeek: 11, ToWeek: 17},
+		{NFLID: "stayed", Season: 2024, Team: "NYJ", FromWeek: 1, ToWeek: 17},
+		{NFLID: "traded", Season: 2023, Team: "NYJ", FromWeek: 1, ToWeek: 18},
+	})
+
+	roster := func(team string, week int) []string {
+		var rows []struct {
+			NFLID string `bson:"_id"`
+		}
+		decodeDocs(t, runPipeline(t, teamRosterForWeekPipeline(team, 2024, week), docs, nil), &rows)
+		ids := []string{}
+		for _, r := range rows {
+			ids = append(ids, r.NFLID)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+
+	tests := []struct {
+		team string
+		week int
+		want []string
+	}{
+		{"NYJ", 8, []string{"stayed", "traded"}},
+		{"LV", 8, []string{}},
+		{"NYJ", 9, []string{"stayed"}},
+		{"LV", 9, []string{"traded"}},
+		{"LV", 10, []string{"traded"}}, // the bye keeps the latest team
+		{"NYJ", 12, []string{"stayed"}},
+	}
+	for _, tt := range tests {
+		if got := roster(tt.team, tt.week); !slices.Equal(got, tt.want) {
+			t.Errorf("%s week %d roster = %v, want %v", tt.team, tt.week, got, tt.want)
+		}
+	}
+}
+
+func TestPlayerStatsBatchOmitsUnknown(t *testing.T) {
+	stat := func(id string, season int, seasonType string, yards int) models.PlayerStats {
+		return models.PlayerStats{NFLID: id, Season: season, SeasonType: seasonType, ReceivingYards: yards}
+	}
+	docs := toDocs(t, []models.PlayerStats{
+		stat("wr1", 2024, models.SeasonTypeRegular, 1400),
+		stat("wr1", 2024, models.SeasonTypePost, 120),
+		stat("wr1", 2023, models.SeasonTypeRegular, 900),
+		stat("wr2", 2024, models.SeasonTypeRegular, 1100),
+		stat("wr3", 2024, models.SeasonTypeRegular, 700), // not requested
+		stat("wr4", 2023, models.SeasonTypeRegular, 500), // no 2024 stats
+	})
+
+	var stats []models.PlayerStats
+	filter := playerStatsBatchFilter([]string{"wr1", "wr2", "wr4", "never-existed"}, 2024, models.SeasonTypeRegular)
+	decodeDocs(t, findDocs(t, docs, filter, nil), &stats)
+	got := statsByPlayer(stats)
+
+	want := map[string]int{"wr1": 1400, "wr2": 1100}
+	if len(got) != len(want) {
+		t.Errorf("batch returned %d players, want %v", len(got), want)
+	}
+	for id, yards := range want {
+		if s, ok := got[id]; !ok || s.ReceivingYards != yards {
+			t.Errorf("batch[%s] = %+v (found %v), want %d receiving yards", id, s, ok, yards)
+		}
+	}
+	for _, id := range []string{"wr3", "wr4", "never-existed"} {
+		if _, ok := got[id]; ok {
+			t.Errorf("batch includes %s", id)
+		}
+	}
+}
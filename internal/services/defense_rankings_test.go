@@ -0,0 +1,148 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestDefenseRankingsPipelineByPosition(t *testing.T) {
+	pass := func(def string, week int, epa float64) models.Play {
+		return models.Play{Season: 2025, Week: week, DefenseTeam: def, PasserPlayerID: "qb", ReceiverPlayerID: "wr", EPA: epa}
+	}
+	run := func(def string, week int, epa float64) models.Play {
+		return models.Play{Season: 2025, Week: week, DefenseTeam: def, RusherPlayerID: "rb", EPA: epa}
+	}
+	tePass := func(def string, week int, epa float64) models.Play {
+		return models.Play{Season: 2025, Week: week, DefenseTeam: def, PasserPlayerID: "qb", ReceiverPlayerID: "te", EPA: epa}
+	}
+	// The season's players at each position, as computeDefensiveRankings looks them up
+	nflIDs := map[string][]string{"RB": {"rb"}, "WR": {"wr"}, "TE": {"te"}}
+	plays := []models.Play{
+		// CLE shuts down receivers but is gashed on the ground
+		pass("CLE", 1, -0.4), pass("CLE", 2, -0.2), run("CLE", 1, 0.5), run("CLE", 2, 0.3),
+		// KC is average against both
+		pass("KC", 1, 0.1), pass("KC", 2, 0.0), run("KC", 1, 0.0), run("KC", 2, -0.1),
+		// CAR is soft against receivers until a great week 3
+		pass("CAR", 1, 0.6), pass("CAR", 2, 0.4), pass("CAR", 3, -3.0), run("CAR", 1, -0.3),
+		// Tight ends are ranked on their own targets: KC covers them best, CLE worst
+		tePass("CLE", 1, 0.6), tePass("KC", 1, -0.5), tePass("CAR", 2, 0.2),
+		// Running back receptions count against RBs; a receiver's carry doesn't
+		{Season: 2025, Week: 1, DefenseTeam: "KC", PasserPlayerID: "qb", ReceiverPlayerID: "rb", EPA: 0.9},
+		{Season: 2025, Week: 1, DefenseTeam: "CLE", RusherPlayerID: "wr", EPA: 5.0},
+		// Plays without a defense and from other seasons are ignored
+		pass("", 1, 5.0),
+		{Season: 2024, Week: 1, DefenseTeam: "KC", PasserPlayerID: "qb", ReceiverPlayerID: "wr", EPA: -9.0},
+	}
+
+	tests := []struct {
+		position    string
+		throughWeek int
+		want        []DefensiveRanking
+	}{
+		{"WR", 2, []DefensiveRanking{
+			{Rank: 1, Team: "CLE", Position: "WR", EPAAllowed: -0.3, Plays: 2},
+			{Rank: 2, Team: "KC", Position: "WR", EPAAllowed: 0.05, Plays: 2},
+			{Rank: 3, Team: "CAR", Position: "WR", EPAAllowed: 0.5, Plays: 2},
+		}},
+		{"WR", 0, []DefensiveRanking{
+			{Rank: 1, Team: "CAR", Position: "WR", EPAAllowed: -2.0 / 3, Plays: 3},
+			{Rank: 2, Team: "CLE", Position: "WR", EPAAllowed: -0.3, Plays: 2},
+			{Rank: 3, Team: "KC", Position: "WR", EPAAllowed: 0.05, Plays: 2},
+		}},
+		{"TE", 0, []DefensiveRanking{
+			{Rank: 1, Team: "KC", Position: "TE", EPAAllowed: -0.5, Plays: 1},
+			{Rank: 2, Team: "CAR", Position: "TE", EPAAllowed: 0.2, Plays: 1},
+			{Rank: 3, Team: "CLE", Position: "TE", EPAAllowed: 0.6, Plays: 1},
+		}},
+		{"RB", 0, []DefensiveRanking{
+			{Rank: 1, Team: "CAR", Position: "RB", EPAAllowed: -0.3, Plays: 1},
+			{Rank: 2, Team: "KC", Position: "RB", EPAAllowed: 0.8 / 3, Plays: 3},
+			{Rank: 3, Team: "CLE", Position: "RB", EPAAllowed: 0.4, Plays: 2},
+		}},
+	}
+
+	for _, tt := range tests {
+		pipeline, err := defenseRankingsPipeline(tt.position, 2025, tt.throughWeek, nflIDs[tt.position])
+		if err != nil {
+			t.Fatalf("defenseRankingsPipeline(%s) error = %v", tt.position, err)
+		}
+		var results []defenseEPA
+		decodeDocs(t, runPipeline(t, pipeline, toDocs(t, plays), nil), &results)
+
+		got := rankDefenses(tt.position, results)
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s through week %d: got %+v, want %+v", tt.position, tt.throughWeek, got, tt.want)
+		}
+		for i, want := range tt.want {
+			if got[i].Team != want.Team || got[i].Rank != want.Rank || got[i].Position != want.Position ||
+				got[i].Plays != want.Plays || math.Abs(got[i].EPAAllowed-want.EPAAllowed) > 1e-9 {
+				t.Errorf("%s through week %d: rankings[%d] = %+v, want %+v", tt.position, tt.throughWeek, i, got[i], want)
+			}
+		}
+	}
+
+	if _, err := defenseRankingsPipeline("K", 2025, 0, nil); err == nil {
+		t.Error("defenseRankingsPipeline(K) returned no error")
+	}
+}
+
+func TestRankDefensesWritesDocuments(t *testing.T) {
+	// Aggregated WR targets, sorted by EPA allowed as the pipeline returns them
+	results := []defenseEPA{
+		{Team: "CLE", AvgEPA: -0.21, Plays: 88},
+		{Team: "KC", AvgEPA: 0.02, Plays: 95},
+		{Team: "CAR", AvgEPA: 0.34, Plays: 101},
+	}
+	computedAt := time.Date(2025, 11, 11, 6, 0, 0, 0, time.UTC)
+
+	docs := defenseRankingDocs(2025, 10, 10, rankDefenses("WR", results), computedAt)
+
+	want := []defenseRankingDoc{
+		{Season: 2025, ThroughWeek: 10, CompletedWeek: 10, ComputedAt: computedAt,
+			DefensiveRanking: DefensiveRanking{Rank: 1, Team: "CLE", Position: "WR", EPAAllowed: -0.21, Plays: 88}},
+		{Season: 2025, ThroughWeek: 10, CompletedWeek: 10, ComputedAt: computedAt,
+			DefensiveRanking: DefensiveRanking{Rank: 2, Team: "KC", Position: "WR", EPAAllowed: 0.02, Plays: 95}},
+		{Season: 2025, ThroughWeek: 10, CompletedWeek: 10, ComputedAt: computedAt,
+			DefensiveRanking: DefensiveRanking{Rank: 3, Team: "CAR", Position: "WR", EPAAllowed: 0.34, Plays: 101}},
+	}
+	if len(docs) != len(want) {
+		t.Fatalf("got %d documents, want %d", len(docs), len(want))
+	}
+	for i := range want {
+		if docs[i] != want[i] {
+			t.Errorf("docs[%d] = %+v, want %+v", i, docs[i], want[i])
+		}
+	}
+
+	if got := rankDefenses("WR", nil); len(got) != 0 {
+		t.Errorf("rankDefenses() with no plays = %v, want none", got)
+	}
+}
+
+func TestDefenseRankingsCurrent(t *testing.T) {
+	tests := []struct {
+		name                                     string
+		throughWeek, computedWeek, completedWeek int
+		want                                     bool
+	}{
+		{"whole season after the latest week", 0, 10, 10, true},
+		{"whole season missing a completed week", 0, 9, 10, false},
+		{"stored before the completed week was tracked", 0, 0, 10, false},
+		{"no completed games yet", 0, 0, 0, true},
+		{"week already complete when computed", 6, 9, 10, true},
+		{"week completed since it was computed", 10, 9, 10, false},
+		{"future week covers the latest completed week", 12, 10, 10, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defenseRankingsCurrent(tt.throughWeek, tt.computedWeek, tt.completedWeek); got != tt.want {
+				t.Errorf("defenseRankingsCurrent(%d, %d, %d) = %v, want %v",
+					tt.throughWeek, tt.computedWeek, tt.completedWeek, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// minEPAPercentilePlays is the play count a player needs to be ranked, so a handful of
+// lucky plays doesn't top the position
+const minEPAPercentilePlays = 50
+
+// EPAPercentile places a player's EPA per play among qualified players at the position
+type EPAPercentile struct {
+	NFLID      string  `json:"nfl_id"`
+	Position   string  `json:"position"`
+	Season     int     `json:"season"`
+	EPA        float64 `json:"epa_per_play"`
+	Plays      int     `json:"plays"`
+	Qualified  bool    `json:"qualified"`  // false when the player has fewer than the minimum plays
+	Percentile float64 `json:"percentile"` // 0-100, higher is better
+	Rank       int     `json:"rank,omitempty"`
+	PoolSize   int     `json:"pool_size"`
+	MinPlays   int     `json:"min_plays"`
+}
+
+const epaPoolTTL = 30 * time.Minute
+
+type epaPoolEntry struct {
+	epa       []float64 // Qualified players' EPA per play, ascending
+	expiresAt time.Time
+}
+
+// epaPoolCache holds each position's qualified EPA values per season, shared by all
+// DataService instances since building a pool scans the position's season stats
+var epaPoolCache = struct {
+	sync.RWMutex
+	entries map[string]epaPoolEntry
+}{entries: make(map[string]epaPoolEntry)}
+
+// GetEPAPercentile ranks a player's season EPA per play against every player at the
+// position with at least minEPAPercentilePlays plays. The percentile is the share of the
+// pool the player beats, counting ties as half.
+func (s *DataService) GetEPAPercentile(ctx context.Context, nflID, position string, season int) (*EPAPercentile, error) {
+	result := &EPAPercentile{NFLID: nflID, Position: position, Season: season, MinPlays: minEPAPercentilePlays}
+
+	stats, err := s.GetPlayerStats(ctx, nflID, season, models.SeasonTypeCombined)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player stats: %w", err)
+	}
+	if len(stats) > 0 {
+		result.EPA = stats[0].EPA
+		result.Plays = stats[0].PlayCount
+	}
+
+	pool, err := s.epaPool(ctx, position, season)
+	if err != nil {
+		return nil, err
+	}
+	rankEPA(result, pool)
+	return result, nil
+}
+
+// rankEPA places result's EPA in pool, the ascending EPA of qualified players
+func rankEPA(result *EPAPercentile, pool []float64) {
+	result.PoolSize = len(pool)
+	result.Qualified = result.Plays >= minEPAPercentilePlays
+	if !result.Qualified || len(pool) == 0 {
+		return
+	}
+
+	below := sort.SearchFloat64s(pool, result.EPA)
+	ties := 0
+	for i := below; i < len(pool) && pool[i] == result.EPA; i++ {
+		ties++
+	}
+	result.Percentile = 100 * (float64(below) + 0.5*float64(ties)) / float64(len(pool))
+	result.Rank = len(pool) - below - ties + 1
+}
+
+// epaPool returns the ascending EPA per play of qualified players at a position
+func (s *DataService) epaPool(ctx context.Context, position string, season int) ([]float64, error) {
+	cacheKey := fmt.Sprintf("%d:%s", season, position)
+	epaPoolCache.RLock()
+	entry, found := epaPoolCache.entries[cacheKey]
+	epaPoolCache.RUnlock()
+	if found && time.Now().Before(entry.expiresAt) {
+		return entry.epa, nil
+	}
+
+	var nflIDs []string
+	err := s.db.Collection("players").Distinct(ctx, "nfl_id", bson.M{"position": position, "season": season}).Decode(&nflIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s players: %w", position, err)
+	}
+
+	cursor, err := s.db.Collection("player_stats").Find(ctx, epaPoolFilter(nflIDs, season), options.Find().SetProjection(bson.M{"epa": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s stats: %w", position, err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		EPA float64 `bson:"epa"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode %s stats: %w", position, err)
+	}
+
+	pool := make([]float64, len(rows))
+	for i, r := range rows {
+		pool[i] = r.EPA
+	}
+	sort.Float64s(pool)
+
+	epaPoolCache.Lock()
+	epaPoolCache.entries[cacheKey] = epaPoolEntry{epa: pool, expiresAt: time.Now().Add(epaPoolTTL)}
+	epaPoolCache.Unlock()
+
+	return pool, nil
+}
+
+// epaPoolFilter matches the combined season stats of the players with enough plays to rank
+func epaPoolFilter(nflIDs []string, season int) bson.M {
+	return bson.M{
+		"nfl_id":      bson.M{"$in": nflIDs},
+		"season":      season,
+		"season_type": models.SeasonTypeCombined,
+		"play_count":  bson.M{"$gte": minEPAPercentilePlays},
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestEPAPercentile(t *testing.T) {
+	// 100 qualified receivers with EPA per play from -0.30 to 0.69
+	var stats []models.PlayerStats
+	var ids []string
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("wr%d", i)
+		ids = append(ids, id)
+		stats = append(stats, models.PlayerStats{NFLID: id, Season: 2024, SeasonType: models.SeasonTypeCombined, EPA: float64(i-30) / 100, PlayCount: 60 + i})
+	}
+	stats = append(stats,
+		// A few lucky plays don't qualify, however high the EPA
+		models.PlayerStats{NFLID: "lucky", Season: 2024, SeasonType: models.SeasonTypeCombined, EPA: 2.5, PlayCount: 12},
+		// Only combined rows of the season count
+		models.PlayerStats{NFLID: "wr99", Season: 2024, SeasonType: models.SeasonTypeRegular, EPA: 5, PlayCount: 80},
+		models.PlayerStats{NFLID: "wr99", Season: 2023, SeasonType: models.SeasonTypeCombined, EPA: 5, PlayCount: 80},
+		// A running back isn't in the receiver pool
+		models.PlayerStats{NFLID: "rb1", Season: 2024, SeasonType: models.SeasonTypeCombined, EPA: 3, PlayCount: 200},
+	)
+	ids = append(ids, "lucky")
+
+	var rows []models.PlayerStats
+	decodeDocs(t, findDocs(t, toDocs(t, stats), epaPoolFilter(ids, 2024), nil), &rows)
+	pool := make([]float64, len(rows))
+	for i, r := range rows {
+		pool[i] = r.EPA
+	}
+	sort.Float64s(pool)
+	if len(pool) != 100 {
+		t.Fatalf("pool has %d players, want the 100 qualified receivers", len(pool))
+	}
+
+	tests := []struct {
+		name       string
+		epa        float64
+		plays      int
+		qualified  bool
+		percentile float64
+		rank       int
+	}{
+		{"top receiver", 0.69, 159, true, 99.5, 1},
+		{"bottom receiver", -0.30, 60, true, 0.5, 100},
+		{"middle of the pack", 0.19, 109, true, 49.5, 51},
+		{"between two pool values", 0.195, 80, true, 50, 51},
+		{"too few plays", 2.5, 12, false, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &EPAPercentile{EPA: tt.epa, Plays: tt.plays}
+			rankEPA(got, pool)
+			if got.Qualified != tt.qualified || math.Abs(got.Percentile-tt.percentile) > 1e-9 || got.Rank != tt.rank || got.PoolSize != 100 {
+				t.Errorf("rankEPA() = qualified %v, percentile %v, rank %d of %d, want %v, %v, %d of 100",
+					got.Qualified, got.Percentile, got.Rank, got.PoolSize, tt.qualified, tt.percentile, tt.rank)
+			}
+		})
+	}
+}
+
+func TestEPAPercentileTies(t *testing.T) {
+	got := &EPAPercentile{EPA: 0.1, Plays: 100}
+	rankEPA(got, []float64{-0.1, 0.1, 0.1, 0.3})
+	// Beats one, ties two (counted as half each): (1 + 1) / 4
+	if got.Percentile != 50 || got.Rank != 2 {
+		t.Errorf("tied rankEPA() = percentile %v, rank %d, want 50, 2", got.Percentile, got.Rank)
+	}
+
+	empty := &EPAPercentile{EPA: 0.1, Plays: 100}
+	rankEPA(empty, nil)
+	if empty.Percentile != 0 || empty.Rank != 0 || !empty.Qualified {
+		t.Errorf("rankEPA() with an empty pool = %+v, want qualified but unranked", empty)
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// WeeklyEPA is a player's play-level efficiency for one week
+type WeeklyEPA struct {
+	Week     int     `json:"week"`
+	Plays    int     `json:"plays"`
+	TotalEPA float64 `json:"total_epa"`
+	EPA      float64 `json:"epa_per_play"`
+}
+
+// GetPlayerEPATrend averages a player's EPA per play for each week of a season, optionally
+// limited to fromWeek..toWeek (0 leaves that end open). Weeks without plays are omitted.
+// The season and week filter is served by the plays {season, week} index.
+func (s *DataService) GetPlayerEPATrend(ctx context.Context, nflID string, season, fromWeek, toWeek int) ([]WeeklyEPA, error) {
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, epaTrendPipeline(nflID, season, fromWeek, toWeek))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate EPA by week: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []weeklyEPARow
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode EPA by week: %w", err)
+	}
+	return buildEPATrend(results), nil
+}
+
+// weeklyEPARow is one week of a player's plays from epaTrendPipeline
+type weeklyEPARow struct {
+	Week     int     `bson:"_id"`
+	TotalEPA float64 `bson:"total_epa"`
+	Plays    int     `bson:"plays"`
+}
+
+// epaTrendPipeline totals a player's EPA and plays by week, in week order
+func epaTrendPipeline(nflID string, season, fromWeek, toWeek int) mongo.Pipeline {
+	match := bson.M{
+		"season": season,
+		"$or": []bson.M{
+			{"passer_player_id": nflID},
+			{"rusher_player_id": nflID},
+			{"receiver_player_id": nflID},
+		},
+	}
+	weekFilter := bson.M{}
+	if fromWeek > 0 {
+		weekFilter["$gte"] = fromWeek
+	}
+	if toWeek > 0 {
+		weekFilter["$lte"] = toWeek
+	}
+	if len(weekFilter) > 0 {
+		match["week"] = weekFilter
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$week",
+			"total_epa": bson.M{"$sum": "$epa"},
+			"plays":     bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+}
+
+// buildEPATrend averages each week's EPA per play
+func buildEPATrend(results []weeklyEPARow) []WeeklyEPA {
+	trend := make([]WeeklyEPA, 0, len(results))
+	for _, r := range results {
+		week := WeeklyEPA{Week: r.Week, Plays: r.Plays, TotalEPA: r.TotalEPA}
+		if r.Plays > 0 {
+			week.EPA = r.TotalEPA / float64(r.Plays)
+		}
+		trend = append(trend, week)
+	}
+	return trend
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestPlayerEPATrend(t *testing.T) {
+	const wr = "00-0036900"
+	var plays []models.Play
+	add := func(season, week int, receiver string, epas ...float64) {
+		for _, epa := range epas {
+			plays = append(plays, models.Play{Season: season, Week: week, ReceiverPlayerID: receiver, EPA: epa})
+		}
+	}
+	// Seeded out of week order
+	add(2024, 3, wr, 1.5, -0.5)
+	add(2024, 1, wr, 0.6, 0.2, -0.2)
+	add(2024, 2, wr, -1.0)
+	add(2024, 2, "00-0000002", 4) // another receiver
+	add(2023, 1, wr, 9)           // last season
+	docs := toDocs(t, plays)
+
+	type week struct {
+		week, plays int
+		epa         float64
+	}
+	tests := []struct {
+		name     string
+		from, to int
+		want     []week
+	}{
+		{"whole season", 0, 0, []week{{1, 3, 0.2}, {2, 1, -1.0}, {3, 2, 0.5}}},
+		{"from week 2", 2, 0, []week{{2, 1, -1.0}, {3, 2, 0.5}}},
+		{"through week 2", 0, 2, []week{{1, 3, 0.2}, {2, 1, -1.0}}},
+		{"single week", 3, 3, []week{{3, 2, 0.5}}},
+		{"no plays in range", 5, 8, []week{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rows []weeklyEPARow
+			decodeDocs(t, runPipeline(t, epaTrendPipeline(wr, 2024, tt.from, tt.to), docs, nil), &rows)
+			got := buildEPATrend(rows)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("trend = %+v, want %d weeks", got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if g.Week != w.week || g.Plays != w.plays || math.Abs(g.EPA-w.epa) > 1e-9 || math.Abs(g.TotalEPA-w.epa*float64(w.plays)) > 1e-9 {
+					t.Errorf("trend[%d] = week %d, %d plays, %v/play (total %v), want week %d, %d plays, %v/play",
+						i, g.Week, g.Plays, g.EPA, g.TotalEPA, w.week, w.plays, w.epa)
+				}
+			}
+		})
+	}
+}
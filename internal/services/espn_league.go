@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/pkg/espn"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ESPNLeagues reads users' connected leagues straight from the ESPN Fantasy API with
+// their saved cookies
+type ESPNLeagues struct {
+	db *mongo.Database
+}
+
+func NewESPNLeagues(db *mongo.Database) *ESPNLeagues {
+	return &ESPNLeagues{db: db}
+}
+
+// client returns an ESPN client for the user's saved league and the user's team in it
+func (l *ESPNLeagues) client(ctx context.Context, userID bson.ObjectID) (*espn.Client, int, error) {
+	user, err := ConnectedESPNUser(ctx, l.db, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return espn.NewClient(strconv.Itoa(user.LeagueID), user.Year, user.ESPNSWID, user.ESPNS2), user.TeamID, nil
+}
+
+// Matchup returns the user's matchup for the week with ESPN's projected scores and win
+// probabilities
+func (l *ESPNLeagues) Matchup(ctx context.Context, userID bson.ObjectID, week int) (*models.ESPNMatchup, error) {
+	client, teamID, err := l.client(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetMatchup(ctx, teamID, week)
+}
+
+// LeagueSettings returns the settings of the user's league, including its starting lineup
+// slots in optimizer terms
+func (l *ESPNLeagues) LeagueSettings(ctx context.Context, userID bson.ObjectID) (*models.ESPNLeagueSettings, error) {
+	client, _, err := l.client(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	league, err := client.GetLeague(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &league.Settings, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrESPNNotConnected is returned when a user hasn't saved ESPN league credentials
+var ErrESPNNotConnected = errors.New("ESPN league not connected")
+
+// ESPNRosterSource fetches the fantasy roster of a user's connected ESPN league
+type ESPNRosterSource interface {
+	FetchRoster(ctx context.Context, userID bson.ObjectID) ([]RosterPlayer, error)
+}
+
+// ConnectedESPNUser loads a user with their saved ESPN league credentials, or returns
+// ErrESPNNotConnected when they haven't saved them
+func ConnectedESPNUser(ctx context.Context, db *mongo.Database, userID bson.ObjectID) (*models.User, error) {
+	var user models.User
+	err := db.Collection("users").FindOne(ctx, bson.M{"_id": userID},
+		options.FindOne().SetProjection(bson.M{"espn_s2": 1, "espn_swid": 1, "league_id": 1, "team_id": 1, "year": 1})).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrESPNNotConnected
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	if user.ESPNS2 == "" || user.ESPNSWID == "" || user.LeagueID == 0 {
+		return nil, ErrESPNNotConnected
+	}
+	return &user, nil
+}
+
+// ESPNServiceError is a non-200 response from the Flask ESPN service
+type ESPNServiceError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ESPNServiceError) Error() string {
+	return fmt.Sprintf("ESPN service returned %d: %s", e.StatusCode, e.Body)
+}
+
+// FlaskESPNClient calls the Flask ESPN service for one user's league, sending the user's
+// saved cookies, league, team and year as headers so the service reads their league rather
+// than its configured default
+type FlaskESPNClient struct {
+	serviceURL string
+	httpClient *http.Client
+}
+
+func NewFlaskESPNClient(serviceURL string) *FlaskESPNClient {
+	return &FlaskESPNClient{
+		serviceURL: serviceURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Get fetches path (with any query string) from the service for the user's league and
+// decodes the JSON response into out
+func (f *FlaskESPNClient) Get(ctx context.Context, user *models.User, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.serviceURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build ESPN service request: %w", err)
+	}
+	req.Header.Set("X-ESPN-S2", user.ESPNS2)
+	req.Header.Set("X-ESPN-SWID", user.ESPNSWID)
+	req.Header.Set("X-ESPN-League-ID", strconv.Itoa(user.LeagueID))
+	req.Header.Set("X-ESPN-Team-ID", strconv.Itoa(user.TeamID))
+	if user.Year > 0 {
+		req.Header.Set("X-ESPN-Year", strconv.Itoa(user.Year))
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach ESPN service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &ESPNServiceError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse ESPN service response: %w", err)
+	}
+	return nil
+}
+
+// FlaskESPNRosters reads rosters from the Flask ESPN service for users with saved credentials
+type FlaskESPNRosters struct {
+	db     *mongo.Database
+	client *FlaskESPNClient
+}
+
+func NewFlaskESPNRosters(db *mongo.Database, client *FlaskESPNClient) *FlaskESPNRosters {
+	return &FlaskESPNRosters{db: db, client: client}
+}
+
+// FetchRoster returns the user's ESPN roster, or ErrESPNNotConnected when they haven't
+// saved credentials
+func (r *FlaskESPNRosters) FetchRoster(ctx context.Context, userID bson.ObjectID) ([]RosterPlayer, error) {
+	user, err := ConnectedESPNUser(ctx, r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The service's roster players share RosterPlayer's field names
+	var roster []RosterPlayer
+	if err := r.client.Get(ctx, user, "/api/espn/roster", &roster); err != nil {
+		return nil, fmt.Errorf("failed to fetch roster: %w", err)
+	}
+	return roster, nil
+}
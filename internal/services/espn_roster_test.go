@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestFlaskESPNClientForwardsCredentials(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		if r.URL.Path != "/api/espn/roster" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[{"name":"Josh Allen","position":"QB","proTeam":"BUF","lineupSlot":"QB","projectedPoints":24.1}]`))
+	}))
+	defer srv.Close()
+
+	user := &models.User{ESPNS2: "user-s2", ESPNSWID: "{USER-SWID}", LeagueID: 123456, TeamID: 7, Year: 2025}
+	var roster []RosterPlayer
+	if err := NewFlaskESPNClient(srv.URL).Get(context.Background(), user, "/api/espn/roster", &roster); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	want := map[string]string{
+		"X-ESPN-S2":        "user-s2",
+		"X-ESPN-SWID":      "{USER-SWID}",
+		"X-ESPN-League-ID": "123456",
+		"X-ESPN-Team-ID":   "7",
+		"X-ESPN-Year":      "2025",
+	}
+	for header, value := range want {
+		if got.Get(header) != value {
+			t.Errorf("%s = %q, want %q", header, got.Get(header), value)
+		}
+	}
+	if len(roster) != 1 || roster[0].Name != "Josh Allen" || roster[0].ProjectedPoints != 24.1 {
+		t.Errorf("roster = %+v, want Josh Allen projected 24.1", roster)
+	}
+
+	err := NewFlaskESPNClient(srv.URL).Get(context.Background(), user, "/api/espn/missing", &roster)
+	var serviceErr *ESPNServiceError
+	if !errors.As(err, &serviceErr) || serviceErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Get() on a 404 error = %v, want an ESPNServiceError with status 404", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/prompts"
 	"github.com/ai-atl/nfl-platform/pkg/gemini"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -15,16 +16,25 @@ type FantasyAdvisorService struct {
 	db          *mongo.Database
 	gemini      *gemini.Client
 	dataService *DataService
+	scoring     ScoringConfig
 }
 
 func NewFantasyAdvisorService(db *mongo.Database) *FantasyAdvisorService {
 	return &FantasyAdvisorService{
 		db:          db,
-		gemini:      gemini.NewClient(),
+		gemini:      gemini.NewClient(gemini.AnalysisTier()),
 		dataService: NewDataService(db),
+		scoring:     ScoringPPR,
 	}
 }
 
+// WithScoring returns a copy of the service that scores games with the given settings
+func (s *FantasyAdvisorService) WithScoring(scoring ScoringConfig) *FantasyAdvisorService {
+	scored := *s
+	scored.scoring = scoring
+	return &scored
+}
+
 // PlayerComparison contains enriched data for comparing two players
 type PlayerComparison struct {
 	PlayerAName    string
@@ -39,38 +49,38 @@ type PlayerComparison struct {
 // EnrichedPlayerData contains all the data needed for AI fantasy advice
 type EnrichedPlayerData struct {
 	// Basic info from ESPN
-	Name            string
-	Position        string
-	Team            string
-	ProjectedPoints float64
-	SeasonAverage   float64
-	InjuryStatus    string
-	IsInjured       bool
+	Name            string  `json:"name"`
+	Position        string  `json:"position"`
+	Team            string  `json:"team"`
+	ProjectedPoints float64 `json:"projected_points"`
+	SeasonAverage   float64 `json:"season_average"`
+	InjuryStatus    string  `json:"injury_status,omitempty"`
+	IsInjured       bool    `json:"is_injured"`
 
 	// Database enrichments
-	RecentGames      []GamePerformance
-	AvgEPA           float64
-	PlayerTrend      string // "hot", "cold", "neutral"
-	TrendDescription string
-	OpponentTeam     string
-	OpponentRank     int // Defensive rank vs this position (1=best, 32=worst)
-	MatchupAnalysis  string
+	RecentGames      []GamePerformance `json:"recent_games"`
+	AvgEPA           float64           `json:"avg_epa"`
+	PlayerTrend      string            `json:"trend"` // "hot", "cold", "neutral"
+	TrendDescription string            `json:"trend_description"`
+	OpponentTeam     string            `json:"opponent_team,omitempty"`
+	OpponentRank     int               `json:"opponent_rank,omitempty"` // Defensive rank vs this position (1=best, 32=worst)
+	MatchupAnalysis  string            `json:"matchup_analysis,omitempty"`
 }
 
 type GamePerformance struct {
-	Week           int
-	Opponent       string
-	PassingYards   int
-	PassingTDs     int
-	Interceptions  int
-	RushingYards   int
-	RushingTDs     int
-	Receptions     int
-	Targets        int
-	ReceivingYards int
-	ReceivingTDs   int
-	FantasyPoints  float64
-	EPA            float64
+	Week           int     `json:"week"`
+	Opponent       string  `json:"opponent"`
+	PassingYards   int     `json:"passing_yards"`
+	PassingTDs     int     `json:"passing_tds"`
+	Interceptions  int     `json:"interceptions"`
+	RushingYards   int     `json:"rushing_yards"`
+	RushingTDs     int     `json:"rushing_tds"`
+	Receptions     int     `json:"receptions"`
+	Targets        int     `json:"targets"`
+	ReceivingYards int     `json:"receiving_yards"`
+	ReceivingTDs   int     `json:"receiving_tds"`
+	FantasyPoints  float64 `json:"fantasy_points"`
+	EPA            float64 `json:"epa"`
 }
 
 // GetStartSitAdvice provides AI-powered start/sit recommendations with database enrichment
@@ -87,7 +97,10 @@ func (s *FantasyAdvisorService) GetStartSitAdvice(ctx context.Context, playerANa
 	enrichedB := s.enrichPlayerData(ctx, playerBName, playerBPos, playerBTeam, playerBProj, playerBSeason, playerBInj, playerBInjStatus, currentSeason, currentWeek)
 
 	// Build comprehensive prompt with database context
-	prompt := s.buildComparisonPrompt(enrichedA, enrichedB)
+	prompt, err := s.buildComparisonPrompt(enrichedA, enrichedB)
+	if err != nil {
+		return nil, err
+	}
 
 	// Get AI recommendation
 	response, err := s.gemini.GenerateWithRetry(ctx, prompt, 3)
@@ -127,8 +140,41 @@ func (s *FantasyAdvisorService) enrichPlayerData(ctx context.Context, name, posi
 		return enriched
 	}
 
+	s.addDatabaseContext(ctx, enriched, player.NFLID, season, currentWeek)
+	return enriched
+}
+
+// EnrichPlayer builds a player's comparison data (recent games, EPA, trend and next
+// matchup) from the database alone, without ESPN projections or an AI call
+func (s *FantasyAdvisorService) EnrichPlayer(ctx context.Context, nflID string, season, currentWeek int) (*EnrichedPlayerData, error) {
+	player, err := s.dataService.GetPlayer(ctx, nflID, season)
+	if err != nil {
+		return nil, err
+	}
+
+	enriched := &EnrichedPlayerData{
+		Name:      player.Name,
+		Position:  player.Position,
+		Team:      player.Team,
+		IsInjured: player.Status == "INA" || isInjuryStatus(player.StatusDescriptionAbbr),
+	}
+	if enriched.IsInjured {
+		enriched.InjuryStatus = models.GetPlayerStatusDescription(player.Status, player.StatusDescriptionAbbr)
+	}
+
+	weeks, _ := s.dataService.GetPlayerWeeklyStatsRange(ctx, nflID, season, 0, currentWeek-1)
+	if len(weeks) > 0 {
+		enriched.SeasonAverage = CalculateConsistency(weeks).MeanPPR
+	}
+
+	s.addDatabaseContext(ctx, enriched, nflID, season, currentWeek)
+	return enriched, nil
+}
+
+// addDatabaseContext fills in recent games, EPA, trend and next matchup for a player
+func (s *FantasyAdvisorService) addDatabaseContext(ctx context.Context, enriched *EnrichedPlayerData, nflID string, season, currentWeek int) {
 	// Get recent game performances (last 5 games)
-	recentGames, avgEPA := s.getRecentGamePerformances(ctx, player.NFLID, position, season, currentWeek, 5)
+	recentGames, avgEPA := s.getRecentGamePerformances(ctx, nflID, enriched.Position, season, currentWeek, 5)
 	enriched.RecentGames = recentGames
 	enriched.AvgEPA = avgEPA
 
@@ -136,15 +182,13 @@ func (s *FantasyAdvisorService) enrichPlayerData(ctx context.Context, name, posi
 	enriched.PlayerTrend, enriched.TrendDescription = s.analyzePlayerTrend(recentGames)
 
 	// Get next opponent and defensive matchup
-	opponent := s.getNextOpponent(ctx, team, season, currentWeek)
+	opponent := s.getNextOpponent(ctx, enriched.Team, season, currentWeek)
 	if opponent != "" {
 		enriched.OpponentTeam = opponent
-		rank, analysis := s.getDefensiveMatchup(ctx, opponent, position, season, currentWeek)
+		rank, analysis := s.getDefensiveMatchup(ctx, opponent, enriched.Position, season, currentWeek)
 		enriched.OpponentRank = rank
 		enriched.MatchupAnalysis = analysis
 	}
-
-	return enriched
 }
 
 // findPlayerByName searches for a player by name and team
@@ -173,6 +217,49 @@ func (s *FantasyAdvisorService) findPlayerByName(ctx context.Context, name, team
 
 // getRecentGamePerformances fetches last N games for a player from plays collection
 func (s *FantasyAdvisorService) getRecentGamePerformances(ctx context.Context, nflID, position string, season, currentWeek, numGames int) ([]GamePerformance, float64) {
+	pipeline := recentGamesPipeline(nflID, position, season, currentWeek, numGames)
+	if pipeline == nil {
+		return nil, 0
+	}
+
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0
+	}
+	defer cursor.Close(ctx)
+
+	var rows []recentGameRow
+	for cursor.Next(ctx) {
+		var row recentGameRow
+		if err := cursor.Decode(&row); err != nil {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return buildRecentGames(rows, s.scoring)
+}
+
+// recentGameRow is one week of a player's plays, as grouped by recentGamesPipeline
+type recentGameRow struct {
+	Week           int     `bson:"_id"`
+	Opponent       string  `bson:"opponent"`
+	PassingYards   int     `bson:"passing_yards"`
+	PassingTDs     int     `bson:"passing_tds"`
+	Interceptions  int     `bson:"interceptions"`
+	RushingYards   int     `bson:"rushing_yards"`
+	RushingTDs     int     `bson:"rushing_tds"`
+	Receptions     int     `bson:"receptions"`
+	Targets        int     `bson:"targets"`
+	ReceivingYards int     `bson:"receiving_yards"`
+	ReceivingTDs   int     `bson:"receiving_tds"`
+	AvgEPA         float64 `bson:"avg_epa"`
+}
+
+// recentGamesPipeline groups a player's plays by week over the numGames most recent
+// weeks before currentWeek, newest first. It returns nil for positions without
+// player-level play stats.
+func recentGamesPipeline(nflID, position string, season, currentWeek, numGames int) mongo.Pipeline {
 	// Build position-specific match condition
 	var playerMatch bson.M
 	switch position {
@@ -188,14 +275,18 @@ func (s *FantasyAdvisorService) getRecentGamePerformances(ctx context.Context, n
 	case "WR", "TE":
 		playerMatch = bson.M{"receiver_player_id": nflID}
 	default:
-		return nil, 0
+		return nil
 	}
 
 	// Aggregate plays by week
-	pipeline := mongo.Pipeline{
+	// $max is an aggregation expression and isn't valid in a $match query, so the lower
+	// bound (the last six weeks, never before week 1) is computed here
+	fromWeek := max(1, currentWeek-6)
+
+	return mongo.Pipeline{
 		{{Key: "$match", Value: bson.M{
 			"season": season,
-			"week":   bson.M{"$lt": currentWeek, "$gte": bson.M{"$max": []interface{}{1, currentWeek - 6}}},
+			"week":   bson.M{"$lt": currentWeek, "$gte": fromWeek},
 		}}},
 		{{Key: "$match", Value: playerMatch}},
 		{{Key: "$group", Value: bson.M{
@@ -281,40 +372,25 @@ func (s *FantasyAdvisorService) getRecentGamePerformances(ctx context.Context, n
 		{{Key: "$sort", Value: bson.M{"_id": -1}}},
 		{{Key: "$limit", Value: numGames}},
 	}
+}
 
-	cursor, err := s.db.Collection("plays").Aggregate(ctx, pipeline)
-	if err != nil {
-		return nil, 0
-	}
-	defer cursor.Close(ctx)
-
+// buildRecentGames scores each week's row and averages the weekly EPA
+func buildRecentGames(rows []recentGameRow, scoring ScoringConfig) ([]GamePerformance, float64) {
 	var games []GamePerformance
 	totalEPA := 0.0
 	epaCount := 0
 
-	for cursor.Next(ctx) {
-		var result struct {
-			Week           int     `bson:"_id"`
-			Opponent       string  `bson:"opponent"`
-			PassingYards   int     `bson:"passing_yards"`
-			PassingTDs     int     `bson:"passing_tds"`
-			Interceptions  int     `bson:"interceptions"`
-			RushingYards   int     `bson:"rushing_yards"`
-			RushingTDs     int     `bson:"rushing_tds"`
-			Receptions     int     `bson:"receptions"`
-			Targets        int     `bson:"targets"`
-			ReceivingYards int     `bson:"receiving_yards"`
-			ReceivingTDs   int     `bson:"receiving_tds"`
-			AvgEPA         float64 `bson:"avg_epa"`
-		}
-
-		if err := cursor.Decode(&result); err != nil {
-			continue
-		}
-
-		// Calculate PPR fantasy points
-		fantasyPoints := s.calculateFantasyPoints(result.PassingYards, result.PassingTDs, result.Interceptions,
-			result.RushingYards, result.RushingTDs, result.ReceivingYards, result.ReceivingTDs, result.Receptions)
+	for _, result := range rows {
+		fantasyPoints := scoring.Points(StatLine{
+			PassingYards:   result.PassingYards,
+			PassingTDs:     result.PassingTDs,
+			Interceptions:  result.Interceptions,
+			RushingYards:   result.RushingYards,
+			RushingTDs:     result.RushingTDs,
+			Receptions:     result.Receptions,
+			ReceivingYards: result.ReceivingYards,
+			ReceivingTDs:   result.ReceivingTDs,
+		})
 
 		games = append(games, GamePerformance{
 			Week:           result.Week,
@@ -344,27 +420,6 @@ func (s *FantasyAdvisorService) getRecentGamePerformances(ctx context.Context, n
 	return games, avgEPA
 }
 
-// calculateFantasyPoints uses standard PPR scoring
-func (s *FantasyAdvisorService) calculateFantasyPoints(passYards, passTDs, ints, rushYards, rushTDs, recYards, recTDs, receptions int) float64 {
-	points := 0.0
-
-	// Passing (1 pt per 25 yards, 4 pts per TD, -2 per INT)
-	points += float64(passYards) * 0.04
-	points += float64(passTDs) * 4.0
-	points -= float64(ints) * 2.0
-
-	// Rushing (1 pt per 10 yards, 6 pts per TD)
-	points += float64(rushYards) * 0.1
-	points += float64(rushTDs) * 6.0
-
-	// Receiving (1 pt per 10 yards, 6 pts per TD, 1 pt per reception for PPR)
-	points += float64(recYards) * 0.1
-	points += float64(recTDs) * 6.0
-	points += float64(receptions) * 1.0
-
-	return points
-}
-
 // analyzePlayerTrend determines if player is hot, cold, or neutral
 func (s *FantasyAdvisorService) analyzePlayerTrend(games []GamePerformance) (string, string) {
 	if len(games) < 2 {
@@ -525,100 +580,17 @@ func (s *FantasyAdvisorService) getDefensiveMatchup(ctx context.Context, defense
 	return rank, analysis
 }
 
-// buildComparisonPrompt creates a comprehensive prompt with database context
-func (s *FantasyAdvisorService) buildComparisonPrompt(playerA, playerB *EnrichedPlayerData) string {
-	var prompt strings.Builder
-
-	prompt.WriteString("You are an expert fantasy football advisor with access to comprehensive NFL play-by-play data, recent game logs, and defensive matchup analysis.\n\n")
-	prompt.WriteString("Compare these two players and recommend which one to START this week. Base your decision on:\n")
-	prompt.WriteString("1. Recent performance trends (last 3-5 games)\n")
-	prompt.WriteString("2. This week's defensive matchup quality\n")
-	prompt.WriteString("3. Statistical efficiency (EPA - Expected Points Added)\n")
-	prompt.WriteString("4. Health status and injury concerns\n")
-	prompt.WriteString("5. Projected points and consistency\n\n")
-
-	// Player A details
-	prompt.WriteString(fmt.Sprintf("=== PLAYER A: %s ===\n", playerA.Name))
-	prompt.WriteString(fmt.Sprintf("Position: %s | Team: %s\n", playerA.Position, playerA.Team))
-	prompt.WriteString(fmt.Sprintf("ESPN Projected Points: %.1f\n", playerA.ProjectedPoints))
-	prompt.WriteString(fmt.Sprintf("Season Average: %.1f PPG\n", playerA.SeasonAverage))
-	prompt.WriteString(fmt.Sprintf("Health: %s", playerA.InjuryStatus))
-	if playerA.IsInjured {
-		prompt.WriteString(" ⚠️ INJURED")
-	}
-	prompt.WriteString("\n\n")
-
-	if len(playerA.RecentGames) > 0 {
-		prompt.WriteString(fmt.Sprintf("Recent Trend: %s\n", playerA.TrendDescription))
-		prompt.WriteString(fmt.Sprintf("Average EPA: %.3f per play\n", playerA.AvgEPA))
-		prompt.WriteString("Last 3 Games:\n")
-		for i, game := range playerA.RecentGames {
-			if i >= 3 {
-				break
-			}
-			prompt.WriteString(fmt.Sprintf("  Week %d vs %s: %.1f pts", game.Week, game.Opponent, game.FantasyPoints))
-			if playerA.Position == "QB" && game.PassingYards > 0 {
-				prompt.WriteString(fmt.Sprintf(" (%d pass yds, %d TD, %d INT)", game.PassingYards, game.PassingTDs, game.Interceptions))
-			} else if playerA.Position == "RB" {
-				prompt.WriteString(fmt.Sprintf(" (%d rush yds, %d rush TD, %d rec)", game.RushingYards, game.RushingTDs, game.Receptions))
-			} else if playerA.Position == "WR" || playerA.Position == "TE" {
-				prompt.WriteString(fmt.Sprintf(" (%d rec, %d yds, %d TD)", game.Receptions, game.ReceivingYards, game.ReceivingTDs))
-			}
-			prompt.WriteString("\n")
-		}
-		prompt.WriteString("\n")
-	}
-
-	if playerA.MatchupAnalysis != "" {
-		prompt.WriteString(fmt.Sprintf("This Week's Matchup: %s\n", playerA.MatchupAnalysis))
-	}
-	prompt.WriteString("\n")
-
-	// Player B details
-	prompt.WriteString(fmt.Sprintf("=== PLAYER B: %s ===\n", playerB.Name))
-	prompt.WriteString(fmt.Sprintf("Position: %s | Team: %s\n", playerB.Position, playerB.Team))
-	prompt.WriteString(fmt.Sprintf("ESPN Projected Points: %.1f\n", playerB.ProjectedPoints))
-	prompt.WriteString(fmt.Sprintf("Season Average: %.1f PPG\n", playerB.SeasonAverage))
-	prompt.WriteString(fmt.Sprintf("Health: %s", playerB.InjuryStatus))
-	if playerB.IsInjured {
-		prompt.WriteString(" ⚠️ INJURED")
-	}
-	prompt.WriteString("\n\n")
-
-	if len(playerB.RecentGames) > 0 {
-		prompt.WriteString(fmt.Sprintf("Recent Trend: %s\n", playerB.TrendDescription))
-		prompt.WriteString(fmt.Sprintf("Average EPA: %.3f per play\n", playerB.AvgEPA))
-		prompt.WriteString("Last 3 Games:\n")
-		for i, game := range playerB.RecentGames {
-			if i >= 3 {
-				break
-			}
-			prompt.WriteString(fmt.Sprintf("  Week %d vs %s: %.1f pts", game.Week, game.Opponent, game.FantasyPoints))
-			if playerB.Position == "QB" && game.PassingYards > 0 {
-				prompt.WriteString(fmt.Sprintf(" (%d pass yds, %d TD, %d INT)", game.PassingYards, game.PassingTDs, game.Interceptions))
-			} else if playerB.Position == "RB" {
-				prompt.WriteString(fmt.Sprintf(" (%d rush yds, %d rush TD, %d rec)", game.RushingYards, game.RushingTDs, game.Receptions))
-			} else if playerB.Position == "WR" || playerB.Position == "TE" {
-				prompt.WriteString(fmt.Sprintf(" (%d rec, %d yds, %d TD)", game.Receptions, game.ReceivingYards, game.ReceivingTDs))
-			}
-			prompt.WriteString("\n")
-		}
-		prompt.WriteString("\n")
-	}
-
-	if playerB.MatchupAnalysis != "" {
-		prompt.WriteString(fmt.Sprintf("This Week's Matchup: %s\n", playerB.MatchupAnalysis))
-	}
-	prompt.WriteString("\n")
-
-	prompt.WriteString("=== YOUR TASK ===\n")
-	prompt.WriteString("Provide your recommendation in EXACTLY this format:\n\n")
-	prompt.WriteString("RECOMMENDATION: [A or B]\n")
-	prompt.WriteString("CONFIDENCE: [number from 0-100]\n")
-	prompt.WriteString("REASONING: [2-3 sentences explaining your choice, referencing specific stats, trends, and matchup quality]\n\n")
-	prompt.WriteString("Be data-driven and concise. Reference specific numbers from the data above.")
+// comparisonPlayer is one side of the start/sit prompt, labelled A or B
+type comparisonPlayer struct {
+	Label string
+	*EnrichedPlayerData
+}
 
-	return prompt.String()
+// buildComparisonPrompt creates a comprehensive prompt with database context
+func (s *FantasyAdvisorService) buildComparisonPrompt(playerA, playerB *EnrichedPlayerData) (string, error) {
+	return prompts.Render(prompts.StartSit, struct{ Players []comparisonPlayer }{
+		Players: []comparisonPlayer{{"A", playerA}, {"B", playerB}},
+	})
 }
 
 // parseAIResponse extracts structured data from AI response
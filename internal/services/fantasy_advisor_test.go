@@ -0,0 +1,115 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// recentGames runs the recent-games pipeline for a player over plays and scores the rows
+func recentGames(t *testing.T, plays []models.Play, nflID, position string, season, week int) ([]GamePerformance, float64) {
+	t.Helper()
+	pipeline := recentGamesPipeline(nflID, position, season, week, 5)
+	if pipeline == nil {
+		t.Fatalf("recentGamesPipeline(%s) = nil", position)
+	}
+	var rows []recentGameRow
+	decodeDocs(t, runPipeline(t, pipeline, toDocs(t, plays), nil), &rows)
+	return buildRecentGames(rows, ScoringPPR)
+}
+
+func gameWeeks(games []GamePerformance) []int {
+	weeks := []int{}
+	for _, g := range games {
+		weeks = append(weeks, g.Week)
+	}
+	return weeks
+}
+
+func TestComparedPlayersRecentGames(t *testing.T) {
+	const wr, rb = "00-0036900", "00-0037100"
+	var plays []models.Play
+	for week := 1; week <= 10; week++ {
+		plays = append(plays,
+			models.Play{Season: 2025, Week: week, DefenseTeam: "DEN", PasserPlayerID: "qb", ReceiverPlayerID: wr, Yards: 10 + week, EPA: 0.5},
+			models.Play{Season: 2025, Week: week, DefenseTeam: "DEN", RusherPlayerID: rb, Yards: 4, EPA: -0.1},
+			models.Play{Season: 2025, Week: week, DefenseTeam: "DEN", PasserPlayerID: "qb", ReceiverPlayerID: rb, Yards: 6, EPA: 0.3},
+		)
+	}
+	plays = append(plays,
+		models.Play{Season: 2025, Week: 9, DefenseTeam: "DEN", PasserPlayerID: "qb", ReceiverPlayerID: wr, Yards: 25, Touchdown: true, EPA: 2.5},
+		models.Play{Season: 2024, Week: 9, DefenseTeam: "LV", PasserPlayerID: "qb", ReceiverPlayerID: wr, Yards: 80, EPA: 4}, // another season
+	)
+
+	// Comparing at week 11 takes the five most recent completed weeks, newest first
+	wrGames, wrEPA := recentGames(t, plays, wr, "WR", 2025, 11)
+	rbGames, rbEPA := recentGames(t, plays, rb, "RB", 2025, 11)
+
+	for name, games := range map[string][]GamePerformance{"WR": wrGames, "RB": rbGames} {
+		if got, want := gameWeeks(games), []int{10, 9, 8, 7, 6}; !slices.Equal(got, want) {
+			t.Errorf("%s recent weeks = %v, want %v", name, got, want)
+		}
+	}
+
+	week9 := wrGames[1]
+	if week9.Receptions != 2 || week9.ReceivingYards != 44 || week9.ReceivingTDs != 1 || week9.Opponent != "DEN" {
+		t.Errorf("WR week 9 = %+v, want 2 catches for 44 yards and a TD against DEN", week9)
+	}
+	if want := 2 + 4.4 + 6; math.Abs(week9.FantasyPoints-want) > 1e-9 {
+		t.Errorf("WR week 9 points = %v, want %v", week9.FantasyPoints, want)
+	}
+	if rbGames[0].RushingYards != 4 || rbGames[0].Receptions != 1 || rbGames[0].ReceivingYards != 6 {
+		t.Errorf("RB week 10 = %+v, want rushing and receiving both counted", rbGames[0])
+	}
+
+	// Average of weekly averages: four weeks at 0.5 and week 9 at (0.5+2.5)/2
+	if want := (4*0.5 + 1.5) / 5; math.Abs(wrEPA-want) > 1e-9 {
+		t.Errorf("WR avg EPA = %v, want %v", wrEPA, want)
+	}
+	if want := 0.1; math.Abs(rbEPA-want) > 1e-9 {
+		t.Errorf("RB avg EPA = %v, want %v", rbEPA, want)
+	}
+
+	if recentGamesPipeline(wr, "K", 2025, 11, 5) != nil {
+		t.Error("recentGamesPipeline(K) != nil, want no play-level stats for kickers")
+	}
+}
+
+func TestRecentGamesEarlySeason(t *testing.T) {
+	const wr = "00-0036900"
+	plays := []models.Play{
+		{Season: 2025, Week: 1, ReceiverPlayerID: wr, Yards: 30},
+		{Season: 2025, Week: 2, ReceiverPlayerID: wr, Yards: 50}, // not played yet at week 2
+		{Season: 2024, Week: 18, ReceiverPlayerID: wr, Yards: 90},
+	}
+
+	tests := []struct {
+		week     int
+		fromWeek int
+		want     []int
+	}{
+		{1, 1, []int{}},
+		{2, 1, []int{1}},
+		{3, 1, []int{2, 1}},
+		{10, 4, []int{}},
+	}
+
+	for _, tt := range tests {
+		pipeline := recentGamesPipeline(wr, "WR", 2025, tt.week, 5)
+
+		// The bound is a literal week, never an aggregation expression like $max, which
+		// isn't valid inside a $match query
+		match := pipeline[0][0].Value.(bson.M)["week"].(bson.M)
+		if match["$gte"] != tt.fromWeek || match["$lt"] != tt.week {
+			t.Errorf("week %d match = %v, want %d <= week < %d", tt.week, match, tt.fromWeek, tt.week)
+		}
+
+		games, _ := recentGames(t, plays, wr, "WR", 2025, tt.week)
+		if got := gameWeeks(games); !slices.Equal(got, tt.want) {
+			t.Errorf("week %d recent weeks = %v, want %v", tt.week, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// League-average fallbacks for teams with no games played yet
+const (
+	leaguePlaysPerGame  = 62.0
+	leaguePassRate      = 0.58
+	leaguePointsPerGame = 22.0
+)
+
+// Game-script adjustments: each point of total above the teams' season scoring adds 1%
+// to both teams' plays, and each point a team is favored by lowers its pass rate by one
+// point (underdogs pass more to catch up)
+const (
+	playsPerTotalPoint = 0.01
+	passRatePerSpread  = 0.01
+	minPassRate        = 0.35
+	maxPassRate        = 0.75
+)
+
+// TeamVolumeProjection is one team's expected offensive volume in a game
+type TeamVolumeProjection struct {
+	Team               string  `json:"team"`
+	GamesPlayed        int     `json:"games_played"`
+	SeasonPlaysPerGame float64 `json:"season_plays_per_game"`
+	SeasonPassRate     float64 `json:"season_pass_rate"`
+	ImpliedPoints      float64 `json:"implied_points"`
+	Plays              float64 `json:"projected_plays"`
+	PassRate           float64 `json:"projected_pass_rate"`
+	PassAttempts       float64 `json:"projected_pass_attempts"`
+	RushAttempts       float64 `json:"projected_rush_attempts"`
+}
+
+// GameProjection is the expected volume for both teams in a game
+type GameProjection struct {
+	GameID    string               `json:"game_id"`
+	Season    int                  `json:"season"`
+	Week      int                  `json:"week"`
+	VegasLine float64              `json:"vegas_line"`
+	OverUnder float64              `json:"over_under"`
+	UsedVegas bool                 `json:"used_vegas"` // false when the game has no line and season averages were used
+	Home      TeamVolumeProjection `json:"home"`
+	Away      TeamVolumeProjection `json:"away"`
+}
+
+// teamSeasonVolume is a team's pace and scoring over its games before a week
+type teamSeasonVolume struct {
+	games                int
+	playsPerGame         float64 // Offensive pass/run plays
+	passRate             float64
+	playsAllowedPerGame  float64 // Opponents' pass/run plays
+	passRateAllowed      float64
+	pointsPerGame        float64
+	pointsAllowedPerGame float64
+}
+
+// ProjectGame projects plays and pass/run volume for both teams in a game. Each team's
+// baseline blends its offensive pace and pass rate with what the opponent's defense has
+// faced; the Vegas total then scales plays and the spread shifts pass rate. Games without
+// a line use the baselines as-is. Tendencies cover every loaded play of the season, so
+// projections are meant for upcoming games.
+func (s *DataService) ProjectGame(ctx context.Context, gameID string) (*GameProjection, error) {
+	game, err := s.GetGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	home, err := s.teamSeasonVolume(ctx, game.HomeTeam, game.Season, game.Week)
+	if err != nil {
+		return nil, err
+	}
+	away, err := s.teamSeasonVolume(ctx, game.AwayTeam, game.Season, game.Week)
+	if err != nil {
+		return nil, err
+	}
+
+	return projectGame(game, home, away), nil
+}
+
+// projectGame projects a game's volume from both teams' season volume and its line
+func projectGame(game *models.Game, home, away teamSeasonVolume) *GameProjection {
+	projection := &GameProjection{
+		GameID:    game.GameID,
+		Season:    game.Season,
+		Week:      game.Week,
+		VegasLine: game.VegasLine,
+		OverUnder: game.OverUnder,
+		UsedVegas: game.OverUnder > 0,
+		Home:      baselineVolume(game.HomeTeam, home, away),
+		Away:      baselineVolume(game.AwayTeam, away, home),
+	}
+
+	// Implied points from the season averages, replaced by the line when there is one.
+	// A negative line means the home team is favored.
+	projection.Home.ImpliedPoints = (home.pointsPerGame + away.pointsAllowedPerGame) / 2
+	projection.Away.ImpliedPoints = (away.pointsPerGame + home.pointsAllowedPerGame) / 2
+	if projection.UsedVegas {
+		seasonTotal := projection.Home.ImpliedPoints + projection.Away.ImpliedPoints
+		projection.Home.ImpliedPoints = (game.OverUnder - game.VegasLine) / 2
+		projection.Away.ImpliedPoints = (game.OverUnder + game.VegasLine) / 2
+
+		pace := 1 + playsPerTotalPoint*(game.OverUnder-seasonTotal)
+		projection.Home.Plays *= pace
+		projection.Away.Plays *= pace
+		projection.Home.PassRate = clampPassRate(projection.Home.PassRate + passRatePerSpread*game.VegasLine)
+		projection.Away.PassRate = clampPassRate(projection.Away.PassRate - passRatePerSpread*game.VegasLine)
+	}
+
+	for _, team := range []*TeamVolumeProjection{&projection.Home, &projection.Away} {
+		team.PassAttempts = team.Plays * team.PassRate
+		team.RushAttempts = team.Plays - team.PassAttempts
+	}
+	return projection
+}
+
+// baselineVolume averages a team's offense with what the opponent's defense allows
+func baselineVolume(team string, offense, opponent teamSeasonVolume) TeamVolumeProjection {
+	return TeamVolumeProjection{
+		Team:               team,
+		GamesPlayed:        offense.games,
+		SeasonPlaysPerGame: offense.playsPerGame,
+		SeasonPassRate:     offense.passRate,
+		Plays:              (offense.playsPerGame + opponent.playsAllowedPerGame) / 2,
+		PassRate:           (offense.passRate + opponent.passRateAllowed) / 2,
+	}
+}
+
+func clampPassRate(rate float64) float64 {
+	return math.Max(minPassRate, math.Min(maxPassRate, rate))
+}
+
+// teamSeasonVolume computes a team's pace from its tendencies and scoring from its
+// final games before week
+func (s *DataService) teamSeasonVolume(ctx context.Context, team string, season, week int) (teamSeasonVolume, error) {
+	cursor, err := s.db.Collection("games").Find(ctx, bson.M{
+		"season": season,
+		"week":   bson.M{"$lt": week},
+		"status": "final",
+		"$or":    []bson.M{{"home_team": team}, {"away_team": team}},
+	})
+	if err != nil {
+		return teamSeasonVolume{}, fmt.Errorf("failed to fetch %s games: %w", team, err)
+	}
+	var games []models.Game
+	if err := cursor.All(ctx, &games); err != nil {
+		return teamSeasonVolume{}, fmt.Errorf("failed to decode %s games: %w", team, err)
+	}
+	if len(games) == 0 {
+		return seasonVolume(team, nil, nil), nil
+	}
+
+	tendencies, err := s.GetTeamTendencies(ctx, team, season)
+	if err != nil {
+		return teamSeasonVolume{}, err
+	}
+	return seasonVolume(team, games, tendencies), nil
+}
+
+// seasonVolume averages a team's scoring over its games and its pace over the plays in
+// tendencies, falling back to league averages before its first game
+func seasonVolume(team string, games []models.Game, tendencies *TeamTendencies) teamSeasonVolume {
+	volume := teamSeasonVolume{
+		playsPerGame:         leaguePlaysPerGame,
+		passRate:             leaguePassRate,
+		playsAllowedPerGame:  leaguePlaysPerGame,
+		passRateAllowed:      leaguePassRate,
+		pointsPerGame:        leaguePointsPerGame,
+		pointsAllowedPerGame: leaguePointsPerGame,
+	}
+	if len(games) == 0 {
+		return volume
+	}
+
+	var scored, allowed int
+	for _, g := range games {
+		if g.HomeTeam == team {
+			scored += g.HomeScore
+			allowed += g.AwayScore
+		} else {
+			scored += g.AwayScore
+			allowed += g.HomeScore
+		}
+	}
+	n := float64(len(games))
+	volume.games = len(games)
+	volume.pointsPerGame = float64(scored) / n
+	volume.pointsAllowedPerGame = float64(allowed) / n
+
+	if tendencies == nil {
+		return volume
+	}
+	if tendencies.Offense.Overall.Plays > 0 {
+		volume.playsPerGame = float64(tendencies.Offense.Overall.Plays) / n
+		volume.passRate = tendencies.Offense.Overall.PassRate
+	}
+	if tendencies.Defense.Overall.Plays > 0 {
+		volume.playsAllowedPerGame = float64(tendencies.Defense.Overall.Plays) / n
+		volume.passRateAllowed = tendencies.Defense.Overall.PassRate
+	}
+	return volume
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+// tendencies seeds a team's overall offensive and defensive tendencies
+func tendencies(offensePlays int, offensePassRate float64, defensePlays int, defensePassRate float64) *TeamTendencies {
+	return &TeamTendencies{
+		Offense: SideTendencies{Overall: TendencySplit{Plays: offensePlays, PassRate: offensePassRate}},
+		Defense: SideTendencies{Overall: TendencySplit{Plays: defensePlays, PassRate: defensePassRate}},
+	}
+}
+
+func TestProjectGame(t *testing.T) {
+	kcGames := []models.Game{
+		{HomeTeam: "KC", AwayTeam: "LV", HomeScore: 27, AwayScore: 20},
+		{HomeTeam: "DEN", AwayTeam: "KC", HomeScore: 17, AwayScore: 24},
+		{HomeTeam: "KC", AwayTeam: "LAC", HomeScore: 30, AwayScore: 14},
+	}
+	bufGames := []models.Game{
+		{HomeTeam: "BUF", AwayTeam: "MIA", HomeScore: 21, AwayScore: 24},
+		{HomeTeam: "NYJ", AwayTeam: "BUF", HomeScore: 21, AwayScore: 28},
+		{HomeTeam: "BUF", AwayTeam: "NE", HomeScore: 23, AwayScore: 18},
+	}
+	// KC: 65 plays/game passing 60%, allowing 60 plays/game; BUF: 62 plays/game passing 50%, allowing 64
+	kc := seasonVolume("KC", kcGames, tendencies(195, 0.6, 180, 0.55))
+	buf := seasonVolume("BUF", bufGames, tendencies(186, 0.5, 192, 0.6))
+
+	if kc.games != 3 || kc.playsPerGame != 65 || kc.pointsPerGame != 27 || kc.pointsAllowedPerGame != 17 {
+		t.Fatalf("KC season volume = %+v, want 3 games, 65 plays, 27 scored, 17 allowed", kc)
+	}
+
+	type want struct {
+		implied, plays, passRate float64
+	}
+	tests := []struct {
+		name       string
+		game       models.Game
+		usedVegas  bool
+		home, away want
+	}{
+		{
+			// The 50.5 total is 6 points above the teams' 44.5 season average, adding 6% to
+			// both teams' plays; KC favored by 3 passes 3 points less, BUF 3 points more
+			name:      "vegas line",
+			game:      models.Game{GameID: "2025_10_BUF_KC", HomeTeam: "KC", AwayTeam: "BUF", VegasLine: -3, OverUnder: 50.5},
+			usedVegas: true,
+			home:      want{26.75, 64.5 * 1.06, 0.57},
+			away:      want{23.75, 61 * 1.06, 0.555},
+		},
+		{
+			name: "no line falls back to season averages",
+			game: models.Game{GameID: "2025_10_BUF_KC", HomeTeam: "KC", AwayTeam: "BUF"},
+			home: want{24, 64.5, 0.6},
+			away: want{20.5, 61, 0.525},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := projectGame(&tt.game, kc, buf)
+			if got.UsedVegas != tt.usedVegas {
+				t.Errorf("UsedVegas = %v, want %v", got.UsedVegas, tt.usedVegas)
+			}
+			for _, side := range []struct {
+				got  TeamVolumeProjection
+				want want
+			}{{got.Home, tt.home}, {got.Away, tt.away}} {
+				p := side.got
+				if math.Abs(p.ImpliedPoints-side.want.implied) > 1e-9 || math.Abs(p.Plays-side.want.plays) > 1e-9 || math.Abs(p.PassRate-side.want.passRate) > 1e-9 {
+					t.Errorf("%s = implied %v, plays %v, pass rate %v, want %v, %v, %v",
+						p.Team, p.ImpliedPoints, p.Plays, p.PassRate, side.want.implied, side.want.plays, side.want.passRate)
+				}
+				if math.Abs(p.PassAttempts+p.RushAttempts-p.Plays) > 1e-9 || math.Abs(p.PassAttempts-p.Plays*p.PassRate) > 1e-9 {
+					t.Errorf("%s attempts = %v pass, %v rush, want a %v split of %v plays", p.Team, p.PassAttempts, p.RushAttempts, p.PassRate, p.Plays)
+				}
+			}
+		})
+	}
+}
+
+func TestSeasonVolumeBeforeFirstGame(t *testing.T) {
+	got := seasonVolume("KC", nil, nil)
+	if got.games != 0 || got.playsPerGame != leaguePlaysPerGame || got.passRate != leaguePassRate || got.pointsPerGame != leaguePointsPerGame {
+		t.Errorf("seasonVolume() before week 1 = %+v, want league averages", got)
+	}
+
+	// Games without loaded plays keep league pace but use the real scoring
+	got = seasonVolume("KC", []models.Game{{HomeTeam: "KC", AwayTeam: "LV", HomeScore: 31, AwayScore: 10}}, tendencies(0, 0, 0, 0))
+	if got.playsPerGame != leaguePlaysPerGame || got.passRateAllowed != leaguePassRate || got.pointsPerGame != 31 || got.pointsAllowedPerGame != 10 {
+		t.Errorf("seasonVolume() without plays = %+v, want league pace and 31-10 scoring", got)
+	}
+}
@@ -4,8 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/prompts"
+	"github.com/ai-atl/nfl-platform/internal/teams"
 	"github.com/ai-atl/nfl-platform/pkg/gemini"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -13,16 +19,29 @@ import (
 )
 
 type GameScriptService struct {
-	db     *mongo.Database
-	gemini *gemini.Client
+	db          *mongo.Database
+	gemini      *gemini.Client
+	dataService *DataService
 }
 
 type GameScriptPrediction struct {
-	GameID          string         `json:"game_id"`
-	PredictedFlow   string         `json:"predicted_flow"`
-	PlayerImpacts   []PlayerImpact `json:"player_impacts"`
-	ConfidenceScore float64        `json:"confidence_score"`
-	KeyFactors      []string       `json:"key_factors"`
+	GameID          string             `json:"game_id"`
+	Summary         *GameScriptSummary `json:"summary,omitempty"`
+	PredictedFlow   string             `json:"predicted_flow"` // Narrative, without the summary and list blocks
+	PlayerImpacts   []PlayerImpact     `json:"player_impacts"`
+	ConfidenceScore float64            `json:"confidence_score"`
+	KeyFactors      []string           `json:"key_factors"`
+}
+
+// GameScriptSummary is the structured header of a prediction, so clients can show badges
+// instead of the whole narrative
+type GameScriptSummary struct {
+	PredictedWinner string  `json:"predicted_winner"`     // Team abbreviation
+	ProjectedTotal  float64 `json:"projected_total"`      // Combined points
+	PaceLean        string  `json:"pace_lean,omitempty"`  // fast, average, slow
+	PlayLean        string  `json:"play_lean,omitempty"`  // pass, run, balanced
+	Confidence      float64 `json:"confidence,omitempty"` // The model's own 0-1 confidence in its script
+	Rationale       string  `json:"rationale,omitempty"`
 }
 
 type PlayerImpact struct {
@@ -33,8 +52,9 @@ type PlayerImpact struct {
 
 func NewGameScriptService(db *mongo.Database) *GameScriptService {
 	return &GameScriptService{
-		db:     db,
-		gemini: gemini.NewClient(),
+		db:          db,
+		gemini:      gemini.NewClient(gemini.AnalysisTier()),
+		dataService: NewDataService(db),
 	}
 }
 
@@ -64,8 +84,15 @@ func (s *GameScriptService) PredictGameScript(ctx context.Context, gameID string
 	// Fetch home/away performance splits
 	homeAwayContext := s.fetchHomeAwaySplits(ctx, game.HomeTeam, game.AwayTeam, game.Season)
 
+	// Fetch play-calling tendencies for both teams
+	tendencyContext := s.fetchTendencyContext(ctx, game.AwayTeam, game.Season) +
+		s.fetchTendencyContext(ctx, game.HomeTeam, game.Season)
+
 	// Build comprehensive context with real database data
-	prompt := s.buildGameScriptPrompt(game, homeTeamContext, awayTeamContext, historicalContext, homeAwayContext)
+	prompt, err := s.buildGameScriptPrompt(game, homeTeamContext, awayTeamContext, historicalContext, homeAwayContext, tendencyContext)
+	if err != nil {
+		return nil, err
+	}
 
 	// Log the first 2000 characters of the prompt to see what player data is included
 	promptPreview := prompt
@@ -80,27 +107,219 @@ func (s *GameScriptService) PredictGameScript(ctx context.Context, gameID string
 		return nil, fmt.Errorf("failed to generate prediction: %w", err)
 	}
 
-	// Parse response (simplified for hackathon)
+	summary, rest := parseGameScriptSummary(response)
+	completeSummary(&summary, game)
+
+	flow, keyFactors, impacts := parseGameScriptResponse(rest)
+	if len(keyFactors) == 0 {
+		keyFactors = lineKeyFactors(game)
+	}
+
 	prediction := &GameScriptPrediction{
 		GameID:          gameID,
-		PredictedFlow:   response,
-		ConfidenceScore: 0.85,
-		KeyFactors: []string{
-			"Vegas line suggests competitive game",
-			"Weather conditions favorable",
-		},
-		PlayerImpacts: []PlayerImpact{
-			{
-				PlayerName: "Key Player",
-				Impact:     "+25% opportunity",
-				Reasoning:  "Favorable game script for passing",
-			},
-		},
+		Summary:         &summary,
+		PredictedFlow:   flow,
+		ConfidenceScore: gameScriptConfidence(game.VegasLine),
+		KeyFactors:      keyFactors,
+		PlayerImpacts:   impacts,
 	}
 
 	return prediction, nil
 }
 
+// gameScriptConfidence maps the Vegas spread to confidence in the predicted script:
+// a pick'em can go either way (0.5), while a two-touchdown favorite is likely to
+// control the game (0.9). Spreads beyond 14 points add nothing.
+func gameScriptConfidence(vegasLine float64) float64 {
+	return 0.5 + 0.4*math.Min(math.Abs(vegasLine), 14)/14
+}
+
+// lineKeyFactors describes the betting lines when the model didn't list key factors
+func lineKeyFactors(game models.Game) []string {
+	factors := []string{}
+	switch {
+	case game.VegasLine < 0:
+		factors = append(factors, fmt.Sprintf("%s favored by %.1f", game.HomeTeam, -game.VegasLine))
+	case game.VegasLine > 0:
+		factors = append(factors, fmt.Sprintf("%s favored by %.1f", game.AwayTeam, game.VegasLine))
+	default:
+		factors = append(factors, "Vegas line is a pick'em")
+	}
+	if game.OverUnder > 0 {
+		factors = append(factors, fmt.Sprintf("Over/under of %.1f points", game.OverUnder))
+	}
+	return factors
+}
+
+// paceLeans and playLeans are the values accepted from the summary's PACE and LEAN lines
+var (
+	paceLeans = map[string]bool{"fast": true, "average": true, "slow": true}
+	playLeans = map[string]bool{"pass": true, "run": true, "balanced": true}
+)
+
+// summaryNumber matches the first number in a summary value ("47.5 points", "70%")
+var summaryNumber = regexp.MustCompile(`\d+(?:\.\d+)?`)
+
+// parseGameScriptSummary reads the SUMMARY block the prompt asks the model to open with and
+// returns it with the rest of the response. The block ends at the first blank line or
+// unrecognized line after it. Values outside the allowed choices are dropped; a response
+// without the block (e.g. a pinned older prompt) returns an empty summary and the response
+// unchanged.
+func parseGameScriptSummary(response string) (GameScriptSummary, string) {
+	var summary GameScriptSummary
+
+	lines := strings.Split(response, "\n")
+	start := -1
+	for i, raw := range lines {
+		if cleanSummaryLine(raw) == "SUMMARY:" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return summary, response
+	}
+
+	end := start + 1
+	for ; end < len(lines); end++ {
+		line := cleanSummaryLine(lines[end])
+		if line == "" {
+			if end > start+1 {
+				break
+			}
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			break
+		}
+		value = strings.TrimSpace(value)
+
+		recognized := true
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "WINNER":
+			summary.PredictedWinner = teams.Normalize(value)
+		case "PROJECTED TOTAL":
+			summary.ProjectedTotal, _ = strconv.ParseFloat(summaryNumber.FindString(value), 64)
+		case "PACE":
+			if lean := strings.ToLower(value); paceLeans[lean] {
+				summary.PaceLean = lean
+			}
+		case "LEAN":
+			if lean := strings.ToLower(value); playLeans[lean] {
+				summary.PlayLean = lean
+			}
+		case "CONFIDENCE":
+			confidence, _ := strconv.ParseFloat(summaryNumber.FindString(value), 64)
+			if confidence > 1 {
+				confidence /= 100 // Asked for 0-100; accept a 0-1 fraction too
+			}
+			summary.Confidence = math.Min(confidence, 1)
+		case "RATIONALE":
+			summary.Rationale = value
+		default:
+			recognized = false
+		}
+		if !recognized {
+			break
+		}
+	}
+
+	rest := append(append([]string{}, lines[:start]...), lines[end:]...)
+	return summary, strings.TrimSpace(strings.Join(rest, "\n"))
+}
+
+// cleanSummaryLine strips markdown emphasis, headers and list markers from a summary line
+func cleanSummaryLine(raw string) string {
+	line := strings.TrimSpace(strings.ReplaceAll(raw, "**", ""))
+	line = strings.TrimSpace(strings.TrimLeft(line, "# "))
+	return strings.TrimSpace(listMarker.ReplaceAllString(line, ""))
+}
+
+// completeSummary fills what the model left out or got wrong from the betting lines: the
+// favorite wins (the home team in a pick'em) and the total is the over/under
+func completeSummary(summary *GameScriptSummary, game models.Game) {
+	if summary.PredictedWinner != game.HomeTeam && summary.PredictedWinner != game.AwayTeam {
+		summary.PredictedWinner = game.HomeTeam
+		if game.VegasLine > 0 {
+			summary.PredictedWinner = game.AwayTeam
+		}
+	}
+	if summary.ProjectedTotal <= 0 && game.OverUnder > 0 {
+		summary.ProjectedTotal = game.OverUnder
+	}
+}
+
+// listMarker matches a leading bullet or number ("- ", "* ", "2. ")
+var listMarker = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s*`)
+
+// parseGameScriptResponse splits the model output into the narrative and the
+// KEY FACTORS / PLAYER IMPACTS blocks requested at the end of the prompt
+func parseGameScriptResponse(response string) (string, []string, []PlayerImpact) {
+	flow := response
+	keyFactors := []string{}
+	impacts := []PlayerImpact{}
+
+	section := ""
+	lines := strings.Split(response, "\n")
+	for i, raw := range lines {
+		line := strings.TrimSpace(strings.ReplaceAll(raw, "**", ""))
+		header := strings.ToUpper(strings.TrimLeft(line, "# "))
+
+		switch {
+		case strings.HasPrefix(header, "KEY FACTORS:"):
+			if section == "" {
+				flow = strings.TrimSpace(strings.Join(lines[:i], "\n"))
+			}
+			section = "factors"
+			continue
+		case strings.HasPrefix(header, "PLAYER IMPACTS:"):
+			if section == "" {
+				flow = strings.TrimSpace(strings.Join(lines[:i], "\n"))
+			}
+			section = "impacts"
+			continue
+		}
+
+		item := strings.TrimSpace(listMarker.ReplaceAllString(line, ""))
+		if item == "" || strings.Contains(item, "[") {
+			continue
+		}
+
+		switch section {
+		case "factors":
+			keyFactors = append(keyFactors, item)
+		case "impacts":
+			if impact, ok := parsePlayerImpact(item); ok {
+				impacts = append(impacts, impact)
+			}
+		}
+	}
+
+	return flow, keyFactors, impacts
+}
+
+// parsePlayerImpact parses "PLAYER: name | IMPACT: change | REASONING: why"
+func parsePlayerImpact(line string) (PlayerImpact, bool) {
+	var impact PlayerImpact
+	for _, part := range strings.Split(line, "|") {
+		key, value, found := strings.Cut(part, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "PLAYER":
+			impact.PlayerName = value
+		case "IMPACT":
+			impact.Impact = value
+		case "REASONING":
+			impact.Reasoning = value
+		}
+	}
+	return impact, impact.PlayerName != "" && impact.Impact != ""
+}
+
 type PlayerWithStats struct {
 	Player      models.Player
 	Stats       models.PlayerStats
@@ -115,21 +334,17 @@ func (s *GameScriptService) fetchTeamContext(ctx context.Context, team string, s
 	var players []models.Player
 	usedSeason := season
 
-	// Try requested season first
-	cursor, err := s.db.Collection("players").Find(ctx, bson.M{
-		"team":   team,
-		"season": season,
-	})
-	if err == nil {
-		cursor.All(ctx, &players)
-		cursor.Close(ctx)
+	// Try requested season first, as of the game's week so mid-season trades are reflected
+	players, err := s.dataService.GetPlayersByTeamForWeek(ctx, team, season, currentWeek)
+	if err != nil {
+		log.Printf("⚠️  Failed to load %s week %d roster: %v", team, currentWeek, err)
 	}
 
 	// If no players found and we're looking at 2025, fall back to 2024
 	// (2025 roster data might be incomplete/unavailable)
 	if len(players) == 0 && season == 2025 {
 		log.Printf("⚠️  No %d roster for %s, falling back to 2024", season, team)
-		cursor, err = s.db.Collection("players").Find(ctx, bson.M{
+		cursor, err := s.db.Collection("players").Find(ctx, bson.M{
 			"team":   team,
 			"season": 2024,
 		})
@@ -450,6 +665,26 @@ func (s *GameScriptService) fetchHomeAwaySplits(ctx context.Context, homeTeam, a
 	return context
 }
 
+func (s *GameScriptService) fetchTendencyContext(ctx context.Context, team string, season int) string {
+	t, err := s.dataService.GetTeamTendencies(ctx, team, season)
+	if err != nil || t.Offense.Overall.Plays == 0 {
+		return ""
+	}
+
+	context := fmt.Sprintf("\n**%s Play-Calling Tendencies (%d):**\n", team, season)
+	context += fmt.Sprintf("- Offense: %.0f%% pass overall, %.0f%% on early downs (%.3f EPA/play early); pass %.3f vs run %.3f EPA/play\n",
+		t.Offense.Overall.PassRate*100, t.Offense.EarlyDown.PassRate*100, t.Offense.EarlyDown.EPA,
+		t.Offense.PassEPA, t.Offense.RunEPA)
+	if third, ok := t.Offense.BySituation["3rd_long"]; ok && third.Plays > 0 {
+		context += fmt.Sprintf("- 3rd & long: %.0f%% pass, %.3f EPA/play\n", third.PassRate*100, third.EPA)
+	}
+	if t.Defense.Overall.Plays > 0 {
+		context += fmt.Sprintf("- Defense allows %.3f EPA/play vs pass, %.3f vs run (opponents pass %.0f%% of the time)\n",
+			t.Defense.PassEPA, t.Defense.RunEPA, t.Defense.Overall.PassRate*100)
+	}
+	return context
+}
+
 func (s *GameScriptService) getTeamRecord(ctx context.Context, team string, season int, isHome bool) (games, wins, pointsFor, pointsAgainst int) {
 	filter := bson.M{
 		"season": season,
@@ -493,84 +728,13 @@ func (s *GameScriptService) getTeamRecord(ctx context.Context, team string, seas
 	return
 }
 
-func (s *GameScriptService) buildGameScriptPrompt(game models.Game, homeTeamContext, awayTeamContext, historicalContext, homeAwayContext string) string {
-	return fmt.Sprintf(`Analyze this NFL matchup and predict the game script:
-
-	**Game:** %s (Away) @ %s (Home)
-	**Vegas Line:** %s %.1f (negative = home team favored)
-	**Over/Under:** %.1f
-	**Start Time:** %s
-	**Week:** %d
-
-	%s
-
-	%s
-
-	%s
-
-	%s
-
-	**Analysis Instructions:**
-
-	1. **Focus on STARTERS & HIGH-USAGE PLAYERS**: 
-	- Players are ranked by FANTASY POINTS PER GAME, not career totals
-	- Look at the "fantasy pts/game" average - this shows current season performance
-	- Players with higher pts/game averages are the actual starters getting opportunities
-	- Games played shown next to each player (e.g., "STARTER, 8 games")
-
-	2. **Use Per-Game Performance**:
-	- Compare fantasy pts/game averages to assess true workload
-	- A player with 12 pts/game over 8 games is more relevant than one with 20 total pts over 2 games
-	- Consider consistency: steady performers vs boom/bust players
-
-	3. **Leverage Historical Context**:
-	- Recent head-to-head results show scoring trends between these teams
-	- Home/away splits reveal team performance in different venues
-	- Factor these patterns into your game script prediction
-
-	4. **Game Script Prediction**:
-	Based on Vegas lines, team trends, and home/away splits:
-	- Will this be competitive, a blowout, or defensive struggle?
-	- Which team will likely be playing from ahead/behind?
-	- How does this affect pass/run ratios?
-
-	5. **Player Impact Analysis** (TOP STARTERS ONLY):
-	- Who benefits from expected game script?
-	- **USE THE EXACT PLAYER NAMES PROVIDED ABOVE** - DO NOT use placeholders like "[Insert X Name]"
-	- Reference their actual pts/game average and recent performance
-	- Project specific opportunity increases (more targets, carries, attempts)
-
-	**CRITICAL RULES:**
-	- **ALWAYS USE ACTUAL PLAYER NAMES from the roster data above** - NEVER use placeholders like "[Insert CHI QB Name]" or "[NYG WR1]"
-	- **DO NOT mention ANY players not explicitly listed above** - the roster is filtered for active, healthy players only
-	- **Players listed have been filtered to exclude injured/inactive players** - if someone isn't listed, they're not available
-	- ONLY reference players with (STARTER) or (BACKUP) labels shown above
-	- Focus on players with HIGH fantasy pts/game averages (they're the actual starters)
-	- Copy player names EXACTLY as they appear in the roster sections (e.g., "Caleb Williams", "Saquon Barkley")
-	- If you're unsure about a player, DO NOT mention them - stick to the provided roster
-	- Use the HOME/AWAY splits and HISTORICAL data to inform predictions
-	- Reference actual numbers from the data (pts/game, team scoring averages, etc.)
-
-	**ROSTER DATA ACCURACY:**
-	The player lists above have been filtered to remove:
-	- Injured players (IR, PUP, Out status)
-	- Players who haven't played in recent weeks
-	- Inactive or practice squad players
-	- **Mid-season trades are not always reflected - only reference players explicitly shown for each team**
-
-	If a notable player you'd expect to see is missing from the roster, they are either injured, traded, or inactive. Do not mention them.
-
-	**Format:** Use clear markdown with ## headers and bullet points. Be specific and actionable.`,
-		game.AwayTeam,
-		game.HomeTeam,
-		game.HomeTeam,
-		game.VegasLine,
-		game.OverUnder,
-		game.StartTime.Format("Mon Jan 2 3:04 PM"),
-		game.Week,
-		awayTeamContext,
-		homeTeamContext,
-		historicalContext,
-		homeAwayContext,
-	)
+func (s *GameScriptService) buildGameScriptPrompt(game models.Game, homeTeamContext, awayTeamContext, historicalContext, homeAwayContext, tendencyContext string) (string, error) {
+	return prompts.Render(prompts.GameScript, struct {
+		Game              models.Game
+		HomeContext       string
+		AwayContext       string
+		HistoricalContext string
+		HomeAwayContext   string
+		TendencyContext   string
+	}{game, homeTeamContext, awayTeamContext, historicalContext, homeAwayContext, tendencyContext})
 }
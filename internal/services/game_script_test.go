@@ -0,0 +1,168 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestParseGameScriptSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     GameScriptSummary
+		wantRest string
+	}{
+		{
+			name: "full block",
+			response: `SUMMARY:
+WINNER: KC
+PROJECTED TOTAL: 47.5 points
+PACE: Fast
+LEAN: pass
+CONFIDENCE: 70%
+RATIONALE: Chiefs attack a thin secondary
+
+The Chiefs open with tempo.`,
+			want:     GameScriptSummary{PredictedWinner: "KC", ProjectedTotal: 47.5, PaceLean: "fast", PlayLean: "pass", Confidence: 0.7, Rationale: "Chiefs attack a thin secondary"},
+			wantRest: "The Chiefs open with tempo.",
+		},
+		{
+			name: "markdown and ESPN team codes",
+			response: `## **SUMMARY:**
+- **WINNER:** wsh
+- **PROJECTED TOTAL:** 41
+- **CONFIDENCE:** 0.55
+
+Washington leans on the run.`,
+			want:     GameScriptSummary{PredictedWinner: "WAS", ProjectedTotal: 41, Confidence: 0.55},
+			wantRest: "Washington leans on the run.",
+		},
+		{
+			name: "values outside the choices are dropped",
+			response: `SUMMARY:
+WINNER: BUF
+PACE: frantic
+LEAN: trick plays
+CONFIDENCE: 150
+Buffalo controls the clock.`,
+			want:     GameScriptSummary{PredictedWinner: "BUF", Confidence: 1},
+			wantRest: "Buffalo controls the clock.",
+		},
+		{
+			name: "block ends at an unrecognized key",
+			response: `Intro line
+SUMMARY:
+WINNER: DAL
+NOTE: this isn't part of the block
+More narrative.`,
+			want:     GameScriptSummary{PredictedWinner: "DAL"},
+			wantRest: "Intro line\nNOTE: this isn't part of the block\nMore narrative.",
+		},
+		{
+			name: "blank line after the header is skipped",
+			response: `SUMMARY:
+
+WINNER: PHI
+PROJECTED TOTAL: 44.5
+
+Narrative.`,
+			want:     GameScriptSummary{PredictedWinner: "PHI", ProjectedTotal: 44.5},
+			wantRest: "Narrative.",
+		},
+		{
+			name:     "no block returns the response unchanged",
+			response: "An older prompt's narrative.\nKEY FACTORS:\n- Weather",
+			want:     GameScriptSummary{},
+			wantRest: "An older prompt's narrative.\nKEY FACTORS:\n- Weather",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rest := parseGameScriptSummary(tt.response)
+			if got != tt.want {
+				t.Errorf("summary = %+v\nwant      %+v", got, tt.want)
+			}
+			if rest != tt.wantRest {
+				t.Errorf("rest = %q, want %q", rest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestCompleteSummary(t *testing.T) {
+	game := models.Game{HomeTeam: "KC", AwayTeam: "BUF", VegasLine: 2.5, OverUnder: 48.5}
+
+	tests := []struct {
+		name       string
+		summary    GameScriptSummary
+		game       models.Game
+		wantWinner string
+		wantTotal  float64
+	}{
+		{"model's answer kept", GameScriptSummary{PredictedWinner: "KC", ProjectedTotal: 51}, game, "KC", 51},
+		{"missing winner goes to the favorite", GameScriptSummary{}, game, "BUF", 48.5},
+		{"team not in the game replaced", GameScriptSummary{PredictedWinner: "DAL"}, game, "BUF", 48.5},
+		{"home favorite", GameScriptSummary{}, models.Game{HomeTeam: "KC", AwayTeam: "BUF", VegasLine: -3}, "KC", 0},
+		{"pick'em goes home", GameScriptSummary{}, models.Game{HomeTeam: "KC", AwayTeam: "BUF"}, "KC", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := tt.summary
+			completeSummary(&summary, tt.game)
+			if summary.PredictedWinner != tt.wantWinner || summary.ProjectedTotal != tt.wantTotal {
+				t.Errorf("completeSummary() = %s/%v, want %s/%v", summary.PredictedWinner, summary.ProjectedTotal, tt.wantWinner, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestParseGameScriptResponse(t *testing.T) {
+	response := `The Ravens lean on the run early and shorten the game.
+
+Kansas City answers through the air in the second half.
+
+## **KEY FACTORS:**
+1. Baltimore's rushing volume
+- [placeholder factor]
+* Chiefs pass rush
+
+**PLAYER IMPACTS:**
+- PLAYER: Derrick Henry | IMPACT: +5 carries | REASONING: Ravens protect a lead
+- **PLAYER:** Travis Kelce | **IMPACT:** more targets | **REASONING:** short passing offsets the rush
+- PLAYER: [exact player name] | IMPACT: [e.g. +20% targets] | REASONING: [one sentence]
+- PLAYER: Rashee Rice | REASONING: missing an impact
+- Just a sentence without fields`
+
+	flow, factors, impacts := parseGameScriptResponse(response)
+
+	wantFlow := "The Ravens lean on the run early and shorten the game.\n\nKansas City answers through the air in the second half."
+	if flow != wantFlow {
+		t.Errorf("flow = %q, want %q", flow, wantFlow)
+	}
+	if want := []string{"Baltimore's rushing volume", "Chiefs pass rush"}; !slices.Equal(factors, want) {
+		t.Errorf("key factors = %q, want %q", factors, want)
+	}
+
+	want := []PlayerImpact{
+		{PlayerName: "Derrick Henry", Impact: "+5 carries", Reasoning: "Ravens protect a lead"},
+		{PlayerName: "Travis Kelce", Impact: "more targets", Reasoning: "short passing offsets the rush"},
+	}
+	if !slices.Equal(impacts, want) {
+		t.Errorf("impacts = %+v, want %+v", impacts, want)
+	}
+}
+
+func TestParseGameScriptResponseWithoutBlocks(t *testing.T) {
+	response := "Just a narrative.\nNo structured sections."
+	flow, factors, impacts := parseGameScriptResponse(response)
+	if flow != response {
+		t.Errorf("flow = %q, want the whole response", flow)
+	}
+	if factors == nil || impacts == nil || len(factors) != 0 || len(impacts) != 0 {
+		t.Errorf("factors/impacts = %v/%v, want empty non-nil slices", factors, impacts)
+	}
+}
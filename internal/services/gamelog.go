@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// GameLogEntry is a player's box line for one game
+type GameLogEntry struct {
+	Week     int    `json:"week"`
+	GameID   string `json:"game_id,omitempty"`
+	Team     string `json:"team,omitempty"`
+	Opponent string `json:"opponent"`
+	Home     bool   `json:"home"`
+
+	Snaps   int     `json:"snaps"`
+	SnapPct float64 `json:"snap_pct"`
+
+	PassingYards   int `json:"passing_yards"`
+	PassingTDs     int `json:"passing_tds"`
+	Interceptions  int `json:"interceptions"`
+	Carries        int `json:"carries"`
+	RushingYards   int `json:"rushing_yards"`
+	RushingTDs     int `json:"rushing_tds"`
+	Targets        int `json:"targets"`
+	Receptions     int `json:"receptions"`
+	ReceivingYards int `json:"receiving_yards"`
+	ReceivingTDs   int `json:"receiving_tds"`
+
+	FantasyPoints    float64 `json:"fantasy_points"`
+	FantasyPointsPPR float64 `json:"fantasy_points_ppr"`
+
+	Plays      int     `json:"plays"`
+	EPA        float64 `json:"epa"` // Total over the player's plays
+	EPAPerPlay float64 `json:"epa_per_play"`
+}
+
+// GetGameLog builds a player's per-game log for a season in week order. Box score numbers
+// and fantasy points come from player_weekly_stats; the game, home/away and EPA come from
+// the player's plays, and snaps from snap_counts. Weeks with only one source still appear.
+func (s *DataService) GetGameLog(ctx context.Context, nflID string, season int) ([]GameLogEntry, error) {
+	weekly, err := s.GetPlayerWeeklyStatsRange(ctx, nflID, season, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch weekly stats: %w", err)
+	}
+
+	games, err := s.playerGames(ctx, nflID, season)
+	if err != nil {
+		return nil, err
+	}
+
+	snaps, err := s.GetSnapCounts(ctx, nflID, season, 1, regularSeasonWeeks+4) // Through the Super Bowl
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch snap counts: %w", err)
+	}
+
+	return mergeGameLog(weekly, games, snaps), nil
+}
+
+// mergeGameLog combines weekly stats, per-game plays and snap counts into one entry per
+// week, in week order
+func mergeGameLog(weekly []models.WeeklyStat, games []playerGame, snaps []models.SnapCount) []GameLogEntry {
+	entries := make(map[int]*GameLogEntry)
+	entry := func(week int) *GameLogEntry {
+		e, ok := entries[week]
+		if !ok {
+			e = &GameLogEntry{Week: week}
+			entries[week] = e
+		}
+		return e
+	}
+
+	for _, w := range weekly {
+		e := entry(w.Week)
+		e.Opponent = w.Opponent
+		e.PassingYards = w.PassingYards
+		e.PassingTDs = w.PassingTDs
+		e.Interceptions = w.Interceptions
+		e.Carries = w.Carries
+		e.RushingYards = w.RushingYards
+		e.RushingTDs = w.RushingTDs
+		e.Targets = w.Targets
+		e.Receptions = w.Receptions
+		e.ReceivingYards = w.ReceivingYards
+		e.ReceivingTDs = w.ReceivingTDs
+		e.FantasyPoints = w.FantasyPoints
+		e.FantasyPointsPPR = w.FantasyPointsPPR
+	}
+
+	for _, g := range games {
+		e := entry(g.Week)
+		e.GameID = g.GameID
+		e.Team = g.Team
+		e.Opponent = g.Opponent
+		e.Home = g.Home
+		e.Plays = g.Plays
+		e.EPA = g.EPA
+		if g.Plays > 0 {
+			e.EPAPerPlay = g.EPA / float64(g.Plays)
+		}
+	}
+
+	for _, sc := range snaps {
+		e := entry(sc.Week)
+		e.Snaps = sc.OffenseSnaps
+		e.SnapPct = sc.OffensePct
+	}
+
+	gameLog := make([]GameLogEntry, 0, len(entries))
+	for _, e := range entries {
+		gameLog = append(gameLog, *e)
+	}
+	sort.Slice(gameLog, func(i, j int) bool { return gameLog[i].Week < gameLog[j].Week })
+	return gameLog
+}
+
+// playerGame is a player's plays in one game, grouped from the plays collection
+type playerGame struct {
+	Week     int
+	GameID   string
+	Team     string
+	Opponent string
+	Home     bool
+	Plays    int
+	EPA      float64
+}
+
+// playerGames groups a player's plays by game and resolves home/away from the schedule
+func (s *DataService) playerGames(ctx context.Context, nflID string, season int) ([]playerGame, error) {
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, playerGamesPipeline(nflID, season))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate plays by game: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []playerGameRow
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode plays by game: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	gameIDs := make([]string, len(results))
+	for i, r := range results {
+		gameIDs[i] = r.ID.GameID
+	}
+	gameCursor, err := s.db.Collection("games").Find(ctx, bson.M{"game_id": bson.M{"$in": gameIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch games: %w", err)
+	}
+	var schedule []models.Game
+	err = gameCursor.All(ctx, &schedule)
+	gameCursor.Close(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode games: %w", err)
+	}
+	homeTeams := make(map[string]string, len(schedule))
+	for _, g := range schedule {
+		homeTeams[g.GameID] = g.HomeTeam
+	}
+
+	return buildPlayerGames(results, homeTeams), nil
+}
+
+// playerGameRow is one game of a player's plays from playerGamesPipeline
+type playerGameRow struct {
+	ID struct {
+		GameID string `bson:"game_id"`
+		Week   int    `bson:"week"`
+	} `bson:"_id"`
+	Team     string  `bson:"team"`
+	Opponent string  `bson:"opponent"`
+	EPA      float64 `bson:"epa"`
+	Plays    int     `bson:"plays"`
+}
+
+// playerGamesPipeline groups a player's plays for a season by game
+func playerGamesPipeline(nflID string, season int) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"season": season,
+			"$or": []bson.M{
+				{"passer_player_id": nflID},
+				{"rusher_player_id": nflID},
+				{"receiver_player_id": nflID},
+			},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"game_id": "$game_id", "week": "$week"},
+			"team":     bson.M{"$first": "$possession_team"},
+			"opponent": bson.M{"$first": "$defense_team"},
+			"epa":      bson.M{"$sum": "$epa"},
+			"plays":    bson.M{"$sum": 1},
+		}}},
+	}
+}
+
+// buildPlayerGames marks each game home or away using the home team of each game ID
+func buildPlayerGames(rows []playerGameRow, homeTeams map[string]string) []playerGame {
+	games := make([]playerGame, 0, len(rows))
+	for _, r := range rows {
+		games = append(games, playerGame{
+			Week:     r.ID.Week,
+			GameID:   r.ID.GameID,
+			Team:     r.Team,
+			Opponent: r.Opponent,
+			Home:     homeTeams[r.ID.GameID] == r.Team,
+			Plays:    r.Plays,
+			EPA:      r.EPA,
+		})
+	}
+	return games
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestGameLogMergesStatsAndPlays(t *testing.T) {
+	const rb = "00-0038542"
+	play := func(gameID string, week int, defense, rusher, receiver string, epa float64) models.Play {
+		return models.Play{GameID: gameID, Season: 2024, Week: week, PossessionTeam: "ATL", DefenseTeam: defense,
+			RusherPlayerID: rusher, ReceiverPlayerID: receiver, EPA: epa}
+	}
+	plays := toDocs(t, []models.Play{
+		// Week 1 at home against PIT: two carries and a catch
+		play("2024_01_PIT_ATL", 1, "PIT", rb, "", 0.4),
+		play("2024_01_PIT_ATL", 1, "PIT", rb, "", -0.2),
+		play("2024_01_PIT_ATL", 1, "PIT", "", rb, 1.0),
+		play("2024_01_PIT_ATL", 1, "PIT", "00-0000001", "", 3), // another back's carry
+		// Week 2 on the road at PHI
+		play("2024_02_ATL_PHI", 2, "PHI", rb, "", -0.5),
+		play("2024_02_ATL_PHI", 2, "PHI", "", rb, 0.1),
+	})
+
+	var rows []playerGameRow
+	decodeDocs(t, runPipeline(t, playerGamesPipeline(rb, 2024), plays, nil), &rows)
+	games := buildPlayerGames(rows, map[string]string{"2024_01_PIT_ATL": "ATL", "2024_02_ATL_PHI": "PHI"})
+
+	weekly := []models.WeeklyStat{
+		{NFLID: rb, Season: 2024, Week: 2, Opponent: "PHI", Carries: 18, RushingYards: 124, Targets: 4, Receptions: 3, ReceivingYards: 27, FantasyPoints: 15.1, FantasyPointsPPR: 18.1},
+		{NFLID: rb, Season: 2024, Week: 1, Opponent: "PIT", Carries: 18, RushingYards: 96, RushingTDs: 1, Targets: 5, Receptions: 4, ReceivingYards: 21, FantasyPoints: 17.7, FantasyPointsPPR: 21.7},
+	}
+	snaps := []models.SnapCount{
+		{Week: 1, OffenseSnaps: 52, OffensePct: 81},
+		{Week: 2, OffenseSnaps: 60, OffensePct: 88},
+	}
+
+	got := mergeGameLog(weekly, games, snaps)
+	if len(got) != 2 {
+		t.Fatalf("mergeGameLog() returned %d entries, want 2: %+v", len(got), got)
+	}
+
+	tests := []struct {
+		week     int
+		gameID   string
+		opponent string
+		home     bool
+		snaps    int
+		carries  int
+		yards    int
+		ppr      float64
+		plays    int
+		epa      float64
+	}{
+		{1, "2024_01_PIT_ATL", "PIT", true, 52, 18, 96, 21.7, 3, 1.2},
+		{2, "2024_02_ATL_PHI", "PHI", false, 60, 18, 124, 18.1, 2, -0.4},
+	}
+	for i, tt := range tests {
+		e := got[i]
+		if e.Week != tt.week || e.GameID != tt.gameID || e.Opponent != tt.opponent || e.Home != tt.home || e.Team != "ATL" {
+			t.Errorf("entry %d game = week %d %s vs %s (home %v, team %s), want week %d %s vs %s (home %v, team ATL)",
+				i, e.Week, e.GameID, e.Opponent, e.Home, e.Team, tt.week, tt.gameID, tt.opponent, tt.home)
+		}
+		if e.Snaps != tt.snaps || e.Carries != tt.carries || e.RushingYards != tt.yards || e.FantasyPointsPPR != tt.ppr {
+			t.Errorf("week %d box = %d snaps, %d carries, %d yards, %v PPR, want %d, %d, %d, %v",
+				e.Week, e.Snaps, e.Carries, e.RushingYards, e.FantasyPointsPPR, tt.snaps, tt.carries, tt.yards, tt.ppr)
+		}
+		if e.Plays != tt.plays || math.Abs(e.EPA-tt.epa) > 1e-9 || math.Abs(e.EPAPerPlay-tt.epa/float64(tt.plays)) > 1e-9 {
+			t.Errorf("week %d EPA = %v over %d plays (%v/play), want %v over %d", e.Week, e.EPA, e.Plays, e.EPAPerPlay, tt.epa, tt.plays)
+		}
+	}
+}
+
+func TestGameLogKeepsSingleSourceWeeks(t *testing.T) {
+	// Week 3 has stats but no loaded plays; week 4 has plays but no weekly stats row yet
+	weekly := []models.WeeklyStat{{Week: 3, Opponent: "KC", RushingYards: 40}}
+	games := []playerGame{{Week: 4, GameID: "2024_04_NO_ATL", Team: "ATL", Opponent: "NO", Home: true, Plays: 0}}
+
+	got := mergeGameLog(weekly, games, nil)
+	if len(got) != 2 || got[0].Week != 3 || got[0].Opponent != "KC" || got[0].GameID != "" {
+		t.Fatalf("mergeGameLog() = %+v, want week 3 from stats only first", got)
+	}
+	if got[1].Week != 4 || got[1].Opponent != "NO" || !got[1].Home || got[1].EPAPerPlay != 0 {
+		t.Errorf("week 4 = %+v, want the home game against NO with no EPA per play", got[1])
+	}
+}
@@ -3,118 +3,258 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/prompts"
 	"github.com/ai-atl/nfl-platform/pkg/gemini"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
-type InjuryAnalyzerService struct {
+// vacatedShareToPosition is the fraction of an injured player's volume expected to stay
+// within the position group; the rest spreads to other positions and scheme changes
+const vacatedShareToPosition = 0.7
+
+type InjuryImpactService struct {
 	db     *mongo.Database
 	gemini *gemini.Client
 }
 
 type InjuryImpact struct {
-	InjuredPlayer  string            `json:"injured_player"`
-	Analysis       string            `json:"analysis"`
-	Beneficiaries  []PlayerBenefit   `json:"beneficiaries"`
-	Confidence     float64           `json:"confidence"`
+	InjuredPlayer   string          `json:"injured_player"`
+	InjuredPlayerID string          `json:"injured_player_id"`
+	Team            string          `json:"team"`
+	Position        string          `json:"position"`
+	Season          int             `json:"season"`
+	Vacated         OpportunityRate `json:"vacated"`
+	Beneficiaries   []PlayerBenefit `json:"beneficiaries"`
+	NoClearBackup   bool            `json:"no_clear_backup"`
+	Analysis        string          `json:"analysis"`
+	Confidence      float64         `json:"confidence"`
+}
+
+// OpportunityRate is per-game volume for a player
+type OpportunityRate struct {
+	Games          int     `json:"games"`
+	TargetsPerGame float64 `json:"targets_per_game"`
+	CarriesPerGame float64 `json:"carries_per_game"`
 }
 
 type PlayerBenefit struct {
-	PlayerName       string  `json:"player_name"`
-	ExpectedIncrease string  `json:"expected_increase"`
-	Reasoning        string  `json:"reasoning"`
+	NFLID            string          `json:"nfl_id"`
+	PlayerName       string          `json:"player_name"`
+	DepthOrder       int             `json:"depth_order"` // 1 = next man up
+	Current          OpportunityRate `json:"current"`
+	Projected        OpportunityRate `json:"projected"`
+	ExpectedIncrease string          `json:"expected_increase"`
+	Reasoning        string          `json:"reasoning"`
 }
 
-func NewInjuryAnalyzerService(db *mongo.Database) *InjuryAnalyzerService {
-	return &InjuryAnalyzerService{
+func NewInjuryImpactService(db *mongo.Database) *InjuryImpactService {
+	return &InjuryImpactService{
 		db:     db,
-		gemini: gemini.NewClient(),
+		gemini: gemini.NewClient(gemini.AnalysisTier()),
 	}
 }
 
-// AnalyzeInjuryImpact predicts how an injury affects other players
-func (s *InjuryAnalyzerService) AnalyzeInjuryImpact(ctx context.Context, playerID string) (*InjuryImpact, error) {
-	// Get injured player
+// Analyze estimates how an injured player's targets and carries redistribute to the
+// players behind him on the depth chart and adds an AI narrative
+func (s *InjuryImpactService) Analyze(ctx context.Context, playerID string) (*InjuryImpact, error) {
+	// Get injured player (most recent season)
 	var player models.Player
-	err := s.db.Collection("players").FindOne(ctx, bson.M{"nfl_id": playerID}).Decode(&player)
+	err := s.db.Collection("players").FindOne(ctx, bson.M{"nfl_id": playerID},
+		options.FindOne().SetSort(bson.D{{Key: "season", Value: -1}})).Decode(&player)
 	if err != nil {
 		return nil, fmt.Errorf("player not found: %w", err)
 	}
-	
-	// Get teammates
+
+	// Get teammates at the same position
 	var teammates []models.Player
 	cursor, err := s.db.Collection("players").Find(ctx, bson.M{
-		"team": player.Team,
-		"nfl_id": bson.M{"$ne": playerID},
+		"team":     player.Team,
+		"position": player.Position,
+		"season":   player.Season,
+		"nfl_id":   bson.M{"$ne": playerID},
 	})
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(ctx)
-	
 	if err := cursor.All(ctx, &teammates); err != nil {
 		return nil, err
 	}
-	
-	// Generate AI analysis
-	prompt := s.buildInjuryPrompt(player, teammates)
-	response, err := s.gemini.GenerateWithRetry(ctx, prompt, 3)
+
+	ids := []string{playerID}
+	for _, t := range teammates {
+		ids = append(ids, t.NFLID)
+	}
+	usage, err := s.opportunityRates(ctx, ids, player.Season)
 	if err != nil {
 		return nil, err
 	}
-	
-	// Parse response and create impact analysis
+
 	impact := &InjuryImpact{
-		InjuredPlayer: player.Name,
-		Analysis:      response,
-		Beneficiaries: s.extractBeneficiaries(teammates, player),
-		Confidence:    0.75,
+		InjuredPlayer:   player.Name,
+		InjuredPlayerID: playerID,
+		Team:            player.Team,
+		Position:        player.Position,
+		Season:          player.Season,
+		Vacated:         usage[playerID],
+		Beneficiaries:   redistributeOpportunity(usage[playerID], teammates, usage),
+	}
+	impact.NoClearBackup = len(impact.Beneficiaries) == 0
+
+	// Confidence grows with the sample behind the injured player's usage
+	impact.Confidence = math.Min(0.9, 0.4+0.05*float64(impact.Vacated.Games))
+	if impact.NoClearBackup {
+		impact.Confidence = math.Min(impact.Confidence, 0.4)
 	}
-	
+
+	prompt, err := s.buildInjuryPrompt(player, impact)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.gemini.GenerateWithRetry(ctx, prompt, 3)
+	if err != nil {
+		log.Printf("Injury impact narrative failed for %s: %v", playerID, err)
+		response = "AI analysis unavailable."
+	}
+	impact.Analysis = response
+
 	return impact, nil
 }
 
-func (s *InjuryAnalyzerService) buildInjuryPrompt(injured models.Player, teammates []models.Player) string {
-	teamStr := fmt.Sprintf("Injured Player: %s (%s - %s)\n", injured.Name, injured.Position, injured.Team)
-	teamStr += fmt.Sprintf("Season: %d\n\n", injured.Season)
-	
-	teamStr += "Team Depth Chart:\n"
-	for _, teammate := range teammates {
-		if teammate.Position == injured.Position {
-			teamStr += fmt.Sprintf("- %s (%s)\n", teammate.Name, teammate.Position)
-		}
+// opportunityRates computes per-game targets and carries for each player from weekly stats
+func (s *InjuryImpactService) opportunityRates(ctx context.Context, nflIDs []string, season int) (map[string]OpportunityRate, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"nfl_id": bson.M{"$in": nflIDs},
+			"season": season,
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$nfl_id",
+			"games":   bson.M{"$sum": 1},
+			"targets": bson.M{"$sum": "$targets"},
+			"carries": bson.M{"$sum": "$carries"},
+		}}},
 	}
-	
-	return fmt.Sprintf(`Analyze this NFL injury impact:
 
-%s
+	cursor, err := s.db.Collection("player_weekly_stats").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate usage: %w", err)
+	}
+	defer cursor.Close(ctx)
 
-Predict:
-1. Which teammates will see increased opportunity?
-2. Specific percentage increases in touches/targets/snaps
-3. How this affects the team's offensive game plan
-4. Fantasy implications for each affected player
+	var results []struct {
+		NFLID   string `bson:"_id"`
+		Games   int    `bson:"games"`
+		Targets int    `bson:"targets"`
+		Carries int    `bson:"carries"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode usage: %w", err)
+	}
 
-Provide quantified predictions (e.g., "+25%% targets") with reasoning.`, teamStr)
+	rates := make(map[string]OpportunityRate, len(results))
+	for _, r := range results {
+		if r.Games == 0 {
+			continue
+		}
+		rates[r.NFLID] = OpportunityRate{
+			Games:          r.Games,
+			TargetsPerGame: float64(r.Targets) / float64(r.Games),
+			CarriesPerGame: float64(r.Carries) / float64(r.Games),
+		}
+	}
+	return rates, nil
 }
 
-func (s *InjuryAnalyzerService) extractBeneficiaries(teammates []models.Player, injured models.Player) []PlayerBenefit {
-	var benefits []PlayerBenefit
-	
-	// Simple heuristic: players at same position are potential beneficiaries
-	for _, teammate := range teammates {
-		if teammate.Position == injured.Position && teammate.NFLID != injured.NFLID {
-			benefits = append(benefits, PlayerBenefit{
-				PlayerName:       teammate.Name,
-				ExpectedIncrease: "+30% opportunity",
-				Reasoning:        "Primary backup - expected to absorb injured player's snaps",
-			})
+// redistributeOpportunity orders backups by current volume and splits the vacated volume
+// between the top three in proportion to that volume (plus one touch of smoothing so
+// backups who have played without a touch still get a share). Teammates with no games
+// this season are skipped; when none have played, no beneficiaries are returned.
+func redistributeOpportunity(vacated OpportunityRate, teammates []models.Player, usage map[string]OpportunityRate) []PlayerBenefit {
+	type candidate struct {
+		player models.Player
+		rate   OpportunityRate
+		volume float64
+	}
+
+	var candidates []candidate
+	for _, t := range teammates {
+		rate, ok := usage[t.NFLID]
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, candidate{t, rate, rate.TargetsPerGame + rate.CarriesPerGame})
+	}
+	if len(candidates) == 0 {
+		return []PlayerBenefit{}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].volume > candidates[j].volume })
+
+	// Only the top three backups realistically absorb work
+	if len(candidates) > 3 {
+		candidates = candidates[:3]
+	}
+
+	totalWeight := 0.0
+	for _, c := range candidates {
+		totalWeight += c.volume + 1
+	}
+
+	benefits := make([]PlayerBenefit, 0, len(candidates))
+	for i, c := range candidates {
+		share := vacatedShareToPosition * (c.volume + 1) / totalWeight
+		extraTargets := vacated.TargetsPerGame * share
+		extraCarries := vacated.CarriesPerGame * share
+
+		var parts []string
+		if extraCarries >= 0.5 {
+			parts = append(parts, fmt.Sprintf("+%.1f carries/game", extraCarries))
+		}
+		if extraTargets >= 0.5 {
+			parts = append(parts, fmt.Sprintf("+%.1f targets/game", extraTargets))
+		}
+		increase := "Minimal change"
+		if len(parts) > 0 {
+			increase = strings.Join(parts, ", ")
+		}
+
+		reasoning := fmt.Sprintf("Depth #%d at %s; projected to absorb %.0f%% of vacated volume", i+1, c.player.Position, share*100)
+		if i == 0 {
+			reasoning = fmt.Sprintf("Next man up at %s; projected to absorb %.0f%% of vacated volume", c.player.Position, share*100)
 		}
+
+		benefits = append(benefits, PlayerBenefit{
+			NFLID:      c.player.NFLID,
+			PlayerName: c.player.Name,
+			DepthOrder: i + 1,
+			Current:    c.rate,
+			Projected: OpportunityRate{
+				Games:          c.rate.Games,
+				TargetsPerGame: c.rate.TargetsPerGame + extraTargets,
+				CarriesPerGame: c.rate.CarriesPerGame + extraCarries,
+			},
+			ExpectedIncrease: increase,
+			Reasoning:        reasoning,
+		})
 	}
-	
+
 	return benefits
 }
 
+// injuryPromptData is the data behind the injury_impact prompt
+type injuryPromptData struct {
+	Injured models.Player
+	Impact  *InjuryImpact
+}
+
+func (s *InjuryImpactService) buildInjuryPrompt(injured models.Player, impact *InjuryImpact) (string, error) {
+	return prompts.Render(prompts.InjuryImpact, injuryPromptData{Injured: injured, Impact: impact})
+}
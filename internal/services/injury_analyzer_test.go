@@ -0,0 +1,63 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestRedistributeOpportunity(t *testing.T) {
+	// The injured starter's volume
+	vacated := OpportunityRate{Games: 9, CarriesPerGame: 15, TargetsPerGame: 4}
+	teammates := []models.Player{
+		{NFLID: "rb3", Name: "Third String", Position: "RB"},
+		{NFLID: "rb2", Name: "Change of Pace", Position: "RB"},
+		{NFLID: "rb4", Name: "Practice Squad Callup", Position: "RB"}, // No games this season
+	}
+	usage := map[string]OpportunityRate{
+		"rb2": {Games: 9, CarriesPerGame: 6, TargetsPerGame: 2},
+		"rb3": {Games: 4, CarriesPerGame: 2, TargetsPerGame: 1},
+	}
+
+	benefits := redistributeOpportunity(vacated, teammates, usage)
+	if len(benefits) != 2 {
+		t.Fatalf("got %d beneficiaries, want 2: %+v", len(benefits), benefits)
+	}
+
+	// Shares are proportional to volume + 1: 9/13 and 4/13 of the 70% that stays in the group
+	want := []struct {
+		nflID   string
+		share   float64
+		carries float64
+		targets float64
+	}{
+		{"rb2", 0.7 * 9 / 13, 6, 2},
+		{"rb3", 0.7 * 4 / 13, 2, 1},
+	}
+	totalShare := 0.0
+	for i, w := range want {
+		b := benefits[i]
+		if b.NFLID != w.nflID || b.DepthOrder != i+1 {
+			t.Errorf("beneficiary %d = %s (depth %d), want %s (depth %d)", i, b.NFLID, b.DepthOrder, w.nflID, i+1)
+		}
+		if got := b.Projected.CarriesPerGame; math.Abs(got-(w.carries+15*w.share)) > 1e-9 {
+			t.Errorf("%s projected carries = %v, want %v", w.nflID, got, w.carries+15*w.share)
+		}
+		if got := b.Projected.TargetsPerGame; math.Abs(got-(w.targets+4*w.share)) > 1e-9 {
+			t.Errorf("%s projected targets = %v, want %v", w.nflID, got, w.targets+4*w.share)
+		}
+		totalShare += w.share
+	}
+	if math.Abs(totalShare-vacatedShareToPosition) > 1e-9 {
+		t.Errorf("shares sum to %v, want %v", totalShare, vacatedShareToPosition)
+	}
+	if benefits[0].ExpectedIncrease != "+7.3 carries/game, +1.9 targets/game" {
+		t.Errorf("next man up increase = %q", benefits[0].ExpectedIncrease)
+	}
+
+	// No teammate has played: no clear backup
+	if got := redistributeOpportunity(vacated, teammates[2:], usage); len(got) != 0 {
+		t.Errorf("redistributeOpportunity() with no usable backups = %+v, want none", got)
+	}
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// InsightService provides data-driven (non-AI) insight queries
+type InsightService struct {
+	db          *mongo.Database
+	data        *DataService
+	projections Projections
+}
+
+func NewInsightService(db *mongo.Database) *InsightService {
+	return &InsightService{
+		db:          db,
+		data:        NewDataService(db),
+		projections: NewTrailingAverageProjections(db),
+	}
+}
+
+// TopPerformer is a ranked fantasy scorer for a week or season-to-date
+type TopPerformer struct {
+	Rank          int     `json:"rank"`
+	NFLID         string  `json:"nfl_id"`
+	Name          string  `json:"name"`
+	Team          string  `json:"team"`
+	Position      string  `json:"position"`
+	FantasyPoints float64 `json:"fantasy_points"`
+	Games         int     `json:"games"`
+	PointsPerGame float64 `json:"points_per_game"`
+}
+
+// TopPerformers ranks players by fantasy points for a week (week=0 for season-to-date).
+// position "" includes all positions.
+func (s *InsightService) TopPerformers(ctx context.Context, position string, season, week, limit int, scoring ScoringConfig) ([]TopPerformer, error) {
+	match := bson.M{"season": season}
+	if week > 0 {
+		match["week"] = week
+	}
+	// Weekly stats carry no position, so filter to the position's players up front and
+	// only join player details for the rows that survive the limit
+	if position != "" {
+		var nflIDs []string
+		err := s.db.Collection("players").Distinct(ctx, "nfl_id", bson.M{"position": position, "season": season}).Decode(&nflIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s players: %w", position, err)
+		}
+		match["nfl_id"] = bson.M{"$in": nflIDs}
+	}
+
+	cursor, err := s.db.Collection("player_weekly_stats").Aggregate(ctx, topPerformersPipeline(match, season, limit, scoring))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate top performers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []topPerformerRow
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode top performers: %w", err)
+	}
+
+	return rankTopPerformers(results), nil
+}
+
+// topPerformerRow is a player's points total from topPerformersPipeline
+type topPerformerRow struct {
+	NFLID  string  `bson:"_id"`
+	Points float64 `bson:"points"`
+	Games  int     `bson:"games"`
+	Player struct {
+		Name     string `bson:"name"`
+		Team     string `bson:"team"`
+		Position string `bson:"position"`
+	} `bson:"player"`
+}
+
+// topPerformersPipeline totals points per player over the weekly stats matching match,
+// keeps the top limit and joins each player's season entry
+func topPerformersPipeline(match bson.M, season, limit int, scoring ScoringConfig) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$nfl_id",
+			"points": bson.M{"$sum": scoring.pointsExpr()},
+			"games":  bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "points", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "players",
+			"let":  bson.M{"nfl_id": "$_id"},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"$expr": bson.M{"$and": []bson.M{
+						{"$eq": []interface{}{"$nfl_id", "$$nfl_id"}},
+						{"$eq": []interface{}{"$season", season}},
+					}},
+				}}},
+				{{Key: "$limit", Value: 1}},
+			},
+			"as": "player",
+		}}},
+		{{Key: "$unwind", Value: "$player"}},
+	}
+}
+
+// rankTopPerformers numbers the pipeline's rows, already sorted by points
+func rankTopPerformers(results []topPerformerRow) []TopPerformer {
+	performers := make([]TopPerformer, 0, len(results))
+	for i, r := range results {
+		ppg := 0.0
+		if r.Games > 0 {
+			ppg = r.Points / float64(r.Games)
+		}
+		performers = append(performers, TopPerformer{
+			Rank:          i + 1,
+			NFLID:         r.NFLID,
+			Name:          r.Player.Name,
+			Team:          r.Player.Team,
+			Position:      r.Player.Position,
+			FantasyPoints: r.Points,
+			Games:         r.Games,
+			PointsPerGame: ppg,
+		})
+	}
+
+	return performers
+}
+
+// StreamingDefense is a defense ranked by the fantasy points it allows to a position
+type StreamingDefense struct {
+	Rank            int     `json:"rank"`
+	Defense         string  `json:"defense"`
+	Games           int     `json:"games"`
+	PointsAllowed   float64 `json:"points_allowed"` // Season-to-date total
+	AllowedPerGame  float64 `json:"allowed_per_game"`
+	LastGameAllowed float64 `json:"last_game_allowed"`  // Most recent week played
+	Opponent        string  `json:"opponent,omitempty"` // Offense facing this defense in the target week
+	Home            bool    `json:"home"`
+}
+
+// StreamingDefenses ranks defenses by fantasy points allowed per game to position over
+// the weeks before week, best matchups first. Weekly totals come from the opponents'
+// player_weekly_stats. With week > 0 only defenses playing that week are returned,
+// along with the offense they face; week=0 ranks the whole season.
+func (s *InsightService) StreamingDefenses(ctx context.Context, position string, season, week int, scoring ScoringConfig) ([]StreamingDefense, error) {
+	cursor, err := s.db.Collection("player_weekly_stats").Aggregate(ctx, streamingDefensesPipeline(position, season, week, scoring))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate points allowed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []pointsAllowedRow
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode points allowed: %w", err)
+	}
+
+	var games []models.Game
+	if week > 0 {
+		cursor, err := s.db.Collection("games").Find(ctx, bson.M{"season": season, "week": week})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch week %d games: %w", week, err)
+		}
+		if err := cursor.All(ctx, &games); err != nil {
+			return nil, fmt.Errorf("failed to decode games: %w", err)
+		}
+	}
+
+	return rankStreamingDefenses(results, week, games), nil
+}
+
+// pointsAllowedRow is one defense's fantasy points allowed from streamingDefensesPipeline
+type pointsAllowedRow struct {
+	Defense string  `bson:"_id"`
+	Total   float64 `bson:"total"`
+	Games   int     `bson:"games"`
+	Latest  float64 `bson:"latest"`
+}
+
+// streamingDefensesPipeline totals the fantasy points each defense allowed to position,
+// per game, in the weeks of season before week (all weeks when week is 0)
+func streamingDefensesPipeline(position string, season, week int, scoring ScoringConfig) mongo.Pipeline {
+	pointsField := scoring.pointsExpr()
+
+	match := bson.M{"season": season, "opponent": bson.M{"$nin": []interface{}{"", nil}}}
+	if week > 0 {
+		match["week"] = bson.M{"$lt": week}
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "players",
+			"let":  bson.M{"nfl_id": "$nfl_id"},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"$expr": bson.M{"$and": []bson.M{
+						{"$eq": []interface{}{"$nfl_id", "$$nfl_id"}},
+						{"$eq": []interface{}{"$season", season}},
+					}},
+				}}},
+				{{Key: "$project", Value: bson.M{"position": 1}}},
+				{{Key: "$limit", Value: 1}},
+			},
+			"as": "player",
+		}}},
+		{{Key: "$unwind", Value: "$player"}},
+		{{Key: "$match", Value: bson.M{"player.position": position}}},
+		// Points allowed by each defense in each week
+		{{Key: "$group", Value: bson.M{
+			"_id":    bson.M{"defense": "$opponent", "week": "$week"},
+			"points": bson.M{"$sum": pointsField},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.week", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$_id.defense",
+			"total":  bson.M{"$sum": "$points"},
+			"games":  bson.M{"$sum": 1},
+			"latest": bson.M{"$last": "$points"},
+		}}},
+	}
+}
+
+// rankStreamingDefenses orders defenses by points allowed per game, most generous first.
+// For a target week, defenses without a game that week (byes) are dropped and the rest
+// get their opponent.
+func rankStreamingDefenses(results []pointsAllowedRow, week int, games []models.Game) []StreamingDefense {
+	// Opponent each defense faces in the target week (teams on bye are dropped)
+	type matchup struct {
+		opponent string
+		home     bool
+	}
+	var matchups map[string]matchup
+	if week > 0 {
+		matchups = make(map[string]matchup, len(games)*2)
+		for _, g := range games {
+			matchups[g.HomeTeam] = matchup{opponent: g.AwayTeam, home: true}
+			matchups[g.AwayTeam] = matchup{opponent: g.HomeTeam, home: false}
+		}
+	}
+
+	defenses := make([]StreamingDefense, 0, len(results))
+	for _, r := range results {
+		if r.Games == 0 {
+			continue
+		}
+		d := StreamingDefense{
+			Defense:         r.Defense,
+			Games:           r.Games,
+			PointsAllowed:   r.Total,
+			AllowedPerGame:  r.Total / float64(r.Games),
+			LastGameAllowed: r.Latest,
+		}
+		if matchups != nil {
+			m, ok := matchups[r.Defense]
+			if !ok {
+				continue
+			}
+			d.Opponent = m.opponent
+			d.Home = m.home
+		}
+		defenses = append(defenses, d)
+	}
+
+	sort.Slice(defenses, func(i, j int) bool { return defenses[i].AllowedPerGame > defenses[j].AllowedPerGame })
+	for i := range defenses {
+		defenses[i].Rank = i + 1
+	}
+
+	return defenses
+}
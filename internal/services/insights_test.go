@@ -0,0 +1,134 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestTopPerformers(t *testing.T) {
+	players := toDocs(t, []models.Player{
+		{NFLID: "qb", Name: "Quarterback", Team: "BUF", Position: "QB", Season: 2025},
+		{NFLID: "wr", Name: "Slot Receiver", Team: "DET", Position: "WR", Season: 2025},
+		{NFLID: "rb", Name: "Power Back", Team: "BAL", Position: "RB", Season: 2025},
+		{NFLID: "te", Name: "Tight End", Team: "KC", Position: "TE", Season: 2025},
+		{NFLID: "wr2", Name: "Deep Threat", Team: "MIA", Position: "WR", Season: 2025},
+		// An older entry for the receiver must not be joined
+		{NFLID: "wr", Name: "Slot Receiver", Team: "LAR", Position: "WR", Season: 2024},
+	})
+	stats := toDocs(t, []models.WeeklyStat{
+		{NFLID: "qb", Season: 2025, Week: 11, FantasyPoints: 24, FantasyPointsPPR: 24},
+		{NFLID: "wr", Season: 2025, Week: 11, FantasyPoints: 14, FantasyPointsPPR: 23, Receptions: 9},
+		{NFLID: "rb", Season: 2025, Week: 11, FantasyPoints: 20, FantasyPointsPPR: 22, Receptions: 2},
+		{NFLID: "te", Season: 2025, Week: 11, FantasyPoints: 8, FantasyPointsPPR: 14, Receptions: 6},
+		{NFLID: "wr2", Season: 2025, Week: 10, FantasyPoints: 40, FantasyPointsPPR: 45, Receptions: 5},
+	})
+	collections := map[string][]bson.M{"players": players}
+
+	tests := []struct {
+		name    string
+		week    int
+		scoring ScoringConfig
+		want    []string
+		points  []float64
+	}{
+		{"week 11 PPR", 11, ScoringPPR, []string{"qb", "wr", "rb"}, []float64{24, 23, 22}},
+		{"week 11 standard", 11, ScoringStandard, []string{"qb", "rb", "wr"}, []float64{24, 20, 14}},
+		{"week 11 half PPR", 11, ScoringHalfPPR, []string{"qb", "rb", "wr"}, []float64{24, 21, 18.5}},
+		{"season to date", 0, ScoringPPR, []string{"wr2", "qb", "wr"}, []float64{45, 24, 23}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := bson.M{"season": 2025}
+			if tt.week > 0 {
+				match["week"] = tt.week
+			}
+			var rows []topPerformerRow
+			decodeDocs(t, runPipeline(t, topPerformersPipeline(match, 2025, 3, tt.scoring), stats, collections), &rows)
+			performers := rankTopPerformers(rows)
+
+			var ids []string
+			for i, p := range performers {
+				ids = append(ids, p.NFLID)
+				if p.Rank != i+1 {
+					t.Errorf("%s rank = %d, want %d", p.NFLID, p.Rank, i+1)
+				}
+				if i < len(tt.points) && math.Abs(p.FantasyPoints-tt.points[i]) > 1e-9 {
+					t.Errorf("%s points = %v, want %v", p.NFLID, p.FantasyPoints, tt.points[i])
+				}
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("top 3 = %v, want %v", ids, tt.want)
+			}
+			if len(performers) > 0 && performers[0].NFLID == "qb" && (performers[0].Name != "Quarterback" || performers[0].Team != "BUF") {
+				t.Errorf("joined player = %s (%s), want Quarterback (BUF)", performers[0].Name, performers[0].Team)
+			}
+		})
+	}
+
+	// The receiver's 2025 entry is joined, not the 2024 one
+	var rows []topPerformerRow
+	decodeDocs(t, runPipeline(t, topPerformersPipeline(bson.M{"season": 2025, "nfl_id": "wr"}, 2025, 3, ScoringPPR), stats, collections), &rows)
+	if got := rankTopPerformers(rows); len(got) != 1 || got[0].Team != "DET" || got[0].PointsPerGame != 23 {
+		t.Errorf("receiver = %+v, want one DET row at 23 points per game", got)
+	}
+}
+
+func TestStreamingDefenses(t *testing.T) {
+	players := toDocs(t, []models.Player{
+		{NFLID: "wr1", Position: "WR", Season: 2025},
+		{NFLID: "wr2", Position: "WR", Season: 2025},
+		{NFLID: "rb1", Position: "RB", Season: 2025},
+		{NFLID: "old", Position: "WR", Season: 2024}, // no 2025 roster entry
+	})
+	stat := func(id, opp string, week int, ppr float64) models.WeeklyStat {
+		return models.WeeklyStat{NFLID: id, Season: 2025, Week: week, Opponent: opp, FantasyPointsPPR: ppr, FantasyPoints: ppr / 2}
+	}
+	stats := toDocs(t, []models.WeeklyStat{
+		stat("wr1", "DEN", 1, 20),
+		stat("wr2", "DEN", 1, 10),
+		stat("wr1", "DEN", 3, 12),
+		stat("wr2", "LV", 2, 25),
+		stat("wr1", "NYJ", 2, 8),
+		stat("wr2", "NYJ", 1, 6),
+		stat("rb1", "LV", 2, 30),  // another position
+		stat("old", "NYJ", 3, 40), // not on a 2025 roster
+		stat("wr1", "KC", 4, 50),  // the target week itself
+		stat("wr2", "", 3, 15),    // no opponent recorded
+	})
+	collections := map[string][]bson.M{"players": players}
+	games := []models.Game{
+		{Season: 2025, Week: 4, HomeTeam: "DEN", AwayTeam: "KC"},
+		{Season: 2025, Week: 4, HomeTeam: "MIA", AwayTeam: "NYJ"},
+	}
+
+	var rows []pointsAllowedRow
+	decodeDocs(t, runPipeline(t, streamingDefensesPipeline("WR", 2025, 4, ScoringPPR), stats, collections), &rows)
+	got := rankStreamingDefenses(rows, 4, games)
+
+	want := []StreamingDefense{
+		{Rank: 1, Defense: "DEN", Games: 2, PointsAllowed: 42, AllowedPerGame: 21, LastGameAllowed: 12, Opponent: "KC", Home: true},
+		{Rank: 2, Defense: "NYJ", Games: 2, PointsAllowed: 14, AllowedPerGame: 7, LastGameAllowed: 8, Opponent: "MIA"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("week 4 = %+v, want %+v (LV is on bye)", got, want)
+	}
+
+	// Without a target week every defense ranks, and all weeks count
+	decodeDocs(t, runPipeline(t, streamingDefensesPipeline("WR", 2025, 0, ScoringStandard), stats, collections), &rows)
+	all := rankStreamingDefenses(rows, 0, nil)
+	var order []string
+	for _, d := range all {
+		order = append(order, d.Defense)
+	}
+	if want := []string{"KC", "LV", "DEN", "NYJ"}; !slices.Equal(order, want) {
+		t.Errorf("season order = %v, want %v", order, want)
+	}
+	if all[1].AllowedPerGame != 12.5 || all[0].Opponent != "" {
+		t.Errorf("standard LV per game = %v, KC opponent = %q; want 12.5 and none", all[1].AllowedPerGame, all[0].Opponent)
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Lineup help buckets
+const (
+	BucketStart        = "start"
+	BucketQuestionable = "questionable"
+	BucketSit          = "sit"
+)
+
+// LineupHelpPlayer is one rostered player's start/sit call for a week
+type LineupHelpPlayer struct {
+	PlayerID        string  `json:"player_id"`
+	Name            string  `json:"name"`
+	Position        string  `json:"position"`
+	Team            string  `json:"team"`
+	Slot            string  `json:"slot,omitempty"` // Slot in the best lineup, if the player makes it
+	ProjectedPoints float64 `json:"projected_points"`
+	Opponent        string  `json:"opponent,omitempty"`
+	Home            bool    `json:"home"`
+	OverUnder       float64 `json:"over_under,omitempty"`
+	MatchupRank     int     `json:"matchup_rank,omitempty"` // Opponent's defense vs the position, 1 = toughest
+	InjuryStatus    string  `json:"injury_status,omitempty"`
+	Reason          string  `json:"reason"`
+}
+
+// LineupHelp buckets a roster into players to start, game-time decisions and players to sit,
+// each ordered by projected points
+type LineupHelp struct {
+	Season       int                `json:"season"`
+	Week         int                `json:"week"`
+	Start        []LineupHelpPlayer `json:"start"`
+	Questionable []LineupHelpPlayer `json:"questionable"`
+	Sit          []LineupHelpPlayer `json:"sit"`
+}
+
+// LineupHelp decides who to start from a roster for a week. Players on bye, out, doubtful
+// or on injured reserve sit. The rest are placed in the best lineup by projected points;
+// questionable players are reported separately with the slot they'd fill if active.
+// Projections are scored with scoring.
+func (s *InsightService) LineupHelp(ctx context.Context, playerIDs []string, season, week int, settings models.LeagueSettings, scoring ScoringConfig) (*LineupHelp, error) {
+	if len(playerIDs) == 0 {
+		return nil, fmt.Errorf("%w: roster has no players", ErrInvalidLineup)
+	}
+
+	players, err := s.latestPlayers(ctx, playerIDs, season)
+	if err != nil {
+		return nil, err
+	}
+
+	games, err := s.data.GetGamesBySeason(ctx, season, week)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch week %d games: %w", week, err)
+	}
+	gamesByTeam := make(map[string]models.Game, len(games)*2)
+	for _, g := range games {
+		gamesByTeam[g.HomeTeam] = g
+		gamesByTeam[g.AwayTeam] = g
+	}
+
+	reports, err := s.injuryReports(ctx, playerIDs, season, week)
+	if err != nil {
+		return nil, err
+	}
+
+	projections := projectAll(ctx, projectionsWithScoring(s.projections, scoring), playerIDs, season, week)
+	matchupRanks := make(map[string]map[string]int)
+	rank := func(position, opponent string) int {
+		return s.matchupRank(ctx, matchupRanks, position, opponent, season, week)
+	}
+
+	help := &LineupHelp{Season: season, Week: week}
+	if err := bucketLineup(help, playerIDs, players, gamesByTeam, reports, projections, settings, rank); err != nil {
+		return nil, err
+	}
+	return help, nil
+}
+
+// bucketLineup fills help's buckets from the roster's players, the week's games by team,
+// injury report statuses and projections. rank returns the opponent's defensive rank
+// against a position.
+func bucketLineup(help *LineupHelp, playerIDs []string, players map[string]models.Player, gamesByTeam map[string]models.Game, reports map[string]string, projections map[string]float64, settings models.LeagueSettings, rank func(position, opponent string) int) error {
+	help.Start, help.Questionable, help.Sit = []LineupHelpPlayer{}, []LineupHelpPlayer{}, []LineupHelpPlayer{}
+	candidates := make([]ProjectedPlayer, 0, len(players))
+	entries := make(map[string]*LineupHelpPlayer, len(players))
+	for _, id := range playerIDs {
+		player, ok := players[id]
+		if !ok {
+			return fmt.Errorf("%w: unknown player %s", ErrInvalidLineup, id)
+		}
+		if _, dup := entries[id]; dup {
+			continue
+		}
+
+		entry := &LineupHelpPlayer{
+			PlayerID:        id,
+			Name:            player.Name,
+			Position:        player.Position,
+			Team:            player.Team,
+			ProjectedPoints: projections[id],
+			InjuryStatus:    reports[id],
+		}
+		entries[id] = entry
+
+		game, playing := gamesByTeam[player.Team]
+		if !playing {
+			entry.Reason = "on bye"
+			help.Sit = append(help.Sit, *entry)
+			continue
+		}
+		entry.Home = game.HomeTeam == player.Team
+		entry.Opponent = game.HomeTeam
+		if entry.Home {
+			entry.Opponent = game.AwayTeam
+		}
+		entry.OverUnder = game.OverUnder
+		entry.MatchupRank = rank(player.Position, entry.Opponent)
+
+		switch {
+		case entry.InjuryStatus == "Out" || entry.InjuryStatus == "Doubtful":
+			entry.Reason = "ruled " + entry.InjuryStatus
+			help.Sit = append(help.Sit, *entry)
+			continue
+		case player.Status == "INA" || player.StatusDescriptionAbbr == "R01" || player.StatusDescriptionAbbr == "R04":
+			entry.InjuryStatus = models.GetPlayerStatusDescription(player.Status, player.StatusDescriptionAbbr)
+			entry.Reason = "inactive"
+			help.Sit = append(help.Sit, *entry)
+			continue
+		}
+
+		candidates = append(candidates, ProjectedPlayer{PlayerID: id, Position: player.Position, Projected: entry.ProjectedPoints})
+	}
+
+	starters := make(map[string]string, len(candidates))
+	for _, slot := range optimizeLineup(candidates, offenseSlotCounts(settings.LineupSlots)) {
+		starters[slot.PlayerID] = slot.Slot
+	}
+
+	for _, c := range candidates {
+		entry := entries[c.PlayerID]
+		entry.Slot = starters[c.PlayerID]
+		switch {
+		case entry.InjuryStatus == "Questionable":
+			entry.Reason = "questionable; check inactives before kickoff"
+			if entry.Slot == "" {
+				entry.Reason = "questionable and projected below your starters"
+			}
+			help.Questionable = append(help.Questionable, *entry)
+		case entry.Slot != "":
+			entry.Reason = "projected starter"
+			help.Start = append(help.Start, *entry)
+		default:
+			entry.Reason = "projected below your starters"
+			help.Sit = append(help.Sit, *entry)
+		}
+	}
+
+	for _, bucket := range [][]LineupHelpPlayer{help.Start, help.Questionable, help.Sit} {
+		sort.SliceStable(bucket, func(i, j int) bool { return bucket[i].ProjectedPoints > bucket[j].ProjectedPoints })
+	}
+	return nil
+}
+
+// latestPlayers maps each player ID to its most recent roster entry up to season
+func (s *InsightService) latestPlayers(ctx context.Context, ids []string, season int) (map[string]models.Player, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "season", Value: -1}})
+	cursor, err := s.db.Collection("players").Find(ctx, bson.M{"nfl_id": bson.M{"$in": ids}, "season": bson.M{"$lte": season}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []models.Player
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode players: %w", err)
+	}
+
+	players := make(map[string]models.Player, len(ids))
+	for _, p := range rows {
+		if _, ok := players[p.NFLID]; !ok {
+			players[p.NFLID] = p
+		}
+	}
+	return players, nil
+}
+
+// injuryReports maps each player ID to its game status (Out, Doubtful, Questionable) for the week
+func (s *InsightService) injuryReports(ctx context.Context, ids []string, season, week int) (map[string]string, error) {
+	cursor, err := s.db.Collection("injuries").Find(ctx, bson.M{"nfl_id": bson.M{"$in": ids}, "season": season, "week": week})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch injury reports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reports []models.InjuryReport
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, fmt.Errorf("failed to decode injury reports: %w", err)
+	}
+
+	statuses := make(map[string]string, len(reports))
+	for _, r := range reports {
+		if r.ReportStatus != "" {
+			statuses[r.NFLID] = r.ReportStatus
+		}
+	}
+	return statuses, nil
+}
+
+// matchupRank looks up the opponent's defensive rank against position through the previous
+// week, caching rankings per position. It returns 0 when the position isn't ranked.
+func (s *InsightService) matchupRank(ctx context.Context, cache map[string]map[string]int, position, opponent string, season, week int) int {
+	ranks, ok := cache[position]
+	if !ok {
+		ranks = make(map[string]int)
+		if rankings, err := s.data.GetDefensiveRankings(ctx, position, season, week-1); err == nil {
+			for _, r := range rankings {
+				ranks[r.Team] = r.Rank
+			}
+		}
+		cache[position] = ranks
+	}
+	return ranks[opponent]
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestBucketLineupByeAndInjury(t *testing.T) {
+	players := map[string]models.Player{
+		"qb1": {NFLID: "qb1", Name: "QB One", Position: "QB", Team: "KC", Status: "ACT"},
+		"rb1": {NFLID: "rb1", Name: "RB One", Position: "RB", Team: "KC", Status: "ACT"},
+		"rb2": {NFLID: "rb2", Name: "RB Two", Position: "RB", Team: "BUF", Status: "ACT"}, // on bye
+		"wr1": {NFLID: "wr1", Name: "WR One", Position: "WR", Team: "LV", Status: "ACT"},  // ruled out
+		"wr2": {NFLID: "wr2", Name: "WR Two", Position: "WR", Team: "LV", Status: "ACT"},
+		"te1": {NFLID: "te1", Name: "TE One", Position: "TE", Team: "KC", Status: "ACT"},
+	}
+	week9 := models.Game{HomeTeam: "KC", AwayTeam: "LV", OverUnder: 44.5}
+	gamesByTeam := map[string]models.Game{"KC": week9, "LV": week9}
+	reports := map[string]string{"wr1": "Out"}
+	projections := map[string]float64{"qb1": 22, "rb1": 14, "rb2": 25, "wr1": 20, "wr2": 11, "te1": 8}
+	rank := func(position, opponent string) int {
+		if opponent == "LV" {
+			return 30
+		}
+		return 5
+	}
+
+	help := &LineupHelp{Season: 2025, Week: 9}
+	ids := []string{"qb1", "rb1", "rb2", "wr1", "wr2", "te1"}
+	if err := bucketLineup(help, ids, players, gamesByTeam, reports, projections, models.DefaultLeagueSettings(), rank); err != nil {
+		t.Fatalf("bucketLineup() error = %v", err)
+	}
+
+	if len(help.Questionable) != 0 {
+		t.Errorf("Questionable = %+v, want none", help.Questionable)
+	}
+
+	start := make(map[string]LineupHelpPlayer)
+	for _, p := range help.Start {
+		start[p.PlayerID] = p
+	}
+	for _, id := range []string{"qb1", "rb1", "wr2", "te1"} {
+		if _, ok := start[id]; !ok {
+			t.Errorf("%s not in Start %+v", id, help.Start)
+		}
+	}
+	if qb := start["qb1"]; qb.Opponent != "LV" || !qb.Home || qb.OverUnder != 44.5 || qb.MatchupRank != 30 {
+		t.Errorf("qb1 matchup = %+v, want home vs LV, o/u 44.5, rank 30", qb)
+	}
+	if wr := start["wr2"]; wr.Opponent != "KC" || wr.Home || wr.MatchupRank != 5 {
+		t.Errorf("wr2 matchup = %+v, want away at KC, rank 5", wr)
+	}
+	for i := 1; i < len(help.Start); i++ {
+		if help.Start[i].ProjectedPoints > help.Start[i-1].ProjectedPoints {
+			t.Errorf("Start not ordered by projection: %+v", help.Start)
+		}
+	}
+
+	// The bye and the injury sit despite the two highest projections
+	sit := make(map[string]LineupHelpPlayer)
+	for _, p := range help.Sit {
+		sit[p.PlayerID] = p
+	}
+	if len(help.Sit) != 2 {
+		t.Errorf("Sit = %+v, want rb2 and wr1", help.Sit)
+	}
+	if rb := sit["rb2"]; rb.Reason != "on bye" || rb.Opponent != "" {
+		t.Errorf("rb2 = %+v, want sat on bye with no opponent", rb)
+	}
+	if wr := sit["wr1"]; wr.Reason != "ruled Out" || wr.InjuryStatus != "Out" {
+		t.Errorf("wr1 = %+v, want sat as ruled Out", wr)
+	}
+	if help.Sit[0].PlayerID != "rb2" {
+		t.Errorf("Sit[0] = %s, want rb2 (highest projection)", help.Sit[0].PlayerID)
+	}
+}
+
+func TestBucketLineupQuestionableAndUnknown(t *testing.T) {
+	players := map[string]models.Player{
+		"qb1": {NFLID: "qb1", Position: "QB", Team: "KC", Status: "ACT"},
+		"qb2": {NFLID: "qb2", Position: "QB", Team: "KC", Status: "INA", StatusDescriptionAbbr: "R01"},
+		"wr1": {NFLID: "wr1", Position: "WR", Team: "KC", Status: "ACT"},
+	}
+	gamesByTeam := map[string]models.Game{"KC": {HomeTeam: "KC", AwayTeam: "LV"}}
+	rank := func(position, opponent string) int { return 0 }
+
+	help := &LineupHelp{}
+	err := bucketLineup(help, []string{"qb1", "qb2", "wr1"}, players, gamesByTeam, map[string]string{"wr1": "Questionable"},
+		map[string]float64{"qb1": 20, "qb2": 25, "wr1": 12}, models.DefaultLeagueSettings(), rank)
+	if err != nil {
+		t.Fatalf("bucketLineup() error = %v", err)
+	}
+	if len(help.Questionable) != 1 || help.Questionable[0].PlayerID != "wr1" || help.Questionable[0].Slot == "" {
+		t.Errorf("Questionable = %+v, want wr1 with the slot they'd fill", help.Questionable)
+	}
+	if len(help.Sit) != 1 || help.Sit[0].PlayerID != "qb2" || help.Sit[0].Reason != "inactive" {
+		t.Errorf("Sit = %+v, want the injured-reserve qb2 inactive", help.Sit)
+	}
+
+	if err := bucketLineup(&LineupHelp{}, []string{"qb1", "nobody"}, players, gamesByTeam, nil, nil, models.DefaultLeagueSettings(), rank); !errors.Is(err, ErrInvalidLineup) {
+		t.Errorf("bucketLineup() with an unknown player error = %v, want ErrInvalidLineup", err)
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+// ProjectedPlayer is a rostered player with a projected score for the week
+type ProjectedPlayer struct {
+	PlayerID  string  `json:"player_id"`
+	Position  string  `json:"position"`
+	Projected float64 `json:"projected_points"`
+}
+
+// ProjectedSlot is a lineup slot filled by a projected player
+type ProjectedSlot struct {
+	Slot string `json:"slot"`
+	ProjectedPlayer
+}
+
+// LineupOptimization compares the optimal lineup for a roster with the user's current one
+type LineupOptimization struct {
+	Season             int             `json:"season"`
+	Week               int             `json:"week"`
+	Optimal            []ProjectedSlot `json:"optimal"`
+	OptimalPoints      float64         `json:"optimal_points"`
+	CurrentPoints      float64         `json:"current_points"`
+	PointsOnBench      float64         `json:"points_left_on_bench"`          // Optimal minus current
+	Start              []string        `json:"start"`                         // Bench players the optimal lineup starts
+	Bench              []string        `json:"bench"`                         // Current starters the optimal lineup benches
+	MissingProjections []string        `json:"missing_projections,omitempty"` // Players projected at zero
+}
+
+// Optimize builds the best lineup for a roster from the service's projections under the
+// league's slot settings. current is the user's existing slot assignment, if any, and is used
+// to report points left on the bench. DST slots are kept as submitted, since team defenses
+// aren't on the player roster, but are projected from their recent D/ST scoring.
+func (s *LineupService) Optimize(ctx context.Context, roster []string, current []models.LineupSlot, season, week int, settings models.LeagueSettings) (*LineupOptimization, error) {
+	ids := make([]string, 0, len(roster))
+	seen := make(map[string]bool, len(roster))
+	for _, id := range roster {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: roster has no players", ErrInvalidLineup)
+	}
+
+	positions, err := s.playerPositions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LineupOptimization{Season: season, Week: week}
+	projections := projectAll(ctx, s.projections, ids, season, week)
+	players := make([]ProjectedPlayer, 0, len(ids))
+	for _, id := range ids {
+		position, ok := positions[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown player %s", ErrInvalidLineup, id)
+		}
+		if projections[id] == 0 {
+			result.MissingProjections = append(result.MissingProjections, id)
+		}
+		players = append(players, ProjectedPlayer{PlayerID: id, Position: position, Projected: projections[id]})
+	}
+
+	result.Optimal = optimizeLineup(players, offenseSlotCounts(settings.LineupSlots))
+
+	starters := make(map[string]bool, len(result.Optimal))
+	for _, slot := range result.Optimal {
+		starters[slot.PlayerID] = true
+		result.OptimalPoints += slot.Projected
+	}
+
+	var defenses []string
+	for _, slot := range current {
+		if slot.Slot == models.SlotDST {
+			defenses = append(defenses, slot.PlayerID)
+		}
+	}
+	defenseProjections := map[string]float64{}
+	if len(defenses) > 0 {
+		defenseProjections = projectAll(ctx, s.projections, defenses, season, week)
+	}
+
+	currentStarters := make(map[string]bool, len(current))
+	for _, slot := range current {
+		if slot.Slot == models.SlotDST {
+			projected := defenseProjections[slot.PlayerID]
+			result.Optimal = append(result.Optimal, ProjectedSlot{Slot: slot.Slot, ProjectedPlayer: ProjectedPlayer{PlayerID: slot.PlayerID, Position: "DST", Projected: projected}})
+			result.OptimalPoints += projected
+			result.CurrentPoints += projected
+			continue
+		}
+		currentStarters[slot.PlayerID] = true
+		result.CurrentPoints += projections[slot.PlayerID]
+		if !starters[slot.PlayerID] {
+			result.Bench = append(result.Bench, slot.PlayerID)
+		}
+	}
+	if len(current) > 0 {
+		for _, slot := range result.Optimal {
+			if slot.Slot != models.SlotDST && !currentStarters[slot.PlayerID] {
+				result.Start = append(result.Start, slot.PlayerID)
+			}
+		}
+		result.PointsOnBench = result.OptimalPoints - result.CurrentPoints
+	}
+
+	return result, nil
+}
+
+// offenseSlotCounts drops the DST slot, which team defenses fill outside the optimizer
+func offenseSlotCounts(slotCounts map[string]int) map[string]int {
+	counts := make(map[string]int, len(slotCounts))
+	for slot, count := range slotCounts {
+		if slot != models.SlotDST {
+			counts[slot] = count
+		}
+	}
+	return counts
+}
+
+// optimizeLineup fills slots with the highest-projected eligible players. Slots are filled
+// from most to least restrictive (QB before FLEX before SUPERFLEX), which is optimal while
+// each wider slot's eligibility contains the narrower ones it overlaps. With a superflex
+// slot a second QB competes with every flex-eligible player, so QBs' typically higher
+// projections make them start over the remaining RB/WR/TE.
+func optimizeLineup(players []ProjectedPlayer, slotCounts map[string]int) []ProjectedSlot {
+	ranked := make([]ProjectedPlayer, len(players))
+	copy(ranked, players)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Projected > ranked[j].Projected })
+
+	slots := make([]string, 0, len(slotCounts))
+	for slot := range slotCounts {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		ei, ej := len(models.SlotEligibility[slots[i]]), len(models.SlotEligibility[slots[j]])
+		if ei != ej {
+			return ei < ej
+		}
+		return slots[i] < slots[j]
+	})
+
+	used := make(map[string]bool, len(players))
+	lineup := make([]ProjectedSlot, 0, len(players))
+	for _, slot := range slots {
+		for n := 0; n < slotCounts[slot]; n++ {
+			for _, p := range ranked {
+				if !used[p.PlayerID] && slotAccepts(slot, p.Position) {
+					used[p.PlayerID] = true
+					lineup = append(lineup, ProjectedSlot{Slot: slot, ProjectedPlayer: p})
+					break
+				}
+			}
+		}
+	}
+	return lineup
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestOptimizeLineupSuperflex(t *testing.T) {
+	roster := []ProjectedPlayer{
+		{PlayerID: "qb1", Position: "QB", Projected: 24},
+		{PlayerID: "qb2", Position: "QB", Projected: 19},
+		{PlayerID: "rb1", Position: "RB", Projected: 16},
+		{PlayerID: "rb2", Position: "RB", Projected: 13},
+		{PlayerID: "rb3", Position: "RB", Projected: 9},
+		{PlayerID: "wr1", Position: "WR", Projected: 17},
+		{PlayerID: "wr2", Position: "WR", Projected: 14},
+		{PlayerID: "wr3", Position: "WR", Projected: 11},
+		{PlayerID: "wr4", Position: "WR", Projected: 8},
+		{PlayerID: "te1", Position: "TE", Projected: 10},
+		{PlayerID: "k1", Position: "K", Projected: 8},
+	}
+
+	tests := []struct {
+		name        string
+		settings    models.LeagueSettings
+		wantStarted []string
+		wantPoints  float64
+	}{
+		{
+			name:        "standard league benches the second QB",
+			settings:    models.DefaultLeagueSettings(),
+			wantStarted: []string{"k1", "qb1", "rb1", "rb2", "rb3", "te1", "wr1", "wr2", "wr3"},
+			wantPoints:  24 + 16 + 13 + 9 + 17 + 14 + 11 + 10 + 8,
+		},
+		{
+			name:        "superflex starts the second QB",
+			settings:    models.SuperflexLeagueSettings(),
+			wantStarted: []string{"k1", "qb1", "qb2", "rb1", "rb2", "rb3", "te1", "wr1", "wr2", "wr3"},
+			wantPoints:  24 + 19 + 16 + 13 + 9 + 17 + 14 + 11 + 10 + 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lineup := optimizeLineup(roster, offenseSlotCounts(tt.settings.LineupSlots))
+
+			var started []string
+			var points float64
+			slotOf := map[string]string{}
+			for _, slot := range lineup {
+				started = append(started, slot.PlayerID)
+				points += slot.Projected
+				slotOf[slot.PlayerID] = slot.Slot
+				if !slotAccepts(slot.Slot, slot.Position) {
+					t.Errorf("%s (%s) placed in ineligible slot %s", slot.PlayerID, slot.Position, slot.Slot)
+				}
+			}
+			sort.Strings(started)
+
+			if !reflect.DeepEqual(started, tt.wantStarted) {
+				t.Errorf("started %v, want %v", started, tt.wantStarted)
+			}
+			if points != tt.wantPoints {
+				t.Errorf("optimal points = %v, want %v", points, tt.wantPoints)
+			}
+			if slotOf["qb1"] != models.SlotQB {
+				t.Errorf("qb1 in slot %q, want QB", slotOf["qb1"])
+			}
+		})
+	}
+}
+
+// mapProjections projects players from a fixed table
+type mapProjections map[string]float64
+
+func (m mapProjections) ProjectPlayer(ctx context.Context, nflID string, season, week int) float64 {
+	return m[nflID]
+}
+
+// TestOptimizeBenchesBigNameOnProjection checks the optimizer goes by projection alone,
+// benching a star receiver the user started for a lesser-known one projected higher
+func TestOptimizeBenchesBigNameOnProjection(t *testing.T) {
+	positions := map[string]string{
+		"mahomes": "QB", "henry": "RB", "robinson": "RB", "pacheco": "RB",
+		"jefferson": "WR", "nacua": "WR", "nailor": "WR", "wilson": "WR",
+		"kelce": "TE", "butker": "K",
+	}
+	projections := mapProjections{
+		"mahomes": 21, "henry": 17, "robinson": 15, "pacheco": 11,
+		"jefferson": 9, "nacua": 16, "nailor": 13, "wilson": 12,
+		"kelce": 10, "butker": 8, "KC": 7,
+	}
+	s := &LineupService{lineups: newMemLineupStore(positions), projections: projections}
+
+	current := []models.LineupSlot{
+		{Slot: models.SlotQB, PlayerID: "mahomes"},
+		{Slot: models.SlotRB, PlayerID: "henry"},
+		{Slot: models.SlotRB, PlayerID: "robinson"},
+		{Slot: models.SlotWR, PlayerID: "jefferson"},
+		{Slot: models.SlotWR, PlayerID: "nacua"},
+		{Slot: models.SlotWR, PlayerID: "wilson"},
+		{Slot: models.SlotTE, PlayerID: "kelce"},
+		{Slot: models.SlotFlex, PlayerID: "pacheco"},
+		{Slot: models.SlotK, PlayerID: "butker"},
+		{Slot: models.SlotDST, PlayerID: "KC"},
+	}
+	var roster []string
+	for _, slot := range current {
+		if slot.Slot != models.SlotDST {
+			roster = append(roster, slot.PlayerID)
+		}
+	}
+	roster = append(roster, "nailor")
+
+	result, err := s.Optimize(context.Background(), roster, current, 2025, 11, models.DefaultLeagueSettings())
+	if err != nil {
+		t.Fatalf("Optimize() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(result.Bench, []string{"jefferson"}) {
+		t.Errorf("Bench = %v, want [jefferson]", result.Bench)
+	}
+	if !reflect.DeepEqual(result.Start, []string{"nailor"}) {
+		t.Errorf("Start = %v, want [nailor]", result.Start)
+	}
+
+	slotOf := map[string]string{}
+	for _, slot := range result.Optimal {
+		slotOf[slot.PlayerID] = slot.Slot
+	}
+	if _, ok := slotOf["jefferson"]; ok {
+		t.Errorf("jefferson started in %s despite the lower projection", slotOf["jefferson"])
+	}
+	if slotOf["KC"] != models.SlotDST {
+		t.Errorf("KC slot = %q, want the submitted DST kept", slotOf["KC"])
+	}
+
+	// Nailor's 13 replaces Jefferson's 9 at WR; the rest of the lineup holds
+	if result.CurrentPoints != 126 || result.OptimalPoints != 130 || result.PointsOnBench != 4 {
+		t.Errorf("current/optimal/on bench = %v/%v/%v, want 126/130/4", result.CurrentPoints, result.OptimalPoints, result.PointsOnBench)
+	}
+	if len(result.MissingProjections) != 0 {
+		t.Errorf("MissingProjections = %v, want none", result.MissingProjections)
+	}
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	// ErrInvalidLineup is returned when a lineup's slots are illegal for the league
+	ErrInvalidLineup = errors.New("invalid lineup")
+	// ErrLineupNotFound is returned when no lineup exists with the given ID
+	ErrLineupNotFound = errors.New("lineup not found")
+	// ErrLineupForbidden is returned when a user acts on another user's lineup
+	ErrLineupForbidden = errors.New("lineup belongs to another user")
+)
+
+type LineupService struct {
+	db          *mongo.Database
+	lineups     lineupStore
+	projections Projections
+}
+
+func NewLineupService(db *mongo.Database) *LineupService {
+	return &LineupService{
+		db:          db,
+		lineups:     mongoLineupStore{db: db},
+		projections: NewTrailingAverageProjections(db),
+	}
+}
+
+// lineupStore keeps saved lineups and looks up the player positions they are validated
+// against. Find returns ErrLineupNotFound for an unknown ID.
+type lineupStore interface {
+	List(ctx context.Context, userID bson.ObjectID) ([]models.FantasyLineup, error)
+	Insert(ctx context.Context, lineup *models.FantasyLineup) error
+	Find(ctx context.Context, lineupID bson.ObjectID) (*models.FantasyLineup, error)
+	Update(ctx context.Context, lineupID, userID bson.ObjectID, set bson.M) error
+	Delete(ctx context.Context, lineupID, userID bson.ObjectID) error
+	PlayerPositions(ctx context.Context, ids []string) (map[string]string, error)
+}
+
+// mongoLineupStore keeps lineups in the lineups collection and reads positions from players
+type mongoLineupStore struct {
+	db *mongo.Database
+}
+
+func (s mongoLineupStore) List(ctx context.Context, userID bson.ObjectID) ([]models.FantasyLineup, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "season", Value: -1}, {Key: "week", Value: -1}})
+	cursor, err := s.db.Collection("lineups").Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	lineups := []models.FantasyLineup{}
+	if err := cursor.All(ctx, &lineups); err != nil {
+		return nil, err
+	}
+	return lineups, nil
+}
+
+func (s mongoLineupStore) Insert(ctx context.Context, lineup *models.FantasyLineup) error {
+	_, err := s.db.Collection("lineups").InsertOne(ctx, lineup)
+	return err
+}
+
+func (s mongoLineupStore) Find(ctx context.Context, lineupID bson.ObjectID) (*models.FantasyLineup, error) {
+	var lineup models.FantasyLineup
+	err := s.db.Collection("lineups").FindOne(ctx, bson.M{"_id": lineupID}).Decode(&lineup)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrLineupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lineup, nil
+}
+
+func (s mongoLineupStore) Update(ctx context.Context, lineupID, userID bson.ObjectID, set bson.M) error {
+	_, err := s.db.Collection("lineups").UpdateOne(ctx, bson.M{"_id": lineupID, "user_id": userID}, bson.M{"$set": set})
+	return err
+}
+
+func (s mongoLineupStore) Delete(ctx context.Context, lineupID, userID bson.ObjectID) error {
+	_, err := s.db.Collection("lineups").DeleteOne(ctx, bson.M{"_id": lineupID, "user_id": userID})
+	return err
+}
+
+// PlayerPositions maps each player ID to its position in the latest season it was rostered
+func (s mongoLineupStore) PlayerPositions(ctx context.Context, ids []string) (map[string]string, error) {
+	positions := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return positions, nil
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "season", Value: -1}}).
+		SetProjection(bson.M{"nfl_id": 1, "position": 1})
+	cursor, err := s.db.Collection("players").Find(ctx, bson.M{"nfl_id": bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var players []models.Player
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, err
+	}
+	for _, p := range players {
+		if _, ok := positions[p.NFLID]; !ok {
+			positions[p.NFLID] = p.Position
+		}
+	}
+	return positions, nil
+}
+
+// WithScoring returns a copy of the service that projects players under the given scoring
+func (s *LineupService) WithScoring(scoring ScoringConfig) *LineupService {
+	scored := *s
+	scored.projections = projectionsWithScoring(s.projections, scoring)
+	return &scored
+}
+
+// WithESPNProjections returns a copy of the service that projects players with ESPN's
+// projected points, keyed by player name, falling back to its own projections for
+// players ESPN doesn't cover
+func (s *LineupService) WithESPNProjections(byName map[string]float64) *LineupService {
+	projected := *s
+	projected.projections = NewESPNProjections(s.db, byName, s.projections)
+	return &projected
+}
+
+// List returns a user's lineups, most recent week first
+func (s *LineupService) List(ctx context.Context, userID bson.ObjectID) ([]models.FantasyLineup, error) {
+	lineups, err := s.lineups.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lineups: %w", err)
+	}
+	return lineups, nil
+}
+
+// Create validates a lineup against its league's slots and stores it for the user
+func (s *LineupService) Create(ctx context.Context, userID bson.ObjectID, lineup *models.FantasyLineup) (*models.FantasyLineup, error) {
+	if err := s.validate(ctx, lineup); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	lineup.ID = bson.NewObjectID()
+	lineup.UserID = userID
+	lineup.CreatedAt = now
+	lineup.UpdatedAt = now
+
+	if err := s.lineups.Insert(ctx, lineup); err != nil {
+		return nil, fmt.Errorf("failed to create lineup: %w", err)
+	}
+	return lineup, nil
+}
+
+// Get returns one of the user's lineups
+func (s *LineupService) Get(ctx context.Context, userID, lineupID bson.ObjectID) (*models.FantasyLineup, error) {
+	lineup, err := s.lineups.Find(ctx, lineupID)
+	if errors.Is(err, ErrLineupNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lineup: %w", err)
+	}
+	if lineup.UserID != userID {
+		return nil, ErrLineupForbidden
+	}
+	return lineup, nil
+}
+
+// Update replaces the name, week and slots of one of the user's lineups. Changes without
+// league slots keep the lineup's league.
+func (s *LineupService) Update(ctx context.Context, userID, lineupID bson.ObjectID, changes *models.FantasyLineup) (*models.FantasyLineup, error) {
+	lineup, err := s.Get(ctx, userID, lineupID)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes.LineupSlots) == 0 {
+		changes.LineupSlots = lineup.LineupSlots
+	}
+	if err := s.validate(ctx, changes); err != nil {
+		return nil, err
+	}
+
+	lineup.Name = changes.Name
+	lineup.Week = changes.Week
+	lineup.Season = changes.Season
+	lineup.Slots = changes.Slots
+	lineup.LineupSlots = changes.LineupSlots
+	lineup.UpdatedAt = time.Now()
+
+	set := bson.M{
+		"name":         lineup.Name,
+		"week":         lineup.Week,
+		"season":       lineup.Season,
+		"slots":        lineup.Slots,
+		"lineup_slots": lineup.LineupSlots,
+		"updated_at":   lineup.UpdatedAt,
+	}
+	if err := s.lineups.Update(ctx, lineupID, userID, set); err != nil {
+		return nil, fmt.Errorf("failed to update lineup: %w", err)
+	}
+	return lineup, nil
+}
+
+// Delete removes one of the user's lineups
+func (s *LineupService) Delete(ctx context.Context, userID, lineupID bson.ObjectID) error {
+	if _, err := s.Get(ctx, userID, lineupID); err != nil {
+		return err
+	}
+	if err := s.lineups.Delete(ctx, lineupID, userID); err != nil {
+		return fmt.Errorf("failed to delete lineup: %w", err)
+	}
+	return nil
+}
+
+// validate checks the lineup's slots against its league's slots (a standard league when
+// it has none), using each player's most recent rostered position
+func (s *LineupService) validate(ctx context.Context, lineup *models.FantasyLineup) error {
+	if lineup.Week < 1 || lineup.Week > 22 {
+		return fmt.Errorf("%w: week must be between 1 and 22", ErrInvalidLineup)
+	}
+
+	ids := make([]string, 0, len(lineup.Slots))
+	for _, slot := range lineup.Slots {
+		if slot.Slot != models.SlotDST {
+			ids = append(ids, slot.PlayerID)
+		}
+	}
+
+	positions, err := s.playerPositions(ctx, ids)
+	if err != nil {
+		return err
+	}
+	slotCounts := lineup.LineupSlots
+	if len(slotCounts) == 0 {
+		slotCounts = models.DefaultLineupSlots
+	}
+	return validateLineupSlots(lineup.Slots, positions, slotCounts)
+}
+
+// playerPositions maps each player ID to its position in the latest season it was rostered
+func (s *LineupService) playerPositions(ctx context.Context, ids []string) (map[string]string, error) {
+	positions, err := s.lineups.PlayerPositions(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+	return positions, nil
+}
+
+// validateLineupSlots rejects unknown slots, over-filled slots, duplicate players and
+// players whose position can't fill their slot. DST slots take a team abbreviation.
+func validateLineupSlots(slots []models.LineupSlot, positions map[string]string, slotCounts map[string]int) error {
+	if len(slots) == 0 {
+		return fmt.Errorf("%w: lineup has no players", ErrInvalidLineup)
+	}
+
+	filled := make(map[string]int, len(slotCounts))
+	seen := make(map[string]bool, len(slots))
+	for _, slot := range slots {
+		limit, ok := slotCounts[slot.Slot]
+		if !ok {
+			return fmt.Errorf("%w: unknown slot %q", ErrInvalidLineup, slot.Slot)
+		}
+		if slot.PlayerID == "" {
+			return fmt.Errorf("%w: %s slot has no player", ErrInvalidLineup, slot.Slot)
+		}
+		if seen[slot.PlayerID] {
+			return fmt.Errorf("%w: player %s is in more than one slot", ErrInvalidLineup, slot.PlayerID)
+		}
+		seen[slot.PlayerID] = true
+
+		filled[slot.Slot]++
+		if filled[slot.Slot] > limit {
+			return fmt.Errorf("%w: too many players in %s (max %d)", ErrInvalidLineup, slot.Slot, limit)
+		}
+
+		if slot.Slot == models.SlotDST {
+			continue
+		}
+		position, ok := positions[slot.PlayerID]
+		if !ok {
+			return fmt.Errorf("%w: unknown player %s", ErrInvalidLineup, slot.PlayerID)
+		}
+		if !slotAccepts(slot.Slot, position) {
+			return fmt.Errorf("%w: %s %s can't fill the %s slot", ErrInvalidLineup, position, slot.PlayerID, slot.Slot)
+		}
+	}
+	return nil
+}
+
+// slotAccepts reports whether a player at position may fill slot
+func slotAccepts(slot, position string) bool {
+	for _, eligible := range models.SlotEligibility[slot] {
+		if eligible == position {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// memLineupStore is a lineupStore over in-memory lineups and player positions
+type memLineupStore struct {
+	lineups   map[bson.ObjectID]models.FantasyLineup
+	positions map[string]string
+}
+
+func newMemLineupStore(positions map[string]string) *memLineupStore {
+	return &memLineupStore{lineups: make(map[bson.ObjectID]models.FantasyLineup), positions: positions}
+}
+
+func (s *memLineupStore) List(ctx context.Context, userID bson.ObjectID) ([]models.FantasyLineup, error) {
+	lineups := []models.FantasyLineup{}
+	for _, l := range s.lineups {
+		if l.UserID == userID {
+			lineups = append(lineups, l)
+		}
+	}
+	return lineups, nil
+}
+
+func (s *memLineupStore) Insert(ctx context.Context, lineup *models.FantasyLineup) error {
+	s.lineups[lineup.ID] = *lineup
+	return nil
+}
+
+func (s *memLineupStore) Find(ctx context.Context, lineupID bson.ObjectID) (*models.FantasyLineup, error) {
+	l, ok := s.lineups[lineupID]
+	if !ok {
+		return nil, ErrLineupNotFound
+	}
+	return &l, nil
+}
+
+func (s *memLineupStore) Update(ctx context.Context, lineupID, userID bson.ObjectID, set bson.M) error {
+	l, ok := s.lineups[lineupID]
+	if !ok || l.UserID != userID {
+		return nil
+	}
+	l.Slots = set["slots"].([]models.LineupSlot)
+	l.LineupSlots = set["lineup_slots"].(map[string]int)
+	s.lineups[lineupID] = l
+	return nil
+}
+
+func (s *memLineupStore) Delete(ctx context.Context, lineupID, userID bson.ObjectID) error {
+	if l, ok := s.lineups[lineupID]; ok && l.UserID == userID {
+		delete(s.lineups, lineupID)
+	}
+	return nil
+}
+
+func (s *memLineupStore) PlayerPositions(ctx context.Context, ids []string) (map[string]string, error) {
+	positions := make(map[string]string, len(ids))
+	for _, id := range ids {
+		if pos, ok := s.positions[id]; ok {
+			positions[id] = pos
+		}
+	}
+	return positions, nil
+}
+
+var lineupPositions = map[string]string{
+	"qb1": "QB", "qb2": "QB",
+	"rb1": "RB", "rb2": "RB", "rb3": "RB",
+	"wr1": "WR", "wr2": "WR", "wr3": "WR",
+	"te1": "TE", "k1": "K",
+}
+
+// standardSlots fills every slot of a standard league
+func standardSlots() []models.LineupSlot {
+	return []models.LineupSlot{
+		{Slot: models.SlotQB, PlayerID: "qb1"},
+		{Slot: models.SlotRB, PlayerID: "rb1"},
+		{Slot: models.SlotRB, PlayerID: "rb2"},
+		{Slot: models.SlotWR, PlayerID: "wr1"},
+		{Slot: models.SlotWR, PlayerID: "wr2"},
+		{Slot: models.SlotWR, PlayerID: "wr3"},
+		{Slot: models.SlotTE, PlayerID: "te1"},
+		{Slot: models.SlotFlex, PlayerID: "rb3"},
+		{Slot: models.SlotK, PlayerID: "k1"},
+		{Slot: models.SlotDST, PlayerID: "KC"},
+	}
+}
+
+func TestLineupCreateValidatesAgainstLeague(t *testing.T) {
+	superflex := append(standardSlots(), models.LineupSlot{Slot: models.SlotSuperflex, PlayerID: "qb2"})
+	overfilled := append(standardSlots(), models.LineupSlot{Slot: models.SlotRB, PlayerID: "qb2"})
+
+	tests := []struct {
+		name        string
+		slots       []models.LineupSlot
+		lineupSlots map[string]int
+		wantErr     bool
+	}{
+		{"valid standard lineup", standardSlots(), nil, false},
+		{"superflex lineup in a superflex league", superflex, models.SuperflexLeagueSettings().LineupSlots, false},
+		{"superflex lineup in a standard league", superflex, nil, true},
+		{"over-filled RB slot", overfilled, nil, true},
+		{"QB in the flex", append(standardSlots()[:7], models.LineupSlot{Slot: models.SlotFlex, PlayerID: "qb2"}), nil, true},
+		{"unknown player", append(standardSlots()[:7], models.LineupSlot{Slot: models.SlotFlex, PlayerID: "nobody"}), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemLineupStore(lineupPositions)
+			s := &LineupService{lineups: store}
+			userID := bson.NewObjectID()
+
+			lineup := &models.FantasyLineup{Name: "Week 9", Week: 9, Season: 2025, Slots: tt.slots, LineupSlots: tt.lineupSlots}
+			got, err := s.Create(context.Background(), userID, lineup)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidLineup) {
+					t.Errorf("Create() error = %v, want ErrInvalidLineup", err)
+				}
+				if len(store.lineups) != 0 {
+					t.Errorf("stored %d lineups after a rejected create", len(store.lineups))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if got.UserID != userID || got.ID.IsZero() || got.CreatedAt.IsZero() {
+				t.Errorf("created lineup = %+v, want an ID, owner and timestamps", got)
+			}
+			if _, ok := store.lineups[got.ID]; !ok {
+				t.Error("created lineup was not stored")
+			}
+		})
+	}
+}
+
+func TestLineupUpdateKeepsLeague(t *testing.T) {
+	store := newMemLineupStore(lineupPositions)
+	s := &LineupService{lineups: store}
+	ctx := context.Background()
+	userID := bson.NewObjectID()
+
+	superflex := append(standardSlots(), models.LineupSlot{Slot: models.SlotSuperflex, PlayerID: "qb2"})
+	created, err := s.Create(ctx, userID, &models.FantasyLineup{Week: 9, Slots: superflex, LineupSlots: models.SuperflexLeagueSettings().LineupSlots})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Changes without league slots still validate against the stored superflex league
+	changes := &models.FantasyLineup{Week: 10, Slots: []models.LineupSlot{
+		{Slot: models.SlotQB, PlayerID: "qb2"},
+		{Slot: models.SlotSuperflex, PlayerID: "qb1"},
+		{Slot: models.SlotRB, PlayerID: "rb1"},
+	}}
+	updated, err := s.Update(ctx, userID, created.ID, changes)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.LineupSlots[models.SlotSuperflex] != 1 || store.lineups[created.ID].LineupSlots[models.SlotSuperflex] != 1 {
+		t.Errorf("league slots after update = %v, want the superflex league kept", updated.LineupSlots)
+	}
+
+	// Switching the lineup to a standard league rejects its superflex slot
+	changes.LineupSlots = models.DefaultLeagueSettings().LineupSlots
+	if _, err := s.Update(ctx, userID, created.ID, changes); !errors.Is(err, ErrInvalidLineup) {
+		t.Errorf("Update() to a standard league error = %v, want ErrInvalidLineup", err)
+	}
+}
+
+func TestLineupDeleteByNonOwner(t *testing.T) {
+	store := newMemLineupStore(lineupPositions)
+	s := &LineupService{lineups: store}
+	ctx := context.Background()
+	owner, other := bson.NewObjectID(), bson.NewObjectID()
+
+	created, err := s.Create(ctx, owner, &models.FantasyLineup{Week: 9, Slots: standardSlots()})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := s.Delete(ctx, other, created.ID); !errors.Is(err, ErrLineupForbidden) {
+		t.Errorf("Delete() by another user error = %v, want ErrLineupForbidden", err)
+	}
+	if _, ok := store.lineups[created.ID]; !ok {
+		t.Fatal("lineup was deleted by another user")
+	}
+
+	if err := s.Delete(ctx, owner, bson.NewObjectID()); !errors.Is(err, ErrLineupNotFound) {
+		t.Errorf("Delete() of an unknown lineup error = %v, want ErrLineupNotFound", err)
+	}
+
+	if err := s.Delete(ctx, owner, created.ID); err != nil {
+		t.Fatalf("Delete() by the owner error = %v", err)
+	}
+	if _, ok := store.lineups[created.ID]; ok {
+		t.Error("lineup still stored after the owner deleted it")
+	}
+}
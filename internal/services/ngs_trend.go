@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// NGSTrendPoint is a metric's value for one week
+type NGSTrendPoint struct {
+	Week  int     `json:"week" bson:"week"`
+	Value float64 `json:"value" bson:"value"`
+}
+
+// NGSTrend is one NGS metric as a week-by-week series with the season average
+type NGSTrend struct {
+	NFLID         string          `json:"nfl_id"`
+	StatType      string          `json:"stat_type"`
+	Metric        string          `json:"metric"`
+	Season        int             `json:"season"`
+	SeasonAverage float64         `json:"season_average"`
+	Weeks         []NGSTrendPoint `json:"weeks"`
+}
+
+// GetPlayerNGSTrend returns a single NGS metric for each week a player has a row in a season,
+// for charting. The metric must be one of NGSMetrics for the stat type. The season average is
+// the NGS season summary row (week 0) when loaded, since rate stats like avg_separation
+// weight weeks by volume, and otherwise the mean of the weekly values.
+func (s *DataService) GetPlayerNGSTrend(ctx context.Context, nflID, statType, metric string, season int) (*NGSTrend, error) {
+	metrics, ok := NGSMetrics[statType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown stat_type %q", ErrInvalidNGSQuery, statType)
+	}
+	if !slices.Contains(metrics, metric) {
+		return nil, fmt.Errorf("%w: unknown %s metric %q", ErrInvalidNGSQuery, statType, metric)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"player_id": nflID,
+			"stat_type": statType,
+			"season":    season,
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":   0,
+			"week":  1,
+			"value": bson.M{"$toDouble": bson.M{"$ifNull": bson.A{"$" + metric, 0}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "week", Value: 1}}}},
+	}
+
+	cursor, err := s.db.Collection("next_gen_stats").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch NGS trend: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var points []NGSTrendPoint
+	if err := cursor.All(ctx, &points); err != nil {
+		return nil, fmt.Errorf("failed to decode NGS trend: %w", err)
+	}
+
+	trend := &NGSTrend{NFLID: nflID, StatType: statType, Metric: metric, Season: season}
+	trend.Weeks, trend.SeasonAverage = splitNGSTrend(points)
+	return trend, nil
+}
+
+// splitNGSTrend separates the week 0 season row from the weekly points and returns the
+// weekly points with the season average
+func splitNGSTrend(points []NGSTrendPoint) ([]NGSTrendPoint, float64) {
+	weeks := make([]NGSTrendPoint, 0, len(points))
+	var seasonRow *NGSTrendPoint
+	total := 0.0
+	for i, p := range points {
+		if p.Week == 0 {
+			seasonRow = &points[i]
+			continue
+		}
+		weeks = append(weeks, p)
+		total += p.Value
+	}
+
+	if seasonRow != nil {
+		return weeks, seasonRow.Value
+	}
+	if len(weeks) == 0 {
+		return weeks, 0
+	}
+	return weeks, total / float64(len(weeks))
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSplitNGSTrend(t *testing.T) {
+	tests := []struct {
+		name        string
+		points      []NGSTrendPoint
+		wantWeeks   []int
+		wantAverage float64
+	}{
+		{"no rows", nil, []int{}, 0},
+		{
+			"season row wins over the weekly mean",
+			[]NGSTrendPoint{{Week: 0, Value: 3.1}, {Week: 1, Value: 2}, {Week: 2, Value: 5}},
+			[]int{1, 2}, 3.1,
+		},
+		{
+			"mean of weeks without a season row",
+			[]NGSTrendPoint{{Week: 1, Value: 2}, {Week: 2, Value: 5}, {Week: 4, Value: 2}},
+			[]int{1, 2, 4}, 3,
+		},
+		{
+			"only a season row",
+			[]NGSTrendPoint{{Week: 0, Value: 2.8}},
+			[]int{}, 2.8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weeks, avg := splitNGSTrend(tt.points)
+
+			if avg != tt.wantAverage {
+				t.Errorf("average = %v, want %v", avg, tt.wantAverage)
+			}
+			if weeks == nil {
+				t.Fatal("weeks = nil, want an empty list")
+			}
+			if len(weeks) != len(tt.wantWeeks) {
+				t.Fatalf("weeks = %+v, want weeks %v", weeks, tt.wantWeeks)
+			}
+			for i, w := range weeks {
+				if w.Week != tt.wantWeeks[i] {
+					t.Errorf("weeks[%d].Week = %d, want %d", i, w.Week, tt.wantWeeks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetPlayerNGSTrendRejectsUnknownMetric(t *testing.T) {
+	s := &DataService{}
+	tests := []struct{ statType, metric string }{
+		{"kicking", "avg_separation"},
+		{"passing", "avg_separation"},
+		{"receiving", ""},
+	}
+	for _, tt := range tests {
+		_, err := s.GetPlayerNGSTrend(context.Background(), "00-0033873", tt.statType, tt.metric, 2024)
+		if !errors.Is(err, ErrInvalidNGSQuery) {
+			t.Errorf("GetPlayerNGSTrend(%q, %q) error = %v, want ErrInvalidNGSQuery", tt.statType, tt.metric, err)
+		}
+	}
+}
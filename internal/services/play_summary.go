@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// PlayerPlaySummary is a player's play-by-play totals as passer, rusher and receiver
+type PlayerPlaySummary struct {
+	Plays int `json:"plays"`
+
+	PassAttempts  int `json:"pass_attempts"` // Dropbacks that weren't sacks
+	Completions   int `json:"completions"`
+	PassingYards  int `json:"passing_yards"`
+	PassingTDs    int `json:"passing_tds"`
+	Interceptions int `json:"interceptions"`
+
+	RushAttempts int `json:"rush_attempts"`
+	RushingYards int `json:"rushing_yards"`
+	RushingTDs   int `json:"rushing_tds"`
+
+	Targets        int `json:"targets"`
+	Receptions     int `json:"receptions"`
+	ReceivingYards int `json:"receiving_yards"`
+	ReceivingTDs   int `json:"receiving_tds"`
+
+	TotalYards      int     `json:"total_yards"`
+	TotalTouchdowns int     `json:"total_touchdowns"`
+	AvgEPA          float64 `json:"avg_epa"` // Per play the player was involved in
+}
+
+// GetPlayerPlaySummary aggregates the plays a player was involved in (season 0 means every
+// season) into pass, rush and receiving totals in a single $group, rather than returning the
+// plays themselves. Touchdowns on plays that ended in an interception are the defense's and
+// aren't credited. Completions and receptions rely on complete_pass, which is only present on
+// plays loaded since it was added to the play-by-play import.
+func (s *DataService) GetPlayerPlaySummary(ctx context.Context, nflID string, season int) (*PlayerPlaySummary, error) {
+	match := bson.M{
+		"$or": []bson.M{
+			{"passer_player_id": nflID},
+			{"rusher_player_id": nflID},
+			{"receiver_player_id": nflID},
+		},
+	}
+	if season > 0 {
+		match["season"] = season
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: playSummaryGroup(nflID)}},
+	}
+
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate player plays: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []playSummaryTotals
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode player play summary: %w", err)
+	}
+
+	if len(results) == 0 {
+		return &PlayerPlaySummary{}, nil
+	}
+	return results[0].summary(), nil
+}
+
+// playSummaryGroup is the $group stage totalling the matched plays from nflID's side
+func playSummaryGroup(nflID string) bson.M {
+	countIf := func(conds ...bson.M) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$and": conds}, 1, 0}}}
+	}
+	yardsIf := func(conds ...bson.M) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$and": conds}, "$yards", 0}}}
+	}
+	isPasser := bson.M{"$eq": bson.A{"$passer_player_id", nflID}}
+	isRusher := bson.M{"$eq": bson.A{"$rusher_player_id", nflID}}
+	isReceiver := bson.M{"$eq": bson.A{"$receiver_player_id", nflID}}
+	notSack := bson.M{"$ne": bson.A{"$sack", true}}
+	isComplete := bson.M{"$eq": bson.A{"$complete_pass", true}}
+	isInterception := bson.M{"$eq": bson.A{"$interception", true}}
+	isOffensiveTD := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$touchdown", true}},
+		bson.M{"$ne": bson.A{"$interception", true}},
+	}}
+
+	return bson.M{
+		"_id":             nil,
+		"plays":           bson.M{"$sum": 1},
+		"pass_attempts":   countIf(isPasser, notSack),
+		"completions":     countIf(isPasser, isComplete),
+		"passing_yards":   yardsIf(isPasser, notSack),
+		"passing_tds":     countIf(isPasser, isOffensiveTD),
+		"interceptions":   countIf(isPasser, isInterception),
+		"rush_attempts":   countIf(isRusher),
+		"rushing_yards":   yardsIf(isRusher),
+		"rushing_tds":     countIf(isRusher, isOffensiveTD),
+		"targets":         countIf(isReceiver),
+		"receptions":      countIf(isReceiver, isComplete),
+		"receiving_yards": yardsIf(isReceiver),
+		"receiving_tds":   countIf(isReceiver, isOffensiveTD),
+		"touchdowns":      countIf(isOffensiveTD),
+		"avg_epa":         bson.M{"$avg": "$epa"},
+	}
+}
+
+// playSummaryTotals is the document playSummaryGroup produces
+type playSummaryTotals struct {
+	Plays          int     `bson:"plays"`
+	PassAttempts   int     `bson:"pass_attempts"`
+	Completions    int     `bson:"completions"`
+	PassingYards   int     `bson:"passing_yards"`
+	PassingTDs     int     `bson:"passing_tds"`
+	Interceptions  int     `bson:"interceptions"`
+	RushAttempts   int     `bson:"rush_attempts"`
+	RushingYards   int     `bson:"rushing_yards"`
+	RushingTDs     int     `bson:"rushing_tds"`
+	Targets        int     `bson:"targets"`
+	Receptions     int     `bson:"receptions"`
+	ReceivingYards int     `bson:"receiving_yards"`
+	ReceivingTDs   int     `bson:"receiving_tds"`
+	Touchdowns     int     `bson:"touchdowns"`
+	AvgEPA         float64 `bson:"avg_epa"`
+}
+
+// summary converts the group totals into the response, combining yards across roles
+func (r playSummaryTotals) summary() *PlayerPlaySummary {
+	summary := &PlayerPlaySummary{}
+	summary.Plays = r.Plays
+	summary.PassAttempts = r.PassAttempts
+	summary.Completions = r.Completions
+	summary.PassingYards = r.PassingYards
+	summary.PassingTDs = r.PassingTDs
+	summary.Interceptions = r.Interceptions
+	summary.RushAttempts = r.RushAttempts
+	summary.RushingYards = r.RushingYards
+	summary.RushingTDs = r.RushingTDs
+	summary.Targets = r.Targets
+	summary.Receptions = r.Receptions
+	summary.ReceivingYards = r.ReceivingYards
+	summary.ReceivingTDs = r.ReceivingTDs
+	summary.TotalYards = r.PassingYards + r.RushingYards + r.ReceivingYards
+	summary.TotalTouchdowns = r.Touchdowns
+	summary.AvgEPA = r.AvgEPA
+	return summary
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// evalGroup runs a single-group $group stage over docs, supporting the operators
+// playSummaryGroup uses, so the stage can be checked without a database
+func evalGroup(t *testing.T, group bson.M, docs []bson.M) bson.M {
+	t.Helper()
+
+	out := bson.M{"_id": nil}
+	for field, spec := range group {
+		if field == "_id" {
+			continue
+		}
+		acc := spec.(bson.M)
+		switch {
+		case acc["$sum"] != nil:
+			var total int64
+			for _, doc := range docs {
+				total += toInt64(t, evalExpr(t, acc["$sum"], doc))
+			}
+			out[field] = total
+		case acc["$avg"] != nil:
+			var total float64
+			for _, doc := range docs {
+				total += evalExpr(t, acc["$avg"], doc).(float64)
+			}
+			if len(docs) > 0 {
+				out[field] = total / float64(len(docs))
+			}
+		default:
+			t.Fatalf("unsupported accumulator for %s: %v", field, acc)
+		}
+	}
+	return out
+}
+
+func evalExpr(t *testing.T, expr interface{}, doc bson.M) interface{} {
+	t.Helper()
+
+	switch e := expr.(type) {
+	case string:
+		if len(e) > 0 && e[0] == '$' {
+			return doc[e[1:]]
+		}
+		return e
+	case bson.M:
+		for op, arg := range e {
+			switch op {
+			case "$cond":
+				args := arg.(bson.A)
+				if evalExpr(t, args[0], doc).(bool) {
+					return evalExpr(t, args[1], doc)
+				}
+				return evalExpr(t, args[2], doc)
+			case "$and":
+				for _, cond := range exprList(t, arg) {
+					if !evalExpr(t, cond, doc).(bool) {
+						return false
+					}
+				}
+				return true
+			case "$eq", "$ne":
+				args := arg.(bson.A)
+				equal := evalExpr(t, args[0], doc) == evalExpr(t, args[1], doc)
+				return equal == (op == "$eq")
+			default:
+				t.Fatalf("unsupported operator %s", op)
+			}
+		}
+	}
+	return expr
+}
+
+func exprList(t *testing.T, arg interface{}) []interface{} {
+	switch a := arg.(type) {
+	case bson.A:
+		return a
+	case []bson.M:
+		list := make([]interface{}, len(a))
+		for i, m := range a {
+			list[i] = m
+		}
+		return list
+	}
+	t.Fatalf("unsupported $and argument %T", arg)
+	return nil
+}
+
+func toInt64(t *testing.T, v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	}
+	t.Fatalf("unsupported number %T", v)
+	return 0
+}
+
+// playDocs stores plays the way the plays collection holds them
+func playDocs(t *testing.T, plays []models.Play) []bson.M {
+	docs := make([]bson.M, len(plays))
+	for i, p := range plays {
+		raw, err := bson.Marshal(p)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if err := bson.Unmarshal(raw, &docs[i]); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+	}
+	return docs
+}
+
+func TestPlaySummaryGroupMatchesManualTotals(t *testing.T) {
+	const qb, rb = "00-0033873", "00-0036389"
+
+	// The quarterback's plays: completions, an incompletion, a sack, a pick-six, a passing
+	// TD, a scramble and a catch on a trick play
+	plays := []models.Play{
+		{PasserPlayerID: qb, ReceiverPlayerID: "wr1", Yards: 12, CompletePass: true, EPA: 0.8},
+		{PasserPlayerID: qb, ReceiverPlayerID: "wr2", Yards: 0, EPA: -0.6},
+		{PasserPlayerID: qb, Yards: -7, Sack: true, EPA: -1.9},
+		{PasserPlayerID: qb, ReceiverPlayerID: "wr1", Yards: 0, Interception: true, Touchdown: true, EPA: -5.2},
+		{PasserPlayerID: qb, ReceiverPlayerID: "te1", Yards: 25, CompletePass: true, Touchdown: true, EPA: 3.4},
+		{RusherPlayerID: qb, Yards: 9, EPA: 0.7},
+		{PasserPlayerID: "wr1", ReceiverPlayerID: qb, Yards: 18, CompletePass: true, EPA: 2.1},
+	}
+
+	var want PlayerPlaySummary
+	var epa float64
+	for _, p := range plays {
+		want.Plays++
+		epa += p.EPA
+		offensiveTD := p.Touchdown && !p.Interception
+		if p.PasserPlayerID == qb {
+			if !p.Sack {
+				want.PassAttempts++
+				want.PassingYards += p.Yards
+			}
+			if p.CompletePass {
+				want.Completions++
+			}
+			if offensiveTD {
+				want.PassingTDs++
+			}
+			if p.Interception {
+				want.Interceptions++
+			}
+		}
+		if p.RusherPlayerID == qb {
+			want.RushAttempts++
+			want.RushingYards += p.Yards
+			if offensiveTD {
+				want.RushingTDs++
+			}
+		}
+		if p.ReceiverPlayerID == qb {
+			want.Targets++
+			want.ReceivingYards += p.Yards
+			if p.CompletePass {
+				want.Receptions++
+			}
+			if offensiveTD {
+				want.ReceivingTDs++
+			}
+		}
+		if offensiveTD {
+			want.TotalTouchdowns++
+		}
+	}
+	want.TotalYards = want.PassingYards + want.RushingYards + want.ReceivingYards
+	want.AvgEPA = epa / float64(len(plays))
+
+	got := summarizeWithGroup(t, qb, plays)
+	if math.Abs(got.AvgEPA-want.AvgEPA) > 1e-9 {
+		t.Errorf("AvgEPA = %v, want %v", got.AvgEPA, want.AvgEPA)
+	}
+	got.AvgEPA = want.AvgEPA
+	if *got != want {
+		t.Errorf("summary = %+v\nwant      %+v", *got, want)
+	}
+
+	// Spot-check the rules the manual totals encode
+	if want.PassAttempts != 4 || want.PassingYards != 37 || want.PassingTDs != 1 || want.TotalTouchdowns != 1 {
+		t.Errorf("fixture totals drifted: %+v", want)
+	}
+
+	// A player on none of the plays' roles only counts the plays themselves
+	other := summarizeWithGroup(t, rb, plays)
+	if other.PassAttempts != 0 || other.RushAttempts != 0 || other.Targets != 0 || other.TotalYards != 0 {
+		t.Errorf("uninvolved player summary = %+v, want no role totals", *other)
+	}
+}
+
+func summarizeWithGroup(t *testing.T, nflID string, plays []models.Play) *PlayerPlaySummary {
+	t.Helper()
+
+	result := evalGroup(t, playSummaryGroup(nflID), playDocs(t, plays))
+	raw, err := bson.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var totals playSummaryTotals
+	if err := bson.Unmarshal(raw, &totals); err != nil {
+		t.Fatalf("Unmarshal(%v) error = %v", result, err)
+	}
+	return totals.summary()
+}
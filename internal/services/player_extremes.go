@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+// PlayerExtremes frames a player's season by its ceiling and floor: the highest and lowest
+// PPR games with their full stat line and opponent, and the median game between them
+type PlayerExtremes struct {
+	NFLID     string             `json:"nfl_id"`
+	Season    int                `json:"season"`
+	Games     int                `json:"games"`
+	Best      *models.WeeklyStat `json:"best"`  // nil when the player has no games
+	Worst     *models.WeeklyStat `json:"worst"` // Same game as Best when only one was played
+	MedianPPR float64            `json:"median_ppr"`
+}
+
+// GetPlayerExtremes finds a player's best and worst PPR games of a season from
+// player_weekly_stats, plus the median game
+func (s *DataService) GetPlayerExtremes(ctx context.Context, nflID string, season int) (*PlayerExtremes, error) {
+	weeks, err := s.GetPlayerWeeklyStatsRange(ctx, nflID, season, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	extremes := playerExtremes(weeks)
+	extremes.NFLID = nflID
+	extremes.Season = season
+	return extremes, nil
+}
+
+// playerExtremes picks the highest and lowest PPR games (the earliest week wins a tie) and
+// the median, averaging the middle two games when the count is even
+func playerExtremes(weeks []models.WeeklyStat) *PlayerExtremes {
+	extremes := &PlayerExtremes{Games: len(weeks)}
+	if len(weeks) == 0 {
+		return extremes
+	}
+
+	best, worst := weeks[0], weeks[0]
+	points := make([]float64, 0, len(weeks))
+	for _, w := range weeks {
+		if w.FantasyPointsPPR > best.FantasyPointsPPR || (w.FantasyPointsPPR == best.FantasyPointsPPR && w.Week < best.Week) {
+			best = w
+		}
+		if w.FantasyPointsPPR < worst.FantasyPointsPPR || (w.FantasyPointsPPR == worst.FantasyPointsPPR && w.Week < worst.Week) {
+			worst = w
+		}
+		points = append(points, w.FantasyPointsPPR)
+	}
+	extremes.Best = &best
+	extremes.Worst = &worst
+
+	sort.Float64s(points)
+	mid := len(points) / 2
+	if len(points)%2 == 1 {
+		extremes.MedianPPR = points[mid]
+	} else {
+		extremes.MedianPPR = (points[mid-1] + points[mid]) / 2
+	}
+
+	return extremes
+}
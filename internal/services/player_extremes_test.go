@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestPlayerExtremes(t *testing.T) {
+	game := func(week int, opponent string, ppr float64) models.WeeklyStat {
+		return models.WeeklyStat{Week: week, Opponent: opponent, FantasyPointsPPR: ppr}
+	}
+
+	tests := []struct {
+		name       string
+		weeks      []models.WeeklyStat
+		wantBest   int // Week of the best game, 0 for none
+		wantWorst  int
+		wantMedian float64
+	}{
+		{"no games", nil, 0, 0, 0},
+		{"single game is best and worst", []models.WeeklyStat{game(3, "BUF", 14.2)}, 3, 3, 14.2},
+		{
+			"odd count",
+			[]models.WeeklyStat{game(1, "LV", 12), game(2, "DEN", 31.5), game(3, "LAC", 4.1)},
+			2, 3, 12,
+		},
+		{
+			"even count averages the middle games",
+			[]models.WeeklyStat{game(1, "LV", 10), game(2, "DEN", 20), game(4, "LAC", 6), game(5, "BUF", 16)},
+			2, 4, 13,
+		},
+		{
+			"ties go to the earliest week",
+			[]models.WeeklyStat{game(6, "NYJ", 18), game(2, "MIA", 5), game(4, "NE", 18), game(8, "BUF", 5)},
+			4, 2, 11.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := playerExtremes(tt.weeks)
+
+			if got.Games != len(tt.weeks) {
+				t.Errorf("Games = %d, want %d", got.Games, len(tt.weeks))
+			}
+			if got.MedianPPR != tt.wantMedian {
+				t.Errorf("MedianPPR = %v, want %v", got.MedianPPR, tt.wantMedian)
+			}
+
+			if tt.wantBest == 0 {
+				if got.Best != nil || got.Worst != nil {
+					t.Errorf("Best/Worst = %v/%v, want nil", got.Best, got.Worst)
+				}
+				return
+			}
+			if got.Best == nil || got.Best.Week != tt.wantBest {
+				t.Errorf("Best = %+v, want week %d", got.Best, tt.wantBest)
+			}
+			if got.Worst == nil || got.Worst.Week != tt.wantWorst {
+				t.Errorf("Worst = %+v, want week %d", got.Worst, tt.wantWorst)
+			}
+		})
+	}
+}
+
+func TestPlayerExtremesKeepsStatLine(t *testing.T) {
+	weeks := []models.WeeklyStat{
+		{Week: 1, Opponent: "LV", FantasyPointsPPR: 8, Receptions: 3},
+		{Week: 2, Opponent: "DEN", FantasyPointsPPR: 27.4, Receptions: 9, ReceivingYards: 132, ReceivingTDs: 1},
+	}
+
+	got := playerExtremes(weeks)
+	if got.Best.Opponent != "DEN" || got.Best.Receptions != 9 || got.Best.ReceivingYards != 132 || got.Best.ReceivingTDs != 1 {
+		t.Errorf("Best = %+v, want the full week 2 line against DEN", got.Best)
+	}
+
+	// The result must not alias the caller's slice
+	weeks[1].Opponent = "changed"
+	if got.Best.Opponent != "DEN" {
+		t.Errorf("Best.Opponent = %q after mutating the input, want DEN", got.Best.Opponent)
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// PlayerStatSortFields are the player_stats fields the players list can sort by. Sorting
+// by a stat joins each player's stats for the requested season type for their latest
+// season before paginating.
+var PlayerStatSortFields = map[string]bool{
+	"fantasy_points":     true,
+	"fantasy_points_ppr": true,
+	"passing_yards":      true,
+	"passing_tds":        true,
+	"rushing_yards":      true,
+	"rushing_tds":        true,
+	"receiving_yards":    true,
+	"receiving_tds":      true,
+	"receptions":         true,
+	"targets":            true,
+	"tackles":            true,
+	"sacks":              true,
+	"epa":                true,
+}
+
+// PlayerSortFields are the indexed player fields the players list can sort by
+var PlayerSortFields = map[string]bool{
+	"name":     true,
+	"season":   true,
+	"team":     true,
+	"position": true,
+}
+
+// ValidPlayerSort reports whether the players list can sort by field, either a player
+// field or a whitelisted stat
+func ValidPlayerSort(field string) bool {
+	return PlayerSortFields[field] || PlayerStatSortFields[field]
+}
+
+// PlayerListQuery filters, sorts and pages the players list, which has one entry per
+// player showing their most recent season
+type PlayerListQuery struct {
+	Filter     bson.M // Player fields to match, e.g. team, position, name
+	SeasonType string // Stats joined for a stat sort: REG, POST or REGPOST
+	Sort       string
+	Descending bool
+	Skip       int
+	Limit      int
+}
+
+// PlayerListPipeline returns one page of unique players matching q
+func PlayerListPipeline(q PlayerListQuery) mongo.Pipeline {
+	sortOrder := 1
+	if q.Descending {
+		sortOrder = -1
+	}
+
+	// Aggregation pipeline to get unique players with their most recent season
+	pipeline := mongo.Pipeline{
+		// Match filters (uses indexes!)
+		{{Key: "$match", Value: q.Filter}},
+		// Sort by season descending to get most recent first
+		{{Key: "$sort", Value: bson.D{{Key: "season", Value: -1}}}},
+		// Group by nfl_id and take the first (most recent) document
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$nfl_id"},
+			{Key: "doc", Value: bson.D{{Key: "$first", Value: "$$ROOT"}}},
+		}}},
+		// Replace root with the document
+		{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$doc"}}}},
+	}
+
+	if PlayerStatSortFields[q.Sort] {
+		// Join the player's season stats so the page is cut after sorting by the stat
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from": "player_stats",
+				"let":  bson.M{"nfl_id": "$nfl_id", "season": "$season"},
+				"pipeline": mongo.Pipeline{
+					{{Key: "$match", Value: bson.M{
+						"season_type": q.SeasonType,
+						"$expr": bson.M{"$and": []bson.M{
+							{"$eq": []string{"$nfl_id", "$$nfl_id"}},
+							{"$eq": []string{"$season", "$$season"}},
+						}},
+					}}},
+					{{Key: "$project", Value: bson.M{q.Sort: 1}}},
+				},
+				"as": "sort_stats",
+			}}},
+			bson.D{{Key: "$addFields", Value: bson.M{
+				"sort_value": bson.M{"$ifNull": []interface{}{bson.M{"$first": "$sort_stats." + q.Sort}, 0}},
+			}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "sort_value", Value: sortOrder}, {Key: "name", Value: 1}}}},
+			bson.D{{Key: "$project", Value: bson.M{"sort_stats": 0, "sort_value": 0}}},
+		)
+	} else {
+		// Sort by name (or another indexed player field) - uses name index!
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: q.Sort, Value: sortOrder}}}})
+	}
+
+	// Pagination
+	return append(pipeline,
+		bson.D{{Key: "$skip", Value: q.Skip}},
+		bson.D{{Key: "$limit", Value: q.Limit}},
+	)
+}
+
+// PlayerCountPipeline counts the unique players matching q's filter, so a player listed
+// in several seasons counts once
+func PlayerCountPipeline(q PlayerListQuery) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: q.Filter}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$nfl_id"}}}},
+		{{Key: "$count", Value: "total"}},
+	}
+}
+
+// PlayerStatsFilter matches a player's season stats of one season type (REG, POST or
+// REGPOST). A season of 0 matches every season.
+func PlayerStatsFilter(nflID string, season int, seasonType string) bson.M {
+	filter := bson.M{"nfl_id": nflID, "season_type": seasonType}
+	if season > 0 {
+		filter["season"] = season
+	}
+	return filter
+}
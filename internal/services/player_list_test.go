@@ -0,0 +1,233 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// listPlayers runs a players list query over docs, returning the page and the total
+func listPlayers(t *testing.T, docs []bson.M, stats []bson.M, q PlayerListQuery) ([]models.Player, int64) {
+	t.Helper()
+
+	var page []models.Player
+	decodeDocs(t, runPipeline(t, PlayerListPipeline(q), docs, map[string][]bson.M{"player_stats": stats}), &page)
+
+	var counts []struct {
+		Total int64 `bson:"total"`
+	}
+	decodeDocs(t, runPipeline(t, PlayerCountPipeline(q), docs, nil), &counts)
+	if len(counts) == 0 {
+		return page, 0
+	}
+	return page, counts[0].Total
+}
+
+func playerKeys(players []models.Player) []string {
+	keys := []string{}
+	for _, p := range players {
+		keys = append(keys, p.Name)
+	}
+	return keys
+}
+
+// rosterHistory has one players document per player per season
+func rosterHistory(t *testing.T) []bson.M {
+	player := func(id, name, team, position string, season int) models.Player {
+		return models.Player{NFLID: id, Name: name, Team: team, Position: position, Season: season}
+	}
+	return toDocs(t, []models.Player{
+		player("00-0033873", "Patrick Mahomes", "KC", "QB", 2023),
+		player("00-0033873", "Patrick Mahomes", "KC", "QB", 2024),
+		player("00-0033873", "Patrick Mahomes", "KC", "QB", 2025),
+		player("00-0034857", "Josh Allen", "BUF", "QB", 2024),
+		player("00-0034857", "Josh Allen", "BUF", "QB", 2025),
+		player("00-0036442", "Joe Burrow", "CIN", "QB", 2025),
+		player("00-0030506", "Travis Kelce", "KC", "TE", 2024),
+		player("00-0030506", "Travis Kelce", "KC", "TE", 2025),
+		player("00-0026498", "Matthew Stafford", "DET", "QB", 2020), // moved teams since
+		player("00-0026498", "Matthew Stafford", "LA", "QB", 2025),
+	})
+}
+
+func TestPlayerListTotalCountsUniquePlayers(t *testing.T) {
+	docs := rosterHistory(t)
+
+	tests := []struct {
+		name      string
+		q         PlayerListQuery
+		wantPage  []string
+		wantTotal int64
+	}{
+		{
+			name:      "first page",
+			q:         PlayerListQuery{Filter: bson.M{}, Sort: "name", Limit: 2},
+			wantPage:  []string{"Joe Burrow", "Josh Allen"},
+			wantTotal: 5,
+		},
+		{
+			name:      "last page",
+			q:         PlayerListQuery{Filter: bson.M{}, Sort: "name", Skip: 4, Limit: 2},
+			wantPage:  []string{"Travis Kelce"},
+			wantTotal: 5,
+		},
+		{
+			name:      "filtered",
+			q:         PlayerListQuery{Filter: bson.M{"team": "KC"}, Sort: "name", Limit: 10},
+			wantPage:  []string{"Patrick Mahomes", "Travis Kelce"},
+			wantTotal: 2,
+		},
+		{
+			name:      "no matches",
+			q:         PlayerListQuery{Filter: bson.M{"team": "NYJ"}, Sort: "name", Limit: 10},
+			wantPage:  []string{},
+			wantTotal: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, total := listPlayers(t, docs, nil, tt.q)
+			if got := playerKeys(page); !slices.Equal(got, tt.wantPage) {
+				t.Errorf("page = %v, want %v", got, tt.wantPage)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("total = %d, want %d unique players", total, tt.wantTotal)
+			}
+		})
+	}
+
+	// Each player is listed once, with their latest season
+	page, _ := listPlayers(t, docs, nil, PlayerListQuery{Filter: bson.M{}, Sort: "name", Limit: 10})
+	for _, p := range page {
+		if p.Season != 2025 {
+			t.Errorf("%s listed with season %d, want their latest (2025)", p.Name, p.Season)
+		}
+		if p.Name == "Matthew Stafford" && p.Team != "LA" {
+			t.Errorf("Stafford listed on %s, want the latest team LA", p.Team)
+		}
+	}
+}
+
+func TestPlayerListSortByStat(t *testing.T) {
+	docs := rosterHistory(t)
+	stat := func(id string, season int, seasonType string, passingYards int) models.PlayerStats {
+		return models.PlayerStats{NFLID: id, Season: season, SeasonType: seasonType, PassingYards: passingYards}
+	}
+	stats := toDocs(t, []models.PlayerStats{
+		stat("00-0033873", 2025, models.SeasonTypeRegular, 3900),
+		stat("00-0033873", 2024, models.SeasonTypeRegular, 4800), // not the listed season
+		stat("00-0034857", 2025, models.SeasonTypeRegular, 4100),
+		stat("00-0034857", 2025, models.SeasonTypePost, 900),
+		stat("00-0036442", 2025, models.SeasonTypeRegular, 4500),
+		stat("00-0026498", 2025, models.SeasonTypeRegular, 3700),
+		stat("00-0026498", 2025, models.SeasonTypePost, 1200),
+	})
+
+	tests := []struct {
+		name string
+		q    PlayerListQuery
+		want []string
+	}{
+		{
+			name: "passing yards descending",
+			q:    PlayerListQuery{Filter: bson.M{"position": "QB"}, SeasonType: models.SeasonTypeRegular, Sort: "passing_yards", Descending: true, Limit: 10},
+			want: []string{"Joe Burrow", "Josh Allen", "Patrick Mahomes", "Matthew Stafford"},
+		},
+		{
+			// The sort runs before the page is cut, so page two continues the ranking
+			name: "second page",
+			q:    PlayerListQuery{Filter: bson.M{}, SeasonType: models.SeasonTypeRegular, Sort: "passing_yards", Descending: true, Skip: 2, Limit: 2},
+			want: []string{"Patrick Mahomes", "Matthew Stafford"},
+		},
+		{
+			// Players without the stat sort as 0, ties broken by name
+			name: "ascending",
+			q:    PlayerListQuery{Filter: bson.M{}, SeasonType: models.SeasonTypeRegular, Sort: "passing_yards", Limit: 3},
+			want: []string{"Travis Kelce", "Matthew Stafford", "Patrick Mahomes"},
+		},
+		{
+			name: "postseason",
+			q:    PlayerListQuery{Filter: bson.M{"position": "QB"}, SeasonType: models.SeasonTypePost, Sort: "passing_yards", Descending: true, Limit: 2},
+			want: []string{"Matthew Stafford", "Josh Allen"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, _ := listPlayers(t, docs, stats, tt.q)
+			if got := playerKeys(page); !slices.Equal(got, tt.want) {
+				t.Errorf("page = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidPlayerSort(t *testing.T) {
+	tests := []struct {
+		field string
+		want  bool
+	}{
+		{"name", true},
+		{"season", true},
+		{"passing_yards", true},
+		{"epa", true},
+		{"status", false},
+		{"password_hash", false},
+		{"$where", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidPlayerSort(tt.field); got != tt.want {
+			t.Errorf("ValidPlayerSort(%q) = %v, want %v", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestPlayerStatsFilterSeasonType(t *testing.T) {
+	stat := func(season int, seasonType string, rushingYards int) models.PlayerStats {
+		return models.PlayerStats{NFLID: "00-0036223", Season: season, SeasonType: seasonType, RushingYards: rushingYards}
+	}
+	docs := toDocs(t, []models.PlayerStats{
+		stat(2024, models.SeasonTypeRegular, 1921),
+		stat(2024, models.SeasonTypePost, 150),
+		stat(2024, models.SeasonTypeCombined, 2071),
+		stat(2023, models.SeasonTypeRegular, 1004),
+		stat(2023, models.SeasonTypeCombined, 1004),
+		{NFLID: "00-0033280", Season: 2024, SeasonType: models.SeasonTypeRegular, RushingYards: 1200},
+	})
+
+	tests := []struct {
+		name       string
+		season     int
+		seasonType string
+		want       []int // rushing yards of the matched rows
+	}{
+		{"regular season", 2024, models.SeasonTypeRegular, []int{1921}},
+		{"postseason", 2024, models.SeasonTypePost, []int{150}},
+		{"combined", 2024, models.SeasonTypeCombined, []int{2071}},
+		{"every season", 0, models.SeasonTypeRegular, []int{1921, 1004}},
+		{"no postseason that year", 2023, models.SeasonTypePost, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stats []models.PlayerStats
+			decodeDocs(t, findDocs(t, docs, PlayerStatsFilter("00-0036223", tt.season, tt.seasonType), nil), &stats)
+
+			got := []int{}
+			for _, s := range stats {
+				if s.SeasonType != tt.seasonType {
+					t.Errorf("matched a %s row, want only %s", s.SeasonType, tt.seasonType)
+				}
+				got = append(got, s.RushingYards)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("rushing yards = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
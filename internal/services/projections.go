@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// projectionWindow is how many recent games are averaged into a trailing projection
+const projectionWindow = 4
+
+// Projections projects a player's fantasy points for a week. Implementations return 0
+// when they have nothing to base a projection on.
+type Projections interface {
+	ProjectPlayer(ctx context.Context, nflID string, season, week int) float64
+}
+
+// scoredProjections is implemented by sources whose projections depend on league scoring
+type scoredProjections interface {
+	withScoring(scoring ScoringConfig) Projections
+}
+
+// projectionsWithScoring returns p projecting under scoring. Sources that don't score
+// stats themselves are returned unchanged.
+func projectionsWithScoring(p Projections, scoring ScoringConfig) Projections {
+	if scored, ok := p.(scoredProjections); ok {
+		return scored.withScoring(scoring)
+	}
+	return p
+}
+
+// batchProjections is implemented by sources that can project many players in one query
+type batchProjections interface {
+	ProjectPlayers(ctx context.Context, ids []string, season, week int) (map[string]float64, error)
+}
+
+// projectAll projects each player, in one query when the source supports it
+func projectAll(ctx context.Context, p Projections, ids []string, season, week int) map[string]float64 {
+	if batch, ok := p.(batchProjections); ok {
+		if projections, err := batch.ProjectPlayers(ctx, ids, season, week); err == nil {
+			return projections
+		}
+	}
+
+	projections := make(map[string]float64, len(ids))
+	for _, id := range ids {
+		if points := p.ProjectPlayer(ctx, id, season, week); points != 0 {
+			projections[id] = points
+		}
+	}
+	return projections
+}
+
+// TrailingAverageProjections projects a player as their average points over their most
+// recent games earlier in the season, scoring each game's raw stats with the league's
+// settings (PPR by default). Kickers and team defenses are projected from
+// kicker_weekly_stats and defense_weekly_stats.
+type TrailingAverageProjections struct {
+	db      *mongo.Database
+	window  int
+	scoring ScoringConfig
+}
+
+func NewTrailingAverageProjections(db *mongo.Database) *TrailingAverageProjections {
+	return &TrailingAverageProjections{db: db, window: projectionWindow, scoring: ScoringPPR}
+}
+
+// WithScoring returns a copy of the source that scores games with the given settings
+func (p *TrailingAverageProjections) WithScoring(scoring ScoringConfig) *TrailingAverageProjections {
+	scored := *p
+	scored.scoring = scoring
+	return &scored
+}
+
+func (p *TrailingAverageProjections) withScoring(scoring ScoringConfig) Projections {
+	return p.WithScoring(scoring)
+}
+
+// ProjectPlayer averages the player's last few games before week (week=0 uses the whole season)
+func (p *TrailingAverageProjections) ProjectPlayer(ctx context.Context, nflID string, season, week int) float64 {
+	projections, err := p.ProjectPlayers(ctx, []string{nflID}, season, week)
+	if err != nil {
+		return 0
+	}
+	return projections[nflID]
+}
+
+// ProjectPlayers projects several players in one query. Players with no games before
+// week are absent from the result.
+func (p *TrailingAverageProjections) ProjectPlayers(ctx context.Context, ids []string, season, week int) (map[string]float64, error) {
+	match := bson.M{"nfl_id": bson.M{"$in": ids}, "season": season}
+	if week > 0 {
+		match["week"] = bson.M{"$lt": week}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "week", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$nfl_id",
+			"points": bson.M{"$push": p.scoring.statPointsExpr()},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"points": bson.M{"$slice": []interface{}{"$points", p.window}},
+		}}},
+	}
+
+	cursor, err := p.db.Collection("player_weekly_stats").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate projections: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		PlayerID string    `bson:"_id"`
+		Points   []float64 `bson:"points"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode projections: %w", err)
+	}
+
+	projections := make(map[string]float64, len(results))
+	for _, r := range results {
+		projections[r.PlayerID] = trailingAverage(r.Points, p.window)
+	}
+
+	if err := p.projectSpecialTeams(ctx, ids, season, week, projections); err != nil {
+		return nil, err
+	}
+	return projections, nil
+}
+
+// projectSpecialTeams projects kickers (by NFL ID) and team defenses (by team abbreviation)
+// from their recent kicking and D/ST lines. Kickers also have player_weekly_stats rows from
+// the same nflverse file, but with no passing, rushing or receiving stats those score 0, so
+// a kicking projection replaces whatever the weekly stats gave.
+func (p *TrailingAverageProjections) projectSpecialTeams(ctx context.Context, ids []string, season, week int, projections map[string]float64) error {
+	filter := func(idField string) bson.M {
+		f := bson.M{idField: bson.M{"$in": ids}, "season": season}
+		if week > 0 {
+			f["week"] = bson.M{"$lt": week}
+		}
+		return f
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "week", Value: -1}})
+
+	cursor, err := p.db.Collection("kicker_weekly_stats").Find(ctx, filter("nfl_id"), opts)
+	if err != nil {
+		return fmt.Errorf("failed to fetch kicker stats: %w", err)
+	}
+	var kickers []models.KickerStats
+	if err := cursor.All(ctx, &kickers); err != nil {
+		return fmt.Errorf("failed to decode kicker stats: %w", err)
+	}
+
+	cursor, err = p.db.Collection("defense_weekly_stats").Find(ctx, filter("team"), opts)
+	if err != nil {
+		return fmt.Errorf("failed to fetch defense stats: %w", err)
+	}
+	var defenses []models.DefenseStats
+	if err := cursor.All(ctx, &defenses); err != nil {
+		return fmt.Errorf("failed to decode defense stats: %w", err)
+	}
+
+	for id, points := range specialTeamsProjections(kickers, defenses, p.scoring, p.window) {
+		projections[id] = points
+	}
+	return nil
+}
+
+// specialTeamsProjections averages each kicker's and defense's most recent window weeks,
+// scored with scoring. Lines must be ordered most recent first.
+func specialTeamsProjections(kickers []models.KickerStats, defenses []models.DefenseStats, scoring ScoringConfig, window int) map[string]float64 {
+	recent := make(map[string][]float64)
+	addRecent := func(id string, points float64) {
+		if len(recent[id]) < window {
+			recent[id] = append(recent[id], points)
+		}
+	}
+	for _, k := range kickers {
+		addRecent(k.NFLID, scoring.KickerPoints(k))
+	}
+	for _, d := range defenses {
+		addRecent(d.Team, scoring.DefensePoints(d))
+	}
+
+	projections := make(map[string]float64, len(recent))
+	for id, points := range recent {
+		projections[id] = trailingAverage(points, window)
+	}
+	return projections
+}
+
+// trailingAverage averages the first window scores of points, which are ordered most recent
+// first. It returns 0 when there are none.
+func trailingAverage(points []float64, window int) float64 {
+	if len(points) > window {
+		points = points[:window]
+	}
+	if len(points) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, pts := range points {
+		total += pts
+	}
+	return total / float64(len(points))
+}
+
+// ESPNProjections serves ESPN's projected points for the players in a user's league, which
+// ESPN already scores with the league's settings. ESPN rosters don't carry NFL IDs, so
+// projections are keyed by player name; players ESPN doesn't project are passed to the
+// fallback source.
+type ESPNProjections struct {
+	db       *mongo.Database
+	byName   map[string]float64
+	fallback Projections
+}
+
+// NewESPNProjections builds a source from ESPN projected points keyed by player name.
+// fallback may be nil.
+func NewESPNProjections(db *mongo.Database, byName map[string]float64, fallback Projections) *ESPNProjections {
+	normalized := make(map[string]float64, len(byName))
+	for name, points := range byName {
+		normalized[normalizePlayerName(name)] = points
+	}
+	return &ESPNProjections{db: db, byName: normalized, fallback: fallback}
+}
+
+// withScoring rescores the fallback; ESPN's own numbers already follow the league
+func (p *ESPNProjections) withScoring(scoring ScoringConfig) Projections {
+	scored := *p
+	if p.fallback != nil {
+		scored.fallback = projectionsWithScoring(p.fallback, scoring)
+	}
+	return &scored
+}
+
+func (p *ESPNProjections) ProjectPlayer(ctx context.Context, nflID string, season, week int) float64 {
+	var player models.Player
+	opts := options.FindOne().SetSort(bson.D{{Key: "season", Value: -1}})
+	err := p.db.Collection("players").FindOne(ctx, bson.M{"nfl_id": nflID, "season": bson.M{"$lte": season}}, opts).Decode(&player)
+	if err == nil {
+		if points, ok := p.byName[normalizePlayerName(player.Name)]; ok {
+			return points
+		}
+	}
+
+	if p.fallback == nil {
+		return 0
+	}
+	return p.fallback.ProjectPlayer(ctx, nflID, season, week)
+}
+
+// normalizePlayerName lowercases a name and drops punctuation so ESPN and NFLverse
+// spellings (e.g. "D.J. Moore" and "DJ Moore") match
+func normalizePlayerName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer(".", "", "'", "", "-", "").Replace(name)
+	return strings.Join(strings.Fields(name), " ")
+}
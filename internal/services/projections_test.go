@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTrailingAverage(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []float64
+		window int
+		want   float64
+	}{
+		{"no games", nil, 4, 0},
+		{"fewer games than the window", []float64{10, 20}, 4, 15},
+		{"exactly the window", []float64{10, 20, 30, 40}, 4, 25},
+		{"older games past the window are ignored", []float64{12, 8, 10, 6, 40, 40}, 4, 9},
+		{"zero-point games count", []float64{0, 0, 12}, 4, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trailingAverage(tt.points, tt.window); got != tt.want {
+				t.Errorf("trailingAverage(%v, %d) = %v, want %v", tt.points, tt.window, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePlayerName(t *testing.T) {
+	tests := map[string]string{
+		"D.J. Moore":          "dj moore",
+		"DJ Moore":            "dj moore",
+		"Ja'Marr Chase":       "jamarr chase",
+		"Amon-Ra St. Brown":   "amonra st brown",
+		"  Patrick   Mahomes": "patrick mahomes",
+	}
+	for name, want := range tests {
+		if got := normalizePlayerName(name); got != want {
+			t.Errorf("normalizePlayerName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// fixedProjections projects every player the same, for testing fallbacks
+type fixedProjections float64
+
+func (f fixedProjections) ProjectPlayer(ctx context.Context, nflID string, season, week int) float64 {
+	return float64(f)
+}
+
+func TestProjectAllWithoutBatchSkipsZeroProjections(t *testing.T) {
+	got := projectAll(context.Background(), fixedProjections(0), []string{"a", "b"}, 2025, 10)
+	if len(got) != 0 {
+		t.Errorf("projectAll() = %v, want no projections", got)
+	}
+
+	got = projectAll(context.Background(), fixedProjections(12.5), []string{"a", "b"}, 2025, 10)
+	if got["a"] != 12.5 || got["b"] != 12.5 {
+		t.Errorf("projectAll() = %v, want 12.5 for each player", got)
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+// These render the prompt templates with the structs the services really pass, so a field
+// renamed on either side fails here rather than on a live request
+
+func TestBuildComparisonPrompt(t *testing.T) {
+	playerA := &EnrichedPlayerData{
+		Name: "Jalen Hurts", Position: "QB", Team: "PHI", ProjectedPoints: 21.4, SeasonAverage: 20.1,
+		InjuryStatus: "ACTIVE", AvgEPA: 0.142, TrendDescription: "trending up",
+		RecentGames:     []GamePerformance{{Week: 9, Opponent: "JAX", PassingYards: 210, PassingTDs: 2, FantasyPoints: 24.2}},
+		MatchupAnalysis: "DAL allows the 5th-most QB points",
+	}
+	playerB := &EnrichedPlayerData{Name: "Tucker Kraft", Position: "TE", Team: "GB", InjuryStatus: "QUESTIONABLE", IsInjured: true}
+
+	prompt, err := (&FantasyAdvisorService{}).buildComparisonPrompt(playerA, playerB)
+	if err != nil {
+		t.Fatalf("buildComparisonPrompt() error = %v", err)
+	}
+	for _, want := range []string{"PLAYER A: Jalen Hurts", "Week 9 vs JAX: 24.2 pts (210 pass yds, 2 TD, 0 INT)", "PLAYER B: Tucker Kraft", "⚠️ INJURED"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt is missing %q", want)
+		}
+	}
+}
+
+func TestBuildGameScriptPrompt(t *testing.T) {
+	game := models.Game{AwayTeam: "KC", HomeTeam: "BUF", VegasLine: -2.5, OverUnder: 47.5, Week: 11,
+		StartTime: time.Date(2025, 11, 16, 21, 25, 0, 0, time.UTC)}
+
+	prompt, err := (&GameScriptService{}).buildGameScriptPrompt(game, "BUF roster", "KC roster", "history", "splits", "tendencies")
+	if err != nil {
+		t.Fatalf("buildGameScriptPrompt() error = %v", err)
+	}
+	for _, want := range []string{"KC (Away) @ BUF (Home)", "BUF -2.5", "Sun Nov 16 9:25 PM", "BUF roster", "tendencies"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt is missing %q", want)
+		}
+	}
+}
+
+func TestBuildChatbotPrompt(t *testing.T) {
+	history := []models.ChatMessage{
+		{Role: models.ChatRoleUser, Content: "Who is ATL's RB1?"},
+		{Role: models.ChatRoleModel, Content: "Bijan Robinson."},
+	}
+	lineups := []models.FantasyLineup{{Slots: []models.LineupSlot{{Slot: "RB", PlayerID: "00-0038542"}}}}
+
+	prompt, err := (&ChatbotService{}).buildChatbotPrompt("Start Bijan?", lineups, "98 rush yds/game", history)
+	if err != nil {
+		t.Fatalf("buildChatbotPrompt() error = %v", err)
+	}
+	for _, want := range []string{"User's current lineup: RB: 00-0038542", "98 rush yds/game", "User: Who is ATL's RB1?\nAdvisor: Bijan Robinson.", "User Question: Start Bijan?"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt is missing %q", want)
+		}
+	}
+}
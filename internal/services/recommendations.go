@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/teams"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// waiverHitPoints is the PPR score a waiver pickup needs in the recommended week to count
+// as a startable week; positions not listed use defaultWaiverHitPoints
+var waiverHitPoints = map[string]float64{
+	"QB": 15,
+	"RB": 10,
+	"WR": 10,
+	"TE": 8,
+}
+
+const defaultWaiverHitPoints = 10
+
+// waiverAddActions are the waiver recommendations that tell the manager to pick a player up
+var waiverAddActions = map[string]bool{
+	"Must Add":   true,
+	"Strong Add": true,
+	"Good Add":   true,
+}
+
+type RecommendationService struct {
+	db *mongo.Database
+}
+
+func NewRecommendationService(db *mongo.Database) *RecommendationService {
+	return &RecommendationService{db: db}
+}
+
+// AccuracyLine is the hit rate for a set of scored recommendations
+type AccuracyLine struct {
+	Total    int     `json:"total"`
+	Hits     int     `json:"hits"`
+	Accuracy float64 `json:"accuracy"` // Hits / Total, 0 when nothing has been scored
+}
+
+// RecommendationAccuracy summarizes how often recommendations paid off in a season
+type RecommendationAccuracy struct {
+	Season  int                     `json:"season"`
+	Overall AccuracyLine            `json:"overall"`
+	ByKind  map[string]AccuracyLine `json:"by_kind"`
+}
+
+// Record saves a recommendation, replacing an earlier one the user got for the same kind,
+// week and players
+func (s *RecommendationService) Record(ctx context.Context, rec models.Recommendation) error {
+	now := time.Now()
+	filter := bson.M{
+		"user_id": rec.UserID,
+		"kind":    rec.Kind,
+		"season":  rec.Season,
+		"week":    rec.Week,
+		"players": rec.Players,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"pick":       rec.Pick,
+			"action":     rec.Action,
+			"confidence": rec.Confidence,
+			"scored":     false,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+
+	_, err := s.db.Collection("recommendations").UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save recommendation: %w", err)
+	}
+	return nil
+}
+
+// RecordWaiverGems saves one waiver recommendation per gem
+func (s *RecommendationService) RecordWaiverGems(ctx context.Context, userID bson.ObjectID, season, week int, gems []WaiverGem) error {
+	for _, gem := range gems {
+		rec := models.Recommendation{
+			UserID:  userID,
+			Kind:    models.RecommendationWaiver,
+			Season:  season,
+			Week:    week,
+			Players: []models.RecommendedPlayer{{Name: gem.PlayerName, Position: gem.Position, Team: gem.Team}},
+			Pick:    gem.PlayerName,
+			Action:  waiverAction(gem.Recommendation),
+		}
+		if err := s.Record(ctx, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waiverAction strips the emoji a waiver recommendation is displayed with ("🔥 Must Add")
+func waiverAction(recommendation string) string {
+	return strings.TrimLeftFunc(recommendation, func(r rune) bool { return !unicode.IsLetter(r) })
+}
+
+// ScorePending scores every unscored recommendation for season up to throughWeek against
+// the PPR points its players actually scored, writing one recommendations_outcomes document
+// each. Recommendations naming a player who can't be matched to the season's roster are
+// marked scored without an outcome so they aren't retried every run. It returns the number
+// of outcomes written.
+func (s *RecommendationService) ScorePending(ctx context.Context, season, throughWeek int) (int, error) {
+	filter := bson.M{"season": season, "week": bson.M{"$lte": throughWeek}, "scored": false}
+	cursor, err := s.db.Collection("recommendations").Find(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch pending recommendations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var pending []models.Recommendation
+	if err := cursor.All(ctx, &pending); err != nil {
+		return 0, fmt.Errorf("failed to decode pending recommendations: %w", err)
+	}
+
+	written := 0
+	for _, rec := range pending {
+		points, ok, err := s.actualPoints(ctx, rec)
+		if err != nil {
+			return written, err
+		}
+
+		if ok {
+			outcome := models.RecommendationOutcome{
+				RecommendationID: rec.ID,
+				UserID:           rec.UserID,
+				Kind:             rec.Kind,
+				Season:           rec.Season,
+				Week:             rec.Week,
+				Pick:             rec.Pick,
+				Points:           points,
+				Hit:              scoreRecommendation(rec, points),
+				ScoredAt:         time.Now(),
+			}
+			// Upsert so a run interrupted before marking the recommendation scored can redo it
+			_, err := s.db.Collection("recommendations_outcomes").ReplaceOne(ctx,
+				bson.M{"recommendation_id": rec.ID}, outcome, options.Replace().SetUpsert(true))
+			if err != nil {
+				return written, fmt.Errorf("failed to save recommendation outcome: %w", err)
+			}
+			written++
+		} else {
+			log.Printf("Skipping %s recommendation %s: a player couldn't be matched to the %d roster", rec.Kind, rec.ID.Hex(), rec.Season)
+		}
+
+		if _, err := s.db.Collection("recommendations").UpdateByID(ctx, rec.ID, bson.M{"$set": bson.M{"scored": true}}); err != nil {
+			return written, fmt.Errorf("failed to mark recommendation scored: %w", err)
+		}
+	}
+
+	return written, nil
+}
+
+// actualPoints looks up each recommended player's PPR points for the recommendation's week.
+// A matched player without a stats row didn't play and scores 0; ok is false when any
+// player can't be matched at all.
+func (s *RecommendationService) actualPoints(ctx context.Context, rec models.Recommendation) (map[string]float64, bool, error) {
+	points := make(map[string]float64, len(rec.Players))
+	for _, player := range rec.Players {
+		nflID, err := s.resolvePlayer(ctx, player, rec.Season)
+		if err == mongo.ErrNoDocuments {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to find player %s: %w", player.Name, err)
+		}
+
+		var stat models.WeeklyStat
+		err = s.db.Collection("player_weekly_stats").FindOne(ctx, bson.M{
+			"nfl_id": nflID,
+			"season": rec.Season,
+			"week":   rec.Week,
+		}).Decode(&stat)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, false, fmt.Errorf("failed to fetch weekly stats for %s: %w", player.Name, err)
+		}
+		points[player.Name] = stat.FantasyPointsPPR
+	}
+	return points, true, nil
+}
+
+// resolvePlayer matches a recommended player to the season's roster by name and team,
+// falling back to a case-insensitive name match on the team. ESPN team codes are mapped to
+// the nflverse ones the roster is stored with.
+func (s *RecommendationService) resolvePlayer(ctx context.Context, player models.RecommendedPlayer, season int) (string, error) {
+	var found models.Player
+	opts := options.FindOne().SetProjection(bson.M{"nfl_id": 1})
+	team := teams.Normalize(player.Team)
+
+	err := s.db.Collection("players").FindOne(ctx, bson.M{
+		"name":   player.Name,
+		"team":   team,
+		"season": season,
+	}, opts).Decode(&found)
+	if err == mongo.ErrNoDocuments {
+		err = s.db.Collection("players").FindOne(ctx, bson.M{
+			"name":   bson.M{"$regex": "^" + regexp.QuoteMeta(player.Name) + "$", "$options": "i"},
+			"team":   team,
+			"season": season,
+		}, opts).Decode(&found)
+	}
+	if err != nil {
+		return "", err
+	}
+	return found.NFLID, nil
+}
+
+// scoreRecommendation decides whether a recommendation paid off given each player's actual
+// points. A start/sit pick hits when it outscored (or tied) the player left on the bench. A
+// waiver call hits when an add recommendation produced a startable week, or when a
+// Monitor/Pass call didn't.
+func scoreRecommendation(rec models.Recommendation, points map[string]float64) bool {
+	picked := points[rec.Pick]
+
+	switch rec.Kind {
+	case models.RecommendationStartSit:
+		for _, player := range rec.Players {
+			if player.Name != rec.Pick && points[player.Name] > picked {
+				return false
+			}
+		}
+		return true
+	case models.RecommendationWaiver:
+		threshold := float64(defaultWaiverHitPoints)
+		if len(rec.Players) > 0 {
+			if t, ok := waiverHitPoints[rec.Players[0].Position]; ok {
+				threshold = t
+			}
+		}
+		return waiverAddActions[rec.Action] == (picked >= threshold)
+	default:
+		return false
+	}
+}
+
+// Accuracy aggregates a season's scored outcomes into an overall and per-kind hit rate
+func (s *RecommendationService) Accuracy(ctx context.Context, season int) (*RecommendationAccuracy, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"season": season}},
+		{"$group": bson.M{
+			"_id":   "$kind",
+			"total": bson.M{"$sum": 1},
+			"hits":  bson.M{"$sum": bson.M{"$cond": bson.A{"$hit", 1, 0}}},
+		}},
+	}
+
+	cursor, err := s.db.Collection("recommendations_outcomes").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate recommendation outcomes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Kind  string `bson:"_id"`
+		Total int    `bson:"total"`
+		Hits  int    `bson:"hits"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode recommendation outcomes: %w", err)
+	}
+
+	accuracy := &RecommendationAccuracy{
+		Season: season,
+		ByKind: make(map[string]AccuracyLine, len(results)),
+	}
+	for _, r := range results {
+		accuracy.ByKind[r.Kind] = accuracyLine(r.Total, r.Hits)
+		accuracy.Overall.Total += r.Total
+		accuracy.Overall.Hits += r.Hits
+	}
+	accuracy.Overall = accuracyLine(accuracy.Overall.Total, accuracy.Overall.Hits)
+
+	return accuracy, nil
+}
+
+func accuracyLine(total, hits int) AccuracyLine {
+	line := AccuracyLine{Total: total, Hits: hits}
+	if total > 0 {
+		line.Accuracy = float64(hits) / float64(total)
+	}
+	return line
+}
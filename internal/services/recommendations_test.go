@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestScoreRecommendation(t *testing.T) {
+	startSit := func(pick string) models.Recommendation {
+		return models.Recommendation{
+			Kind: models.RecommendationStartSit,
+			Players: []models.RecommendedPlayer{
+				{Name: "Player A", Position: "WR"},
+				{Name: "Player B", Position: "WR"},
+			},
+			Pick: pick,
+		}
+	}
+	waiver := func(position, action string) models.Recommendation {
+		return models.Recommendation{
+			Kind:    models.RecommendationWaiver,
+			Players: []models.RecommendedPlayer{{Name: "Player A", Position: position}},
+			Pick:    "Player A",
+			Action:  action,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		rec    models.Recommendation
+		points map[string]float64
+		want   bool
+	}{
+		{"start/sit pick outscored bench", startSit("Player A"), map[string]float64{"Player A": 18, "Player B": 7}, true},
+		{"start/sit pick outscored by bench", startSit("Player A"), map[string]float64{"Player A": 7, "Player B": 18}, false},
+		{"start/sit tie counts as hit", startSit("Player B"), map[string]float64{"Player A": 12, "Player B": 12}, true},
+		{"start/sit pick didn't play", startSit("Player A"), map[string]float64{"Player A": 0, "Player B": 3}, false},
+		{"waiver add hit startable week", waiver("RB", "Must Add"), map[string]float64{"Player A": 10}, true},
+		{"waiver add missed", waiver("RB", "Strong Add"), map[string]float64{"Player A": 9.9}, false},
+		{"waiver QB uses higher threshold", waiver("QB", "Good Add"), map[string]float64{"Player A": 14}, false},
+		{"waiver TE uses lower threshold", waiver("TE", "Good Add"), map[string]float64{"Player A": 8}, true},
+		{"waiver unlisted position uses default", waiver("K", "Good Add"), map[string]float64{"Player A": 10}, true},
+		{"waiver pass on a dud", waiver("WR", "Pass"), map[string]float64{"Player A": 4}, true},
+		{"waiver monitor on a breakout", waiver("WR", "Monitor"), map[string]float64{"Player A": 22}, false},
+		{"unknown kind", models.Recommendation{Kind: "trade", Pick: "Player A"}, map[string]float64{"Player A": 30}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scoreRecommendation(tt.rec, tt.points); got != tt.want {
+				t.Errorf("scoreRecommendation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaiverAction(t *testing.T) {
+	tests := map[string]string{
+		"🔥 Must Add": "Must Add",
+		"⭐ Good Add": "Good Add",
+		"Monitor":    "Monitor",
+		"👀 👀 Pass":   "Pass",
+		"":           "",
+	}
+	for in, want := range tests {
+		if got := waiverAction(in); got != want {
+			t.Errorf("waiverAction(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
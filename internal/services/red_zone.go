@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// redZoneYardLine is the deepest yard line (yards from the opponent's end zone) counted as red zone
+const redZoneYardLine = 20
+
+// RedZoneUsage is a player's involvement inside the opponent's 20 for a season
+type RedZoneUsage struct {
+	NFLID  string `json:"nfl_id"`
+	Season int    `json:"season"`
+
+	Targets      int `json:"targets"`
+	Carries      int `json:"carries"`
+	PassAttempts int `json:"pass_attempts"`
+	Touches      int `json:"touches"` // Targets plus carries
+	Touchdowns   int `json:"touchdowns"`
+
+	TotalTargets int `json:"total_targets"`
+	TotalCarries int `json:"total_carries"`
+
+	TouchShare float64 `json:"touch_share"` // Share of the player's targets and carries that came in the red zone
+	TDRate     float64 `json:"td_rate"`     // Touchdowns per red-zone touch or pass attempt
+}
+
+// GetRedZoneUsage counts a player's targets, carries, pass attempts and touchdowns on plays
+// snapped at or inside the opponent's 20 (yard_line is nflverse's yardline_100), alongside
+// their season totals so the red-zone share can be compared across players.
+func (s *DataService) GetRedZoneUsage(ctx context.Context, nflID string, season int) (*RedZoneUsage, error) {
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, redZonePipeline(nflID, season))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate red zone usage: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []redZoneRow
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode red zone usage: %w", err)
+	}
+	return buildRedZoneUsage(nflID, season, results), nil
+}
+
+// redZoneRow is a player's season and red-zone counts from redZonePipeline
+type redZoneRow struct {
+	TotalTargets int `bson:"total_targets"`
+	TotalCarries int `bson:"total_carries"`
+	Targets      int `bson:"targets"`
+	Carries      int `bson:"carries"`
+	PassAttempts int `bson:"pass_attempts"`
+	Touchdowns   int `bson:"touchdowns"`
+}
+
+// redZonePipeline counts a player's season targets and carries and their red-zone
+// targets, carries, pass attempts and touchdowns in one group
+func redZonePipeline(nflID string, season int) mongo.Pipeline {
+	inRedZone := bson.M{"$and": bson.A{
+		bson.M{"$gt": bson.A{"$yard_line", 0}},
+		bson.M{"$lte": bson.A{"$yard_line", redZoneYardLine}},
+	}}
+	countIf := func(conds ...bson.M) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$and": conds}, 1, 0}}}
+	}
+	isTarget := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$receiver_player_id", nflID}},
+		bson.M{"$eq": bson.A{"$play_type", "pass"}},
+	}}
+	isCarry := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$rusher_player_id", nflID}},
+		bson.M{"$eq": bson.A{"$play_type", "run"}},
+	}}
+	isPass := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$passer_player_id", nflID}},
+		bson.M{"$eq": bson.A{"$play_type", "pass"}},
+	}}
+	isTD := bson.M{"$eq": bson.A{"$touchdown", true}}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"season": season,
+			"$or": []bson.M{
+				{"passer_player_id": nflID},
+				{"rusher_player_id": nflID},
+				{"receiver_player_id": nflID},
+			},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           nil,
+			"total_targets": countIf(isTarget),
+			"total_carries": countIf(isCarry),
+			"targets":       countIf(inRedZone, isTarget),
+			"carries":       countIf(inRedZone, isCarry),
+			"pass_attempts": countIf(inRedZone, isPass),
+			"touchdowns":    countIf(inRedZone, isTD),
+		}}},
+	}
+}
+
+// buildRedZoneUsage turns the pipeline's counts into usage and rates
+func buildRedZoneUsage(nflID string, season int, results []redZoneRow) *RedZoneUsage {
+	usage := &RedZoneUsage{NFLID: nflID, Season: season}
+	if len(results) == 0 {
+		return usage
+	}
+
+	r := results[0]
+	usage.Targets = r.Targets
+	usage.Carries = r.Carries
+	usage.PassAttempts = r.PassAttempts
+	usage.Touches = r.Targets + r.Carries
+	usage.Touchdowns = r.Touchdowns
+	usage.TotalTargets = r.TotalTargets
+	usage.TotalCarries = r.TotalCarries
+
+	if total := r.TotalTargets + r.TotalCarries; total > 0 {
+		usage.TouchShare = float64(usage.Touches) / float64(total)
+	}
+	if opportunities := usage.Touches + usage.PassAttempts; opportunities > 0 {
+		usage.TDRate = float64(usage.Touchdowns) / float64(opportunities)
+	}
+	return usage
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestRedZoneUsage(t *testing.T) {
+	const rb = "00-0038542"
+	carry := func(yardLine int, touchdown bool) models.Play {
+		return models.Play{Season: 2024, PlayType: "run", RusherPlayerID: rb, YardLine: yardLine, Touchdown: touchdown}
+	}
+	target := func(yardLine int, touchdown bool) models.Play {
+		return models.Play{Season: 2024, PlayType: "pass", PasserPlayerID: "00-0036442", ReceiverPlayerID: rb, YardLine: yardLine, Touchdown: touchdown}
+	}
+
+	plays := []models.Play{
+		// Red zone: three carries, one a touchdown, and a touchdown catch
+		carry(5, true),
+		carry(15, false),
+		carry(20, false), // the 20 counts
+		target(10, true),
+		// Outside the red zone, including a long touchdown run
+		carry(21, false),
+		carry(60, true),
+		carry(0, false), // unknown field position
+		target(45, false),
+		// Not this player or season
+		{Season: 2024, PlayType: "run", RusherPlayerID: "00-0000002", YardLine: 3, Touchdown: true},
+		{Season: 2023, PlayType: "run", RusherPlayerID: rb, YardLine: 1, Touchdown: true},
+	}
+
+	var rows []redZoneRow
+	decodeDocs(t, runPipeline(t, redZonePipeline(rb, 2024), toDocs(t, plays), nil), &rows)
+	got := buildRedZoneUsage(rb, 2024, rows)
+
+	want := RedZoneUsage{
+		NFLID: rb, Season: 2024,
+		Targets: 1, Carries: 3, Touches: 4, Touchdowns: 2,
+		TotalTargets: 2, TotalCarries: 6,
+	}
+	gotCounts := *got
+	gotCounts.TouchShare, gotCounts.TDRate = 0, 0
+	if gotCounts != want {
+		t.Errorf("red zone counts = %+v, want %+v", gotCounts, want)
+	}
+	if math.Abs(got.TouchShare-0.5) > 1e-9 || math.Abs(got.TDRate-0.5) > 1e-9 {
+		t.Errorf("touch share / TD rate = %v / %v, want 0.5 / 0.5", got.TouchShare, got.TDRate)
+	}
+}
+
+func TestRedZoneUsageQuarterback(t *testing.T) {
+	const qb = "00-0036442"
+	plays := []models.Play{
+		{Season: 2024, PlayType: "pass", PasserPlayerID: qb, ReceiverPlayerID: "wr", YardLine: 8, Touchdown: true},
+		{Season: 2024, PlayType: "pass", PasserPlayerID: qb, ReceiverPlayerID: "te", YardLine: 12},
+		{Season: 2024, PlayType: "run", RusherPlayerID: qb, YardLine: 2, Touchdown: true},
+		{Season: 2024, PlayType: "pass", PasserPlayerID: qb, ReceiverPlayerID: "wr", YardLine: 50},
+	}
+
+	var rows []redZoneRow
+	decodeDocs(t, runPipeline(t, redZonePipeline(qb, 2024), toDocs(t, plays), nil), &rows)
+	got := buildRedZoneUsage(qb, 2024, rows)
+
+	// Pass attempts count toward the TD rate but not touches
+	if got.PassAttempts != 2 || got.Carries != 1 || got.Touches != 1 || got.Touchdowns != 2 {
+		t.Errorf("QB red zone = %+v, want 2 pass attempts, 1 carry, 2 touchdowns", got)
+	}
+	if math.Abs(got.TDRate-2.0/3) > 1e-9 || got.TouchShare != 1 {
+		t.Errorf("QB TD rate / touch share = %v / %v, want 2/3 / 1", got.TDRate, got.TouchShare)
+	}
+
+	if empty := buildRedZoneUsage(qb, 2024, nil); empty.Touches != 0 || empty.TDRate != 0 || empty.NFLID != qb {
+		t.Errorf("buildRedZoneUsage() without plays = %+v, want zero usage", empty)
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/teams"
+)
+
+// PositionStrength compares a roster's starters at one position with the league's
+type PositionStrength struct {
+	Position      string  `json:"position"`
+	Starters      int     `json:"starters"`
+	AvgProjected  float64 `json:"avgProjected"`  // Average projected points of the roster's starters
+	LeagueAverage float64 `json:"leagueAverage"` // Average projection of starting-caliber players league-wide
+	VsLeague      float64 `json:"vsLeague"`      // Fractional difference from the league average, e.g. -0.2
+	Weak          bool    `json:"weak"`
+}
+
+// ByeGap is a week where a position has fewer healthy, playing players than starting slots
+type ByeGap struct {
+	Week      int      `json:"week"`
+	Position  string   `json:"position"`
+	Starters  int      `json:"starters"`
+	Available int      `json:"available"`
+	OnBye     []string `json:"onBye"`
+}
+
+// RosterAnalysis is a roster's strength by position, its weak spots and upcoming bye gaps
+type RosterAnalysis struct {
+	Season        int                `json:"season"`
+	Week          int                `json:"week"`
+	Positions     []PositionStrength `json:"positions"`
+	WeakPositions []string           `json:"weakPositions"`
+	ByeGaps       []ByeGap           `json:"byeGaps"`
+}
+
+// longTermInjuryStatuses are ESPN injury statuses that keep a player out beyond this week
+var longTermInjuryStatuses = map[string]bool{
+	"INJURY_RESERVE": true,
+	"SUSPENSION":     true,
+}
+
+// AnalyzeRoster rates a roster's starters at each position against the league baseline and
+// finds bye gaps from week through the end of the regular season: weeks where a position
+// has fewer players who are healthy and not on bye than the roster starts there. An OUT
+// status only counts against the current week; IR and suspensions count against every week.
+// Players without a proTeam are assumed to be playing.
+func (s *WaiverWireService) AnalyzeRoster(ctx context.Context, roster []RosterPlayer, season, week int) (*RosterAnalysis, error) {
+	baselines := s.positionBaselines(ctx, season, week)
+	games, err := s.dataService.GetGamesBySeason(ctx, season, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schedule: %w", err)
+	}
+	return s.analyzeRoster(roster, baselines, games, season, week), nil
+}
+
+// analyzeRoster builds the analysis for AnalyzeRoster from the league baselines and the
+// season's schedule
+func (s *WaiverWireService) analyzeRoster(roster []RosterPlayer, baselines map[string]float64, games []models.Game, season, week int) *RosterAnalysis {
+	strength := s.analyzeRosterStrength(roster)
+	weak := s.identifyWeakPositions(strength, baselines)
+	sort.Strings(weak)
+
+	starters := make(map[string]int)
+	for _, p := range roster {
+		if p.LineupSlot == p.Position {
+			starters[p.Position]++
+		}
+	}
+
+	analysis := &RosterAnalysis{Season: season, Week: week, Positions: []PositionStrength{}, WeakPositions: weak, ByeGaps: []ByeGap{}}
+	for pos, avg := range strength {
+		ps := PositionStrength{
+			Position:     pos,
+			Starters:     starters[pos],
+			AvgProjected: avg,
+		}
+		if leagueAvg, ok := baselines[pos]; ok && leagueAvg > 0 {
+			ps.LeagueAverage = leagueAvg
+			ps.VsLeague = (avg - leagueAvg) / leagueAvg
+		}
+		for _, w := range weak {
+			ps.Weak = ps.Weak || w == pos
+		}
+		analysis.Positions = append(analysis.Positions, ps)
+	}
+	sort.Slice(analysis.Positions, func(i, j int) bool {
+		return analysis.Positions[i].Position < analysis.Positions[j].Position
+	})
+
+	playing := make(map[int]map[string]bool)
+	for _, g := range games {
+		if g.Week < week || g.Week > regularSeasonWeeks {
+			continue
+		}
+		if playing[g.Week] == nil {
+			playing[g.Week] = make(map[string]bool)
+		}
+		playing[g.Week][teams.Normalize(g.HomeTeam)] = true
+		playing[g.Week][teams.Normalize(g.AwayTeam)] = true
+	}
+
+	for w := week; w <= regularSeasonWeeks; w++ {
+		teamsPlaying, scheduled := playing[w]
+		if !scheduled {
+			continue
+		}
+		for _, pos := range sortedKeys(starters) {
+			gap := ByeGap{Week: w, Position: pos, Starters: starters[pos], OnBye: []string{}}
+			for _, p := range roster {
+				status := strings.ToUpper(p.InjuryStatus)
+				if p.Position != pos || p.LineupSlot == "IR" || longTermInjuryStatuses[status] || (status == "OUT" && w == week) {
+					continue
+				}
+				if p.ProTeam != "" && !teamsPlaying[teams.Normalize(p.ProTeam)] {
+					gap.OnBye = append(gap.OnBye, p.Name)
+					continue
+				}
+				gap.Available++
+			}
+			if gap.Available < gap.Starters {
+				analysis.ByeGaps = append(analysis.ByeGaps, gap)
+			}
+		}
+	}
+
+	return analysis
+}
+
+// sortedKeys returns a map's keys in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
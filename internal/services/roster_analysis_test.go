@@ -0,0 +1,50 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestAnalyzeRosterWeakTEAndRBByeWeek(t *testing.T) {
+	// ESPN team codes: WSH and LAR are WAS and LA in the schedule
+	roster := []RosterPlayer{
+		{Name: "Jalen Hurts", Position: "QB", ProTeam: "PHI", LineupSlot: "QB", ProjectedPoints: 21},
+		{Name: "Brian Robinson", Position: "RB", ProTeam: "WSH", LineupSlot: "RB", ProjectedPoints: 13},
+		{Name: "Kyren Williams", Position: "RB", ProTeam: "LAR", LineupSlot: "RB", ProjectedPoints: 15},
+		{Name: "CeeDee Lamb", Position: "WR", ProTeam: "DAL", LineupSlot: "WR", ProjectedPoints: 16},
+		{Name: "Cade Otton", Position: "TE", ProTeam: "TB", LineupSlot: "TE", ProjectedPoints: 5},
+		{Name: "Bench RB", Position: "RB", ProTeam: "NE", LineupSlot: "BE", ProjectedPoints: 6, InjuryStatus: "INJURY_RESERVE"},
+	}
+	baselines := map[string]float64{"QB": 18, "RB": 12, "WR": 10, "TE": 8}
+	games := []models.Game{
+		{Week: 11, HomeTeam: "WAS", AwayTeam: "PHI"},
+		{Week: 11, HomeTeam: "LA", AwayTeam: "DAL"},
+		{Week: 11, HomeTeam: "TB", AwayTeam: "NE"},
+		// WAS and LA are on bye in week 12
+		{Week: 12, HomeTeam: "PHI", AwayTeam: "DAL"},
+		{Week: 12, HomeTeam: "TB", AwayTeam: "NE"},
+	}
+
+	analysis := NewWaiverWireService(nil).analyzeRoster(roster, baselines, games, 2025, 11)
+
+	if !reflect.DeepEqual(analysis.WeakPositions, []string{"TE"}) {
+		t.Errorf("WeakPositions = %v, want [TE]", analysis.WeakPositions)
+	}
+	for _, ps := range analysis.Positions {
+		if ps.Position == "TE" && (!ps.Weak || ps.VsLeague != -0.375) {
+			t.Errorf("TE strength = %+v, want weak at -0.375 vs league", ps)
+		}
+		if ps.Position == "RB" && (ps.Weak || ps.Starters != 2 || ps.AvgProjected != 14) {
+			t.Errorf("RB strength = %+v, want 2 starters averaging 14, not weak", ps)
+		}
+	}
+
+	want := []ByeGap{
+		{Week: 12, Position: "RB", Starters: 2, Available: 0, OnBye: []string{"Brian Robinson", "Kyren Williams"}},
+	}
+	if !reflect.DeepEqual(analysis.ByeGaps, want) {
+		t.Errorf("ByeGaps = %+v, want %+v", analysis.ByeGaps, want)
+	}
+}
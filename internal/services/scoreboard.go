@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ScoreboardGame is the compact view of a game used by the scoreboard
+type ScoreboardGame struct {
+	GameID    string    `json:"game_id" bson:"game_id"`
+	AwayTeam  string    `json:"away_team" bson:"away_team"`
+	HomeTeam  string    `json:"home_team" bson:"home_team"`
+	AwayScore int       `json:"away_score" bson:"away_score"`
+	HomeScore int       `json:"home_score" bson:"home_score"`
+	Status    string    `json:"status" bson:"status"`
+	StartTime time.Time `json:"start_time" bson:"start_time"`
+	Quarter   int       `json:"quarter,omitempty" bson:"-"` // Only set while a game is live
+}
+
+// GetScoreboard lists a week's games in kickoff order with just teams, scores, status and
+// start time. A live game's quarter comes from the latest play loaded for it; when no plays
+// have been loaded yet there is no live data, so the game falls back to final if it has a
+// score and scheduled if it doesn't.
+func (s *DataService) GetScoreboard(ctx context.Context, season int, week int) ([]ScoreboardGame, error) {
+	filter := bson.M{"season": season, "week": week}
+	opts := options.Find().
+		SetSort(bson.D{{"start_time", 1}, {"game_id", 1}}).
+		SetProjection(bson.M{"_id": 0, "game_id": 1, "away_team": 1, "home_team": 1, "away_score": 1, "home_score": 1, "status": 1, "start_time": 1})
+
+	cursor, err := s.db.Collection("games").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch games: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	games := []ScoreboardGame{}
+	if err := cursor.All(ctx, &games); err != nil {
+		return nil, fmt.Errorf("failed to decode games: %w", err)
+	}
+
+	var liveIDs []string
+	for _, game := range games {
+		if game.Status == "live" {
+			liveIDs = append(liveIDs, game.GameID)
+		}
+	}
+	if len(liveIDs) == 0 {
+		return games, nil
+	}
+
+	quarters, err := s.liveQuarters(ctx, liveIDs)
+	if err != nil {
+		return nil, err
+	}
+	applyLiveQuarters(games, quarters)
+
+	return games, nil
+}
+
+// applyLiveQuarters sets each live game's quarter from quarters, falling back to final or
+// scheduled for live games without plays
+func applyLiveQuarters(games []ScoreboardGame, quarters map[string]int) {
+	for i := range games {
+		if games[i].Status != "live" {
+			continue
+		}
+		if quarter, ok := quarters[games[i].GameID]; ok {
+			games[i].Quarter = quarter
+		} else if games[i].HomeScore > 0 || games[i].AwayScore > 0 {
+			games[i].Status = "final"
+		} else {
+			games[i].Status = "scheduled"
+		}
+	}
+}
+
+// liveQuarters returns the quarter of the latest loaded play for each game
+func (s *DataService) liveQuarters(ctx context.Context, gameIDs []string) (map[string]int, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"game_id": bson.M{"$in": gameIDs}}},
+		{"$group": bson.M{"_id": "$game_id", "quarter": bson.M{"$max": "$quarter"}}},
+	}
+
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch live game plays: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		GameID  string `bson:"_id"`
+		Quarter int    `bson:"quarter"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode live game plays: %w", err)
+	}
+
+	quarters := make(map[string]int, len(results))
+	for _, r := range results {
+		quarters[r.GameID] = r.Quarter
+	}
+	return quarters, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestScoreboardCompactProjection(t *testing.T) {
+	kickoff := time.Date(2025, 11, 16, 18, 0, 0, 0, time.UTC)
+	seeded := []models.Game{
+		{GameID: "2025_11_KC_DEN", Season: 2025, Week: 11, AwayTeam: "KC", HomeTeam: "DEN", StartTime: kickoff,
+			Status: "final", AwayScore: 19, HomeScore: 22, VegasLine: -3.5, OverUnder: 43.5},
+		{GameID: "2025_11_TB_BUF", Season: 2025, Week: 11, AwayTeam: "TB", HomeTeam: "BUF", StartTime: kickoff,
+			Status: "live", AwayScore: 7, HomeScore: 10},
+		{GameID: "2025_11_SEA_LA", Season: 2025, Week: 11, AwayTeam: "SEA", HomeTeam: "LA", StartTime: kickoff.Add(3 * time.Hour),
+			Status: "live", AwayScore: 3},
+		{GameID: "2025_11_DAL_LV", Season: 2025, Week: 11, AwayTeam: "DAL", HomeTeam: "LV", StartTime: kickoff.Add(26 * time.Hour),
+			Status: "live"},
+	}
+
+	// Decode the stored documents the way the games query does
+	games := make([]ScoreboardGame, len(seeded))
+	for i, g := range seeded {
+		raw, err := bson.Marshal(g)
+		if err != nil {
+			t.Fatalf("marshal game: %v", err)
+		}
+		if err := bson.Unmarshal(raw, &games[i]); err != nil {
+			t.Fatalf("unmarshal scoreboard game: %v", err)
+		}
+	}
+
+	// Only TB @ BUF has plays loaded; the others have no live data
+	applyLiveQuarters(games, map[string]int{"2025_11_TB_BUF": 3})
+
+	want := []ScoreboardGame{
+		{GameID: "2025_11_KC_DEN", AwayTeam: "KC", HomeTeam: "DEN", AwayScore: 19, HomeScore: 22, Status: "final", StartTime: kickoff},
+		{GameID: "2025_11_TB_BUF", AwayTeam: "TB", HomeTeam: "BUF", AwayScore: 7, HomeScore: 10, Status: "live", StartTime: kickoff, Quarter: 3},
+		{GameID: "2025_11_SEA_LA", AwayTeam: "SEA", HomeTeam: "LA", AwayScore: 3, Status: "final", StartTime: kickoff.Add(3 * time.Hour)},
+		{GameID: "2025_11_DAL_LV", AwayTeam: "DAL", HomeTeam: "LV", Status: "scheduled", StartTime: kickoff.Add(26 * time.Hour)},
+	}
+	for i := range want {
+		if !games[i].StartTime.Equal(want[i].StartTime) {
+			t.Errorf("games[%d].StartTime = %v, want %v", i, games[i].StartTime, want[i].StartTime)
+		}
+		games[i].StartTime = want[i].StartTime
+	}
+	if !reflect.DeepEqual(games, want) {
+		t.Errorf("scoreboard = %+v, want %+v", games, want)
+	}
+
+	// The response carries only the compact fields; quarter only while live
+	var fields map[string]any
+	body, _ := json.Marshal(games[0])
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatal(err)
+	}
+	wantFields := []string{"away_score", "away_team", "game_id", "home_score", "home_team", "start_time", "status"}
+	if len(fields) != len(wantFields) {
+		t.Errorf("final game JSON = %s, want only %v", body, wantFields)
+	}
+	for _, f := range wantFields {
+		if _, ok := fields[f]; !ok {
+			t.Errorf("final game JSON %s is missing %s", body, f)
+		}
+	}
+	live, _ := json.Marshal(games[1])
+	if q := quarterOf(t, live); q != 3.0 {
+		t.Errorf("live game JSON = %s, want quarter 3", live)
+	}
+}
+
+// quarterOf returns the quarter field of a game's JSON, nil when it is omitted
+func quarterOf(t *testing.T, body []byte) any {
+	t.Helper()
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatal(err)
+	}
+	return fields["quarter"]
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ScoringConfig is a league's scoring settings
+type ScoringConfig struct {
+	Name         string  `json:"name"`
+	PassYard     float64 `json:"pass_yard"`
+	PassTD       float64 `json:"pass_td"`
+	Interception float64 `json:"interception"` // Negative
+	RushYard     float64 `json:"rush_yard"`
+	RushTD       float64 `json:"rush_td"`
+	Reception    float64 `json:"reception"`
+	RecYard      float64 `json:"rec_yard"`
+	RecTD        float64 `json:"rec_td"`
+
+	Kicking KickingScoring `json:"kicking"`
+	Defense DefenseScoring `json:"defense"`
+}
+
+// KickingScoring scores kickers by field goal distance and extra points
+type KickingScoring struct {
+	FG0To39   float64 `json:"fg_0_39"`
+	FG40To49  float64 `json:"fg_40_49"`
+	FG50Plus  float64 `json:"fg_50_plus"`
+	FGMissed  float64 `json:"fg_missed"` // Negative
+	PATMade   float64 `json:"pat_made"`
+	PATMissed float64 `json:"pat_missed"` // Negative
+}
+
+// DefenseScoring scores team defense/special teams on turnovers, scores and points allowed
+type DefenseScoring struct {
+	Sack           float64             `json:"sack"`
+	Interception   float64             `json:"interception"`
+	FumbleRecovery float64             `json:"fumble_recovery"`
+	Safety         float64             `json:"safety"`
+	Touchdown      float64             `json:"touchdown"` // Defensive and return touchdowns
+	PointsAllowed  []PointsAllowedTier `json:"points_allowed"`
+}
+
+// PointsAllowedTier awards Points when a defense allows at most MaxAllowed points. Tiers are
+// checked in order; a defense past the last tier scores that tier's points.
+type PointsAllowedTier struct {
+	MaxAllowed int     `json:"max_allowed"`
+	Points     float64 `json:"points"`
+}
+
+// ESPN's standard kicker and D/ST scoring, shared by every preset
+var (
+	standardKicking = KickingScoring{FG0To39: 3, FG40To49: 4, FG50Plus: 5, FGMissed: -1, PATMade: 1, PATMissed: -1}
+	standardDefense = DefenseScoring{
+		Sack: 1, Interception: 2, FumbleRecovery: 2, Safety: 2, Touchdown: 6,
+		PointsAllowed: []PointsAllowedTier{
+			{MaxAllowed: 0, Points: 5},
+			{MaxAllowed: 6, Points: 4},
+			{MaxAllowed: 13, Points: 3},
+			{MaxAllowed: 17, Points: 1},
+			{MaxAllowed: 27, Points: 0},
+			{MaxAllowed: 34, Points: -1},
+			{MaxAllowed: 45, Points: -3},
+			{MaxAllowed: 46, Points: -5},
+		},
+	}
+)
+
+// Scoring presets. Yardage and touchdown values match NFLverse's fantasy_points columns;
+// the presets differ only in points per reception.
+var (
+	ScoringStandard = ScoringConfig{Name: "standard", PassYard: 0.04, PassTD: 4, Interception: -2, RushYard: 0.1, RushTD: 6, Reception: 0, RecYard: 0.1, RecTD: 6, Kicking: standardKicking, Defense: standardDefense}
+	ScoringHalfPPR  = ScoringConfig{Name: "half_ppr", PassYard: 0.04, PassTD: 4, Interception: -2, RushYard: 0.1, RushTD: 6, Reception: 0.5, RecYard: 0.1, RecTD: 6, Kicking: standardKicking, Defense: standardDefense}
+	ScoringPPR      = ScoringConfig{Name: "ppr", PassYard: 0.04, PassTD: 4, Interception: -2, RushYard: 0.1, RushTD: 6, Reception: 1, RecYard: 0.1, RecTD: 6, Kicking: standardKicking, Defense: standardDefense}
+)
+
+// ScoringPreset looks up a preset by name: standard, half_ppr (or half) and ppr
+func ScoringPreset(name string) (ScoringConfig, bool) {
+	switch strings.ToLower(name) {
+	case "standard":
+		return ScoringStandard, true
+	case "half_ppr", "half":
+		return ScoringHalfPPR, true
+	case "ppr":
+		return ScoringPPR, true
+	}
+	return ScoringConfig{}, false
+}
+
+// StatLine is one game's offensive production
+type StatLine struct {
+	PassingYards   int
+	PassingTDs     int
+	Interceptions  int
+	RushingYards   int
+	RushingTDs     int
+	Receptions     int
+	ReceivingYards int
+	ReceivingTDs   int
+}
+
+// Points scores a stat line
+func (c ScoringConfig) Points(line StatLine) float64 {
+	return float64(line.PassingYards)*c.PassYard +
+		float64(line.PassingTDs)*c.PassTD +
+		float64(line.Interceptions)*c.Interception +
+		float64(line.RushingYards)*c.RushYard +
+		float64(line.RushingTDs)*c.RushTD +
+		float64(line.Receptions)*c.Reception +
+		float64(line.ReceivingYards)*c.RecYard +
+		float64(line.ReceivingTDs)*c.RecTD
+}
+
+// WeeklyStatLine is the scoring stat line of a player_weekly_stats row
+func WeeklyStatLine(w models.WeeklyStat) StatLine {
+	return StatLine{
+		PassingYards:   w.PassingYards,
+		PassingTDs:     w.PassingTDs,
+		Interceptions:  w.Interceptions,
+		RushingYards:   w.RushingYards,
+		RushingTDs:     w.RushingTDs,
+		Receptions:     w.Receptions,
+		ReceivingYards: w.ReceivingYards,
+		ReceivingTDs:   w.ReceivingTDs,
+	}
+}
+
+// KickerPoints scores a kicker's week
+func (c ScoringConfig) KickerPoints(k models.KickerStats) float64 {
+	return float64(k.FGMade0To39)*c.Kicking.FG0To39 +
+		float64(k.FGMade40To49)*c.Kicking.FG40To49 +
+		float64(k.FGMade50Plus)*c.Kicking.FG50Plus +
+		float64(k.FGMissed)*c.Kicking.FGMissed +
+		float64(k.PATMade)*c.Kicking.PATMade +
+		float64(k.PATMissed)*c.Kicking.PATMissed
+}
+
+// DefensePoints scores a team defense/special teams week, including its points-allowed tier
+func (c ScoringConfig) DefensePoints(d models.DefenseStats) float64 {
+	points := d.Sacks*c.Defense.Sack +
+		float64(d.Interceptions)*c.Defense.Interception +
+		float64(d.FumbleRecoveries)*c.Defense.FumbleRecovery +
+		float64(d.Safeties)*c.Defense.Safety +
+		float64(d.DefensiveTDs+d.SpecialTeamsTDs)*c.Defense.Touchdown
+	return points + c.Defense.pointsAllowed(d.PointsAllowed)
+}
+
+// pointsAllowed returns the tier points for a defense that allowed the given points
+func (d DefenseScoring) pointsAllowed(allowed int) float64 {
+	for _, tier := range d.PointsAllowed {
+		if allowed <= tier.MaxAllowed {
+			return tier.Points
+		}
+	}
+	if n := len(d.PointsAllowed); n > 0 {
+		return d.PointsAllowed[n-1].Points
+	}
+	return 0
+}
+
+// pointsExpr is an aggregation expression for a player_weekly_stats document's points under
+// the preset. Standard and PPR read the stored columns; other reception values are applied
+// on top of the standard points.
+func (c ScoringConfig) pointsExpr() interface{} {
+	switch c.Reception {
+	case 0:
+		return "$fantasy_points"
+	case 1:
+		return "$fantasy_points_ppr"
+	}
+	return bson.M{"$add": []interface{}{
+		"$fantasy_points",
+		bson.M{"$multiply": []interface{}{c.Reception, bson.M{"$ifNull": []interface{}{"$receptions", 0}}}},
+	}}
+}
+
+// statPointsExpr is an aggregation expression scoring a player_weekly_stats document's raw
+// passing, rushing and receiving stats with the config, matching Points
+func (c ScoringConfig) statPointsExpr() interface{} {
+	weighted := func(field string, points float64) bson.M {
+		return bson.M{"$multiply": []interface{}{points, bson.M{"$ifNull": []interface{}{"$" + field, 0}}}}
+	}
+	return bson.M{"$add": []interface{}{
+		weighted("passing_yards", c.PassYard),
+		weighted("passing_tds", c.PassTD),
+		weighted("interceptions", c.Interception),
+		weighted("rushing_yards", c.RushYard),
+		weighted("rushing_tds", c.RushTD),
+		weighted("receptions", c.Reception),
+		weighted("receiving_yards", c.RecYard),
+		weighted("receiving_tds", c.RecTD),
+	}}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestPointsAcrossPresets(t *testing.T) {
+	// 250 pass yds, 2 pass TD, 1 INT, 30 rush yds, 6 rec, 80 rec yds, 1 rec TD
+	line := StatLine{
+		PassingYards:   250,
+		PassingTDs:     2,
+		Interceptions:  1,
+		RushingYards:   30,
+		Receptions:     6,
+		ReceivingYards: 80,
+		ReceivingTDs:   1,
+	}
+	const standard = 10 + 8 - 2 + 3 + 8 + 6
+
+	tests := []struct {
+		scoring ScoringConfig
+		want    float64
+	}{
+		{ScoringStandard, standard},
+		{ScoringHalfPPR, standard + 3},
+		{ScoringPPR, standard + 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scoring.Name, func(t *testing.T) {
+			if got := tt.scoring.Points(line); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Points() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The presets differ only by points per reception
+	if diff := ScoringPPR.Points(line) - ScoringHalfPPR.Points(line); math.Abs(diff-0.5*float64(line.Receptions)) > 1e-9 {
+		t.Errorf("PPR - half PPR = %v, want %v", diff, 0.5*float64(line.Receptions))
+	}
+}
+
+func TestScoringPreset(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"standard", "standard", true},
+		{"half", "half_ppr", true},
+		{"HALF_PPR", "half_ppr", true},
+		{"ppr", "ppr", true},
+		{"superflex", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ScoringPreset(tt.name)
+		if ok != tt.wantOK || got.Name != tt.want {
+			t.Errorf("ScoringPreset(%q) = %q, %v; want %q, %v", tt.name, got.Name, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestProjectionsWithScoring(t *testing.T) {
+	trailing := NewTrailingAverageProjections(nil)
+	scored, ok := projectionsWithScoring(trailing, ScoringStandard).(*TrailingAverageProjections)
+	if !ok || scored.scoring.Name != "standard" {
+		t.Fatalf("projectionsWithScoring(trailing) = %+v, want a standard-scored copy", scored)
+	}
+	if trailing.scoring.Name != "ppr" {
+		t.Errorf("original source scoring = %q, want it left at ppr", trailing.scoring.Name)
+	}
+
+	espn := NewESPNProjections(nil, nil, trailing)
+	rescored := projectionsWithScoring(espn, ScoringHalfPPR).(*ESPNProjections)
+	if fallback := rescored.fallback.(*TrailingAverageProjections); fallback.scoring.Name != "half_ppr" {
+		t.Errorf("ESPN fallback scoring = %q, want half_ppr", fallback.scoring.Name)
+	}
+
+	if got := projectionsWithScoring(fixedProjections(3), ScoringStandard); got != fixedProjections(3) {
+		t.Errorf("projectionsWithScoring(unscored) = %v, want it unchanged", got)
+	}
+}
+
+func TestDefensePointsAllowedTiers(t *testing.T) {
+	tests := []struct {
+		allowed int
+		want    float64
+	}{
+		{0, 5}, {1, 4}, {6, 4}, {7, 3}, {13, 3}, {14, 1}, {17, 1},
+		{18, 0}, {27, 0}, {28, -1}, {34, -1}, {35, -3}, {45, -3}, {46, -5}, {60, -5},
+	}
+	for _, tt := range tests {
+		if got := ScoringPPR.DefensePoints(models.DefenseStats{PointsAllowed: tt.allowed}); got != tt.want {
+			t.Errorf("DefensePoints(%d allowed) = %v, want %v", tt.allowed, got, tt.want)
+		}
+	}
+
+	// 3.5 sacks, 2 INT, 1 fumble recovery, 1 safety, a pick-six and a punt return TD, 10 allowed
+	d := models.DefenseStats{Sacks: 3.5, Interceptions: 2, FumbleRecoveries: 1, Safeties: 1, DefensiveTDs: 1, SpecialTeamsTDs: 1, PointsAllowed: 10}
+	for _, scoring := range []ScoringConfig{ScoringStandard, ScoringHalfPPR, ScoringPPR} {
+		if got := scoring.DefensePoints(d); got != 26.5 {
+			t.Errorf("%s DefensePoints() = %v, want 26.5", scoring.Name, got)
+		}
+	}
+}
+
+func TestKickerPoints(t *testing.T) {
+	tests := []struct {
+		name string
+		line models.KickerStats
+		want float64
+	}{
+		{"no attempts", models.KickerStats{}, 0},
+		{"field goals by distance", models.KickerStats{FGMade0To39: 2, FGMade40To49: 1, FGMade50Plus: 1}, 15},
+		{"misses count against", models.KickerStats{FGMade0To39: 1, FGMissed: 2, PATMade: 3, PATMissed: 1}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScoringPPR.KickerPoints(tt.line); got != tt.want {
+				t.Errorf("KickerPoints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecialTeamsProjections(t *testing.T) {
+	// Most recent first; only the last two weeks count with a window of 2
+	kickers := []models.KickerStats{
+		{NFLID: "00-0033553", Week: 9, FGMade0To39: 2, PATMade: 2},  // 8
+		{NFLID: "00-0033553", Week: 8, FGMade50Plus: 2, PATMade: 1}, // 11
+		{NFLID: "00-0033553", Week: 7, FGMissed: 3},                 // ignored
+	}
+	defenses := []models.DefenseStats{
+		{Team: "BAL", Week: 9, Sacks: 4, PointsAllowed: 3},          // 8
+		{Team: "BAL", Week: 8, Interceptions: 1, PointsAllowed: 24}, // 2
+	}
+
+	got := specialTeamsProjections(kickers, defenses, ScoringPPR, 2)
+	if got["00-0033553"] != 9.5 || got["BAL"] != 5 || len(got) != 2 {
+		t.Errorf("specialTeamsProjections() = %v, want kicker 9.5 and BAL 5", got)
+	}
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// similarityFeatures names the usage and efficiency features compared, in vector order
+var similarityFeatures = []string{"snap_pct", "target_share", "adot", "epa_per_play", "yac_over_expected"}
+
+// PlayerProfile is a player's usage and efficiency for a season
+type PlayerProfile struct {
+	NFLID           string  `json:"nfl_id"`
+	Name            string  `json:"name"`
+	Team            string  `json:"team"`
+	Position        string  `json:"position"`
+	SnapPct         float64 `json:"snap_pct"`
+	TargetShare     float64 `json:"target_share"`
+	ADOT            float64 `json:"adot"`
+	EPA             float64 `json:"epa_per_play"`
+	YACOverExpected float64 `json:"yac_over_expected"`
+}
+
+func (p PlayerProfile) vector() []float64 {
+	return []float64{p.SnapPct, p.TargetShare, p.ADOT, p.EPA, p.YACOverExpected}
+}
+
+// SimilarPlayer is a comparable player and how closely their profile matches
+type SimilarPlayer struct {
+	PlayerProfile
+	Similarity float64 `json:"similarity"` // Cosine similarity of standardized profiles, -1 to 1
+}
+
+// SimilarPlayers lists the closest comps for a player
+type SimilarPlayers struct {
+	Player   PlayerProfile   `json:"player"`
+	Season   int             `json:"season"`
+	Features []string        `json:"features"`
+	PoolSize int             `json:"pool_size"`
+	Similar  []SimilarPlayer `json:"similar"`
+}
+
+// SimilarPlayers finds the players at the same position whose season usage and efficiency
+// most resemble the given player's. Each player is profiled by snap %, target share, aDOT,
+// EPA per play and NGS YAC over expected; features are standardized across the position's
+// qualified players (minEPAPercentilePlays) so no single scale dominates, then ranked by
+// cosine similarity.
+func (s *InsightService) SimilarPlayers(ctx context.Context, nflID string, season, limit int) (*SimilarPlayers, error) {
+	player, err := s.data.GetPlayer(ctx, nflID, season)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("player %s has no %d roster entry: %w", nflID, season, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+
+	profiles, err := s.positionProfiles(ctx, player.Position, season, nflID)
+	if err != nil {
+		return nil, err
+	}
+
+	targetIdx := -1
+	for i, p := range profiles {
+		if p.NFLID == nflID {
+			targetIdx = i
+			break
+		}
+	}
+	if targetIdx < 0 {
+		return nil, fmt.Errorf("player %s has no %d stats: %w", nflID, season, mongo.ErrNoDocuments)
+	}
+
+	return rankSimilar(profiles, targetIdx, season, limit), nil
+}
+
+// rankSimilar compares every profile to the one at targetIdx and keeps the closest limit
+func rankSimilar(profiles []PlayerProfile, targetIdx, season, limit int) *SimilarPlayers {
+	vectors := standardize(profiles)
+	result := &SimilarPlayers{
+		Player:   profiles[targetIdx],
+		Season:   season,
+		Features: similarityFeatures,
+		PoolSize: len(profiles),
+		Similar:  []SimilarPlayer{},
+	}
+	for i, p := range profiles {
+		if i == targetIdx {
+			continue
+		}
+		result.Similar = append(result.Similar, SimilarPlayer{
+			PlayerProfile: p,
+			Similarity:    cosineSimilarity(vectors[targetIdx], vectors[i]),
+		})
+	}
+
+	sort.Slice(result.Similar, func(i, j int) bool { return result.Similar[i].Similarity > result.Similar[j].Similarity })
+	if len(result.Similar) > limit {
+		result.Similar = result.Similar[:limit]
+	}
+	return result
+}
+
+// positionProfiles builds profiles for the position's qualified players in a season. The
+// player being compared is always included, even below the play minimum.
+func (s *InsightService) positionProfiles(ctx context.Context, position string, season int, include string) ([]PlayerProfile, error) {
+	playerCursor, err := s.db.Collection("players").Find(ctx, bson.M{"position": position, "season": season},
+		options.Find().SetProjection(bson.M{"nfl_id": 1, "name": 1, "team": 1, "position": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s players: %w", position, err)
+	}
+	var players []models.Player
+	err = playerCursor.All(ctx, &players)
+	playerCursor.Close(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s players: %w", position, err)
+	}
+
+	nflIDs := make([]string, len(players))
+	for i, p := range players {
+		nflIDs[i] = p.NFLID
+	}
+
+	statsCursor, err := s.db.Collection("player_stats").Find(ctx, bson.M{
+		"nfl_id":      bson.M{"$in": nflIDs},
+		"season":      season,
+		"season_type": models.SeasonTypeCombined,
+		"$or": []bson.M{
+			{"play_count": bson.M{"$gte": minEPAPercentilePlays}},
+			{"nfl_id": include},
+		},
+	}, options.Find().SetProjection(bson.M{"nfl_id": 1, "epa": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s stats: %w", position, err)
+	}
+	var stats []models.PlayerStats
+	err = statsCursor.All(ctx, &stats)
+	statsCursor.Close(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s stats: %w", position, err)
+	}
+
+	byID := make(map[string]models.Player, len(players))
+	for _, p := range players {
+		byID[p.NFLID] = p
+	}
+	profiles := make([]PlayerProfile, 0, len(stats))
+	pool := make([]string, 0, len(stats))
+	for _, st := range stats {
+		p := byID[st.NFLID]
+		profiles = append(profiles, PlayerProfile{
+			NFLID:    st.NFLID,
+			Name:     p.Name,
+			Team:     p.Team,
+			Position: p.Position,
+			EPA:      st.EPA,
+		})
+		pool = append(pool, st.NFLID)
+	}
+	if len(profiles) == 0 {
+		return profiles, nil
+	}
+
+	snaps, err := s.averageSnapPct(ctx, pool, season)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := s.targetProfiles(ctx, pool, season)
+	if err != nil {
+		return nil, err
+	}
+	yacoe, err := s.yacOverExpected(ctx, pool, season)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range profiles {
+		id := profiles[i].NFLID
+		profiles[i].SnapPct = snaps[id]
+		profiles[i].TargetShare = targets[id].share
+		profiles[i].ADOT = targets[id].adot
+		profiles[i].YACOverExpected = yacoe[id]
+	}
+	return profiles, nil
+}
+
+// averageSnapPct averages each player's weekly offensive snap share
+func (s *InsightService) averageSnapPct(ctx context.Context, nflIDs []string, season int) (map[string]float64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"nfl_id": bson.M{"$in": nflIDs}, "season": season}}},
+		{{Key: "$group", Value: bson.M{"_id": "$nfl_id", "snap_pct": bson.M{"$avg": "$offense_pct"}}}},
+	}
+	cursor, err := s.db.Collection("snap_counts").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate snap shares: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		NFLID   string  `bson:"_id"`
+		SnapPct float64 `bson:"snap_pct"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode snap shares: %w", err)
+	}
+
+	snaps := make(map[string]float64, len(results))
+	for _, r := range results {
+		snaps[r.NFLID] = r.SnapPct
+	}
+	return snaps, nil
+}
+
+type targetProfile struct {
+	share float64
+	adot  float64
+}
+
+// targetProfiles computes each player's share of their team's pass attempts and their aDOT
+func (s *InsightService) targetProfiles(ctx context.Context, nflIDs []string, season int) (map[string]targetProfile, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"season":             season,
+			"play_type":          "pass",
+			"receiver_player_id": bson.M{"$in": nflIDs},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$receiver_player_id",
+			"team":      bson.M{"$last": "$possession_team"},
+			"targets":   bson.M{"$sum": 1},
+			"air_yards": bson.M{"$sum": "$air_yards"},
+		}}},
+	}
+	cursor, err := s.db.Collection("plays").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate targets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var receivers []struct {
+		NFLID    string `bson:"_id"`
+		Team     string `bson:"team"`
+		Targets  int    `bson:"targets"`
+		AirYards int    `bson:"air_yards"`
+	}
+	if err := cursor.All(ctx, &receivers); err != nil {
+		return nil, fmt.Errorf("failed to decode targets: %w", err)
+	}
+
+	teamPipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"season": season, "play_type": "pass"}}},
+		{{Key: "$group", Value: bson.M{"_id": "$possession_team", "attempts": bson.M{"$sum": 1}}}},
+	}
+	teamCursor, err := s.db.Collection("plays").Aggregate(ctx, teamPipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate team pass attempts: %w", err)
+	}
+	defer teamCursor.Close(ctx)
+
+	var teams []struct {
+		Team     string `bson:"_id"`
+		Attempts int    `bson:"attempts"`
+	}
+	if err := teamCursor.All(ctx, &teams); err != nil {
+		return nil, fmt.Errorf("failed to decode team pass attempts: %w", err)
+	}
+	attempts := make(map[string]int, len(teams))
+	for _, t := range teams {
+		attempts[t.Team] = t.Attempts
+	}
+
+	profiles := make(map[string]targetProfile, len(receivers))
+	for _, r := range receivers {
+		var p targetProfile
+		if a := attempts[r.Team]; a > 0 {
+			p.share = float64(r.Targets) / float64(a)
+		}
+		if r.Targets > 0 {
+			p.adot = float64(r.AirYards) / float64(r.Targets)
+		}
+		profiles[r.NFLID] = p
+	}
+	return profiles, nil
+}
+
+// yacOverExpected reads each player's season NGS receiving YAC above expectation
+func (s *InsightService) yacOverExpected(ctx context.Context, nflIDs []string, season int) (map[string]float64, error) {
+	cursor, err := s.db.Collection("next_gen_stats").Find(ctx, bson.M{
+		"player_id": bson.M{"$in": nflIDs},
+		"season":    season,
+		"week":      0,
+		"stat_type": "receiving",
+	}, options.Find().SetProjection(bson.M{"player_id": 1, "avg_yac_above_expectation": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch NGS receiving: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var ngs []models.NextGenStat
+	if err := cursor.All(ctx, &ngs); err != nil {
+		return nil, fmt.Errorf("failed to decode NGS receiving: %w", err)
+	}
+
+	yacoe := make(map[string]float64, len(ngs))
+	for _, n := range ngs {
+		yacoe[n.PlayerID] = n.AvgYACAboveExpectation
+	}
+	return yacoe, nil
+}
+
+// standardize converts each feature to a z-score across the profiles. Features with no
+// spread (e.g. target share for QBs) become 0 and drop out of the comparison.
+func standardize(profiles []PlayerProfile) [][]float64 {
+	vectors := make([][]float64, len(profiles))
+	for i, p := range profiles {
+		vectors[i] = p.vector()
+	}
+
+	n := float64(len(vectors))
+	for f := range similarityFeatures {
+		var mean float64
+		for _, v := range vectors {
+			mean += v[f]
+		}
+		mean /= n
+
+		var variance float64
+		for _, v := range vectors {
+			variance += (v[f] - mean) * (v[f] - mean)
+		}
+		stdDev := math.Sqrt(variance / n)
+
+		for _, v := range vectors {
+			if stdDev == 0 {
+				v[f] = 0
+			} else {
+				v[f] = (v[f] - mean) / stdDev
+			}
+		}
+	}
+	return vectors
+}
+
+// cosineSimilarity is 0 when either vector is all zeros
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
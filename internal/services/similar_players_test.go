@@ -0,0 +1,65 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+// receivers is a pool of WR profiles: a deep threat, a near copy of them, a possession
+// slot receiver and a gadget player at the other end of every feature
+func receivers() []PlayerProfile {
+	return []PlayerProfile{
+		{NFLID: "deep", SnapPct: 0.92, TargetShare: 0.26, ADOT: 14.5, EPA: 0.31, YACOverExpected: 0.4},
+		{NFLID: "deep_copy", SnapPct: 0.90, TargetShare: 0.25, ADOT: 13.8, EPA: 0.28, YACOverExpected: 0.5},
+		{NFLID: "slot", SnapPct: 0.80, TargetShare: 0.22, ADOT: 7.0, EPA: 0.12, YACOverExpected: 1.8},
+		{NFLID: "gadget", SnapPct: 0.35, TargetShare: 0.08, ADOT: 2.0, EPA: -0.15, YACOverExpected: 2.4},
+	}
+}
+
+func TestRankSimilar(t *testing.T) {
+	result := rankSimilar(receivers(), 0, 2024, 10)
+
+	if result.Player.NFLID != "deep" || result.PoolSize != 4 || result.Season != 2024 {
+		t.Fatalf("result = %+v, want deep compared across a pool of 4 in 2024", result)
+	}
+	if len(result.Similar) != 3 {
+		t.Fatalf("got %d comps, want 3 with the player left out", len(result.Similar))
+	}
+
+	want := []string{"deep_copy", "slot", "gadget"}
+	for i, id := range want {
+		if result.Similar[i].NFLID != id {
+			t.Errorf("comp %d = %s, want %s", i+1, result.Similar[i].NFLID, id)
+		}
+	}
+	if top := result.Similar[0].Similarity; top < 0.95 {
+		t.Errorf("near copy similarity = %v, want close to 1", top)
+	}
+	if bottom := result.Similar[2].Similarity; bottom > -0.5 {
+		t.Errorf("opposite profile similarity = %v, want strongly negative", bottom)
+	}
+}
+
+func TestRankSimilarLimit(t *testing.T) {
+	result := rankSimilar(receivers(), 0, 2024, 1)
+
+	if len(result.Similar) != 1 || result.Similar[0].NFLID != "deep_copy" {
+		t.Errorf("limited comps = %+v, want only deep_copy", result.Similar)
+	}
+}
+
+func TestStandardizeDropsFlatFeatures(t *testing.T) {
+	// QBs have no target share, so the feature has no spread
+	profiles := []PlayerProfile{
+		{SnapPct: 1.0, EPA: 0.2},
+		{SnapPct: 0.5, EPA: 0.0},
+	}
+	vectors := standardize(profiles)
+
+	if vectors[0][1] != 0 || vectors[1][1] != 0 {
+		t.Errorf("target share z-scores = %v, %v, want 0 with no spread", vectors[0][1], vectors[1][1])
+	}
+	if math.Abs(vectors[0][0]-1) > 1e-9 || math.Abs(vectors[1][0]+1) > 1e-9 {
+		t.Errorf("snap pct z-scores = %v, %v, want 1 and -1", vectors[0][0], vectors[1][0])
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TeamRecord is a team's regular season record in the standings
+type TeamRecord struct {
+	Team           string  `json:"team"`
+	Name           string  `json:"name,omitempty"`
+	Conference     string  `json:"conference"`
+	Division       string  `json:"division"`
+	Wins           int     `json:"wins"`
+	Losses         int     `json:"losses"`
+	Ties           int     `json:"ties"`
+	WinPct         float64 `json:"win_pct"` // Ties count as half a win
+	PointsFor      int     `json:"points_for"`
+	PointsAgainst  int     `json:"points_against"`
+	PointDiff      int     `json:"point_diff"`
+	DivisionWins   int     `json:"division_wins"`
+	DivisionLosses int     `json:"division_losses"`
+	DivisionTies   int     `json:"division_ties"`
+}
+
+// DivisionStandings is one division's teams in standings order
+type DivisionStandings struct {
+	Division string       `json:"division"`
+	Teams    []TeamRecord `json:"teams"`
+}
+
+// ConferenceStandings is a conference's divisions plus every team in conference order
+type ConferenceStandings struct {
+	Conference string              `json:"conference"`
+	Divisions  []DivisionStandings `json:"divisions"`
+	Teams      []TeamRecord        `json:"teams"`
+}
+
+// Standings are the real NFL standings for a season through a week
+type Standings struct {
+	Season      int                   `json:"season"`
+	ThroughWeek int                   `json:"through_week"`
+	Conferences []ConferenceStandings `json:"conferences"`
+}
+
+// regularSeasonLength is the number of regular season weeks in a season: 18 since 2021,
+// 17 before
+func regularSeasonLength(season int) int {
+	if season >= 2021 {
+		return regularSeasonWeeks
+	}
+	return regularSeasonWeeks - 1
+}
+
+// GetStandings computes a season's division and conference standings from the final
+// regular season games through throughWeek (0 or past the regular season means all of it).
+// Conference and division come from the teams collection; a team missing from it is grouped
+// under an empty conference and division. Teams are ordered by win percentage, then point
+// differential, then abbreviation - tiebreakers like head-to-head aren't applied.
+func (s *DataService) GetStandings(ctx context.Context, season, throughWeek int) (*Standings, error) {
+	lastWeek := regularSeasonLength(season)
+	if throughWeek <= 0 || throughWeek > lastWeek {
+		throughWeek = lastWeek
+	}
+
+	cursor, err := s.db.Collection("games").Find(ctx, bson.M{
+		"season": season,
+		"week":   bson.M{"$gte": 1, "$lte": throughWeek},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch games: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var games []models.Game
+	if err := cursor.All(ctx, &games); err != nil {
+		return nil, fmt.Errorf("failed to decode games: %w", err)
+	}
+
+	teams, err := s.GetTeams(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch teams: %w", err)
+	}
+
+	standings := computeStandings(games, teams)
+	standings.Season = season
+	standings.ThroughWeek = throughWeek
+	return standings, nil
+}
+
+// computeStandings tallies records from final games and groups them by conference and
+// division. Every team on the schedule is listed, including those yet to play.
+func computeStandings(games []models.Game, teams []models.Team) *Standings {
+	info := make(map[string]models.Team, len(teams))
+	for _, t := range teams {
+		info[t.Abbr] = t
+	}
+
+	records := make(map[string]*TeamRecord)
+	record := func(abbr string) *TeamRecord {
+		r, ok := records[abbr]
+		if !ok {
+			t := info[abbr]
+			r = &TeamRecord{Team: abbr, Name: t.Name, Conference: t.Conference, Division: t.Division}
+			records[abbr] = r
+		}
+		return r
+	}
+
+	for _, g := range games {
+		home, away := record(g.HomeTeam), record(g.AwayTeam)
+		if g.Status != "final" {
+			continue
+		}
+
+		home.PointsFor += g.HomeScore
+		home.PointsAgainst += g.AwayScore
+		away.PointsFor += g.AwayScore
+		away.PointsAgainst += g.HomeScore
+
+		divisional := home.Division != "" && home.Division == away.Division
+		switch {
+		case g.HomeScore > g.AwayScore:
+			home.Wins++
+			away.Losses++
+			if divisional {
+				home.DivisionWins++
+				away.DivisionLosses++
+			}
+		case g.AwayScore > g.HomeScore:
+			away.Wins++
+			home.Losses++
+			if divisional {
+				away.DivisionWins++
+				home.DivisionLosses++
+			}
+		default:
+			home.Ties++
+			away.Ties++
+			if divisional {
+				home.DivisionTies++
+				away.DivisionTies++
+			}
+		}
+	}
+
+	conferences := make(map[string]map[string][]TeamRecord)
+	for _, r := range records {
+		if played := r.Wins + r.Losses + r.Ties; played > 0 {
+			r.WinPct = (float64(r.Wins) + 0.5*float64(r.Ties)) / float64(played)
+		}
+		r.PointDiff = r.PointsFor - r.PointsAgainst
+
+		if conferences[r.Conference] == nil {
+			conferences[r.Conference] = make(map[string][]TeamRecord)
+		}
+		conferences[r.Conference][r.Division] = append(conferences[r.Conference][r.Division], *r)
+	}
+
+	standings := &Standings{Conferences: []ConferenceStandings{}}
+	for _, conf := range sortedKeys(conferences) {
+		cs := ConferenceStandings{Conference: conf}
+		divisions := conferences[conf]
+		for _, div := range sortedKeys(divisions) {
+			teams := divisions[div]
+			sortStandings(teams)
+			cs.Divisions = append(cs.Divisions, DivisionStandings{Division: div, Teams: teams})
+			cs.Teams = append(cs.Teams, teams...)
+		}
+		sortStandings(cs.Teams)
+		standings.Conferences = append(standings.Conferences, cs)
+	}
+
+	return standings
+}
+
+// sortStandings orders records by win percentage, then point differential, then team
+func sortStandings(records []TeamRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.WinPct != b.WinPct {
+			return a.WinPct > b.WinPct
+		}
+		if a.PointDiff != b.PointDiff {
+			return a.PointDiff > b.PointDiff
+		}
+		return a.Team < b.Team
+	})
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestComputeStandings(t *testing.T) {
+	teams := []models.Team{
+		{Abbr: "KC", Name: "Kansas City Chiefs", Conference: "AFC", Division: "AFC West"},
+		{Abbr: "LV", Name: "Las Vegas Raiders", Conference: "AFC", Division: "AFC West"},
+		{Abbr: "BUF", Name: "Buffalo Bills", Conference: "AFC", Division: "AFC East"},
+		{Abbr: "MIA", Name: "Miami Dolphins", Conference: "AFC", Division: "AFC East"}, // Not on the schedule
+		{Abbr: "DAL", Name: "Dallas Cowboys", Conference: "NFC", Division: "NFC East"},
+	}
+	games := []models.Game{
+		{Week: 1, HomeTeam: "KC", AwayTeam: "LV", Status: "final", HomeScore: 27, AwayScore: 20},
+		{Week: 1, HomeTeam: "BUF", AwayTeam: "DAL", Status: "final", HomeScore: 24, AwayScore: 24},
+		{Week: 2, HomeTeam: "LV", AwayTeam: "KC", Status: "final", HomeScore: 30, AwayScore: 10},
+		{Week: 2, HomeTeam: "DAL", AwayTeam: "XXX", Status: "final", HomeScore: 20, AwayScore: 13},
+		{Week: 3, HomeTeam: "KC", AwayTeam: "BUF", Status: "live", HomeScore: 7, AwayScore: 0},
+		{Week: 4, HomeTeam: "BUF", AwayTeam: "KC", Status: "scheduled"},
+	}
+
+	standings := computeStandings(games, teams)
+
+	byTeam := make(map[string]TeamRecord)
+	for _, conf := range standings.Conferences {
+		for _, r := range conf.Teams {
+			byTeam[r.Team] = r
+		}
+	}
+
+	tests := []struct {
+		team                 string
+		wins, losses, ties   int
+		winPct               float64
+		pf, pa, diff         int
+		divW, divL, divT     int
+		conference, division string
+	}{
+		{"KC", 1, 1, 0, 0.5, 37, 50, -13, 1, 1, 0, "AFC", "AFC West"},
+		{"LV", 1, 1, 0, 0.5, 50, 37, 13, 1, 1, 0, "AFC", "AFC West"},
+		{"BUF", 0, 0, 1, 0.5, 24, 24, 0, 0, 0, 0, "AFC", "AFC East"},
+		{"DAL", 1, 0, 1, 0.75, 44, 37, 7, 0, 0, 0, "NFC", "NFC East"},
+		{"XXX", 0, 1, 0, 0, 13, 20, -7, 0, 0, 0, "", ""},
+	}
+	for _, tt := range tests {
+		r, ok := byTeam[tt.team]
+		if !ok {
+			t.Errorf("%s missing from standings", tt.team)
+			continue
+		}
+		if r.Wins != tt.wins || r.Losses != tt.losses || r.Ties != tt.ties || r.WinPct != tt.winPct {
+			t.Errorf("%s record = %d-%d-%d (%.3f), want %d-%d-%d (%.3f)", tt.team, r.Wins, r.Losses, r.Ties, r.WinPct, tt.wins, tt.losses, tt.ties, tt.winPct)
+		}
+		if r.PointsFor != tt.pf || r.PointsAgainst != tt.pa || r.PointDiff != tt.diff {
+			t.Errorf("%s points = %d/%d (%d), want %d/%d (%d)", tt.team, r.PointsFor, r.PointsAgainst, r.PointDiff, tt.pf, tt.pa, tt.diff)
+		}
+		if r.DivisionWins != tt.divW || r.DivisionLosses != tt.divL || r.DivisionTies != tt.divT {
+			t.Errorf("%s division record = %d-%d-%d, want %d-%d-%d", tt.team, r.DivisionWins, r.DivisionLosses, r.DivisionTies, tt.divW, tt.divL, tt.divT)
+		}
+		if r.Conference != tt.conference || r.Division != tt.division {
+			t.Errorf("%s grouped under %q/%q, want %q/%q", tt.team, r.Conference, r.Division, tt.conference, tt.division)
+		}
+	}
+	if _, ok := byTeam["MIA"]; ok {
+		t.Error("MIA listed without a game on the schedule")
+	}
+
+	var confs []string
+	for _, conf := range standings.Conferences {
+		confs = append(confs, conf.Conference)
+	}
+	if got, want := confs, []string{"", "AFC", "NFC"}; !slices.Equal(got, want) {
+		t.Errorf("conferences = %q, want %q", got, want)
+	}
+
+	afc := standings.Conferences[1]
+	if got, want := teamOrder(afc.Teams), []string{"LV", "BUF", "KC"}; !slices.Equal(got, want) {
+		t.Errorf("AFC order = %q, want %q (win pct tied, then point differential)", got, want)
+	}
+	if len(afc.Divisions) != 2 || afc.Divisions[0].Division != "AFC East" || afc.Divisions[1].Division != "AFC West" {
+		t.Fatalf("AFC divisions = %+v, want AFC East then AFC West", afc.Divisions)
+	}
+	if got, want := teamOrder(afc.Divisions[1].Teams), []string{"LV", "KC"}; !slices.Equal(got, want) {
+		t.Errorf("AFC West order = %q, want %q", got, want)
+	}
+}
+
+func TestComputeStandingsNoGames(t *testing.T) {
+	standings := computeStandings(nil, []models.Team{{Abbr: "KC", Conference: "AFC", Division: "AFC West"}})
+	if standings.Conferences == nil || len(standings.Conferences) != 0 {
+		t.Errorf("Conferences = %#v, want an empty list", standings.Conferences)
+	}
+}
+
+func teamOrder(records []TeamRecord) []string {
+	order := make([]string, len(records))
+	for i, r := range records {
+		order[i] = r.Team
+	}
+	return order
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// StreaksService finds players on hot or cold scoring runs from player_weekly_stats
+type StreaksService struct {
+	db *mongo.Database
+}
+
+func NewStreaksService(db *mongo.Database) *StreaksService {
+	return &StreaksService{db: db}
+}
+
+// StreakOptions controls what counts as a streak
+type StreakOptions struct {
+	MinGames  int     // Minimum consecutive games to report (default 3)
+	Threshold float64 // Fractional deviation from season average, e.g. 0.2 = 20% (default 0.2)
+}
+
+// PlayerStreak is an active hot or cold run ending with the player's most recent game
+type PlayerStreak struct {
+	NFLID        string    `json:"nfl_id"`
+	Name         string    `json:"name"`
+	Team         string    `json:"team"`
+	Position     string    `json:"position"`
+	Direction    string    `json:"direction"` // "hot" or "cold"
+	StreakLength int       `json:"streak_length"`
+	SeasonAvg    float64   `json:"season_avg"`
+	StreakAvg    float64   `json:"streak_avg"`
+	RecentPoints []float64 `json:"recent_points"` // PPR points for the streak games, oldest first
+	LastWeek     int       `json:"last_week"`
+}
+
+// FindStreaks returns active streaks for players at position ("" for all skill positions),
+// sorted by streak length and then by how far the streak deviates from the season average
+func (s *StreaksService) FindStreaks(ctx context.Context, position string, season int, opts StreakOptions) ([]PlayerStreak, error) {
+	if opts.MinGames <= 0 {
+		opts.MinGames = 3
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = 0.2
+	}
+
+	playerFilter := bson.M{"season": season}
+	if position != "" {
+		playerFilter["position"] = position
+	} else {
+		playerFilter["position"] = bson.M{"$in": []string{"QB", "RB", "WR", "TE"}}
+	}
+
+	cursor, err := s.db.Collection("players").Find(ctx, playerFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+	var players []models.Player
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, fmt.Errorf("failed to decode players: %w", err)
+	}
+
+	playersByID := make(map[string]models.Player, len(players))
+	ids := make([]string, 0, len(players))
+	for _, p := range players {
+		playersByID[p.NFLID] = p
+		ids = append(ids, p.NFLID)
+	}
+	if len(ids) == 0 {
+		return []PlayerStreak{}, nil
+	}
+
+	cursor, err = s.db.Collection("player_weekly_stats").Find(ctx, bson.M{
+		"season": season,
+		"nfl_id": bson.M{"$in": ids},
+	}, options.Find().SetSort(bson.D{{Key: "nfl_id", Value: 1}, {Key: "week", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch weekly stats: %w", err)
+	}
+	var weeklyStats []models.WeeklyStat
+	if err := cursor.All(ctx, &weeklyStats); err != nil {
+		return nil, fmt.Errorf("failed to decode weekly stats: %w", err)
+	}
+
+	weeksByPlayer := make(map[string][]models.WeeklyStat)
+	for _, w := range weeklyStats {
+		weeksByPlayer[w.NFLID] = append(weeksByPlayer[w.NFLID], w)
+	}
+
+	streaks := []PlayerStreak{}
+	for nflID, weeks := range weeksByPlayer {
+		streak, ok := detectStreak(weeks, opts)
+		if !ok {
+			continue
+		}
+		player := playersByID[nflID]
+		streak.NFLID = nflID
+		streak.Name = player.Name
+		streak.Team = player.Team
+		streak.Position = player.Position
+		streaks = append(streaks, streak)
+	}
+
+	sort.Slice(streaks, func(i, j int) bool {
+		if streaks[i].StreakLength != streaks[j].StreakLength {
+			return streaks[i].StreakLength > streaks[j].StreakLength
+		}
+		return math.Abs(streaks[i].StreakAvg-streaks[i].SeasonAvg) > math.Abs(streaks[j].StreakAvg-streaks[j].SeasonAvg)
+	})
+
+	return streaks, nil
+}
+
+// detectStreak walks back from the most recent week (weeks must be in week order) counting
+// consecutive games above (hot) or below (cold) the season average by the threshold
+func detectStreak(weeks []models.WeeklyStat, opts StreakOptions) (PlayerStreak, bool) {
+	// Need games outside the streak for the average to mean anything
+	if len(weeks) <= opts.MinGames {
+		return PlayerStreak{}, false
+	}
+
+	total := 0.0
+	for _, w := range weeks {
+		total += w.FantasyPointsPPR
+	}
+	avg := total / float64(len(weeks))
+	if avg <= 0 {
+		return PlayerStreak{}, false
+	}
+
+	hotLine := avg * (1 + opts.Threshold)
+	coldLine := avg * (1 - opts.Threshold)
+
+	last := weeks[len(weeks)-1].FantasyPointsPPR
+	var direction string
+	switch {
+	case last > hotLine:
+		direction = "hot"
+	case last < coldLine:
+		direction = "cold"
+	default:
+		return PlayerStreak{}, false
+	}
+
+	length := 0
+	for i := len(weeks) - 1; i >= 0; i-- {
+		pts := weeks[i].FantasyPointsPPR
+		if (direction == "hot" && pts > hotLine) || (direction == "cold" && pts < coldLine) {
+			length++
+			continue
+		}
+		break
+	}
+	if length < opts.MinGames {
+		return PlayerStreak{}, false
+	}
+
+	recent := make([]float64, 0, length)
+	streakTotal := 0.0
+	for _, w := range weeks[len(weeks)-length:] {
+		recent = append(recent, w.FantasyPointsPPR)
+		streakTotal += w.FantasyPointsPPR
+	}
+
+	return PlayerStreak{
+		Direction:    direction,
+		StreakLength: length,
+		SeasonAvg:    avg,
+		StreakAvg:    streakTotal / float64(length),
+		RecentPoints: recent,
+		LastWeek:     weeks[len(weeks)-1].Week,
+	}, true
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+// pprWeeks builds consecutive weeks of PPR points starting at week 1
+func pprWeeks(points ...float64) []models.WeeklyStat {
+	weeks := make([]models.WeeklyStat, len(points))
+	for i, p := range points {
+		weeks[i] = models.WeeklyStat{Week: i + 1, FantasyPointsPPR: p}
+	}
+	return weeks
+}
+
+func TestDetectStreak(t *testing.T) {
+	defaults := StreakOptions{MinGames: 3, Threshold: 0.2}
+
+	tests := []struct {
+		name      string
+		weeks     []models.WeeklyStat
+		opts      StreakOptions
+		direction string // "" for no streak
+		recent    []float64
+	}{
+		{"hot streak", pprWeeks(10, 10, 10, 20, 22, 25), defaults, "hot", []float64{20, 22, 25}},
+		{"cold streak", pprWeeks(20, 20, 20, 20, 8, 7, 6), defaults, "cold", []float64{8, 7, 6}},
+		{"streak too short", pprWeeks(10, 10, 10, 10, 22, 25), defaults, "", nil},
+		{"latest game near average", pprWeeks(10, 22, 25, 24, 18), defaults, "", nil},
+		{"higher threshold", pprWeeks(10, 10, 10, 20, 22, 25), StreakOptions{MinGames: 3, Threshold: 0.5}, "", nil},
+		{"shorter minimum", pprWeeks(10, 10, 10, 10, 22, 25), StreakOptions{MinGames: 2, Threshold: 0.2}, "hot", []float64{22, 25}},
+		{"too few games for an average", pprWeeks(5, 20, 22), defaults, "", nil},
+		{"never scored", pprWeeks(0, 0, 0, 0), defaults, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := detectStreak(tt.weeks, tt.opts)
+			if ok != (tt.direction != "") {
+				t.Fatalf("detectStreak() found = %v (%+v), want %v", ok, got, tt.direction != "")
+			}
+			if !ok {
+				return
+			}
+			if got.Direction != tt.direction || got.StreakLength != len(tt.recent) || !slices.Equal(got.RecentPoints, tt.recent) {
+				t.Errorf("detectStreak() = %s x%d %v, want %s x%d %v", got.Direction, got.StreakLength, got.RecentPoints, tt.direction, len(tt.recent), tt.recent)
+			}
+			if got.LastWeek != len(tt.weeks) {
+				t.Errorf("LastWeek = %d, want %d", got.LastWeek, len(tt.weeks))
+			}
+		})
+	}
+}
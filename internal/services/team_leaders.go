@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/teams"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// TeamLeader is the player leading a team in one stat
+type TeamLeader struct {
+	NFLID    string  `json:"nfl_id" bson:"nfl_id"`
+	Name     string  `json:"name" bson:"name"`
+	Position string  `json:"position" bson:"position"`
+	Value    float64 `json:"value" bson:"value"`
+}
+
+// TeamLeaders is a team's leader in each headline stat. A category is nil when no rostered
+// player has recorded any of that stat yet.
+type TeamLeaders struct {
+	Team             string      `json:"team"`
+	Season           int         `json:"season"`
+	PassingYards     *TeamLeader `json:"passing_yards"`
+	RushingYards     *TeamLeader `json:"rushing_yards"`
+	ReceivingYards   *TeamLeader `json:"receiving_yards"`
+	FantasyPointsPPR *TeamLeader `json:"fantasy_points_ppr"`
+}
+
+// teamLeaderStats are the player_stats fields TeamLeaders reports a leader for
+var teamLeaderStats = []string{"passing_yards", "rushing_yards", "receiving_yards", "fantasy_points_ppr"}
+
+// GetTeamLeaders finds a team's leader in passing, rushing and receiving yards and PPR
+// fantasy points for a regular season. The team's roster is joined to its players' season
+// stats and grouped in one pass; each category keeps the $max of a {value, nfl_id, ...}
+// document, which compares on value first. Rostered players without a stats row are skipped.
+func (s *DataService) GetTeamLeaders(ctx context.Context, team string, season int) (*TeamLeaders, error) {
+	team = teams.Normalize(team)
+	cursor, err := s.db.Collection("players").Aggregate(ctx, teamLeadersPipeline(team, season))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate team leaders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []map[string]TeamLeader
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode team leaders: %w", err)
+	}
+	return buildTeamLeaders(team, season, results), nil
+}
+
+// teamLeadersPipeline joins a team's roster to its regular-season stats and keeps the
+// leader of each category
+func teamLeadersPipeline(team string, season int) mongo.Pipeline {
+	group := bson.M{"_id": nil}
+	for _, stat := range teamLeaderStats {
+		group[stat] = bson.M{"$max": bson.D{
+			{Key: "value", Value: bson.M{"$ifNull": bson.A{"$stats." + stat, 0}}},
+			{Key: "nfl_id", Value: "$nfl_id"},
+			{Key: "name", Value: "$name"},
+			{Key: "position", Value: "$position"},
+		}}
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"team": team, "season": season}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "player_stats",
+			"let":  bson.M{"nfl_id": "$nfl_id"},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"$expr": bson.M{"$and": []bson.M{
+						{"$eq": []interface{}{"$nfl_id", "$$nfl_id"}},
+						{"$eq": []interface{}{"$season", season}},
+						{"$eq": []interface{}{"$season_type", models.SeasonTypeRegular}},
+					}},
+				}}},
+				{{Key: "$limit", Value: 1}},
+			},
+			"as": "stats",
+		}}},
+		{{Key: "$unwind", Value: "$stats"}},
+		{{Key: "$group", Value: group}},
+		{{Key: "$project", Value: bson.M{"_id": 0}}},
+	}
+}
+
+// buildTeamLeaders reads the grouped leaders, leaving out categories nobody has recorded
+func buildTeamLeaders(team string, season int, results []map[string]TeamLeader) *TeamLeaders {
+	leaders := &TeamLeaders{Team: team, Season: season}
+	if len(results) == 0 {
+		return leaders
+	}
+
+	leader := func(stat string) *TeamLeader {
+		l, ok := results[0][stat]
+		if !ok || l.Value <= 0 {
+			return nil
+		}
+		return &l
+	}
+	leaders.PassingYards = leader("passing_yards")
+	leaders.RushingYards = leader("rushing_yards")
+	leaders.ReceivingYards = leader("receiving_yards")
+	leaders.FantasyPointsPPR = leader("fantasy_points_ppr")
+	return leaders
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestTeamLeaders(t *testing.T) {
+	players := toDocs(t, []models.Player{
+		{NFLID: "qb", Season: 2024, Name: "Quarter Back", Team: "ATL", Position: "QB"},
+		{NFLID: "rb", Season: 2024, Name: "Running Back", Team: "ATL", Position: "RB"},
+		{NFLID: "wr", Season: 2024, Name: "Wide Out", Team: "ATL", Position: "WR"},
+		{NFLID: "te", Season: 2024, Name: "Tight End", Team: "ATL", Position: "TE"},
+		{NFLID: "other", Season: 2024, Name: "Other Team", Team: "TB", Position: "WR"},
+	})
+	stats := toDocs(t, []models.PlayerStats{
+		{NFLID: "qb", Season: 2024, SeasonType: models.SeasonTypeRegular, PassingYards: 3900, RushingYards: 180, FantasyPointsPPR: 270.5},
+		{NFLID: "rb", Season: 2024, SeasonType: models.SeasonTypeRegular, RushingYards: 1250, ReceivingYards: 310, FantasyPointsPPR: 241.2},
+		{NFLID: "wr", Season: 2024, SeasonType: models.SeasonTypeRegular, ReceivingYards: 1180, FantasyPointsPPR: 255.8},
+		// Playoff and prior-season rows don't count toward the regular season
+		{NFLID: "rb", Season: 2024, SeasonType: "POST", ReceivingYards: 2000, FantasyPointsPPR: 500},
+		{NFLID: "wr", Season: 2023, SeasonType: models.SeasonTypeRegular, PassingYards: 5000},
+		{NFLID: "other", Season: 2024, SeasonType: models.SeasonTypeRegular, ReceivingYards: 1600, FantasyPointsPPR: 320},
+	})
+
+	var results []map[string]TeamLeader
+	decodeDocs(t, runPipeline(t, teamLeadersPipeline("ATL", 2024), players, map[string][]bson.M{"player_stats": stats}), &results)
+	leaders := buildTeamLeaders("ATL", 2024, results)
+
+	tests := []struct {
+		category string
+		got      *TeamLeader
+		nflID    string
+		value    float64
+	}{
+		{"passing_yards", leaders.PassingYards, "qb", 3900},
+		{"rushing_yards", leaders.RushingYards, "rb", 1250},
+		{"receiving_yards", leaders.ReceivingYards, "wr", 1180},
+		{"fantasy_points_ppr", leaders.FantasyPointsPPR, "qb", 270.5},
+	}
+	for _, tt := range tests {
+		if tt.got == nil {
+			t.Errorf("%s leader = nil, want %s", tt.category, tt.nflID)
+			continue
+		}
+		if tt.got.NFLID != tt.nflID || tt.got.Value != tt.value {
+			t.Errorf("%s leader = %s with %v, want %s with %v", tt.category, tt.got.NFLID, tt.got.Value, tt.nflID, tt.value)
+		}
+	}
+	if leaders.RushingYards != nil && (leaders.RushingYards.Name != "Running Back" || leaders.RushingYards.Position != "RB") {
+		t.Errorf("rushing leader = %+v, want the roster name and position", leaders.RushingYards)
+	}
+}
+
+func TestTeamLeadersNoStats(t *testing.T) {
+	// A category nobody has recorded is left empty rather than crediting a zero
+	players := toDocs(t, []models.Player{
+		{NFLID: "rb", Season: 2024, Name: "Running Back", Team: "ATL", Position: "RB"},
+	})
+	stats := toDocs(t, []models.PlayerStats{
+		{NFLID: "rb", Season: 2024, SeasonType: models.SeasonTypeRegular, RushingYards: 40, FantasyPointsPPR: 4},
+	})
+
+	var results []map[string]TeamLeader
+	decodeDocs(t, runPipeline(t, teamLeadersPipeline("ATL", 2024), players, map[string][]bson.M{"player_stats": stats}), &results)
+	leaders := buildTeamLeaders("ATL", 2024, results)
+
+	if leaders.PassingYards != nil || leaders.ReceivingYards != nil {
+		t.Errorf("passing %+v, receiving %+v, want nil with no yards recorded", leaders.PassingYards, leaders.ReceivingYards)
+	}
+	if leaders.RushingYards == nil || leaders.RushingYards.NFLID != "rb" {
+		t.Errorf("rushing leader = %+v, want rb", leaders.RushingYards)
+	}
+
+	empty := buildTeamLeaders("ATL", 2024, nil)
+	if empty.Team != "ATL" || empty.FantasyPointsPPR != nil {
+		t.Errorf("empty roster leaders = %+v, want only the team and season", empty)
+	}
+}
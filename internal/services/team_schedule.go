@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/teams"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ScheduleGame is one game from a team's point of view
+type ScheduleGame struct {
+	Week          int       `json:"week"`
+	GameID        string    `json:"game_id"`
+	StartTime     time.Time `json:"start_time"`
+	Opponent      string    `json:"opponent"`
+	Home          bool      `json:"home"`
+	Status        string    `json:"status"`
+	TeamScore     *int      `json:"team_score,omitempty"` // Set once the game is final, even when 0
+	OpponentScore *int      `json:"opponent_score,omitempty"`
+	Result        string    `json:"result,omitempty"` // W, L or T once the game is final
+	Record        string    `json:"record,omitempty"` // Team's record after this game
+}
+
+// TeamSchedule is a team's season schedule with results to date
+type TeamSchedule struct {
+	Team   string         `json:"team"`
+	Season int            `json:"season"`
+	Wins   int            `json:"wins"`
+	Losses int            `json:"losses"`
+	Ties   int            `json:"ties"`
+	Record string         `json:"record"`
+	Games  []ScheduleGame `json:"games"`
+}
+
+// GetTeamSchedule returns every game for a team in a season in week order. Final games carry
+// the score, result and the running record after that game; scheduled and live games only
+// the opponent, site and kickoff.
+func (s *DataService) GetTeamSchedule(ctx context.Context, team string, season int) (*TeamSchedule, error) {
+	team = teams.Normalize(team)
+	filter := bson.M{
+		"season": season,
+		"$or": []bson.M{
+			{"home_team": team},
+			{"away_team": team},
+		},
+	}
+
+	cursor, err := s.db.Collection("games").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "week", Value: 1}, {Key: "start_time", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch team schedule: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var games []models.Game
+	if err := cursor.All(ctx, &games); err != nil {
+		return nil, fmt.Errorf("failed to decode team schedule: %w", err)
+	}
+
+	return buildTeamSchedule(team, season, games), nil
+}
+
+// buildTeamSchedule views week-ordered games from team's side, tallying final results
+func buildTeamSchedule(team string, season int, games []models.Game) *TeamSchedule {
+	schedule := &TeamSchedule{Team: team, Season: season, Games: make([]ScheduleGame, 0, len(games))}
+	for _, g := range games {
+		game := ScheduleGame{
+			Week:      g.Week,
+			GameID:    g.GameID,
+			StartTime: g.StartTime,
+			Home:      g.HomeTeam == team,
+			Status:    g.Status,
+		}
+		teamScore, opponentScore := g.HomeScore, g.AwayScore
+		game.Opponent = g.AwayTeam
+		if !game.Home {
+			teamScore, opponentScore = g.AwayScore, g.HomeScore
+			game.Opponent = g.HomeTeam
+		}
+
+		if g.Status == "final" {
+			game.TeamScore, game.OpponentScore = &teamScore, &opponentScore
+			switch {
+			case teamScore > opponentScore:
+				game.Result = "W"
+				schedule.Wins++
+			case teamScore < opponentScore:
+				game.Result = "L"
+				schedule.Losses++
+			default:
+				game.Result = "T"
+				schedule.Ties++
+			}
+			game.Record = formatRecord(schedule.Wins, schedule.Losses, schedule.Ties)
+		}
+
+		schedule.Games = append(schedule.Games, game)
+	}
+	schedule.Record = formatRecord(schedule.Wins, schedule.Losses, schedule.Ties)
+
+	return schedule
+}
+
+// formatRecord renders W-L, adding ties only when there are any
+func formatRecord(wins, losses, ties int) string {
+	if ties > 0 {
+		return fmt.Sprintf("%d-%d-%d", wins, losses, ties)
+	}
+	return fmt.Sprintf("%d-%d", wins, losses)
+}
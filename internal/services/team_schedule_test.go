@@ -0,0 +1,68 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+func TestBuildTeamSchedule(t *testing.T) {
+	games := []models.Game{
+		{Week: 1, GameID: "2025_01_ATL_TB", HomeTeam: "ATL", AwayTeam: "TB", Status: "final", HomeScore: 20, AwayScore: 23},
+		{Week: 2, GameID: "2025_02_MIN_ATL", HomeTeam: "MIN", AwayTeam: "ATL", Status: "final", HomeScore: 6, AwayScore: 22},
+		{Week: 3, GameID: "2025_03_ATL_CAR", HomeTeam: "ATL", AwayTeam: "CAR", Status: "final", HomeScore: 0, AwayScore: 30},
+		{Week: 4, GameID: "2025_04_ATL_WAS", HomeTeam: "ATL", AwayTeam: "WAS", Status: "final", HomeScore: 17, AwayScore: 17},
+		{Week: 6, GameID: "2025_06_ATL_BUF", HomeTeam: "ATL", AwayTeam: "BUF", Status: "live", HomeScore: 10, AwayScore: 7},
+		{Week: 7, GameID: "2025_07_SF_ATL", HomeTeam: "SF", AwayTeam: "ATL", Status: "scheduled"},
+	}
+
+	schedule := buildTeamSchedule("ATL", 2025, games)
+
+	if schedule.Wins != 1 || schedule.Losses != 2 || schedule.Ties != 1 || schedule.Record != "1-2-1" {
+		t.Errorf("record = %d-%d-%d (%q), want 1-2-1", schedule.Wins, schedule.Losses, schedule.Ties, schedule.Record)
+	}
+
+	want := []struct {
+		opponent       string
+		home           bool
+		result, record string
+		team, opp      int
+		hasScore       bool
+	}{
+		{"TB", true, "L", "0-1", 20, 23, true},
+		{"MIN", false, "W", "1-1", 22, 6, true},
+		{"CAR", true, "L", "1-2", 0, 30, true},
+		{"WAS", true, "T", "1-2-1", 17, 17, true},
+		{"BUF", true, "", "", 0, 0, false},
+		{"SF", false, "", "", 0, 0, false},
+	}
+	if len(schedule.Games) != len(want) {
+		t.Fatalf("got %d games, want %d", len(schedule.Games), len(want))
+	}
+	for i, w := range want {
+		g := schedule.Games[i]
+		if g.Opponent != w.opponent || g.Home != w.home || g.Result != w.result || g.Record != w.record {
+			t.Errorf("week %d = %s home=%v %s %s, want %s home=%v %s %s", g.Week, g.Opponent, g.Home, g.Result, g.Record,
+				w.opponent, w.home, w.result, w.record)
+		}
+		if (g.TeamScore != nil) != w.hasScore {
+			t.Errorf("week %d has score = %v, want %v", g.Week, g.TeamScore != nil, w.hasScore)
+			continue
+		}
+		if w.hasScore && (*g.TeamScore != w.team || *g.OpponentScore != w.opp) {
+			t.Errorf("week %d score = %d-%d, want %d-%d", g.Week, *g.TeamScore, *g.OpponentScore, w.team, w.opp)
+		}
+	}
+
+	// A shutout keeps its 0 in the response; unplayed games omit scores
+	shutout, _ := json.Marshal(schedule.Games[2])
+	if !strings.Contains(string(shutout), `"team_score":0`) {
+		t.Errorf("shutout JSON %s is missing team_score 0", shutout)
+	}
+	upcoming, _ := json.Marshal(schedule.Games[5])
+	if strings.Contains(string(upcoming), "score") {
+		t.Errorf("scheduled game JSON %s has a score", upcoming)
+	}
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/prompts"
+	"github.com/ai-atl/nfl-platform/pkg/gemini"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// positionScarcity weights rest-of-season value by how hard a position is to replace
+var positionScarcity = map[string]float64{
+	"QB": 0.8, // Deep position in single-QB leagues
+	"RB": 1.2, // Bell-cow backs are the scarcest asset
+	"WR": 1.0,
+	"TE": 1.1, // Few reliable TEs
+}
+
+type TradeAnalyzerService struct {
+	db          *mongo.Database
+	gemini      *gemini.Client
+	dataService *DataService
+	advisor     *FantasyAdvisorService
+	projections Projections
+	trades      tradeStore
+	scoring     ScoringConfig
+}
+
+func NewTradeAnalyzerService(db *mongo.Database) *TradeAnalyzerService {
+	return &TradeAnalyzerService{
+		db:          db,
+		gemini:      gemini.NewClient(gemini.AnalysisTier()),
+		dataService: NewDataService(db),
+		advisor:     NewFantasyAdvisorService(db),
+		projections: NewTrailingAverageProjections(db),
+		trades:      mongoTradeStore{db: db},
+		scoring:     ScoringPPR,
+	}
+}
+
+// WithScoring returns a copy of the service that values players under the given scoring
+func (s *TradeAnalyzerService) WithScoring(scoring ScoringConfig) *TradeAnalyzerService {
+	scored := *s
+	scored.scoring = scoring
+	scored.projections = projectionsWithScoring(s.projections, scoring)
+	scored.advisor = s.advisor.WithScoring(scoring)
+	return &scored
+}
+
+// TradePlayerValue is a single player's rest-of-season value estimate
+type TradePlayerValue struct {
+	NFLID            string  `json:"nfl_id"`
+	Name             string  `json:"name"`
+	Position         string  `json:"position"`
+	Team             string  `json:"team"`
+	WeeklyProjection float64 `json:"weekly_projection"` // Points per game under the league's scoring
+	RemainingGames   int     `json:"remaining_games"`
+	ScarcityWeight   float64 `json:"scarcity_weight"`
+	Trend            string  `json:"trend"`
+	AvgEPA           float64 `json:"avg_epa"`
+	InjuryStatus     string  `json:"injury_status,omitempty"`
+	Schedule         string  `json:"schedule"` // "favorable", "average", "difficult"
+	Value            float64 `json:"value"`
+}
+
+// TradeSide is the set of players one team receives
+type TradeSide struct {
+	Players    []TradePlayerValue `json:"players"`
+	TotalValue float64            `json:"total_value"`
+}
+
+// TradeAnalysis is the full evaluation of a proposed trade
+type TradeAnalysis struct {
+	TradeID       string    `json:"trade_id,omitempty"` // Set once saved to the user's history
+	Season        int       `json:"season"`
+	Week          int       `json:"week"`
+	TeamAReceives TradeSide `json:"team_a_receives"`
+	TeamBReceives TradeSide `json:"team_b_receives"`
+	ValueDelta    float64   `json:"value_delta"` // Net value for team A (positive favors A)
+	Verdict       string    `json:"verdict"`     // "favors_a", "favors_b", "even"
+	Rationale     string    `json:"rationale"`
+}
+
+// AnalyzeTrade evaluates a trade where team A sends aGives (nfl_ids) to team B for aGets
+func (s *TradeAnalyzerService) AnalyzeTrade(ctx context.Context, aGives, aGets []string, season, currentWeek int) (*TradeAnalysis, error) {
+	teamBReceives, err := s.valueSide(ctx, aGives, season, currentWeek)
+	if err != nil {
+		return nil, err
+	}
+	teamAReceives, err := s.valueSide(ctx, aGets, season, currentWeek)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &TradeAnalysis{
+		Season:        season,
+		Week:          currentWeek,
+		TeamAReceives: teamAReceives,
+		TeamBReceives: teamBReceives,
+		ValueDelta:    teamAReceives.TotalValue - teamBReceives.TotalValue,
+	}
+	analysis.Verdict = tradeVerdict(teamAReceives.TotalValue, teamBReceives.TotalValue)
+
+	prompt, err := s.buildTradePrompt(analysis)
+	if err != nil {
+		return nil, err
+	}
+	rationale, err := s.gemini.GenerateWithRetry(ctx, prompt, 3)
+	if err != nil {
+		log.Printf("Trade rationale generation failed: %v", err)
+		rationale = fmt.Sprintf("AI rationale unavailable. Team A receives %.1f value and gives up %.1f.",
+			teamAReceives.TotalValue, teamBReceives.TotalValue)
+	}
+	analysis.Rationale = rationale
+
+	return analysis, nil
+}
+
+// tradeVerdict treats trades within 10% of the combined value as even
+func tradeVerdict(aValue, bValue float64) string {
+	delta := aValue - bValue
+	threshold := 0.1 * math.Max((aValue+bValue)/2, 1)
+
+	switch {
+	case delta > threshold:
+		return "favors_a"
+	case delta < -threshold:
+		return "favors_b"
+	default:
+		return "even"
+	}
+}
+
+// valueSide looks up and values every player in a trade package
+func (s *TradeAnalyzerService) valueSide(ctx context.Context, nflIDs []string, season, currentWeek int) (TradeSide, error) {
+	side := TradeSide{Players: []TradePlayerValue{}}
+
+	for _, nflID := range nflIDs {
+		player, err := s.findPlayer(ctx, nflID, season)
+		if err != nil {
+			return side, fmt.Errorf("player %s not found: %w", nflID, err)
+		}
+
+		value := s.valuePlayer(ctx, player, season, currentWeek)
+		side.Players = append(side.Players, value)
+		side.TotalValue += value.Value
+	}
+
+	return side, nil
+}
+
+// findPlayer returns the player's entry for season, falling back to their most recent season
+func (s *TradeAnalyzerService) findPlayer(ctx context.Context, nflID string, season int) (*models.Player, error) {
+	player, err := s.dataService.GetPlayer(ctx, nflID, season)
+	if err == nil {
+		return player, nil
+	}
+
+	var latest models.Player
+	err = s.db.Collection("players").FindOne(ctx, bson.M{"nfl_id": nflID},
+		options.FindOne().SetSort(bson.D{{Key: "season", Value: -1}})).Decode(&latest)
+	if err != nil {
+		return nil, err
+	}
+	return &latest, nil
+}
+
+// valuePlayer estimates rest-of-season value from recent form, trend, health and scarcity
+func (s *TradeAnalyzerService) valuePlayer(ctx context.Context, player *models.Player, season, currentWeek int) TradePlayerValue {
+	in := tradeValueInputs{}
+	injured := player.Status == "INA" || isInjuryStatus(player.StatusDescriptionAbbr)
+	if injured {
+		in.InjuryStatus = models.GetPlayerStatusDescription(player.Status, player.StatusDescriptionAbbr)
+	}
+
+	// Season average from weekly stats anchors the projection
+	weeks, _ := s.dataService.GetPlayerWeeklyStatsRange(ctx, player.NFLID, season, 0, currentWeek-1)
+	for _, w := range weeks {
+		in.SeasonAvg += s.scoring.Points(WeeklyStatLine(w)) / float64(len(weeks))
+	}
+
+	enriched := s.advisor.enrichPlayerData(ctx, player.Name, player.Position, player.Team,
+		0, in.SeasonAvg, injured, in.InjuryStatus, season, currentWeek)
+	in.Trend = enriched.PlayerTrend
+	in.AvgEPA = enriched.AvgEPA
+
+	in.Recent = s.projections.ProjectPlayer(ctx, player.NFLID, season, currentWeek)
+
+	if outlook, err := s.dataService.RestOfSeasonSchedule(ctx, player.Team, player.Position, season, currentWeek); err == nil {
+		in.Outlook = outlook
+	}
+
+	return tradePlayerValue(player, in, currentWeek)
+}
+
+// tradeValueInputs is what valuePlayer gathers about a player before valuing them
+type tradeValueInputs struct {
+	SeasonAvg    float64 // Points per game so far this season
+	Recent       float64 // Recent-form projection, 0 if unavailable
+	Trend        string  // "hot", "cold" or "neutral"
+	AvgEPA       float64
+	Outlook      *ScheduleOutlook // Remaining schedule, nil if unavailable
+	InjuryStatus string           // Empty when healthy
+}
+
+// tradePlayerValue combines a player's projection, remaining games, availability,
+// positional scarcity and schedule into a rest-of-season value
+func tradePlayerValue(player *models.Player, in tradeValueInputs, currentWeek int) TradePlayerValue {
+	// Blend the recent-form projection with the season average (recent form weighted more heavily)
+	projection := in.SeasonAvg
+	if in.Recent > 0 {
+		if in.SeasonAvg > 0 {
+			projection = 0.6*in.Recent + 0.4*in.SeasonAvg
+		} else {
+			projection = in.Recent
+		}
+	}
+
+	switch in.Trend {
+	case "hot":
+		projection *= 1.1
+	case "cold":
+		projection *= 0.9
+	}
+
+	remaining := regularSeasonWeeks - currentWeek + 1
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	// Count actual remaining games (skipping byes) and adjust for opponent strength:
+	// the hardest possible schedule costs 20%, the easiest adds 20%
+	schedule := "average"
+	scheduleFactor := 1.0
+	if in.Outlook != nil && in.Outlook.GamesRemaining > 0 {
+		remaining = in.Outlook.GamesRemaining
+		schedule = in.Outlook.Difficulty
+		scheduleFactor = 1 + (50-in.Outlook.DifficultyScore)/250
+	}
+
+	// Injured players lose a chunk of their remaining availability
+	availability := 1.0
+	if in.InjuryStatus != "" {
+		availability = 0.5
+	}
+
+	scarcity, ok := positionScarcity[strings.ToUpper(player.Position)]
+	if !ok {
+		scarcity = 0.5
+	}
+
+	return TradePlayerValue{
+		NFLID:            player.NFLID,
+		Name:             player.Name,
+		Position:         player.Position,
+		Team:             player.Team,
+		WeeklyProjection: projection,
+		RemainingGames:   remaining,
+		ScarcityWeight:   scarcity,
+		Trend:            in.Trend,
+		AvgEPA:           in.AvgEPA,
+		InjuryStatus:     in.InjuryStatus,
+		Schedule:         schedule,
+		Value:            projection * float64(remaining) * availability * scarcity * scheduleFactor,
+	}
+}
+
+// tradePromptData is the data behind the trade_rationale prompt
+type tradePromptData struct {
+	ScoringName   string
+	TeamAReceives TradeSide
+	TeamBReceives TradeSide
+	Verdict       string
+}
+
+func (s *TradeAnalyzerService) buildTradePrompt(analysis *TradeAnalysis) (string, error) {
+	return prompts.Render(prompts.TradeRationale, tradePromptData{
+		ScoringName:   s.scoring.Name,
+		TeamAReceives: analysis.TeamAReceives,
+		TeamBReceives: analysis.TeamBReceives,
+		Verdict:       analysis.Verdict,
+	})
+}
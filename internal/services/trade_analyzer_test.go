@@ -0,0 +1,115 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+// tradeSide values each player the way valueSide does
+func tradeSide(players []*models.Player, inputs []tradeValueInputs, currentWeek int) TradeSide {
+	side := TradeSide{}
+	for i, p := range players {
+		value := tradePlayerValue(p, inputs[i], currentWeek)
+		side.Players = append(side.Players, value)
+		side.TotalValue += value.Value
+	}
+	return side
+}
+
+func TestTradePlayerValue(t *testing.T) {
+	rb := &models.Player{NFLID: "00-0038542", Name: "Bijan Robinson", Position: "RB", Team: "ATL"}
+
+	tests := []struct {
+		name       string
+		in         tradeValueInputs
+		week       int
+		projection float64
+		games      int
+		value      float64
+	}{
+		{"season average only", tradeValueInputs{SeasonAvg: 20}, 11, 20, 8, 20 * 8 * 1.2},
+		{"recent form weighted", tradeValueInputs{SeasonAvg: 20, Recent: 25}, 11, 23, 8, 23 * 8 * 1.2},
+		{"hot streak", tradeValueInputs{SeasonAvg: 20, Trend: "hot"}, 11, 22, 8, 22 * 8 * 1.2},
+		{"injured", tradeValueInputs{SeasonAvg: 20, InjuryStatus: "Reserve/Injured"}, 11, 20, 8, 20 * 8 * 0.5 * 1.2},
+		{
+			// A bye leaves 7 games, and the easiest schedule adds 20%
+			"schedule", tradeValueInputs{SeasonAvg: 20, Outlook: &ScheduleOutlook{GamesRemaining: 7, DifficultyScore: 0, Difficulty: "favorable"}},
+			11, 20, 7, 20 * 7 * 1.2 * 1.2,
+		},
+		{"season over", tradeValueInputs{SeasonAvg: 20}, 19, 20, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tradePlayerValue(rb, tt.in, tt.week)
+			if math.Abs(got.WeeklyProjection-tt.projection) > 1e-9 || got.RemainingGames != tt.games || math.Abs(got.Value-tt.value) > 1e-9 {
+				t.Errorf("tradePlayerValue() = projection %v, games %d, value %v, want %v, %d, %v",
+					got.WeeklyProjection, got.RemainingGames, got.Value, tt.projection, tt.games, tt.value)
+			}
+		})
+	}
+}
+
+func TestTradeVerdict(t *testing.T) {
+	const week = 10
+
+	// A healthy bell-cow back for an injured receiver and a backup tight end
+	bellCow := &models.Player{NFLID: "rb1", Name: "Bell Cow", Position: "RB", Team: "ATL"}
+	injuredWR := &models.Player{NFLID: "wr1", Name: "Injured Receiver", Position: "WR", Team: "MIA"}
+	backupTE := &models.Player{NFLID: "te1", Name: "Backup Tight End", Position: "TE", Team: "NYG"}
+
+	aGets := tradeSide([]*models.Player{bellCow}, []tradeValueInputs{{SeasonAvg: 21, Recent: 24, Trend: "hot"}}, week)
+	aGives := tradeSide([]*models.Player{injuredWR, backupTE}, []tradeValueInputs{
+		{SeasonAvg: 14, InjuryStatus: "Reserve/Injured"},
+		{SeasonAvg: 6, Trend: "cold"},
+	}, week)
+
+	if got := tradeVerdict(aGets.TotalValue, aGives.TotalValue); got != "favors_a" {
+		t.Errorf("lopsided trade verdict = %s (A gets %.1f, gives %.1f), want favors_a", got, aGets.TotalValue, aGives.TotalValue)
+	}
+	if got := tradeVerdict(aGives.TotalValue, aGets.TotalValue); got != "favors_b" {
+		t.Errorf("reversed lopsided trade verdict = %s, want favors_b", got)
+	}
+
+	// Two similar receivers swapped straight up
+	wrA := &models.Player{NFLID: "wr2", Name: "Receiver A", Position: "WR", Team: "CIN"}
+	wrB := &models.Player{NFLID: "wr3", Name: "Receiver B", Position: "WR", Team: "DET"}
+	gets := tradeSide([]*models.Player{wrA}, []tradeValueInputs{{SeasonAvg: 16, Recent: 17}}, week)
+	gives := tradeSide([]*models.Player{wrB}, []tradeValueInputs{{SeasonAvg: 17, Recent: 16}}, week)
+
+	if got := tradeVerdict(gets.TotalValue, gives.TotalValue); got != "even" {
+		t.Errorf("even swap verdict = %s (A gets %.1f, gives %.1f), want even", got, gets.TotalValue, gives.TotalValue)
+	}
+
+	// Scarcity alone can tip an otherwise equal swap: an RB is worth more than a QB at the same output
+	rb := tradeSide([]*models.Player{{NFLID: "rb2", Position: "RB"}}, []tradeValueInputs{{SeasonAvg: 18}}, week)
+	qb := tradeSide([]*models.Player{{NFLID: "qb1", Position: "QB"}}, []tradeValueInputs{{SeasonAvg: 18}}, week)
+	if got := tradeVerdict(rb.TotalValue, qb.TotalValue); got != "favors_a" {
+		t.Errorf("RB for QB at equal output verdict = %s, want favors_a", got)
+	}
+
+	if got := tradeVerdict(0, 0); got != "even" {
+		t.Errorf("tradeVerdict(0, 0) = %s, want even", got)
+	}
+}
+
+func TestBuildTradePromptScoring(t *testing.T) {
+	analysis := &TradeAnalysis{
+		TeamAReceives: TradeSide{Players: []TradePlayerValue{{Name: "Bell Cow", Position: "RB", Team: "ATL", WeeklyProjection: 18.5}}},
+		TeamBReceives: TradeSide{Players: []TradePlayerValue{{Name: "Receiver A", Position: "WR", Team: "CIN", WeeklyProjection: 15}}},
+		Verdict:       "favors_a",
+	}
+
+	for _, scoring := range []ScoringConfig{ScoringStandard, ScoringHalfPPR, ScoringPPR} {
+		prompt, err := (&TradeAnalyzerService{scoring: scoring}).buildTradePrompt(analysis)
+		if err != nil {
+			t.Fatalf("buildTradePrompt() error = %v", err)
+		}
+		if want := "18.5 " + scoring.Name + " pts/game"; !strings.Contains(prompt, want) {
+			t.Errorf("%s prompt does not contain %q:\n%s", scoring.Name, want, prompt)
+		}
+	}
+}
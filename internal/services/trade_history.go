@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	// ErrTradeNotFound is returned when a saved trade doesn't exist
+	ErrTradeNotFound = errors.New("trade not found")
+	// ErrTradeForbidden is returned when a saved trade belongs to another user
+	ErrTradeForbidden = errors.New("trade belongs to another user")
+)
+
+// SaveTrade stores an analysis in the user's trade history and returns the saved trade
+func (s *TradeAnalyzerService) SaveTrade(ctx context.Context, userID bson.ObjectID, analysis *TradeAnalysis) (*models.Trade, error) {
+	trade := &models.Trade{
+		UserID:     userID,
+		Season:     analysis.Season,
+		Week:       analysis.Week,
+		TeamAGives: tradePlayers(analysis.TeamBReceives),
+		TeamAGets:  tradePlayers(analysis.TeamAReceives),
+		ValueDelta: analysis.ValueDelta,
+		Verdict:    analysis.Verdict,
+		Rationale:  analysis.Rationale,
+		CreatedAt:  time.Now(),
+	}
+
+	trade.ID = bson.NewObjectID()
+	if err := s.trades.Insert(ctx, trade); err != nil {
+		return nil, fmt.Errorf("failed to save trade: %w", err)
+	}
+	return trade, nil
+}
+
+// ListTrades returns a page of the user's saved trades, newest first, and the total count
+func (s *TradeAnalyzerService) ListTrades(ctx context.Context, userID bson.ObjectID, page, limit int) ([]models.Trade, int64, error) {
+	return s.trades.List(ctx, userID, page, limit)
+}
+
+// GetTrade returns one of the user's saved trades
+func (s *TradeAnalyzerService) GetTrade(ctx context.Context, userID, tradeID bson.ObjectID) (*models.Trade, error) {
+	trade, err := s.trades.Find(ctx, tradeID)
+	if errors.Is(err, ErrTradeNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trade: %w", err)
+	}
+	if trade.UserID != userID {
+		return nil, ErrTradeForbidden
+	}
+	return trade, nil
+}
+
+// tradeStore keeps saved trades. Find returns ErrTradeNotFound for an unknown ID.
+type tradeStore interface {
+	Insert(ctx context.Context, trade *models.Trade) error
+	List(ctx context.Context, userID bson.ObjectID, page, limit int) ([]models.Trade, int64, error)
+	Find(ctx context.Context, tradeID bson.ObjectID) (*models.Trade, error)
+}
+
+// mongoTradeStore keeps trades in the trades collection
+type mongoTradeStore struct {
+	db *mongo.Database
+}
+
+func (s mongoTradeStore) Insert(ctx context.Context, trade *models.Trade) error {
+	_, err := s.db.Collection("trades").InsertOne(ctx, trade)
+	return err
+}
+
+func (s mongoTradeStore) List(ctx context.Context, userID bson.ObjectID, page, limit int) ([]models.Trade, int64, error) {
+	collection := s.db.Collection("trades")
+	filter := bson.M{"user_id": userID}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count trades: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch trades: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	trades := []models.Trade{}
+	if err := cursor.All(ctx, &trades); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode trades: %w", err)
+	}
+	return trades, total, nil
+}
+
+func (s mongoTradeStore) Find(ctx context.Context, tradeID bson.ObjectID) (*models.Trade, error) {
+	var trade models.Trade
+	err := s.db.Collection("trades").FindOne(ctx, bson.M{"_id": tradeID}).Decode(&trade)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrTradeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &trade, nil
+}
+
+// tradePlayers keeps the identifying fields and value of each player on a side
+func tradePlayers(side TradeSide) []models.TradePlayer {
+	players := make([]models.TradePlayer, 0, len(side.Players))
+	for _, p := range side.Players {
+		players = append(players, models.TradePlayer{
+			NFLID:    p.NFLID,
+			Name:     p.Name,
+			Position: p.Position,
+			Team:     p.Team,
+			Value:    p.Value,
+		})
+	}
+	return players
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// memTradeStore is a tradeStore over in-memory trades
+type memTradeStore struct {
+	trades map[bson.ObjectID]models.Trade
+}
+
+func newMemTradeStore() *memTradeStore {
+	return &memTradeStore{trades: make(map[bson.ObjectID]models.Trade)}
+}
+
+func (s *memTradeStore) Insert(ctx context.Context, trade *models.Trade) error {
+	s.trades[trade.ID] = *trade
+	return nil
+}
+
+func (s *memTradeStore) List(ctx context.Context, userID bson.ObjectID, page, limit int) ([]models.Trade, int64, error) {
+	trades := []models.Trade{}
+	for _, t := range s.trades {
+		if t.UserID == userID {
+			trades = append(trades, t)
+		}
+	}
+	sort.Slice(trades, func(i, j int) bool { return trades[i].CreatedAt.After(trades[j].CreatedAt) })
+
+	total := int64(len(trades))
+	start := (page - 1) * limit
+	if start > len(trades) {
+		start = len(trades)
+	}
+	end := start + limit
+	if end > len(trades) {
+		end = len(trades)
+	}
+	return trades[start:end], total, nil
+}
+
+func (s *memTradeStore) Find(ctx context.Context, tradeID bson.ObjectID) (*models.Trade, error) {
+	t, ok := s.trades[tradeID]
+	if !ok {
+		return nil, ErrTradeNotFound
+	}
+	return &t, nil
+}
+
+func savedTradeAnalysis() *TradeAnalysis {
+	return &TradeAnalysis{
+		Season: 2024,
+		Week:   9,
+		TeamAReceives: TradeSide{Players: []TradePlayerValue{
+			{NFLID: "rb1", Name: "Back One", Position: "RB", Team: "ATL", Value: 110},
+		}},
+		TeamBReceives: TradeSide{Players: []TradePlayerValue{
+			{NFLID: "wr1", Name: "Wideout One", Position: "WR", Team: "DET", Value: 80},
+			{NFLID: "te1", Name: "End One", Position: "TE", Team: "KC", Value: 20},
+		}},
+		ValueDelta: 10,
+		Verdict:    "favors_a",
+		Rationale:  "Team A lands the best player in the deal.",
+	}
+}
+
+func TestSaveAndGetTrade(t *testing.T) {
+	ctx := context.Background()
+	s := &TradeAnalyzerService{trades: newMemTradeStore()}
+	owner := bson.NewObjectID()
+
+	saved, err := s.SaveTrade(ctx, owner, savedTradeAnalysis())
+	if err != nil {
+		t.Fatalf("SaveTrade: %v", err)
+	}
+	if saved.ID.IsZero() {
+		t.Fatal("saved trade has no ID")
+	}
+
+	got, err := s.GetTrade(ctx, owner, saved.ID)
+	if err != nil {
+		t.Fatalf("GetTrade: %v", err)
+	}
+	if got.Season != 2024 || got.Week != 9 || got.Verdict != "favors_a" || got.ValueDelta != 10 {
+		t.Errorf("got %+v, want the saved season, week, verdict and delta", got)
+	}
+	// Team A gives what team B receives and gets what team A receives
+	if len(got.TeamAGives) != 2 || got.TeamAGives[0].NFLID != "wr1" || got.TeamAGives[1].NFLID != "te1" {
+		t.Errorf("TeamAGives = %+v, want wr1 and te1", got.TeamAGives)
+	}
+	if len(got.TeamAGets) != 1 || got.TeamAGets[0].NFLID != "rb1" || got.TeamAGets[0].Value != 110 {
+		t.Errorf("TeamAGets = %+v, want rb1 valued at 110", got.TeamAGets)
+	}
+}
+
+func TestGetTradeOwnership(t *testing.T) {
+	ctx := context.Background()
+	s := &TradeAnalyzerService{trades: newMemTradeStore()}
+	owner := bson.NewObjectID()
+
+	saved, err := s.SaveTrade(ctx, owner, savedTradeAnalysis())
+	if err != nil {
+		t.Fatalf("SaveTrade: %v", err)
+	}
+
+	if _, err := s.GetTrade(ctx, bson.NewObjectID(), saved.ID); !errors.Is(err, ErrTradeForbidden) {
+		t.Errorf("another user's GetTrade error = %v, want ErrTradeForbidden", err)
+	}
+	if _, err := s.GetTrade(ctx, owner, bson.NewObjectID()); !errors.Is(err, ErrTradeNotFound) {
+		t.Errorf("unknown trade error = %v, want ErrTradeNotFound", err)
+	}
+}
+
+func TestListTradesNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	store := newMemTradeStore()
+	s := &TradeAnalyzerService{trades: store}
+	owner := bson.NewObjectID()
+
+	base := time.Date(2024, 11, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		store.trades[bson.NewObjectID()] = models.Trade{UserID: owner, Week: i + 1, CreatedAt: base.Add(time.Duration(i) * time.Hour)}
+	}
+	store.trades[bson.NewObjectID()] = models.Trade{UserID: bson.NewObjectID(), Week: 9, CreatedAt: base}
+
+	trades, total, err := s.ListTrades(ctx, owner, 1, 2)
+	if err != nil {
+		t.Fatalf("ListTrades: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("total = %d, want 3", total)
+	}
+	if len(trades) != 2 || trades[0].Week != 3 || trades[1].Week != 2 {
+		t.Errorf("page 1 = %+v, want weeks 3 then 2", trades)
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultReplacementLevels is the positional rank of a freely available starter in a
+// 12-team league: QB12, RB24, WR36, TE12
+var DefaultReplacementLevels = map[string]int{
+	"QB": 12,
+	"RB": 24,
+	"WR": 36,
+	"TE": 12,
+}
+
+// ReplacementLevel is the replacement rank for a position. In superflex leagues roughly two
+// QBs start per team, so the QB baseline drops to QB24.
+func ReplacementLevel(position string, superflex bool) int {
+	if superflex && position == "QB" {
+		return 2 * DefaultReplacementLevels["QB"]
+	}
+	return DefaultReplacementLevels[position]
+}
+
+// vorpPoolSize caps how many players per position are ranked
+const vorpPoolSize = 200
+
+// PlayerVORP is a player's season fantasy points above the positional replacement level
+type PlayerVORP struct {
+	TopPerformer
+	VORP float64 `json:"vorp"`
+}
+
+// PositionVORP ranks a position group by value over replacement
+type PositionVORP struct {
+	Position          string       `json:"position"`
+	Season            int          `json:"season"`
+	Scoring           string       `json:"scoring"`
+	ReplacementLevel  int          `json:"replacement_level"`
+	ReplacementPlayer string       `json:"replacement_player,omitempty"`
+	ReplacementPoints float64      `json:"replacement_points"`
+	Players           []PlayerVORP `json:"players"`
+}
+
+// VORP ranks a position by season fantasy points and subtracts the points of the player at
+// the replacement rank (replacementLevel=0 uses DefaultReplacementLevels; pass
+// ReplacementLevel(position, true) for superflex leagues). When fewer
+// players than the replacement rank have scored, the last ranked player is the baseline.
+func (s *InsightService) VORP(ctx context.Context, position string, season, replacementLevel int, scoring ScoringConfig) (*PositionVORP, error) {
+	if replacementLevel <= 0 {
+		level, ok := DefaultReplacementLevels[position]
+		if !ok {
+			return nil, fmt.Errorf("no replacement level for position %q", position)
+		}
+		replacementLevel = level
+	}
+
+	performers, err := s.TopPerformers(ctx, position, season, 0, vorpPoolSize, scoring)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PositionVORP{
+		Position:         position,
+		Season:           season,
+		Scoring:          scoring.Name,
+		ReplacementLevel: replacementLevel,
+	}
+	rankVORP(result, performers)
+	return result, nil
+}
+
+// rankVORP fills result's replacement baseline and players from performers, ranked by
+// fantasy points
+func rankVORP(result *PositionVORP, performers []TopPerformer) {
+	result.Players = make([]PlayerVORP, 0, len(performers))
+	if len(performers) == 0 {
+		return
+	}
+
+	replacement := performers[len(performers)-1]
+	if result.ReplacementLevel <= len(performers) {
+		replacement = performers[result.ReplacementLevel-1]
+	}
+	result.ReplacementPlayer = replacement.Name
+	result.ReplacementPoints = replacement.FantasyPoints
+
+	for _, p := range performers {
+		result.Players = append(result.Players, PlayerVORP{
+			TopPerformer: p,
+			VORP:         p.FantasyPoints - replacement.FantasyPoints,
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+)
+
+// runningBacks ranks n backs by fantasy points, 300 for RB1 down by 10 per rank
+func runningBacks(n int) []TopPerformer {
+	performers := make([]TopPerformer, n)
+	for i := range performers {
+		performers[i] = TopPerformer{
+			Rank:          i + 1,
+			NFLID:         fmt.Sprintf("rb%d", i+1),
+			Name:          fmt.Sprintf("RB%d", i+1),
+			Position:      "RB",
+			FantasyPoints: float64(300 - 10*i),
+		}
+	}
+	return performers
+}
+
+func TestRankVORP(t *testing.T) {
+	result := &PositionVORP{Position: "RB", ReplacementLevel: ReplacementLevel("RB", false)}
+	rankVORP(result, runningBacks(30))
+
+	if result.ReplacementPlayer != "RB24" || result.ReplacementPoints != 70 {
+		t.Fatalf("replacement = %s with %v points, want RB24 with 70", result.ReplacementPlayer, result.ReplacementPoints)
+	}
+	if len(result.Players) != 30 {
+		t.Fatalf("ranked %d players, want 30", len(result.Players))
+	}
+	for _, p := range result.Players {
+		switch {
+		case p.Rank == 24 && p.VORP != 0:
+			t.Errorf("VORP at the replacement line = %v, want 0", p.VORP)
+		case p.Rank < 24 && p.VORP <= 0:
+			t.Errorf("%s VORP = %v, want positive above the replacement line", p.Name, p.VORP)
+		case p.Rank > 24 && p.VORP >= 0:
+			t.Errorf("%s VORP = %v, want negative below the replacement line", p.Name, p.VORP)
+		}
+	}
+	if got := result.Players[0].VORP; got != 230 {
+		t.Errorf("RB1 VORP = %v, want 230", got)
+	}
+}
+
+func TestRankVORPShortPool(t *testing.T) {
+	// Fewer backs than the replacement rank: the last ranked back is the baseline
+	result := &PositionVORP{Position: "RB", ReplacementLevel: 24}
+	rankVORP(result, runningBacks(10))
+	if result.ReplacementPlayer != "RB10" || result.Players[9].VORP != 0 || result.Players[0].VORP != 90 {
+		t.Errorf("short pool = replacement %s, RB10 VORP %v, RB1 VORP %v, want RB10, 0, 90",
+			result.ReplacementPlayer, result.Players[9].VORP, result.Players[0].VORP)
+	}
+
+	empty := &PositionVORP{Position: "RB", ReplacementLevel: 24}
+	rankVORP(empty, nil)
+	if empty.Players == nil || len(empty.Players) != 0 || empty.ReplacementPlayer != "" {
+		t.Errorf("empty pool = %+v, want no players and no baseline", empty)
+	}
+}
+
+func TestReplacementLevel(t *testing.T) {
+	tests := []struct {
+		position  string
+		superflex bool
+		want      int
+	}{
+		{"QB", false, 12},
+		{"QB", true, 24},
+		{"RB", true, 24},
+		{"WR", false, 36},
+		{"K", false, 0},
+	}
+	for _, tt := range tests {
+		if got := ReplacementLevel(tt.position, tt.superflex); got != tt.want {
+			t.Errorf("ReplacementLevel(%s, %v) = %d, want %d", tt.position, tt.superflex, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrInvalidVote is returned when a vote's subject type or choice is not recognized
+var ErrInvalidVote = errors.New("invalid vote")
+
+type VoteService struct {
+	db *mongo.Database
+}
+
+func NewVoteService(db *mongo.Database) *VoteService {
+	return &VoteService{db: db}
+}
+
+// VoteConsensus is the aggregated community opinion on a subject
+type VoteConsensus struct {
+	SubjectType string             `json:"subject_type"`
+	SubjectID   string             `json:"subject_id"`
+	TotalVotes  int                `json:"total_votes"`
+	Counts      map[string]int     `json:"counts"`
+	Percentages map[string]float64 `json:"percentages"`
+	Leader      string             `json:"leader,omitempty"` // Most popular choice, empty on a tie or no votes
+}
+
+// Create records a user's vote, replacing any earlier vote by the same user on the same subject
+func (s *VoteService) Create(ctx context.Context, userID bson.ObjectID, subjectType, subjectID, choice string) (*models.Vote, error) {
+	if !validVoteChoice(subjectType, choice) {
+		return nil, fmt.Errorf("%w: %q is not a valid choice for %q", ErrInvalidVote, choice, subjectType)
+	}
+
+	filter, update := voteUpsert(userID, subjectType, subjectID, choice, time.Now())
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var vote models.Vote
+	if err := s.db.Collection("votes").FindOneAndUpdate(ctx, filter, update, opts).Decode(&vote); err != nil {
+		return nil, fmt.Errorf("failed to save vote: %w", err)
+	}
+
+	return &vote, nil
+}
+
+// voteUpsert builds the filter and update that keep a single vote per user and subject
+func voteUpsert(userID bson.ObjectID, subjectType, subjectID, choice string, now time.Time) (bson.M, bson.M) {
+	filter := bson.M{
+		"user_id":      userID,
+		"subject_type": subjectType,
+		"subject_id":   subjectID,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"choice":     choice,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": now,
+		},
+	}
+	return filter, update
+}
+
+// GetConsensus aggregates vote counts and percentages per choice for a subject
+func (s *VoteService) GetConsensus(ctx context.Context, subjectType, subjectID string) (*VoteConsensus, error) {
+	choices, ok := models.VoteChoices[subjectType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown subject type %q", ErrInvalidVote, subjectType)
+	}
+
+	cursor, err := s.db.Collection("votes").Aggregate(ctx, voteConsensusPipeline(subjectType, subjectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate votes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []voteCount
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode votes: %w", err)
+	}
+
+	return buildConsensus(subjectType, subjectID, tallyVotes(choices, results)), nil
+}
+
+// voteCount is one row of the consensus aggregation
+type voteCount struct {
+	Choice string `bson:"_id"`
+	Count  int    `bson:"count"`
+}
+
+// voteConsensusPipeline counts votes per choice for a subject
+func voteConsensusPipeline(subjectType, subjectID string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"subject_type": subjectType,
+			"subject_id":   subjectID,
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$choice",
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+}
+
+// tallyVotes maps aggregated rows onto every valid choice so unvoted choices report zero
+func tallyVotes(choices []string, results []voteCount) map[string]int {
+	counts := make(map[string]int, len(choices))
+	for _, choice := range choices {
+		counts[choice] = 0
+	}
+	for _, r := range results {
+		counts[r.Choice] = r.Count
+	}
+	return counts
+}
+
+// buildConsensus computes totals, percentages and the leading choice from raw counts
+func buildConsensus(subjectType, subjectID string, counts map[string]int) *VoteConsensus {
+	consensus := &VoteConsensus{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Counts:      counts,
+		Percentages: make(map[string]float64, len(counts)),
+	}
+
+	best := 0
+	for _, count := range counts {
+		consensus.TotalVotes += count
+	}
+	for choice, count := range counts {
+		if consensus.TotalVotes > 0 {
+			consensus.Percentages[choice] = float64(count) / float64(consensus.TotalVotes) * 100
+		} else {
+			consensus.Percentages[choice] = 0
+		}
+
+		switch {
+		case count > best:
+			best = count
+			consensus.Leader = choice
+		case count == best && count > 0:
+			consensus.Leader = "" // tie
+		}
+	}
+
+	return consensus
+}
+
+func validVoteChoice(subjectType, choice string) bool {
+	for _, c := range models.VoteChoices[subjectType] {
+		if c == choice {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// upsertVote applies a voteUpsert filter and update to an in-memory collection
+func upsertVote(t *testing.T, docs []bson.M, filter, update bson.M) []bson.M {
+	t.Helper()
+	set := asMap(t, update["$set"])
+	for _, doc := range docs {
+		if matchDoc(t, filter, doc) {
+			for k, v := range set {
+				doc[k] = v
+			}
+			return docs
+		}
+	}
+
+	doc := bson.M{"_id": bson.NewObjectID()}
+	for k, v := range filter {
+		doc[k] = v
+	}
+	for k, v := range asMap(t, update["$setOnInsert"]) {
+		doc[k] = v
+	}
+	for k, v := range set {
+		doc[k] = v
+	}
+	return append(docs, doc)
+}
+
+func TestVoteConsensusAfterOverwrite(t *testing.T) {
+	alice, bob, carol, dave := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+	start := time.Date(2025, 11, 16, 12, 0, 0, 0, time.UTC)
+
+	votes := []struct {
+		user    bson.ObjectID
+		subject string
+		choice  string
+	}{
+		{alice, "p1", "start"},
+		{bob, "p1", "start"},
+		{carol, "p1", "sit"},
+		{dave, "p1", "start"},
+		{bob, "p2", "sit"},    // another subject must not be counted
+		{alice, "p1", "sit"},  // alice switches to sit
+		{dave, "p1", "start"}, // repeating the same vote is a no-op
+	}
+
+	var docs []bson.M
+	for i, v := range votes {
+		filter, update := voteUpsert(v.user, models.VoteSubjectStartSit, v.subject, v.choice, start.Add(time.Duration(i)*time.Minute))
+		docs = upsertVote(t, docs, filter, update)
+	}
+
+	if len(docs) != 5 {
+		t.Fatalf("stored %d votes, want 5 (one per user and subject)", len(docs))
+	}
+	var stored []models.Vote
+	decodeDocs(t, docs, &stored)
+	for _, v := range stored {
+		if v.UserID == alice {
+			if v.Choice != "sit" {
+				t.Errorf("alice choice = %q, want sit", v.Choice)
+			}
+			if !v.CreatedAt.Equal(start) || !v.UpdatedAt.Equal(start.Add(5*time.Minute)) {
+				t.Errorf("alice created/updated = %v/%v, want %v/%v", v.CreatedAt, v.UpdatedAt, start, start.Add(5*time.Minute))
+			}
+		}
+	}
+
+	var rows []voteCount
+	decodeDocs(t, runPipeline(t, voteConsensusPipeline(models.VoteSubjectStartSit, "p1"), docs, nil), &rows)
+	consensus := buildConsensus(models.VoteSubjectStartSit, "p1", tallyVotes(models.VoteChoices[models.VoteSubjectStartSit], rows))
+
+	if consensus.TotalVotes != 4 {
+		t.Errorf("TotalVotes = %d, want 4", consensus.TotalVotes)
+	}
+	if consensus.Counts["start"] != 2 || consensus.Counts["sit"] != 2 {
+		t.Errorf("Counts = %v, want start=2 sit=2", consensus.Counts)
+	}
+	if consensus.Leader != "" {
+		t.Errorf("Leader = %q, want tie", consensus.Leader)
+	}
+}
+
+func TestBuildConsensus(t *testing.T) {
+	tests := []struct {
+		name        string
+		subjectType string
+		rows        []voteCount
+		total       int
+		percentages map[string]float64
+		leader      string
+	}{
+		{
+			name:        "no votes",
+			subjectType: models.VoteSubjectTrade,
+			percentages: map[string]float64{"accept": 0, "reject": 0},
+		},
+		{
+			name:        "clear leader",
+			subjectType: models.VoteSubjectStartSit,
+			rows:        []voteCount{{"start", 3}, {"sit", 1}},
+			total:       4,
+			percentages: map[string]float64{"start": 75, "sit": 25},
+			leader:      "start",
+		},
+		{
+			name:        "thirds",
+			subjectType: models.VoteSubjectTrade,
+			rows:        []voteCount{{"accept", 1}, {"reject", 2}},
+			total:       3,
+			percentages: map[string]float64{"accept": 100.0 / 3, "reject": 200.0 / 3},
+			leader:      "reject",
+		},
+		{
+			name:        "unvoted choices report zero",
+			subjectType: models.VoteSubjectPlayerProp,
+			rows:        []voteCount{{"lock", 5}},
+			total:       5,
+			percentages: map[string]float64{"over": 0, "under": 0, "lock": 100, "fade": 0},
+			leader:      "lock",
+		},
+		{
+			name:        "tie for the lead",
+			subjectType: models.VoteSubjectPlayerProp,
+			rows:        []voteCount{{"over", 2}, {"under", 2}, {"fade", 1}},
+			total:       5,
+			percentages: map[string]float64{"over": 40, "under": 40, "lock": 0, "fade": 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts := tallyVotes(models.VoteChoices[tt.subjectType], tt.rows)
+			got := buildConsensus(tt.subjectType, "s1", counts)
+
+			if got.TotalVotes != tt.total {
+				t.Errorf("TotalVotes = %d, want %d", got.TotalVotes, tt.total)
+			}
+			if len(got.Percentages) != len(tt.percentages) {
+				t.Errorf("Percentages = %v, want %v", got.Percentages, tt.percentages)
+			}
+			sum := 0.0
+			for choice, want := range tt.percentages {
+				sum += got.Percentages[choice]
+				if math.Abs(got.Percentages[choice]-want) > 1e-9 {
+					t.Errorf("Percentages[%s] = %v, want %v", choice, got.Percentages[choice], want)
+				}
+			}
+			if tt.total > 0 && math.Abs(sum-100) > 1e-9 {
+				t.Errorf("percentages sum to %v, want 100", sum)
+			}
+			if got.Leader != tt.leader {
+				t.Errorf("Leader = %q, want %q", got.Leader, tt.leader)
+			}
+		})
+	}
+}
+
+func TestValidVoteChoice(t *testing.T) {
+	tests := []struct {
+		subjectType string
+		choice      string
+		want        bool
+	}{
+		{models.VoteSubjectStartSit, "start", true},
+		{models.VoteSubjectStartSit, "accept", false},
+		{models.VoteSubjectTrade, "reject", true},
+		{models.VoteSubjectPlayerProp, "fade", true},
+		{"unknown", "start", false},
+	}
+
+	for _, tt := range tests {
+		if got := validVoteChoice(tt.subjectType, tt.choice); got != tt.want {
+			t.Errorf("validVoteChoice(%q, %q) = %v, want %v", tt.subjectType, tt.choice, got, tt.want)
+		}
+	}
+}
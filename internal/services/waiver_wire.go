@@ -3,23 +3,27 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/prompts"
+	"github.com/ai-atl/nfl-platform/internal/season"
 	"github.com/ai-atl/nfl-platform/pkg/gemini"
-	"github.com/ai-atl/nfl-platform/pkg/sleeper"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type WaiverWireService struct {
-	db            *mongo.Database
-	gemini        *gemini.Client
-	dataService   *DataService
-	sleeperClient *sleeper.Client
+	db          *mongo.Database
+	gemini      *gemini.Client
+	dataService *DataService
+	projections Projections
+	scoring     ScoringConfig
+	faabBudget  int // Remaining FAAB dollars; 0 means no bid suggestions
 }
 
 type WaiverGem struct {
@@ -33,6 +37,9 @@ type WaiverGem struct {
 	TargetShareTrend string  `json:"targetShareTrend"` // "increasing", "stable", "decreasing"
 	SnapCountPct     float64 `json:"snapCountPct"`     // Recent snap percentage
 	EPAPerPlay       float64 `json:"epaPerPlay"`
+	EPAPercentile    float64 `json:"epaPercentile"`           // 0-100 among qualified players at the position; 0 if unqualified
+	ADOT             float64 `json:"adot,omitempty"`          // Average depth of target (WR/TE)
+	AirYardsShare    float64 `json:"airYardsShare,omitempty"` // Share of team air yards, 0-1 (WR/TE)
 
 	// Opportunity analysis
 	DepthChartStatus string `json:"depthChartStatus"` // "starter injured", "increased role", "backup"
@@ -46,6 +53,10 @@ type WaiverGem struct {
 	// AI analysis
 	AIAnalysis     string `json:"aiAnalysis"`
 	Recommendation string `json:"recommendation"` // "Must Add", "Strong Add", "Monitor", "Pass"
+
+	// FAAB bid suggestion, only set when a remaining budget is given
+	FAABBidPct float64 `json:"faabBidPct,omitempty"` // Percent of the remaining budget
+	FAABBid    int     `json:"faabBid,omitempty"`    // Whole dollars
 }
 
 type GameStats struct {
@@ -58,36 +69,46 @@ type GameStats struct {
 	FantasyPoints float64 `json:"fantasyPoints"`
 }
 
-type ScheduleAnalysis struct {
-	NextThreeOpponents []string
-	AvgDefensiveRank   float64
-	Difficulty         string
-}
-
 func NewWaiverWireService(db *mongo.Database) *WaiverWireService {
 	return &WaiverWireService{
-		db:            db,
-		gemini:        gemini.NewClient(),
-		dataService:   NewDataService(db),
-		sleeperClient: sleeper.NewClient(),
+		db:          db,
+		gemini:      gemini.NewClient(gemini.AnalysisTier()),
+		dataService: NewDataService(db),
+		projections: NewTrailingAverageProjections(db),
+		scoring:     ScoringPPR,
 	}
 }
 
+// WithScoring returns a copy of the service that scores games with the given settings
+func (s *WaiverWireService) WithScoring(scoring ScoringConfig) *WaiverWireService {
+	scored := *s
+	scored.scoring = scoring
+	scored.projections = projectionsWithScoring(s.projections, scoring)
+	return &scored
+}
+
+// WithFAABBudget returns a copy of the service that suggests a FAAB bid for each gem out of
+// the given remaining budget
+func (s *WaiverWireService) WithFAABBudget(budget int) *WaiverWireService {
+	budgeted := *s
+	budgeted.faabBudget = budget
+	return &budgeted
+}
+
 // FindWaiverGems identifies undervalued players with breakout potential
 func (s *WaiverWireService) FindWaiverGems(ctx context.Context, position string, limit int) ([]WaiverGem, error) {
-	season := 2025
-	currentWeek := 10
+	currentSeason, currentWeek := season.Current(ctx)
 
 	// Get all players for the position (limit initial query for performance)
 	var positionFilter bson.M
 	maxPlayersToAnalyze := 20 // Reduced to 20 for faster analysis
 
 	if position != "" && position != "ALL" {
-		positionFilter = bson.M{"position": position, "season": season}
+		positionFilter = bson.M{"position": position, "season": currentSeason}
 	} else {
 		positionFilter = bson.M{
 			"position": bson.M{"$in": []string{"QB", "RB", "WR", "TE"}},
-			"season":   season,
+			"season":   currentSeason,
 		}
 		maxPlayersToAnalyze = 30 // Reduced to 30 for ALL positions
 	}
@@ -115,7 +136,7 @@ func (s *WaiverWireService) FindWaiverGems(ctx context.Context, position string,
 			fmt.Printf("Progress: %d/%d players analyzed\n", i, len(players))
 		}
 
-		gem := s.analyzeBreakoutPotential(ctx, player, season, currentWeek)
+		gem := s.analyzeBreakoutPotential(ctx, player, currentSeason, currentWeek)
 		if gem != nil && gem.BreakoutScore > 0 { // Include all players for now (no real data yet)
 			gems = append(gems, *gem)
 		}
@@ -143,6 +164,7 @@ func (s *WaiverWireService) FindWaiverGems(ctx context.Context, position string,
 		}
 	}
 
+	s.suggestBids(gems)
 	return gems, nil
 }
 
@@ -152,17 +174,14 @@ type RosterPlayer struct {
 	Position        string  `json:"position"`
 	ProjectedPoints float64 `json:"projectedPoints"`
 	LineupSlot      string  `json:"lineupSlot"`
+	ProTeam         string  `json:"proTeam,omitempty"`
+	InjuryStatus    string  `json:"injuryStatus,omitempty"` // ESPN status, e.g. ACTIVE, QUESTIONABLE, OUT
 }
 
 // FindPersonalizedWaiverGems analyzes waiver wire based on user's roster needs
 func (s *WaiverWireService) FindPersonalizedWaiverGems(ctx context.Context, roster []RosterPlayer, position string, limit int) ([]WaiverGem, error) {
-	// Analyze roster strength by position
-	positionStrength := s.analyzeRosterStrength(roster)
-
-	// Find weak positions that need upgrades
-	weakPositions := s.identifyWeakPositions(positionStrength)
-
-	fmt.Printf("Roster analysis: Weak positions: %v, Position filter: %s\n", weakPositions, position)
+	currentSeason, currentWeek := season.Current(ctx)
+	baselines := s.positionBaselines(ctx, currentSeason, currentWeek)
 
 	// Get waiver gems (filter by position if specified)
 	searchPosition := position
@@ -177,6 +196,22 @@ func (s *WaiverWireService) FindPersonalizedWaiverGems(ctx context.Context, rost
 
 	fmt.Printf("Found %d candidates for position: %s\n", len(allGems), searchPosition)
 
+	allGems = s.personalizeGems(allGems, roster, baselines, limit)
+
+	// Bid on the roster-adjusted scores
+	s.suggestBids(allGems)
+	return allGems, nil
+}
+
+// personalizeGems reranks waiver gems for a roster, boosting positions where the roster's
+// starters project more than 15% below the league baselines, and keeps the top limit
+func (s *WaiverWireService) personalizeGems(allGems []WaiverGem, roster []RosterPlayer, baselines map[string]float64, limit int) []WaiverGem {
+	// Analyze roster strength by position
+	positionStrength := s.analyzeRosterStrength(roster)
+
+	// Find weak positions that need upgrades
+	weakPositions := s.identifyWeakPositions(positionStrength, baselines)
+
 	// Prioritize based on roster needs
 	for i := range allGems {
 		allGems[i].BreakoutScore = s.adjustScoreForRosterFit(allGems[i], positionStrength, weakPositions)
@@ -200,8 +235,7 @@ func (s *WaiverWireService) FindPersonalizedWaiverGems(ctx context.Context, rost
 	if limit > 0 && len(allGems) > limit {
 		allGems = allGems[:limit]
 	}
-
-	return allGems, nil
+	return allGems
 }
 
 // analyzeRosterStrength calculates average projected points by position
@@ -226,19 +260,108 @@ func (s *WaiverWireService) analyzeRosterStrength(roster []RosterPlayer) map[str
 	return averages
 }
 
-// identifyWeakPositions finds positions that need upgrades
-func (s *WaiverWireService) identifyWeakPositions(positionStrength map[string]float64) []string {
-	// League average projected points by position (approximate)
-	leagueAverages := map[string]float64{
-		"QB": 18.0,
-		"RB": 12.0,
-		"WR": 10.0,
-		"TE": 8.0,
+// defaultPositionBaselines are approximate weekly points for a starter at each position,
+// used when there is too little data to project the league's starters
+var defaultPositionBaselines = map[string]float64{
+	"QB": 18.0,
+	"RB": 12.0,
+	"WR": 10.0,
+	"TE": 8.0,
+}
+
+// startersPerPosition is how many players start at each position across a 12-team league
+var startersPerPosition = map[string]int{
+	"QB": 12,
+	"RB": 24,
+	"WR": 36,
+	"TE": 12,
+}
+
+// positionBaselines returns the average projected points of the league's starting-caliber
+// players at each position (the top scorers so far this season), falling back to
+// defaultPositionBaselines for positions that can't be projected
+func (s *WaiverWireService) positionBaselines(ctx context.Context, season, week int) map[string]float64 {
+	baselines := make(map[string]float64, len(defaultPositionBaselines))
+	for pos, avg := range defaultPositionBaselines {
+		baselines[pos] = avg
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"season": season, "week": bson.M{"$lt": week}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$nfl_id",
+			"points": bson.M{"$sum": s.scoring.pointsExpr()},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "points", Value: -1}}}},
+		{{Key: "$limit", Value: 250}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "players",
+			"let":  bson.M{"nfl_id": "$_id"},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"$expr": bson.M{"$and": []bson.M{
+						{"$eq": []interface{}{"$nfl_id", "$$nfl_id"}},
+						{"$eq": []interface{}{"$season", season}},
+					}},
+				}}},
+				{{Key: "$project", Value: bson.M{"position": 1}}},
+				{{Key: "$limit", Value: 1}},
+			},
+			"as": "player",
+		}}},
+		{{Key: "$unwind", Value: "$player"}},
+		{{Key: "$project", Value: bson.M{"position": "$player.position"}}},
 	}
 
+	cursor, err := s.db.Collection("player_weekly_stats").Aggregate(ctx, pipeline)
+	if err != nil {
+		return baselines
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		NFLID    string `bson:"_id"`
+		Position string `bson:"position"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return baselines
+	}
+
+	// Results are sorted by season points, so the first N at each position are its starters
+	starters := make(map[string][]string, len(startersPerPosition))
+	var ids []string
+	for _, r := range results {
+		if limit, ok := startersPerPosition[r.Position]; ok && len(starters[r.Position]) < limit {
+			starters[r.Position] = append(starters[r.Position], r.NFLID)
+			ids = append(ids, r.NFLID)
+		}
+	}
+	if len(ids) == 0 {
+		return baselines
+	}
+
+	projections := projectAll(ctx, s.projections, ids, season, week)
+	for pos, posIDs := range starters {
+		total, count := 0.0, 0
+		for _, id := range posIDs {
+			if points, ok := projections[id]; ok {
+				total += points
+				count++
+			}
+		}
+		if count > 0 {
+			baselines[pos] = total / float64(count)
+		}
+	}
+
+	return baselines
+}
+
+// identifyWeakPositions finds positions averaging more than 15% below the league baseline
+func (s *WaiverWireService) identifyWeakPositions(positionStrength, baselines map[string]float64) []string {
 	weak := []string{}
 	for pos, avg := range positionStrength {
-		if leagueAvg, ok := leagueAverages[pos]; ok {
+		if leagueAvg, ok := baselines[pos]; ok {
 			if avg < leagueAvg*0.85 { // If 15% below league average
 				weak = append(weak, pos)
 			}
@@ -280,31 +403,16 @@ func (s *WaiverWireService) generatePersonalizedAnalysis(ctx context.Context, ge
 
 	posAvg := positionStrength[gem.Position]
 
-	prompt := fmt.Sprintf(`Analyze this waiver wire pickup for a fantasy team:
-
-Player: %s (%s, %s)
-Breakout Score: %.0f/100
-EPA per Play: %.2f
-Snap %%: %.1f
-Trend: %s
-
-Current %s starters on roster:
-`, gem.PlayerName, gem.Position, gem.Team, gem.BreakoutScore, gem.EPAPerPlay, gem.SnapCountPct, gem.TargetShareTrend, gem.Position)
-
-	if len(currentStarters) > 0 {
-		for _, starter := range currentStarters {
-			prompt += fmt.Sprintf("- %s (%.1f projected pts)\n", starter.Name, starter.ProjectedPoints)
-		}
-		prompt += fmt.Sprintf("Team's %s average: %.1f pts/week\n\n", gem.Position, posAvg)
-	} else {
-		prompt += fmt.Sprintf("No current starters at %s\n\n", gem.Position)
+	prompt, err := prompts.Render(prompts.WaiverFit, struct {
+		Gem              *WaiverGem
+		AirYardsSharePct float64
+		Starters         []RosterPlayer
+		PositionAverage  float64
+	}{gem, gem.AirYardsShare * 100, currentStarters, posAvg})
+	if err != nil {
+		return gem.AIAnalysis // Fallback to generic analysis
 	}
 
-	prompt += `In 2-3 sentences, explain:
-1. How this player would improve their team
-2. Whether they should start immediately or be a bench stash
-3. Specific roster move recommendation (who to drop/bench)`
-
 	response, err := s.gemini.Generate(ctx, prompt)
 	if err != nil {
 		return gem.AIAnalysis // Fallback to generic analysis
@@ -335,19 +443,25 @@ func (s *WaiverWireService) analyzeBreakoutPotential(ctx context.Context, player
 	// Use player's aggregated stats (these are already calculated in players collection)
 	gem.LastThreeGames = []GameStats{} // Empty for now - would need different data source
 
-	// Get real snap count from Sleeper API
-	// Try last 3 weeks to find the most recent game this player played
+	// Use the snap share from the most recent game the player played in the last 3 weeks
 	gem.SnapCountPct = 0.0
-	for week := 10; week >= 8; week-- {
-		snapPct, err := s.sleeperClient.GetPlayerSnapCount(ctx, player.Name, "2025", week)
-		if err == nil && snapPct > 0 {
-			gem.SnapCountPct = snapPct
-			break // Found recent snap data
-		}
+	if snaps, err := s.dataService.GetSnapCounts(ctx, player.NFLID, season, currentWeek-3, currentWeek-1); err == nil && len(snaps) > 0 {
+		gem.SnapCountPct = snaps[0].OffensePct
+	}
+
+	// Get EPA per play from plays collection for the season (using player name)
+	gem.EPAPerPlay = s.getPlayerEPAPerPlay(ctx, player.Name, season)
+	if pct, err := s.dataService.GetEPAPercentile(ctx, player.NFLID, player.Position, season); err == nil {
+		gem.EPAPercentile = pct.Percentile
 	}
 
-	// Get EPA per play from plays collection for 2025 season (using player name)
-	gem.EPAPerPlay = s.getPlayerEPAPerPlay(ctx, player.Name, 2025)
+	// Downfield role for pass catchers
+	if player.Position == "WR" || player.Position == "TE" {
+		if air, err := s.dataService.GetReceiverAirYards(ctx, player.NFLID, season); err == nil {
+			gem.ADOT = air.ADOT
+			gem.AirYardsShare = air.AirYardsShare
+		}
+	}
 
 	// Set default trends without expensive query
 	gem.TargetShareTrend = "stable"
@@ -364,8 +478,13 @@ func (s *WaiverWireService) analyzeBreakoutPotential(ctx context.Context, player
 		gem.DepthChartStatus = "unknown"
 	}
 
-	gem.UpcomingSchedule = "average" // Default - would need schedule API
-	gem.ScheduleRank = 16            // Default middle rank
+	// Rate the rest of the schedule against this position (ScheduleRank: lower is easier)
+	gem.UpcomingSchedule = "average"
+	gem.ScheduleRank = 16
+	if outlook, err := s.dataService.RestOfSeasonSchedule(ctx, player.Team, player.Position, season, currentWeek); err == nil && outlook.GamesRemaining > 0 {
+		gem.UpcomingSchedule = outlook.Difficulty
+		gem.ScheduleRank = 33 - int(math.Round(outlook.AvgOpponentRank))
+	}
 
 	// Calculate breakout score (0-100)
 	gem.BreakoutScore = s.calculateBreakoutScore(gem)
@@ -532,6 +651,14 @@ func (s *WaiverWireService) getRecentGameStats(ctx context.Context, nflID, posit
 	}
 	defer cursor.Close(ctx)
 
+	// Real snap shares from participation data, where loaded
+	snapPcts := make(map[int]float64)
+	if snaps, err := s.dataService.GetSnapCounts(ctx, nflID, season, currentWeek-numGames-2, currentWeek-1); err == nil {
+		for _, snap := range snaps {
+			snapPcts[snap.Week] = snap.OffensePct
+		}
+	}
+
 	var games []GameStats
 	for cursor.Next(ctx) {
 		var result struct {
@@ -550,10 +677,13 @@ func (s *WaiverWireService) getRecentGameStats(ctx context.Context, nflID, posit
 			continue
 		}
 
-		// Calculate fantasy points (PPR)
-		fantasyPts := float64(result.RecYards+result.RushYards)*0.1 +
-			float64(result.RecTDs+result.RushTDs)*6.0 +
-			float64(result.Receptions)*1.0
+		fantasyPts := s.scoring.Points(StatLine{
+			RushingYards:   result.RushYards,
+			RushingTDs:     result.RushTDs,
+			Receptions:     result.Receptions,
+			ReceivingYards: result.RecYards,
+			ReceivingTDs:   result.RecTDs,
+		})
 
 		// Build production string
 		production := ""
@@ -569,10 +699,10 @@ func (s *WaiverWireService) getRecentGameStats(ctx context.Context, nflID, posit
 			}
 		}
 
-		// Estimate snap percentage (plays involved / ~60 offensive plays per game)
-		snapPct := float64(result.TotalPlays) / 60.0 * 100
-		if snapPct > 100 {
-			snapPct = 100
+		// Fall back to estimating snap percentage (plays involved / ~60 offensive plays per game)
+		snapPct, ok := snapPcts[result.Week]
+		if !ok {
+			snapPct = math.Min(float64(result.TotalPlays)/60.0*100, 100)
 		}
 
 		// Estimate target share (targets / ~30 team pass attempts)
@@ -641,112 +771,6 @@ func (s *WaiverWireService) checkDepthChartStatus(ctx context.Context, player mo
 	return "Normal role"
 }
 
-// analyzeUpcomingSchedule looks at next 3 opponents' defensive strength
-func (s *WaiverWireService) analyzeUpcomingSchedule(ctx context.Context, team, position string, season, currentWeek int) ScheduleAnalysis {
-	analysis := ScheduleAnalysis{
-		NextThreeOpponents: []string{},
-		AvgDefensiveRank:   16,
-		Difficulty:         "average",
-	}
-
-	// Get next 3 games
-	cursor, err := s.db.Collection("games").Find(ctx, bson.M{
-		"season": season,
-		"week":   bson.M{"$gte": currentWeek, "$lte": currentWeek + 2},
-		"$or": []bson.M{
-			{"home_team": team},
-			{"away_team": team},
-		},
-	})
-
-	if err != nil {
-		return analysis
-	}
-	defer cursor.Close(ctx)
-
-	var games []models.Game
-	cursor.All(ctx, &games)
-
-	totalDefensiveEPA := 0.0
-	for _, game := range games {
-		opponent := game.AwayTeam
-		if opponent == team {
-			opponent = game.HomeTeam
-		}
-		analysis.NextThreeOpponents = append(analysis.NextThreeOpponents, opponent)
-
-		// Get opponent's defensive EPA vs this position
-		defEPA := s.getDefensiveEPA(ctx, opponent, position, season, currentWeek)
-		totalDefensiveEPA += defEPA
-	}
-
-	if len(games) > 0 {
-		avgEPA := totalDefensiveEPA / float64(len(games))
-
-		// Rank based on EPA (lower EPA = better defense = harder matchup)
-		if avgEPA < -0.1 {
-			analysis.Difficulty = "difficult"
-			analysis.AvgDefensiveRank = 8
-		} else if avgEPA < 0 {
-			analysis.Difficulty = "average"
-			analysis.AvgDefensiveRank = 16
-		} else if avgEPA > 0.1 {
-			analysis.Difficulty = "favorable"
-			analysis.AvgDefensiveRank = 28
-		} else {
-			analysis.Difficulty = "average"
-			analysis.AvgDefensiveRank = 18
-		}
-	}
-
-	return analysis
-}
-
-// getDefensiveEPA calculates how good a defense is vs a position
-func (s *WaiverWireService) getDefensiveEPA(ctx context.Context, defenseTeam, position string, season, currentWeek int) float64 {
-	var matchCondition bson.M
-
-	switch position {
-	case "QB":
-		matchCondition = bson.M{"passer_player_id": bson.M{"$ne": ""}}
-	case "RB":
-		matchCondition = bson.M{"rusher_player_id": bson.M{"$ne": ""}}
-	case "WR", "TE":
-		matchCondition = bson.M{"receiver_player_id": bson.M{"$ne": ""}}
-	default:
-		return 0
-	}
-
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{
-			"season":       season,
-			"week":         bson.M{"$lt": currentWeek},
-			"defense_team": defenseTeam,
-		}}},
-		{{Key: "$match", Value: matchCondition}},
-		{{Key: "$group", Value: bson.M{
-			"_id":     nil,
-			"avg_epa": bson.M{"$avg": "$epa"},
-		}}},
-	}
-
-	cursor, err := s.db.Collection("plays").Aggregate(ctx, pipeline)
-	if err != nil {
-		return 0
-	}
-	defer cursor.Close(ctx)
-
-	if cursor.Next(ctx) {
-		var result struct {
-			AvgEPA float64 `bson:"avg_epa"`
-		}
-		cursor.Decode(&result)
-		return result.AvgEPA
-	}
-
-	return 0
-}
-
 // calculateBreakoutScore computes 0-100 score based on all factors
 func (s *WaiverWireService) calculateBreakoutScore(gem *WaiverGem) float64 {
 	score := 0.0
@@ -818,6 +842,58 @@ func (s *WaiverWireService) determineRecommendation(score float64) string {
 	return "❌ Pass"
 }
 
+// FAAB bids scale from faabMinBidPct of the remaining budget at faabMinScore up to
+// faabMaxBidPct at a breakout score of 100; gems scoring below faabMinScore are a Pass
+const (
+	faabMinScore  = 40.0
+	faabMinBidPct = 1.0
+	faabMaxBidPct = 35.0
+)
+
+// faabScarcity scales bids by how hard a startable player at the position is to find on
+// waivers; positions not listed use 1
+var faabScarcity = map[string]float64{
+	"RB": 1.25,
+	"WR": 1.0,
+	"TE": 0.9,
+	"QB": 0.75,
+}
+
+// suggestBids sets each gem's FAAB bid when the service has a budget
+func (s *WaiverWireService) suggestBids(gems []WaiverGem) {
+	if s.faabBudget <= 0 {
+		return
+	}
+	for i := range gems {
+		gems[i].FAABBidPct, gems[i].FAABBid = suggestFAABBid(gems[i].BreakoutScore, gems[i].Position, s.faabBudget)
+	}
+}
+
+// suggestFAABBid turns a breakout score into a bid as a percentage of the remaining budget
+// and in whole dollars (at least $1 for any bid, never more than the budget). Bids rise with
+// the score and are scaled by positional scarcity.
+func suggestFAABBid(score float64, position string, budget int) (float64, int) {
+	if budget <= 0 || score < faabMinScore {
+		return 0, 0
+	}
+
+	score = math.Min(score, 100)
+	pct := faabMinBidPct + (score-faabMinScore)/(100-faabMinScore)*(faabMaxBidPct-faabMinBidPct)
+	if scarcity, ok := faabScarcity[position]; ok {
+		pct *= scarcity
+	}
+	pct = math.Min(math.Round(pct*10)/10, 100)
+
+	bid := int(math.Round(pct / 100 * float64(budget)))
+	if bid < 1 {
+		bid = 1
+	}
+	if bid > budget {
+		bid = budget
+	}
+	return pct, bid
+}
+
 // calculatePlayerEPA gets EPA for recent weeks
 func (s *WaiverWireService) calculatePlayerEPA(ctx context.Context, playerID string) float64 {
 	filter := bson.M{
@@ -863,36 +939,13 @@ func (s *WaiverWireService) generateAIAnalysis(ctx context.Context, gem *WaiverG
 			game.Week, game.Opponent, game.Production, game.FantasyPoints, game.SnapPct))
 	}
 
-	prompt := fmt.Sprintf(`You are an expert fantasy football waiver wire analyst. Analyze this breakout candidate:
-
-Player: %s (%s - %s)
-Breakout Score: %.0f/100
-
-KEY METRICS:
-- EPA per play: %.3f (efficiency)
-- Snap Count: %.0f%% (recent usage)
-- Target Share Trend: %s
-- Depth Chart: %s
-- Upcoming Schedule: %s (next 3 weeks rank #%d)
-
-RECENT PERFORMANCE:
-%s
-
-Provide a 2-3 sentence analysis covering:
-1. Why this player has breakout potential NOW
-2. The specific opportunity (injury, role change, or favorable matchups)
-3. A clear action recommendation for fantasy managers
-
-Be data-driven, concise, and actionable.`,
-		gem.PlayerName, gem.Position, gem.Team,
-		gem.BreakoutScore,
-		gem.EPAPerPlay,
-		gem.SnapCountPct,
-		gem.TargetShareTrend,
-		gem.DepthChartStatus,
-		gem.UpcomingSchedule, gem.ScheduleRank,
-		recentPerf.String(),
-	)
+	prompt, err := prompts.Render(prompts.WaiverBreakout, struct {
+		Gem               *WaiverGem
+		RecentPerformance string
+	}{gem, recentPerf.String()})
+	if err != nil {
+		return "AI analysis unavailable"
+	}
 
 	response, err := s.gemini.GenerateWithRetry(ctx, prompt, 2)
 	if err != nil {
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// fakeRosterSource serves a fixed connected roster in place of the ESPN service
+type fakeRosterSource struct {
+	rosters map[bson.ObjectID][]RosterPlayer
+}
+
+func (f fakeRosterSource) FetchRoster(ctx context.Context, userID bson.ObjectID) ([]RosterPlayer, error) {
+	roster, ok := f.rosters[userID]
+	if !ok {
+		return nil, ErrESPNNotConnected
+	}
+	return roster, nil
+}
+
+func TestPersonalizeGemsFromConnectedRoster(t *testing.T) {
+	userID := bson.NewObjectID()
+	var source ESPNRosterSource = fakeRosterSource{rosters: map[bson.ObjectID][]RosterPlayer{
+		userID: {
+			{Name: "Josh Allen", Position: "QB", ProjectedPoints: 24, LineupSlot: "QB"},
+			{Name: "Rhamondre Stevenson", Position: "RB", ProjectedPoints: 7, LineupSlot: "RB"},
+			{Name: "Zack Moss", Position: "RB", ProjectedPoints: 6, LineupSlot: "RB"},
+			{Name: "Ja'Marr Chase", Position: "WR", ProjectedPoints: 19, LineupSlot: "WR"},
+			{Name: "Nico Collins", Position: "WR", ProjectedPoints: 15, LineupSlot: "WR"},
+			{Name: "Bench RB", Position: "RB", ProjectedPoints: 30, LineupSlot: "BE"},
+		},
+	}}
+
+	roster, err := source.FetchRoster(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("FetchRoster() error = %v", err)
+	}
+
+	gems := []WaiverGem{
+		{PlayerName: "Jalen McMillian", Position: "WR", BreakoutScore: 70, AIAnalysis: "Rising targets."},
+		{PlayerName: "Tank Bigsby", Position: "RB", BreakoutScore: 60, AIAnalysis: "Goal-line role."},
+		{PlayerName: "Tucker Kraft", Position: "TE", BreakoutScore: 55},
+		{PlayerName: "Ray Davis", Position: "RB", BreakoutScore: 90},
+	}
+	baselines := map[string]float64{"QB": 18, "RB": 12, "WR": 10, "TE": 8}
+
+	got := NewWaiverWireService(nil).personalizeGems(gems, roster, baselines, 3)
+
+	// The starting RBs average 6.5 against a 12-point baseline, so RBs get the team-need boost
+	// (capped at 100) while WR and TE keep their scores. Bench players don't count.
+	want := []struct {
+		name  string
+		score float64
+	}{
+		{"Ray Davis", 100},
+		{"Tank Bigsby", 80},
+		{"Jalen McMillian", 70},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("personalizeGems() returned %d gems, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].PlayerName != w.name || got[i].BreakoutScore != w.score {
+			t.Errorf("gem %d = %s (%v), want %s (%v)", i, got[i].PlayerName, got[i].BreakoutScore, w.name, w.score)
+		}
+	}
+	if got[1].AIAnalysis != "🎯 TEAM NEED: Your RB average 6.5 pts/week. Goal-line role." {
+		t.Errorf("RB analysis = %q, want the team need prefix", got[1].AIAnalysis)
+	}
+
+	if _, err := source.FetchRoster(context.Background(), bson.NewObjectID()); err != ErrESPNNotConnected {
+		t.Errorf("FetchRoster() for an unconnected user error = %v, want ErrESPNNotConnected", err)
+	}
+}
+
+func TestSuggestFAABBidMonotonic(t *testing.T) {
+	positions := []string{"QB", "RB", "WR", "TE", "K"}
+	budgets := []int{1, 7, 100, 1000}
+
+	for _, position := range positions {
+		for _, budget := range budgets {
+			prevPct, prevBid := 0.0, 0
+			for score := 0.0; score <= 120; score += 0.5 {
+				pct, bid := suggestFAABBid(score, position, budget)
+				if pct < prevPct || bid < prevBid {
+					t.Fatalf("%s budget %d: score %.1f bid %.1f%%/$%d is below the previous %.1f%%/$%d",
+						position, budget, score, pct, bid, prevPct, prevBid)
+				}
+				if bid > budget || pct > 100 {
+					t.Fatalf("%s budget %d: score %.1f bid %.1f%%/$%d exceeds the budget", position, budget, score, pct, bid)
+				}
+				if score >= faabMinScore && bid < 1 {
+					t.Fatalf("%s budget %d: score %.1f bid $%d, want at least $1", position, budget, score, bid)
+				}
+				prevPct, prevBid = pct, bid
+			}
+		}
+	}
+}
+
+func TestSuggestFAABBid(t *testing.T) {
+	tests := []struct {
+		name     string
+		score    float64
+		position string
+		budget   int
+		wantPct  float64
+		wantBid  int
+	}{
+		{"below the minimum score is a pass", faabMinScore - 0.1, "WR", 100, 0, 0},
+		{"no budget", 90, "RB", 0, 0, 0},
+		{"minimum score", faabMinScore, "WR", 100, faabMinBidPct, 1},
+		{"perfect score", 100, "WR", 100, faabMaxBidPct, 35},
+		{"scores past 100 are capped", 150, "WR", 100, faabMaxBidPct, 35},
+		{"RB scarcity raises the bid", 100, "RB", 100, 43.8, 44},
+		{"QB depth lowers the bid", 100, "QB", 100, 26.3, 26},
+		{"unlisted position uses 1", 100, "K", 100, faabMaxBidPct, 35},
+		{"small budgets still bid $1", faabMinScore, "TE", 10, 0.9, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pct, bid := suggestFAABBid(tt.score, tt.position, tt.budget)
+			if pct != tt.wantPct || bid != tt.wantBid {
+				t.Errorf("suggestFAABBid(%v, %q, %d) = %v%%/$%d, want %v%%/$%d", tt.score, tt.position, tt.budget, pct, bid, tt.wantPct, tt.wantBid)
+			}
+		})
+	}
+}
+
+func TestSuggestFAABBidScarcityOrder(t *testing.T) {
+	// At the same score a scarcer position never gets a smaller bid
+	order := []string{"RB", "WR", "TE", "QB"}
+	for score := faabMinScore; score <= 100; score += 5 {
+		for i := 1; i < len(order); i++ {
+			higher, _ := suggestFAABBid(score, order[i-1], 100)
+			lower, _ := suggestFAABBid(score, order[i], 100)
+			if lower > higher {
+				t.Errorf("score %.0f: %s bid %.1f%% above %s bid %.1f%%", score, order[i], lower, order[i-1], higher)
+			}
+		}
+	}
+}
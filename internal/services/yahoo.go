@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -24,10 +26,31 @@ type YahooTeam struct {
 	LogoURL    string `json:"logo_url,omitempty"`
 }
 
+// YahooPlayer is a player on a Yahoo fantasy roster
+type YahooPlayer struct {
+	PlayerKey         string   `json:"player_key"`
+	Name              string   `json:"name"`
+	Team              string   `json:"team"`     // NFL team abbreviation
+	Position          string   `json:"position"` // Display position, e.g. WR or WR,TE
+	EligiblePositions []string `json:"eligible_positions"`
+	SelectedPosition  string   `json:"selected_position"` // Roster slot this week, BN for bench
+	Status            string   `json:"status,omitempty"`  // Q, D, O, IR, etc.; empty when healthy
+	StatusFull        string   `json:"status_full,omitempty"`
+	InjuryNote        string   `json:"injury_note,omitempty"`
+}
+
+// yahooAPIBase is the Yahoo Fantasy Sports API root
+const yahooAPIBase = "https://fantasysports.yahooapis.com/fantasy/v2"
+
 type YahooService struct {
 	db          *mongo.Database
 	oauthConfig *oauth2.Config
 	httpClient  *http.Client
+	apiBase     string
+
+	// updateUser applies an update to a user document; a field so tests can run
+	// without MongoDB
+	updateUser func(ctx context.Context, userID bson.ObjectID, update bson.M) error
 }
 
 func NewYahooService(db *mongo.Database, cfg *config.Config) *YahooService {
@@ -45,13 +68,21 @@ func NewYahooService(db *mongo.Database, cfg *config.Config) *YahooService {
 		}
 	}
 
-	return &YahooService{
+	s := &YahooService{
 		db:          db,
 		oauthConfig: oauthCfg,
 		httpClient: &http.Client{
 			Timeout: 15 * time.Second,
 		},
+		apiBase: yahooAPIBase,
 	}
+	s.updateUser = s.updateUserDoc
+	return s
+}
+
+func (s *YahooService) updateUserDoc(ctx context.Context, userID bson.ObjectID, update bson.M) error {
+	_, err := s.db.Collection("users").UpdateByID(ctx, userID, update)
+	return err
 }
 
 func (s *YahooService) Enabled() bool {
@@ -117,7 +148,7 @@ func (s *YahooService) RefreshIfNeeded(ctx context.Context, user *models.User, t
 			update["$set"].(bson.M)["yahoo_refresh_token"] = refreshedToken.RefreshToken
 		}
 
-		if _, err := s.db.Collection("users").UpdateByID(ctx, user.ID, update); err != nil {
+		if err := s.updateUser(ctx, user.ID, update); err != nil {
 			return nil, fmt.Errorf("failed to persist refreshed token: %w", err)
 		}
 
@@ -146,8 +177,7 @@ func (s *YahooService) SaveToken(ctx context.Context, userID bson.ObjectID, toke
 		},
 	}
 
-	_, err := s.db.Collection("users").UpdateByID(ctx, userID, update)
-	if err != nil {
+	if err := s.updateUser(ctx, userID, update); err != nil {
 		return fmt.Errorf("failed to store yahoo tokens: %w", err)
 	}
 
@@ -168,26 +198,54 @@ func (s *YahooService) LoadUser(ctx context.Context, userID string) (*models.Use
 	return &user, nil
 }
 
-func (s *YahooService) FetchTeams(ctx context.Context, token *oauth2.Token) ([]YahooTeam, error) {
+// FetchTeams returns the user's NFL fantasy teams
+func (s *YahooService) FetchTeams(ctx context.Context, user *models.User, token *oauth2.Token) ([]YahooTeam, error) {
+	payload, err := s.get(ctx, user, token, s.apiBase+"/users;use_login=1/games;game_keys=nfl/teams?format=json")
+	if err != nil {
+		return nil, err
+	}
+
+	return extractTeams(payload), nil
+}
+
+// FetchRoster returns the current roster for a Yahoo team_key
+func (s *YahooService) FetchRoster(ctx context.Context, user *models.User, token *oauth2.Token, teamKey string) ([]YahooPlayer, error) {
+	payload, err := s.get(ctx, user, token, s.apiBase+"/team/"+url.PathEscape(teamKey)+"/roster?format=json")
+	if err != nil {
+		return nil, err
+	}
+
+	return extractRoster(payload), nil
+}
+
+// get calls a Yahoo Fantasy API URL and decodes the JSON response. If Yahoo rejects the
+// access token with a 401 (revoked, or expired earlier than its recorded expiry), the
+// token is refreshed once, persisted to the user, and the request retried.
+func (s *YahooService) get(ctx context.Context, user *models.User, token *oauth2.Token, apiURL string) (map[string]any, error) {
 	if s.oauthConfig == nil {
 		return nil, errors.New("yahoo oauth not configured")
 	}
 
-	req, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodGet,
-		"https://fantasysports.yahooapis.com/fantasy/v2/users;use_login=1/games;game_keys=nfl/teams?format=json",
-		nil,
-	)
+	resp, err := s.do(ctx, token, apiURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
 
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query yahoo api: %w", err)
+		// Mark the token expired so the token source refreshes it
+		expired := *token
+		expired.Expiry = time.Now().Add(-time.Minute)
+		refreshed, err := s.RefreshIfNeeded(ctx, user, &expired)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = s.do(ctx, refreshed, apiURL)
+		if err != nil {
+			return nil, err
+		}
 	}
 	defer resp.Body.Close()
 
@@ -200,114 +258,140 @@ func (s *YahooService) FetchTeams(ctx context.Context, token *oauth2.Token) ([]Y
 		return nil, fmt.Errorf("failed to decode yahoo response: %w", err)
 	}
 
-	teams := extractTeams(payload)
+	return payload, nil
+}
 
-	return teams, nil
+// do sends an authenticated GET to the Yahoo API
+func (s *YahooService) do(ctx context.Context, token *oauth2.Token, apiURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query yahoo api: %w", err)
+	}
+	return resp, nil
 }
 
-func extractTeams(payload map[string]any) []YahooTeam {
+// extractRoster walks fantasy_content.team[1].roster["0"].players. Each player is a
+// two-element array: a list of single-key attribute objects, then the selected position.
+func extractRoster(payload map[string]any) []YahooPlayer {
 	fantasyContent := toMap(payload["fantasy_content"])
-	users := toMap(fantasyContent["users"])
-	if users == nil {
+	teamSlice := toSlice(fantasyContent["team"])
+
+	var playersMap map[string]any
+	for _, teamPart := range teamSlice {
+		roster := toMap(toMap(teamPart)["roster"])
+		if roster == nil {
+			continue
+		}
+		playersMap = toMap(toMap(roster["0"])["players"])
+	}
+	if playersMap == nil {
 		return nil
 	}
 
-	var teams []YahooTeam
-
-	for key, userEntry := range users {
-		if key == "count" {
+	count := toInt(playersMap["count"])
+	players := make([]YahooPlayer, 0, count)
+	for i := 0; i < count; i++ {
+		playerParts := toSlice(toMap(playersMap[strconv.Itoa(i)])["player"])
+		if len(playerParts) == 0 {
 			continue
 		}
 
-		entryMap := toMap(userEntry)
-		if entryMap == nil {
-			continue
+		attrs := mergeParts(toSlice(playerParts[0]))
+		player := YahooPlayer{
+			PlayerKey:  toString(attrs["player_key"]),
+			Name:       toString(toMap(attrs["name"])["full"]),
+			Team:       toString(attrs["editorial_team_abbr"]),
+			Position:   toString(attrs["display_position"]),
+			Status:     toString(attrs["status"]),
+			StatusFull: toString(attrs["status_full"]),
+			InjuryNote: toString(attrs["injury_note"]),
+		}
+		for _, eligible := range toSlice(attrs["eligible_positions"]) {
+			if pos := toString(toMap(eligible)["position"]); pos != "" {
+				player.EligiblePositions = append(player.EligiblePositions, pos)
+			}
 		}
 
-		userSlice := toSlice(entryMap["user"])
-		for _, userItem := range userSlice {
-			itemMap := toMap(userItem)
-			if itemMap == nil {
-				continue
+		for _, part := range playerParts[1:] {
+			selected := mergeParts(toSlice(toMap(part)["selected_position"]))
+			if pos := toString(selected["position"]); pos != "" {
+				player.SelectedPosition = pos
 			}
+		}
 
-			gamesMap := toMap(itemMap["games"])
-			if gamesMap == nil {
+		if player.PlayerKey != "" {
+			players = append(players, player)
+		}
+	}
+
+	return players
+}
+
+// mergeParts flattens Yahoo's list of single-key objects into one map
+func mergeParts(parts []any) map[string]any {
+	merged := make(map[string]any)
+	for _, part := range parts {
+		for key, value := range toMap(part) {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// extractTeams walks fantasy_content.users[n].user[].games[n].game[].teams[n].team[].
+// Yahoo is inconsistent about whether each level is an object or an array (and nests
+// attribute arrays inside arrays), so every level is read through yahooParts and
+// yahooIndexed rather than asserted to one shape. Missing keys are logged at debug level.
+func extractTeams(payload map[string]any) []YahooTeam {
+	fantasyContent := toMap(payload["fantasy_content"])
+	if fantasyContent == nil {
+		slog.Debug("yahoo teams: missing key", "key", "fantasy_content")
+		return nil
+	}
+	if fantasyContent["users"] == nil {
+		slog.Debug("yahoo teams: missing key", "key", "fantasy_content.users")
+		return nil
+	}
+
+	var teams []YahooTeam
+	for _, userEntry := range yahooIndexed(fantasyContent["users"]) {
+		userParts := yahooParts(toMap(userEntry)["user"])
+		games := mergeParts(userParts)["games"]
+		if games == nil {
+			slog.Debug("yahoo teams: missing key", "key", "user.games")
+			continue
+		}
+
+		for _, gameEntry := range yahooIndexed(games) {
+			game := mergeParts(yahooParts(toMap(gameEntry)["game"]))
+			leagueName := toString(game["name"])
+
+			if game["teams"] == nil {
+				slog.Debug("yahoo teams: missing key", "key", "game.teams", "game_key", toString(game["game_key"]))
 				continue
 			}
 
-			gameCount := toInt(gamesMap["count"])
-			for i := 0; i < gameCount; i++ {
-				gameWrapper := toMap(gamesMap[strconv.Itoa(i)])
-				if gameWrapper == nil {
-					continue
+			for _, teamEntry := range yahooIndexed(game["teams"]) {
+				attrs := mergeParts(yahooParts(toMap(teamEntry)["team"]))
+				team := YahooTeam{
+					TeamKey:    toString(attrs["team_key"]),
+					Name:       toString(attrs["name"]),
+					LeagueName: leagueName,
+					LogoURL:    teamLogoURL(attrs["team_logos"]),
 				}
 
-				gameSlice := toSlice(gameWrapper["game"])
-				var leagueName string
-
-				for _, gameItem := range gameSlice {
-					gm := toMap(gameItem)
-					if gm == nil {
-						continue
-					}
-
-					if name, ok := gm["name"].(string); ok && leagueName == "" {
-						leagueName = name
-					}
-
-					teamsMap := toMap(gm["teams"])
-					if teamsMap == nil {
-						continue
-					}
-
-					teamCount := toInt(teamsMap["count"])
-					for ti := 0; ti < teamCount; ti++ {
-						teamWrapper := toMap(teamsMap[strconv.Itoa(ti)])
-						if teamWrapper == nil {
-							continue
-						}
-
-						teamSlice := toSlice(teamWrapper["team"])
-						team := YahooTeam{LeagueName: leagueName}
-
-						for _, teamPart := range teamSlice {
-							tm := toMap(teamPart)
-							if tm == nil {
-								continue
-							}
-
-							if key, ok := tm["team_key"].(string); ok {
-								team.TeamKey = key
-							}
-
-							if name, ok := tm["name"].(string); ok {
-								team.Name = name
-							}
-
-							if logoMap := toMap(tm["team_logos"]); logoMap != nil {
-								logoCount := toInt(logoMap["count"])
-								if logoCount > 0 {
-									firstLogo := toMap(logoMap["0"])
-									if firstLogo != nil {
-										logoSlice := toSlice(firstLogo["team_logo"])
-										for _, logo := range logoSlice {
-											if logoMap := toMap(logo); logoMap != nil {
-												if url, ok := logoMap["url"].(string); ok {
-													team.LogoURL = url
-												}
-											}
-										}
-									}
-								}
-							}
-						}
-
-						if team.TeamKey != "" && team.Name != "" {
-							teams = append(teams, team)
-						}
-					}
+				if team.TeamKey == "" || team.Name == "" {
+					slog.Debug("yahoo teams: team missing team_key or name", "team_key", team.TeamKey, "name", team.Name)
+					continue
 				}
+				teams = append(teams, team)
 			}
 		}
 	}
@@ -315,6 +399,65 @@ func extractTeams(payload map[string]any) []YahooTeam {
 	return dedupeTeams(teams)
 }
 
+// teamLogoURL returns the first logo URL from team_logos, which Yahoo sends either as
+// an array of {"team_logo": {...}} or as an indexed object, with team_logo itself an
+// object or an array of attribute objects
+func teamLogoURL(v any) string {
+	if v == nil {
+		return ""
+	}
+	for _, entry := range yahooIndexed(v) {
+		logo := mergeParts(yahooParts(toMap(entry)["team_logo"]))
+		if url := toString(logo["url"]); url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+// yahooParts returns the attribute objects of a Yahoo value that may be an object,
+// an array of objects or nested arrays of objects, flattened into one list
+func yahooParts(v any) []any {
+	switch val := v.(type) {
+	case map[string]any:
+		return []any{val}
+	case []any:
+		var parts []any
+		for _, item := range val {
+			parts = append(parts, yahooParts(item)...)
+		}
+		return parts
+	default:
+		return nil
+	}
+}
+
+// yahooIndexed returns the entries of a Yahoo collection, which is either an array or
+// an object keyed "0".."n-1" alongside a "count". When count is missing or wrong the
+// numeric keys present are used.
+func yahooIndexed(v any) []any {
+	if s := toSlice(v); s != nil {
+		return s
+	}
+	m := toMap(v)
+	if m == nil {
+		return nil
+	}
+
+	var entries []any
+	for i := 0; ; i++ {
+		entry, ok := m[strconv.Itoa(i)]
+		if !ok {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	if count := toInt(m["count"]); count != len(entries) {
+		slog.Debug("yahoo: collection count mismatch", "count", count, "entries", len(entries))
+	}
+	return entries
+}
+
 func toMap(v any) map[string]any {
 	if v == nil {
 		return nil
@@ -335,6 +478,17 @@ func toSlice(v any) []any {
 	return nil
 }
 
+func toString(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
 func toInt(v any) int {
 	switch val := v.(type) {
 	case float64:
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/oauth2"
+)
+
+func loadYahooPayload(t *testing.T, name string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "yahoo", name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+	return payload
+}
+
+func TestExtractTeams(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    []YahooTeam
+	}{
+		{
+			name:    "single team",
+			payload: "single_team.json",
+			want: []YahooTeam{
+				{TeamKey: "449.l.12345.t.3", Name: "Touchdown Tobias", LeagueName: "Football", LogoURL: "https://s.yimg.com/logo3.png"},
+			},
+		},
+		{
+			// Arrays where the single-team payload has indexed objects, a game sent as a
+			// plain object and a team key repeated across games in a different case
+			name:    "multiple leagues",
+			payload: "multiple_leagues.json",
+			want: []YahooTeam{
+				{TeamKey: "449.l.12345.t.3", Name: "Touchdown Tobias", LeagueName: "Football", LogoURL: "https://s.yimg.com/logo3.png"},
+				{TeamKey: "449.l.67890.t.8", Name: "Work League Squad", LeagueName: "Football"},
+				{TeamKey: "423.l.555.t.1", Name: "Plus Team", LeagueName: "Football PLUS"},
+			},
+		},
+		{
+			// Also a team without a name, a game without teams and a count that overstates
+			// the entries
+			name:    "league with no logo",
+			payload: "no_logo.json",
+			want: []YahooTeam{
+				{TeamKey: "449.l.999.t.2", Name: "Logo-less Legends", LeagueName: "Football"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractTeams(loadYahooPayload(t, tt.payload))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractTeams() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractTeamsMissingKeys(t *testing.T) {
+	payloads := map[string]map[string]any{
+		"empty":               {},
+		"no users":            {"fantasy_content": map[string]any{}},
+		"users is a string":   {"fantasy_content": map[string]any{"users": "0"}},
+		"user without games":  {"fantasy_content": map[string]any{"users": []any{map[string]any{"user": []any{map[string]any{"guid": "X"}}}}}},
+		"fantasy_content nil": {"fantasy_content": nil},
+	}
+
+	for name, payload := range payloads {
+		t.Run(name, func(t *testing.T) {
+			if got := extractTeams(payload); len(got) != 0 {
+				t.Errorf("extractTeams() = %+v, want no teams", got)
+			}
+		})
+	}
+}
+
+func TestExtractRoster(t *testing.T) {
+	got := extractRoster(loadYahooPayload(t, "roster.json"))
+
+	// The player without a player_key is dropped, and one without a selected position
+	// keeps an empty slot
+	want := []YahooPlayer{
+		{
+			PlayerKey:         "449.p.33393",
+			Name:              "Justin Jefferson",
+			Team:              "MIN",
+			Position:          "WR",
+			EligiblePositions: []string{"WR"},
+			SelectedPosition:  "WR",
+		},
+		{
+			PlayerKey:         "449.p.32692",
+			Name:              "Kyle Pitts",
+			Team:              "ATL",
+			Position:          "TE",
+			EligiblePositions: []string{"TE", "W/R/T"},
+			SelectedPosition:  "BN",
+			Status:            "Q",
+			StatusFull:        "Questionable",
+			InjuryNote:        "Hamstring",
+		},
+		{
+			PlayerKey:         "449.p.100005",
+			Name:              "Kansas City",
+			Team:              "KC",
+			Position:          "DEF",
+			EligiblePositions: []string{"DEF"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractRoster() = %+v, want %+v", got, want)
+	}
+
+	for name, payload := range map[string]map[string]any{
+		"empty payload":         {},
+		"team without a roster": {"fantasy_content": map[string]any{"team": []any{[]any{map[string]any{"team_key": "449.l.1.t.1"}}}}},
+	} {
+		if got := extractRoster(payload); len(got) != 0 {
+			t.Errorf("extractRoster(%s) = %+v, want none", name, got)
+		}
+	}
+}
+
+func TestFetchRosterRefreshesOn401(t *testing.T) {
+	roster, err := os.ReadFile(filepath.Join("testdata", "yahoo", "roster.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rosterCalls, refreshes int
+	var auths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			refreshes++
+			if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh-1" {
+				t.Errorf("token request form = %v, want a refresh_token grant for refresh-1", r.Form)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"access-2","token_type":"bearer","expires_in":3600,"refresh_token":"refresh-2"}`))
+		case "/team/449.l.12345.t.3/roster":
+			rosterCalls++
+			auths = append(auths, r.Header.Get("Authorization"))
+			if r.Header.Get("Authorization") != "Bearer access-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write(roster)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var updates []bson.M
+	s := &YahooService{
+		oauthConfig: &oauth2.Config{ClientID: "id", ClientSecret: "secret", Endpoint: oauth2.Endpoint{TokenURL: srv.URL + "/token", AuthStyle: oauth2.AuthStyleInParams}},
+		httpClient:  srv.Client(),
+		apiBase:     srv.URL,
+		updateUser: func(ctx context.Context, userID bson.ObjectID, update bson.M) error {
+			updates = append(updates, update)
+			return nil
+		},
+	}
+
+	// The recorded expiry is still in the future, so only the 401 triggers the refresh
+	user := &models.User{ID: bson.NewObjectID(), YahooAccessToken: "access-1", YahooRefreshToken: "refresh-1", YahooTokenExpiry: time.Now().Add(time.Hour)}
+	token, err := s.TokenFromUser(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	players, err := s.FetchRoster(context.Background(), user, token, "449.l.12345.t.3")
+	if err != nil {
+		t.Fatalf("FetchRoster() error = %v", err)
+	}
+	if len(players) != 3 {
+		t.Errorf("FetchRoster() returned %d players, want 3", len(players))
+	}
+	if refreshes != 1 || rosterCalls != 2 || !reflect.DeepEqual(auths, []string{"Bearer access-1", "Bearer access-2"}) {
+		t.Errorf("refreshes = %d, roster calls = %d with %v, want one refresh and a retry with the new token", refreshes, rosterCalls, auths)
+	}
+
+	if len(updates) != 1 {
+		t.Fatalf("persisted %d token updates, want 1", len(updates))
+	}
+	set := updates[0]["$set"].(bson.M)
+	if set["yahoo_access_token"] != "access-2" || set["yahoo_refresh_token"] != "refresh-2" {
+		t.Errorf("persisted tokens = %v, want access-2/refresh-2", set)
+	}
+	if user.YahooAccessToken != "access-2" || user.YahooRefreshToken != "refresh-2" {
+		t.Errorf("user tokens = %s/%s, want access-2/refresh-2", user.YahooAccessToken, user.YahooRefreshToken)
+	}
+}
+
+func TestFetchRosterGivesUpAfterRetry(t *testing.T) {
+	var rosterCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"access-2","token_type":"bearer","expires_in":3600}`))
+			return
+		}
+		rosterCalls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	s := &YahooService{
+		oauthConfig: &oauth2.Config{Endpoint: oauth2.Endpoint{TokenURL: srv.URL + "/token", AuthStyle: oauth2.AuthStyleInParams}},
+		httpClient:  srv.Client(),
+		apiBase:     srv.URL,
+		updateUser:  func(ctx context.Context, userID bson.ObjectID, update bson.M) error { return nil },
+	}
+	user := &models.User{YahooAccessToken: "access-1", YahooRefreshToken: "refresh-1", YahooTokenExpiry: time.Now().Add(time.Hour)}
+	token, _ := s.TokenFromUser(user)
+
+	if _, err := s.FetchRoster(context.Background(), user, token, "449.l.12345.t.3"); err == nil {
+		t.Error("FetchRoster() error = nil, want the second 401 reported")
+	}
+	if rosterCalls != 2 {
+		t.Errorf("roster calls = %d, want 2 (no retry loop)", rosterCalls)
+	}
+}
@@ -0,0 +1,44 @@
+// Package teams maps the team abbreviations used by different data sources onto the
+// nflverse abbreviations stored in the database.
+package teams
+
+import "strings"
+
+// aliases maps every known alternate abbreviation to its canonical nflverse one. It covers
+// relocations (a franchise's old abbreviation maps to its current one), ESPN's abbreviations
+// and the three-letter forms some stat sites use.
+var aliases = map[string]string{
+	// Relocations
+	"OAK": "LV",  // Raiders, Oakland until 2019
+	"SD":  "LAC", // Chargers, San Diego until 2016
+	"STL": "LA",  // Rams, St. Louis until 2015
+
+	// ESPN and other feeds
+	"WSH": "WAS",
+	"LAR": "LA",
+	"LVR": "LV",
+	"JAC": "JAX",
+	"SDG": "LAC",
+	"SL":  "LA",
+	"ARZ": "ARI",
+	"BLT": "BAL",
+	"CLV": "CLE",
+	"HST": "HOU",
+	"GNB": "GB",
+	"KAN": "KC",
+	"NWE": "NE",
+	"NOR": "NO",
+	"SFO": "SF",
+	"TAM": "TB",
+}
+
+// Normalize returns the canonical abbreviation for abbr, ignoring case and surrounding
+// whitespace. Abbreviations that aren't aliases, including canonical ones and free agents
+// ("FA"), are returned upper-cased.
+func Normalize(abbr string) string {
+	abbr = strings.ToUpper(strings.TrimSpace(abbr))
+	if canonical, ok := aliases[abbr]; ok {
+		return canonical
+	}
+	return abbr
+}
@@ -0,0 +1,62 @@
+package teams
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		// Canonical abbreviations are unchanged
+		"KC":  "KC",
+		"LA":  "LA",
+		"LAC": "LAC",
+		"WAS": "WAS",
+
+		// Relocations
+		"OAK": "LV",
+		"SD":  "LAC",
+		"STL": "LA",
+
+		// ESPN and stat site codes
+		"WSH": "WAS",
+		"LAR": "LA",
+		"LVR": "LV",
+		"JAC": "JAX",
+		"GNB": "GB",
+		"KAN": "KC",
+		"NWE": "NE",
+		"NOR": "NO",
+		"SFO": "SF",
+		"TAM": "TB",
+		"ARZ": "ARI",
+		"BLT": "BAL",
+		"CLV": "CLE",
+		"HST": "HOU",
+
+		// Case and whitespace are ignored
+		"wsh":     "WAS",
+		" kc ":    "KC",
+		"\tLar\n": "LA",
+
+		// Free agents and unknown codes are only upper-cased
+		"FA":  "FA",
+		"fa":  "FA",
+		"XYZ": "XYZ",
+		"":    "",
+	}
+
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAliasesAreCanonical(t *testing.T) {
+	for alias, canonical := range aliases {
+		if _, ok := aliases[canonical]; ok {
+			t.Errorf("alias %s maps to %s, which is itself an alias", alias, canonical)
+		}
+		if Normalize(canonical) != canonical {
+			t.Errorf("Normalize(%q) = %q, want it unchanged", canonical, Normalize(canonical))
+		}
+	}
+}
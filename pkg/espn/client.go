@@ -6,12 +6,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/cookiejar"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/ai-atl/nfl-platform/internal/models"
+	"github.com/ai-atl/nfl-platform/internal/teams"
 )
 
 // Helper function
@@ -23,12 +26,13 @@ func min(a, b int) int {
 }
 
 const (
-	baseURL = "https://fantasy.espn.com/apis/v3/games/ffl"
+	defaultBaseURL = "https://fantasy.espn.com/apis/v3/games/ffl"
 )
 
 // Client handles ESPN Fantasy Football API requests
 type Client struct {
 	httpClient *http.Client
+	baseURL    string
 	leagueID   string
 	seasonYear int
 	swid       string
@@ -44,6 +48,7 @@ func NewClient(leagueID string, seasonYear int, swid, espnS2 string) *Client {
 			Jar:     jar,
 			Timeout: 30 * time.Second,
 		},
+		baseURL:    defaultBaseURL,
 		leagueID:   leagueID,
 		seasonYear: seasonYear,
 		swid:       swid,
@@ -54,7 +59,7 @@ func NewClient(leagueID string, seasonYear int, swid, espnS2 string) *Client {
 // GetLeague fetches complete league information including settings and all teams
 func (c *Client) GetLeague(ctx context.Context) (*models.ESPNLeague, error) {
 	endpoint := fmt.Sprintf("%s/seasons/%d/segments/0/leagues/%s?view=mTeam&view=mRoster&view=mSettings&view=mStandings",
-		baseURL, c.seasonYear, c.leagueID)
+		c.baseURL, c.seasonYear, c.leagueID)
 
 	data, err := c.doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
@@ -95,6 +100,9 @@ func (c *Client) GetLeague(ctx context.Context) (*models.ESPNLeague, error) {
 			ScoringSettings struct {
 				ScoringType string `json:"scoringType"`
 			} `json:"scoringSettings"`
+			RosterSettings struct {
+				LineupSlotCounts map[int]int `json:"lineupSlotCounts"` // ESPN slot ID -> count
+			} `json:"rosterSettings"`
 		} `json:"settings"`
 		Teams []struct {
 			ID         int    `json:"id"`
@@ -119,7 +127,7 @@ func (c *Client) GetLeague(ctx context.Context) (*models.ESPNLeague, error) {
 							ID        int    `json:"id"`
 							FullName  string `json:"fullName"`
 							ProTeam   int    `json:"proTeamId"`
-							Position  string `json:"defaultPositionId"`
+							Position  int    `json:"defaultPositionId"`
 							InjStatus string `json:"injuryStatus"`
 							Ownership struct {
 								PercentOwned   float64 `json:"percentOwned"`
@@ -164,6 +172,8 @@ func (c *Client) GetLeague(ctx context.Context) (*models.ESPNLeague, error) {
 			VetoVotesRequired:  response.Settings.TradeSettings.VetoVotesRequired,
 			WaiverProcessHour:  response.Settings.AcquisitionSettings.WaiverProcessHour,
 			RegSeasonCount:     response.Settings.SchedulePeriods,
+			LineupSlotCounts:   response.Settings.RosterSettings.LineupSlotCounts,
+			Lineup:             LeagueSettingsFromSlotCounts(response.Settings.RosterSettings.LineupSlotCounts),
 		},
 		Teams: []models.ESPNTeam{},
 	}
@@ -217,7 +227,7 @@ func (c *Client) GetLeague(ctx context.Context) (*models.ESPNLeague, error) {
 func (c *Client) GetTeam(ctx context.Context, teamID int) (*models.ESPNTeam, error) {
 	// First, try a simple league settings request to verify auth works
 	settingsEndpoint := fmt.Sprintf("%s/seasons/%d/segments/0/leagues/%s",
-		baseURL, c.seasonYear, c.leagueID)
+		c.baseURL, c.seasonYear, c.leagueID)
 
 	fmt.Printf("[ESPN Client] Testing auth with settings endpoint: %s\n", settingsEndpoint)
 	testData, err := c.doRequest(ctx, "GET", settingsEndpoint, nil)
@@ -234,7 +244,7 @@ func (c *Client) GetTeam(ctx context.Context, teamID int) (*models.ESPNTeam, err
 
 	// Now get the full team data
 	endpoint := fmt.Sprintf("%s/seasons/%d/segments/0/leagues/%s?view=mTeam&view=mRoster",
-		baseURL, c.seasonYear, c.leagueID)
+		c.baseURL, c.seasonYear, c.leagueID)
 
 	data, err := c.doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
@@ -269,7 +279,7 @@ func (c *Client) GetTeam(ctx context.Context, teamID int) (*models.ESPNTeam, err
 							ID        int    `json:"id"`
 							FullName  string `json:"fullName"`
 							ProTeam   int    `json:"proTeamId"`
-							Position  string `json:"defaultPositionId"`
+							Position  int    `json:"defaultPositionId"`
 							InjStatus string `json:"injuryStatus"`
 						} `json:"player"`
 					} `json:"playerPoolEntry"`
@@ -328,10 +338,73 @@ func (c *Client) GetRoster(ctx context.Context, teamID int) ([]models.ESPNPlayer
 	return team.Roster, nil
 }
 
-// GetMatchup fetches matchup information for a specific week
+// matchupSide is one team's half of a matchup in the mMatchupScore/mScoreboard views
+type matchupSide struct {
+	TeamID                        int     `json:"teamId"`
+	TotalPoints                   float64 `json:"totalPoints"`
+	TotalProjectedPoints          float64 `json:"totalProjectedPoints"`
+	RosterForCurrentScoringPeriod struct {
+		Entries []struct {
+			LineupSlotID    int `json:"lineupSlotId"`
+			PlayerPoolEntry struct {
+				Player struct {
+					Stats []struct {
+						ScoringPeriodID int `json:"scoringPeriodId"`
+						StatSourceID    int `json:"statSourceId"` // 0 = actual, 1 = projected
+					} `json:"stats"`
+				} `json:"player"`
+			} `json:"playerPoolEntry"`
+		} `json:"entries"`
+	} `json:"rosterForCurrentScoringPeriod"`
+}
+
+// yetToPlay counts starters with no actual stats for the week (bench and IR excluded)
+func (m matchupSide) yetToPlay(week int) int {
+	count := 0
+	for _, entry := range m.RosterForCurrentScoringPeriod.Entries {
+		if entry.LineupSlotID == 20 || entry.LineupSlotID == 21 {
+			continue
+		}
+		played := false
+		for _, stat := range entry.PlayerPoolEntry.Player.Stats {
+			if stat.ScoringPeriodID == week && stat.StatSourceID == 0 {
+				played = true
+				break
+			}
+		}
+		if !played {
+			count++
+		}
+	}
+	return count
+}
+
+// playerScoreStdDev is the typical spread of one starter's score around its projection
+const playerScoreStdDev = 7.0
+
+// winProbability estimates the home team's chance of winning from the projected final
+// scores. The uncertainty shrinks as fewer starters are left to play; once everyone has
+// played the current score decides it.
+func winProbability(homeProjected, awayProjected, homeScore, awayScore float64, remaining int) float64 {
+	if remaining == 0 {
+		switch {
+		case homeScore > awayScore:
+			return 1
+		case awayScore > homeScore:
+			return 0
+		default:
+			return 0.5
+		}
+	}
+	sigma := playerScoreStdDev * math.Sqrt(float64(remaining))
+	return 0.5 * (1 + math.Erf((homeProjected-awayProjected)/(sigma*math.Sqrt2)))
+}
+
+// GetMatchup fetches matchup information for a specific week, including ESPN's projected
+// final scores and the home team's win probability
 func (c *Client) GetMatchup(ctx context.Context, teamID int, week int) (*models.ESPNMatchup, error) {
-	endpoint := fmt.Sprintf("%s/seasons/%d/segments/0/leagues/%s?view=mMatchup&scoringPeriodId=%d",
-		baseURL, c.seasonYear, c.leagueID, week)
+	endpoint := fmt.Sprintf("%s/seasons/%d/segments/0/leagues/%s?view=mMatchupScore&view=mScoreboard&scoringPeriodId=%d",
+		c.baseURL, c.seasonYear, c.leagueID, week)
 
 	data, err := c.doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
@@ -340,15 +413,9 @@ func (c *Client) GetMatchup(ctx context.Context, teamID int, week int) (*models.
 
 	var response struct {
 		Schedule []struct {
-			MatchupPeriodId int `json:"matchupPeriodId"`
-			Home            struct {
-				TeamID      int     `json:"teamId"`
-				TotalPoints float64 `json:"totalPoints"`
-			} `json:"home"`
-			Away struct {
-				TeamID      int     `json:"teamId"`
-				TotalPoints float64 `json:"totalPoints"`
-			} `json:"away"`
+			MatchupPeriodId int         `json:"matchupPeriodId"`
+			Home            matchupSide `json:"home"`
+			Away            matchupSide `json:"away"`
 		} `json:"schedule"`
 	}
 
@@ -360,11 +427,13 @@ func (c *Client) GetMatchup(ctx context.Context, teamID int, week int) (*models.
 	for _, m := range response.Schedule {
 		if m.MatchupPeriodId == week && (m.Home.TeamID == teamID || m.Away.TeamID == teamID) {
 			matchup := &models.ESPNMatchup{
-				Week:       week,
-				HomeTeamID: m.Home.TeamID,
-				AwayTeamID: m.Away.TeamID,
-				HomeScore:  m.Home.TotalPoints,
-				AwayScore:  m.Away.TotalPoints,
+				Week:          week,
+				HomeTeamID:    m.Home.TeamID,
+				AwayTeamID:    m.Away.TeamID,
+				HomeScore:     m.Home.TotalPoints,
+				AwayScore:     m.Away.TotalPoints,
+				HomeProjected: m.Home.TotalProjectedPoints,
+				AwayProjected: m.Away.TotalProjectedPoints,
 			}
 
 			if m.Home.TotalPoints > m.Away.TotalPoints {
@@ -375,6 +444,11 @@ func (c *Client) GetMatchup(ctx context.Context, teamID int, week int) (*models.
 				matchup.Winner = "tie"
 			}
 
+			remaining := m.Home.yetToPlay(week) + m.Away.yetToPlay(week)
+			matchup.HomeWinProbability = winProbability(matchup.HomeProjected, matchup.AwayProjected,
+				matchup.HomeScore, matchup.AwayScore, remaining)
+			matchup.AwayWinProbability = 1 - matchup.HomeWinProbability
+
 			return matchup, nil
 		}
 	}
@@ -385,7 +459,7 @@ func (c *Client) GetMatchup(ctx context.Context, teamID int, week int) (*models.
 // GetFreeAgents fetches available free agents
 func (c *Client) GetFreeAgents(ctx context.Context, position string, limit int) ([]models.ESPNFreeAgent, error) {
 	endpoint := fmt.Sprintf("%s/seasons/%d/segments/0/leagues/%s?view=kona_player_info",
-		baseURL, c.seasonYear, c.leagueID)
+		c.baseURL, c.seasonYear, c.leagueID)
 
 	data, err := c.doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
@@ -395,12 +469,14 @@ func (c *Client) GetFreeAgents(ctx context.Context, position string, limit int)
 	var response struct {
 		Players []struct {
 			Player struct {
-				ID        int     `json:"id"`
-				FullName  string  `json:"fullName"`
-				ProTeam   int     `json:"proTeamId"`
-				Position  string  `json:"defaultPositionId"`
-				Ownership float64 `json:"ownership"`
-				InjStatus string  `json:"injuryStatus"`
+				ID        int    `json:"id"`
+				FullName  string `json:"fullName"`
+				ProTeam   int    `json:"proTeamId"`
+				Position  int    `json:"defaultPositionId"`
+				InjStatus string `json:"injuryStatus"`
+				Ownership struct {
+					PercentOwned float64 `json:"percentOwned"`
+				} `json:"ownership"`
 			} `json:"player"`
 		} `json:"players"`
 	}
@@ -422,7 +498,7 @@ func (c *Client) GetFreeAgents(ctx context.Context, position string, limit int)
 				Name:         p.Player.FullName,
 				Position:     playerPos,
 				Team:         c.mapTeam(p.Player.ProTeam),
-				PercentOwned: p.Player.Ownership,
+				PercentOwned: p.Player.Ownership.PercentOwned,
 				InjuryStatus: p.Player.InjStatus,
 			}
 			freeAgents = append(freeAgents, agent)
@@ -435,7 +511,7 @@ func (c *Client) GetFreeAgents(ctx context.Context, position string, limit int)
 // GetStandings fetches league standings
 func (c *Client) GetStandings(ctx context.Context) ([]models.ESPNTeam, error) {
 	endpoint := fmt.Sprintf("%s/seasons/%d/segments/0/leagues/%s?view=mTeam",
-		baseURL, c.seasonYear, c.leagueID)
+		c.baseURL, c.seasonYear, c.leagueID)
 
 	data, err := c.doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
@@ -485,7 +561,7 @@ func (c *Client) GetStandings(ctx context.Context) ([]models.ESPNTeam, error) {
 // GetBoxScore fetches detailed box score for a specific week's matchup
 func (c *Client) GetBoxScore(ctx context.Context, week int) ([]models.ESPNBoxScore, error) {
 	endpoint := fmt.Sprintf("%s/seasons/%d/segments/0/leagues/%s?view=mMatchupScore&view=mScoreboard&scoringPeriodId=%d",
-		baseURL, c.seasonYear, c.leagueID, week)
+		c.baseURL, c.seasonYear, c.leagueID, week)
 
 	data, err := c.doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
@@ -506,7 +582,7 @@ func (c *Client) GetBoxScore(ctx context.Context, week int) ([]models.ESPNBoxSco
 								ID       int    `json:"id"`
 								FullName string `json:"fullName"`
 								ProTeam  int    `json:"proTeamId"`
-								Position string `json:"defaultPositionId"`
+								Position int    `json:"defaultPositionId"`
 								Stats    []struct {
 									ScoringPeriodID int                `json:"scoringPeriodId"`
 									AppliedTotal    float64            `json:"appliedTotal"`
@@ -529,7 +605,7 @@ func (c *Client) GetBoxScore(ctx context.Context, week int) ([]models.ESPNBoxSco
 								ID       int    `json:"id"`
 								FullName string `json:"fullName"`
 								ProTeam  int    `json:"proTeamId"`
-								Position string `json:"defaultPositionId"`
+								Position int    `json:"defaultPositionId"`
 								Stats    []struct {
 									ScoringPeriodID int                `json:"scoringPeriodId"`
 									AppliedTotal    float64            `json:"appliedTotal"`
@@ -602,7 +678,7 @@ func (c *Client) GetBoxScore(ctx context.Context, week int) ([]models.ESPNBoxSco
 // GetRecentActivity fetches recent league transactions and activity
 func (c *Client) GetRecentActivity(ctx context.Context, size int) ([]models.ESPNActivity, error) {
 	endpoint := fmt.Sprintf("%s/seasons/%d/segments/0/leagues/%s?view=kona_league_communication",
-		baseURL, c.seasonYear, c.leagueID)
+		c.baseURL, c.seasonYear, c.leagueID)
 
 	data, err := c.doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
@@ -668,7 +744,7 @@ func (c *Client) parseBoxPlayer(entry interface{}, week int) models.ESPNPlayerBo
 				ID       int    `json:"id"`
 				FullName string `json:"fullName"`
 				ProTeam  int    `json:"proTeamId"`
-				Position string `json:"defaultPositionId"`
+				Position int    `json:"defaultPositionId"`
 				Stats    []struct {
 					ScoringPeriodID int                `json:"scoringPeriodId"`
 					AppliedTotal    float64            `json:"appliedTotal"`
@@ -734,12 +810,8 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, body in
 	req.Header.Set("Origin", "https://fantasy.espn.com")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
 
-	// Debug logging
+	// Debug logging; never log the cookie values, they grant full access to the account
 	fmt.Printf("[ESPN Client] Request to: %s\n", endpoint)
-	fmt.Printf("[ESPN Client] SWID length: %d, S2 length: %d\n", len(c.swid), len(c.espnS2))
-	fmt.Printf("[ESPN Client] SWID value: %s\n", c.swid)
-	fmt.Printf("[ESPN Client] S2 preview: %s...\n", c.espnS2[:min(50, len(c.espnS2))])
-	fmt.Printf("[ESPN Client] Cookie header: %s\n", req.Header.Get("Cookie"))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -752,53 +824,50 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, body in
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("ESPN authentication failed - cookies may be expired")
+	if err := classifyError(resp.StatusCode, data); err != nil {
+		if len(data) > 0 && data[0] == '<' {
+			writeHTMLDebug(data)
+		}
+		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		// Log first 500 chars of response for debugging
-		preview := string(data)
-		if len(preview) > 500 {
-			preview = preview[:500] + "..."
-		}
-		return nil, fmt.Errorf("ESPN API returned status %d. Response: %s", resp.StatusCode, preview)
-	}
-
-	// Check if response looks like HTML instead of JSON
-	if len(data) > 0 && data[0] == '<' {
-		// Write full HTML response to a debug file
-		debugFile := "/tmp/espn_error_response.html"
-		if err := os.WriteFile(debugFile, data, 0644); err == nil {
-			fmt.Printf("\n[ESPN Client] ==================== HTML ERROR ====================\n")
-			fmt.Printf("Received HTML instead of JSON (likely auth/access issue)\n")
-			fmt.Printf("Full HTML response saved to: %s\n", debugFile)
-			fmt.Printf("First 500 chars: %s\n", string(data[:min(500, len(data))]))
-			fmt.Printf("==========================================================\n\n")
-		}
-		return nil, fmt.Errorf("ESPN returned HTML instead of JSON (likely auth issue)")
+		return nil, statusError(resp.StatusCode, data)
 	}
 
 	return data, nil
 }
 
+// writeHTMLDebug saves an HTML response ESPN sent in place of JSON for inspection
+func writeHTMLDebug(data []byte) {
+	debugFile := "/tmp/espn_error_response.html"
+	if err := os.WriteFile(debugFile, data, 0644); err == nil {
+		fmt.Printf("\n[ESPN Client] ==================== HTML ERROR ====================\n")
+		fmt.Printf("Received HTML instead of JSON (likely auth/access issue)\n")
+		fmt.Printf("Full HTML response saved to: %s\n", debugFile)
+		fmt.Printf("First 500 chars: %s\n", string(data[:min(500, len(data))]))
+		fmt.Printf("==========================================================\n\n")
+	}
+}
+
 // Helper functions to map ESPN IDs to readable values
 
-func (c *Client) mapPosition(posID string) string {
-	positions := map[string]string{
-		"1": "QB", "2": "RB", "3": "WR", "4": "TE",
-		"5": "K", "16": "D/ST",
+// mapPosition maps ESPN's numeric defaultPositionId to a position
+func (c *Client) mapPosition(posID int) string {
+	positions := map[int]string{
+		1: "QB", 2: "RB", 3: "WR", 4: "TE",
+		5: "K", 16: "D/ST",
 	}
 	if pos, ok := positions[posID]; ok {
 		return pos
 	}
-	return posID
+	return strconv.Itoa(posID)
 }
 
 func (c *Client) mapSlotPosition(slotID int) string {
 	slots := map[int]string{
-		0: "QB", 2: "RB", 4: "WR", 6: "TE",
-		16: "D/ST", 17: "K", 20: "BENCH", 23: "FLEX",
+		0: "QB", 2: "RB", 4: "WR", 6: "TE", 7: "OP",
+		16: "D/ST", 17: "K", 20: "BENCH", 21: "IR", 23: "FLEX",
 	}
 	if slot, ok := slots[slotID]; ok {
 		return slot
@@ -807,7 +876,7 @@ func (c *Client) mapSlotPosition(slotID int) string {
 }
 
 func (c *Client) mapTeam(teamID int) string {
-	teams := map[int]string{
+	proTeams := map[int]string{
 		1: "ATL", 2: "BUF", 3: "CHI", 4: "CIN", 5: "CLE", 6: "DAL",
 		7: "DEN", 8: "DET", 9: "GB", 10: "TEN", 11: "IND", 12: "KC",
 		13: "LV", 14: "LAR", 15: "MIA", 16: "MIN", 17: "NE", 18: "NO",
@@ -815,8 +884,9 @@ func (c *Client) mapTeam(teamID int) string {
 		25: "SF", 26: "SEA", 27: "TB", 28: "WSH", 29: "CAR", 30: "JAX",
 		33: "BAL", 34: "HOU",
 	}
-	if team, ok := teams[teamID]; ok {
-		return team
+	if team, ok := proTeams[teamID]; ok {
+		// ESPN abbreviations (WSH, LAR) differ from the nflverse ones stored in the database
+		return teams.Normalize(team)
 	}
 	return "FA"
 }
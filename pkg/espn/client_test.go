@@ -0,0 +1,185 @@
+package espn
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+)
+
+// newTestClient returns a client for league 123456 whose requests are answered with the
+// captured payload in testdata/name
+func newTestClient(t *testing.T, name string) *Client {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("espn_s2"); err != nil || cookie.Value != "test-s2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(payload)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient("123456", 2025, "{TEST-SWID}", "test-s2")
+	c.baseURL = srv.URL
+	return c
+}
+
+func TestGetMatchup(t *testing.T) {
+	c := newTestClient(t, "matchup.json")
+
+	m, err := c.GetMatchup(context.Background(), 2, 10)
+	if err != nil {
+		t.Fatalf("GetMatchup() error = %v", err)
+	}
+
+	if m.HomeTeamID != 1 || m.AwayTeamID != 2 {
+		t.Errorf("teams = %d vs %d, want 1 vs 2", m.HomeTeamID, m.AwayTeamID)
+	}
+	if m.HomeScore != 62.4 || m.AwayScore != 80.1 {
+		t.Errorf("scores = %v-%v, want 62.4-80.1", m.HomeScore, m.AwayScore)
+	}
+	if m.HomeProjected != 118.6 || m.AwayProjected != 104.2 {
+		t.Errorf("projected = %v-%v, want 118.6-104.2", m.HomeProjected, m.AwayProjected)
+	}
+	if m.Winner != "away" {
+		t.Errorf("Winner = %q, want away", m.Winner)
+	}
+
+	// One home and one away starter are yet to play; bench and IR don't count
+	want := winProbability(118.6, 104.2, 62.4, 80.1, 2)
+	if math.Abs(m.HomeWinProbability-want) > 1e-9 || math.Abs(m.HomeWinProbability-0.9271) > 1e-4 {
+		t.Errorf("HomeWinProbability = %v, want %v", m.HomeWinProbability, want)
+	}
+	if math.Abs(m.HomeWinProbability+m.AwayWinProbability-1) > 1e-9 {
+		t.Errorf("win probabilities %v + %v don't sum to 1", m.HomeWinProbability, m.AwayWinProbability)
+	}
+}
+
+func TestGetMatchupNotFound(t *testing.T) {
+	c := newTestClient(t, "matchup.json")
+	if _, err := c.GetMatchup(context.Background(), 7, 10); err == nil {
+		t.Error("GetMatchup() for a team without a matchup returned no error")
+	}
+}
+
+func TestWinProbability(t *testing.T) {
+	tests := []struct {
+		name                 string
+		homeProj, awayProj   float64
+		homeScore, awayScore float64
+		remaining            int
+		want                 float64
+	}{
+		{"even projections", 100, 100, 40, 60, 4, 0.5},
+		{"away favored with one left", 97, 100, 90, 80, 1, 0.334118},
+		{"finished home win", 90, 120, 101, 100, 0, 1},
+		{"finished away win", 120, 90, 99, 100, 0, 0},
+		{"finished tie", 100, 100, 100, 100, 0, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := winProbability(tt.homeProj, tt.awayProj, tt.homeScore, tt.awayScore, tt.remaining)
+			if math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("winProbability() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// More starters left to play means more uncertainty, pulling the favorite toward 50%
+	if few, many := winProbability(110, 100, 0, 0, 2), winProbability(110, 100, 0, 0, 9); few <= many {
+		t.Errorf("winProbability with 2 left = %v, want more than with 9 left (%v)", few, many)
+	}
+}
+
+func TestGetLeagueSettings(t *testing.T) {
+	c := newTestClient(t, "league_settings.json")
+
+	league, err := c.GetLeague(context.Background())
+	if err != nil {
+		t.Fatalf("GetLeague() error = %v", err)
+	}
+
+	settings := league.Settings
+	if settings.Name != "Sunday Superflex" || settings.Size != 10 || settings.CurrentWeek != 10 {
+		t.Errorf("settings = %q size %d week %d, want Sunday Superflex size 10 week 10",
+			settings.Name, settings.Size, settings.CurrentWeek)
+	}
+	if settings.WaiverProcessHour == nil || *settings.WaiverProcessHour != 3 {
+		t.Errorf("WaiverProcessHour = %v, want 3", settings.WaiverProcessHour)
+	}
+
+	wantCounts := map[int]int{0: 1, 2: 2, 4: 2, 6: 1, 7: 1, 16: 1, 17: 1, 20: 7, 21: 1, 23: 1}
+	for slotID, want := range wantCounts {
+		if got := settings.LineupSlotCounts[slotID]; got != want {
+			t.Errorf("LineupSlotCounts[%d] = %d, want %d", slotID, got, want)
+		}
+	}
+
+	wantLineup := map[string]int{
+		models.SlotQB: 1, models.SlotRB: 2, models.SlotWR: 2, models.SlotTE: 1,
+		models.SlotFlex: 1, models.SlotSuperflex: 1, models.SlotDST: 1, models.SlotK: 1,
+	}
+	if !reflect.DeepEqual(settings.Lineup.LineupSlots, wantLineup) {
+		t.Errorf("Lineup = %v, want %v", settings.Lineup.LineupSlots, wantLineup)
+	}
+	if !settings.Lineup.IsSuperflex() {
+		t.Error("Lineup.IsSuperflex() = false for a league with an OP slot")
+	}
+
+	if len(league.Teams) != 1 || len(league.Teams[0].Roster) != 4 {
+		t.Fatalf("teams = %+v, want one team with 4 players", league.Teams)
+	}
+	team := league.Teams[0]
+	if team.Owner != "tobias" || team.TeamName != "Touchdown Tobias" {
+		t.Errorf("team = %q owned by %q, want Touchdown Tobias owned by tobias", team.TeamName, team.Owner)
+	}
+	wantRoster := []struct{ name, position, team, slot string }{
+		{"Josh Allen", "QB", "BUF", "QB"},
+		{"Jayden Daniels", "QB", "WAS", "OP"},
+		{"Rams D/ST", "D/ST", "LA", "D/ST"},
+		{"Justin Jefferson", "WR", "MIN", "BENCH"},
+	}
+	for i, want := range wantRoster {
+		p := team.Roster[i]
+		if p.Name != want.name || p.Position != want.position || p.Team != want.team || p.SlotPosition != want.slot {
+			t.Errorf("Roster[%d] = %s %s %s %s, want %s %s %s %s", i, p.Name, p.Position, p.Team, p.SlotPosition,
+				want.name, want.position, want.team, want.slot)
+		}
+	}
+}
+
+func TestLeagueSettingsFromSlotCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[int]int
+		want   map[string]int
+	}{
+		{"no starting slots falls back to standard", map[int]int{20: 7, 21: 1}, models.DefaultLineupSlots},
+		{"combo slots count as flex", map[int]int{0: 1, 2: 2, 3: 1, 4: 2, 5: 1, 6: 1},
+			map[string]int{models.SlotQB: 1, models.SlotRB: 2, models.SlotWR: 2, models.SlotTE: 1, models.SlotFlex: 2}},
+		{"IDP slots are ignored", map[int]int{0: 2, 10: 2, 14: 1},
+			map[string]int{models.SlotQB: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LeagueSettingsFromSlotCounts(tt.counts); !reflect.DeepEqual(got.LineupSlots, tt.want) {
+				t.Errorf("LeagueSettingsFromSlotCounts() = %v, want %v", got.LineupSlots, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package espn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors returned for the ways ESPN refuses a league request, so callers can tell users
+// whether to refresh their cookies, ask for league access or check the league ID
+var (
+	ErrAuthExpired    = errors.New("ESPN authentication failed - espn_s2/SWID cookies are missing or expired")
+	ErrLeaguePrivate  = errors.New("ESPN league is private and these cookies don't have access to it")
+	ErrLeagueNotFound = errors.New("ESPN league not found - check the league ID and season")
+)
+
+// ESPN error detail types seen in JSON error bodies
+const (
+	detailLeagueNotVisible = "AUTH_LEAGUE_NOT_VISIBLE"
+	detailLeagueNotFound   = "LEAGUE_NOT_FOUND"
+)
+
+// errorResponse is the JSON body ESPN sends with 401/404 responses
+type errorResponse struct {
+	Messages []string `json:"messages"`
+	Details  []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"details"`
+}
+
+// classifyError maps a non-JSON or non-200 ESPN response to a typed error. ESPN answers a
+// private league with 401 and an AUTH_LEAGUE_NOT_VISIBLE detail, a missing league with 404
+// (LEAGUE_NOT_FOUND), and bad or expired cookies with a bare 401 or its HTML login page.
+// Other responses return nil so the caller can report them generically.
+func classifyError(status int, body []byte) error {
+	var parsed errorResponse
+	if len(body) > 0 && body[0] == '{' && json.Unmarshal(body, &parsed) == nil {
+		for _, d := range parsed.Details {
+			switch d.Type {
+			case detailLeagueNotVisible:
+				return ErrLeaguePrivate
+			case detailLeagueNotFound:
+				return ErrLeagueNotFound
+			}
+		}
+	}
+
+	switch {
+	case status == http.StatusNotFound:
+		return ErrLeagueNotFound
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrAuthExpired
+	case status == http.StatusOK && len(body) > 0 && body[0] == '<':
+		// ESPN redirects requests without valid cookies to an HTML login page
+		return ErrAuthExpired
+	}
+	return nil
+}
+
+// statusError describes an unexpected ESPN response, trimmed for logs
+func statusError(status int, body []byte) error {
+	preview := string(body)
+	if len(preview) > 500 {
+		preview = preview[:500] + "..."
+	}
+	return fmt.Errorf("ESPN API returned status %d. Response: %s", status, preview)
+}
@@ -0,0 +1,85 @@
+package espn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	privateLeagueBody = `{"messages":["You are not authorized to view this League."],"details":[{"message":"You are not authorized to view this League.","shortMessage":"You are not authorized to view this League.","resolved":false,"type":"AUTH_LEAGUE_NOT_VISIBLE","metaData":null}]}`
+	missingLeagueBody = `{"messages":["Not Found"],"details":[{"message":"Not Found","shortMessage":"Not Found","resolved":false,"type":"LEAGUE_NOT_FOUND","metaData":null}]}`
+	loginPageBody     = `<!DOCTYPE html><html><head><title>ESPN Fan Account</title></head><body>Log In</body></html>`
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"private league", http.StatusUnauthorized, privateLeagueBody, ErrLeaguePrivate},
+		{"private league detail wins over the status", http.StatusForbidden, privateLeagueBody, ErrLeaguePrivate},
+		{"missing league", http.StatusNotFound, missingLeagueBody, ErrLeagueNotFound},
+		{"bare 404", http.StatusNotFound, "", ErrLeagueNotFound},
+		{"expired cookies", http.StatusUnauthorized, `{"messages":["Unauthorized"]}`, ErrAuthExpired},
+		{"bare 401", http.StatusUnauthorized, "", ErrAuthExpired},
+		{"403 without details", http.StatusForbidden, "", ErrAuthExpired},
+		{"login page served with 200", http.StatusOK, loginPageBody, ErrAuthExpired},
+		{"login page served with 401", http.StatusUnauthorized, loginPageBody, ErrAuthExpired},
+		{"league JSON", http.StatusOK, `{"id":123456,"teams":[]}`, nil},
+		{"server error", http.StatusInternalServerError, `{"messages":["oops"]}`, nil},
+		{"malformed JSON", http.StatusBadGateway, `{"details":`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.status, []byte(tt.body)); got != tt.want {
+				t.Errorf("classifyError(%d, %q) = %v, want %v", tt.status, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoRequestErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"private league", http.StatusUnauthorized, privateLeagueBody, ErrLeaguePrivate},
+		{"missing league", http.StatusNotFound, missingLeagueBody, ErrLeagueNotFound},
+		{"expired cookies", http.StatusUnauthorized, "", ErrAuthExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := NewClient("123456", 2025, "{TEST-SWID}", "test-s2")
+			c.baseURL = srv.URL
+			if _, err := c.GetMatchup(context.Background(), 1, 10); !errors.Is(err, tt.want) {
+				t.Errorf("GetMatchup() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c := NewClient("123456", 2025, "{TEST-SWID}", "test-s2")
+	c.baseURL = srv.URL
+	_, err := c.GetMatchup(context.Background(), 1, 10)
+	if err == nil || errors.Is(err, ErrAuthExpired) || errors.Is(err, ErrLeaguePrivate) || errors.Is(err, ErrLeagueNotFound) {
+		t.Errorf("GetMatchup() on a 503 error = %v, want an untyped error", err)
+	}
+}
@@ -0,0 +1,34 @@
+package espn
+
+import "github.com/ai-atl/nfl-platform/internal/models"
+
+// lineupSlotMap maps ESPN lineup slot IDs to optimizer slots. The RB/WR (3) and WR/TE (5)
+// combo slots are treated as FLEX; bench, IR and IDP slots don't start offensive players.
+var lineupSlotMap = map[int]string{
+	0:  models.SlotQB,
+	2:  models.SlotRB,
+	3:  models.SlotFlex,
+	4:  models.SlotWR,
+	5:  models.SlotFlex,
+	6:  models.SlotTE,
+	7:  models.SlotSuperflex, // OP
+	16: models.SlotDST,
+	17: models.SlotK,
+	23: models.SlotFlex,
+}
+
+// LeagueSettingsFromSlotCounts converts ESPN's rosterSettings.lineupSlotCounts into the
+// optimizer's league settings, falling back to a standard league when no starting slots
+// are set
+func LeagueSettingsFromSlotCounts(counts map[int]int) models.LeagueSettings {
+	slots := make(map[string]int)
+	for slotID, count := range counts {
+		if slot, ok := lineupSlotMap[slotID]; ok && count > 0 {
+			slots[slot] += count
+		}
+	}
+	if len(slots) == 0 {
+		return models.DefaultLeagueSettings()
+	}
+	return models.LeagueSettings{LineupSlots: slots}
+}
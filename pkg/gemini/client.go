@@ -1,6 +1,7 @@
 package gemini
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -8,21 +9,75 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
 	baseURL = "https://generativelanguage.googleapis.com/v1"
+	// apiKeyHeader carries the API key, so it never appears in URLs that end up in errors or logs
+	apiKeyHeader = "x-goog-api-key"
+
+	defaultModel         = "gemini-2.5-flash-lite"
+	defaultAnalysisModel = "gemini-2.5-flash"
+	defaultTemperature   = 0.7
 )
 
 type Client struct {
-	apiKey     string
-	httpClient *http.Client
-	model      string
+	apiKey          string
+	baseURL         string
+	httpClient      *http.Client
+	model           string
+	temperature     float64
+	maxOutputTokens int // 0 leaves the model's default
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithModel sets the model name, e.g. gemini-2.5-flash
+func WithModel(model string) Option {
+	return func(c *Client) {
+		if model != "" {
+			c.model = model
+		}
+	}
+}
+
+// WithTemperature sets the sampling temperature (0 is deterministic)
+func WithTemperature(temperature float64) Option {
+	return func(c *Client) {
+		c.temperature = temperature
+	}
+}
+
+// WithMaxOutputTokens caps the length of generated responses
+func WithMaxOutputTokens(tokens int) Option {
+	return func(c *Client) {
+		c.maxOutputTokens = tokens
+	}
+}
+
+// FastTier selects the cheap, low-latency model (GEMINI_FAST_MODEL) with a low temperature,
+// for structured work like intent extraction
+func FastTier() Option {
+	return func(c *Client) {
+		c.model = getEnv("GEMINI_FAST_MODEL", defaultModel)
+		c.temperature = 0.1
+	}
+}
+
+// AnalysisTier selects the stronger model (GEMINI_ANALYSIS_MODEL) for long-form analysis
+// like trade, injury and lineup advice
+func AnalysisTier() Option {
+	return func(c *Client) {
+		c.model = getEnv("GEMINI_ANALYSIS_MODEL", defaultAnalysisModel)
+	}
 }
 
 type GenerateRequest struct {
-	Contents []Content `json:"contents"`
+	Contents         []Content        `json:"contents"`
 	GenerationConfig GenerationConfig `json:"generationConfig,omitempty"`
 }
 
@@ -35,9 +90,10 @@ type Part struct {
 }
 
 type GenerationConfig struct {
-	Temperature float64 `json:"temperature,omitempty"`
-	TopK        int     `json:"topK,omitempty"`
-	TopP        float64 `json:"topP,omitempty"`
+	Temperature     *float64 `json:"temperature,omitempty"` // Pointer so 0 is sent rather than dropped
+	TopK            int      `json:"topK,omitempty"`
+	TopP            float64  `json:"topP,omitempty"`
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
 }
 
 type GenerateResponse struct {
@@ -48,27 +104,40 @@ type Candidate struct {
 	Content Content `json:"content"`
 }
 
-// NewClient creates a new Gemini API client
-func NewClient() *Client {
+// NewClient creates a new Gemini API client. The model, temperature and output cap default to
+// GEMINI_MODEL, GEMINI_TEMPERATURE and GEMINI_MAX_OUTPUT_TOKENS; opts are applied after them.
+func NewClient(opts ...Option) *Client {
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
 		apiKey = "demo-key" // For development
 	}
 
-	return &Client{
-		apiKey: apiKey,
+	c := &Client{
+		apiKey:  apiKey,
+		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		model: "gemini-2.5-flash-lite",
+		model:       getEnv("GEMINI_MODEL", defaultModel),
+		temperature: defaultTemperature,
+	}
+	if t, err := strconv.ParseFloat(os.Getenv("GEMINI_TEMPERATURE"), 64); err == nil {
+		c.temperature = t
+	}
+	if n, err := strconv.Atoi(os.Getenv("GEMINI_MAX_OUTPUT_TOKENS")); err == nil {
+		c.maxOutputTokens = n
 	}
-}
 
-// Generate sends a prompt to Gemini and returns the response
-func (c *Client) Generate(ctx context.Context, prompt string) (string, error) {
-	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", baseURL, c.model, c.apiKey)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
 
-	reqBody := GenerateRequest{
+// newRequest builds the request body for a prompt with the client's generation settings
+func (c *Client) newRequest(prompt string) GenerateRequest {
+	temperature := c.temperature
+	return GenerateRequest{
 		Contents: []Content{
 			{
 				Parts: []Part{
@@ -77,11 +146,19 @@ func (c *Client) Generate(ctx context.Context, prompt string) (string, error) {
 			},
 		},
 		GenerationConfig: GenerationConfig{
-			Temperature: 0.7,
-			TopK:        40,
-			TopP:        0.95,
+			Temperature:     &temperature,
+			TopK:            40,
+			TopP:            0.95,
+			MaxOutputTokens: c.maxOutputTokens,
 		},
 	}
+}
+
+// Generate sends a prompt to Gemini and returns the response
+func (c *Client) Generate(ctx context.Context, prompt string) (string, error) {
+	url := fmt.Sprintf("%s/models/%s:generateContent", c.baseURL, c.model)
+
+	reqBody := c.newRequest(prompt)
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
@@ -94,6 +171,7 @@ func (c *Client) Generate(ctx context.Context, prompt string) (string, error) {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(apiKeyHeader, c.apiKey)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -122,6 +200,32 @@ func (c *Client) Generate(ctx context.Context, prompt string) (string, error) {
 	return genResp.Candidates[0].Content.Parts[0].Text, nil
 }
 
+// Ping checks that an API key is configured and that the model is reachable. It fetches
+// the model's metadata, which doesn't consume generation quota.
+func (c *Client) Ping(ctx context.Context) error {
+	if c.apiKey == "" || c.apiKey == "demo-key" {
+		return fmt.Errorf("GEMINI_API_KEY is not configured")
+	}
+
+	url := fmt.Sprintf("%s/models/%s", c.baseURL, c.model)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set(apiKeyHeader, c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error (%d)", resp.StatusCode)
+	}
+	return nil
+}
+
 // GenerateWithRetry generates with automatic retry on failure
 func (c *Client) GenerateWithRetry(ctx context.Context, prompt string, retries int) (string, error) {
 	var lastErr error
@@ -136,3 +240,94 @@ func (c *Client) GenerateWithRetry(ctx context.Context, prompt string, retries i
 	return "", fmt.Errorf("failed after %d retries: %w", retries, lastErr)
 }
 
+// GenerateStream sends a prompt to Gemini and streams the response text as it
+// is generated. The chunk channel is closed when the response completes; at
+// most one error is sent on the error channel. Cancelling ctx aborts the
+// upstream request.
+func (c *Client) GenerateStream(ctx context.Context, prompt string) (<-chan string, <-chan error) {
+	chunks := make(chan string)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+		defer close(errs)
+
+		url := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse", c.baseURL, c.model)
+
+		reqBody := c.newRequest(prompt)
+
+		jsonData, err := json.Marshal(reqBody)
+		if err != nil {
+			errs <- fmt.Errorf("failed to marshal request: %w", err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			errs <- fmt.Errorf("failed to create request: %w", err)
+			return
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(apiKeyHeader, c.apiKey)
+
+		// Streams can outlive the default client timeout, so rely on ctx instead
+		streamClient := &http.Client{Transport: c.httpClient.Transport}
+		resp, err := streamClient.Do(req)
+		if err != nil {
+			errs <- fmt.Errorf("failed to send request: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			errs <- fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			var genResp GenerateResponse
+			if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &genResp); err != nil {
+				errs <- fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+				return
+			}
+			if len(genResp.Candidates) == 0 {
+				continue
+			}
+
+			for _, part := range genResp.Candidates[0].Content.Parts {
+				if part.Text == "" {
+					continue
+				}
+				select {
+				case chunks <- part.Text:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errs <- fmt.Errorf("failed to read stream: %w", err)
+		}
+	}()
+
+	return chunks, errs
+}
+
+// getEnv returns an environment variable or a default
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
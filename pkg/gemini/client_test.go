@@ -0,0 +1,114 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const generatePayload = `{"candidates": [{"content": {"parts": [{"text": "ok"}]}}]}`
+
+// sentRequest is what the test server received for one generate call
+type sentRequest struct {
+	path string
+	body GenerateRequest
+}
+
+// setGeminiEnv sets the Gemini env vars for the test, clearing any not in env
+func setGeminiEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range []string{"GEMINI_MODEL", "GEMINI_FAST_MODEL", "GEMINI_ANALYSIS_MODEL", "GEMINI_TEMPERATURE", "GEMINI_MAX_OUTPUT_TOKENS"} {
+		t.Setenv(key, env[key])
+	}
+}
+
+// newTestClient returns a client built with opts that sends to a test server, and the
+// requests that server received
+func newTestClient(t *testing.T, opts ...Option) (*Client, *[]sentRequest) {
+	t.Helper()
+
+	var sent []sentRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body GenerateRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		sent = append(sent, sentRequest{path: r.URL.Path, body: body})
+		w.Write([]byte(generatePayload))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient(opts...)
+	c.baseURL = srv.URL
+	return c, &sent
+}
+
+func TestTierOptionsPropagateIntoRequest(t *testing.T) {
+	tests := []struct {
+		name            string
+		env             map[string]string
+		opts            []Option
+		wantPath        string
+		wantTemperature float64
+		wantMaxTokens   int
+	}{
+		{
+			name:            "default",
+			wantPath:        "/models/gemini-2.5-flash-lite:generateContent",
+			wantTemperature: 0.7,
+		},
+		{
+			name:            "fast tier",
+			env:             map[string]string{"GEMINI_FAST_MODEL": "gemini-fast-test", "GEMINI_TEMPERATURE": "0.9"},
+			opts:            []Option{FastTier()},
+			wantPath:        "/models/gemini-fast-test:generateContent",
+			wantTemperature: 0.1,
+		},
+		{
+			name:            "analysis tier keeps env temperature and cap",
+			env:             map[string]string{"GEMINI_TEMPERATURE": "0.3", "GEMINI_MAX_OUTPUT_TOKENS": "2048"},
+			opts:            []Option{AnalysisTier()},
+			wantPath:        "/models/gemini-2.5-flash:generateContent",
+			wantTemperature: 0.3,
+			wantMaxTokens:   2048,
+		},
+		{
+			name:            "options after a tier override it",
+			opts:            []Option{AnalysisTier(), WithModel("gemini-custom"), WithTemperature(0), WithMaxOutputTokens(256)},
+			wantPath:        "/models/gemini-custom:generateContent",
+			wantTemperature: 0,
+			wantMaxTokens:   256,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setGeminiEnv(t, tt.env)
+			c, sent := newTestClient(t, tt.opts...)
+
+			if _, err := c.Generate(context.Background(), "Who starts at flex?"); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if len(*sent) != 1 {
+				t.Fatalf("sent %d requests, want 1", len(*sent))
+			}
+
+			req := (*sent)[0]
+			if req.path != tt.wantPath {
+				t.Errorf("path = %s, want %s", req.path, tt.wantPath)
+			}
+			config := req.body.GenerationConfig
+			// A temperature of 0 must be sent rather than dropped
+			if config.Temperature == nil {
+				t.Errorf("temperature omitted, want %v", tt.wantTemperature)
+			} else if *config.Temperature != tt.wantTemperature {
+				t.Errorf("temperature = %v, want %v", *config.Temperature, tt.wantTemperature)
+			}
+			if config.MaxOutputTokens != tt.wantMaxTokens {
+				t.Errorf("maxOutputTokens = %d, want %d", config.MaxOutputTokens, tt.wantMaxTokens)
+			}
+		})
+	}
+}
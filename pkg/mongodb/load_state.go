@@ -0,0 +1,26 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// RecordLoad marks a dataset as loaded now in the load_state collection, keyed by dataset
+// and year (0 for data spanning all seasons). Anything that rewrites games, stats or plays
+// calls it after writing so responses cached against the last load, like the leaderboard
+// ETags, are revalidated. A content hash the full loader stored for the key is kept.
+func RecordLoad(ctx context.Context, db *mongo.Database, dataset string, year, rows int) error {
+	_, err := db.Collection("load_state").UpdateOne(ctx,
+		bson.M{"dataset": dataset, "year": year},
+		bson.M{"$set": bson.M{"rows": rows, "loaded_at": time.Now()}},
+		options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to record %s %d load: %w", dataset, year, err)
+	}
+	return nil
+}
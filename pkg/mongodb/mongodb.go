@@ -2,6 +2,8 @@ package mongodb
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -10,21 +12,51 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
-// Connect establishes a connection to MongoDB
-func Connect(ctx context.Context, uri string) (*mongo.Client, error) {
+// PoolOptions tunes the driver's connection pool, timeouts and retries
+type PoolOptions struct {
+	MaxPoolSize            uint64
+	MinPoolSize            uint64
+	MaxConnIdleTime        time.Duration
+	ConnectTimeout         time.Duration
+	ServerSelectionTimeout time.Duration // How long an operation waits for a reachable server
+	RetryWrites            bool
+	RetryReads             bool
+}
+
+// DefaultPoolOptions are the settings used when none are configured
+func DefaultPoolOptions() PoolOptions {
+	return PoolOptions{
+		MaxPoolSize:            50,
+		MinPoolSize:            10,
+		MaxConnIdleTime:        30 * time.Second,
+		ConnectTimeout:         30 * time.Second, // Longer timeout for initial connection
+		ServerSelectionTimeout: 30 * time.Second, // Longer timeout for Atlas
+		RetryWrites:            true,
+		RetryReads:             true,
+	}
+}
+
+// ClientOptions builds the driver options for uri with the given pool settings
+func ClientOptions(uri string, pool PoolOptions) *options.ClientOptions {
 	// Use ServerAPI for MongoDB Atlas compatibility
 	serverAPI := options.ServerAPI(options.ServerAPIVersion1)
 
-	clientOptions := options.Client().
+	return options.Client().
 		ApplyURI(uri).
 		SetServerAPIOptions(serverAPI).
-		SetMaxPoolSize(50).
-		SetMinPoolSize(10).
-		SetMaxConnIdleTime(30 * time.Second).
-		SetConnectTimeout(30 * time.Second).        // Longer timeout for initial connection
-		SetServerSelectionTimeout(30 * time.Second) // Longer timeout for Atlas
+		SetMaxPoolSize(pool.MaxPoolSize).
+		SetMinPoolSize(pool.MinPoolSize).
+		SetMaxConnIdleTime(pool.MaxConnIdleTime).
+		SetConnectTimeout(pool.ConnectTimeout).
+		SetServerSelectionTimeout(pool.ServerSelectionTimeout).
+		SetRetryWrites(pool.RetryWrites).
+		SetRetryReads(pool.RetryReads)
+}
 
-	client, err := mongo.Connect(clientOptions)
+// Connect establishes a connection to MongoDB. The driver reconnects on its own after an
+// outage; use a Monitor to report the primary as unavailable in the meantime.
+func Connect(ctx context.Context, uri string, pool PoolOptions) (*mongo.Client, error) {
+	client, err := mongo.Connect(ClientOptions(uri, pool))
 	if err != nil {
 		return nil, err
 	}
@@ -37,8 +69,12 @@ func Connect(ctx context.Context, uri string) (*mongo.Client, error) {
 	return client, nil
 }
 
-// CreateIndexes creates necessary MongoDB indexes for performance
+// CreateIndexes creates necessary MongoDB indexes for performance and for the unique and
+// TTL constraints the app relies on. A collection whose indexes can't be created doesn't
+// stop the rest; the failures are returned together.
 func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	var errs []error
+
 	// Players collection indexes
 	playerIndexes := []mongo.IndexModel{
 		{
@@ -52,10 +88,13 @@ func CreateIndexes(ctx context.Context, db *mongo.Database) error {
 		{
 			Keys: bson.D{{"season", 1}},
 		},
+		{
+			// Supports anchored (prefix) name searches
+			Keys: bson.D{{"name", 1}},
+		},
 	}
-	_, err := db.Collection("players").Indexes().CreateMany(ctx, playerIndexes)
-	if err != nil {
-		return err
+	if _, err := db.Collection("players").Indexes().CreateMany(ctx, playerIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("players indexes: %w", err))
 	}
 
 	// Player stats collection indexes
@@ -69,9 +108,8 @@ func CreateIndexes(ctx context.Context, db *mongo.Database) error {
 			Keys: bson.D{{"season", 1}},
 		},
 	}
-	_, err = db.Collection("player_stats").Indexes().CreateMany(ctx, playerStatsIndexes)
-	if err != nil {
-		return err
+	if _, err := db.Collection("player_stats").Indexes().CreateMany(ctx, playerStatsIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("player_stats indexes: %w", err))
 	}
 
 	// Player weekly stats collection indexes
@@ -88,9 +126,22 @@ func CreateIndexes(ctx context.Context, db *mongo.Database) error {
 			Keys: bson.D{{"season", 1}, {"week", 1}},
 		},
 	}
-	_, err = db.Collection("player_weekly_stats").Indexes().CreateMany(ctx, weeklyStatsIndexes)
-	if err != nil {
-		return err
+	if _, err := db.Collection("player_weekly_stats").Indexes().CreateMany(ctx, weeklyStatsIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("player_weekly_stats indexes: %w", err))
+	}
+
+	// Kicker and team defense weekly stats indexes (one entry per kicker/team per week)
+	if _, err := db.Collection("kicker_weekly_stats").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"nfl_id", 1}, {"season", 1}, {"week", 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		errs = append(errs, fmt.Errorf("kicker_weekly_stats indexes: %w", err))
+	}
+	if _, err := db.Collection("defense_weekly_stats").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"team", 1}, {"season", 1}, {"week", 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		errs = append(errs, fmt.Errorf("defense_weekly_stats indexes: %w", err))
 	}
 
 	// Games collection indexes
@@ -102,10 +153,16 @@ func CreateIndexes(ctx context.Context, db *mongo.Database) error {
 		{
 			Keys: bson.D{{"season", 1}, {"week", 1}},
 		},
+		{
+			// Upcoming games by team ($or on home/away, each branch sorted by kickoff)
+			Keys: bson.D{{"home_team", 1}, {"start_time", 1}},
+		},
+		{
+			Keys: bson.D{{"away_team", 1}, {"start_time", 1}},
+		},
 	}
-	_, err = db.Collection("games").Indexes().CreateMany(ctx, gameIndexes)
-	if err != nil {
-		return err
+	if _, err := db.Collection("games").Indexes().CreateMany(ctx, gameIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("games indexes: %w", err))
 	}
 
 	// Plays collection indexes
@@ -118,9 +175,8 @@ func CreateIndexes(ctx context.Context, db *mongo.Database) error {
 			Keys: bson.D{{"season", 1}, {"week", 1}},
 		},
 	}
-	_, err = db.Collection("plays").Indexes().CreateMany(ctx, playIndexes)
-	if err != nil {
-		return err
+	if _, err := db.Collection("plays").Indexes().CreateMany(ctx, playIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("plays indexes: %w", err))
 	}
 
 	// Next Gen Stats collection indexes
@@ -137,9 +193,8 @@ func CreateIndexes(ctx context.Context, db *mongo.Database) error {
 			Keys: bson.D{{"stat_type", 1}, {"season", 1}},
 		},
 	}
-	_, err = db.Collection("next_gen_stats").Indexes().CreateMany(ctx, ngsIndexes)
-	if err != nil {
-		return err
+	if _, err := db.Collection("next_gen_stats").Indexes().CreateMany(ctx, ngsIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("next_gen_stats indexes: %w", err))
 	}
 
 	// Users collection indexes
@@ -149,9 +204,26 @@ func CreateIndexes(ctx context.Context, db *mongo.Database) error {
 			Options: options.Index().SetUnique(true),
 		},
 	}
-	_, err = db.Collection("users").Indexes().CreateMany(ctx, userIndexes)
-	if err != nil {
-		return err
+	if _, err := db.Collection("users").Indexes().CreateMany(ctx, userIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("users indexes: %w", err))
+	}
+
+	// Refresh tokens collection indexes (expired tokens are removed by the TTL index)
+	refreshTokenIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"token_hash", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{"user_id", 1}},
+		},
+		{
+			Keys:    bson.D{{"expires_at", 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+	if _, err := db.Collection("refresh_tokens").Indexes().CreateMany(ctx, refreshTokenIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("refresh_tokens indexes: %w", err))
 	}
 
 	// Lineups collection indexes
@@ -160,18 +232,208 @@ func CreateIndexes(ctx context.Context, db *mongo.Database) error {
 			Keys: bson.D{{"user_id", 1}, {"week", 1}},
 		},
 	}
-	_, err = db.Collection("lineups").Indexes().CreateMany(ctx, lineupIndexes)
-	if err != nil {
-		return err
+	if _, err := db.Collection("lineups").Indexes().CreateMany(ctx, lineupIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("lineups indexes: %w", err))
 	}
 
 	// Votes collection indexes
 	voteIndexes := []mongo.IndexModel{
 		{
-			Keys: bson.D{{"player_id", 1}, {"week", 1}},
+			// One vote per user per subject (re-voting replaces the previous choice)
+			Keys:    bson.D{{"user_id", 1}, {"subject_type", 1}, {"subject_id", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{"subject_type", 1}, {"subject_id", 1}},
+		},
+	}
+	if _, err := db.Collection("votes").Indexes().CreateMany(ctx, voteIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("votes indexes: %w", err))
+	}
+
+	// Idempotency keys collection indexes (expired keys are removed by the TTL index)
+	idempotencyKeyIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"key", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{"expires_at", 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+	if _, err := db.Collection("idempotency_keys").Indexes().CreateMany(ctx, idempotencyKeyIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("idempotency_keys indexes: %w", err))
+	}
+
+	// Team branding and alignment (one document per abbreviation)
+	teamIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"abbr", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := db.Collection("teams").Indexes().CreateMany(ctx, teamIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("teams indexes: %w", err))
+	}
+
+	// Precomputed defense rankings indexes
+	defenseRankingIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"season", 1}, {"through_week", 1}, {"position", 1}, {"rank", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{"computed_at", -1}}, // Latest refresh, for leaderboard ETags
+		},
+	}
+	if _, err := db.Collection("defense_rankings").Indexes().CreateMany(ctx, defenseRankingIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("defense_rankings indexes: %w", err))
+	}
+
+	// Trade history collection indexes
+	tradeIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{"user_id", 1}, {"created_at", -1}},
 		},
 	}
-	_, err = db.Collection("votes").Indexes().CreateMany(ctx, voteIndexes)
+	if _, err := db.Collection("trades").Indexes().CreateMany(ctx, tradeIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("trades indexes: %w", err))
+	}
+
+	// Chat messages collection indexes
+	chatIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{"user_id", 1}, {"timestamp", -1}},
+		},
+	}
+	if _, err := db.Collection("chat_messages").Indexes().CreateMany(ctx, chatIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("chat_messages indexes: %w", err))
+	}
+
+	// Injury reports collection indexes
+	injuryIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"nfl_id", 1}, {"season", 1}, {"week", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{"season", 1}, {"week", 1}, {"report_status", 1}},
+		},
+	}
+	if _, err := db.Collection("injuries").Indexes().CreateMany(ctx, injuryIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("injuries indexes: %w", err))
+	}
+
+	// Snap counts collection indexes
+	snapIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"nfl_id", 1}, {"season", 1}, {"week", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := db.Collection("snap_counts").Indexes().CreateMany(ctx, snapIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("snap_counts indexes: %w", err))
+	}
+
+	// Player team history indexes (one stint per player, season and starting week)
+	teamHistoryIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"nfl_id", 1}, {"season", 1}, {"from_week", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{"team", 1}, {"season", 1}, {"from_week", 1}},
+		},
+	}
+	if _, err := db.Collection("player_team_history").Indexes().CreateMany(ctx, teamHistoryIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("player_team_history indexes: %w", err))
+	}
+
+	// Player weekly status indexes (one status per player, season and week)
+	weeklyStatusIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"nfl_id", 1}, {"season", 1}, {"week", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{"season", 1}, {"week", 1}, {"status", 1}},
+		},
+	}
+	if _, err := db.Collection("player_weekly_status").Indexes().CreateMany(ctx, weeklyStatusIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("player_weekly_status indexes: %w", err))
+	}
+
+	// ESPN roster alert indexes (one snapshot per user)
+	rosterSnapshotIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"user_id", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := db.Collection("roster_snapshots").Indexes().CreateMany(ctx, rosterSnapshotIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("roster_snapshots indexes: %w", err))
+	}
+
+	alertIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{"user_id", 1}, {"created_at", -1}},
+		},
+	}
+	if _, err := db.Collection("alerts").Indexes().CreateMany(ctx, alertIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("alerts indexes: %w", err))
+	}
+
+	// Recommendation accuracy indexes (pending lookup by the scoring job, outcomes by season)
+	recommendationIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{"user_id", 1}, {"kind", 1}, {"season", 1}, {"week", 1}},
+		},
+		{
+			Keys: bson.D{{"scored", 1}, {"season", 1}, {"week", 1}},
+		},
+	}
+	if _, err := db.Collection("recommendations").Indexes().CreateMany(ctx, recommendationIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("recommendations indexes: %w", err))
+	}
+
+	outcomeIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{"season", 1}, {"kind", 1}},
+		},
+		{
+			Keys:    bson.D{{"recommendation_id", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := db.Collection("recommendations_outcomes").Indexes().CreateMany(ctx, outcomeIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("recommendations_outcomes indexes: %w", err))
+	}
+
+	// QBR collection indexes
+	qbrIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"espn_id", 1}, {"season", 1}, {"season_type", 1}, {"week", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{"player_name", 1}, {"season", 1}},
+		},
+	}
+	if _, err := db.Collection("qbr").Indexes().CreateMany(ctx, qbrIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("qbr indexes: %w", err))
+	}
+
+	// Load state collection indexes (one record per loaded source file)
+	loadStateIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{"dataset", 1}, {"year", 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := db.Collection("load_state").Indexes().CreateMany(ctx, loadStateIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("load_state indexes: %w", err))
+	}
 
-	return err
+	return errors.Join(errs...)
 }
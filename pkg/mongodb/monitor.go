@@ -0,0 +1,56 @@
+package mongodb
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+// Monitor periodically pings the primary so requests can fail fast with a clear error while
+// it is unreachable instead of each waiting out server selection
+type Monitor struct {
+	client  *mongo.Client
+	healthy atomic.Bool
+}
+
+// NewMonitor returns a monitor for a connected client, initially healthy
+func NewMonitor(client *mongo.Client) *Monitor {
+	m := &Monitor{client: client}
+	m.healthy.Store(true)
+	return m
+}
+
+// Healthy reports whether the last ping reached the primary
+func (m *Monitor) Healthy() bool {
+	return m.healthy.Load()
+}
+
+// Run pings the primary every interval until ctx is cancelled, logging when it goes down
+// and when it recovers
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, interval)
+			err := m.client.Ping(pingCtx, readpref.Primary())
+			cancel()
+
+			wasHealthy := m.healthy.Swap(err == nil)
+			switch {
+			case err != nil && wasHealthy:
+				log.Printf("MongoDB primary unreachable: %v", err)
+			case err == nil && !wasHealthy:
+				log.Println("MongoDB primary reachable again")
+			}
+		}
+	}
+}
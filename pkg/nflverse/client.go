@@ -32,6 +32,12 @@ func (c *Client) FetchPlayerStats(ctx context.Context, season int) ([]byte, erro
 	return c.downloadFile(ctx, url)
 }
 
+// FetchSchedules downloads the schedule and results for every season
+func (c *Client) FetchSchedules(ctx context.Context) ([]byte, error) {
+	url := fmt.Sprintf("%s/schedules/games.parquet", baseURL)
+	return c.downloadFile(ctx, url)
+}
+
 // FetchPlayByPlay downloads play-by-play data for a given season
 func (c *Client) FetchPlayByPlay(ctx context.Context, season int) ([]byte, error) {
 	url := fmt.Sprintf("%s/pbp/play_by_play_%d.parquet", baseURL, season)
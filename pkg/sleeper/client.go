@@ -6,16 +6,29 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	baseURL = "https://api.sleeper.app/v1"
+	defaultBaseURL = "https://api.sleeper.app/v1"
+
+	// playerMapTTL is how long the players dump is reused; Sleeper asks that it be
+	// fetched at most once a day
+	playerMapTTL = 24 * time.Hour
+
+	// playerMapRetryInterval is how long to wait after a failed players download before
+	// trying again, so lookups don't each retry it while Sleeper is down
+	playerMapRetryInterval = 10 * time.Minute
+
+	// Sleeper allows up to 1000 calls a minute; stay well under it
+	requestsPerSecond = 10
+	requestBurst      = 10
 )
 
 type Client struct {
-	httpClient     *http.Client
-	playerMappings map[string]string // NFL name -> Sleeper ID
+	httpClient *http.Client
+	baseURL    string
 }
 
 func NewClient() *Client {
@@ -23,10 +36,81 @@ func NewClient() *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		playerMappings: make(map[string]string),
+		baseURL: defaultBaseURL,
+	}
+}
+
+// playerCache is the name -> Sleeper ID map shared by every Client, so the multi-megabyte
+// players dump is downloaded once per day rather than once per client or lookup
+var playerCache = struct {
+	sync.Mutex
+	byNameTeam map[string]string // "name|TEAM" -> Sleeper ID
+	byName     map[string]string // name -> Sleeper ID, for lookups without a team
+	loadedAt   time.Time
+	failedAt   time.Time // Last failed download, for backing off retries
+}{}
+
+// limiter throttles every request to Sleeper across all clients
+var limiter = newTokenBucket(requestsPerSecond, requestBurst)
+
+// tokenBucket is a minimal rate limiter: tokens refill at rate per second up to burst,
+// and each request takes one
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	rate     float64
+	burst    float64
+	lastFill time.Time
+}
+
+func newTokenBucket(rate, burst int) *tokenBucket {
+	return &tokenBucket{
+		tokens:   float64(burst),
+		rate:     float64(rate),
+		burst:    float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.lastFill).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.lastFill = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
 	}
 }
 
+// get issues a rate-limited GET request
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	if err := limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.httpClient.Do(req)
+}
+
 // SleeperPlayer represents a player from Sleeper's players endpoint
 type SleeperPlayer struct {
 	PlayerID  string `json:"player_id"`
@@ -40,16 +124,18 @@ type SleeperPlayer struct {
 	Active    bool   `json:"active"`
 }
 
-// LoadPlayerMappings fetches all players and builds name->ID mapping
+// LoadPlayerMappings downloads all players and replaces the shared name+team -> ID mapping
 func (c *Client) LoadPlayerMappings(ctx context.Context) error {
-	url := fmt.Sprintf("%s/players/nfl", baseURL)
+	playerCache.Lock()
+	defer playerCache.Unlock()
+	return c.loadPlayerMappingsLocked(ctx)
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+// loadPlayerMappingsLocked refreshes playerCache; the caller holds its lock
+func (c *Client) loadPlayerMappingsLocked(ctx context.Context) error {
+	url := fmt.Sprintf("%s/players/nfl", c.baseURL)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.get(ctx, url)
 	if err != nil {
 		return fmt.Errorf("failed to fetch players: %w", err)
 	}
@@ -64,28 +150,76 @@ func (c *Client) LoadPlayerMappings(ctx context.Context) error {
 		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// Build mapping: normalized name -> sleeper ID
+	// Build mappings: normalized name (+ team) -> sleeper ID
+	byNameTeam := make(map[string]string, len(players))
+	byName := make(map[string]string, len(players))
 	for sleeperID, player := range players {
 		if player.Active && player.FullName != "" {
 			normalizedName := normalizeName(player.FullName)
-			c.playerMappings[normalizedName] = sleeperID
+			byName[normalizedName] = sleeperID
+			if player.Team != "" {
+				byNameTeam[nameTeamKey(normalizedName, player.Team)] = sleeperID
+			}
 		}
 	}
 
-	fmt.Printf("Loaded %d active player mappings from Sleeper\n", len(c.playerMappings))
+	playerCache.byNameTeam = byNameTeam
+	playerCache.byName = byName
+	playerCache.loadedAt = time.Now()
+
+	fmt.Printf("Loaded %d active player mappings from Sleeper\n", len(byName))
 	return nil
 }
 
-// GetWeeklyStats fetches weekly stats for all players
-func (c *Client) GetWeeklyStats(ctx context.Context, season string, week int) (map[string]map[string]float64, error) {
-	url := fmt.Sprintf("%s/stats/nfl/regular/%s/%d", baseURL, season, week)
+// ResolvePlayerID returns the Sleeper ID for a player, loading the players dump on first
+// use and refreshing it once it is older than playerMapTTL. A failed download isn't retried
+// for playerMapRetryInterval. Team disambiguates players who share a name; pass "" to match
+// on name alone.
+func (c *Client) ResolvePlayerID(ctx context.Context, playerName, team string) (string, error) {
+	playerCache.Lock()
+	defer playerCache.Unlock()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	needsLoad := playerCache.byName == nil || time.Since(playerCache.loadedAt) > playerMapTTL
+	if needsLoad && time.Since(playerCache.failedAt) > playerMapRetryInterval {
+		fmt.Println("Loading Sleeper player mappings...")
+		if err := c.loadPlayerMappingsLocked(ctx); err != nil {
+			playerCache.failedAt = time.Now()
+			// Keep serving a stale map rather than failing every lookup
+			if playerCache.byName == nil {
+				return "", err
+			}
+			fmt.Printf("Warning: failed to refresh Sleeper players, using cached map: %v\n", err)
+		}
+	}
+	if playerCache.byName == nil {
+		return "", fmt.Errorf("sleeper player mappings unavailable, retrying after %s", playerCache.failedAt.Add(playerMapRetryInterval).Format(time.Kitchen))
+	}
+
+	candidates := []string{normalizeName(playerName)}
+	// Try alternative formats (first name + last name)
+	if parts := strings.Fields(playerName); len(parts) >= 2 {
+		candidates = append(candidates, normalizeName(parts[0]+parts[len(parts)-1]))
+	}
+
+	for _, name := range candidates {
+		if team != "" {
+			if id, ok := playerCache.byNameTeam[nameTeamKey(name, team)]; ok {
+				return id, nil
+			}
+		}
+		if id, ok := playerCache.byName[name]; ok {
+			return id, nil
+		}
 	}
 
-	resp, err := c.httpClient.Do(req)
+	return "", fmt.Errorf("player not found: %s (normalized: %s)", playerName, candidates[0])
+}
+
+// GetWeeklyStats fetches weekly stats for all players
+func (c *Client) GetWeeklyStats(ctx context.Context, season string, week int) (map[string]map[string]float64, error) {
+	url := fmt.Sprintf("%s/stats/nfl/regular/%s/%d", c.baseURL, season, week)
+
+	resp, err := c.get(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch stats: %w", err)
 	}
@@ -103,34 +237,13 @@ func (c *Client) GetWeeklyStats(ctx context.Context, season string, week int) (m
 	return stats, nil
 }
 
-// GetPlayerSnapCount gets snap percentage for a specific player and week
-func (c *Client) GetPlayerSnapCount(ctx context.Context, playerName string, season string, week int) (float64, error) {
-	// Load player mappings if not already loaded
-	if len(c.playerMappings) == 0 {
-		fmt.Println("Loading Sleeper player mappings...")
-		if err := c.LoadPlayerMappings(ctx); err != nil {
-			return 0, err
-		}
-	}
-
-	// Find Sleeper ID for this player
-	normalizedName := normalizeName(playerName)
-	sleeperID, ok := c.playerMappings[normalizedName]
-	if !ok {
-		// Try alternative formats (first name + last name)
-		parts := strings.Fields(playerName)
-		if len(parts) >= 2 {
-			// Try "FirstLast" format
-			altName := normalizeName(parts[0] + parts[len(parts)-1])
-			if id, found := c.playerMappings[altName]; found {
-				sleeperID = id
-				ok = true
-			}
-		}
-
-		if !ok {
-			return 0, fmt.Errorf("player not found: %s (normalized: %s)", playerName, normalizedName)
-		}
+// GetPlayerSnapCount gets snap percentage for a specific player and week. Team is optional
+// and disambiguates players who share a name.
+func (c *Client) GetPlayerSnapCount(ctx context.Context, playerName, team string, season string, week int) (float64, error) {
+	// Find Sleeper ID for this player from the cached mapping
+	sleeperID, err := c.ResolvePlayerID(ctx, playerName, team)
+	if err != nil {
+		return 0, err
 	}
 
 	// Get weekly stats
@@ -161,6 +274,11 @@ func (c *Client) GetPlayerSnapCount(ctx context.Context, playerName string, seas
 	return 0, nil
 }
 
+// nameTeamKey keys a normalized name by team abbreviation
+func nameTeamKey(normalizedName, team string) string {
+	return normalizedName + "|" + strings.ToUpper(team)
+}
+
 // normalizeName converts player name to lowercase, removes punctuation
 func normalizeName(name string) string {
 	name = strings.ToLower(name)
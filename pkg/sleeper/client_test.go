@@ -0,0 +1,139 @@
+package sleeper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const playersPayload = `{
+	"4034": {"player_id": "4034", "full_name": "Christian McCaffrey", "team": "SF", "position": "RB", "active": true},
+	"6794": {"player_id": "6794", "full_name": "Justin Jefferson", "team": "MIN", "position": "WR", "active": true},
+	"9000": {"player_id": "9000", "full_name": "Mike Williams", "team": "PIT", "position": "WR", "active": true},
+	"9001": {"player_id": "9001", "full_name": "Mike Williams", "team": "NYJ", "position": "WR", "active": true},
+	"1234": {"player_id": "1234", "full_name": "Retired Player", "team": "", "position": "QB", "active": false}
+}`
+
+// newTestClient resets the shared player cache and returns a client whose players dump is
+// served by handler, along with a count of dump downloads
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *int32) {
+	t.Helper()
+	resetPlayerCache()
+	t.Cleanup(resetPlayerCache)
+
+	var downloads int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/players/nfl" {
+			atomic.AddInt32(&downloads, 1)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient()
+	c.baseURL = srv.URL
+	return c, &downloads
+}
+
+func resetPlayerCache() {
+	playerCache.Lock()
+	defer playerCache.Unlock()
+	playerCache.byNameTeam = nil
+	playerCache.byName = nil
+	playerCache.loadedAt = time.Time{}
+	playerCache.failedAt = time.Time{}
+}
+
+func TestResolvePlayerIDLoadsPlayersOnce(t *testing.T) {
+	c, downloads := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(playersPayload))
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name, team, want string
+	}{
+		{"Christian McCaffrey", "SF", "4034"},
+		{"Justin Jefferson", "", "6794"},
+		{"Mike Williams", "NYJ", "9001"},
+		{"Mike Williams", "PIT", "9000"},
+	}
+	for _, tt := range tests {
+		got, err := c.ResolvePlayerID(ctx, tt.name, tt.team)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolvePlayerID(%q, %q) = %q, %v; want %q", tt.name, tt.team, got, err, tt.want)
+		}
+	}
+
+	// A second client shares the cache
+	other := NewClient()
+	other.baseURL = c.baseURL
+	if _, err := other.ResolvePlayerID(ctx, "Justin Jefferson", "MIN"); err != nil {
+		t.Errorf("ResolvePlayerID() on a second client error = %v", err)
+	}
+	if _, err := c.ResolvePlayerID(ctx, "Retired Player", ""); err == nil {
+		t.Error("ResolvePlayerID() found an inactive player")
+	}
+
+	if n := atomic.LoadInt32(downloads); n != 1 {
+		t.Errorf("players dump downloaded %d times, want 1", n)
+	}
+}
+
+func TestResolvePlayerIDBacksOffFailedLoads(t *testing.T) {
+	c, downloads := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.ResolvePlayerID(ctx, "Justin Jefferson", "MIN"); err == nil {
+			t.Fatal("ResolvePlayerID() with Sleeper down returned no error")
+		}
+	}
+	if n := atomic.LoadInt32(downloads); n != 1 {
+		t.Errorf("players dump requested %d times while backing off, want 1", n)
+	}
+
+	// Once the retry interval has passed the next lookup tries again
+	playerCache.Lock()
+	playerCache.failedAt = time.Now().Add(-playerMapRetryInterval - time.Second)
+	playerCache.Unlock()
+	c.ResolvePlayerID(ctx, "Justin Jefferson", "MIN")
+	if n := atomic.LoadInt32(downloads); n != 2 {
+		t.Errorf("players dump requested %d times after the retry interval, want 2", n)
+	}
+}
+
+func TestResolvePlayerIDKeepsStaleMapOnFailedRefresh(t *testing.T) {
+	var fail atomic.Bool
+	c, downloads := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(playersPayload))
+	})
+	ctx := context.Background()
+
+	if _, err := c.ResolvePlayerID(ctx, "Justin Jefferson", "MIN"); err != nil {
+		t.Fatalf("ResolvePlayerID() error = %v", err)
+	}
+
+	// Expire the map with Sleeper down: lookups use the stale map and retry only once
+	fail.Store(true)
+	playerCache.Lock()
+	playerCache.loadedAt = time.Now().Add(-playerMapTTL - time.Minute)
+	playerCache.Unlock()
+	for i := 0; i < 3; i++ {
+		if got, err := c.ResolvePlayerID(ctx, "Justin Jefferson", "MIN"); err != nil || got != "6794" {
+			t.Errorf("ResolvePlayerID() with a stale map = %q, %v; want 6794", got, err)
+		}
+	}
+	if n := atomic.LoadInt32(downloads); n != 2 {
+		t.Errorf("players dump requested %d times, want 2", n)
+	}
+}
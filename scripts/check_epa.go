@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/ai-atl/nfl-platform/internal/config"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -30,7 +31,8 @@ func main() {
 	}
 	defer client.Disconnect(context.Background())
 
-	db := client.Database("nfl_platform")
+	cfg := config.Load()
+	db := client.Database(cfg.DBName)
 
 	// Check plays collection
 	playsCount, _ := db.Collection("plays").CountDocuments(ctx, bson.M{})
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"github.com/ai-atl/nfl-platform/internal/config"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -13,7 +14,8 @@ func main() {
 	if uri == "" { log.Fatal("MONGO_URI not set") }
 	client, _ := mongo.Connect(options.Client().ApplyURI(uri))
 	defer client.Disconnect(context.Background())
-	db := client.Database("nfl_platform")
+	cfg := config.Load()
+	db := client.Database(cfg.DBName)
 	
 	// Check total 2025 plays
 	count2025, _ := db.Collection("plays").CountDocuments(context.Background(), bson.M{"season": 2025})
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"github.com/ai-atl/nfl-platform/internal/config"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -14,7 +15,8 @@ func main() {
 	if uri == "" { log.Fatal("MONGO_URI not set") }
 	client, _ := mongo.Connect(options.Client().ApplyURI(uri))
 	defer client.Disconnect(context.Background())
-	db := client.Database("nfl_platform")
+	cfg := config.Load()
+	db := client.Database(cfg.DBName)
 	result, err := db.Collection("plays").DeleteMany(context.Background(), bson.M{})
 	if err != nil { log.Fatal(err) }
 	fmt.Printf("Deleted %d plays\n", result.DeletedCount)
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/ai-atl/nfl-platform/internal/config"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -28,7 +29,8 @@ func main() {
 	}
 	defer client.Disconnect(ctx)
 
-	db := client.Database("nfl_platform")
+	cfg := config.Load()
+	db := client.Database(cfg.DBName)
 
 	// Get sample player names from players collection
 	fmt.Println("=== Sample names from PLAYERS collection ===")
@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/ai-atl/nfl-platform/internal/config"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -31,7 +32,8 @@ func main() {
 	}
 	defer client.Disconnect(ctx)
 
-	db := client.Database("nfl_platform")
+	cfg := config.Load()
+	db := client.Database(cfg.DBName)
 
 	log.Println("🏗️  Creating MongoDB indexes for performance...")
 
@@ -34,13 +34,13 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	client, err := mongodb.Connect(ctx, cfg.MongoURI)
+	client, err := mongodb.Connect(ctx, cfg.MongoURI, cfg.MongoPool())
 	if err != nil {
 		log.Fatal(fmt.Errorf("failed to connect to MongoDB: %w", err))
 	}
 	defer client.Disconnect(context.Background())
 
-	db := client.Database("nfl_platform")
+	db := client.Database(cfg.DBName)
 	playersCollection := db.Collection("players")
 
 	fmt.Println("✓ Connected to MongoDB")
@@ -3,25 +3,28 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ai-atl/nfl-platform/internal/config"
+	"github.com/ai-atl/nfl-platform/internal/jobs"
 	"github.com/ai-atl/nfl-platform/internal/models"
 	"github.com/ai-atl/nfl-platform/internal/parquet"
 	"github.com/ai-atl/nfl-platform/pkg/mongodb"
-	"github.com/apache/arrow/go/v14/arrow"
-	"github.com/apache/arrow/go/v14/arrow/array"
-	"github.com/apache/arrow/go/v14/arrow/memory"
-	"github.com/apache/arrow/go/v14/parquet/file"
-	"github.com/apache/arrow/go/v14/parquet/pqarrow"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -50,6 +53,8 @@ var dataURLs = map[string]string{
 	"roster_weekly": nflverseBaseURL + "/weekly_rosters/roster_weekly_%d.parquet",
 
 	// Player stats (2017+)
+	"player_stats_reg":     nflverseBaseURL + "/stats_player/stats_player_reg_%d.parquet",
+	"player_stats_post":    nflverseBaseURL + "/stats_player/stats_player_post_%d.parquet",
 	"player_stats_regpost": nflverseBaseURL + "/stats_player/stats_player_regpost_%d.parquet",
 	"player_stats_weekly":  nflverseBaseURL + "/stats_player/stats_player_week_%d.parquet",
 
@@ -73,26 +78,73 @@ var dataURLs = map[string]string{
 type DataLoader struct {
 	db         *mongo.Database
 	httpClient *http.Client
-	mu         sync.Mutex
+	force      bool       // Reload files even when unchanged since the last successful load
+	mu         sync.Mutex // Guards stats; years are loaded in parallel goroutines
 	stats      LoadStats
 }
 
 type LoadStats struct {
-	TotalFiles    int
-	Downloaded    int
-	Processed     int
-	Errors        int
-	PlayersLoaded int
-	GamesLoaded   int
-	PlaysLoaded   int
-	NGSLoaded     int
-	StartTime     time.Time
+	TotalFiles     int
+	Downloaded     int
+	Processed      int
+	Errors         int
+	PlayersLoaded  int
+	GamesLoaded    int
+	PlaysLoaded    int
+	NGSLoaded      int
+	InjuriesLoaded int
+	QBRLoaded      int
+	SnapsLoaded    int
+	Skipped        int
+	Failed         []FailedLoad // Files that could not be loaded after retrying
+	StartTime      time.Time
+}
+
+// FailedLoad is one dataset/year that permanently failed, so it can be re-run on its own
+type FailedLoad struct {
+	Dataset string
+	Year    int
+	URL     string
+	Err     error
+}
+
+// downloadAttempts is how many times a per-year file is downloaded before it counts as failed
+const downloadAttempts = 2
+
+// retryDelay is the pause between download attempts
+const retryDelay = 5 * time.Second
+
+// LoadState records the last successful load of one source file in the load_state
+// collection, keyed by dataset and year (0 for files that span all seasons)
+type LoadState struct {
+	Dataset     string    `bson:"dataset"`
+	Year        int       `bson:"year"`
+	URL         string    `bson:"url"`
+	ContentHash string    `bson:"content_hash"`
+	Rows        int       `bson:"rows"`
+	LoadedAt    time.Time `bson:"loaded_at"`
+}
+
+// cacheMeta holds the HTTP validators for a cached file so unchanged files aren't re-downloaded
+type cacheMeta struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
 }
 
 func main() {
+	opts, err := parseFlags(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Invalid options: %v", err)
+	}
+
 	fmt.Println("=== NFLverse Maximum Data Loader ===")
-	fmt.Println("Loading ALL available data (1999-2025)")
-	fmt.Println("This will take approximately 30-60 minutes")
+	if opts.datasets == "all" && opts.startYear == 0 && opts.endYear == 0 {
+		fmt.Println("Loading ALL available data (1999-2025)")
+		fmt.Println("This will take approximately 30-60 minutes")
+	}
 	fmt.Println()
 
 	// Load environment
@@ -104,14 +156,14 @@ func main() {
 
 	// Connect to MongoDB
 	ctx := context.Background()
-	client, err := mongodb.Connect(ctx, cfg.MongoURI)
+	client, err := mongodb.Connect(ctx, cfg.MongoURI, cfg.MongoPool())
 	if err != nil {
 		log.Fatal(fmt.Errorf("failed to connect to MongoDB: %w", err))
 	}
 	defer client.Disconnect(ctx)
 
-	db := client.Database("nfl_platform")
-	log.Println("✓ Connected to MongoDB (database: nfl_platform)")
+	db := client.Database(cfg.DBName)
+	log.Printf("✓ Connected to MongoDB (database: %s)", cfg.DBName)
 
 	// Create cache directory
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
@@ -124,48 +176,206 @@ func main() {
 		httpClient: &http.Client{
 			Timeout: 5 * time.Minute,
 		},
+		force: opts.force,
 		stats: LoadStats{
 			StartTime: time.Now(),
 		},
 	}
 
+	if opts.force {
+		fmt.Println("⚠ --force set: ignoring load_state and cached validators")
+	}
+
 	// Start loading
-	loader.LoadAll(ctx)
+	loader.LoadAll(ctx, opts.plans)
 
 	// Print final stats
 	loader.PrintFinalStats()
 }
 
-func (l *DataLoader) LoadAll(ctx context.Context) {
-	fmt.Println("\n📊 Phase 1: Loading Schedules & Teams")
-	fmt.Println(strings.Repeat("=", 50))
-	//l.LoadSchedules(ctx)
-	//l.LoadTeams(ctx)
+// loadOptions are the loader's command-line flags with the load plan they resolve to
+type loadOptions struct {
+	force     bool
+	datasets  string
+	startYear int
+	endYear   int
+	plans     []loadPlan
+}
 
-	fmt.Println("\n📊 Phase 2: Loading Rosters (2020-2025)")
-	fmt.Println(strings.Repeat("=", 50))
-	//l.LoadRosters(ctx, 2020, 2025)
+// parseFlags parses the command line and plans the load, so bad datasets or years fail
+// before connecting to MongoDB
+func parseFlags(args []string) (*loadOptions, error) {
+	opts := &loadOptions{}
+	fs := flag.NewFlagSet("load_maximum_data", flag.ContinueOnError)
+	fs.BoolVar(&opts.force, "force", false, "re-download and reload every file, even if unchanged since the last load")
+	fs.StringVar(&opts.datasets, "datasets", "all", "comma-separated datasets to load: "+datasetNames())
+	fs.IntVar(&opts.startYear, "start-year", 0, "first season to load (0 = each dataset's default)")
+	fs.IntVar(&opts.endYear, "end-year", 0, "last season to load (0 = each dataset's default)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
 
-	fmt.Println("\n📊 Phase 3: Loading Weekly Rosters for Injury Status (2024-2025)")
-	fmt.Println(strings.Repeat("=", 50))
-	//l.LoadWeeklyRosters(ctx, 2024, 2025)
+	plans, err := planLoad(opts.datasets, opts.startYear, opts.endYear)
+	if err != nil {
+		return nil, err
+	}
+	opts.plans = plans
+	return opts, nil
+}
 
-	fmt.Println("\n📊 Phase 4: Loading Player Stats (2020-2025)")
-	fmt.Println(strings.Repeat("=", 50))
-	//l.LoadPlayerStats(ctx, 2020, 2025)
+// datasetSpec is one selectable loader phase. Per-year datasets have a default range and
+// the first season nflverse publishes; all-seasons files (allYears) ignore the year flags.
+type datasetSpec struct {
+	name      string
+	title     string
+	allYears  bool
+	minYear   int
+	startYear int
+	endYear   int
+	load      func(l *DataLoader, ctx context.Context, startYear, endYear int)
+}
 
-	fmt.Println("\n📊 Phase 4.5: Loading Weekly Player Stats (2020-2025)")
-	fmt.Println(strings.Repeat("=", 50))
-	//l.LoadWeeklyStats(ctx, 2020, 2025)
+// datasets lists every phase in load order; selecting a subset keeps this order
+var datasets = []datasetSpec{
+	{name: "schedules", title: "Schedules & Teams", allYears: true,
+		load: func(l *DataLoader, ctx context.Context, _, _ int) { l.LoadSchedules(ctx); l.LoadTeams(ctx) }},
+	{name: "rosters", title: "Rosters", minYear: 1999, startYear: 2020, endYear: 2025,
+		load: (*DataLoader).LoadRosters},
+	{name: "weekly_rosters", title: "Weekly Rosters for Injury Status", minYear: 2002, startYear: 2024, endYear: 2025,
+		load: (*DataLoader).LoadWeeklyRosters},
+	{name: "injuries", title: "Injury Reports", minYear: 2009, startYear: 2020, endYear: 2025,
+		load: (*DataLoader).LoadInjuries},
+	{name: "stats", title: "Player Stats", minYear: 2017, startYear: 2020, endYear: 2025,
+		load: (*DataLoader).LoadPlayerStats},
+	{name: "weekly_stats", title: "Weekly Player Stats", minYear: 2017, startYear: 2020, endYear: 2025,
+		load: (*DataLoader).LoadWeeklyStats},
+	{name: "defense_stats", title: "Team Defense Stats for D/ST Scoring", minYear: 2017, startYear: 2020, endYear: 2025,
+		load: (*DataLoader).LoadDefenseStats},
+	{name: "pbp", title: "Play-by-Play Data", minYear: 1999, startYear: 1999, endYear: 2025,
+		load: (*DataLoader).LoadPlayByPlay},
+	{name: "snaps", title: "Snap Counts from Participation Data", minYear: 2016, startYear: 2020, endYear: 2025,
+		load: (*DataLoader).LoadSnapCounts},
+	{name: "ngs", title: "Next Gen Stats (All Seasons)", allYears: true,
+		load: (*DataLoader).LoadNextGenStats},
+	{name: "qbr", title: "ESPN QBR (All Seasons)", allYears: true,
+		load: func(l *DataLoader, ctx context.Context, _, _ int) { l.LoadQBR(ctx) }},
+}
 
-	fmt.Println("\n📊 Phase 5: Loading Play-by-Play Data (ALL 27 SEASONS!) 🏈")
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Println("This is the biggest dataset - will take 15-20 minutes")
-	l.LoadPlayByPlay(ctx, 1999, 2025)
+// latestSeason is the most recent season nflverse publishes files for
+const latestSeason = 2025
 
-	fmt.Println("\n📊 Phase 6: Loading Next Gen Stats (All Seasons)")
-	fmt.Println(strings.Repeat("=", 50))
-	//l.LoadNextGenStats(ctx, 2020, 2025)
+// selectDatasets parses a comma-separated --datasets value ("all" or empty selects every dataset)
+func selectDatasets(list string) ([]datasetSpec, error) {
+	if list == "" || list == "all" {
+		return datasets, nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, d := range datasets {
+			if d.name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown dataset %q (valid: %s)", name, datasetNames())
+		}
+		wanted[name] = true
+	}
+
+	var selected []datasetSpec
+	for _, d := range datasets {
+		if wanted[d.name] {
+			selected = append(selected, d)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("no datasets selected")
+	}
+	return selected, nil
+}
+
+func datasetNames() string {
+	names := make([]string, len(datasets))
+	for i, d := range datasets {
+		names[i] = d.name
+	}
+	return strings.Join(names, ",")
+}
+
+// yearRange resolves the seasons to load for a dataset. A zero flag keeps the dataset's
+// default; a start before the dataset's first season is raised to it, and a range that
+// ends before the first season or is reversed is an error.
+func (d datasetSpec) yearRange(startYear, endYear int) (int, int, error) {
+	if d.allYears {
+		return 0, 0, nil
+	}
+
+	start, end := d.startYear, d.endYear
+	if startYear > 0 {
+		start = startYear
+	}
+	if endYear > 0 {
+		end = endYear
+	}
+
+	switch {
+	case end > latestSeason:
+		return 0, 0, fmt.Errorf("%s: end year %d is after the latest season %d", d.name, end, latestSeason)
+	case end < d.minYear:
+		return 0, 0, fmt.Errorf("%s: only available from %d, requested through %d", d.name, d.minYear, end)
+	case start > end:
+		return 0, 0, fmt.Errorf("%s: start year %d is after end year %d", d.name, start, end)
+	}
+	if start < d.minYear {
+		start = d.minYear
+	}
+	return start, end, nil
+}
+
+// loadPlan is a dataset with its resolved season range
+type loadPlan struct {
+	dataset   datasetSpec
+	startYear int
+	endYear   int
+}
+
+// planLoad selects datasets and resolves each one's seasons, failing before anything loads
+func planLoad(list string, startYear, endYear int) ([]loadPlan, error) {
+	selected, err := selectDatasets(list)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]loadPlan, 0, len(selected))
+	for _, d := range selected {
+		start, end, err := d.yearRange(startYear, endYear)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, loadPlan{dataset: d, startYear: start, endYear: end})
+	}
+	return plans, nil
+}
+
+// LoadAll runs the planned phases in order. Files whose content matches the last successful
+// load are skipped, so re-running after a crash resumes where the previous run stopped.
+func (l *DataLoader) LoadAll(ctx context.Context, plans []loadPlan) {
+	for i, p := range plans {
+		if p.dataset.allYears {
+			fmt.Printf("\n📊 Phase %d: Loading %s\n", i+1, p.dataset.title)
+		} else {
+			fmt.Printf("\n📊 Phase %d: Loading %s (%d-%d)\n", i+1, p.dataset.title, p.startYear, p.endYear)
+		}
+		fmt.Println(strings.Repeat("=", 50))
+		p.dataset.load(l, ctx, p.startYear, p.endYear)
+	}
 
 	fmt.Println("\n✅ All data loaded!")
 }
@@ -177,7 +387,12 @@ func (l *DataLoader) LoadSchedules(ctx context.Context) {
 	data, err := l.downloadFile(url, "games.parquet")
 	if err != nil {
 		log.Printf("❌ Failed to download schedules: %v", err)
-		l.stats.Errors++
+		l.updateStats(func(s *LoadStats) { s.Errors++ })
+		return
+	}
+
+	hash, ok := l.beginLoad(ctx, "schedules", 0, data)
+	if !ok {
 		return
 	}
 
@@ -186,7 +401,11 @@ func (l *DataLoader) LoadSchedules(ctx context.Context) {
 
 	fmt.Printf("→ Inserting %d games into MongoDB...\n", len(games))
 	inserted := l.insertGames(ctx, games)
-	l.stats.GamesLoaded += inserted
+	l.updateStats(func(s *LoadStats) { s.GamesLoaded += inserted })
+
+	if len(games) > 0 {
+		l.finishLoad(ctx, "schedules", 0, url, hash, len(games))
+	}
 
 	fmt.Printf("✓ Loaded %d games\n", inserted)
 }
@@ -195,14 +414,31 @@ func (l *DataLoader) LoadTeams(ctx context.Context) {
 	fmt.Println("→ Downloading teams...")
 
 	url := dataURLs["teams"]
-	_, err := l.downloadFile(url, "teams.parquet")
+	data, err := l.downloadFile(url, "teams.parquet")
 	if err != nil {
 		log.Printf("❌ Failed to download teams: %v", err)
-		l.stats.Errors++
+		l.updateStats(func(s *LoadStats) { s.Errors++ })
+		return
+	}
+
+	hash, ok := l.beginLoad(ctx, "teams", 0, data)
+	if !ok {
 		return
 	}
 
-	fmt.Println("✓ Teams data cached (use for UI logos/colors)")
+	teams, err := parquet.ParseTeams(data)
+	if err != nil {
+		log.Printf("❌ Failed to parse teams: %v", err)
+		l.updateStats(func(s *LoadStats) { s.Errors++ })
+		return
+	}
+
+	inserted := l.insertTeams(ctx, teams)
+	if len(teams) > 0 {
+		l.finishLoad(ctx, "teams", 0, url, hash, len(teams))
+	}
+
+	fmt.Printf("✓ Loaded %d teams (colors, logos, divisions)\n", inserted)
 }
 
 func (l *DataLoader) LoadRosters(ctx context.Context, startYear, endYear int) {
@@ -230,18 +466,23 @@ func (l *DataLoader) loadRosterYear(ctx context.Context, year int) {
 	data, err := l.downloadFile(url, fmt.Sprintf("roster_%d.parquet", year))
 	if err != nil {
 		log.Printf("❌ Failed to download roster %d: %v", year, err)
-		l.mu.Lock()
-		l.stats.Errors++
-		l.mu.Unlock()
+		l.updateStats(func(s *LoadStats) { s.Errors++ })
+		return
+	}
+
+	hash, ok := l.beginLoad(ctx, "roster_yearly", year, data)
+	if !ok {
 		return
 	}
 
 	players := l.parseRoster(data, year)
 	inserted := l.insertPlayers(ctx, players)
 
-	l.mu.Lock()
-	l.stats.PlayersLoaded += inserted
-	l.mu.Unlock()
+	l.updateStats(func(s *LoadStats) { s.PlayersLoaded += inserted })
+
+	if len(players) > 0 {
+		l.finishLoad(ctx, "roster_yearly", year, url, hash, len(players))
+	}
 
 	fmt.Printf("✓ Loaded %d players from %d\n", inserted, year)
 }
@@ -271,9 +512,12 @@ func (l *DataLoader) loadWeeklyRosterYear(ctx context.Context, year int) {
 	data, err := l.downloadFile(url, fmt.Sprintf("roster_weekly_%d.parquet", year))
 	if err != nil {
 		log.Printf("❌ Failed to download weekly roster %d: %v", year, err)
-		l.mu.Lock()
-		l.stats.Errors++
-		l.mu.Unlock()
+		l.updateStats(func(s *LoadStats) { s.Errors++ })
+		return
+	}
+
+	hash, ok := l.beginLoad(ctx, "roster_weekly", year, data)
+	if !ok {
 		return
 	}
 
@@ -281,12 +525,23 @@ func (l *DataLoader) loadWeeklyRosterYear(ctx context.Context, year int) {
 	weeklyRosters := l.parseWeeklyRoster(data, year)
 	fmt.Printf("  📦 Parsed %d weekly roster entries\n", len(weeklyRosters))
 
-	// Update players with injury status
+	// Update players with injury status and their latest team
 	updated := l.updatePlayerInjuryStatus(ctx, weeklyRosters)
 
-	l.mu.Lock()
-	l.stats.PlayersLoaded += updated
-	l.mu.Unlock()
+	// Keep every week's status, since the players collection only holds the latest
+	statuses := l.insertWeeklyStatuses(ctx, weeklyRosters)
+	fmt.Printf("  🩺 Recorded %d weekly statuses\n", statuses)
+
+	// Record every team each player was on, so mid-season moves aren't lost
+	stints := teamStints(weeklyRosters)
+	l.insertTeamHistory(ctx, stints)
+	fmt.Printf("  🔁 Recorded %d team stints\n", len(stints))
+
+	l.updateStats(func(s *LoadStats) { s.PlayersLoaded += updated })
+
+	if len(weeklyRosters) > 0 {
+		l.finishLoad(ctx, "roster_weekly", year, url, hash, len(weeklyRosters))
+	}
 
 	fmt.Printf("✓ Updated %d players with injury status from %d\n", updated, year)
 }
@@ -309,33 +564,51 @@ func (l *DataLoader) LoadPlayerStats(ctx context.Context, startYear, endYear int
 	wg.Wait()
 }
 
+// playerStatsFiles maps each stored season_type to its player stats file
+var playerStatsFiles = []struct {
+	seasonType string
+	urlKey     string
+}{
+	{models.SeasonTypeRegular, "player_stats_reg"},
+	{models.SeasonTypePost, "player_stats_post"},
+	{models.SeasonTypeCombined, "player_stats_regpost"}, // Regular + Post season combined
+}
+
 func (l *DataLoader) loadPlayerStatsYear(ctx context.Context, year int) {
 	// Player stats only available from 2017+
 	if year < 2017 {
 		return
 	}
 
-	fmt.Printf("→ Loading player stats %d...\n", year)
+	for _, file := range playerStatsFiles {
+		fmt.Printf("→ Loading %s player stats %d...\n", file.seasonType, year)
 
-	url := fmt.Sprintf(dataURLs["player_stats_regpost"], year)
-	data, err := l.downloadFile(url, fmt.Sprintf("player_stats_regpost_%d.parquet", year))
-	if err != nil {
-		log.Printf("❌ Failed to download player stats %d: %v", year, err)
-		l.mu.Lock()
-		l.stats.Errors++
-		l.mu.Unlock()
-		return
-	}
+		url := fmt.Sprintf(dataURLs[file.urlKey], year)
+		data, err := l.downloadFile(url, fmt.Sprintf("%s_%d.parquet", file.urlKey, year))
+		if err != nil {
+			// Post-season files don't exist until the playoffs start
+			log.Printf("❌ Failed to download %s player stats %d: %v", file.seasonType, year, err)
+			l.updateStats(func(s *LoadStats) { s.Errors++ })
+			continue
+		}
 
-	// Parse the stats
-	stats := l.parsePlayerStats(data, year, "REGPOST") // Regular + Post season combined
-	inserted := l.insertPlayerStats(ctx, stats)
+		hash, ok := l.beginLoad(ctx, file.urlKey, year, data)
+		if !ok {
+			continue
+		}
 
-	l.mu.Lock()
-	l.stats.PlayersLoaded += inserted // Reuse counter for stats
-	l.mu.Unlock()
+		// Parse the stats
+		stats := l.parsePlayerStats(data, year, file.seasonType)
+		inserted := l.insertPlayerStats(ctx, stats)
+
+		l.updateStats(func(s *LoadStats) { s.PlayersLoaded += inserted }) // Reuse counter for stats
 
-	fmt.Printf("✓ Loaded %d player stats from %d\n", inserted, year)
+		if len(stats) > 0 {
+			l.finishLoad(ctx, file.urlKey, year, url, hash, len(stats))
+		}
+
+		fmt.Printf("✓ Loaded %d %s player stats from %d\n", inserted, file.seasonType, year)
+	}
 }
 
 func (l *DataLoader) LoadWeeklyStats(ctx context.Context, startYear, endYear int) {
@@ -368,9 +641,12 @@ func (l *DataLoader) loadWeeklyStatsYear(ctx context.Context, year int) {
 	data, err := l.downloadFile(url, fmt.Sprintf("player_stats_weekly_%d.parquet", year))
 	if err != nil {
 		log.Printf("❌ Failed to download weekly stats %d: %v", year, err)
-		l.mu.Lock()
-		l.stats.Errors++
-		l.mu.Unlock()
+		l.updateStats(func(s *LoadStats) { s.Errors++ })
+		return
+	}
+
+	hash, ok := l.beginLoad(ctx, "player_stats_weekly", year, data)
+	if !ok {
 		return
 	}
 
@@ -378,11 +654,76 @@ func (l *DataLoader) loadWeeklyStatsYear(ctx context.Context, year int) {
 	weeklyStats := l.parseWeeklyStats(data, year)
 	inserted := l.insertWeeklyStats(ctx, weeklyStats)
 
-	l.mu.Lock()
-	l.stats.PlayersLoaded += inserted // Reuse counter
-	l.mu.Unlock()
+	// Kicking lines come from the same file
+	kickers, err := parquet.ParseKickerStats(data, year)
+	if err != nil {
+		log.Printf("Error parsing kicker stats %d: %v", year, err)
+	}
+	kickersInserted := l.insertKickerStats(ctx, kickers)
+
+	l.updateStats(func(s *LoadStats) { s.PlayersLoaded += inserted }) // Reuse counter
 
-	fmt.Printf("✓ Loaded %d weekly stat records from %d\n", inserted, year)
+	if len(weeklyStats) > 0 {
+		l.finishLoad(ctx, "player_stats_weekly", year, url, hash, len(weeklyStats))
+	}
+
+	fmt.Printf("✓ Loaded %d weekly stat records and %d kicker weeks from %d\n", inserted, kickersInserted, year)
+}
+
+func (l *DataLoader) LoadDefenseStats(ctx context.Context, startYear, endYear int) {
+	for year := startYear; year <= endYear; year++ {
+		fmt.Printf("→ Loading team defense stats %d...\n", year)
+
+		url := fmt.Sprintf(dataURLs["team_stats_week"], year)
+		data, err := l.downloadFile(url, fmt.Sprintf("team_stats_week_%d.parquet", year))
+		if err != nil {
+			log.Printf("❌ Failed to download team stats %d: %v", year, err)
+			l.recordFailure("defense_stats", year, url, err)
+			continue
+		}
+
+		hash, ok := l.beginLoad(ctx, "team_stats_week", year, data)
+		if !ok {
+			continue
+		}
+
+		defenses, err := parquet.ParseDefenseStats(data, year)
+		if err != nil {
+			log.Printf("Error parsing team stats %d: %v", year, err)
+			continue
+		}
+		l.fillPointsAllowed(ctx, defenses, year)
+		inserted := l.insertDefenseStats(ctx, defenses)
+
+		if len(defenses) > 0 {
+			l.finishLoad(ctx, "team_stats_week", year, url, hash, len(defenses))
+		}
+
+		fmt.Printf("✓ Loaded %d team defense weeks from %d\n", inserted, year)
+	}
+}
+
+// fillPointsAllowed sets each defense's points allowed from its game's final score
+func (l *DataLoader) fillPointsAllowed(ctx context.Context, defenses []models.DefenseStats, year int) {
+	cursor, err := l.db.Collection("games").Find(ctx, bson.M{"season": year, "status": "final"})
+	if err != nil {
+		log.Printf("⚠ Failed to fetch %d games for points allowed: %v", year, err)
+		return
+	}
+	var games []models.Game
+	if err := cursor.All(ctx, &games); err != nil {
+		log.Printf("⚠ Failed to decode %d games for points allowed: %v", year, err)
+		return
+	}
+
+	allowed := make(map[string]int, len(games)*2) // key: team_week
+	for _, g := range games {
+		allowed[g.HomeTeam+"_"+strconv.Itoa(g.Week)] = g.AwayScore
+		allowed[g.AwayTeam+"_"+strconv.Itoa(g.Week)] = g.HomeScore
+	}
+	for i := range defenses {
+		defenses[i].PointsAllowed = allowed[defenses[i].Team+"_"+strconv.Itoa(defenses[i].Week)]
+	}
 }
 
 func (l *DataLoader) LoadPlayByPlay(ctx context.Context, startYear, endYear int) {
@@ -410,38 +751,172 @@ func (l *DataLoader) loadPlayByPlayYear(ctx context.Context, year int) {
 	fmt.Printf("→ Loading play-by-play %d...\n", year)
 
 	url := fmt.Sprintf(dataURLs["pbp"], year)
-	data, err := l.downloadFile(url, fmt.Sprintf("pbp_%d.parquet", year))
+	data, err := retryDownload(downloadAttempts, retryDelay, func() ([]byte, error) {
+		return l.downloadFile(url, fmt.Sprintf("pbp_%d.parquet", year))
+	})
 	if err != nil {
-		log.Printf("❌ Failed to download PBP %d: %v", year, err)
-		l.mu.Lock()
-		l.stats.Errors++
-		l.mu.Unlock()
+		log.Printf("❌ Failed to download PBP %d after %d attempts: %v", year, downloadAttempts, err)
+		l.recordFailure("pbp", year, url, err)
+		return
+	}
+
+	hash, ok := l.beginLoad(ctx, "pbp", year, data)
+	if !ok {
 		return
 	}
 
-	plays := l.parsePlayByPlay(data, year)
-	inserted := l.insertPlays(ctx, plays)
+	// Stream record batches straight into MongoDB so a full season is never held in memory
+	parsed, inserted := 0, 0
+	err = parquet.StreamPlayByPlay(data, year, parquet.DefaultBatchSize, func(plays []models.Play) error {
+		parsed += len(plays)
+		n := l.insertPlays(ctx, plays)
+		inserted += n
 
-	l.mu.Lock()
-	l.stats.PlaysLoaded += inserted
-	l.mu.Unlock()
+		l.updateStats(func(s *LoadStats) { s.PlaysLoaded += n })
+		return ctx.Err()
+	})
+	if err != nil {
+		log.Printf("❌ Failed to parse PBP %d: %v", year, err)
+		l.updateStats(func(s *LoadStats) { s.Errors++ })
+		return
+	}
+
+	if parsed > 0 {
+		l.finishLoad(ctx, "pbp", year, url, hash, parsed)
+	}
+
+	total := l.snapshot().PlaysLoaded
 
-	fmt.Printf("✓ Loaded %d plays from %d (Total: %d plays)\n", inserted, year, l.stats.PlaysLoaded)
+	fmt.Printf("✓ Loaded %d plays from %d (Total: %d plays)\n", inserted, year, total)
 }
 
 func (l *DataLoader) LoadInjuries(ctx context.Context, startYear, endYear int) {
+	// Injury reports are only published from 2009
+	if startYear < 2009 {
+		startYear = 2009
+	}
+
 	for year := startYear; year <= endYear; year++ {
 		fmt.Printf("→ Loading injuries %d...\n", year)
 
 		url := fmt.Sprintf(dataURLs["injuries"], year)
-		_, err := l.downloadFile(url, fmt.Sprintf("injuries_%d.parquet", year))
+		data, err := l.downloadFile(url, fmt.Sprintf("injuries_%d.parquet", year))
 		if err != nil {
 			log.Printf("⚠ Injuries %d not available: %v", year, err)
 			continue
 		}
 
-		fmt.Printf("✓ Cached injuries %d\n", year)
+		hash, ok := l.beginLoad(ctx, "injuries", year, data)
+		if !ok {
+			continue
+		}
+
+		reports, err := parquet.ParseInjuries(data)
+		if err != nil {
+			log.Printf("⚠ Failed to parse injuries %d: %v", year, err)
+			l.updateStats(func(s *LoadStats) { s.Errors++ })
+			continue
+		}
+
+		inserted := l.insertInjuries(ctx, reports)
+
+		l.updateStats(func(s *LoadStats) { s.InjuriesLoaded += inserted })
+
+		if len(reports) > 0 {
+			l.finishLoad(ctx, "injuries", year, url, hash, len(reports))
+		}
+
+		fmt.Printf("✓ Loaded %d injury reports from %d\n", inserted, year)
+	}
+}
+
+func (l *DataLoader) insertInjuries(ctx context.Context, reports []models.InjuryReport) int {
+	if len(reports) == 0 {
+		return 0
+	}
+
+	collection := l.db.Collection("injuries")
+
+	// Upsert reports with compound key (nfl_id + season + week)
+	writes := make([]mongo.WriteModel, 0, len(reports))
+	for _, report := range reports {
+		filter := bson.M{
+			"nfl_id": report.NFLID,
+			"season": report.Season,
+			"week":   report.Week,
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": report}).
+			SetUpsert(true))
+	}
+
+	return l.bulkUpsert(ctx, collection, writes, "injury report")
+}
+
+// LoadSnapCounts builds per-game offensive snap shares from play participation files
+func (l *DataLoader) LoadSnapCounts(ctx context.Context, startYear, endYear int) {
+	// Participation data is only published from 2016
+	if startYear < 2016 {
+		startYear = 2016
+	}
+
+	for year := startYear; year <= endYear; year++ {
+		fmt.Printf("→ Loading participation %d...\n", year)
+
+		url := fmt.Sprintf(dataURLs["pbp_participation"], year)
+		data, err := l.downloadFile(url, fmt.Sprintf("pbp_participation_%d.parquet", year))
+		if err != nil {
+			log.Printf("⚠ Participation %d not available: %v", year, err)
+			continue
+		}
+
+		hash, ok := l.beginLoad(ctx, "pbp_participation", year, data)
+		if !ok {
+			continue
+		}
+
+		snaps, err := parquet.ParseParticipation(data, year)
+		if err != nil {
+			log.Printf("⚠ Failed to parse participation %d: %v", year, err)
+			l.updateStats(func(s *LoadStats) { s.Errors++ })
+			continue
+		}
+
+		inserted := l.insertSnapCounts(ctx, snaps)
+
+		l.updateStats(func(s *LoadStats) { s.SnapsLoaded += inserted })
+
+		if len(snaps) > 0 {
+			l.finishLoad(ctx, "pbp_participation", year, url, hash, len(snaps))
+		}
+
+		fmt.Printf("✓ Loaded %d snap counts from %d\n", inserted, year)
+	}
+}
+
+func (l *DataLoader) insertSnapCounts(ctx context.Context, snaps []models.SnapCount) int {
+	if len(snaps) == 0 {
+		return 0
+	}
+
+	collection := l.db.Collection("snap_counts")
+
+	// Upsert with compound key (nfl_id + season + week)
+	writes := make([]mongo.WriteModel, 0, len(snaps))
+	for _, snap := range snaps {
+		filter := bson.M{
+			"nfl_id": snap.NFLID,
+			"season": snap.Season,
+			"week":   snap.Week,
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": snap}).
+			SetUpsert(true))
 	}
+
+	return l.bulkUpsert(ctx, collection, writes, "snap count")
 }
 
 func (l *DataLoader) LoadNextGenStats(ctx context.Context, startYear, endYear int) {
@@ -459,9 +934,12 @@ func (l *DataLoader) LoadNextGenStats(ctx context.Context, startYear, endYear in
 		data, err := l.downloadFile(url, fmt.Sprintf("ngs_%s.parquet", statName))
 		if err != nil {
 			log.Printf("⚠ NGS %s not available: %v", statName, err)
-			l.mu.Lock()
-			l.stats.Errors++
-			l.mu.Unlock()
+			l.updateStats(func(s *LoadStats) { s.Errors++ })
+			continue
+		}
+
+		hash, ok := l.beginLoad(ctx, urlKey, 0, data)
+		if !ok {
 			continue
 		}
 
@@ -469,9 +947,7 @@ func (l *DataLoader) LoadNextGenStats(ctx context.Context, startYear, endYear in
 		stats, err := parquet.ParseNextGenStats(data, statName)
 		if err != nil {
 			log.Printf("⚠ Failed to parse NGS %s: %v", statName, err)
-			l.mu.Lock()
-			l.stats.Errors++
-			l.mu.Unlock()
+			l.updateStats(func(s *LoadStats) { s.Errors++ })
 			continue
 		}
 		if len(stats) == 0 {
@@ -482,14 +958,84 @@ func (l *DataLoader) LoadNextGenStats(ctx context.Context, startYear, endYear in
 		// Insert into MongoDB
 		inserted := l.insertNGSStats(ctx, stats)
 
-		l.mu.Lock()
-		l.stats.NGSLoaded += inserted
-		l.mu.Unlock()
+		l.updateStats(func(s *LoadStats) { s.NGSLoaded += inserted })
+
+		l.finishLoad(ctx, urlKey, 0, url, hash, len(stats))
 
 		fmt.Printf("✓ Loaded %d NGS %s stats (all years)\n", inserted, statName)
 	}
 }
 
+func (l *DataLoader) LoadQBR(ctx context.Context) {
+	// QBR files contain ALL years in a single file (not per-year)
+	levels := []struct {
+		urlKey string
+		weekly bool
+	}{
+		{"qbr_week", true},
+		{"qbr_season", false},
+	}
+
+	for _, level := range levels {
+		fmt.Printf("→ Loading %s (all seasons)...\n", level.urlKey)
+
+		url := dataURLs[level.urlKey]
+		data, err := l.downloadFile(url, level.urlKey+".parquet")
+		if err != nil {
+			log.Printf("⚠ %s not available: %v", level.urlKey, err)
+			l.updateStats(func(s *LoadStats) { s.Errors++ })
+			continue
+		}
+
+		hash, ok := l.beginLoad(ctx, level.urlKey, 0, data)
+		if !ok {
+			continue
+		}
+
+		stats, err := parquet.ParseQBR(data, level.weekly)
+		if err != nil {
+			log.Printf("⚠ Failed to parse %s: %v", level.urlKey, err)
+			l.updateStats(func(s *LoadStats) { s.Errors++ })
+			continue
+		}
+
+		inserted := l.insertQBRStats(ctx, stats)
+
+		l.updateStats(func(s *LoadStats) { s.QBRLoaded += inserted })
+
+		if len(stats) > 0 {
+			l.finishLoad(ctx, level.urlKey, 0, url, hash, len(stats))
+		}
+
+		fmt.Printf("✓ Loaded %d %s records (all years)\n", inserted, level.urlKey)
+	}
+}
+
+func (l *DataLoader) insertQBRStats(ctx context.Context, stats []models.QBRStat) int {
+	if len(stats) == 0 {
+		return 0
+	}
+
+	collection := l.db.Collection("qbr")
+
+	// Upsert stats with compound key (espn_id + season + season_type + week)
+	writes := make([]mongo.WriteModel, 0, len(stats))
+	for _, stat := range stats {
+		filter := bson.M{
+			"espn_id":     stat.ESPNID,
+			"season":      stat.Season,
+			"season_type": stat.SeasonType,
+			"week":        stat.Week,
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": stat}).
+			SetUpsert(true))
+	}
+
+	return l.bulkUpsert(ctx, collection, writes, "QBR stat")
+}
+
 func (l *DataLoader) insertNGSStats(ctx context.Context, stats []models.NextGenStat) int {
 	if len(stats) == 0 {
 		return 0
@@ -497,8 +1043,12 @@ func (l *DataLoader) insertNGSStats(ctx context.Context, stats []models.NextGenS
 
 	collection := l.db.Collection("next_gen_stats")
 
-	// Upsert stats with compound key (player_id + season + week + stat_type)
-	inserted := 0
+	return l.bulkUpsert(ctx, collection, ngsStatWrites(stats), "NGS stat")
+}
+
+// ngsStatWrites upserts each stat on its player, season, week and stat type
+func ngsStatWrites(stats []models.NextGenStat) []mongo.WriteModel {
+	writes := make([]mongo.WriteModel, 0, len(stats))
 	for _, stat := range stats {
 		filter := bson.M{
 			"player_id": stat.PlayerID,
@@ -506,40 +1056,105 @@ func (l *DataLoader) insertNGSStats(ctx context.Context, stats []models.NextGenS
 			"week":      stat.Week,
 			"stat_type": stat.StatType,
 		}
-		update := bson.M{"$set": stat}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": stat}).
+			SetUpsert(true))
+	}
+
+	return writes
+}
+
+// Helper functions
+
+// bulkBatchSize is the number of upserts sent per BulkWrite round trip
+const bulkBatchSize = 1000
+
+// bulkWriter is the part of *mongo.Collection that bulkUpsert needs
+type bulkWriter interface {
+	BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...options.Lister[options.BulkWriteOptions]) (*mongo.BulkWriteResult, error)
+}
+
+// bulkUpsert runs writes as unordered bulk writes in batches of bulkBatchSize and returns
+// the number of documents inserted or updated. A failing document doesn't stop its batch.
+func (l *DataLoader) bulkUpsert(ctx context.Context, collection bulkWriter, writes []mongo.WriteModel, label string) int {
+	opts := options.BulkWrite().SetOrdered(false)
+
+	written := 0
+	for i := 0; i < len(writes); i += bulkBatchSize {
+		end := i + bulkBatchSize
+		if end > len(writes) {
+			end = len(writes)
+		}
 
-		opts := options.UpdateOne().SetUpsert(true)
-		_, err := collection.UpdateOne(ctx, filter, update, opts)
+		batch := writes[i:end]
+		_, err := collection.BulkWrite(ctx, batch, opts)
 		if err != nil {
-			log.Printf("Error upserting NGS stat: %v", err)
+			var bulkErr mongo.BulkWriteException
+			if errors.As(err, &bulkErr) {
+				log.Printf("Error upserting %d of %d %s: %v", len(bulkErr.WriteErrors), len(batch), label, err)
+				written += len(batch) - len(bulkErr.WriteErrors)
+				continue
+			}
+			log.Printf("Error upserting %s batch: %v", label, err)
 			continue
 		}
-		inserted++
+		written += len(batch)
 	}
 
-	return inserted
+	return written
 }
 
-// Helper functions
-
+// downloadFile returns the file from the source, revalidating any cached copy with its
+// ETag/Last-Modified so unchanged files are served from cache without re-downloading
 func (l *DataLoader) downloadFile(url, filename string) ([]byte, error) {
-	cachePath := fmt.Sprintf("%s/%s", cacheDir, filename)
+	cachePath := filepath.Join(cacheDir, filename)
+	metaPath := cachePath + ".meta.json"
+
+	cached, cacheErr := os.ReadFile(cachePath)
+	if cacheErr == nil && !isParquet(cached) {
+		// A bad cache (e.g. an HTML error page saved by an older run) would be re-read forever
+		log.Printf("⚠ Cached %s is not a Parquet file, discarding it", filename)
+		os.Remove(cachePath)
+		os.Remove(metaPath)
+		cached, cacheErr = nil, errInvalidParquet
+	}
 
-	// Check cache first
-	if data, err := os.ReadFile(cachePath); err == nil {
-		l.mu.Lock()
-		l.stats.Downloaded++
-		l.mu.Unlock()
-		return data, nil
+	var meta cacheMeta
+	if cacheErr == nil && !l.force {
+		if raw, err := os.ReadFile(metaPath); err == nil {
+			json.Unmarshal(raw, &meta)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if meta.ETag != "" {
+		req.Header.Set("If-None-Match", meta.ETag)
+	}
+	if meta.LastModified != "" {
+		req.Header.Set("If-Modified-Since", meta.LastModified)
 	}
 
-	// Download
-	resp, err := l.httpClient.Get(url)
+	resp, err := l.httpClient.Do(req)
 	if err != nil {
+		// Fall back to the cached copy when the source is unreachable
+		if cacheErr == nil {
+			log.Printf("⚠ %s unreachable, using cached copy: %v", filename, err)
+			l.countDownload()
+			return cached, nil
+		}
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cacheErr == nil {
+		l.countDownload()
+		return cached, nil
+	}
+
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
 	}
@@ -549,16 +1164,120 @@ func (l *DataLoader) downloadFile(url, filename string) ([]byte, error) {
 		return nil, err
 	}
 
-	// Cache it
+	// nflverse/GitHub can answer 200 with an HTML page; never cache or parse that
+	if !isParquet(data) {
+		return nil, fmt.Errorf("%w: %s returned %d bytes of %q", errInvalidParquet, url, len(data), resp.Header.Get("Content-Type"))
+	}
+
+	// Cache it along with its validators
 	os.WriteFile(cachePath, data, 0644)
+	meta = cacheMeta{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if raw, err := json.Marshal(meta); err == nil {
+		os.WriteFile(metaPath, raw, 0644)
+	}
 
-	l.mu.Lock()
-	l.stats.Downloaded++
-	l.mu.Unlock()
+	l.countDownload()
 
 	return data, nil
 }
 
+// retryDownload calls download up to attempts times, waiting delay between tries, and
+// returns the last error if every attempt fails
+func retryDownload(attempts int, delay time.Duration, download func() ([]byte, error)) ([]byte, error) {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var data []byte
+		if data, err = download(); err == nil {
+			return data, nil
+		}
+		if attempt < attempts {
+			log.Printf("⚠ Download attempt %d/%d failed, retrying in %s: %v", attempt, attempts, delay, err)
+			time.Sleep(delay)
+		}
+	}
+	return nil, err
+}
+
+// recordFailure counts an error and remembers the file so PrintFinalStats can list it
+func (l *DataLoader) recordFailure(dataset string, year int, url string, err error) {
+	l.updateStats(func(s *LoadStats) {
+		s.Errors++
+		s.Failed = append(s.Failed, FailedLoad{Dataset: dataset, Year: year, URL: url, Err: err})
+	})
+}
+
+// parquetMagic opens and closes every Parquet file
+var parquetMagic = []byte("PAR1")
+
+var errInvalidParquet = errors.New("not a Parquet file")
+
+// isParquet reports whether data starts and ends with the Parquet magic bytes
+func isParquet(data []byte) bool {
+	return len(data) >= 2*len(parquetMagic) &&
+		bytes.HasPrefix(data, parquetMagic) &&
+		bytes.HasSuffix(data, parquetMagic)
+}
+
+func (l *DataLoader) countDownload() {
+	l.updateStats(func(s *LoadStats) { s.Downloaded++ })
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// needsLoad reports whether a file must be loaded: always when forced or never loaded
+// before, otherwise only when its content changed since the last successful load
+func needsLoad(prev *LoadState, hash string, force bool) bool {
+	return force || prev == nil || prev.ContentHash != hash
+}
+
+// beginLoad hashes a downloaded file and checks it against load_state. It returns the
+// hash to pass to finishLoad and whether the file needs loading.
+func (l *DataLoader) beginLoad(ctx context.Context, dataset string, year int, data []byte) (string, bool) {
+	hash := contentHash(data)
+
+	var prev *LoadState
+	var state LoadState
+	err := l.db.Collection("load_state").FindOne(ctx, bson.M{"dataset": dataset, "year": year}).Decode(&state)
+	switch {
+	case err == nil:
+		prev = &state
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		log.Printf("⚠ Failed to read load state for %s %d: %v", dataset, year, err)
+	}
+
+	if !needsLoad(prev, hash, l.force) {
+		fmt.Printf("↺ Skipping %s %d: unchanged since %s\n", dataset, year, prev.LoadedAt.Format(time.RFC3339))
+		l.updateStats(func(s *LoadStats) { s.Skipped++ })
+		return hash, false
+	}
+
+	return hash, true
+}
+
+// finishLoad records a successful load so unchanged files are skipped next run
+func (l *DataLoader) finishLoad(ctx context.Context, dataset string, year int, url, hash string, rows int) {
+	state := LoadState{
+		Dataset:     dataset,
+		Year:        year,
+		URL:         url,
+		ContentHash: hash,
+		Rows:        rows,
+		LoadedAt:    time.Now(),
+	}
+
+	filter := bson.M{"dataset": dataset, "year": year}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := l.db.Collection("load_state").ReplaceOne(ctx, filter, state, opts); err != nil {
+		log.Printf("⚠ Failed to record load state for %s %d: %v", dataset, year, err)
+	}
+}
+
 // Real Parquet parsers using Apache Arrow
 
 func (l *DataLoader) parseSchedules(data []byte) []models.Game {
@@ -597,46 +1316,13 @@ func (l *DataLoader) parseWeeklyStats(data []byte, year int) []models.WeeklyStat
 	return weeklyStats
 }
 
-func (l *DataLoader) parsePlayByPlay(data []byte, year int) []models.Play {
-	plays, err := parquet.ParsePlayByPlay(data, year)
-	if err != nil {
-		log.Printf("Error parsing play-by-play %d: %v", year, err)
-		return []models.Play{}
-	}
-	return plays
-}
-
 func (l *DataLoader) insertGames(ctx context.Context, games []models.Game) int {
-	if len(games) == 0 {
-		return 0
-	}
-
-	collection := l.db.Collection("games")
-
-	// Batch insert
-	docs := make([]interface{}, len(games))
-	for i, game := range games {
-		docs[i] = game
-	}
-
-	opts := options.InsertMany().SetOrdered(false) // Continue on duplicates
-	result, err := collection.InsertMany(ctx, docs, opts)
+	// Upsert by game_id so reloads backfill scores for games that were scheduled last time
+	written, err := jobs.UpsertGames(ctx, l.db, games)
 	if err != nil {
-		// Check if it's a bulk write exception (duplicate keys are expected)
-		if bulkErr, ok := err.(mongo.BulkWriteException); ok {
-			// Return the number of successfully inserted documents
-			// In v2, InsertedIDs will contain the successful inserts
-			if result != nil && len(result.InsertedIDs) > 0 {
-				return len(result.InsertedIDs)
-			}
-			// Calculate from error
-			return len(games) - len(bulkErr.WriteErrors)
-		}
-		log.Printf("Error inserting games: %v", err)
-		return 0
+		log.Printf("Error upserting games: %v", err)
 	}
-
-	return len(result.InsertedIDs)
+	return written
 }
 
 func (l *DataLoader) insertPlayers(ctx context.Context, players []models.Player) int {
@@ -648,118 +1334,28 @@ func (l *DataLoader) insertPlayers(ctx context.Context, players []models.Player)
 
 	// Upsert players with compound key (nfl_id + season)
 	// This allows tracking player movement across seasons
-	inserted := 0
+	writes := make([]mongo.WriteModel, 0, len(players))
 	for _, player := range players {
 		filter := bson.M{
 			"nfl_id": player.NFLID,
 			"season": player.Season,
 		}
-		update := bson.M{"$set": player}
-
-		opts := options.UpdateOne().SetUpsert(true)
-		_, err := collection.UpdateOne(ctx, filter, update, opts)
-		if err != nil {
-			log.Printf("Error upserting player: %v", err)
-			continue
-		}
-		inserted++
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": player}).
+			SetUpsert(true))
 	}
 
-	return inserted
+	return l.bulkUpsert(ctx, collection, writes, "player")
 }
 
 func (l *DataLoader) parseWeeklyRoster(data []byte, season int) []models.WeeklyRosterEntry {
-	// Parse weekly roster from Parquet using the parquet parser
-	// Weekly rosters include status and status_description_abbr columns
-	reader, err := file.NewParquetReader(bytes.NewReader(data))
-	if err != nil {
-		log.Printf("Failed to create parquet reader: %v", err)
-		return nil
-	}
-	defer reader.Close()
-
-	arrowReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	entries, err := parquet.ParseWeeklyRoster(data, season)
 	if err != nil {
-		log.Printf("Failed to create arrow reader: %v", err)
+		log.Printf("Error parsing weekly roster %d: %v", season, err)
 		return nil
 	}
 
-	table, err := arrowReader.ReadTable(context.Background())
-	if err != nil {
-		log.Printf("Failed to read table: %v", err)
-		return nil
-	}
-	defer table.Release()
-
-	numRows := int(table.NumRows())
-	entries := make([]models.WeeklyRosterEntry, 0, numRows)
-
-	// Create column map
-	schema := table.Schema()
-	colMap := make(map[string]int)
-	for i, field := range schema.Fields() {
-		colMap[field.Name] = i
-	}
-
-	getChunkAndOffset := func(col *arrow.Column, rowIdx int) (arrow.Array, int) {
-		offset := rowIdx
-		for _, chunk := range col.Data().Chunks() {
-			if offset < chunk.Len() {
-				return chunk, offset
-			}
-			offset -= chunk.Len()
-		}
-		return nil, 0
-	}
-
-	getString := func(colName string, rowIdx int) string {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				if arr, ok := chunk.(*array.String); ok && !arr.IsNull(offset) {
-					return arr.Value(offset)
-				}
-			}
-		}
-		return ""
-	}
-
-	getInt := func(colName string, rowIdx int) int {
-		if colIdx, ok := colMap[colName]; ok {
-			col := table.Column(colIdx)
-			chunk, offset := getChunkAndOffset(col, rowIdx)
-			if chunk != nil {
-				switch arr := chunk.(type) {
-				case *array.Int64:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				case *array.Int32:
-					if !arr.IsNull(offset) {
-						return int(arr.Value(offset))
-					}
-				}
-			}
-		}
-		return 0
-	}
-
-	for i := 0; i < numRows; i++ {
-		entry := models.WeeklyRosterEntry{
-			NFLID:                 getString("gsis_id", i), // Use gsis_id, not player_id!
-			Season:                season,
-			Week:                  getInt("week", i),
-			Team:                  getString("team", i),
-			Status:                getString("status", i),
-			StatusDescriptionAbbr: getString("status_description_abbr", i),
-		}
-
-		if entry.NFLID != "" {
-			entries = append(entries, entry)
-		}
-	}
-
 	// Debug: log first few entries
 	if len(entries) > 0 {
 		fmt.Printf("  📋 Sample weekly roster entry: NFLID=%s, Week=%d, Status=%s, StatusAbbr=%s\n",
@@ -801,14 +1397,17 @@ func (l *DataLoader) updatePlayerInjuryStatus(ctx context.Context, weeklyRosters
 			"season": entry.Season,
 		}
 
-		update := bson.M{
-			"$set": bson.M{
-				"status":                  entry.Status,
-				"status_description_abbr": entry.StatusDescriptionAbbr,
-				"week":                    entry.Week,
-				"updated_at":              time.Now(),
-			},
+		set := bson.M{
+			"status":                  entry.Status,
+			"status_description_abbr": entry.StatusDescriptionAbbr,
+			"week":                    entry.Week,
+			"updated_at":              time.Now(),
+		}
+		// The yearly roster lists a player's first team; the latest week reflects trades
+		if entry.Team != "" {
+			set["team"] = entry.Team
 		}
+		update := bson.M{"$set": set}
 
 		result, err := collection.UpdateOne(ctx, filter, update)
 		if err != nil {
@@ -829,6 +1428,93 @@ func (l *DataLoader) updatePlayerInjuryStatus(ctx context.Context, weeklyRosters
 	return updated
 }
 
+// teamStints collapses weekly roster entries into runs of consecutive weeks on the same
+// team for each player and season
+func teamStints(entries []models.WeeklyRosterEntry) []models.PlayerTeamStint {
+	byPlayer := make(map[string][]models.WeeklyRosterEntry)
+	for _, e := range entries {
+		if e.Team == "" {
+			continue
+		}
+		key := e.NFLID + "_" + strconv.Itoa(e.Season)
+		byPlayer[key] = append(byPlayer[key], e)
+	}
+
+	var stints []models.PlayerTeamStint
+	for _, weeks := range byPlayer {
+		sort.Slice(weeks, func(i, j int) bool { return weeks[i].Week < weeks[j].Week })
+
+		current := models.PlayerTeamStint{NFLID: weeks[0].NFLID, Season: weeks[0].Season, Team: weeks[0].Team, FromWeek: weeks[0].Week, ToWeek: weeks[0].Week}
+		for _, w := range weeks[1:] {
+			if w.Team != current.Team {
+				stints = append(stints, current)
+				current = models.PlayerTeamStint{NFLID: w.NFLID, Season: w.Season, Team: w.Team, FromWeek: w.Week}
+			}
+			current.ToWeek = w.Week
+		}
+		stints = append(stints, current)
+	}
+	return stints
+}
+
+func (l *DataLoader) insertTeamHistory(ctx context.Context, stints []models.PlayerTeamStint) int {
+	if len(stints) == 0 {
+		return 0
+	}
+
+	collection := l.db.Collection("player_team_history")
+
+	// Upsert stints with compound key (nfl_id + season + from_week)
+	writes := make([]mongo.WriteModel, 0, len(stints))
+	for _, stint := range stints {
+		stint.UpdatedAt = time.Now()
+		filter := bson.M{
+			"nfl_id":    stint.NFLID,
+			"season":    stint.Season,
+			"from_week": stint.FromWeek,
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": stint}).
+			SetUpsert(true))
+	}
+
+	return l.bulkUpsert(ctx, collection, writes, "team stint")
+}
+
+func (l *DataLoader) insertWeeklyStatuses(ctx context.Context, weeklyRosters []models.WeeklyRosterEntry) int {
+	if len(weeklyRosters) == 0 {
+		return 0
+	}
+
+	collection := l.db.Collection("player_weekly_status")
+
+	// Upsert statuses with compound key (nfl_id + season + week)
+	writes := make([]mongo.WriteModel, 0, len(weeklyRosters))
+	for _, entry := range weeklyRosters {
+		status := models.PlayerWeeklyStatus{
+			NFLID:                 entry.NFLID,
+			Season:                entry.Season,
+			Week:                  entry.Week,
+			Team:                  entry.Team,
+			Status:                entry.Status,
+			StatusDescriptionAbbr: entry.StatusDescriptionAbbr,
+			UpdatedAt:             time.Now(),
+		}
+		filter := bson.M{
+			"nfl_id": entry.NFLID,
+			"season": entry.Season,
+			"week":   entry.Week,
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": status}).
+			SetUpsert(true))
+	}
+
+	return l.bulkUpsert(ctx, collection, writes, "weekly status")
+}
+
 func (l *DataLoader) insertPlayerStats(ctx context.Context, stats []models.PlayerStats) int {
 	if len(stats) == 0 {
 		return 0
@@ -836,26 +1522,25 @@ func (l *DataLoader) insertPlayerStats(ctx context.Context, stats []models.Playe
 
 	collection := l.db.Collection("player_stats")
 
-	// Upsert stats with compound key (nfl_id + season + season_type)
-	inserted := 0
+	return l.bulkUpsert(ctx, collection, playerStatsWrites(stats), "player stats")
+}
+
+// playerStatsWrites upserts each stat on its player, season and season type
+func playerStatsWrites(stats []models.PlayerStats) []mongo.WriteModel {
+	writes := make([]mongo.WriteModel, 0, len(stats))
 	for _, stat := range stats {
 		filter := bson.M{
 			"nfl_id":      stat.NFLID,
 			"season":      stat.Season,
 			"season_type": stat.SeasonType,
 		}
-		update := bson.M{"$set": stat}
-
-		opts := options.UpdateOne().SetUpsert(true)
-		_, err := collection.UpdateOne(ctx, filter, update, opts)
-		if err != nil {
-			log.Printf("Error upserting player stats: %v", err)
-			continue
-		}
-		inserted++
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": stat}).
+			SetUpsert(true))
 	}
 
-	return inserted
+	return writes
 }
 
 func (l *DataLoader) insertWeeklyStats(ctx context.Context, weeklyStats []models.WeeklyStat) int {
@@ -865,26 +1550,92 @@ func (l *DataLoader) insertWeeklyStats(ctx context.Context, weeklyStats []models
 
 	collection := l.db.Collection("player_weekly_stats")
 
-	// Upsert weekly stats with compound key (nfl_id + season + week)
-	inserted := 0
+	return l.bulkUpsert(ctx, collection, weeklyStatsWrites(weeklyStats), "weekly stats")
+}
+
+// weeklyStatsWrites upserts each stat on its player, season and week
+func weeklyStatsWrites(weeklyStats []models.WeeklyStat) []mongo.WriteModel {
+	writes := make([]mongo.WriteModel, 0, len(weeklyStats))
 	for _, stat := range weeklyStats {
 		filter := bson.M{
 			"nfl_id": stat.NFLID,
 			"season": stat.Season,
 			"week":   stat.Week,
 		}
-		update := bson.M{"$set": stat}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": stat}).
+			SetUpsert(true))
+	}
 
-		opts := options.UpdateOne().SetUpsert(true)
-		_, err := collection.UpdateOne(ctx, filter, update, opts)
-		if err != nil {
-			log.Printf("Error upserting weekly stats: %v", err)
-			continue
+	return writes
+}
+
+func (l *DataLoader) insertKickerStats(ctx context.Context, kickers []models.KickerStats) int {
+	if len(kickers) == 0 {
+		return 0
+	}
+
+	collection := l.db.Collection("kicker_weekly_stats")
+
+	// Upsert kicker weeks with compound key (nfl_id + season + week)
+	writes := make([]mongo.WriteModel, 0, len(kickers))
+	for _, k := range kickers {
+		filter := bson.M{
+			"nfl_id": k.NFLID,
+			"season": k.Season,
+			"week":   k.Week,
 		}
-		inserted++
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": k}).
+			SetUpsert(true))
 	}
 
-	return inserted
+	return l.bulkUpsert(ctx, collection, writes, "kicker stats")
+}
+
+func (l *DataLoader) insertDefenseStats(ctx context.Context, defenses []models.DefenseStats) int {
+	if len(defenses) == 0 {
+		return 0
+	}
+
+	collection := l.db.Collection("defense_weekly_stats")
+
+	// Upsert defense weeks with compound key (team + season + week)
+	writes := make([]mongo.WriteModel, 0, len(defenses))
+	for _, d := range defenses {
+		filter := bson.M{
+			"team":   d.Team,
+			"season": d.Season,
+			"week":   d.Week,
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": d}).
+			SetUpsert(true))
+	}
+
+	return l.bulkUpsert(ctx, collection, writes, "defense stats")
+}
+
+func (l *DataLoader) insertTeams(ctx context.Context, teams []models.Team) int {
+	if len(teams) == 0 {
+		return 0
+	}
+
+	collection := l.db.Collection("teams")
+
+	// Upsert teams by abbreviation
+	writes := make([]mongo.WriteModel, 0, len(teams))
+	for _, team := range teams {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"abbr": team.Abbr}).
+			SetUpdate(bson.M{"$set": team}).
+			SetUpsert(true))
+	}
+
+	return l.bulkUpsert(ctx, collection, writes, "team")
 }
 
 func (l *DataLoader) insertPlays(ctx context.Context, plays []models.Play) int {
@@ -930,18 +1681,54 @@ func (l *DataLoader) insertPlays(ctx context.Context, plays []models.Play) int {
 	return inserted
 }
 
+// updateStats applies fn to the shared stats under the lock, since years load in parallel
+func (l *DataLoader) updateStats(fn func(s *LoadStats)) {
+	l.mu.Lock()
+	fn(&l.stats)
+	l.mu.Unlock()
+}
+
+// snapshot copies the stats under the lock so they can be read while loads are running
+func (l *DataLoader) snapshot() LoadStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats
+	stats.Failed = append([]FailedLoad(nil), stats.Failed...)
+	return stats
+}
+
 func (l *DataLoader) PrintFinalStats() {
-	duration := time.Since(l.stats.StartTime)
+	stats := l.snapshot()
+	duration := time.Since(stats.StartTime)
 
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("📊 LOADING COMPLETE!")
 	fmt.Println(strings.Repeat("=", 60))
 	fmt.Printf("\n⏱️  Total Time: %s\n", duration.Round(time.Second))
-	fmt.Printf("\n📥 Downloaded: %d files\n", l.stats.Downloaded)
-	fmt.Printf("✅ Games Loaded: %d\n", l.stats.GamesLoaded)
-	fmt.Printf("✅ Players Loaded: %d\n", l.stats.PlayersLoaded)
-	fmt.Printf("✅ Plays Loaded: %d\n", l.stats.PlaysLoaded)
-	fmt.Printf("❌ Errors: %d\n", l.stats.Errors)
+	fmt.Printf("\n📥 Downloaded: %d files\n", stats.Downloaded)
+	fmt.Printf("↺ Skipped (unchanged): %d files\n", stats.Skipped)
+	fmt.Printf("✅ Games Loaded: %d\n", stats.GamesLoaded)
+	fmt.Printf("✅ Players Loaded: %d\n", stats.PlayersLoaded)
+	fmt.Printf("✅ Plays Loaded: %d\n", stats.PlaysLoaded)
+	fmt.Printf("✅ Injury Reports Loaded: %d\n", stats.InjuriesLoaded)
+	fmt.Printf("✅ QBR Records Loaded: %d\n", stats.QBRLoaded)
+	fmt.Printf("✅ Snap Counts Loaded: %d\n", stats.SnapsLoaded)
+	fmt.Printf("❌ Errors: %d\n", stats.Errors)
+
+	if len(stats.Failed) > 0 {
+		sort.Slice(stats.Failed, func(i, j int) bool {
+			a, b := stats.Failed[i], stats.Failed[j]
+			if a.Dataset != b.Dataset {
+				return a.Dataset < b.Dataset
+			}
+			return a.Year < b.Year
+		})
+		fmt.Println("\n⚠ Failed files (re-run these years):")
+		for _, f := range stats.Failed {
+			fmt.Printf("  %s %d: %s (%v)\n", f.Dataset, f.Year, f.URL, f.Err)
+		}
+	}
 
 	fmt.Println("\n🎯 Next Steps:")
 	fmt.Println("1. Start backend: go run cmd/api/main.go")
@@ -0,0 +1,483 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ai-atl/nfl-platform/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// TestLoaderStatsConcurrent hammers the stats helpers from parallel goroutines the way
+// the per-year loaders do; run it with -race to catch unguarded updates
+func TestLoaderStatsConcurrent(t *testing.T) {
+	const workers, perWorker = 8, 200
+
+	l := &DataLoader{}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(year int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				l.countDownload()
+				l.updateStats(func(s *LoadStats) { s.PlaysLoaded += 10 })
+				if i%50 == 0 {
+					l.recordFailure("pbp", year, "url", errors.New("boom"))
+				}
+				_ = l.snapshot().PlaysLoaded
+			}
+		}(2000 + w)
+	}
+	wg.Wait()
+
+	stats := l.snapshot()
+	if want := workers * perWorker; stats.Downloaded != want {
+		t.Errorf("Downloaded = %d, want %d", stats.Downloaded, want)
+	}
+	if want := workers * perWorker * 10; stats.PlaysLoaded != want {
+		t.Errorf("PlaysLoaded = %d, want %d", stats.PlaysLoaded, want)
+	}
+	if want := workers * perWorker / 50; stats.Errors != want || len(stats.Failed) != want {
+		t.Errorf("Errors = %d, Failed = %d, want %d", stats.Errors, len(stats.Failed), want)
+	}
+}
+
+// TestSnapshotCopiesFailures checks a snapshot does not share its Failed slice with the loader
+func TestSnapshotCopiesFailures(t *testing.T) {
+	l := &DataLoader{}
+	l.recordFailure("ngs", 2023, "url", errors.New("boom"))
+
+	stats := l.snapshot()
+	stats.Failed[0].Dataset = "changed"
+
+	if got := l.snapshot().Failed[0].Dataset; got != "ngs" {
+		t.Errorf("Failed[0].Dataset = %q after mutating a snapshot, want %q", got, "ngs")
+	}
+}
+
+func TestNeedsLoad(t *testing.T) {
+	data := []byte("PAR1 season file PAR1")
+	hash := contentHash(data)
+	prev := &LoadState{Dataset: "pbp", Year: 2024, ContentHash: hash}
+
+	tests := []struct {
+		name  string
+		prev  *LoadState
+		hash  string
+		force bool
+		want  bool
+	}{
+		{"never loaded", nil, hash, false, true},
+		{"unchanged", prev, hash, false, false},
+		{"content changed", prev, contentHash([]byte("PAR1 updated PAR1")), false, true},
+		{"forced while unchanged", prev, hash, true, true},
+		{"forced and never loaded", nil, hash, true, true},
+	}
+
+	for _, tt := range tests {
+		if got := needsLoad(tt.prev, tt.hash, tt.force); got != tt.want {
+			t.Errorf("%s: needsLoad() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if contentHash(data) != hash {
+		t.Error("contentHash() is not stable for the same bytes")
+	}
+}
+
+// TestDownloadFileRevalidatesCache checks an unchanged file is served from cache after a
+// 304, and that --force skips the cached validators
+func TestDownloadFileRevalidatesCache(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	body := []byte("PAR1 schedules PAR1")
+	var conditional []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	l := &DataLoader{httpClient: server.Client()}
+	for i := 0; i < 2; i++ {
+		data, err := l.downloadFile(server.URL, "games.parquet")
+		if err != nil {
+			t.Fatalf("download %d: %v", i+1, err)
+		}
+		if !bytes.Equal(data, body) {
+			t.Errorf("download %d = %q, want %q", i+1, data, body)
+		}
+	}
+
+	l.force = true
+	if _, err := l.downloadFile(server.URL, "games.parquet"); err != nil {
+		t.Fatalf("forced download: %v", err)
+	}
+
+	want := []string{"", `"v1"`, ""}
+	if !slices.Equal(conditional, want) {
+		t.Errorf("If-None-Match headers = %q, want %q", conditional, want)
+	}
+	if got := l.snapshot().Downloaded; got != 3 {
+		t.Errorf("Downloaded = %d, want 3", got)
+	}
+}
+
+// memCollection applies upserting UpdateOneModels to an in-memory collection keyed by filter
+type memCollection struct {
+	docs    map[string]bson.M
+	batches []int
+}
+
+func (c *memCollection) BulkWrite(ctx context.Context, writes []mongo.WriteModel, opts ...options.Lister[options.BulkWriteOptions]) (*mongo.BulkWriteResult, error) {
+	c.batches = append(c.batches, len(writes))
+	for _, w := range writes {
+		m, ok := w.(*mongo.UpdateOneModel)
+		if !ok || m.Upsert == nil || !*m.Upsert {
+			return nil, fmt.Errorf("unexpected write model %T", w)
+		}
+		filter := roundTrip(m.Filter)
+		keys := make([]string, 0, len(filter))
+		for k := range filter {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var key strings.Builder
+		for _, k := range keys {
+			fmt.Fprintf(&key, "%s=%v;", k, filter[k])
+		}
+
+		doc, ok := c.docs[key.String()]
+		if !ok {
+			doc = filter
+			c.docs[key.String()] = doc
+		}
+		for k, v := range roundTrip(m.Update.(bson.M)["$set"]) {
+			doc[k] = v
+		}
+	}
+	return &mongo.BulkWriteResult{}, nil
+}
+
+func roundTrip(v interface{}) bson.M {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	var m bson.M
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// TestBulkUpsertRerunUpdates checks that loading the same season twice updates rows in
+// place rather than duplicating them, across several bulk batches
+func TestBulkUpsertRerunUpdates(t *testing.T) {
+	const players, weeks = 130, 18
+	load := func(yards int) []models.WeeklyStat {
+		stats := make([]models.WeeklyStat, 0, players*weeks)
+		for p := 0; p < players; p++ {
+			for w := 1; w <= weeks; w++ {
+				stats = append(stats, models.WeeklyStat{
+					NFLID:        fmt.Sprintf("00-%07d", p),
+					Season:       2024,
+					Week:         w,
+					RushingYards: yards,
+				})
+			}
+		}
+		return stats
+	}
+
+	l := &DataLoader{}
+	coll := &memCollection{docs: make(map[string]bson.M)}
+	ctx := context.Background()
+
+	if got := l.bulkUpsert(ctx, coll, weeklyStatsWrites(load(50)), "weekly stats"); got != players*weeks {
+		t.Errorf("first load wrote %d, want %d", got, players*weeks)
+	}
+	l.bulkUpsert(ctx, coll, weeklyStatsWrites(load(80)), "weekly stats")
+
+	if len(coll.docs) != players*weeks {
+		t.Errorf("collection has %d documents after a re-run, want %d", len(coll.docs), players*weeks)
+	}
+	for _, doc := range coll.docs {
+		if doc["rushing_yards"] != int32(80) {
+			t.Fatalf("rushing_yards = %v after re-run, want 80", doc["rushing_yards"])
+		}
+	}
+	// 2340 writes per load go out as 1000 + 1000 + 340
+	if want := []int{1000, 1000, 340, 1000, 1000, 340}; !slices.Equal(coll.batches, want) {
+		t.Errorf("batch sizes = %v, want %v", coll.batches, want)
+	}
+}
+
+func TestStatWritesKeys(t *testing.T) {
+	coll := &memCollection{docs: make(map[string]bson.M)}
+	ctx := context.Background()
+	l := &DataLoader{}
+
+	// The same player in two seasons, stat types or season types must stay separate rows
+	l.bulkUpsert(ctx, coll, ngsStatWrites([]models.NextGenStat{
+		{PlayerID: "p1", Season: 2024, Week: 1, StatType: "passing"},
+		{PlayerID: "p1", Season: 2024, Week: 1, StatType: "rushing"},
+		{PlayerID: "p1", Season: 2023, Week: 1, StatType: "passing"},
+		{PlayerID: "p1", Season: 2024, Week: 1, StatType: "passing", PlayerName: "renamed"},
+	}), "NGS stat")
+	if len(coll.docs) != 3 {
+		t.Errorf("NGS upserts stored %d documents, want 3", len(coll.docs))
+	}
+
+	coll.docs = make(map[string]bson.M)
+	l.bulkUpsert(ctx, coll, playerStatsWrites([]models.PlayerStats{
+		{NFLID: "p1", Season: 2024, SeasonType: "REG"},
+		{NFLID: "p1", Season: 2024, SeasonType: "POST"},
+		{NFLID: "p1", Season: 2024, SeasonType: "REG", RushingYards: 900},
+	}), "player stats")
+	if len(coll.docs) != 2 {
+		t.Errorf("player stat upserts stored %d documents, want 2", len(coll.docs))
+	}
+}
+
+func TestRetryDownload(t *testing.T) {
+	// flaky errors on its first `failures` calls and then returns the file
+	flaky := func(failures int) (func() ([]byte, error), *int) {
+		calls := 0
+		return func() ([]byte, error) {
+			calls++
+			if calls <= failures {
+				return nil, fmt.Errorf("attempt %d: connection reset", calls)
+			}
+			return []byte("PAR1 pbp PAR1"), nil
+		}, &calls
+	}
+
+	tests := []struct {
+		name      string
+		failures  int
+		wantCalls int
+		wantErr   string
+	}{
+		{"first try", 0, 1, ""},
+		{"recovers on retry", 1, 2, ""},
+		{"fails every attempt", 2, 2, "attempt 2: connection reset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			download, calls := flaky(tt.failures)
+			data, err := retryDownload(2, 0, download)
+			if *calls != tt.wantCalls {
+				t.Errorf("download called %d times, want %d", *calls, tt.wantCalls)
+			}
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr || data != nil {
+					t.Errorf("retryDownload() = %q, %v, want the last error %q", data, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || string(data) != "PAR1 pbp PAR1" {
+				t.Errorf("retryDownload() = %q, %v, want the file", data, err)
+			}
+		})
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	planNames := func(plans []loadPlan) []string {
+		names := make([]string, len(plans))
+		for i, p := range plans {
+			names[i] = fmt.Sprintf("%s:%d-%d", p.dataset.name, p.startYear, p.endYear)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "datasets keep load order",
+			args: []string{"--datasets=ngs,pbp,rosters"},
+			want: []string{"rosters:2020-2025", "pbp:1999-2025", "ngs:0-0"},
+		},
+		{
+			name: "year flags apply to per-year datasets",
+			args: []string{"--datasets", "stats, pbp", "--start-year", "2022", "--end-year", "2023"},
+			want: []string{"stats:2022-2023", "pbp:2022-2023"},
+		},
+		{
+			// Stats start in 2017, so an earlier start is raised to it
+			name: "start raised to the first season",
+			args: []string{"--datasets=stats,pbp", "--start-year=2010", "--end-year=2020"},
+			want: []string{"stats:2017-2020", "pbp:2010-2020"},
+		},
+		{name: "unknown dataset", args: []string{"--datasets=pbp,odds"}, wantErr: `unknown dataset "odds"`},
+		{name: "empty selection", args: []string{"--datasets=,"}, wantErr: "no datasets selected"},
+		{name: "range before the first season", args: []string{"--datasets=stats", "--end-year=2015"}, wantErr: "stats: only available from 2017"},
+		{name: "reversed range", args: []string{"--datasets=pbp", "--start-year=2024", "--end-year=2020"}, wantErr: "start year 2024 is after end year 2020"},
+		{name: "future season", args: []string{"--end-year=2030"}, wantErr: "after the latest season"},
+		{name: "non-numeric year", args: []string{"--start-year=twenty"}, wantErr: "invalid value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseFlags(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseFlags(%q) error = %v, want %q", tt.args, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFlags(%q) error = %v", tt.args, err)
+			}
+			if got := planNames(opts.plans); !slices.Equal(got, tt.want) {
+				t.Errorf("plans = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	opts, err := parseFlags(nil)
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if opts.force || opts.datasets != "all" || len(opts.plans) != len(datasets) {
+		t.Errorf("defaults = force %v, datasets %q, %d plans, want every dataset without --force", opts.force, opts.datasets, len(opts.plans))
+	}
+}
+
+func TestIsParquet(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{"parquet", "PAR1 row groups PAR1", true},
+		{"html error page", "<!DOCTYPE html><html><body>429 Too Many Requests</body></html>", false},
+		{"truncated download", "PAR1 row gro", false},
+		{"magic only once", "PAR1", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		if got := isParquet([]byte(tt.data)); got != tt.want {
+			t.Errorf("%s: isParquet() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestDownloadFileRejectsHTML checks an HTML page served with a 200 is neither returned
+// nor cached, and that an HTML page cached by an older run is discarded
+func TestDownloadFileRejectsHTML(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	html := []byte("<!DOCTYPE html><html><body>Rate limited</body></html>")
+	body := html
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"html"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("ETag", `"html"`)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	l := &DataLoader{httpClient: server.Client()}
+	cachePath := filepath.Join(cacheDir, "pbp_2024.parquet")
+	if _, err := l.downloadFile(server.URL, "pbp_2024.parquet"); !errors.Is(err, errInvalidParquet) {
+		t.Fatalf("downloadFile() of an HTML page error = %v, want errInvalidParquet", err)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("HTML page was cached (stat error = %v)", err)
+	}
+	if got := l.snapshot().Downloaded; got != 0 {
+		t.Errorf("Downloaded = %d, want 0", got)
+	}
+
+	// A bad cache is dropped along with its validators, so the file is fetched unconditionally
+	if err := os.WriteFile(cachePath, html, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cachePath+".meta.json", []byte(`{"etag":"\"html\""}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	body = []byte("PAR1 plays PAR1")
+	data, err := l.downloadFile(server.URL, "pbp_2024.parquet")
+	if err != nil || !bytes.Equal(data, body) {
+		t.Fatalf("downloadFile() over a bad cache = %q, %v, want %q", data, err, body)
+	}
+	if cached, _ := os.ReadFile(cachePath); !bytes.Equal(cached, body) {
+		t.Errorf("cache = %q, want the downloaded Parquet file", cached)
+	}
+}
+
+func TestTeamStintsTrackTrades(t *testing.T) {
+	week := func(id string, season, week int, team string) models.WeeklyRosterEntry {
+		return models.WeeklyRosterEntry{NFLID: id, Season: season, Week: week, Team: team}
+	}
+	// Out of order, as the weekly roster file may list them
+	entries := []models.WeeklyRosterEntry{
+		week("traded", 2024, 9, "LV"),
+		week("traded", 2024, 1, "NYJ"),
+		week("traded", 2024, 8, "NYJ"),
+		week("traded", 2024, 11, "LV"),  // week 10 is LV's bye
+		week("traded", 2024, 12, "NYJ"), // released and re-signed
+		week("traded", 2023, 18, "NYJ"),
+		week("stayed", 2024, 1, "NYJ"),
+		week("stayed", 2024, 2, "NYJ"),
+		week("stayed", 2024, 3, ""), // no team that week
+	}
+
+	got := teamStints(entries)
+	sort.Slice(got, func(i, j int) bool {
+		a, b := got[i], got[j]
+		if a.NFLID != b.NFLID {
+			return a.NFLID < b.NFLID
+		}
+		if a.Season != b.Season {
+			return a.Season < b.Season
+		}
+		return a.FromWeek < b.FromWeek
+	})
+
+	want := []models.PlayerTeamStint{
+		{NFLID: "stayed", Season: 2024, Team: "NYJ", FromWeek: 1, ToWeek: 2},
+		{NFLID: "traded", Season: 2023, Team: "NYJ", FromWeek: 18, ToWeek: 18},
+		{NFLID: "traded", Season: 2024, Team: "NYJ", FromWeek: 1, ToWeek: 8},
+		{NFLID: "traded", Season: 2024, Team: "LV", FromWeek: 9, ToWeek: 11},
+		{NFLID: "traded", Season: 2024, Team: "NYJ", FromWeek: 12, ToWeek: 12},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("teamStints() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/ai-atl/nfl-platform/internal/config"
+	"github.com/ai-atl/nfl-platform/pkg/mongodb"
 	"github.com/parquet-go/parquet-go"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -65,7 +67,8 @@ func main() {
 	}
 	defer client.Disconnect(ctx)
 
-	db := client.Database("nfl_platform")
+	cfg := config.Load()
+	db := client.Database(cfg.DBName)
 	collection := db.Collection("plays")
 
 	// Load 2025 season play-by-play data
@@ -204,6 +207,9 @@ func main() {
 	}
 
 	fmt.Printf("\n✅ Successfully loaded %d plays from %d season\n", totalInserted, season)
+	if err := mongodb.RecordLoad(ctx, db, "pbp", season, totalInserted); err != nil {
+		log.Printf("Failed to record load state: %v", err)
+	}
 	fmt.Println("\nNow creating indexes for fast queries...")
 
 	// Create indexes for fast EPA queries
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/config"
+	"github.com/ai-atl/nfl-platform/internal/jobs"
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/ai-atl/nfl-platform/pkg/mongodb"
+	"github.com/joho/godotenv"
+)
+
+// Refreshes scores and status for one week of games without reloading everything.
+// Usage: go run scripts/refresh_current_week.go [--season 2025] [--week 10]
+func main() {
+	currentSeason, _ := season.Current(context.Background())
+	seasonFlag := flag.Int("season", currentSeason, "season to refresh")
+	weekFlag := flag.Int("week", 0, "week to refresh (default: latest week that has kicked off)")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: .env file not found: %v", err)
+	}
+
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, err := mongodb.Connect(ctx, cfg.MongoURI, cfg.MongoPool())
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	db := client.Database(cfg.DBName)
+
+	fmt.Printf("🔄 Refreshing %d games...\n", *seasonFlag)
+	refreshed, games, err := jobs.RefreshWeek(ctx, db, *seasonFlag, *weekFlag)
+	if err != nil {
+		log.Fatalf("Failed to refresh week: %v", err)
+	}
+
+	fmt.Printf("✓ Refreshed %d games for week %d\n", len(games), refreshed)
+	for _, g := range games {
+		score := ""
+		if g.Status == "final" {
+			score = fmt.Sprintf(" %d-%d", g.AwayScore, g.HomeScore)
+		}
+		fmt.Printf("   %s @ %s: %s%s\n", g.AwayTeam, g.HomeTeam, g.Status, score)
+	}
+}
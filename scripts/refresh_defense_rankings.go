@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ai-atl/nfl-platform/internal/config"
+	"github.com/ai-atl/nfl-platform/internal/jobs"
+	"github.com/ai-atl/nfl-platform/internal/season"
+	"github.com/ai-atl/nfl-platform/pkg/mongodb"
+	"github.com/joho/godotenv"
+)
+
+// Precomputes defensive EPA rankings by position into defense_rankings.
+// Usage: go run scripts/refresh_defense_rankings.go [--season 2025] [--through-week 10]
+func main() {
+	seasonFlag := flag.Int("season", season.DefaultSeason, "season to rank")
+	throughWeek := flag.Int("through-week", 0, "last week to rank through (default: latest week with a final game)")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: .env file not found: %v", err)
+	}
+
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	client, err := mongodb.Connect(ctx, cfg.MongoURI, cfg.MongoPool())
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	db := client.Database(cfg.DBName)
+
+	fmt.Printf("🔄 Precomputing %d defense rankings...\n", *seasonFlag)
+	week, written, err := jobs.RefreshDefenseRankings(ctx, db, *seasonFlag, *throughWeek)
+	if err != nil {
+		log.Fatalf("Failed to refresh defense rankings: %v", err)
+	}
+
+	fmt.Printf("✓ Wrote %d rankings through week %d\n", written, week)
+}
@@ -8,8 +8,10 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/ai-atl/nfl-platform/internal/config"
 	"github.com/ai-atl/nfl-platform/internal/models"
 	"github.com/ai-atl/nfl-platform/internal/parquet"
+	"github.com/ai-atl/nfl-platform/pkg/mongodb"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -48,7 +50,8 @@ func main() {
 	}
 	defer client.Disconnect(context.Background())
 
-	db := client.Database("nfl_platform")
+	cfg := config.Load()
+	db := client.Database(cfg.DBName)
 	ctx := context.Background()
 
 	// Download games.parquet
@@ -111,6 +114,12 @@ func main() {
 
 	opts := options.InsertMany().SetOrdered(false)
 	result, err := collection.InsertMany(ctx, docs, opts)
+
+	// The collection was replaced even if some inserts failed, so cached responses are stale
+	if recordErr := mongodb.RecordLoad(ctx, db, "schedules", 0, len(games)); recordErr != nil {
+		log.Printf("⚠️  %v", recordErr)
+	}
+
 	if err != nil {
 		// Check if it's a bulk write error (some succeeded)
 		if bulkErr, ok := err.(mongo.BulkWriteException); ok {
@@ -7,9 +7,12 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 
+	"github.com/ai-atl/nfl-platform/internal/config"
 	"github.com/ai-atl/nfl-platform/internal/models"
 	"github.com/ai-atl/nfl-platform/internal/parquet"
+	"github.com/ai-atl/nfl-platform/pkg/mongodb"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -22,6 +25,9 @@ const (
 	endYear         = 2025
 )
 
+// seasonTypes are the player stats files loaded for each year
+var seasonTypes = []string{models.SeasonTypeRegular, models.SeasonTypePost, models.SeasonTypeCombined}
+
 func main() {
 	log.Println("🔄 Reloading player_stats with CORRECTED column names...")
 	log.Println("   This includes:")
@@ -48,7 +54,8 @@ func main() {
 	}
 	defer client.Disconnect(ctx)
 
-	db := client.Database("nfl_platform")
+	cfg := config.Load()
+	db := client.Database(cfg.DBName)
 	collection := db.Collection("player_stats")
 
 	// Step 1: Clear existing player_stats
@@ -62,75 +69,83 @@ func main() {
 	// Step 2: Reload player_stats for all years
 	totalInserted := 0
 	for year := startYear; year <= endYear; year++ {
-		log.Printf("\n📥 Loading player_stats for %d...", year)
+		for _, seasonType := range seasonTypes {
+			log.Printf("\n📥 Loading %s player_stats for %d...", seasonType, year)
 
-		url := fmt.Sprintf("%s/stats_player/stats_player_regpost_%d.parquet", nflverseBaseURL, year)
+			url := fmt.Sprintf("%s/stats_player/stats_player_%s_%d.parquet", nflverseBaseURL, strings.ToLower(seasonType), year)
 
-		// Download
-		resp, err := http.Get(url)
-		if err != nil {
-			log.Printf("   ⚠️  Failed to download: %v", err)
-			continue
-		}
+			// Download
+			resp, err := http.Get(url)
+			if err != nil {
+				log.Printf("   ⚠️  Failed to download: %v", err)
+				continue
+			}
 
-		if resp.StatusCode != 200 {
-			log.Printf("   ⚠️  HTTP %d (data may not exist for this year)", resp.StatusCode)
+			if resp.StatusCode != 200 {
+				log.Printf("   ⚠️  HTTP %d (data may not exist for this year)", resp.StatusCode)
+				resp.Body.Close()
+				continue
+			}
+
+			data, err := io.ReadAll(resp.Body)
 			resp.Body.Close()
-			continue
-		}
+			if err != nil {
+				log.Printf("   ⚠️  Failed to read: %v", err)
+				continue
+			}
 
-		data, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			log.Printf("   ⚠️  Failed to read: %v", err)
-			continue
-		}
+			log.Printf("   ✓ Downloaded %d bytes", len(data))
 
-		log.Printf("   ✓ Downloaded %d bytes", len(data))
+			// Parse with CORRECTED column names
+			stats, err := parquet.ParsePlayerStats(data, year, seasonType)
+			if err != nil {
+				log.Printf("   ⚠️  Failed to parse: %v", err)
+				continue
+			}
 
-		// Parse with CORRECTED column names
-		stats, err := parquet.ParsePlayerStats(data, year, "REGPOST")
-		if err != nil {
-			log.Printf("   ⚠️  Failed to parse: %v", err)
-			continue
-		}
+			log.Printf("   ✓ Parsed %d player records", len(stats))
 
-		log.Printf("   ✓ Parsed %d player records", len(stats))
+			// Insert into MongoDB
+			if len(stats) > 0 {
+				// Convert to []interface{} for bulk insert
+				docs := make([]interface{}, len(stats))
+				for i, stat := range stats {
+					docs[i] = stat
+				}
 
-		// Insert into MongoDB
-		if len(stats) > 0 {
-			// Convert to []interface{} for bulk insert
-			docs := make([]interface{}, len(stats))
-			for i, stat := range stats {
-				docs[i] = stat
-			}
+				insertResult, err := collection.InsertMany(ctx, docs)
+				if err != nil {
+					log.Printf("   ⚠️  Failed to insert: %v", err)
+					continue
+				}
 
-			insertResult, err := collection.InsertMany(ctx, docs)
-			if err != nil {
-				log.Printf("   ⚠️  Failed to insert: %v", err)
-				continue
-			}
+				inserted := len(insertResult.InsertedIDs)
+				totalInserted += inserted
+				log.Printf("   ✅ Inserted %d records", inserted)
+
+				dataset := "player_stats_" + strings.ToLower(seasonType)
+				if err := mongodb.RecordLoad(ctx, db, dataset, year, inserted); err != nil {
+					log.Printf("   ⚠️  %v", err)
+				}
 
-			inserted := len(insertResult.InsertedIDs)
-			totalInserted += inserted
-			log.Printf("   ✅ Inserted %d records", inserted)
-
-			// Show sample with EPA
-			if inserted > 0 && year == 2023 {
-				// Find a QB with EPA
-				var sample models.PlayerStats
-				err := collection.FindOne(ctx, bson.M{
-					"season": 2023,
-					"epa":    bson.M{"$ne": 0},
-				}).Decode(&sample)
-				if err == nil {
-					log.Printf("\n   📊 Sample record (2023):")
-					log.Printf("      Player ID: %s", sample.NFLID)
-					log.Printf("      Passing Yards: %d", sample.PassingYards)
-					log.Printf("      Passing TDs: %d", sample.PassingTDs)
-					log.Printf("      Interceptions: %d", sample.Interceptions)
-					log.Printf("      EPA: %.3f ⭐", sample.EPA)
-					log.Printf("      Play Count: %d", sample.PlayCount)
+				// Show sample with EPA
+				if inserted > 0 && year == 2023 {
+					// Find a QB with EPA
+					var sample models.PlayerStats
+					err := collection.FindOne(ctx, bson.M{
+						"season":      2023,
+						"season_type": seasonType,
+						"epa":         bson.M{"$ne": 0},
+					}).Decode(&sample)
+					if err == nil {
+						log.Printf("\n   📊 Sample record (2023):")
+						log.Printf("      Player ID: %s", sample.NFLID)
+						log.Printf("      Passing Yards: %d", sample.PassingYards)
+						log.Printf("      Passing TDs: %d", sample.PassingTDs)
+						log.Printf("      Interceptions: %d", sample.Interceptions)
+						log.Printf("      EPA: %.3f ⭐", sample.EPA)
+						log.Printf("      Play Count: %d", sample.PlayCount)
+					}
 				}
 			}
 		}
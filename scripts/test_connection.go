@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/ai-atl/nfl-platform/internal/config"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -90,7 +91,8 @@ func main() {
 
 	// Test specific database
 	fmt.Println("7. Testing AI-ATL database...")
-	db := client.Database("nfl_platform")
+	cfg := config.Load()
+	db := client.Database(cfg.DBName)
 	collections, err := db.ListCollectionNames(ctx, map[string]interface{}{})
 	if err != nil {
 		log.Printf("   ⚠ Warning: Could not list collections: %v", err)